@@ -1,14 +1,24 @@
 package main
 
 import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
+	"fmt"
+	"io"
 	"log"
 	"math/rand"
+	"net/http"
 	"os"
+	"path/filepath"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/dto"
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/mq"
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/utils/errors"
 	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/utils/logger"
 	"github.com/google/uuid"
 	"github.com/rabbitmq/amqp091-go"
@@ -63,31 +73,89 @@ func main() {
         }
     }
     
-    logger.Logger.Info("Starting heartbeat simulator", "device_count", len(deviceUUIDs))
+    // Devices pre-registered with claim codes provision themselves on start,
+    // like real hardware would on first boot.
+    provisionURL := os.Getenv("PROVISION_URL")
+    var claims []dto.DeviceClaimRequest
+    if deviceClaims := os.Getenv("DEVICE_CLAIMS"); deviceClaims != "" {
+        if provisionURL == "" {
+            logger.Logger.Error("DEVICE_CLAIMS requires PROVISION_URL")
+            os.Exit(1)
+        }
+        for _, claim := range strings.Split(deviceClaims, ",") {
+            parts := strings.SplitN(strings.TrimSpace(claim), ":", 2)
+            if len(parts) != 2 {
+                logger.Logger.Error("Invalid device claim, expected sn:claim_code", "claim", claim)
+                os.Exit(1)
+            }
+            claims = append(claims, dto.DeviceClaimRequest{SN: parts[0], ClaimCode: parts[1]})
+        }
+    }
 
-    if len(deviceUUIDs) == 0 {
+    // Credentials received from provisioning are kept in a file so that a
+    // restarted simulator keeps its devices instead of spending the claim
+    // codes again.
+    credentialsFile := os.Getenv("DEVICE_CREDENTIALS_FILE")
+    if credentialsFile == "" {
+        credentialsFile = defaultCredentialsFile
+    }
+    credentials, err := loadCredentials(credentialsFile)
+    if err != nil {
+        logger.Logger.Error("Failed to load device credentials", "file", credentialsFile, "error", err)
+        os.Exit(1)
+    }
+    var unprovisioned []dto.DeviceClaimRequest
+    for _, claim := range claims {
+        if _, ok := credentials[claim.SN]; !ok {
+            unprovisioned = append(unprovisioned, claim)
+        }
+    }
+    claims = unprovisioned
+
+    logger.Logger.Info("Starting heartbeat simulator", "device_count", len(deviceUUIDs), "provisioned_devices", len(credentials), "pending_claims", len(claims))
+
+    if len(deviceUUIDs) == 0 && len(credentials) == 0 && len(claims) == 0 {
         logger.Logger.Warn("No devices configured. Simulator is running but won't send heartbeats.")
     }
-    
+
+    firmwareVersion := os.Getenv("FIRMWARE_VERSION")
+    if firmwareVersion == "" {
+        firmwareVersion = defaultFirmwareVersion
+    }
+
+    fleet := newFleet(firmwareVersion)
+    subscription, err := subscribeDevices(ch)
+    if err != nil {
+        logger.Logger.Error("Failed to subscribe to device messages", "error", err)
+        os.Exit(1)
+    }
+    go fleet.handle(ch, subscription.messages)
+
+    for _, deviceID := range deviceUUIDs {
+        fleet.add(deviceID, "", subscription)
+    }
+    for _, credential := range credentials {
+        fleet.add(credential.DeviceID, credential.DeviceToken, subscription)
+    }
+
+    var lastProvisioning time.Time
     for {
-        if len(deviceUUIDs) == 0 {
-            logger.Logger.Info("Waiting for devices to be configured...")
-            time.Sleep(1 * time.Minute)
-            continue
-        }
-        
-        for _, deviceID := range deviceUUIDs {
-            msg := dto.HeartbeatMessage{
-                DeviceID:     deviceID.String(),
-                CPU:          rand.Float64() * 100,
-                RAM:          rand.Float64() * 100,
-                DiskFree:     rand.Float64() * 100,
-                Temperature:  20 + rand.Float64()*60,
-                Latency:      rand.Intn(500),
-                Connectivity: rand.Intn(2),
-                BootTime:     time.Now().UTC().Add(-time.Duration(rand.Intn(86400)) * time.Second),
+        if len(claims) > 0 && time.Since(lastProvisioning) >= time.Minute {
+            var provisioned map[string]dto.ProvisionResponse
+            provisioned, claims = provisionDevices(provisionURL, claims)
+            for sn, credential := range provisioned {
+                fleet.add(credential.DeviceID, credential.DeviceToken, subscription)
+                credentials[sn] = credential
             }
-            
+            if len(provisioned) > 0 {
+                if err := saveCredentials(credentialsFile, credentials); err != nil {
+                    logger.Logger.Error("Failed to save device credentials", "file", credentialsFile, "error", err)
+                }
+            }
+            lastProvisioning = time.Now()
+        }
+
+        for _, msg := range fleet.due(time.Now()) {
             body, err := json.Marshal(msg)
             if err != nil {
                 logger.Logger.Error("Error marshaling message", "error", err)
@@ -106,11 +174,393 @@ func main() {
             if err != nil {
                 logger.Logger.Error("Failed to publish a message", "error", err)
             } else {
-                logger.Logger.Debug("Published heartbeat for device", "device", deviceID)
+                logger.Logger.Debug("Published heartbeat for device", "device", msg.DeviceID, "reported", msg.Reported != nil)
             }
         }
-        
-        logger.Logger.Info("Completed heartbeat cycle", "device_count", len(deviceUUIDs))
-        time.Sleep(1 * time.Minute)
+
+        time.Sleep(time.Second)
+    }
+}
+
+// defaultHeartbeatInterval is used until the shadow of a device sets
+// heartbeat_interval_seconds.
+const defaultHeartbeatInterval = time.Minute
+
+// defaultFirmwareVersion is the firmware simulated devices boot with unless
+// FIRMWARE_VERSION is set.
+const defaultFirmwareVersion = "1.0.0"
+
+// defaultCredentialsFile stores the credentials of provisioned devices unless
+// DEVICE_CREDENTIALS_FILE is set.
+const defaultCredentialsFile = "devices.json"
+
+// simulatedDevice keeps the configuration a device runs with. Reported is
+// sent with the next heartbeat after boot and after applying a delta.
+type simulatedDevice struct {
+    id            uuid.UUID
+    token         string
+    interval      time.Duration
+    nextHeartbeat time.Time
+    reported      map[string]interface{}
+    reportPending bool
+    firmware      string
+}
+
+type fleet struct {
+    mu       sync.Mutex
+    devices  map[uuid.UUID]*simulatedDevice
+    firmware string
+}
+
+func newFleet(firmware string) *fleet {
+    return &fleet{devices: make(map[uuid.UUID]*simulatedDevice), firmware: firmware}
+}
+
+func (f *fleet) add(deviceID uuid.UUID, token string, subscription *deviceSubscription) {
+    if err := subscription.bind(deviceID); err != nil {
+        logger.Logger.Error("Failed to bind device messages", "device", deviceID, "error", err)
+    }
+
+    f.mu.Lock()
+    defer f.mu.Unlock()
+    f.devices[deviceID] = &simulatedDevice{
+        id:            deviceID,
+        token:         token,
+        interval:      defaultHeartbeatInterval,
+        nextHeartbeat: time.Now(),
+        reported:      map[string]interface{}{"heartbeat_interval_seconds": defaultHeartbeatInterval.Seconds()},
+        reportPending: true,
+        firmware:      f.firmware,
+    }
+}
+
+// due returns the heartbeats of the devices whose interval has elapsed.
+func (f *fleet) due(now time.Time) []dto.HeartbeatMessage {
+    f.mu.Lock()
+    defer f.mu.Unlock()
+
+    var msgs []dto.HeartbeatMessage
+    for _, device := range f.devices {
+        if now.Before(device.nextHeartbeat) {
+            continue
+        }
+        msg := dto.HeartbeatMessage{
+            DeviceID:     device.id.String(),
+            Token:        device.token,
+            CPU:          rand.Float64() * 100,
+            RAM:          rand.Float64() * 100,
+            DiskFree:     rand.Float64() * 100,
+            Temperature:  20 + rand.Float64()*60,
+            Latency:      rand.Intn(500),
+            Connectivity: rand.Intn(2),
+            BootTime:     time.Now().UTC().Add(-time.Duration(rand.Intn(86400)) * time.Second),
+            FirmwareVersion: device.firmware,
+        }
+        if device.reportPending {
+            msg.Reported = device.reported
+            device.reportPending = false
+        }
+        msgs = append(msgs, msg)
+        device.nextHeartbeat = now.Add(device.interval)
+    }
+    return msgs
+}
+
+// handle dispatches the messages addressed to the simulated devices.
+func (f *fleet) handle(ch *amqp091.Channel, deliveries <-chan amqp091.Delivery) {
+    for d := range deliveries {
+        if strings.HasSuffix(d.RoutingKey, ".command") {
+            f.runCommand(ch, d.Body)
+        } else {
+            f.applyDelta(d.Body)
+        }
+    }
+}
+
+// applyDelta applies a delta to the device it is addressed to and schedules
+// a heartbeat right away to report the new state.
+func (f *fleet) applyDelta(body []byte) {
+    var msg dto.ShadowDeltaMessage
+    if err := json.Unmarshal(body, &msg); err != nil {
+        logger.Logger.Error("Error decoding shadow delta", "error", err)
+        return
+    }
+    deviceID, err := uuid.Parse(msg.DeviceID)
+    if err != nil {
+        logger.Logger.Error("Invalid device ID in shadow delta", "device_id", msg.DeviceID)
+        return
+    }
+
+    f.mu.Lock()
+    device, ok := f.devices[deviceID]
+    if ok {
+        reported := make(map[string]interface{}, len(device.reported)+len(msg.Delta))
+        for key, value := range device.reported {
+            reported[key] = value
+        }
+        for key, value := range msg.Delta {
+            reported[key] = value
+        }
+        if seconds, ok := msg.Delta["heartbeat_interval_seconds"].(float64); ok && seconds > 0 {
+            device.interval = time.Duration(seconds) * time.Second
+        }
+        device.reported = reported
+        device.reportPending = true
+        device.nextHeartbeat = time.Now()
+    }
+    f.mu.Unlock()
+
+    if ok {
+        logger.Logger.Info("Applied shadow delta", "device", deviceID, "version", msg.Version, "delta", msg.Delta)
+    }
+}
+
+// runCommand acknowledges a command right away and replies with its outcome
+// after a short while, like a device doing the work would.
+func (f *fleet) runCommand(ch *amqp091.Channel, body []byte) {
+    var msg dto.DeviceCommandMessage
+    if err := json.Unmarshal(body, &msg); err != nil {
+        logger.Logger.Error("Error decoding command", "error", err)
+        return
+    }
+    deviceID, err := uuid.Parse(msg.DeviceID)
+    if err != nil {
+        logger.Logger.Error("Invalid device ID in command", "device_id", msg.DeviceID)
+        return
+    }
+    if time.Now().After(msg.ExpiresAt) {
+        logger.Logger.Warn("Skipping expired command", "device", deviceID, "command", msg.Name, "command_id", msg.ID)
+        return
+    }
+
+    f.mu.Lock()
+    device, ok := f.devices[deviceID]
+    f.mu.Unlock()
+    if !ok {
+        return
+    }
+    token := device.token
+
+    logger.Logger.Info("Running command", "device", deviceID, "command", msg.Name, "command_id", msg.ID)
+    reply(ch, msg, token, dto.DeviceCommandReply{Status: "acknowledged"})
+
+    go func() {
+        time.Sleep(time.Duration(2+rand.Intn(4)) * time.Second)
+
+        outcome := dto.DeviceCommandReply{Status: "succeeded"}
+        switch msg.Name {
+        case "reboot":
+            f.mu.Lock()
+            if device, ok := f.devices[deviceID]; ok {
+                device.reportPending = true
+                device.nextHeartbeat = time.Now()
+            }
+            f.mu.Unlock()
+            outcome.Result = map[string]interface{}{"boot_time": time.Now().UTC()}
+        case "upload_logs":
+            outcome.Result = map[string]interface{}{
+                "lines": rand.Intn(5000),
+                "url":   fmt.Sprintf("logs/%s/%s.log", deviceID, msg.ID),
+            }
+        case "update_firmware":
+            outcome = f.updateFirmware(deviceID, msg.Params)
+        case "run_diagnostics":
+            if rand.Intn(10) == 0 {
+                outcome = dto.DeviceCommandReply{Status: "failed", Error: "diagnostics timed out"}
+                break
+            }
+            outcome.Result = map[string]interface{}{
+                "cpu":        rand.Float64() * 100,
+                "disk_free":  rand.Float64() * 100,
+                "latency_ms": rand.Intn(500),
+            }
+        default:
+            outcome = dto.DeviceCommandReply{Status: "failed", Error: "unsupported command"}
+        }
+        reply(ch, msg, token, outcome)
+    }()
+}
+
+// updateFirmware downloads the image of an update_firmware command, checks
+// it against the checksum sent along and "reboots" into the new version,
+// which the next heartbeat reports.
+func (f *fleet) updateFirmware(deviceID uuid.UUID, params map[string]interface{}) dto.DeviceCommandReply {
+    version, _ := params["version"].(string)
+    url, _ := params["url"].(string)
+    checksum, _ := params["checksum"].(string)
+    if version == "" || url == "" {
+        return dto.DeviceCommandReply{Status: "failed", Error: "missing version or url"}
+    }
+
+    resp, err := http.Get(url)
+    if err != nil {
+        return dto.DeviceCommandReply{Status: "failed", Error: "download failed: " + err.Error()}
+    }
+    defer resp.Body.Close()
+    if resp.StatusCode != http.StatusOK {
+        return dto.DeviceCommandReply{Status: "failed", Error: fmt.Sprintf("download failed with status %d", resp.StatusCode)}
+    }
+
+    hash := sha256.New()
+    size, err := io.Copy(hash, resp.Body)
+    if err != nil {
+        return dto.DeviceCommandReply{Status: "failed", Error: "download failed: " + err.Error()}
+    }
+    if !strings.EqualFold(hex.EncodeToString(hash.Sum(nil)), checksum) {
+        return dto.DeviceCommandReply{Status: "failed", Error: "checksum mismatch"}
+    }
+
+    f.mu.Lock()
+    if device, ok := f.devices[deviceID]; ok {
+        device.firmware = version
+        device.reportPending = true
+        device.nextHeartbeat = time.Now()
+    }
+    f.mu.Unlock()
+
+    logger.Logger.Info("Installed firmware", "device", deviceID, "version", version, "bytes", size)
+    return dto.DeviceCommandReply{
+        Status: "succeeded",
+        Result: map[string]interface{}{"version": version, "bytes": size},
+    }
+}
+
+func reply(ch *amqp091.Channel, msg dto.DeviceCommandMessage, token string, reply dto.DeviceCommandReply) {
+    reply.CommandID = msg.ID
+    reply.DeviceID = msg.DeviceID
+    reply.Token = token
+
+    body, err := json.Marshal(reply)
+    if err != nil {
+        logger.Logger.Error("Error marshaling command reply", "error", err)
+        return
+    }
+
+    replyTo := msg.ReplyTo
+    if replyTo == "" {
+        replyTo = mq.CommandReplyQueue
+    }
+    err = ch.Publish("", replyTo, false, false, amqp091.Publishing{
+        ContentType: "application/json",
+        Body:        body,
+    })
+    if err != nil {
+        logger.Logger.Error("Failed to publish command reply", "command_id", msg.ID, "error", err)
+    }
+}
+
+type deviceSubscription struct {
+    ch       *amqp091.Channel
+    queue    string
+    messages <-chan amqp091.Delivery
+}
+
+// subscribeDevices declares a private queue for the shadow deltas and
+// commands of the simulated devices. Each device is bound with its own
+// routing keys.
+func subscribeDevices(ch *amqp091.Channel) (*deviceSubscription, error) {
+    if err := ch.ExchangeDeclare(mq.DeviceExchange, "topic", true, false, false, false, nil); err != nil {
+        return nil, err
+    }
+    q, err := ch.QueueDeclare("", false, true, true, false, nil)
+    if err != nil {
+        return nil, err
+    }
+    messages, err := ch.Consume(q.Name, "", true, true, false, false, nil)
+    if err != nil {
+        return nil, err
+    }
+    return &deviceSubscription{ch: ch, queue: q.Name, messages: messages}, nil
+}
+
+func (s *deviceSubscription) bind(deviceID uuid.UUID) error {
+    if err := s.ch.QueueBind(s.queue, mq.ShadowDeltaRoutingKey(deviceID), mq.DeviceExchange, false, nil); err != nil {
+        return err
+    }
+    return s.ch.QueueBind(s.queue, mq.CommandRoutingKey(deviceID), mq.DeviceExchange, false, nil)
+}
+
+// provisionDevices exchanges each claim for its device credentials, keyed by
+// serial number, and returns the claims that have to be retried because the
+// backend is unreachable or nobody claimed the device yet. A claim code that
+// is rejected or already used is dropped.
+func provisionDevices(provisionURL string, claims []dto.DeviceClaimRequest) (map[string]dto.ProvisionResponse, []dto.DeviceClaimRequest) {
+    provisioned := make(map[string]dto.ProvisionResponse)
+    var pending []dto.DeviceClaimRequest
+    for _, claim := range claims {
+        credential, retry, err := provisionDevice(provisionURL, claim)
+        switch {
+        case err == nil:
+            logger.Logger.Info("Provisioned device", "sn", claim.SN, "device", credential.DeviceID)
+            provisioned[claim.SN] = *credential
+        case retry:
+            logger.Logger.Warn("Provisioning deferred, will retry", "sn", claim.SN, "reason", err)
+            pending = append(pending, claim)
+        default:
+            logger.Logger.Error("Failed to provision device", "sn", claim.SN, "error", err)
+        }
+    }
+    return provisioned, pending
+}
+
+func provisionDevice(provisionURL string, claim dto.DeviceClaimRequest) (*dto.ProvisionResponse, bool, error) {
+    body, err := json.Marshal(claim)
+    if err != nil {
+        return nil, false, err
+    }
+
+    resp, err := http.Post(provisionURL, "application/json", bytes.NewReader(body))
+    if err != nil {
+        return nil, true, err
+    }
+    defer resp.Body.Close()
+
+    if resp.StatusCode != http.StatusOK {
+        var errResp dto.DetailedErrorResponse
+        json.NewDecoder(resp.Body).Decode(&errResp)
+        retry := resp.StatusCode >= http.StatusInternalServerError ||
+            (resp.StatusCode == http.StatusConflict && errResp.Message == errors.ErrDeviceNotClaimed.Message())
+        return nil, retry, fmt.Errorf("provisioning failed with status %d: %s", resp.StatusCode, errResp.Message)
+    }
+
+    var credentials dto.ProvisionResponse
+    if err := json.NewDecoder(resp.Body).Decode(&credentials); err != nil {
+        return nil, false, err
+    }
+    return &credentials, false, nil
+}
+
+// loadCredentials reads the credentials saved by saveCredentials, keyed by
+// serial number. A missing file means no device was provisioned yet.
+func loadCredentials(path string) (map[string]dto.ProvisionResponse, error) {
+    credentials := make(map[string]dto.ProvisionResponse)
+    data, err := os.ReadFile(path)
+    if os.IsNotExist(err) {
+        return credentials, nil
+    }
+    if err != nil {
+        return nil, err
+    }
+    if err := json.Unmarshal(data, &credentials); err != nil {
+        return nil, err
+    }
+    return credentials, nil
+}
+
+// saveCredentials replaces the credentials file atomically. The device tokens
+// are shown only once, so losing the file means provisioning the devices
+// again with new claim codes.
+func saveCredentials(path string, credentials map[string]dto.ProvisionResponse) error {
+    data, err := json.MarshalIndent(credentials, "", "  ")
+    if err != nil {
+        return err
+    }
+    if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+        return err
+    }
+    tmp := path + ".tmp"
+    if err := os.WriteFile(tmp, data, 0o600); err != nil {
+        return err
     }
-}
\ No newline at end of file
+    return os.Rename(tmp, path)
+}
@@ -3,6 +3,7 @@ module github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025
 go 1.25.1
 
 require (
+	github.com/DATA-DOG/go-sqlmock v1.5.2
 	github.com/rabbitmq/amqp091-go v1.10.0
 	github.com/stretchr/testify v1.11.1
 	github.com/swaggo/files v1.0.1
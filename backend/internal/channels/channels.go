@@ -0,0 +1,148 @@
+// Package channels implements the destinations notification alerts are
+// delivered to besides the WebSocket: HTTP webhooks, chat incoming webhooks
+// and email.
+package channels
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/dto"
+)
+
+// DefaultTimeout bounds each request made to a channel.
+const DefaultTimeout = 10 * time.Second
+
+var errInvalidHeader = errors.New("header names cannot be empty")
+
+// StatusError is returned when the receiver answers with a status outside
+// 2xx.
+type StatusError struct {
+	StatusCode int
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("unexpected status %d", e.StatusCode)
+}
+
+// Retryable reports whether the request may succeed later: server errors and
+// rate limiting are retried, other client errors are not.
+func (e *StatusError) Retryable() bool {
+	return e.StatusCode >= 500 || e.StatusCode == http.StatusTooManyRequests
+}
+
+// configError is returned when a stored config cannot be used; retrying
+// does not help.
+type configError struct {
+	err error
+}
+
+func (e *configError) Error() string {
+	return "invalid config: " + e.err.Error()
+}
+
+func (e *configError) Unwrap() error {
+	return e.err
+}
+
+func (e *configError) Retryable() bool {
+	return false
+}
+
+// decodeConfig decodes a channel config, rejecting unknown fields so that
+// typos do not go unnoticed.
+func decodeConfig(config []byte, v interface{}) error {
+	decoder := json.NewDecoder(bytes.NewReader(config))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(v); err != nil {
+		return &configError{err: err}
+	}
+	return nil
+}
+
+func validateURL(raw string) error {
+	if raw == "" {
+		return errors.New("url is required")
+	}
+	parsed, err := url.Parse(raw)
+	if err != nil {
+		return errors.New("url is invalid")
+	}
+	if parsed.Scheme != "http" && parsed.Scheme != "https" {
+		return errors.New("url must use http or https")
+	}
+	if parsed.Host == "" {
+		return errors.New("url must have a host")
+	}
+	return nil
+}
+
+// postJSON posts body to target and fails unless the response status is 2xx.
+func postJSON(ctx context.Context, client *http.Client, target string, headers map[string]string, body interface{}) error {
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	return post(ctx, client, target, headers, payload)
+}
+
+func post(ctx context.Context, client *http.Client, target string, headers map[string]string, payload []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, target, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return &StatusError{StatusCode: resp.StatusCode}
+	}
+	return nil
+}
+
+// alertSubject is the one line summary of an alert used as chat message
+// title and email subject.
+func alertSubject(alert dto.NotificationAlert) string {
+	if alert.Rendered != nil && alert.Rendered.Subject != "" {
+		return alert.Rendered.Subject
+	}
+	subject := fmt.Sprintf("%s triggered on device %s", alert.Name, alert.DeviceSN)
+	if alert.Severity != "" {
+		subject = "[" + strings.ToUpper(alert.Severity) + "] " + subject
+	}
+	return subject
+}
+
+// alertDetails describes the alert and the heartbeat that triggered it.
+func alertDetails(alert dto.NotificationAlert) string {
+	if alert.Rendered != nil && alert.Rendered.Text != "" {
+		return alert.Rendered.Text
+	}
+	var b strings.Builder
+	if alert.Description != "" {
+		fmt.Fprintf(&b, "%s\n", alert.Description)
+	}
+	fmt.Fprintf(&b, "Triggered value: %g\n", alert.TriggeredValue)
+	data := alert.HeartbeatData
+	fmt.Fprintf(&b, "CPU: %.1f%% | RAM: %.1f%% | Disk free: %.1f%% | Temperature: %.1f°C | Latency: %d ms | Connectivity: %d\n",
+		data.CPU, data.RAM, data.DiskFree, data.Temperature, data.Latency, data.Connectivity)
+	fmt.Fprintf(&b, "Time: %s", alert.Timestamp)
+	return b.String()
+}
@@ -0,0 +1,55 @@
+package channels
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/dto"
+)
+
+// ChatConfig is the config of a Slack or Teams channel: the URL of an
+// incoming webhook of the chat room.
+type ChatConfig struct {
+	URL string `json:"url"`
+}
+
+// ChatSender posts the alert as a text message to a Slack or Teams incoming
+// webhook. Both accept {"text": ...}; they only differ in how bold text is
+// written.
+type ChatSender struct {
+	client *http.Client
+	bold   string
+}
+
+func NewSlackSender(client *http.Client) *ChatSender {
+	return newChatSender(client, "*")
+}
+
+func NewTeamsSender(client *http.Client) *ChatSender {
+	return newChatSender(client, "**")
+}
+
+func newChatSender(client *http.Client, bold string) *ChatSender {
+	if client == nil {
+		client = &http.Client{Timeout: DefaultTimeout}
+	}
+	return &ChatSender{client: client, bold: bold}
+}
+
+func (s *ChatSender) Validate(config []byte) error {
+	var cfg ChatConfig
+	if err := decodeConfig(config, &cfg); err != nil {
+		return err
+	}
+	return validateURL(cfg.URL)
+}
+
+func (s *ChatSender) Send(ctx context.Context, config []byte, msg dto.ChannelMessage) error {
+	var cfg ChatConfig
+	if err := decodeConfig(config, &cfg); err != nil {
+		return err
+	}
+	text := fmt.Sprintf("%s%s%s\n%s", s.bold, alertSubject(msg.Alert), s.bold, alertDetails(msg.Alert))
+	return postJSON(ctx, s.client, cfg.URL, nil, map[string]string{"text": text})
+}
@@ -0,0 +1,64 @@
+package channels
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/dto"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChatSender_Send(t *testing.T) {
+	tests := []struct {
+		name   string
+		sender func(*http.Client) *ChatSender
+		title  string
+	}{
+		{"Slack", NewSlackSender, "*High CPU Alert triggered on device SN123456*"},
+		{"Teams", NewTeamsSender, "**High CPU Alert triggered on device SN123456**"},
+	}
+
+	for _, tt := range tests {
+		t.Run("Success - "+tt.name+" text message", func(t *testing.T) {
+			var received map[string]string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				json.NewDecoder(r.Body).Decode(&received)
+				w.Write([]byte("ok"))
+			}))
+			defer server.Close()
+
+			config, _ := json.Marshal(ChatConfig{URL: server.URL})
+			err := tt.sender(server.Client()).Send(context.Background(), config, dto.ChannelMessage{Alert: testAlert()})
+
+			assert.NoError(t, err)
+			assert.Contains(t, received["text"], tt.title+"\n")
+			assert.Contains(t, received["text"], "Triggered value: 92.5")
+		})
+	}
+
+	t.Run("Success - Rendered title with the default body", func(t *testing.T) {
+		var received map[string]string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			json.NewDecoder(r.Body).Decode(&received)
+			w.Write([]byte("ok"))
+		}))
+		defer server.Close()
+
+		alert := testAlert()
+		alert.Rendered = &dto.RenderedMessage{Subject: "CPU at 92.5% on SN123456"}
+		config, _ := json.Marshal(ChatConfig{URL: server.URL})
+		err := NewSlackSender(server.Client()).Send(context.Background(), config, dto.ChannelMessage{Alert: alert})
+
+		assert.NoError(t, err)
+		assert.Contains(t, received["text"], "*CPU at 92.5% on SN123456*\n")
+		assert.Contains(t, received["text"], "Triggered value: 92.5")
+	})
+
+	t.Run("Error - Invalid config", func(t *testing.T) {
+		err := NewSlackSender(nil).Validate([]byte(`{"url": "not a url"}`))
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,164 @@
+package channels
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"mime"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/dto"
+)
+
+// MaxEmailRecipients caps the addresses of an email channel.
+const MaxEmailRecipients = 20
+
+// SMTPConfig is the server email channels send through.
+type SMTPConfig struct {
+	Host     string
+	Port     int
+	Username string
+	Password string
+	From     string
+}
+
+// EmailConfig is the config of an email channel.
+type EmailConfig struct {
+	To []string `json:"to"`
+}
+
+// EmailSender sends the alert as a plain text email, with an HTML
+// alternative when the message was rendered with one. The connection is
+// upgraded with STARTTLS when the server offers it, and authenticated when
+// a username is configured.
+type EmailSender struct {
+	smtp SMTPConfig
+}
+
+func NewEmailSender(config SMTPConfig) *EmailSender {
+	if config.Port == 0 {
+		config.Port = 587
+	}
+	return &EmailSender{smtp: config}
+}
+
+func (s *EmailSender) Validate(config []byte) error {
+	var cfg EmailConfig
+	if err := decodeConfig(config, &cfg); err != nil {
+		return err
+	}
+	if len(cfg.To) == 0 {
+		return errors.New("to must list at least one address")
+	}
+	if len(cfg.To) > MaxEmailRecipients {
+		return fmt.Errorf("to can list at most %d addresses", MaxEmailRecipients)
+	}
+	for _, address := range cfg.To {
+		if _, err := mail.ParseAddress(address); err != nil {
+			return fmt.Errorf("invalid address %q", address)
+		}
+	}
+	return nil
+}
+
+func (s *EmailSender) Send(ctx context.Context, config []byte, msg dto.ChannelMessage) error {
+	var cfg EmailConfig
+	if err := decodeConfig(config, &cfg); err != nil {
+		return err
+	}
+
+	addr := net.JoinHostPort(s.smtp.Host, strconv.Itoa(s.smtp.Port))
+	conn, err := (&net.Dialer{}).DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return err
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	client, err := smtp.NewClient(conn, s.smtp.Host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer client.Close()
+
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err := client.StartTLS(&tls.Config{ServerName: s.smtp.Host}); err != nil {
+			return err
+		}
+	}
+	if s.smtp.Username != "" {
+		if err := client.Auth(smtp.PlainAuth("", s.smtp.Username, s.smtp.Password, s.smtp.Host)); err != nil {
+			return err
+		}
+	}
+
+	if err := client.Mail(s.smtp.From); err != nil {
+		return err
+	}
+	for _, address := range cfg.To {
+		parsed, _ := mail.ParseAddress(address)
+		if parsed == nil {
+			return fmt.Errorf("invalid address %q", address)
+		}
+		if err := client.Rcpt(parsed.Address); err != nil {
+			return err
+		}
+	}
+
+	w, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(s.message(cfg.To, msg.Alert)); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return client.Quit()
+}
+
+func (s *EmailSender) message(to []string, alert dto.NotificationAlert) []byte {
+	var b strings.Builder
+	fmt.Fprintf(&b, "From: %s\r\n", s.smtp.From)
+	fmt.Fprintf(&b, "To: %s\r\n", strings.Join(to, ", "))
+	fmt.Fprintf(&b, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", alertSubject(alert)))
+	fmt.Fprintf(&b, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	b.WriteString("MIME-Version: 1.0\r\n")
+	if alert.Rendered == nil || alert.Rendered.HTML == "" {
+		b.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+		b.WriteString("\r\n")
+		b.WriteString(crlf(alertDetails(alert)))
+		b.WriteString("\r\n")
+		return []byte(b.String())
+	}
+
+	boundary := fmt.Sprintf("alt-%d", time.Now().UnixNano())
+	fmt.Fprintf(&b, "Content-Type: multipart/alternative; boundary=%q\r\n", boundary)
+	b.WriteString("\r\n")
+	for _, part := range []struct{ contentType, body string }{
+		{"text/plain", alertDetails(alert)},
+		{"text/html", alert.Rendered.HTML},
+	} {
+		fmt.Fprintf(&b, "--%s\r\n", boundary)
+		fmt.Fprintf(&b, "Content-Type: %s; charset=utf-8\r\n", part.contentType)
+		b.WriteString("\r\n")
+		b.WriteString(crlf(part.body))
+		b.WriteString("\r\n")
+	}
+	fmt.Fprintf(&b, "--%s--\r\n", boundary)
+	return []byte(b.String())
+}
+
+// crlf converts line endings to the CRLF mail bodies require.
+func crlf(text string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(text, "\r\n", "\n"), "\n", "\r\n")
+}
@@ -0,0 +1,152 @@
+package channels
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/dto"
+	"github.com/stretchr/testify/assert"
+)
+
+// smtpStub is a minimal SMTP server accepting a single message.
+type smtpStub struct {
+	listener   net.Listener
+	recipients []string
+	data       string
+	done       chan struct{}
+}
+
+func newSMTPStub(t *testing.T) *smtpStub {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+
+	stub := &smtpStub{listener: listener, done: make(chan struct{})}
+	go stub.serve()
+	t.Cleanup(func() { listener.Close() })
+	return stub
+}
+
+func (s *smtpStub) port() int {
+	return s.listener.Addr().(*net.TCPAddr).Port
+}
+
+func (s *smtpStub) serve() {
+	defer close(s.done)
+	conn, err := s.listener.Accept()
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	reader := bufio.NewReader(conn)
+	reply := func(line string) { conn.Write([]byte(line + "\r\n")) }
+	reply("220 stub ESMTP")
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return
+		}
+		command := strings.ToUpper(strings.TrimSpace(line))
+		switch {
+		case strings.HasPrefix(command, "EHLO"), strings.HasPrefix(command, "HELO"):
+			reply("250 stub")
+		case strings.HasPrefix(command, "RCPT TO:"):
+			s.recipients = append(s.recipients, strings.Trim(strings.TrimSpace(line)[len("RCPT TO:"):], "<>"))
+			reply("250 OK")
+		case command == "DATA":
+			reply("354 End data with <CR><LF>.<CR><LF>")
+			var data strings.Builder
+			for {
+				dataLine, err := reader.ReadString('\n')
+				if err != nil || dataLine == ".\r\n" {
+					break
+				}
+				data.WriteString(dataLine)
+			}
+			s.data = data.String()
+			reply("250 OK")
+		case command == "QUIT":
+			reply("221 Bye")
+			return
+		default:
+			reply("250 OK")
+		}
+	}
+}
+
+func TestEmailSender_Validate(t *testing.T) {
+	sender := NewEmailSender(SMTPConfig{Host: "localhost"})
+
+	t.Run("Success - Valid addresses", func(t *testing.T) {
+		err := sender.Validate([]byte(`{"to": ["ops@example.com", "On call <oncall@example.com>"]}`))
+		assert.NoError(t, err)
+	})
+
+	t.Run("Error - No recipients", func(t *testing.T) {
+		err := sender.Validate([]byte(`{"to": []}`))
+		assert.ErrorContains(t, err, "at least one address")
+	})
+
+	t.Run("Error - Invalid address", func(t *testing.T) {
+		err := sender.Validate([]byte(`{"to": ["not an address"]}`))
+		assert.ErrorContains(t, err, "invalid address")
+	})
+}
+
+func TestEmailSender_Send(t *testing.T) {
+	t.Run("Success - Sends the alert to every recipient", func(t *testing.T) {
+		stub := newSMTPStub(t)
+		sender := NewEmailSender(SMTPConfig{Host: "127.0.0.1", Port: stub.port(), From: "alerts@example.com"})
+
+		err := sender.Send(context.Background(), []byte(`{"to": ["ops@example.com", "On call <oncall@example.com>"]}`), dto.ChannelMessage{Alert: testAlert()})
+		<-stub.done
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"ops@example.com", "oncall@example.com"}, stub.recipients)
+		assert.Contains(t, stub.data, "Subject: High CPU Alert triggered on device SN123456\r\n")
+		assert.Contains(t, stub.data, "Triggered value: 92.5\r\n")
+	})
+
+	t.Run("Success - Subject flags the severity", func(t *testing.T) {
+		stub := newSMTPStub(t)
+		sender := NewEmailSender(SMTPConfig{Host: "127.0.0.1", Port: stub.port(), From: "alerts@example.com"})
+
+		alert := testAlert()
+		alert.Severity = "critical"
+		err := sender.Send(context.Background(), []byte(`{"to": ["ops@example.com"]}`), dto.ChannelMessage{Alert: alert})
+		<-stub.done
+
+		assert.NoError(t, err)
+		assert.Contains(t, stub.data, "Subject: [CRITICAL] High CPU Alert triggered on device SN123456\r\n")
+	})
+
+	t.Run("Success - Rendered message sent as text and HTML", func(t *testing.T) {
+		stub := newSMTPStub(t)
+		sender := NewEmailSender(SMTPConfig{Host: "127.0.0.1", Port: stub.port(), From: "alerts@example.com"})
+
+		alert := testAlert()
+		alert.Rendered = &dto.RenderedMessage{Subject: "Daily digest", Text: "Alerts fired: 2\n", HTML: "<h3>Alerts fired: 2</h3>"}
+		err := sender.Send(context.Background(), []byte(`{"to": ["ops@example.com"]}`), dto.ChannelMessage{Alert: alert})
+		<-stub.done
+
+		assert.NoError(t, err)
+		assert.Contains(t, stub.data, "Subject: Daily digest\r\n")
+		assert.Contains(t, stub.data, "Content-Type: multipart/alternative;")
+		assert.Contains(t, stub.data, "Content-Type: text/plain; charset=utf-8\r\n\r\nAlerts fired: 2\r\n")
+		assert.Contains(t, stub.data, "Content-Type: text/html; charset=utf-8\r\n\r\n<h3>Alerts fired: 2</h3>\r\n")
+	})
+
+	t.Run("Error - Server unreachable", func(t *testing.T) {
+		listener, _ := net.Listen("tcp", "127.0.0.1:0")
+		port := listener.Addr().(*net.TCPAddr).Port
+		listener.Close()
+		sender := NewEmailSender(SMTPConfig{Host: "127.0.0.1", Port: port, From: "alerts@example.com"})
+
+		err := sender.Send(context.Background(), []byte(`{"to": ["ops@example.com"]}`), dto.ChannelMessage{Alert: testAlert()})
+
+		assert.Error(t, err)
+	})
+}
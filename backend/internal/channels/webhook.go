@@ -0,0 +1,147 @@
+package channels
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/dto"
+)
+
+const (
+	// WebhookIDHeader carries the delivery ID, which stays the same across
+	// retries so receivers can discard duplicates.
+	WebhookIDHeader = "X-Webhook-Id"
+	// WebhookSignatureHeader carries "t=<unix timestamp>,v1=<signature>";
+	// during a secret rotation it holds a v1 signature per secret.
+	WebhookSignatureHeader = "X-Webhook-Signature"
+)
+
+// WebhookConfig is the config of a generic webhook channel. Headers are
+// added to every request, e.g. to authenticate with the receiver.
+type WebhookConfig struct {
+	URL     string            `json:"url"`
+	Headers map[string]string `json:"headers"`
+}
+
+// WebhookSender posts the alert as JSON to the configured URL, signed with
+// the secrets of the message.
+type WebhookSender struct {
+	client *http.Client
+	now    func() time.Time
+}
+
+func NewWebhookSender(client *http.Client) *WebhookSender {
+	if client == nil {
+		client = &http.Client{Timeout: DefaultTimeout}
+	}
+	return &WebhookSender{client: client, now: time.Now}
+}
+
+func (s *WebhookSender) Validate(config []byte) error {
+	var cfg WebhookConfig
+	if err := decodeConfig(config, &cfg); err != nil {
+		return err
+	}
+	if err := validateURL(cfg.URL); err != nil {
+		return err
+	}
+	for name := range cfg.Headers {
+		if strings.TrimSpace(name) == "" {
+			return errInvalidHeader
+		}
+		if strings.HasPrefix(http.CanonicalHeaderKey(name), "X-Webhook-") {
+			return fmt.Errorf("header %s is reserved", name)
+		}
+	}
+	return nil
+}
+
+func (s *WebhookSender) Send(ctx context.Context, config []byte, msg dto.ChannelMessage) error {
+	var cfg WebhookConfig
+	if err := decodeConfig(config, &cfg); err != nil {
+		return err
+	}
+
+	payload, err := json.Marshal(msg.Alert)
+	if err != nil {
+		return err
+	}
+
+	headers := make(map[string]string, len(cfg.Headers)+2)
+	for name, value := range cfg.Headers {
+		headers[name] = value
+	}
+	headers[WebhookIDHeader] = msg.DeliveryID.String()
+	if len(msg.Secrets) > 0 {
+		headers[WebhookSignatureHeader] = SignatureHeader(msg.Secrets, s.now().Unix(), payload)
+	}
+	return post(ctx, s.client, cfg.URL, headers, payload)
+}
+
+// SignatureHeader builds the signature header of a payload sent at
+// timestamp, with a v1 signature per secret.
+func SignatureHeader(secrets []string, timestamp int64, payload []byte) string {
+	parts := []string{"t=" + strconv.FormatInt(timestamp, 10)}
+	for _, secret := range secrets {
+		parts = append(parts, "v1="+Sign(secret, timestamp, payload))
+	}
+	return strings.Join(parts, ",")
+}
+
+// Sign returns the hex encoded HMAC-SHA256 of "<timestamp>.<payload>".
+func Sign(secret string, timestamp int64, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(payload)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerifySignature checks a signature header against the payload received,
+// as a receiver would: one of its v1 signatures must match secret and its
+// timestamp must be within tolerance of now, which rejects replays.
+func VerifySignature(header string, payload []byte, secret string, tolerance time.Duration, now time.Time) error {
+	var timestamp int64
+	var signatures []string
+	for _, part := range strings.Split(header, ",") {
+		key, value, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok {
+			continue
+		}
+		switch key {
+		case "t":
+			parsed, err := strconv.ParseInt(value, 10, 64)
+			if err != nil {
+				return errors.New("invalid signature timestamp")
+			}
+			timestamp = parsed
+		case "v1":
+			signatures = append(signatures, value)
+		}
+	}
+	if timestamp == 0 || len(signatures) == 0 {
+		return errors.New("missing signature")
+	}
+
+	age := now.Sub(time.Unix(timestamp, 0))
+	if age > tolerance || age < -tolerance {
+		return errors.New("signature timestamp outside tolerance")
+	}
+
+	expected := Sign(secret, timestamp, payload)
+	for _, signature := range signatures {
+		if hmac.Equal([]byte(signature), []byte(expected)) {
+			return nil
+		}
+	}
+	return errors.New("signature mismatch")
+}
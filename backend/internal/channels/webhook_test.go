@@ -0,0 +1,118 @@
+package channels
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/dto"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func testAlert() dto.NotificationAlert {
+	return dto.NotificationAlert{
+		ID:             uuid.New(),
+		UserID:         uuid.New(),
+		Name:           "High CPU Alert",
+		Description:    "CPU above 90%",
+		DeviceID:       uuid.New(),
+		DeviceSN:       "SN123456",
+		TriggeredValue: 92.5,
+		Timestamp:      "2025-10-01T12:00:00Z",
+		HeartbeatData:  dto.AlertHeartbeatData{CPU: 92.5, RAM: 40, DiskFree: 60, Temperature: 55, Latency: 20, Connectivity: 1},
+	}
+}
+
+func TestWebhookSender_Validate(t *testing.T) {
+	sender := NewWebhookSender(nil)
+
+	t.Run("Success - URL with headers", func(t *testing.T) {
+		err := sender.Validate([]byte(`{"url": "https://example.com/hook", "headers": {"Authorization": "Bearer token"}}`))
+		assert.NoError(t, err)
+	})
+
+	t.Run("Error - Missing URL", func(t *testing.T) {
+		err := sender.Validate([]byte(`{}`))
+		assert.ErrorContains(t, err, "url is required")
+	})
+
+	t.Run("Error - Unsupported scheme", func(t *testing.T) {
+		err := sender.Validate([]byte(`{"url": "ftp://example.com/hook"}`))
+		assert.ErrorContains(t, err, "http or https")
+	})
+
+	t.Run("Error - Reserved header", func(t *testing.T) {
+		err := sender.Validate([]byte(`{"url": "https://example.com/hook", "headers": {"x-webhook-signature": "forged"}}`))
+		assert.ErrorContains(t, err, "reserved")
+	})
+
+	t.Run("Error - Unknown field", func(t *testing.T) {
+		err := sender.Validate([]byte(`{"uri": "https://example.com/hook"}`))
+		assert.ErrorContains(t, err, "invalid config")
+	})
+}
+
+func TestWebhookSender_Send(t *testing.T) {
+	alert := testAlert()
+	deliveryID := uuid.New()
+
+	t.Run("Success - Posts the signed alert with the headers", func(t *testing.T) {
+		var body []byte
+		var header http.Header
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			header = r.Header
+			body, _ = io.ReadAll(r.Body)
+			w.WriteHeader(http.StatusNoContent)
+		}))
+		defer server.Close()
+
+		config, _ := json.Marshal(WebhookConfig{URL: server.URL, Headers: map[string]string{"Authorization": "Bearer token"}})
+		msg := dto.ChannelMessage{DeliveryID: deliveryID, Secrets: []string{"whsec_new", "whsec_old"}, Alert: alert}
+		err := NewWebhookSender(server.Client()).Send(context.Background(), config, msg)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "Bearer token", header.Get("Authorization"))
+		assert.Equal(t, deliveryID.String(), header.Get(WebhookIDHeader))
+
+		var received dto.NotificationAlert
+		json.Unmarshal(body, &received)
+		assert.Equal(t, alert, received)
+
+		signature := header.Get(WebhookSignatureHeader)
+		assert.NoError(t, VerifySignature(signature, body, "whsec_new", 5*time.Minute, time.Now()))
+		assert.NoError(t, VerifySignature(signature, body, "whsec_old", 5*time.Minute, time.Now()))
+		assert.EqualError(t, VerifySignature(signature, body, "whsec_other", 5*time.Minute, time.Now()), "signature mismatch")
+		assert.EqualError(t, VerifySignature(signature, append(body, ' '), "whsec_new", 5*time.Minute, time.Now()), "signature mismatch")
+		assert.EqualError(t, VerifySignature(signature, body, "whsec_new", 5*time.Minute, time.Now().Add(time.Hour)), "signature timestamp outside tolerance")
+	})
+
+	t.Run("Error - Server error is retryable", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusBadGateway)
+		}))
+		defer server.Close()
+
+		config, _ := json.Marshal(WebhookConfig{URL: server.URL})
+		err := NewWebhookSender(server.Client()).Send(context.Background(), config, dto.ChannelMessage{Alert: alert})
+
+		assert.EqualError(t, err, "unexpected status 502")
+		assert.True(t, err.(*StatusError).Retryable())
+	})
+
+	t.Run("Error - Client error is not retryable", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusGone)
+		}))
+		defer server.Close()
+
+		config, _ := json.Marshal(WebhookConfig{URL: server.URL})
+		err := NewWebhookSender(server.Client()).Send(context.Background(), config, dto.ChannelMessage{Alert: alert})
+
+		assert.False(t, err.(*StatusError).Retryable())
+	})
+}
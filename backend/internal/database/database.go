@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/models"
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/repository"
 	logger "github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/utils/logger"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
@@ -70,8 +71,48 @@ func AutoMigrate(db *gorm.DB) error {
 		&models.Device{},
 		&models.Heartbeat{},
 		&models.Notification{},
+		&models.DeviceGroup{},
+		&models.Organization{},
+		&models.Membership{},
+		&models.DeviceShare{},
+		&models.DeviceTransfer{},
+		&models.AuditLog{},
+		&models.DeviceRegistration{},
+		&models.DeviceShadow{},
+		&models.DeviceCommand{},
+		&models.FirmwareArtifact{},
+		&models.FirmwareRollout{},
+		&models.FirmwareRolloutDevice{},
+		&models.MaintenanceWindow{},
+		&models.SuppressedAlert{},
+		&models.NotificationChannel{},
+		&models.NotificationDelivery{},
+		&models.DeliveryAttempt{},
+		&models.EscalationPolicy{},
+		&models.Alert{},
+		&models.NotificationPreference{},
+		&models.HeldNotification{},
+		&models.Mute{},
 		); err != nil {
 		return fmt.Errorf("failed to auto migrate: %w", err)
 	}
+
+	createSearchIndexes(db)
 	return nil
+}
+
+// createSearchIndexes adds the trigram index backing the device substring
+// search. It needs the pg_trgm extension; without it search still works,
+// only slower, so failures are logged instead of aborting startup.
+func createSearchIndexes(db *gorm.DB) {
+	if err := db.Exec("CREATE EXTENSION IF NOT EXISTS pg_trgm").Error; err != nil {
+		logger.Logger.Warn("pg_trgm extension unavailable, device search will not be indexed", "error", err.Error())
+		return
+	}
+
+	statement := "CREATE INDEX IF NOT EXISTS idx_devices_search_trgm ON devices USING gin (" +
+		repository.DeviceSearchExpression + " gin_trgm_ops)"
+	if err := db.Exec(statement).Error; err != nil {
+		logger.Logger.Warn("failed to create device search index", "error", err.Error())
+	}
 }
\ No newline at end of file
@@ -0,0 +1,18 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// @Description Audit log entry
+type AuditLogResponse struct {
+	ID         uuid.UUID              `json:"id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	ActorID    uuid.UUID              `json:"actor_id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	Action     string                 `json:"action" example:"device.transfer.accepted"`
+	EntityType string                 `json:"entity_type" example:"device"`
+	EntityID   uuid.UUID              `json:"entity_id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	Details    map[string]interface{} `json:"details"`
+	CreatedAt  time.Time              `json:"created_at" example:"2023-01-01T12:00:00Z"`
+}
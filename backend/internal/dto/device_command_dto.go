@@ -0,0 +1,51 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// @Description Command to run on a device
+type DeviceCommandRequest struct {
+	Name           string                 `json:"name" binding:"required" example:"reboot"` // reboot, upload_logs or run_diagnostics
+	Params         map[string]interface{} `json:"params" swaggertype:"object"`
+	TimeoutSeconds int                    `json:"timeout_seconds" example:"300"` // Time the device has to finish the command, 300 by default
+}
+
+// @Description Command issued to a device
+type DeviceCommandResponse struct {
+	ID             uuid.UUID              `json:"id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	DeviceID       uuid.UUID              `json:"device_id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	IssuedBy       uuid.UUID              `json:"issued_by" example:"550e8400-e29b-41d4-a716-446655440000"`
+	Name           string                 `json:"name" example:"reboot"`
+	Params         map[string]interface{} `json:"params" swaggertype:"object"`
+	Status         string                 `json:"status" example:"succeeded"` // pending, sent, acknowledged, succeeded, failed, expired or cancelled
+	Result         map[string]interface{} `json:"result,omitempty" swaggertype:"object"`
+	Error          string                 `json:"error,omitempty" example:"disk full"`
+	ExpiresAt      time.Time              `json:"expires_at" example:"2023-01-01T12:05:00Z"`
+	SentAt         *time.Time             `json:"sent_at" example:"2023-01-01T12:00:00Z"`
+	AcknowledgedAt *time.Time             `json:"acknowledged_at" example:"2023-01-01T12:00:01Z"`
+	CompletedAt    *time.Time             `json:"completed_at" example:"2023-01-01T12:00:30Z"`
+	CreatedAt      time.Time              `json:"created_at" example:"2023-01-01T12:00:00Z"`
+}
+
+// @Description Command published to a device
+type DeviceCommandMessage struct {
+	ID        string                 `json:"id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	DeviceID  string                 `json:"device_id" example:"f8aac3d7-b2ac-43e8-94d5-bbee59dd4ac9"`
+	Name      string                 `json:"name" example:"reboot"`
+	Params    map[string]interface{} `json:"params" swaggertype:"object"`
+	ExpiresAt time.Time              `json:"expires_at" example:"2023-01-01T12:05:00Z"`
+	ReplyTo   string                 `json:"reply_to" example:"command_results"` // Queue the device publishes its replies to
+}
+
+// @Description Reply a device publishes while running a command
+type DeviceCommandReply struct {
+	CommandID string                 `json:"command_id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	DeviceID  string                 `json:"device_id" example:"f8aac3d7-b2ac-43e8-94d5-bbee59dd4ac9"`
+	Token     string                 `json:"token,omitempty" example:"dt_4f9c2a..."` // Device token returned by provisioning
+	Status    string                 `json:"status" example:"succeeded"`             // acknowledged, succeeded or failed
+	Result    map[string]interface{} `json:"result,omitempty" swaggertype:"object"`
+	Error     string                 `json:"error,omitempty" example:"disk full"`
+}
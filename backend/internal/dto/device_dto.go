@@ -23,12 +23,60 @@ type UpdateDeviceRequest struct {
 
 // @Description Device response
 type DeviceResponse struct {
-	UUID        uuid.UUID `json:"uuid"`
-	Name        string    `json:"name"`
-	Location    string    `json:"location"`
-	SN          string    `json:"sn"`
-	Description string    `json:"description"`
-	UserID      uuid.UUID `json:"user_id"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
-}
\ No newline at end of file
+	UUID            uuid.UUID         `json:"uuid"`
+	Name            string            `json:"name"`
+	Location        string            `json:"location"`
+	SN              string            `json:"sn"`
+	Description     string            `json:"description"`
+	Labels          map[string]string `json:"labels"`
+	UserID          uuid.UUID         `json:"user_id"`
+	OrganizationID  *uuid.UUID        `json:"organization_id"`
+	Shared          bool              `json:"shared"` // Reached through a share grant rather than ownership
+	GroupID         *uuid.UUID        `json:"group_id"`
+	FirmwareVersion string            `json:"firmware_version"` // Last version reported in a heartbeat
+	LastSeenAt      *time.Time        `json:"last_seen_at"`
+	CreatedAt       time.Time         `json:"created_at"`
+	UpdatedAt       time.Time         `json:"updated_at"`
+	DeletedAt       *time.Time        `json:"deleted_at"` // Set on devices listed by /devices/deleted
+}
+
+// @Description Query parameters for listing devices
+type DeviceListQuery struct {
+	Search   string `form:"q" example:"gateway"`               // Substring match on name, description and SN
+	Location string `form:"location" example:"Sao Paulo"`      // Exact location filter
+	Sort     string `form:"sort" example:"name"`               // name, created_at or last_seen
+	Order    string `form:"order" example:"asc"`               // asc or desc
+	Limit    int    `form:"limit" example:"50"`                // Page size; all devices when omitted
+	Offset   int    `form:"offset" example:"0"`                // Number of devices to skip
+	Labels   string `form:"labels" example:"env=prod,site=sp"` // Label selector
+}
+
+// @Description Labels attached to a device
+type DeviceLabelsRequest struct {
+	Labels map[string]string `json:"labels" example:"env:prod,site:sp"`
+}
+
+// @Description Value of a single device label
+type DeviceLabelRequest struct {
+	Value string `json:"value" example:"prod"`
+}
+
+// @Description Outcome of a single row of a bulk device import
+type DeviceImportResult struct {
+	Row      int        `json:"row" example:"1"`
+	SN       string     `json:"sn" example:"123456789012"`
+	Status   string     `json:"status" example:"created"`
+	DeviceID *uuid.UUID `json:"device_id,omitempty" example:"550e8400-e29b-41d4-a716-446655440000"`
+	Error    string     `json:"error,omitempty" example:"Serial number must be exactly 12 digits"`
+}
+
+// @Description Per-row report of a bulk device import
+type DeviceImportResponse struct {
+	DryRun  bool                 `json:"dry_run" example:"false"`
+	Mode    string               `json:"mode" example:"all_or_nothing"`
+	Total   int                  `json:"total" example:"300"`
+	Valid   int                  `json:"valid" example:"298"`
+	Created int                  `json:"created" example:"0"`
+	Failed  int                  `json:"failed" example:"2"`
+	Results []DeviceImportResult `json:"results"`
+}
@@ -0,0 +1,65 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// @Description Create or update device group request
+type DeviceGroupRequest struct {
+	Name        string     `json:"name" binding:"required" example:"Rack 01"`
+	Description string     `json:"description" example:"First rack of the Sao Paulo site"`
+	ParentID    *uuid.UUID `json:"parent_id" example:"550e8400-e29b-41d4-a716-446655440000"`
+}
+
+// @Description Devices to add to a group
+type DeviceGroupMembersRequest struct {
+	DeviceIDs []uuid.UUID `json:"device_ids" binding:"required" example:"550e8400-e29b-41d4-a716-446655440000"`
+}
+
+// @Description Device group
+type DeviceGroupResponse struct {
+	ID             uuid.UUID  `json:"id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	UserID         uuid.UUID  `json:"user_id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	OrganizationID *uuid.UUID `json:"organization_id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	ParentID       *uuid.UUID `json:"parent_id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	Name           string     `json:"name" example:"Rack 01"`
+	Description    string     `json:"description" example:"First rack of the Sao Paulo site"`
+	CreatedAt      time.Time  `json:"created_at" example:"2023-01-01T12:00:00Z"`
+	UpdatedAt      time.Time  `json:"updated_at" example:"2023-01-01T12:00:00Z"`
+}
+
+// @Description Device status counts of a group
+type DeviceGroupStatusCounts struct {
+	Total     int `json:"total" example:"12"`
+	Online    int `json:"online" example:"10"`
+	Offline   int `json:"offline" example:"1"`
+	NeverSeen int `json:"never_seen" example:"1"`
+}
+
+// @Description Aggregate of one metric over the latest heartbeat of each member device
+type MetricRollup struct {
+	Average float64 `json:"average" example:"42.5"`
+	Min     float64 `json:"min" example:"12.3"`
+	Max     float64 `json:"max" example:"87.1"`
+}
+
+// @Description Latest metrics rolled up across the member devices of a group
+type DeviceGroupMetrics struct {
+	Devices      int          `json:"devices" example:"11"`
+	CPU          MetricRollup `json:"cpu"`
+	RAM          MetricRollup `json:"ram"`
+	DiskFree     MetricRollup `json:"disk_free"`
+	Temperature  MetricRollup `json:"temperature"`
+	Latency      MetricRollup `json:"latency"`
+	Connectivity MetricRollup `json:"connectivity"`
+}
+
+// @Description Group-level view over a group and all its subgroups
+type DeviceGroupSummaryResponse struct {
+	Group         DeviceGroupResponse     `json:"group"`
+	SubgroupCount int                     `json:"subgroup_count" example:"3"`
+	Status        DeviceGroupStatusCounts `json:"status"`
+	LatestMetrics *DeviceGroupMetrics     `json:"latest_metrics"`
+}
@@ -0,0 +1,39 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// @Description Pre-register serial numbers for zero-touch provisioning
+type DeviceRegistrationRequest struct {
+	Devices    []CreateDeviceRequest `json:"devices" binding:"required,min=1"`
+	Unassigned bool                  `json:"unassigned" example:"false"` // Leave the devices for users to claim instead of assigning them to the caller
+}
+
+// @Description Pre-registered serial number
+type DeviceRegistrationResponse struct {
+	ID             uuid.UUID  `json:"id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	SN             string     `json:"sn" example:"123456789012"`
+	Name           string     `json:"name" example:"Gateway 01"`
+	Location       string     `json:"location" example:"Sao Paulo"`
+	Status         string     `json:"status" example:"assigned"`                          // unassigned, assigned or provisioned
+	ClaimCode      string     `json:"claim_code,omitempty" example:"7KQM-XR2P-9ZC4-HW3N"` // Only returned when the registration is created
+	OrganizationID *uuid.UUID `json:"organization_id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	DeviceID       *uuid.UUID `json:"device_id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	ProvisionedAt  *time.Time `json:"provisioned_at" example:"2023-01-01T12:00:00Z"`
+	CreatedAt      time.Time  `json:"created_at" example:"2023-01-01T12:00:00Z"`
+}
+
+// @Description Serial number and one-time claim code of a pre-registered device
+type DeviceClaimRequest struct {
+	SN        string `json:"sn" binding:"required" example:"123456789012"`
+	ClaimCode string `json:"claim_code" binding:"required" example:"7KQM-XR2P-9ZC4-HW3N"`
+}
+
+// @Description Credentials of a provisioned device
+type ProvisionResponse struct {
+	DeviceID    uuid.UUID `json:"device_id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	DeviceToken string    `json:"device_token" example:"dt_4f9c2a..."` // Shown once; only its hash is stored
+}
@@ -0,0 +1,33 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// @Description Desired and reported configuration of a device
+type DeviceShadowResponse struct {
+	DeviceID          uuid.UUID              `json:"device_id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	Desired           map[string]interface{} `json:"desired" swaggertype:"object"`
+	Reported          map[string]interface{} `json:"reported" swaggertype:"object"`
+	Delta             map[string]interface{} `json:"delta" swaggertype:"object"` // Desired values the device has not reported yet
+	DesiredVersion    int64                  `json:"desired_version" example:"3"`
+	ReportedVersion   int64                  `json:"reported_version" example:"2"`
+	DesiredUpdatedAt  *time.Time             `json:"desired_updated_at" example:"2023-01-01T12:00:00Z"`
+	ReportedUpdatedAt *time.Time             `json:"reported_updated_at" example:"2023-01-01T12:00:00Z"`
+}
+
+// @Description Desired configuration update
+type DeviceShadowDesiredRequest struct {
+	Desired map[string]interface{} `json:"desired" binding:"required" swaggertype:"object"`
+	Version *int64                 `json:"version" example:"3"` // Expected desired_version; the update fails with 409 when the shadow changed since
+}
+
+// @Description Delta pushed to a device when its desired configuration changes
+type ShadowDeltaMessage struct {
+	DeviceID  string                 `json:"device_id" example:"f8aac3d7-b2ac-43e8-94d5-bbee59dd4ac9"`
+	Version   int64                  `json:"version" example:"3"` // desired_version the delta was computed from
+	Delta     map[string]interface{} `json:"delta" swaggertype:"object"`
+	Timestamp time.Time              `json:"timestamp" example:"2023-01-01T12:00:00Z"`
+}
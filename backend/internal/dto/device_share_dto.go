@@ -0,0 +1,26 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// @Description Share a device with another registered user
+type DeviceShareRequest struct {
+	Email      string     `json:"email" binding:"required" example:"contractor@example.com"`
+	Permission string     `json:"permission" binding:"required" example:"viewer"` // viewer (read-only) or operator
+	ExpiresAt  *time.Time `json:"expires_at" example:"2023-02-01T00:00:00Z"`      // Optional; the grant never expires when omitted
+}
+
+// @Description Device share grant
+type DeviceShareResponse struct {
+	ID         uuid.UUID  `json:"id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	DeviceID   uuid.UUID  `json:"device_id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	GranteeID  uuid.UUID  `json:"grantee_id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	Email      string     `json:"email" example:"contractor@example.com"`
+	Permission string     `json:"permission" example:"viewer"`
+	ExpiresAt  *time.Time `json:"expires_at" example:"2023-02-01T00:00:00Z"`
+	Expired    bool       `json:"expired" example:"false"`
+	CreatedAt  time.Time  `json:"created_at" example:"2023-01-01T12:00:00Z"`
+}
@@ -0,0 +1,26 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// @Description Transfer a device to the user registered under an email
+type DeviceTransferRequest struct {
+	Email     string     `json:"email" binding:"required,email" example:"buyer@example.com"`
+	ExpiresAt *time.Time `json:"expires_at" example:"2023-01-08T12:00:00Z"` // Optional; defaults to 7 days from now
+}
+
+// @Description Device ownership transfer
+type DeviceTransferResponse struct {
+	ID          uuid.UUID  `json:"id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	DeviceID    uuid.UUID  `json:"device_id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	FromUserID  uuid.UUID  `json:"from_user_id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	ToEmail     string     `json:"to_email" example:"buyer@example.com"`
+	ToUserID    *uuid.UUID `json:"to_user_id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	Status      string     `json:"status" example:"pending"` // pending, accepted, declined, cancelled or expired
+	ExpiresAt   time.Time  `json:"expires_at" example:"2023-01-08T12:00:00Z"`
+	CompletedAt *time.Time `json:"completed_at" example:"2023-01-02T12:00:00Z"`
+	CreatedAt   time.Time  `json:"created_at" example:"2023-01-01T12:00:00Z"`
+}
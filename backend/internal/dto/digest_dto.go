@@ -0,0 +1,63 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// @Description Query parameters for previewing a digest
+type DigestPreviewQuery struct {
+	Period string `form:"period" example:"daily"` // daily (last 24 hours, the default) or weekly (last 7 days)
+}
+
+// @Description Digest of the alerts and fleet health of a user over a period
+type DigestReport struct {
+	Period              string           `json:"period" example:"daily"`
+	From                time.Time        `json:"from" example:"2023-01-01T08:00:00Z"`
+	To                  time.Time        `json:"to" example:"2023-01-02T08:00:00Z"`
+	AlertsFired         int64            `json:"alerts_fired" example:"12"`
+	AlertsBySeverity    map[string]int64 `json:"alerts_by_severity"`
+	LatestAlerts        []DigestAlert    `json:"latest_alerts"`
+	DevicesOffline      []DigestDevice   `json:"devices_offline"` // The ones that went offline last, up to 10
+	DevicesOfflineCount int              `json:"devices_offline_count" example:"3"`
+	TopOffenders        []DigestOffender `json:"top_offenders"`
+	Reboots             []DigestReboot   `json:"reboots"`
+}
+
+// @Description Alert fired during the period of a digest
+type DigestAlert struct {
+	ID           uuid.UUID `json:"id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	Name         string    `json:"name" example:"High CPU Alert"`
+	Severity     string    `json:"severity" example:"warning"`
+	Status       string    `json:"status" example:"resolved"`
+	DeviceSN     string    `json:"device_sn" example:"SN123456"`
+	TriggerCount int       `json:"trigger_count" example:"4"`
+	FiredAt      time.Time `json:"fired_at" example:"2023-01-01T12:00:00Z"`
+}
+
+// @Description Device offline when the digest was compiled
+type DigestDevice struct {
+	DeviceID   uuid.UUID  `json:"device_id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	Name       string     `json:"name" example:"Gateway 01"`
+	SN         string     `json:"sn" example:"SN123456"`
+	LastSeenAt *time.Time `json:"last_seen_at" example:"2023-01-01T12:00:00Z"` // Null for devices that never reported
+}
+
+// @Description Device among the worst for a metric over the period: highest average cpu or ram, highest temperature or lowest disk_free
+type DigestOffender struct {
+	Metric   string    `json:"metric" example:"cpu"`
+	DeviceID uuid.UUID `json:"device_id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	Name     string    `json:"name" example:"Gateway 01"`
+	SN       string    `json:"sn" example:"SN123456"`
+	Value    float64   `json:"value" example:"91.5"`
+}
+
+// @Description Device that rebooted during the period
+type DigestReboot struct {
+	DeviceID     uuid.UUID `json:"device_id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	Name         string    `json:"name" example:"Gateway 01"`
+	SN           string    `json:"sn" example:"SN123456"`
+	Reboots      int64     `json:"reboots" example:"2"`
+	LastBootTime time.Time `json:"last_boot_time" example:"2023-01-01T12:00:00Z"`
+}
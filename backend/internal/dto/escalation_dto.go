@@ -0,0 +1,64 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// @Description Request to create or update an escalation policy
+type EscalationPolicyRequest struct {
+	Name        string           `json:"name" binding:"required" example:"Database on-call"`
+	Description string           `json:"description" example:"Page the on-call engineer, then the team lead"`
+	Steps       []EscalationStep `json:"steps" binding:"required"`
+}
+
+// @Description Step of an escalation policy. It notifies its users (over WebSocket) and channels delay_minutes after the previous step, or after the alert fired for the first step, and then repeat more times, delay_minutes apart.
+type EscalationStep struct {
+	DelayMinutes int         `json:"delay_minutes" example:"15"`
+	UserIDs      []uuid.UUID `json:"user_ids" example:"550e8400-e29b-41d4-a716-446655440000"` // Members of the policy's organization; only the owner for personal policies
+	ChannelIDs   []uuid.UUID `json:"channel_ids" example:"550e8400-e29b-41d4-a716-446655440000"`
+	Repeat       int         `json:"repeat" example:"2"` // Extra notifications before moving on to the next step
+}
+
+// @Description Escalation policy
+type EscalationPolicyResponse struct {
+	ID             uuid.UUID        `json:"id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	OrganizationID *uuid.UUID       `json:"organization_id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	Name           string           `json:"name" example:"Database on-call"`
+	Description    string           `json:"description" example:"Page the on-call engineer, then the team lead"`
+	Steps          []EscalationStep `json:"steps"`
+	CreatedAt      time.Time        `json:"created_at" example:"2023-01-01T12:00:00Z"`
+	UpdatedAt      time.Time        `json:"updated_at" example:"2023-01-01T12:00:00Z"`
+}
+
+// @Description Query parameters for listing alerts
+type AlertListQuery struct {
+	Status         string `form:"status" example:"firing"`        // firing, acknowledged or resolved
+	MinSeverity    string `form:"min_severity" example:"warning"` // info, warning or critical
+	NotificationID string `form:"notification_id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	DeviceID       string `form:"device_id" example:"550e8400-e29b-41d4-a716-446655440000"`
+}
+
+// @Description Episode of a notification rule firing for a device, from the first heartbeat that triggered it until the first one that did not
+type AlertResponse struct {
+	ID                 uuid.UUID         `json:"id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	NotificationID     uuid.UUID         `json:"notification_id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	OrganizationID     *uuid.UUID        `json:"organization_id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	DeviceID           uuid.UUID         `json:"device_id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	DeviceSN           string            `json:"device_sn" example:"123456789012"`
+	Name               string            `json:"name" example:"High CPU Alert"`
+	Status             string            `json:"status" example:"firing"` // firing, acknowledged or resolved
+	Severity           string            `json:"severity" example:"critical"`
+	TriggeredValue     float64           `json:"triggered_value" example:"85.5"` // Value of the last triggering heartbeat
+	TriggerCount       int               `json:"trigger_count" example:"3"`      // Heartbeats that triggered the rule
+	Conditions         []ConditionResult `json:"conditions"`                     // Result of each condition for the last triggering heartbeat
+	FiredAt            time.Time         `json:"fired_at" example:"2023-01-01T12:00:00Z"`
+	LastTriggeredAt    time.Time         `json:"last_triggered_at" example:"2023-01-01T12:02:00Z"`
+	AcknowledgedAt     *time.Time        `json:"acknowledged_at" example:"2023-01-01T12:05:00Z"`
+	AcknowledgedBy     *uuid.UUID        `json:"acknowledged_by" example:"550e8400-e29b-41d4-a716-446655440000"`
+	ResolvedAt         *time.Time        `json:"resolved_at" example:"2023-01-01T12:10:00Z"`
+	EscalationPolicyID *uuid.UUID        `json:"escalation_policy_id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	EscalationStep     int               `json:"escalation_step" example:"1"`                       // Index of the step notified next, from 0
+	NextEscalationAt   *time.Time        `json:"next_escalation_at" example:"2023-01-01T12:15:00Z"` // Empty once acknowledged, resolved or out of steps
+}
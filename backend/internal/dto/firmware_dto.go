@@ -0,0 +1,92 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// @Description Metadata sent along with a firmware file upload
+type FirmwareUploadRequest struct {
+	Version  string `form:"version" binding:"required" example:"1.4.0"`
+	Notes    string `form:"notes" example:"Fixes watchdog resets"`
+	Checksum string `form:"checksum" example:"9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08"` // Optional SHA-256 the uploaded file must match
+}
+
+// @Description Firmware artifact in the registry
+type FirmwareArtifactResponse struct {
+	ID             uuid.UUID  `json:"id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	Version        string     `json:"version" example:"1.4.0"`
+	FileName       string     `json:"file_name" example:"gateway-1.4.0.bin"`
+	Size           int64      `json:"size" example:"1048576"`
+	Checksum       string     `json:"checksum" example:"9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08"` // SHA-256 of the file
+	Notes          string     `json:"notes" example:"Fixes watchdog resets"`
+	OrganizationID *uuid.UUID `json:"organization_id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	CreatedAt      time.Time  `json:"created_at" example:"2023-01-01T12:00:00Z"`
+}
+
+// @Description Number of devices running a firmware version
+type FirmwareVersionCount struct {
+	Version string `json:"version" example:"1.4.0"` // Empty for devices that never reported a version
+	Devices int64  `json:"devices" example:"42"`
+}
+
+// @Description Firmware versions running across the devices of the user
+type FirmwareInventoryResponse struct {
+	Total    int64                  `json:"total" example:"50"`
+	Versions []FirmwareVersionCount `json:"versions"`
+}
+
+// @Description Staged rollout of a firmware artifact
+type FirmwareRolloutRequest struct {
+	ArtifactID         uuid.UUID  `json:"artifact_id" binding:"required" example:"550e8400-e29b-41d4-a716-446655440000"`
+	GroupID            *uuid.UUID `json:"group_id" example:"550e8400-e29b-41d4-a716-446655440000"` // Target the group and its subgroups
+	LabelSelector      string     `json:"label_selector" example:"env=prod,site=sp"`               // Target matching devices; narrows group_id when both are set
+	Waves              []int      `json:"waves" example:"10,50,100"`                               // Cumulative percentage of the devices per wave, 10,50,100 by default
+	WaveTimeoutSeconds int        `json:"wave_timeout_seconds" example:"1800"`                     // Time a device has to report the new version, 1800 by default
+	MaxFailurePercent  *int       `json:"max_failure_percent" example:"10"`                        // Failed devices a wave tolerates before the rollout pauses, 10 by default
+}
+
+// @Description Progress of a firmware rollout
+type FirmwareRolloutProgress struct {
+	Total     int `json:"total" example:"50"`
+	Pending   int `json:"pending" example:"40"`
+	Updating  int `json:"updating" example:"4"`
+	Succeeded int `json:"succeeded" example:"5"`
+	Failed    int `json:"failed" example:"1"`
+	Skipped   int `json:"skipped" example:"0"`
+}
+
+// @Description Device targeted by a firmware rollout
+type FirmwareRolloutDeviceResponse struct {
+	DeviceID        uuid.UUID  `json:"device_id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	Wave            int        `json:"wave" example:"1"`
+	Status          string     `json:"status" example:"succeeded"` // pending, updating, succeeded, failed or skipped
+	PreviousVersion string     `json:"previous_version" example:"1.3.2"`
+	CommandID       *uuid.UUID `json:"command_id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	Error           string     `json:"error,omitempty" example:"device did not report version 1.4.0 in time"`
+	StartedAt       *time.Time `json:"started_at" example:"2023-01-01T12:00:00Z"`
+	CompletedAt     *time.Time `json:"completed_at" example:"2023-01-01T12:03:00Z"`
+}
+
+// @Description Firmware rollout
+type FirmwareRolloutResponse struct {
+	ID                 uuid.UUID                       `json:"id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	ArtifactID         uuid.UUID                       `json:"artifact_id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	Version            string                          `json:"version" example:"1.4.0"`
+	OrganizationID     *uuid.UUID                      `json:"organization_id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	GroupID            *uuid.UUID                      `json:"group_id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	LabelSelector      string                          `json:"label_selector" example:"env=prod,site=sp"`
+	Waves              []int                           `json:"waves" example:"10,50,100"`
+	CurrentWave        int                             `json:"current_wave" example:"1"`
+	WaveTimeoutSeconds int                             `json:"wave_timeout_seconds" example:"1800"`
+	MaxFailurePercent  int                             `json:"max_failure_percent" example:"10"`
+	Status             string                          `json:"status" example:"running"` // running, paused, completed or cancelled
+	PauseReason        string                          `json:"pause_reason,omitempty" example:"2 of 5 devices in wave 1 failed to update"`
+	Progress           *FirmwareRolloutProgress        `json:"progress,omitempty"`
+	Devices            []FirmwareRolloutDeviceResponse `json:"devices,omitempty"` // Only returned for a single rollout
+	WaveStartedAt      *time.Time                      `json:"wave_started_at" example:"2023-01-01T12:00:00Z"`
+	ResumedAt          *time.Time                      `json:"resumed_at" example:"2023-01-01T12:30:00Z"` // Updated devices are watched again from this point
+	CompletedAt        *time.Time                      `json:"completed_at" example:"2023-01-01T14:00:00Z"`
+	CreatedAt          time.Time                       `json:"created_at" example:"2023-01-01T12:00:00Z"`
+}
@@ -5,6 +5,7 @@ import "time"
 // @Description Heartbeat message containing device telemetry data
 type HeartbeatMessage struct {
     DeviceID     string    `json:"device_id" example:"f8aac3d7-b2ac-43e8-94d5-bbee59dd4ac9"`
+    Token        string    `json:"token,omitempty" example:"dt_4f9c2a..."` // Device token returned by provisioning
     CPU          float64   `json:"cpu" example:"45.67"`                    // CPU usage in %
     RAM          float64   `json:"ram" example:"67.89"`                    // RAM usage in %
     DiskFree     float64   `json:"disk_free" example:"23.45"`              // Free disk space in %
@@ -12,6 +13,8 @@ type HeartbeatMessage struct {
     Latency      int       `json:"latency" example:"150"`                  // Latency to DNS 8.8.8.8 in milliseconds
     Connectivity int       `json:"connectivity" example:"1"`               // 0 (no connection) or 1 (has connection)
     BootTime     time.Time `json:"boot_time" example:"2023-01-01T00:00:00Z"` // Boot timestamp with UTC+00
+    Reported     map[string]interface{} `json:"reported,omitempty" swaggertype:"object"` // Optional configuration the device is running with, merged into its shadow
+    FirmwareVersion string `json:"firmware_version,omitempty" example:"1.4.0"` // Firmware the device is running
 }
 
 // @Description Heartbeat response with telemetry data and metadata
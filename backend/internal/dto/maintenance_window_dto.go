@@ -0,0 +1,51 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// @Description Request to create a maintenance window. Without schedule the window runs once from starts_at to ends_at; with a cron schedule it runs for duration_minutes from every occurrence between starts_at and the optional ends_at.
+type MaintenanceWindowRequest struct {
+	Name            string      `json:"name" binding:"required" example:"Rack 3 power work"`
+	Description     string      `json:"description" example:"UPS replacement"`
+	StartsAt        time.Time   `json:"starts_at" binding:"required" example:"2025-10-01T22:00:00Z"`
+	EndsAt          *time.Time  `json:"ends_at" example:"2025-10-02T02:00:00Z"`
+	Schedule        string      `json:"schedule" example:"0 2 * * 0"`         // 5 field cron expression (minute hour day-of-month month day-of-week) or @daily, @weekly...; RRULEs are not supported
+	DurationMinutes int         `json:"duration_minutes" example:"120"`       // Length of each occurrence of a recurring window
+	Timezone        string      `json:"timezone" example:"America/Sao_Paulo"` // Time zone of the schedule, UTC by default
+	DeviceIDs       []uuid.UUID `json:"device_ids" example:"550e8400-e29b-41d4-a716-446655440000"`
+	GroupIDs        []uuid.UUID `json:"group_ids" example:"550e8400-e29b-41d4-a716-446655440000"` // Subgroups are included
+	Locations       []string    `json:"locations" example:"Data Center SP"`
+}
+
+// @Description Maintenance window with its current or next occurrence
+type MaintenanceWindowResponse struct {
+	ID              uuid.UUID   `json:"id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	OrganizationID  *uuid.UUID  `json:"organization_id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	Name            string      `json:"name" example:"Rack 3 power work"`
+	Description     string      `json:"description" example:"UPS replacement"`
+	StartsAt        time.Time   `json:"starts_at" example:"2025-10-01T22:00:00Z"`
+	EndsAt          *time.Time  `json:"ends_at" example:"2025-10-02T02:00:00Z"`
+	Schedule        string      `json:"schedule" example:"0 2 * * 0"`
+	DurationMinutes int         `json:"duration_minutes" example:"120"`
+	Timezone        string      `json:"timezone" example:"America/Sao_Paulo"`
+	DeviceIDs       []uuid.UUID `json:"device_ids"`
+	GroupIDs        []uuid.UUID `json:"group_ids"`
+	Locations       []string    `json:"locations"`
+	Active          bool        `json:"active" example:"false"`
+	NextStartAt     *time.Time  `json:"next_start_at" example:"2025-10-05T05:00:00Z"` // Start of the current occurrence when active, of the next one otherwise
+	NextEndAt       *time.Time  `json:"next_end_at" example:"2025-10-05T07:00:00Z"`
+	CreatedAt       time.Time   `json:"created_at" example:"2023-01-01T12:00:00Z"`
+}
+
+// @Description Alert that was not sent because a maintenance window was active
+type SuppressedAlertResponse struct {
+	ID             uuid.UUID `json:"id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	NotificationID uuid.UUID `json:"notification_id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	DeviceID       uuid.UUID `json:"device_id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	Name           string    `json:"name" example:"High CPU Alert"`
+	TriggeredValue float64   `json:"triggered_value" example:"92.5"`
+	CreatedAt      time.Time `json:"created_at" example:"2023-01-01T12:00:00Z"`
+}
@@ -0,0 +1,30 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// @Description Request to mute a notification rule, a device, or a rule on one device. Set notification_id, device_id or both, and either duration_minutes or expires_at.
+type MuteRequest struct {
+	NotificationID  *uuid.UUID `json:"notification_id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	DeviceID        *uuid.UUID `json:"device_id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	DurationMinutes int        `json:"duration_minutes" example:"120"` // Mute for this long from now
+	ExpiresAt       *time.Time `json:"expires_at" example:"2025-10-01T22:00:00Z"`
+	Reason          string     `json:"reason" example:"Replacing the fan"`
+}
+
+// @Description Mute of a notification rule, a device, or a rule on one device
+type MuteResponse struct {
+	ID             uuid.UUID  `json:"id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	OrganizationID *uuid.UUID `json:"organization_id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	NotificationID *uuid.UUID `json:"notification_id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	DeviceID       *uuid.UUID `json:"device_id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	Reason         string     `json:"reason" example:"Replacing the fan"`
+	ExpiresAt      time.Time  `json:"expires_at" example:"2025-10-01T22:00:00Z"`
+	CancelledAt    *time.Time `json:"cancelled_at" example:"2025-10-01T21:00:00Z"`
+	Active         bool       `json:"active" example:"true"`
+	CreatedBy      uuid.UUID  `json:"created_by" example:"550e8400-e29b-41d4-a716-446655440000"`
+	CreatedAt      time.Time  `json:"created_at" example:"2023-01-01T12:00:00Z"`
+}
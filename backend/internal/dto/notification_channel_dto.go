@@ -0,0 +1,93 @@
+package dto
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// @Description Request to create or update a notification channel. The config depends on the type: webhook {"url", "headers"}, slack and teams {"url"} (incoming webhook URL), email {"to": [addresses]}.
+type NotificationChannelRequest struct {
+	Name        string          `json:"name" binding:"required" example:"On-call webhook"`
+	Type        string          `json:"type" binding:"required" example:"webhook"` // webhook, email, slack or teams
+	Config      json.RawMessage `json:"config" binding:"required" swaggertype:"object"`
+	Enabled     *bool           `json:"enabled" example:"true"`          // Defaults to true
+	MinSeverity string          `json:"min_severity" example:"critical"` // Only alerts of at least this severity (info, warning or critical) are delivered; all when empty
+}
+
+// @Description Notification channel
+type NotificationChannelResponse struct {
+	ID                  uuid.UUID       `json:"id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	OrganizationID      *uuid.UUID      `json:"organization_id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	Name                string          `json:"name" example:"On-call webhook"`
+	Type                string          `json:"type" example:"webhook"`
+	Config              json.RawMessage `json:"config" swaggertype:"object"`
+	Enabled             bool            `json:"enabled" example:"true"`
+	MinSeverity         string          `json:"min_severity" example:"critical"`
+	Secret              string          `json:"secret,omitempty" example:"whsec_3f2a..."` // Signing secret of a webhook, only returned when it is created
+	ConsecutiveFailures int             `json:"consecutive_failures" example:"0"`
+	DisabledAt          *time.Time      `json:"disabled_at" example:"2023-01-01T12:00:00Z"` // Set when the channel was disabled after repeated failures
+	DisabledReason      string          `json:"disabled_reason" example:""`
+	CreatedAt           time.Time       `json:"created_at" example:"2023-01-01T12:00:00Z"`
+	UpdatedAt           time.Time       `json:"updated_at" example:"2023-01-01T12:00:00Z"`
+}
+
+// @Description Alert queued for a channel, with every attempt made to send it
+type NotificationDeliveryResponse struct {
+	ID             uuid.UUID                 `json:"id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	ChannelID      uuid.UUID                 `json:"channel_id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	NotificationID *uuid.UUID                `json:"notification_id" example:"550e8400-e29b-41d4-a716-446655440000"` // Empty for test messages
+	DeviceID       *uuid.UUID                `json:"device_id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	Status         string                    `json:"status" example:"delivered"` // pending, delivered or failed
+	Attempts       []DeliveryAttemptResponse `json:"attempts"`
+	NextAttemptAt  *time.Time                `json:"next_attempt_at" example:"2023-01-01T12:01:00Z"` // Set while pending
+	LastError      string                    `json:"last_error" example:"unexpected status 502"`
+	DeliveredAt    *time.Time                `json:"delivered_at" example:"2023-01-01T12:00:01Z"`
+	CreatedAt      time.Time                 `json:"created_at" example:"2023-01-01T12:00:00Z"`
+}
+
+// @Description Attempt to send a delivery
+type DeliveryAttemptResponse struct {
+	Attempt    int       `json:"attempt" example:"1"`
+	Success    bool      `json:"success" example:"false"`
+	Error      string    `json:"error" example:"unexpected status 502"`
+	DurationMs int64     `json:"duration_ms" example:"120"`
+	CreatedAt  time.Time `json:"created_at" example:"2023-01-01T12:00:00Z"`
+}
+
+// ChannelMessage is what a channel sender delivers: the alert, the ID of the
+// delivery, which stays the same across retries, and the secrets webhook
+// payloads are signed with.
+type ChannelMessage struct {
+	DeliveryID uuid.UUID
+	Secrets    []string
+	Alert      NotificationAlert
+}
+
+// @Description Request to create or update a webhook endpoint
+type WebhookRequest struct {
+	Name        string            `json:"name" binding:"required" example:"Incident manager"`
+	URL         string            `json:"url" binding:"required" example:"https://hooks.example.com/alerts"`
+	Headers     map[string]string `json:"headers"`                        // Added to every request
+	Enabled     *bool             `json:"enabled" example:"true"`         // Defaults to true; enabling resets the failure count
+	MinSeverity string            `json:"min_severity" example:"warning"` // Only alerts of at least this severity (info, warning or critical) are delivered; all when empty
+}
+
+// @Description Webhook endpoint
+type WebhookResponse struct {
+	ID                      uuid.UUID         `json:"id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	OrganizationID          *uuid.UUID        `json:"organization_id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	Name                    string            `json:"name" example:"Incident manager"`
+	URL                     string            `json:"url" example:"https://hooks.example.com/alerts"`
+	Headers                 map[string]string `json:"headers"`
+	Enabled                 bool              `json:"enabled" example:"true"`
+	MinSeverity             string            `json:"min_severity" example:"warning"`
+	Secret                  string            `json:"secret,omitempty" example:"whsec_3f2a..."`                  // Only returned when the webhook is created and when its secret is rotated
+	PreviousSecretExpiresAt *time.Time        `json:"previous_secret_expires_at" example:"2023-01-02T12:00:00Z"` // Until then payloads are also signed with the previous secret
+	ConsecutiveFailures     int               `json:"consecutive_failures" example:"0"`
+	DisabledAt              *time.Time        `json:"disabled_at" example:"2023-01-01T12:00:00Z"` // Set when the endpoint was disabled after repeated failures
+	DisabledReason          string            `json:"disabled_reason" example:""`
+	CreatedAt               time.Time         `json:"created_at" example:"2023-01-01T12:00:00Z"`
+	UpdatedAt               time.Time         `json:"updated_at" example:"2023-01-01T12:00:00Z"`
+}
@@ -8,11 +8,17 @@ import (
 
 // @Description Request to create a notification rule
 type CreateNotificationRequest struct {
-	Name        string                 `json:"name" binding:"required" example:"High CPU Alert"`
-	Description string                 `json:"description" example:"Alert when CPU usage is high"`
-	Enabled     bool                   `json:"enabled" example:"true"`
-	Conditions  []NotificationCondition `json:"conditions" binding:"required"`
-	DeviceIDs   []uuid.UUID            `json:"device_ids" example:"550e8400-e29b-41d4-a716-446655440000"`
+	Name               string                  `json:"name" binding:"required" example:"High CPU Alert"`
+	Description        string                  `json:"description" example:"Alert when CPU usage is high"`
+	Enabled            bool                    `json:"enabled" example:"true"`
+	Severity           string                  `json:"severity" example:"critical"` // info, warning or critical; defaults to warning
+	Conditions         []NotificationCondition `json:"conditions" binding:"required"`
+	DeviceIDs          []uuid.UUID             `json:"device_ids" example:"550e8400-e29b-41d4-a716-446655440000"`
+	LabelSelector      string                  `json:"label_selector" example:"env=prod,site=sp"`
+	ChannelIDs         []uuid.UUID             `json:"channel_ids" example:"550e8400-e29b-41d4-a716-446655440000"`          // Channels the alerts are also delivered to
+	EscalationPolicyID *uuid.UUID              `json:"escalation_policy_id" example:"550e8400-e29b-41d4-a716-446655440000"` // Policy escalating the alerts nobody acknowledges
+	TitleTemplate      string                  `json:"title_template" example:"{{.Rule.Name}} on {{.Device.Name}}"`         // Go text/template for the message title
+	BodyTemplate       string                  `json:"body_template" example:"CPU at {{.Heartbeat.CPU | round 1}}%"`        // Go text/template for the message body
 }
 
 // @Description Notification condition
@@ -22,15 +28,149 @@ type NotificationCondition struct {
 	Value     interface{} `json:"value" example:"70.0"`
 }
 
+// @Description Result of evaluating one condition of a rule against a heartbeat
+type ConditionResult struct {
+	Parameter string  `json:"parameter" example:"cpu"`
+	Operator  string  `json:"operator" example:">"`
+	Threshold float64 `json:"threshold" example:"70"`
+	Actual    float64 `json:"actual" example:"92.5"`
+	Matched   bool    `json:"matched" example:"true"`
+}
+
+// @Description Request to preview the title and body templates of a rule. Without device_id a sample device is used, without heartbeat a sample heartbeat.
+type TemplatePreviewRequest struct {
+	Name          string                  `json:"name" example:"High CPU Alert"`
+	Description   string                  `json:"description" example:"Alert when CPU usage is high"`
+	Severity      string                  `json:"severity" example:"critical"`
+	Conditions    []NotificationCondition `json:"conditions"`
+	TitleTemplate string                  `json:"title_template" example:"{{.Rule.Name}} on {{.Device.Name}}"`
+	BodyTemplate  string                  `json:"body_template" example:"CPU at {{.Heartbeat.CPU | round 1}}%"`
+	DeviceID      *uuid.UUID              `json:"device_id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	Heartbeat     *AlertHeartbeatData     `json:"heartbeat"`
+}
+
+// @Description Templates rendered for a preview, with the condition results they were rendered with
+type TemplatePreviewResponse struct {
+	Title      string            `json:"title" example:"High CPU Alert on Gateway"`
+	Body       string            `json:"body" example:"CPU at 92.5%"`
+	Matched    bool              `json:"matched" example:"true"` // Whether the heartbeat triggers the rule
+	Conditions []ConditionResult `json:"conditions"`
+}
+
+// @Description Request to test a notification rule against stored heartbeats. Set either notification_id or rule. Without device_ids every device of the rule's owner is tested; to defaults to now.
+type RuleTestRequest struct {
+	NotificationID *uuid.UUID                 `json:"notification_id" example:"550e8400-e29b-41d4-a716-446655440000"` // Stored rule to test
+	Rule           *CreateNotificationRequest `json:"rule"`                                                           // Rule definition to test
+	DeviceIDs      []uuid.UUID                `json:"device_ids" example:"550e8400-e29b-41d4-a716-446655440000"`
+	From           time.Time                  `json:"from" binding:"required" example:"2025-09-01T00:00:00Z"`
+	To             *time.Time                 `json:"to" example:"2025-09-08T00:00:00Z"`
+}
+
+// @Description How often a rule would have fired over a period
+type RuleTestResponse struct {
+	From              time.Time        `json:"from" example:"2025-09-01T00:00:00Z"`
+	To                time.Time        `json:"to" example:"2025-09-08T00:00:00Z"`
+	Heartbeats        int              `json:"heartbeats" example:"2016"`          // Heartbeats evaluated
+	Matched           int              `json:"matched" example:"42"`               // Heartbeats that triggered the rule
+	Notifications     int              `json:"notifications" example:"40"`         // WebSocket messages that would have been sent; channels only get the alerts
+	Suppressed        int              `json:"suppressed" example:"2"`             // Triggers held by maintenance windows or mutes
+	Alerts            int              `json:"alerts" example:"3"`                 // Alerts that would have been opened
+	Truncated         bool             `json:"truncated" example:"false"`          // Stopped at the heartbeat limit
+	TimelineTruncated bool             `json:"timeline_truncated" example:"false"` // Timeline stopped at its limit; counts are complete
+	Devices           []RuleTestDevice `json:"devices"`
+	Timeline          []RuleTestAlert  `json:"timeline"`
+}
+
+// @Description Result of a rule test for one device. Devices the rule does not target are not evaluated.
+type RuleTestDevice struct {
+	DeviceID      uuid.UUID `json:"device_id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	DeviceSN      string    `json:"device_sn" example:"123456789012"`
+	Targeted      bool      `json:"targeted" example:"true"`
+	Heartbeats    int       `json:"heartbeats" example:"672"`
+	Matched       int       `json:"matched" example:"14"`
+	Notifications int       `json:"notifications" example:"14"`
+	Suppressed    int       `json:"suppressed" example:"0"`
+	Alerts        int       `json:"alerts" example:"1"`
+}
+
+// @Description Alert a rule would have opened during a test, from its first trigger until the heartbeat that resolved it
+type RuleTestAlert struct {
+	DeviceID        uuid.UUID         `json:"device_id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	DeviceSN        string            `json:"device_sn" example:"123456789012"`
+	FiredAt         time.Time         `json:"fired_at" example:"2025-09-02T14:00:00Z"`
+	LastTriggeredAt time.Time         `json:"last_triggered_at" example:"2025-09-02T14:13:00Z"`
+	ResolvedAt      *time.Time        `json:"resolved_at" example:"2025-09-02T14:14:00Z"` // Empty when still open at the end of the period
+	TriggerCount    int               `json:"trigger_count" example:"14"`
+	Conditions      []ConditionResult `json:"conditions"` // Condition results of the first trigger
+}
+
 // @Description Response for notification rule
 type NotificationResponse struct {
-	ID          uuid.UUID               `json:"id" example:"550e8400-e29b-41d4-a716-446655440000"`
-	UserID      uuid.UUID               `json:"user_id" example:"550e8400-e29b-41d4-a716-446655440000"`
-	Name        string                  `json:"name" example:"High CPU Alert"`
-	Description string                  `json:"description" example:"Alert when CPU usage is high"`
-	Enabled     bool                    `json:"enabled" example:"true"`
-	Conditions  []NotificationCondition `json:"conditions"`
-	DeviceIDs   []uuid.UUID             `json:"device_ids"`
-	CreatedAt   time.Time               `json:"created_at" example:"2023-01-01T12:00:00Z"`
-	UpdatedAt   time.Time               `json:"updated_at" example:"2023-01-01T12:00:00Z"`
-}
\ No newline at end of file
+	ID                 uuid.UUID               `json:"id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	UserID             uuid.UUID               `json:"user_id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	OrganizationID     *uuid.UUID              `json:"organization_id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	Name               string                  `json:"name" example:"High CPU Alert"`
+	Description        string                  `json:"description" example:"Alert when CPU usage is high"`
+	Enabled            bool                    `json:"enabled" example:"true"`
+	Severity           string                  `json:"severity" example:"critical"`
+	Conditions         []NotificationCondition `json:"conditions"`
+	DeviceIDs          []uuid.UUID             `json:"device_ids"`
+	LabelSelector      string                  `json:"label_selector" example:"env=prod,site=sp"`
+	ChannelIDs         []uuid.UUID             `json:"channel_ids"`
+	EscalationPolicyID *uuid.UUID              `json:"escalation_policy_id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	TitleTemplate      string                  `json:"title_template" example:"{{.Rule.Name}} on {{.Device.Name}}"`
+	BodyTemplate       string                  `json:"body_template" example:"CPU at {{.Heartbeat.CPU | round 1}}%"`
+	CreatedAt          time.Time               `json:"created_at" example:"2023-01-01T12:00:00Z"`
+	UpdatedAt          time.Time               `json:"updated_at" example:"2023-01-01T12:00:00Z"`
+}
+
+// NotificationAlert is the message sent when a rule triggers, both to the
+// WebSocket clients of its owner and to the rule's channels. ID is the rule
+// and AlertID the alert it opened or retriggered. Conditions holds the result
+// of each condition of the rule for the heartbeat. Escalation notifications
+// carry the 1-based EscalationStep that sent them. Digests of the messages
+// held during quiet hours list them in Digest. Rendered, when set, replaces
+// the default text channels build from the alert; an empty Subject or Text
+// keeps the default. Alerts of a muted rule or device only reach the
+// WebSocket, with Suppressed set and the mute that silenced them. Triggers of
+// an alert that is already open only reach the WebSocket too, with
+// Retriggered set.
+type NotificationAlert struct {
+	ID             uuid.UUID           `json:"id"`
+	AlertID        uuid.UUID           `json:"alert_id"`
+	UserID         uuid.UUID           `json:"user_id"`
+	Name           string              `json:"name"`
+	Description    string              `json:"description"`
+	Severity       string              `json:"severity"`
+	DeviceID       uuid.UUID           `json:"device_id"`
+	DeviceSN       string              `json:"device_sn"`
+	TriggeredValue float64             `json:"triggered_value"`
+	Timestamp      string              `json:"timestamp"`
+	HeartbeatData  AlertHeartbeatData  `json:"heartbeat_data"`
+	Conditions     []ConditionResult   `json:"conditions,omitempty"`
+	EscalationStep int                 `json:"escalation_step,omitempty"`
+	Digest         []NotificationAlert `json:"digest,omitempty"`
+	Rendered       *RenderedMessage    `json:"rendered,omitempty"`
+	Suppressed     bool                `json:"suppressed,omitempty"`
+	Retriggered    bool                `json:"retriggered,omitempty"`
+	MuteID         *uuid.UUID          `json:"mute_id,omitempty"`
+	MutedUntil     *time.Time          `json:"muted_until,omitempty"`
+}
+
+// RenderedMessage is the text of a message as channels show it: Subject as
+// email subject or chat title, Text as plain text body and HTML, when set,
+// as the HTML alternative of emails.
+type RenderedMessage struct {
+	Subject string `json:"subject"`
+	Text    string `json:"text"`
+	HTML    string `json:"html,omitempty"`
+}
+
+type AlertHeartbeatData struct {
+	CPU          float64 `json:"cpu"`
+	RAM          float64 `json:"ram"`
+	DiskFree     float64 `json:"disk_free"`
+	Temperature  float64 `json:"temperature"`
+	Latency      int     `json:"latency"`
+	Connectivity int     `json:"connectivity"`
+}
@@ -0,0 +1,43 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// @Description Notification preferences of the current user. Fields left out keep their defaults.
+type NotificationPreferenceRequest struct {
+	Timezone                   string               `json:"timezone" example:"America/Sao_Paulo"`
+	QuietHours                 []QuietHours         `json:"quiet_hours"`
+	QuietHoursAction           string               `json:"quiet_hours_action" example:"defer"` // defer, digest or drop
+	CriticalBypassesQuietHours *bool                `json:"critical_bypasses_quiet_hours" example:"true"`
+	PreferredChannels          map[string]uuid.UUID `json:"preferred_channels"` // Personal channel per severity (info, warning or critical)
+	DailyDigest                bool                 `json:"daily_digest" example:"true"`
+	WeeklyDigest               bool                 `json:"weekly_digest" example:"false"`
+	DigestHour                 *int                 `json:"digest_hour" example:"8"`                                          // Local hour the digests are sent at
+	DigestWeekday              *int                 `json:"digest_weekday" example:"1"`                                       // Day the weekly digest is sent on, 0 is Sunday
+	DigestChannelID            *uuid.UUID           `json:"digest_channel_id" example:"550e8400-e29b-41d4-a716-446655440000"` // Personal email channel the digests are sent to
+}
+
+// @Description Quiet period starting on weekday (0 is Sunday) at start and ending at end, in the user's timezone. An end not after start ends on the following day.
+type QuietHours struct {
+	Weekday int    `json:"weekday" example:"1"`
+	Start   string `json:"start" example:"22:00"`
+	End     string `json:"end" example:"07:00"`
+}
+
+// @Description Notification preferences
+type NotificationPreferenceResponse struct {
+	Timezone                   string               `json:"timezone" example:"America/Sao_Paulo"`
+	QuietHours                 []QuietHours         `json:"quiet_hours"`
+	QuietHoursAction           string               `json:"quiet_hours_action" example:"defer"`
+	CriticalBypassesQuietHours bool                 `json:"critical_bypasses_quiet_hours" example:"true"`
+	PreferredChannels          map[string]uuid.UUID `json:"preferred_channels"`
+	DailyDigest                bool                 `json:"daily_digest" example:"true"`
+	WeeklyDigest               bool                 `json:"weekly_digest" example:"false"`
+	DigestHour                 int                  `json:"digest_hour" example:"8"`
+	DigestWeekday              int                  `json:"digest_weekday" example:"1"`
+	DigestChannelID            *uuid.UUID           `json:"digest_channel_id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	UpdatedAt                  *time.Time           `json:"updated_at" example:"2023-01-01T12:00:00Z"`
+}
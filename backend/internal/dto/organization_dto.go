@@ -0,0 +1,45 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// @Description Create organization request
+type OrganizationRequest struct {
+	Name string `json:"name" binding:"required" example:"Acme Monitoring"`
+}
+
+// @Description Organization together with the role of the authenticated user
+type OrganizationResponse struct {
+	ID        uuid.UUID `json:"id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	Name      string    `json:"name" example:"Acme Monitoring"`
+	Role      string    `json:"role" example:"owner"`
+	CreatedAt time.Time `json:"created_at" example:"2023-01-01T12:00:00Z"`
+	UpdatedAt time.Time `json:"updated_at" example:"2023-01-01T12:00:00Z"`
+}
+
+// @Description Member of an organization
+type OrganizationMemberResponse struct {
+	UserID    uuid.UUID `json:"user_id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	Email     string    `json:"email" example:"user@example.com"`
+	Role      string    `json:"role" example:"operator"`
+	CreatedAt time.Time `json:"created_at" example:"2023-01-01T12:00:00Z"`
+}
+
+// @Description Add an existing user to an organization
+type AddMemberRequest struct {
+	Email string `json:"email" binding:"required" example:"user@example.com"`
+	Role  string `json:"role" binding:"required" example:"operator"` // owner, admin, operator or viewer
+}
+
+// @Description Change the role of a member
+type UpdateMemberRequest struct {
+	Role string `json:"role" binding:"required" example:"viewer"` // owner, admin, operator or viewer
+}
+
+// @Description Personal devices to move into an organization
+type MoveDevicesRequest struct {
+	DeviceIDs []uuid.UUID `json:"device_ids" binding:"required" example:"550e8400-e29b-41d4-a716-446655440000"`
+}
@@ -0,0 +1,32 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// @Description Availability figures for a single device or an aggregated location
+type AvailabilityReportItem struct {
+	DeviceID                      *uuid.UUID `json:"device_id,omitempty" example:"550e8400-e29b-41d4-a716-446655440000"`
+	DeviceName                    string     `json:"device_name,omitempty" example:"Gateway 01"`
+	DeviceSN                      string     `json:"device_sn,omitempty" example:"123456789012"`
+	Location                      string     `json:"location" example:"Sao Paulo"`
+	DeviceCount                   int        `json:"device_count" example:"1"`
+	PeriodSeconds                 float64    `json:"period_seconds" example:"2592000"`
+	ExpectedHeartbeats            int64      `json:"expected_heartbeats" example:"43200"`
+	ReceivedHeartbeats            int64      `json:"received_heartbeats" example:"43010"`
+	HeartbeatPercentage           float64    `json:"heartbeat_percentage" example:"99.56"`
+	ConnectivityPercentage        float64    `json:"connectivity_percentage" example:"98.12"`
+	OutageCount                   int        `json:"outage_count" example:"3"`
+	MeanTimeBetweenOutagesSeconds *float64   `json:"mean_time_between_outages_seconds,omitempty" example:"847680"`
+	LongestOutageSeconds          float64    `json:"longest_outage_seconds" example:"3600"`
+}
+
+// @Description Availability report over a time range
+type AvailabilityReportResponse struct {
+	From    time.Time                `json:"from" example:"2023-01-01T00:00:00Z"`
+	To      time.Time                `json:"to" example:"2023-02-01T00:00:00Z"`
+	GroupBy string                   `json:"group_by" example:"location"`
+	Items   []AvailabilityReportItem `json:"items"`
+}
@@ -0,0 +1,221 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/dto"
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/services"
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/utils/errors"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type AlertHandler struct {
+	alertService services.AlertService
+}
+
+func NewAlertHandler(alertService services.AlertService) *AlertHandler {
+	return &AlertHandler{alertService: alertService}
+}
+
+// ListAlerts godoc
+// @Summary List alerts
+// @Description List the latest alerts (up to 200) of the rules the user can see, newest first. An alert opens when a rule first triggers for a device, stays open while later heartbeats keep triggering it and is resolved by the first heartbeat that does not.
+// @Tags notifications
+// @Accept  json
+// @Produce  json
+// @Param status query string false "firing, acknowledged or resolved"
+// @Param notification_id query string false "Only alerts of this rule"
+// @Param device_id query string false "Only alerts of this device"
+// @Success 200 {array} dto.AlertResponse "Alerts"
+// @Failure 400 {object} dto.BadRequestErrorResponse "Invalid query parameters"
+// @Failure 401 {object} dto.DetailedErrorResponse "Unauthorized"
+// @Failure 500 {object} dto.InternalServerErrorResponse "Internal server error"
+// @Security ApiKeyAuth
+// @Router /v1/alerts [get]
+func (h *AlertHandler) ListAlerts(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, dto.DetailedErrorResponse{
+			Code:    dto.ErrorCodeInvalidCredentials,
+			Message: "Unauthorized",
+			Details: "User ID not found in context",
+		})
+		return
+	}
+
+	uuidUserID, ok := userID.(uuid.UUID)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, dto.DetailedErrorResponse{
+			Code:    dto.ErrorCodeInternalError,
+			Message: "Internal server error",
+			Details: "Invalid user ID type",
+		})
+		return
+	}
+
+	var query dto.AlertListQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, dto.DetailedErrorResponse{
+			Code:    dto.ErrorCodeInvalidRequest,
+			Message: "Invalid query parameters",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	alerts, err := h.alertService.ListAlerts(uuidUserID, query)
+	if err != nil {
+		if customErr, ok := err.(errors.CustomError); ok {
+			c.JSON(customErr.StatusCode(), dto.DetailedErrorResponse{
+				Code:    dto.ErrorCodeFromStatusCode(customErr.StatusCode()),
+				Message: customErr.Message(),
+				Details: "Failed to list alerts",
+			})
+		} else {
+			c.JSON(http.StatusInternalServerError, dto.DetailedErrorResponse{
+				Code:    dto.ErrorCodeInternalError,
+				Message: "Internal server error",
+				Details: err.Error(),
+			})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, alerts)
+}
+
+// GetAlert godoc
+// @Summary Get an alert
+// @Description Get an alert with its escalation state
+// @Tags notifications
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Alert ID"
+// @Success 200 {object} dto.AlertResponse "Alert"
+// @Failure 400 {object} dto.BadRequestErrorResponse "Invalid alert ID"
+// @Failure 401 {object} dto.DetailedErrorResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenErrorResponse "Forbidden"
+// @Failure 404 {object} dto.DetailedErrorResponse "Alert not found"
+// @Failure 500 {object} dto.InternalServerErrorResponse "Internal server error"
+// @Security ApiKeyAuth
+// @Router /v1/alerts/{id} [get]
+func (h *AlertHandler) GetAlert(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, dto.DetailedErrorResponse{
+			Code:    dto.ErrorCodeInvalidCredentials,
+			Message: "Unauthorized",
+			Details: "User ID not found in context",
+		})
+		return
+	}
+
+	uuidUserID, ok := userID.(uuid.UUID)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, dto.DetailedErrorResponse{
+			Code:    dto.ErrorCodeInternalError,
+			Message: "Internal server error",
+			Details: "Invalid user ID type",
+		})
+		return
+	}
+
+	alertID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.DetailedErrorResponse{
+			Code:    dto.ErrorCodeInvalidRequest,
+			Message: "Invalid alert ID",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	alert, err := h.alertService.GetAlert(uuidUserID, alertID)
+	if err != nil {
+		if customErr, ok := err.(errors.CustomError); ok {
+			c.JSON(customErr.StatusCode(), dto.DetailedErrorResponse{
+				Code:    dto.ErrorCodeFromStatusCode(customErr.StatusCode()),
+				Message: customErr.Message(),
+				Details: "Failed to get alert",
+			})
+		} else {
+			c.JSON(http.StatusInternalServerError, dto.DetailedErrorResponse{
+				Code:    dto.ErrorCodeInternalError,
+				Message: "Internal server error",
+				Details: err.Error(),
+			})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, alert)
+}
+
+// AcknowledgeAlert godoc
+// @Summary Acknowledge an alert
+// @Description Acknowledge a firing alert, which stops its escalation. The alert stays open until a heartbeat no longer triggers the rule. Any member who can see the alert can acknowledge it.
+// @Tags notifications
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Alert ID"
+// @Success 200 {object} dto.AlertResponse "Acknowledged alert"
+// @Failure 400 {object} dto.BadRequestErrorResponse "Invalid alert ID"
+// @Failure 401 {object} dto.DetailedErrorResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenErrorResponse "Forbidden"
+// @Failure 404 {object} dto.DetailedErrorResponse "Alert not found"
+// @Failure 409 {object} dto.ConflictErrorResponse "Alert already acknowledged or resolved"
+// @Failure 500 {object} dto.InternalServerErrorResponse "Internal server error"
+// @Security ApiKeyAuth
+// @Router /v1/alerts/{id}/acknowledge [post]
+func (h *AlertHandler) AcknowledgeAlert(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, dto.DetailedErrorResponse{
+			Code:    dto.ErrorCodeInvalidCredentials,
+			Message: "Unauthorized",
+			Details: "User ID not found in context",
+		})
+		return
+	}
+
+	uuidUserID, ok := userID.(uuid.UUID)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, dto.DetailedErrorResponse{
+			Code:    dto.ErrorCodeInternalError,
+			Message: "Internal server error",
+			Details: "Invalid user ID type",
+		})
+		return
+	}
+
+	alertID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.DetailedErrorResponse{
+			Code:    dto.ErrorCodeInvalidRequest,
+			Message: "Invalid alert ID",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	alert, err := h.alertService.AcknowledgeAlert(uuidUserID, alertID)
+	if err != nil {
+		if customErr, ok := err.(errors.CustomError); ok {
+			c.JSON(customErr.StatusCode(), dto.DetailedErrorResponse{
+				Code:    dto.ErrorCodeFromStatusCode(customErr.StatusCode()),
+				Message: customErr.Message(),
+				Details: "Failed to acknowledge alert",
+			})
+		} else {
+			c.JSON(http.StatusInternalServerError, dto.DetailedErrorResponse{
+				Code:    dto.ErrorCodeInternalError,
+				Message: "Internal server error",
+				Details: err.Error(),
+			})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, alert)
+}
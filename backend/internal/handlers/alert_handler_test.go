@@ -0,0 +1,129 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/dto"
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/models"
+	custom_errors "github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/utils/errors"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockAlertService struct {
+	mock.Mock
+}
+
+func (m *MockAlertService) ListAlerts(userID uuid.UUID, query dto.AlertListQuery) ([]dto.AlertResponse, error) {
+	args := m.Called(userID, query)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]dto.AlertResponse), args.Error(1)
+}
+
+func (m *MockAlertService) GetAlert(userID, alertID uuid.UUID) (*dto.AlertResponse, error) {
+	args := m.Called(userID, alertID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dto.AlertResponse), args.Error(1)
+}
+
+func (m *MockAlertService) AcknowledgeAlert(userID, alertID uuid.UUID) (*dto.AlertResponse, error) {
+	args := m.Called(userID, alertID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dto.AlertResponse), args.Error(1)
+}
+
+func (m *MockAlertService) Fire(notification models.Notification, device *models.Device, alert *dto.NotificationAlert, now time.Time) (bool, error) {
+	args := m.Called(notification, device, alert, now)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockAlertService) Resolve(device *models.Device, notificationIDs []uuid.UUID, now time.Time) error {
+	args := m.Called(device, notificationIDs, now)
+	return args.Error(0)
+}
+
+func (m *MockAlertService) ProcessEscalations(now time.Time) (int, error) {
+	args := m.Called(now)
+	return args.Int(0), args.Error(1)
+}
+
+func TestAlertHandler_ListAlerts(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	userID := uuid.New()
+
+	t.Run("Success - Filter firing alerts", func(t *testing.T) {
+		mockAlertService := new(MockAlertService)
+		handler := NewAlertHandler(mockAlertService)
+
+		mockAlertService.On("ListAlerts", userID, dto.AlertListQuery{Status: "firing"}).Return([]dto.AlertResponse{{ID: uuid.New(), Status: "firing"}}, nil)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Set("userID", userID)
+		c.Request, _ = http.NewRequest("GET", "/alerts?status=firing", nil)
+
+		handler.ListAlerts(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response []dto.AlertResponse
+		json.Unmarshal(w.Body.Bytes(), &response)
+
+		assert.Len(t, response, 1)
+	})
+}
+
+func TestAlertHandler_AcknowledgeAlert(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	userID := uuid.New()
+	alertID := uuid.New()
+
+	t.Run("Success - Alert acknowledged", func(t *testing.T) {
+		mockAlertService := new(MockAlertService)
+		handler := NewAlertHandler(mockAlertService)
+
+		mockAlertService.On("AcknowledgeAlert", userID, alertID).Return(&dto.AlertResponse{ID: alertID, Status: "acknowledged", AcknowledgedBy: &userID}, nil)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Set("userID", userID)
+		c.Params = gin.Params{{Key: "id", Value: alertID.String()}}
+		c.Request, _ = http.NewRequest("POST", "/alerts/"+alertID.String()+"/acknowledge", nil)
+
+		handler.AcknowledgeAlert(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		mockAlertService.AssertExpectations(t)
+	})
+
+	t.Run("Error - Alert no longer firing", func(t *testing.T) {
+		mockAlertService := new(MockAlertService)
+		handler := NewAlertHandler(mockAlertService)
+
+		mockAlertService.On("AcknowledgeAlert", userID, alertID).Return(nil, custom_errors.ErrAlertNotFiring)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Set("userID", userID)
+		c.Params = gin.Params{{Key: "id", Value: alertID.String()}}
+		c.Request, _ = http.NewRequest("POST", "/alerts/"+alertID.String()+"/acknowledge", nil)
+
+		handler.AcknowledgeAlert(c)
+
+		assert.Equal(t, http.StatusConflict, w.Code)
+	})
+}
@@ -0,0 +1,322 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/dto"
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/services"
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/utils/errors"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type DeviceCommandHandler struct {
+	commandService services.DeviceCommandService
+}
+
+func NewDeviceCommandHandler(commandService services.DeviceCommandService) *DeviceCommandHandler {
+	return &DeviceCommandHandler{commandService: commandService}
+}
+
+// IssueDeviceCommand godoc
+// @Summary Issue a device command
+// @Description Send reboot, upload_logs or run_diagnostics to a device. The command is published on the device exchange with routing key device.<id>.command and expires if the device does not finish it within timeout_seconds (300 by default). Requires the operator role.
+// @Tags devices
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Device ID"
+// @Param request body dto.DeviceCommandRequest true "Command"
+// @Success 201 {object} dto.DeviceCommandResponse "Issued command"
+// @Failure 400 {object} dto.BadRequestErrorResponse "Invalid input"
+// @Failure 401 {object} dto.DetailedErrorResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenErrorResponse "Forbidden"
+// @Failure 404 {object} dto.DetailedErrorResponse "Device not found"
+// @Failure 500 {object} dto.InternalServerErrorResponse "Internal server error"
+// @Security ApiKeyAuth
+// @Router /v1/devices/{id}/commands [post]
+func (h *DeviceCommandHandler) IssueDeviceCommand(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, dto.DetailedErrorResponse{
+			Code:    dto.ErrorCodeInvalidCredentials,
+			Message: "Unauthorized",
+			Details: "User ID not found in context",
+		})
+		return
+	}
+
+	uuidUserID, ok := userID.(uuid.UUID)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, dto.DetailedErrorResponse{
+			Code:    dto.ErrorCodeInternalError,
+			Message: "Internal server error",
+			Details: "Invalid user ID type",
+		})
+		return
+	}
+
+	deviceID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.DetailedErrorResponse{
+			Code:    dto.ErrorCodeInvalidRequest,
+			Message: "Invalid device ID",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	var req dto.DeviceCommandRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.DetailedErrorResponse{
+			Code:    dto.ErrorCodeInvalidRequest,
+			Message: "Invalid request format",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	command, err := h.commandService.IssueCommand(uuidUserID, deviceID, req)
+	if err != nil {
+		if customErr, ok := err.(errors.CustomError); ok {
+			c.JSON(customErr.StatusCode(), dto.DetailedErrorResponse{
+				Code:    dto.ErrorCodeFromStatusCode(customErr.StatusCode()),
+				Message: customErr.Message(),
+				Details: "Please check your input and try again",
+			})
+		} else {
+			c.JSON(http.StatusInternalServerError, dto.DetailedErrorResponse{
+				Code:    dto.ErrorCodeInternalError,
+				Message: "Internal server error",
+				Details: err.Error(),
+			})
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, command)
+}
+
+// ListDeviceCommands godoc
+// @Summary List device commands
+// @Description List the latest 100 commands of a device, newest first
+// @Tags devices
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Device ID"
+// @Param status query string false "Only commands in this status (pending, sent, acknowledged, succeeded, failed, expired, cancelled)"
+// @Success 200 {array} dto.DeviceCommandResponse "Commands"
+// @Failure 400 {object} dto.BadRequestErrorResponse "Invalid device ID"
+// @Failure 401 {object} dto.DetailedErrorResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenErrorResponse "Forbidden"
+// @Failure 404 {object} dto.DetailedErrorResponse "Device not found"
+// @Failure 500 {object} dto.InternalServerErrorResponse "Internal server error"
+// @Security ApiKeyAuth
+// @Router /v1/devices/{id}/commands [get]
+func (h *DeviceCommandHandler) ListDeviceCommands(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, dto.DetailedErrorResponse{
+			Code:    dto.ErrorCodeInvalidCredentials,
+			Message: "Unauthorized",
+			Details: "User ID not found in context",
+		})
+		return
+	}
+
+	uuidUserID, ok := userID.(uuid.UUID)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, dto.DetailedErrorResponse{
+			Code:    dto.ErrorCodeInternalError,
+			Message: "Internal server error",
+			Details: "Invalid user ID type",
+		})
+		return
+	}
+
+	deviceID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.DetailedErrorResponse{
+			Code:    dto.ErrorCodeInvalidRequest,
+			Message: "Invalid device ID",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	commands, err := h.commandService.ListCommands(uuidUserID, deviceID, c.Query("status"))
+	if err != nil {
+		if customErr, ok := err.(errors.CustomError); ok {
+			c.JSON(customErr.StatusCode(), dto.DetailedErrorResponse{
+				Code:    dto.ErrorCodeFromStatusCode(customErr.StatusCode()),
+				Message: customErr.Message(),
+				Details: "Failed to list device commands",
+			})
+		} else {
+			c.JSON(http.StatusInternalServerError, dto.DetailedErrorResponse{
+				Code:    dto.ErrorCodeInternalError,
+				Message: "Internal server error",
+				Details: err.Error(),
+			})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, commands)
+}
+
+// GetDeviceCommand godoc
+// @Summary Get a device command
+// @Description Get a command of a device with its status and result
+// @Tags devices
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Device ID"
+// @Param command_id path string true "Command ID"
+// @Success 200 {object} dto.DeviceCommandResponse "Command"
+// @Failure 400 {object} dto.BadRequestErrorResponse "Invalid ID"
+// @Failure 401 {object} dto.DetailedErrorResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenErrorResponse "Forbidden"
+// @Failure 404 {object} dto.DetailedErrorResponse "Device or command not found"
+// @Failure 500 {object} dto.InternalServerErrorResponse "Internal server error"
+// @Security ApiKeyAuth
+// @Router /v1/devices/{id}/commands/{command_id} [get]
+func (h *DeviceCommandHandler) GetDeviceCommand(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, dto.DetailedErrorResponse{
+			Code:    dto.ErrorCodeInvalidCredentials,
+			Message: "Unauthorized",
+			Details: "User ID not found in context",
+		})
+		return
+	}
+
+	uuidUserID, ok := userID.(uuid.UUID)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, dto.DetailedErrorResponse{
+			Code:    dto.ErrorCodeInternalError,
+			Message: "Internal server error",
+			Details: "Invalid user ID type",
+		})
+		return
+	}
+
+	deviceID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.DetailedErrorResponse{
+			Code:    dto.ErrorCodeInvalidRequest,
+			Message: "Invalid device ID",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	commandID, err := uuid.Parse(c.Param("command_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.DetailedErrorResponse{
+			Code:    dto.ErrorCodeInvalidRequest,
+			Message: "Invalid command ID",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	command, err := h.commandService.GetCommand(uuidUserID, deviceID, commandID)
+	if err != nil {
+		if customErr, ok := err.(errors.CustomError); ok {
+			c.JSON(customErr.StatusCode(), dto.DetailedErrorResponse{
+				Code:    dto.ErrorCodeFromStatusCode(customErr.StatusCode()),
+				Message: customErr.Message(),
+				Details: "Failed to get device command",
+			})
+		} else {
+			c.JSON(http.StatusInternalServerError, dto.DetailedErrorResponse{
+				Code:    dto.ErrorCodeInternalError,
+				Message: "Internal server error",
+				Details: err.Error(),
+			})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, command)
+}
+
+// CancelDeviceCommand godoc
+// @Summary Cancel a device command
+// @Description Cancel a command the device has not acknowledged yet. Replies the device sends for it afterwards are ignored. Requires the operator role.
+// @Tags devices
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Device ID"
+// @Param command_id path string true "Command ID"
+// @Success 200 {object} dto.DeviceCommandResponse "Cancelled command"
+// @Failure 400 {object} dto.BadRequestErrorResponse "Invalid ID"
+// @Failure 401 {object} dto.DetailedErrorResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenErrorResponse "Forbidden"
+// @Failure 404 {object} dto.DetailedErrorResponse "Device or command not found"
+// @Failure 409 {object} dto.ConflictErrorResponse "Command already acknowledged or finished"
+// @Failure 500 {object} dto.InternalServerErrorResponse "Internal server error"
+// @Security ApiKeyAuth
+// @Router /v1/devices/{id}/commands/{command_id} [delete]
+func (h *DeviceCommandHandler) CancelDeviceCommand(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, dto.DetailedErrorResponse{
+			Code:    dto.ErrorCodeInvalidCredentials,
+			Message: "Unauthorized",
+			Details: "User ID not found in context",
+		})
+		return
+	}
+
+	uuidUserID, ok := userID.(uuid.UUID)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, dto.DetailedErrorResponse{
+			Code:    dto.ErrorCodeInternalError,
+			Message: "Internal server error",
+			Details: "Invalid user ID type",
+		})
+		return
+	}
+
+	deviceID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.DetailedErrorResponse{
+			Code:    dto.ErrorCodeInvalidRequest,
+			Message: "Invalid device ID",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	commandID, err := uuid.Parse(c.Param("command_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.DetailedErrorResponse{
+			Code:    dto.ErrorCodeInvalidRequest,
+			Message: "Invalid command ID",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	command, err := h.commandService.CancelCommand(uuidUserID, deviceID, commandID)
+	if err != nil {
+		if customErr, ok := err.(errors.CustomError); ok {
+			c.JSON(customErr.StatusCode(), dto.DetailedErrorResponse{
+				Code:    dto.ErrorCodeFromStatusCode(customErr.StatusCode()),
+				Message: customErr.Message(),
+				Details: "Failed to cancel device command",
+			})
+		} else {
+			c.JSON(http.StatusInternalServerError, dto.DetailedErrorResponse{
+				Code:    dto.ErrorCodeInternalError,
+				Message: "Internal server error",
+				Details: err.Error(),
+			})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, command)
+}
@@ -0,0 +1,211 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/dto"
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/models"
+	custom_errors "github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/utils/errors"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockDeviceCommandService struct {
+	mock.Mock
+}
+
+func (m *MockDeviceCommandService) IssueCommand(userID, deviceID uuid.UUID, req dto.DeviceCommandRequest) (*dto.DeviceCommandResponse, error) {
+	args := m.Called(userID, deviceID, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dto.DeviceCommandResponse), args.Error(1)
+}
+
+func (m *MockDeviceCommandService) DispatchCommand(issuedBy, deviceID uuid.UUID, name string, params map[string]interface{}, timeout time.Duration) (*models.DeviceCommand, error) {
+	args := m.Called(issuedBy, deviceID, name, params, timeout)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.DeviceCommand), args.Error(1)
+}
+
+func (m *MockDeviceCommandService) ListCommands(userID, deviceID uuid.UUID, status string) ([]dto.DeviceCommandResponse, error) {
+	args := m.Called(userID, deviceID, status)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]dto.DeviceCommandResponse), args.Error(1)
+}
+
+func (m *MockDeviceCommandService) GetCommand(userID, deviceID, commandID uuid.UUID) (*dto.DeviceCommandResponse, error) {
+	args := m.Called(userID, deviceID, commandID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dto.DeviceCommandResponse), args.Error(1)
+}
+
+func (m *MockDeviceCommandService) CancelCommand(userID, deviceID, commandID uuid.UUID) (*dto.DeviceCommandResponse, error) {
+	args := m.Called(userID, deviceID, commandID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dto.DeviceCommandResponse), args.Error(1)
+}
+
+func (m *MockDeviceCommandService) HandleReply(reply dto.DeviceCommandReply) error {
+	args := m.Called(reply)
+	return args.Error(0)
+}
+
+func (m *MockDeviceCommandService) ProcessCommands() (int64, int, error) {
+	args := m.Called()
+	return args.Get(0).(int64), args.Int(1), args.Error(2)
+}
+
+func TestDeviceCommandHandler_IssueDeviceCommand(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	userID := uuid.New()
+	deviceID := uuid.New()
+
+	t.Run("Success - Issue reboot", func(t *testing.T) {
+		mockCommandService := new(MockDeviceCommandService)
+		handler := NewDeviceCommandHandler(mockCommandService)
+
+		req := dto.DeviceCommandRequest{Name: "reboot"}
+		mockCommandService.On("IssueCommand", userID, deviceID, req).Return(&dto.DeviceCommandResponse{ID: uuid.New(), DeviceID: deviceID, Name: "reboot", Status: "sent"}, nil)
+
+		body, _ := json.Marshal(req)
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Set("userID", userID)
+		c.Params = gin.Params{{Key: "id", Value: deviceID.String()}}
+		c.Request, _ = http.NewRequest("POST", "/devices/"+deviceID.String()+"/commands", bytes.NewBuffer(body))
+		c.Request.Header.Set("Content-Type", "application/json")
+
+		handler.IssueDeviceCommand(c)
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+
+		var response dto.DeviceCommandResponse
+		json.Unmarshal(w.Body.Bytes(), &response)
+
+		assert.Equal(t, "sent", response.Status)
+		mockCommandService.AssertExpectations(t)
+	})
+
+	t.Run("Error - Missing name", func(t *testing.T) {
+		mockCommandService := new(MockDeviceCommandService)
+		handler := NewDeviceCommandHandler(mockCommandService)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Set("userID", userID)
+		c.Params = gin.Params{{Key: "id", Value: deviceID.String()}}
+		c.Request, _ = http.NewRequest("POST", "/devices/"+deviceID.String()+"/commands", bytes.NewBufferString(`{"timeout_seconds":60}`))
+		c.Request.Header.Set("Content-Type", "application/json")
+
+		handler.IssueDeviceCommand(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		mockCommandService.AssertNotCalled(t, "IssueCommand", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestDeviceCommandHandler_ListDeviceCommands(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	userID := uuid.New()
+	deviceID := uuid.New()
+
+	t.Run("Success - Filter by status", func(t *testing.T) {
+		mockCommandService := new(MockDeviceCommandService)
+		handler := NewDeviceCommandHandler(mockCommandService)
+
+		mockCommandService.On("ListCommands", userID, deviceID, "failed").Return([]dto.DeviceCommandResponse{{ID: uuid.New(), Status: "failed"}}, nil)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Set("userID", userID)
+		c.Params = gin.Params{{Key: "id", Value: deviceID.String()}}
+		c.Request, _ = http.NewRequest("GET", "/devices/"+deviceID.String()+"/commands?status=failed", nil)
+
+		handler.ListDeviceCommands(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response []dto.DeviceCommandResponse
+		json.Unmarshal(w.Body.Bytes(), &response)
+
+		assert.Len(t, response, 1)
+		mockCommandService.AssertExpectations(t)
+	})
+}
+
+func TestDeviceCommandHandler_CancelDeviceCommand(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	userID := uuid.New()
+	deviceID := uuid.New()
+	commandID := uuid.New()
+
+	t.Run("Success - Cancel command", func(t *testing.T) {
+		mockCommandService := new(MockDeviceCommandService)
+		handler := NewDeviceCommandHandler(mockCommandService)
+
+		mockCommandService.On("CancelCommand", userID, deviceID, commandID).Return(&dto.DeviceCommandResponse{ID: commandID, Status: "cancelled"}, nil)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Set("userID", userID)
+		c.Params = gin.Params{{Key: "id", Value: deviceID.String()}, {Key: "command_id", Value: commandID.String()}}
+		c.Request, _ = http.NewRequest("DELETE", "/devices/"+deviceID.String()+"/commands/"+commandID.String(), nil)
+
+		handler.CancelDeviceCommand(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		mockCommandService.AssertExpectations(t)
+	})
+
+	t.Run("Error - Already finished", func(t *testing.T) {
+		mockCommandService := new(MockDeviceCommandService)
+		handler := NewDeviceCommandHandler(mockCommandService)
+
+		mockCommandService.On("CancelCommand", userID, deviceID, commandID).Return(nil, custom_errors.ErrDeviceCommandFinished)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Set("userID", userID)
+		c.Params = gin.Params{{Key: "id", Value: deviceID.String()}, {Key: "command_id", Value: commandID.String()}}
+		c.Request, _ = http.NewRequest("DELETE", "/devices/"+deviceID.String()+"/commands/"+commandID.String(), nil)
+
+		handler.CancelDeviceCommand(c)
+
+		assert.Equal(t, http.StatusConflict, w.Code)
+	})
+
+	t.Run("Error - Invalid command ID", func(t *testing.T) {
+		mockCommandService := new(MockDeviceCommandService)
+		handler := NewDeviceCommandHandler(mockCommandService)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Set("userID", userID)
+		c.Params = gin.Params{{Key: "id", Value: deviceID.String()}, {Key: "command_id", Value: "invalid"}}
+		c.Request, _ = http.NewRequest("DELETE", "/devices/"+deviceID.String()+"/commands/invalid", nil)
+
+		handler.CancelDeviceCommand(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		mockCommandService.AssertNotCalled(t, "CancelCommand", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
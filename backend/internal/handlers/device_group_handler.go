@@ -0,0 +1,657 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/dto"
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/services"
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/utils/errors"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type DeviceGroupHandler struct {
+	groupService  services.DeviceGroupService
+	deviceService services.DeviceService
+}
+
+func NewDeviceGroupHandler(groupService services.DeviceGroupService, deviceService services.DeviceService) *DeviceGroupHandler {
+	return &DeviceGroupHandler{
+		groupService:  groupService,
+		deviceService: deviceService,
+	}
+}
+
+// ListGroups godoc
+// @Summary List device groups
+// @Description Get all device groups of the authenticated user as a flat list; use parent_id to rebuild the tree
+// @Tags groups
+// @Accept  json
+// @Produce  json
+// @Success 200 {array} dto.DeviceGroupResponse "List of groups"
+// @Failure 401 {object} dto.DetailedErrorResponse "Unauthorized"
+// @Failure 500 {object} dto.InternalServerErrorResponse "Internal server error"
+// @Security ApiKeyAuth
+// @Router /v1/groups [get]
+func (h *DeviceGroupHandler) ListGroups(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, dto.DetailedErrorResponse{
+			Code:    dto.ErrorCodeInvalidCredentials,
+			Message: "Unauthorized",
+			Details: "User ID not found in context",
+		})
+		return
+	}
+
+	uuidUserID, ok := userID.(uuid.UUID)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, dto.DetailedErrorResponse{
+			Code:    dto.ErrorCodeInternalError,
+			Message: "Internal server error",
+			Details: "Invalid user ID type",
+		})
+		return
+	}
+
+	groups, err := h.groupService.ListGroups(uuidUserID)
+	if err != nil {
+		if customErr, ok := err.(errors.CustomError); ok {
+			c.JSON(customErr.StatusCode(), dto.DetailedErrorResponse{
+				Code:    dto.ErrorCodeFromStatusCode(customErr.StatusCode()),
+				Message: customErr.Message(),
+				Details: "Failed to list groups",
+			})
+		} else {
+			c.JSON(http.StatusInternalServerError, dto.DetailedErrorResponse{
+				Code:    dto.ErrorCodeInternalError,
+				Message: "Internal server error",
+				Details: err.Error(),
+			})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, groups)
+}
+
+// CreateGroup godoc
+// @Summary Create a device group
+// @Description Create a group, optionally nested under a parent group
+// @Tags groups
+// @Accept  json
+// @Produce  json
+// @Param request body dto.DeviceGroupRequest true "Group information"
+// @Success 201 {object} dto.DeviceGroupResponse "Group created"
+// @Failure 400 {object} dto.BadRequestErrorResponse "Invalid input or parent group"
+// @Failure 401 {object} dto.DetailedErrorResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenErrorResponse "Forbidden"
+// @Failure 500 {object} dto.InternalServerErrorResponse "Internal server error"
+// @Security ApiKeyAuth
+// @Router /v1/groups [post]
+func (h *DeviceGroupHandler) CreateGroup(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, dto.DetailedErrorResponse{
+			Code:    dto.ErrorCodeInvalidCredentials,
+			Message: "Unauthorized",
+			Details: "User ID not found in context",
+		})
+		return
+	}
+
+	uuidUserID, ok := userID.(uuid.UUID)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, dto.DetailedErrorResponse{
+			Code:    dto.ErrorCodeInternalError,
+			Message: "Internal server error",
+			Details: "Invalid user ID type",
+		})
+		return
+	}
+
+	var req dto.DeviceGroupRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.DetailedErrorResponse{
+			Code:    dto.ErrorCodeInvalidRequest,
+			Message: "Invalid request format",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	group, err := h.groupService.CreateGroup(uuidUserID, activeOrganizationID(c), req)
+	if err != nil {
+		if customErr, ok := err.(errors.CustomError); ok {
+			c.JSON(customErr.StatusCode(), dto.DetailedErrorResponse{
+				Code:    dto.ErrorCodeFromStatusCode(customErr.StatusCode()),
+				Message: customErr.Message(),
+				Details: "Please check your input and try again",
+			})
+		} else {
+			c.JSON(http.StatusInternalServerError, dto.DetailedErrorResponse{
+				Code:    dto.ErrorCodeInternalError,
+				Message: "Internal server error",
+				Details: err.Error(),
+			})
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, group)
+}
+
+// GetGroup godoc
+// @Summary Get a device group
+// @Description Get a specific device group by ID
+// @Tags groups
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Group ID"
+// @Success 200 {object} dto.DeviceGroupResponse "Group details"
+// @Failure 400 {object} dto.BadRequestErrorResponse "Invalid group ID"
+// @Failure 401 {object} dto.DetailedErrorResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenErrorResponse "Forbidden"
+// @Failure 404 {object} dto.DetailedErrorResponse "Group not found"
+// @Failure 500 {object} dto.InternalServerErrorResponse "Internal server error"
+// @Security ApiKeyAuth
+// @Router /v1/groups/{id} [get]
+func (h *DeviceGroupHandler) GetGroup(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, dto.DetailedErrorResponse{
+			Code:    dto.ErrorCodeInvalidCredentials,
+			Message: "Unauthorized",
+			Details: "User ID not found in context",
+		})
+		return
+	}
+
+	uuidUserID, ok := userID.(uuid.UUID)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, dto.DetailedErrorResponse{
+			Code:    dto.ErrorCodeInternalError,
+			Message: "Internal server error",
+			Details: "Invalid user ID type",
+		})
+		return
+	}
+
+	groupID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.DetailedErrorResponse{
+			Code:    dto.ErrorCodeInvalidRequest,
+			Message: "Invalid group ID",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	group, err := h.groupService.GetGroup(uuidUserID, groupID)
+	if err != nil {
+		if customErr, ok := err.(errors.CustomError); ok {
+			c.JSON(customErr.StatusCode(), dto.DetailedErrorResponse{
+				Code:    dto.ErrorCodeFromStatusCode(customErr.StatusCode()),
+				Message: customErr.Message(),
+				Details: "Please check your input and try again",
+			})
+		} else {
+			c.JSON(http.StatusInternalServerError, dto.DetailedErrorResponse{
+				Code:    dto.ErrorCodeInternalError,
+				Message: "Internal server error",
+				Details: err.Error(),
+			})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, group)
+}
+
+// UpdateGroup godoc
+// @Summary Update a device group
+// @Description Rename a group or move it under another parent (omit parent_id to make it a root group). A group cannot be moved under one of its own subgroups.
+// @Tags groups
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Group ID"
+// @Param request body dto.DeviceGroupRequest true "Group information"
+// @Success 200 {object} dto.DeviceGroupResponse "Updated group"
+// @Failure 400 {object} dto.BadRequestErrorResponse "Invalid input or parent group"
+// @Failure 401 {object} dto.DetailedErrorResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenErrorResponse "Forbidden"
+// @Failure 404 {object} dto.DetailedErrorResponse "Group not found"
+// @Failure 500 {object} dto.InternalServerErrorResponse "Internal server error"
+// @Security ApiKeyAuth
+// @Router /v1/groups/{id} [put]
+func (h *DeviceGroupHandler) UpdateGroup(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, dto.DetailedErrorResponse{
+			Code:    dto.ErrorCodeInvalidCredentials,
+			Message: "Unauthorized",
+			Details: "User ID not found in context",
+		})
+		return
+	}
+
+	uuidUserID, ok := userID.(uuid.UUID)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, dto.DetailedErrorResponse{
+			Code:    dto.ErrorCodeInternalError,
+			Message: "Internal server error",
+			Details: "Invalid user ID type",
+		})
+		return
+	}
+
+	groupID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.DetailedErrorResponse{
+			Code:    dto.ErrorCodeInvalidRequest,
+			Message: "Invalid group ID",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	var req dto.DeviceGroupRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.DetailedErrorResponse{
+			Code:    dto.ErrorCodeInvalidRequest,
+			Message: "Invalid request format",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	group, err := h.groupService.UpdateGroup(uuidUserID, groupID, req)
+	if err != nil {
+		if customErr, ok := err.(errors.CustomError); ok {
+			c.JSON(customErr.StatusCode(), dto.DetailedErrorResponse{
+				Code:    dto.ErrorCodeFromStatusCode(customErr.StatusCode()),
+				Message: customErr.Message(),
+				Details: "Please check your input and try again",
+			})
+		} else {
+			c.JSON(http.StatusInternalServerError, dto.DetailedErrorResponse{
+				Code:    dto.ErrorCodeInternalError,
+				Message: "Internal server error",
+				Details: err.Error(),
+			})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, group)
+}
+
+// DeleteGroup godoc
+// @Summary Delete a device group
+// @Description Delete a group without subgroups; its devices are detached, not deleted
+// @Tags groups
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Group ID"
+// @Success 204 "Group deleted"
+// @Failure 400 {object} dto.BadRequestErrorResponse "Invalid group ID"
+// @Failure 409 {object} dto.DetailedErrorResponse "Group has subgroups"
+// @Failure 401 {object} dto.DetailedErrorResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenErrorResponse "Forbidden"
+// @Failure 404 {object} dto.DetailedErrorResponse "Group not found"
+// @Failure 500 {object} dto.InternalServerErrorResponse "Internal server error"
+// @Security ApiKeyAuth
+// @Router /v1/groups/{id} [delete]
+func (h *DeviceGroupHandler) DeleteGroup(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, dto.DetailedErrorResponse{
+			Code:    dto.ErrorCodeInvalidCredentials,
+			Message: "Unauthorized",
+			Details: "User ID not found in context",
+		})
+		return
+	}
+
+	uuidUserID, ok := userID.(uuid.UUID)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, dto.DetailedErrorResponse{
+			Code:    dto.ErrorCodeInternalError,
+			Message: "Internal server error",
+			Details: "Invalid user ID type",
+		})
+		return
+	}
+
+	groupID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.DetailedErrorResponse{
+			Code:    dto.ErrorCodeInvalidRequest,
+			Message: "Invalid group ID",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	err = h.groupService.DeleteGroup(uuidUserID, groupID)
+	if err != nil {
+		if customErr, ok := err.(errors.CustomError); ok {
+			c.JSON(customErr.StatusCode(), dto.DetailedErrorResponse{
+				Code:    dto.ErrorCodeFromStatusCode(customErr.StatusCode()),
+				Message: customErr.Message(),
+				Details: "Failed to delete group",
+			})
+		} else {
+			c.JSON(http.StatusInternalServerError, dto.DetailedErrorResponse{
+				Code:    dto.ErrorCodeInternalError,
+				Message: "Internal server error",
+				Details: err.Error(),
+			})
+		}
+		return
+	}
+
+	c.AbortWithStatus(http.StatusNoContent)
+}
+
+// GetGroupSummary godoc
+// @Summary Device group summary
+// @Description Status counts (online, offline, never seen) and latest metrics rolled up across the devices of the group and all its subgroups. A device is offline after two heartbeat intervals without a heartbeat; the interval is the heartbeat_interval_seconds of its shadow, reported before desired, 1 minute when unset
+// @Tags groups
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Group ID"
+// @Success 200 {object} dto.DeviceGroupSummaryResponse "Group summary"
+// @Failure 400 {object} dto.BadRequestErrorResponse "Invalid group ID"
+// @Failure 401 {object} dto.DetailedErrorResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenErrorResponse "Forbidden"
+// @Failure 404 {object} dto.DetailedErrorResponse "Group not found"
+// @Failure 500 {object} dto.InternalServerErrorResponse "Internal server error"
+// @Security ApiKeyAuth
+// @Router /v1/groups/{id}/summary [get]
+func (h *DeviceGroupHandler) GetGroupSummary(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, dto.DetailedErrorResponse{
+			Code:    dto.ErrorCodeInvalidCredentials,
+			Message: "Unauthorized",
+			Details: "User ID not found in context",
+		})
+		return
+	}
+
+	uuidUserID, ok := userID.(uuid.UUID)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, dto.DetailedErrorResponse{
+			Code:    dto.ErrorCodeInternalError,
+			Message: "Internal server error",
+			Details: "Invalid user ID type",
+		})
+		return
+	}
+
+	groupID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.DetailedErrorResponse{
+			Code:    dto.ErrorCodeInvalidRequest,
+			Message: "Invalid group ID",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	summary, err := h.groupService.GetGroupSummary(uuidUserID, groupID)
+	if err != nil {
+		if customErr, ok := err.(errors.CustomError); ok {
+			c.JSON(customErr.StatusCode(), dto.DetailedErrorResponse{
+				Code:    dto.ErrorCodeFromStatusCode(customErr.StatusCode()),
+				Message: customErr.Message(),
+				Details: "Failed to build group summary",
+			})
+		} else {
+			c.JSON(http.StatusInternalServerError, dto.DetailedErrorResponse{
+				Code:    dto.ErrorCodeInternalError,
+				Message: "Internal server error",
+				Details: err.Error(),
+			})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, summary)
+}
+
+// ListGroupDevices godoc
+// @Summary List devices of a group
+// @Description List the devices of a group; with recursive=true the devices of all subgroups are included
+// @Tags groups
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Group ID"
+// @Param recursive query bool false "Include devices of subgroups" default(false)
+// @Success 200 {array} dto.DeviceResponse "List of devices"
+// @Failure 400 {object} dto.BadRequestErrorResponse "Invalid group ID or parameters"
+// @Failure 401 {object} dto.DetailedErrorResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenErrorResponse "Forbidden"
+// @Failure 404 {object} dto.DetailedErrorResponse "Group not found"
+// @Failure 500 {object} dto.InternalServerErrorResponse "Internal server error"
+// @Security ApiKeyAuth
+// @Router /v1/groups/{id}/devices [get]
+func (h *DeviceGroupHandler) ListGroupDevices(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, dto.DetailedErrorResponse{
+			Code:    dto.ErrorCodeInvalidCredentials,
+			Message: "Unauthorized",
+			Details: "User ID not found in context",
+		})
+		return
+	}
+
+	uuidUserID, ok := userID.(uuid.UUID)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, dto.DetailedErrorResponse{
+			Code:    dto.ErrorCodeInternalError,
+			Message: "Internal server error",
+			Details: "Invalid user ID type",
+		})
+		return
+	}
+
+	groupID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.DetailedErrorResponse{
+			Code:    dto.ErrorCodeInvalidRequest,
+			Message: "Invalid group ID",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	recursive, err := strconv.ParseBool(c.DefaultQuery("recursive", "false"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.DetailedErrorResponse{
+			Code:    dto.ErrorCodeInvalidRequest,
+			Message: "Invalid recursive parameter",
+			Details: "Use true or false",
+		})
+		return
+	}
+
+	devices, err := h.deviceService.ListDevicesByGroup(uuidUserID, groupID, recursive)
+	if err != nil {
+		if customErr, ok := err.(errors.CustomError); ok {
+			c.JSON(customErr.StatusCode(), dto.DetailedErrorResponse{
+				Code:    dto.ErrorCodeFromStatusCode(customErr.StatusCode()),
+				Message: customErr.Message(),
+				Details: "Failed to list group devices",
+			})
+		} else {
+			c.JSON(http.StatusInternalServerError, dto.DetailedErrorResponse{
+				Code:    dto.ErrorCodeInternalError,
+				Message: "Internal server error",
+				Details: err.Error(),
+			})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, devices)
+}
+
+// AddDevices godoc
+// @Summary Add devices to a group
+// @Description Move devices into a group. A device belongs to at most one group, so devices already in another group are moved.
+// @Tags groups
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Group ID"
+// @Param request body dto.DeviceGroupMembersRequest true "Devices to add"
+// @Success 204 "Devices added"
+// @Failure 400 {object} dto.BadRequestErrorResponse "Invalid input"
+// @Failure 401 {object} dto.DetailedErrorResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenErrorResponse "Forbidden"
+// @Failure 404 {object} dto.DetailedErrorResponse "Group not found"
+// @Failure 500 {object} dto.InternalServerErrorResponse "Internal server error"
+// @Security ApiKeyAuth
+// @Router /v1/groups/{id}/devices [post]
+func (h *DeviceGroupHandler) AddDevices(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, dto.DetailedErrorResponse{
+			Code:    dto.ErrorCodeInvalidCredentials,
+			Message: "Unauthorized",
+			Details: "User ID not found in context",
+		})
+		return
+	}
+
+	uuidUserID, ok := userID.(uuid.UUID)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, dto.DetailedErrorResponse{
+			Code:    dto.ErrorCodeInternalError,
+			Message: "Internal server error",
+			Details: "Invalid user ID type",
+		})
+		return
+	}
+
+	groupID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.DetailedErrorResponse{
+			Code:    dto.ErrorCodeInvalidRequest,
+			Message: "Invalid group ID",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	var req dto.DeviceGroupMembersRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.DetailedErrorResponse{
+			Code:    dto.ErrorCodeInvalidRequest,
+			Message: "Invalid request format",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	err = h.groupService.AddDevices(uuidUserID, groupID, req.DeviceIDs)
+	if err != nil {
+		if customErr, ok := err.(errors.CustomError); ok {
+			c.JSON(customErr.StatusCode(), dto.DetailedErrorResponse{
+				Code:    dto.ErrorCodeFromStatusCode(customErr.StatusCode()),
+				Message: customErr.Message(),
+				Details: "Failed to add devices to group",
+			})
+		} else {
+			c.JSON(http.StatusInternalServerError, dto.DetailedErrorResponse{
+				Code:    dto.ErrorCodeInternalError,
+				Message: "Internal server error",
+				Details: err.Error(),
+			})
+		}
+		return
+	}
+
+	c.AbortWithStatus(http.StatusNoContent)
+}
+
+// RemoveDevice godoc
+// @Summary Remove a device from a group
+// @Description Detach a device from a group; the device itself is kept
+// @Tags groups
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Group ID"
+// @Param device_id path string true "Device ID"
+// @Success 204 "Device removed from group"
+// @Failure 400 {object} dto.BadRequestErrorResponse "Invalid ID"
+// @Failure 401 {object} dto.DetailedErrorResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenErrorResponse "Forbidden"
+// @Failure 404 {object} dto.DetailedErrorResponse "Group, device or membership not found"
+// @Failure 500 {object} dto.InternalServerErrorResponse "Internal server error"
+// @Security ApiKeyAuth
+// @Router /v1/groups/{id}/devices/{device_id} [delete]
+func (h *DeviceGroupHandler) RemoveDevice(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, dto.DetailedErrorResponse{
+			Code:    dto.ErrorCodeInvalidCredentials,
+			Message: "Unauthorized",
+			Details: "User ID not found in context",
+		})
+		return
+	}
+
+	uuidUserID, ok := userID.(uuid.UUID)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, dto.DetailedErrorResponse{
+			Code:    dto.ErrorCodeInternalError,
+			Message: "Internal server error",
+			Details: "Invalid user ID type",
+		})
+		return
+	}
+
+	groupID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.DetailedErrorResponse{
+			Code:    dto.ErrorCodeInvalidRequest,
+			Message: "Invalid group ID",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	deviceID, err := uuid.Parse(c.Param("device_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.DetailedErrorResponse{
+			Code:    dto.ErrorCodeInvalidRequest,
+			Message: "Invalid device ID",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	err = h.groupService.RemoveDevice(uuidUserID, groupID, deviceID)
+	if err != nil {
+		if customErr, ok := err.(errors.CustomError); ok {
+			c.JSON(customErr.StatusCode(), dto.DetailedErrorResponse{
+				Code:    dto.ErrorCodeFromStatusCode(customErr.StatusCode()),
+				Message: customErr.Message(),
+				Details: "Failed to remove device from group",
+			})
+		} else {
+			c.JSON(http.StatusInternalServerError, dto.DetailedErrorResponse{
+				Code:    dto.ErrorCodeInternalError,
+				Message: "Internal server error",
+				Details: err.Error(),
+			})
+		}
+		return
+	}
+
+	c.AbortWithStatus(http.StatusNoContent)
+}
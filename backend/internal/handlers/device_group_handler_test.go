@@ -0,0 +1,354 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/dto"
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/models"
+	custom_errors "github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/utils/errors"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockDeviceGroupService struct {
+	mock.Mock
+}
+
+func (m *MockDeviceGroupService) CreateGroup(userID uuid.UUID, orgID *uuid.UUID, req dto.DeviceGroupRequest) (*models.DeviceGroup, error) {
+	args := m.Called(userID, orgID, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.DeviceGroup), args.Error(1)
+}
+
+func (m *MockDeviceGroupService) GetGroup(userID, groupID uuid.UUID) (*models.DeviceGroup, error) {
+	args := m.Called(userID, groupID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.DeviceGroup), args.Error(1)
+}
+
+func (m *MockDeviceGroupService) ListGroups(userID uuid.UUID) ([]models.DeviceGroup, error) {
+	args := m.Called(userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.DeviceGroup), args.Error(1)
+}
+
+func (m *MockDeviceGroupService) UpdateGroup(userID, groupID uuid.UUID, req dto.DeviceGroupRequest) (*models.DeviceGroup, error) {
+	args := m.Called(userID, groupID, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.DeviceGroup), args.Error(1)
+}
+
+func (m *MockDeviceGroupService) DeleteGroup(userID, groupID uuid.UUID) error {
+	args := m.Called(userID, groupID)
+	return args.Error(0)
+}
+
+func (m *MockDeviceGroupService) AddDevices(userID, groupID uuid.UUID, deviceIDs []uuid.UUID) error {
+	args := m.Called(userID, groupID, deviceIDs)
+	return args.Error(0)
+}
+
+func (m *MockDeviceGroupService) RemoveDevice(userID, groupID, deviceID uuid.UUID) error {
+	args := m.Called(userID, groupID, deviceID)
+	return args.Error(0)
+}
+
+func (m *MockDeviceGroupService) GetGroupSummary(userID, groupID uuid.UUID) (*dto.DeviceGroupSummaryResponse, error) {
+	args := m.Called(userID, groupID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dto.DeviceGroupSummaryResponse), args.Error(1)
+}
+
+func TestDeviceGroupHandler_CreateGroup(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	userID := uuid.New()
+	parentID := uuid.New()
+
+	t.Run("Success - Create nested group", func(t *testing.T) {
+		mockGroupService := new(MockDeviceGroupService)
+		handler := NewDeviceGroupHandler(mockGroupService, new(MockDeviceService))
+
+		req := dto.DeviceGroupRequest{Name: "Rack 01", ParentID: &parentID}
+		mockGroupService.On("CreateGroup", userID, (*uuid.UUID)(nil), req).Return(&models.DeviceGroup{ID: uuid.New(), UserID: userID, ParentID: &parentID, Name: "Rack 01"}, nil)
+
+		body, _ := json.Marshal(req)
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Set("userID", userID)
+		c.Request, _ = http.NewRequest("POST", "/groups", bytes.NewBuffer(body))
+		c.Request.Header.Set("Content-Type", "application/json")
+
+		handler.CreateGroup(c)
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+
+		var response models.DeviceGroup
+		json.Unmarshal(w.Body.Bytes(), &response)
+
+		assert.Equal(t, "Rack 01", response.Name)
+		assert.Equal(t, parentID, *response.ParentID)
+		mockGroupService.AssertExpectations(t)
+	})
+
+	t.Run("Error - Missing name", func(t *testing.T) {
+		mockGroupService := new(MockDeviceGroupService)
+		handler := NewDeviceGroupHandler(mockGroupService, new(MockDeviceService))
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Set("userID", userID)
+		c.Request, _ = http.NewRequest("POST", "/groups", bytes.NewBufferString(`{"description":"no name"}`))
+		c.Request.Header.Set("Content-Type", "application/json")
+
+		handler.CreateGroup(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		mockGroupService.AssertNotCalled(t, "CreateGroup", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Error - User ID not found in context", func(t *testing.T) {
+		handler := NewDeviceGroupHandler(new(MockDeviceGroupService), new(MockDeviceService))
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+
+		handler.CreateGroup(c)
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+}
+
+func TestDeviceGroupHandler_DeleteGroup(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	userID := uuid.New()
+	groupID := uuid.New()
+
+	t.Run("Success - Delete group", func(t *testing.T) {
+		mockGroupService := new(MockDeviceGroupService)
+		handler := NewDeviceGroupHandler(mockGroupService, new(MockDeviceService))
+
+		mockGroupService.On("DeleteGroup", userID, groupID).Return(nil)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Set("userID", userID)
+		c.Params = gin.Params{{Key: "id", Value: groupID.String()}}
+
+		handler.DeleteGroup(c)
+
+		assert.Equal(t, http.StatusNoContent, c.Writer.Status())
+		mockGroupService.AssertExpectations(t)
+	})
+
+	t.Run("Error - Group has subgroups", func(t *testing.T) {
+		mockGroupService := new(MockDeviceGroupService)
+		handler := NewDeviceGroupHandler(mockGroupService, new(MockDeviceService))
+
+		mockGroupService.On("DeleteGroup", userID, groupID).Return(custom_errors.ErrDeviceGroupHasChildren)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Set("userID", userID)
+		c.Params = gin.Params{{Key: "id", Value: groupID.String()}}
+
+		handler.DeleteGroup(c)
+
+		assert.Equal(t, http.StatusConflict, w.Code)
+		mockGroupService.AssertExpectations(t)
+	})
+}
+
+func TestDeviceGroupHandler_ListGroupDevices(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	userID := uuid.New()
+	groupID := uuid.New()
+
+	t.Run("Success - Recursive listing", func(t *testing.T) {
+		mockDeviceService := new(MockDeviceService)
+		handler := NewDeviceGroupHandler(new(MockDeviceGroupService), mockDeviceService)
+
+		devices := []models.Device{
+			{UUID: uuid.New(), Name: "Device 1", UserID: userID},
+			{UUID: uuid.New(), Name: "Device 2", UserID: userID},
+		}
+		mockDeviceService.On("ListDevicesByGroup", userID, groupID, true).Return(devices, nil)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Set("userID", userID)
+		c.Params = gin.Params{{Key: "id", Value: groupID.String()}}
+		c.Request, _ = http.NewRequest("GET", "/groups/"+groupID.String()+"/devices?recursive=true", nil)
+
+		handler.ListGroupDevices(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response []models.Device
+		json.Unmarshal(w.Body.Bytes(), &response)
+
+		assert.Len(t, response, 2)
+		mockDeviceService.AssertExpectations(t)
+	})
+
+	t.Run("Error - Invalid recursive parameter", func(t *testing.T) {
+		mockDeviceService := new(MockDeviceService)
+		handler := NewDeviceGroupHandler(new(MockDeviceGroupService), mockDeviceService)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Set("userID", userID)
+		c.Params = gin.Params{{Key: "id", Value: groupID.String()}}
+		c.Request, _ = http.NewRequest("GET", "/groups/"+groupID.String()+"/devices?recursive=maybe", nil)
+
+		handler.ListGroupDevices(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		mockDeviceService.AssertNotCalled(t, "ListDevicesByGroup", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Error - Group not found", func(t *testing.T) {
+		mockDeviceService := new(MockDeviceService)
+		handler := NewDeviceGroupHandler(new(MockDeviceGroupService), mockDeviceService)
+
+		mockDeviceService.On("ListDevicesByGroup", userID, groupID, false).Return(nil, custom_errors.ErrDeviceGroupNotFound)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Set("userID", userID)
+		c.Params = gin.Params{{Key: "id", Value: groupID.String()}}
+		c.Request, _ = http.NewRequest("GET", "/groups/"+groupID.String()+"/devices", nil)
+
+		handler.ListGroupDevices(c)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		mockDeviceService.AssertExpectations(t)
+	})
+}
+
+func TestDeviceGroupHandler_Membership(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	userID := uuid.New()
+	groupID := uuid.New()
+	deviceID := uuid.New()
+
+	t.Run("Success - Add devices", func(t *testing.T) {
+		mockGroupService := new(MockDeviceGroupService)
+		handler := NewDeviceGroupHandler(mockGroupService, new(MockDeviceService))
+
+		mockGroupService.On("AddDevices", userID, groupID, []uuid.UUID{deviceID}).Return(nil)
+
+		body, _ := json.Marshal(dto.DeviceGroupMembersRequest{DeviceIDs: []uuid.UUID{deviceID}})
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Set("userID", userID)
+		c.Params = gin.Params{{Key: "id", Value: groupID.String()}}
+		c.Request, _ = http.NewRequest("POST", "/groups/"+groupID.String()+"/devices", bytes.NewBuffer(body))
+		c.Request.Header.Set("Content-Type", "application/json")
+
+		handler.AddDevices(c)
+
+		assert.Equal(t, http.StatusNoContent, c.Writer.Status())
+		mockGroupService.AssertExpectations(t)
+	})
+
+	t.Run("Error - Remove device not in group", func(t *testing.T) {
+		mockGroupService := new(MockDeviceGroupService)
+		handler := NewDeviceGroupHandler(mockGroupService, new(MockDeviceService))
+
+		mockGroupService.On("RemoveDevice", userID, groupID, deviceID).Return(custom_errors.ErrDeviceNotInGroup)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Set("userID", userID)
+		c.Params = gin.Params{{Key: "id", Value: groupID.String()}, {Key: "device_id", Value: deviceID.String()}}
+
+		handler.RemoveDevice(c)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		mockGroupService.AssertExpectations(t)
+	})
+
+	t.Run("Error - Invalid device ID", func(t *testing.T) {
+		mockGroupService := new(MockDeviceGroupService)
+		handler := NewDeviceGroupHandler(mockGroupService, new(MockDeviceService))
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Set("userID", userID)
+		c.Params = gin.Params{{Key: "id", Value: groupID.String()}, {Key: "device_id", Value: "invalid"}}
+
+		handler.RemoveDevice(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestDeviceGroupHandler_GetGroupSummary(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	userID := uuid.New()
+	groupID := uuid.New()
+
+	t.Run("Success - Summary", func(t *testing.T) {
+		mockGroupService := new(MockDeviceGroupService)
+		handler := NewDeviceGroupHandler(mockGroupService, new(MockDeviceService))
+
+		summary := &dto.DeviceGroupSummaryResponse{
+			Group:  dto.DeviceGroupResponse{ID: groupID, UserID: userID, Name: "Site SP"},
+			Status: dto.DeviceGroupStatusCounts{Total: 2, Online: 1, Offline: 1},
+		}
+		mockGroupService.On("GetGroupSummary", userID, groupID).Return(summary, nil)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Set("userID", userID)
+		c.Params = gin.Params{{Key: "id", Value: groupID.String()}}
+
+		handler.GetGroupSummary(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response dto.DeviceGroupSummaryResponse
+		json.Unmarshal(w.Body.Bytes(), &response)
+
+		assert.Equal(t, 2, response.Status.Total)
+		assert.Nil(t, response.LatestMetrics)
+		mockGroupService.AssertExpectations(t)
+	})
+
+	t.Run("Error - Forbidden", func(t *testing.T) {
+		mockGroupService := new(MockDeviceGroupService)
+		handler := NewDeviceGroupHandler(mockGroupService, new(MockDeviceService))
+
+		mockGroupService.On("GetGroupSummary", userID, groupID).Return(nil, custom_errors.ErrForbidden)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Set("userID", userID)
+		c.Params = gin.Params{{Key: "id", Value: groupID.String()}}
+
+		handler.GetGroupSummary(c)
+
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+}
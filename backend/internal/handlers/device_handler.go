@@ -1,7 +1,13 @@
 package handlers
 
 import (
+	"encoding/csv"
+	"fmt"
+	"io"
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/dto"
 	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/services"
@@ -20,11 +26,20 @@ func NewDeviceHandler(deviceService services.DeviceService) *DeviceHandler {
 
 // ListDevices godoc
 // @Summary List user devices
-// @Description Get all devices for the authenticated user
+// @Description Get the devices of the authenticated user, optionally filtered, sorted and paginated. The total number of matching devices is returned in the X-Total-Count header.
 // @Tags devices
 // @Accept  json
 // @Produce  json
+// @Param q query string false "Substring search on name, description and SN"
+// @Param location query string false "Exact location filter"
+// @Param sort query string false "Sort by name, created_at or last_seen" default(created_at)
+// @Param order query string false "asc or desc" default(asc)
+// @Param limit query int false "Page size (max 500); all devices when omitted"
+// @Param offset query int false "Number of devices to skip"
+// @Param labels query string false "Label selector, e.g. env=prod,site!=rj,rack,!decommissioned"
 // @Success 200 {array} dto.DeviceResponse "List of devices"
+// @Header 200 {integer} X-Total-Count "Total number of matching devices"
+// @Failure 400 {object} dto.BadRequestErrorResponse "Invalid query parameters"
 // @Failure 401 {object} dto.DetailedErrorResponse "Unauthorized"
 // @Failure 500 {object} dto.InternalServerErrorResponse "Internal server error"
 // @Security ApiKeyAuth
@@ -50,7 +65,17 @@ func (h *DeviceHandler) ListDevices(c *gin.Context) {
 		return
 	}
 
-	devices, err := h.deviceService.ListDevices(uuidUserID)
+	var query dto.DeviceListQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, dto.DetailedErrorResponse{
+			Code:    dto.ErrorCodeInvalidRequest,
+			Message: "Invalid query parameters",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	devices, total, err := h.deviceService.SearchDevices(uuidUserID, query)
 	if err != nil {
 		if customErr, ok := err.(errors.CustomError); ok {
 			c.JSON(customErr.StatusCode(), dto.DetailedErrorResponse{
@@ -68,12 +93,13 @@ func (h *DeviceHandler) ListDevices(c *gin.Context) {
 		return
 	}
 
+	c.Header("X-Total-Count", strconv.FormatInt(total, 10))
 	c.JSON(http.StatusOK, devices)
 }
 
 // CreateDevice godoc
 // @Summary Create a new device
-// @Description Create a new device for the authenticated user
+// @Description Create a new device for the authenticated user, or for the organization of an organization token
 // @Tags devices
 // @Accept  json
 // @Produce  json
@@ -81,6 +107,7 @@ func (h *DeviceHandler) ListDevices(c *gin.Context) {
 // @Success 201 {object} dto.DeviceResponse "Created device"
 // @Failure 400 {object} dto.BadRequestErrorResponse "Invalid request body"
 // @Failure 401 {object} dto.DetailedErrorResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenErrorResponse "Admin role required in the organization"
 // @Failure 409 {object} dto.ConflictErrorResponse "Device already exists"
 // @Failure 500 {object} dto.InternalServerErrorResponse "Internal server error"
 // @Security ApiKeyAuth
@@ -116,7 +143,7 @@ func (h *DeviceHandler) CreateDevice(c *gin.Context) {
 		return
 	}
 
-	device, err := h.deviceService.CreateDevice(uuidUserID, req.Name, req.Location, req.SN, req.Description)
+	device, err := h.deviceService.CreateDevice(uuidUserID, activeOrganizationID(c), req.Name, req.Location, req.SN, req.Description)
 	if err != nil {
 		if customErr, ok := err.(errors.CustomError); ok {
 			c.JSON(customErr.StatusCode(), dto.DetailedErrorResponse{
@@ -284,7 +311,7 @@ func (h *DeviceHandler) UpdateDevice(c *gin.Context) {
 
 // DeleteDevice godoc
 // @Summary Delete a device
-// @Description Delete a specific device by ID
+// @Description Soft-delete a device. It is removed from the target lists of notification rules right away and can be restored until the restore window (DEVICE_RESTORE_WINDOW, 30 days by default) is over, after which it is purged together with its heartbeats. Its serial number stays taken until the purge.
 // @Tags devices
 // @Accept  json
 // @Produce  json
@@ -346,4 +373,717 @@ func (h *DeviceHandler) DeleteDevice(c *gin.Context) {
 	}
 
 	c.AbortWithStatus(http.StatusNoContent)
-}
\ No newline at end of file
+}
+
+// ListDeletedDevices godoc
+// @Summary List deleted devices
+// @Description List the deleted devices that can still be restored, most recently deleted first.
+// @Tags devices
+// @Accept  json
+// @Produce  json
+// @Success 200 {array} dto.DeviceResponse "Deleted devices"
+// @Failure 401 {object} dto.DetailedErrorResponse "Unauthorized"
+// @Failure 500 {object} dto.InternalServerErrorResponse "Internal server error"
+// @Security ApiKeyAuth
+// @Router /v1/devices/deleted [get]
+func (h *DeviceHandler) ListDeletedDevices(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, dto.DetailedErrorResponse{
+			Code:    dto.ErrorCodeInvalidCredentials,
+			Message: "Unauthorized",
+			Details: "User ID not found in context",
+		})
+		return
+	}
+
+	uuidUserID, ok := userID.(uuid.UUID)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, dto.DetailedErrorResponse{
+			Code:    dto.ErrorCodeInternalError,
+			Message: "Internal server error",
+			Details: "Invalid user ID type",
+		})
+		return
+	}
+
+	devices, err := h.deviceService.ListDeletedDevices(uuidUserID)
+	if err != nil {
+		if customErr, ok := err.(errors.CustomError); ok {
+			c.JSON(customErr.StatusCode(), dto.DetailedErrorResponse{
+				Code:    dto.ErrorCodeFromStatusCode(customErr.StatusCode()),
+				Message: customErr.Message(),
+				Details: "Failed to list deleted devices",
+			})
+		} else {
+			c.JSON(http.StatusInternalServerError, dto.DetailedErrorResponse{
+				Code:    dto.ErrorCodeInternalError,
+				Message: "Internal server error",
+				Details: err.Error(),
+			})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, devices)
+}
+
+// RestoreDevice godoc
+// @Summary Restore a deleted device
+// @Description Restore a device deleted within the restore window. The device does not rejoin the notification rules it was removed from.
+// @Tags devices
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Device ID"
+// @Success 200 {object} dto.DeviceResponse "Restored device"
+// @Failure 400 {object} dto.BadRequestErrorResponse "Invalid device ID"
+// @Failure 401 {object} dto.DetailedErrorResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenErrorResponse "Forbidden"
+// @Failure 404 {object} dto.DetailedErrorResponse "Deleted device not found"
+// @Failure 500 {object} dto.InternalServerErrorResponse "Internal server error"
+// @Security ApiKeyAuth
+// @Router /v1/devices/{id}/restore [post]
+func (h *DeviceHandler) RestoreDevice(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, dto.DetailedErrorResponse{
+			Code:    dto.ErrorCodeInvalidCredentials,
+			Message: "Unauthorized",
+			Details: "User ID not found in context",
+		})
+		return
+	}
+
+	uuidUserID, ok := userID.(uuid.UUID)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, dto.DetailedErrorResponse{
+			Code:    dto.ErrorCodeInternalError,
+			Message: "Internal server error",
+			Details: "Invalid user ID type",
+		})
+		return
+	}
+
+	deviceID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.DetailedErrorResponse{
+			Code:    dto.ErrorCodeInvalidRequest,
+			Message: "Invalid device ID",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	device, err := h.deviceService.RestoreDevice(uuidUserID, deviceID)
+	if err != nil {
+		if customErr, ok := err.(errors.CustomError); ok {
+			c.JSON(customErr.StatusCode(), dto.DetailedErrorResponse{
+				Code:    dto.ErrorCodeFromStatusCode(customErr.StatusCode()),
+				Message: customErr.Message(),
+				Details: "Failed to restore device",
+			})
+		} else {
+			c.JSON(http.StatusInternalServerError, dto.DetailedErrorResponse{
+				Code:    dto.ErrorCodeInternalError,
+				Message: "Internal server error",
+				Details: err.Error(),
+			})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, device)
+}
+
+// PurgeDevice godoc
+// @Summary Purge a deleted device
+// @Description Permanently remove a deleted device with its heartbeats and share grants before the end of its restore window, freeing its serial number.
+// @Tags devices
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Device ID"
+// @Success 204 "No content"
+// @Failure 400 {object} dto.BadRequestErrorResponse "Invalid device ID"
+// @Failure 401 {object} dto.DetailedErrorResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenErrorResponse "Forbidden"
+// @Failure 404 {object} dto.DetailedErrorResponse "Deleted device not found"
+// @Failure 500 {object} dto.InternalServerErrorResponse "Internal server error"
+// @Security ApiKeyAuth
+// @Router /v1/devices/{id}/purge [delete]
+func (h *DeviceHandler) PurgeDevice(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, dto.DetailedErrorResponse{
+			Code:    dto.ErrorCodeInvalidCredentials,
+			Message: "Unauthorized",
+			Details: "User ID not found in context",
+		})
+		return
+	}
+
+	uuidUserID, ok := userID.(uuid.UUID)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, dto.DetailedErrorResponse{
+			Code:    dto.ErrorCodeInternalError,
+			Message: "Internal server error",
+			Details: "Invalid user ID type",
+		})
+		return
+	}
+
+	deviceID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.DetailedErrorResponse{
+			Code:    dto.ErrorCodeInvalidRequest,
+			Message: "Invalid device ID",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	err = h.deviceService.PurgeDevice(uuidUserID, deviceID)
+	if err != nil {
+		if customErr, ok := err.(errors.CustomError); ok {
+			c.JSON(customErr.StatusCode(), dto.DetailedErrorResponse{
+				Code:    dto.ErrorCodeFromStatusCode(customErr.StatusCode()),
+				Message: customErr.Message(),
+				Details: "Failed to purge device",
+			})
+		} else {
+			c.JSON(http.StatusInternalServerError, dto.DetailedErrorResponse{
+				Code:    dto.ErrorCodeInternalError,
+				Message: "Internal server error",
+				Details: err.Error(),
+			})
+		}
+		return
+	}
+
+	c.AbortWithStatus(http.StatusNoContent)
+}
+
+// ImportDevices godoc
+// @Summary Bulk import devices
+// @Description Import devices from a JSON array or a CSV file (columns: name, location, sn, description). Every row goes through the same validation as device creation. In all_or_nothing mode one invalid row rejects the whole batch; in best_effort mode valid rows are created and invalid ones reported. A request holds at most 1000 rows and 2 MB.
+// @Tags devices
+// @Accept  json
+// @Accept  text/csv
+// @Produce  json
+// @Param request body []dto.CreateDeviceRequest true "Devices to import"
+// @Param mode query string false "all_or_nothing or best_effort" default(all_or_nothing)
+// @Param dry_run query bool false "Only validate, do not create anything"
+// @Success 200 {object} dto.DeviceImportResponse "Dry-run report"
+// @Success 201 {object} dto.DeviceImportResponse "Import report"
+// @Failure 400 {object} dto.BadRequestErrorResponse "Invalid request body or parameters"
+// @Failure 401 {object} dto.DetailedErrorResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenErrorResponse "Admin role required in the organization"
+// @Failure 422 {object} dto.DeviceImportResponse "No device could be imported"
+// @Failure 500 {object} dto.InternalServerErrorResponse "Internal server error"
+// @Security ApiKeyAuth
+// @Router /v1/devices/import [post]
+func (h *DeviceHandler) ImportDevices(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, dto.DetailedErrorResponse{
+			Code:    dto.ErrorCodeInvalidCredentials,
+			Message: "Unauthorized",
+			Details: "User ID not found in context",
+		})
+		return
+	}
+
+	uuidUserID, ok := userID.(uuid.UUID)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, dto.DetailedErrorResponse{
+			Code:    dto.ErrorCodeInternalError,
+			Message: "Internal server error",
+			Details: "Invalid user ID type",
+		})
+		return
+	}
+
+	dryRun := false
+	if value := c.Query("dry_run"); value != "" {
+		dryRun = value == "true" || value == "1"
+	}
+
+	var rows []dto.CreateDeviceRequest
+	var err error
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, services.MaxImportSize)
+	if strings.Contains(c.ContentType(), "csv") {
+		rows, err = parseDeviceImportCSV(c.Request.Body, services.MaxImportRows+1)
+	} else {
+		err = c.ShouldBindJSON(&rows)
+	}
+	if err != nil {
+		details := err.Error()
+		if _, ok := err.(*http.MaxBytesError); ok {
+			details = fmt.Sprintf("Request body must be at most %d MB", services.MaxImportSize>>20)
+		}
+		c.JSON(http.StatusBadRequest, dto.DetailedErrorResponse{
+			Code:    dto.ErrorCodeInvalidRequest,
+			Message: "Invalid request body",
+			Details: details,
+		})
+		return
+	}
+
+	report, err := h.deviceService.ImportDevices(uuidUserID, activeOrganizationID(c), rows, c.Query("mode"), dryRun)
+	if err != nil {
+		if customErr, ok := err.(errors.CustomError); ok {
+			c.JSON(customErr.StatusCode(), dto.DetailedErrorResponse{
+				Code:    dto.ErrorCodeFromStatusCode(customErr.StatusCode()),
+				Message: customErr.Message(),
+				Details: "Please check your input and try again",
+			})
+		} else {
+			c.JSON(http.StatusInternalServerError, dto.DetailedErrorResponse{
+				Code:    dto.ErrorCodeInternalError,
+				Message: "Internal server error",
+				Details: err.Error(),
+			})
+		}
+		return
+	}
+
+	switch {
+	case report.DryRun:
+		c.JSON(http.StatusOK, report)
+	case report.Created == 0 && report.Failed > 0:
+		c.JSON(http.StatusUnprocessableEntity, report)
+	default:
+		c.JSON(http.StatusCreated, report)
+	}
+}
+
+// ExportDevices godoc
+// @Summary Export devices
+// @Description Export all devices of the authenticated user as CSV (same columns accepted by the import) or JSON
+// @Tags devices
+// @Produce  json
+// @Produce  text/csv
+// @Param format query string false "Export format: csv or json" default(csv)
+// @Success 200 {array} dto.DeviceResponse "Devices"
+// @Failure 400 {object} dto.BadRequestErrorResponse "Invalid export format"
+// @Failure 401 {object} dto.DetailedErrorResponse "Unauthorized"
+// @Failure 500 {object} dto.InternalServerErrorResponse "Internal server error"
+// @Security ApiKeyAuth
+// @Router /v1/devices/export [get]
+func (h *DeviceHandler) ExportDevices(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, dto.DetailedErrorResponse{
+			Code:    dto.ErrorCodeInvalidCredentials,
+			Message: "Unauthorized",
+			Details: "User ID not found in context",
+		})
+		return
+	}
+
+	uuidUserID, ok := userID.(uuid.UUID)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, dto.DetailedErrorResponse{
+			Code:    dto.ErrorCodeInternalError,
+			Message: "Internal server error",
+			Details: "Invalid user ID type",
+		})
+		return
+	}
+
+	format := strings.ToLower(c.DefaultQuery("format", "csv"))
+	if format != "csv" && format != "json" {
+		c.JSON(http.StatusBadRequest, dto.DetailedErrorResponse{
+			Code:    dto.ErrorCodeInvalidRequest,
+			Message: "Invalid export format",
+			Details: "Supported formats are csv and json",
+		})
+		return
+	}
+
+	devices, err := h.deviceService.ListDevices(uuidUserID)
+	if err != nil {
+		if customErr, ok := err.(errors.CustomError); ok {
+			c.JSON(customErr.StatusCode(), dto.DetailedErrorResponse{
+				Code:    dto.ErrorCodeFromStatusCode(customErr.StatusCode()),
+				Message: customErr.Message(),
+				Details: "Failed to export devices",
+			})
+		} else {
+			c.JSON(http.StatusInternalServerError, dto.DetailedErrorResponse{
+				Code:    dto.ErrorCodeInternalError,
+				Message: "Internal server error",
+				Details: err.Error(),
+			})
+		}
+		return
+	}
+
+	if format == "json" {
+		c.Header("Content-Disposition", "attachment; filename=devices.json")
+		c.JSON(http.StatusOK, devices)
+		return
+	}
+
+	c.Header("Content-Type", "text/csv; charset=utf-8")
+	c.Header("Content-Disposition", "attachment; filename=devices.csv")
+	c.Status(http.StatusOK)
+
+	w := csv.NewWriter(c.Writer)
+	_ = w.Write([]string{"uuid", "name", "location", "sn", "description", "created_at"})
+	for _, device := range devices {
+		_ = w.Write([]string{
+			device.UUID.String(),
+			device.Name,
+			device.Location,
+			device.SN,
+			device.Description,
+			device.CreatedAt.UTC().Format(time.RFC3339),
+		})
+	}
+	w.Flush()
+}
+
+// parseDeviceImportCSV reads devices from a CSV with a header row. Columns
+// are matched by name, so extra columns such as uuid from an export are
+// ignored. Reading stops after maxRows rows.
+func parseDeviceImportCSV(r io.Reader, maxRows int) ([]dto.CreateDeviceRequest, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		if err == io.EOF {
+			return nil, fmt.Errorf("CSV body is empty")
+		}
+		return nil, err
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, required := range []string{"name", "location", "sn"} {
+		if _, ok := columns[required]; !ok {
+			return nil, fmt.Errorf("missing required column %q", required)
+		}
+	}
+
+	field := func(record []string, name string) string {
+		i, ok := columns[name]
+		if !ok || i >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[i])
+	}
+
+	reader.FieldsPerRecord = len(header)
+	var rows []dto.CreateDeviceRequest
+	for len(rows) < maxRows {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		rows = append(rows, dto.CreateDeviceRequest{
+			Name:        field(record, "name"),
+			Location:    field(record, "location"),
+			SN:          field(record, "sn"),
+			Description: field(record, "description"),
+		})
+	}
+	return rows, nil
+}
+
+// GetDeviceLabels godoc
+// @Summary Get device labels
+// @Description Get the key/value labels attached to a device
+// @Tags devices
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Device ID"
+// @Success 200 {object} dto.DeviceLabelsRequest "Device labels"
+// @Failure 400 {object} dto.BadRequestErrorResponse "Invalid device ID"
+// @Failure 401 {object} dto.DetailedErrorResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenErrorResponse "Forbidden"
+// @Failure 404 {object} dto.DetailedErrorResponse "Device not found"
+// @Failure 500 {object} dto.InternalServerErrorResponse "Internal server error"
+// @Security ApiKeyAuth
+// @Router /v1/devices/{id}/labels [get]
+func (h *DeviceHandler) GetDeviceLabels(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, dto.DetailedErrorResponse{
+			Code:    dto.ErrorCodeInvalidCredentials,
+			Message: "Unauthorized",
+			Details: "User ID not found in context",
+		})
+		return
+	}
+
+	uuidUserID, ok := userID.(uuid.UUID)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, dto.DetailedErrorResponse{
+			Code:    dto.ErrorCodeInternalError,
+			Message: "Internal server error",
+			Details: "Invalid user ID type",
+		})
+		return
+	}
+
+	deviceID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.DetailedErrorResponse{
+			Code:    dto.ErrorCodeInvalidRequest,
+			Message: "Invalid device ID",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	labels, err := h.deviceService.GetDeviceLabels(uuidUserID, deviceID)
+	if err != nil {
+		if customErr, ok := err.(errors.CustomError); ok {
+			c.JSON(customErr.StatusCode(), dto.DetailedErrorResponse{
+				Code:    dto.ErrorCodeFromStatusCode(customErr.StatusCode()),
+				Message: customErr.Message(),
+				Details: "Failed to get device labels",
+			})
+		} else {
+			c.JSON(http.StatusInternalServerError, dto.DetailedErrorResponse{
+				Code:    dto.ErrorCodeInternalError,
+				Message: "Internal server error",
+				Details: err.Error(),
+			})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.DeviceLabelsRequest{Labels: labels})
+}
+
+// ReplaceDeviceLabels godoc
+// @Summary Replace device labels
+// @Description Replace the whole label set of a device. Keys and values use up to 63 letters, digits, '.', '_' or '-' (keys may also contain '/'); at most 32 labels per device.
+// @Tags devices
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Device ID"
+// @Param request body dto.DeviceLabelsRequest true "New labels"
+// @Success 200 {object} dto.DeviceLabelsRequest "Device labels"
+// @Failure 400 {object} dto.BadRequestErrorResponse "Invalid device ID or labels"
+// @Failure 401 {object} dto.DetailedErrorResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenErrorResponse "Forbidden"
+// @Failure 404 {object} dto.DetailedErrorResponse "Device not found"
+// @Failure 500 {object} dto.InternalServerErrorResponse "Internal server error"
+// @Security ApiKeyAuth
+// @Router /v1/devices/{id}/labels [put]
+func (h *DeviceHandler) ReplaceDeviceLabels(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, dto.DetailedErrorResponse{
+			Code:    dto.ErrorCodeInvalidCredentials,
+			Message: "Unauthorized",
+			Details: "User ID not found in context",
+		})
+		return
+	}
+
+	uuidUserID, ok := userID.(uuid.UUID)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, dto.DetailedErrorResponse{
+			Code:    dto.ErrorCodeInternalError,
+			Message: "Internal server error",
+			Details: "Invalid user ID type",
+		})
+		return
+	}
+
+	deviceID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.DetailedErrorResponse{
+			Code:    dto.ErrorCodeInvalidRequest,
+			Message: "Invalid device ID",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	var req dto.DeviceLabelsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.DetailedErrorResponse{
+			Code:    dto.ErrorCodeInvalidRequest,
+			Message: "Invalid request format",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	labels, err := h.deviceService.ReplaceDeviceLabels(uuidUserID, deviceID, req.Labels)
+	if err != nil {
+		if customErr, ok := err.(errors.CustomError); ok {
+			c.JSON(customErr.StatusCode(), dto.DetailedErrorResponse{
+				Code:    dto.ErrorCodeFromStatusCode(customErr.StatusCode()),
+				Message: customErr.Message(),
+				Details: "Failed to update device labels",
+			})
+		} else {
+			c.JSON(http.StatusInternalServerError, dto.DetailedErrorResponse{
+				Code:    dto.ErrorCodeInternalError,
+				Message: "Internal server error",
+				Details: err.Error(),
+			})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.DeviceLabelsRequest{Labels: labels})
+}
+
+// SetDeviceLabel godoc
+// @Summary Set a device label
+// @Description Add a label to a device or change the value of an existing one
+// @Tags devices
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Device ID"
+// @Param key path string true "Label key"
+// @Param request body dto.DeviceLabelRequest true "Label value"
+// @Success 200 {object} dto.DeviceLabelsRequest "Device labels"
+// @Failure 400 {object} dto.BadRequestErrorResponse "Invalid device ID or label"
+// @Failure 401 {object} dto.DetailedErrorResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenErrorResponse "Forbidden"
+// @Failure 404 {object} dto.DetailedErrorResponse "Device not found"
+// @Failure 500 {object} dto.InternalServerErrorResponse "Internal server error"
+// @Security ApiKeyAuth
+// @Router /v1/devices/{id}/labels/{key} [put]
+func (h *DeviceHandler) SetDeviceLabel(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, dto.DetailedErrorResponse{
+			Code:    dto.ErrorCodeInvalidCredentials,
+			Message: "Unauthorized",
+			Details: "User ID not found in context",
+		})
+		return
+	}
+
+	uuidUserID, ok := userID.(uuid.UUID)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, dto.DetailedErrorResponse{
+			Code:    dto.ErrorCodeInternalError,
+			Message: "Internal server error",
+			Details: "Invalid user ID type",
+		})
+		return
+	}
+
+	deviceID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.DetailedErrorResponse{
+			Code:    dto.ErrorCodeInvalidRequest,
+			Message: "Invalid device ID",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	var req dto.DeviceLabelRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.DetailedErrorResponse{
+			Code:    dto.ErrorCodeInvalidRequest,
+			Message: "Invalid request format",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	labels, err := h.deviceService.SetDeviceLabel(uuidUserID, deviceID, c.Param("key"), req.Value)
+	if err != nil {
+		if customErr, ok := err.(errors.CustomError); ok {
+			c.JSON(customErr.StatusCode(), dto.DetailedErrorResponse{
+				Code:    dto.ErrorCodeFromStatusCode(customErr.StatusCode()),
+				Message: customErr.Message(),
+				Details: "Failed to set device label",
+			})
+		} else {
+			c.JSON(http.StatusInternalServerError, dto.DetailedErrorResponse{
+				Code:    dto.ErrorCodeInternalError,
+				Message: "Internal server error",
+				Details: err.Error(),
+			})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.DeviceLabelsRequest{Labels: labels})
+}
+
+// DeleteDeviceLabel godoc
+// @Summary Delete a device label
+// @Description Remove a label from a device
+// @Tags devices
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Device ID"
+// @Param key path string true "Label key"
+// @Success 200 {object} dto.DeviceLabelsRequest "Remaining device labels"
+// @Failure 400 {object} dto.BadRequestErrorResponse "Invalid device ID"
+// @Failure 401 {object} dto.DetailedErrorResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenErrorResponse "Forbidden"
+// @Failure 404 {object} dto.DetailedErrorResponse "Device or label not found"
+// @Failure 500 {object} dto.InternalServerErrorResponse "Internal server error"
+// @Security ApiKeyAuth
+// @Router /v1/devices/{id}/labels/{key} [delete]
+func (h *DeviceHandler) DeleteDeviceLabel(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, dto.DetailedErrorResponse{
+			Code:    dto.ErrorCodeInvalidCredentials,
+			Message: "Unauthorized",
+			Details: "User ID not found in context",
+		})
+		return
+	}
+
+	uuidUserID, ok := userID.(uuid.UUID)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, dto.DetailedErrorResponse{
+			Code:    dto.ErrorCodeInternalError,
+			Message: "Internal server error",
+			Details: "Invalid user ID type",
+		})
+		return
+	}
+
+	deviceID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.DetailedErrorResponse{
+			Code:    dto.ErrorCodeInvalidRequest,
+			Message: "Invalid device ID",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	labels, err := h.deviceService.DeleteDeviceLabel(uuidUserID, deviceID, c.Param("key"))
+	if err != nil {
+		if customErr, ok := err.(errors.CustomError); ok {
+			c.JSON(customErr.StatusCode(), dto.DetailedErrorResponse{
+				Code:    dto.ErrorCodeFromStatusCode(customErr.StatusCode()),
+				Message: customErr.Message(),
+				Details: "Failed to delete device label",
+			})
+		} else {
+			c.JSON(http.StatusInternalServerError, dto.DetailedErrorResponse{
+				Code:    dto.ErrorCodeInternalError,
+				Message: "Internal server error",
+				Details: err.Error(),
+			})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.DeviceLabelsRequest{Labels: labels})
+}
@@ -2,13 +2,17 @@ package handlers
 
 import (
 	"bytes"
+	"encoding/csv"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/dto"
 	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/models"
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/services"
 	custom_errors "github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/utils/errors"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -20,8 +24,8 @@ type MockDeviceService struct {
 	mock.Mock
 }
 
-func (m *MockDeviceService) CreateDevice(userID uuid.UUID, name, location, sn, description string) (*models.Device, error) {
-	args := m.Called(userID, name, location, sn, description)
+func (m *MockDeviceService) CreateDevice(userID uuid.UUID, orgID *uuid.UUID, name, location, sn, description string) (*models.Device, error) {
+	args := m.Called(userID, orgID, name, location, sn, description)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
@@ -44,6 +48,14 @@ func (m *MockDeviceService) ListDevices(userID uuid.UUID) ([]models.Device, erro
 	return args.Get(0).([]models.Device), args.Error(1)
 }
 
+func (m *MockDeviceService) SearchDevices(userID uuid.UUID, query dto.DeviceListQuery) ([]models.Device, int64, error) {
+	args := m.Called(userID, query)
+	if args.Get(0) == nil {
+		return nil, 0, args.Error(2)
+	}
+	return args.Get(0).([]models.Device), args.Get(1).(int64), args.Error(2)
+}
+
 func (m *MockDeviceService) UpdateDevice(userID, deviceID uuid.UUID, name, location, description string) (*models.Device, error) {
 	args := m.Called(userID, deviceID, name, location, description)
 	if args.Get(0) == nil {
@@ -57,6 +69,80 @@ func (m *MockDeviceService) DeleteDevice(userID, deviceID uuid.UUID) error {
 	return args.Error(0)
 }
 
+func (m *MockDeviceService) ListDeletedDevices(userID uuid.UUID) ([]models.Device, error) {
+	args := m.Called(userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Device), args.Error(1)
+}
+
+func (m *MockDeviceService) RestoreDevice(userID, deviceID uuid.UUID) (*models.Device, error) {
+	args := m.Called(userID, deviceID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Device), args.Error(1)
+}
+
+func (m *MockDeviceService) PurgeDevice(userID, deviceID uuid.UUID) error {
+	args := m.Called(userID, deviceID)
+	return args.Error(0)
+}
+
+func (m *MockDeviceService) PurgeDeletedDevices() (int, error) {
+	args := m.Called()
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockDeviceService) ImportDevices(userID uuid.UUID, orgID *uuid.UUID, rows []dto.CreateDeviceRequest, mode string, dryRun bool) (*dto.DeviceImportResponse, error) {
+	args := m.Called(userID, orgID, rows, mode, dryRun)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dto.DeviceImportResponse), args.Error(1)
+}
+
+func (m *MockDeviceService) GetDeviceLabels(userID, deviceID uuid.UUID) (models.Labels, error) {
+	args := m.Called(userID, deviceID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(models.Labels), args.Error(1)
+}
+
+func (m *MockDeviceService) ReplaceDeviceLabels(userID, deviceID uuid.UUID, labels map[string]string) (models.Labels, error) {
+	args := m.Called(userID, deviceID, labels)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(models.Labels), args.Error(1)
+}
+
+func (m *MockDeviceService) SetDeviceLabel(userID, deviceID uuid.UUID, key, value string) (models.Labels, error) {
+	args := m.Called(userID, deviceID, key, value)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(models.Labels), args.Error(1)
+}
+
+func (m *MockDeviceService) DeleteDeviceLabel(userID, deviceID uuid.UUID, key string) (models.Labels, error) {
+	args := m.Called(userID, deviceID, key)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(models.Labels), args.Error(1)
+}
+
+func (m *MockDeviceService) ListDevicesByGroup(userID, groupID uuid.UUID, recursive bool) ([]models.Device, error) {
+	args := m.Called(userID, groupID, recursive)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Device), args.Error(1)
+}
+
 func TestDeviceHandler_ListDevices(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
@@ -71,15 +157,17 @@ func TestDeviceHandler_ListDevices(t *testing.T) {
 			{UUID: uuid.New(), Name: "Device 2", UserID: userID},
 		}
 
-		mockDeviceService.On("ListDevices", userID).Return(devices, nil)
+		mockDeviceService.On("SearchDevices", userID, dto.DeviceListQuery{}).Return(devices, int64(2), nil)
 
 		w := httptest.NewRecorder()
 		c, _ := gin.CreateTestContext(w)
 		c.Set("userID", userID)
+		c.Request, _ = http.NewRequest("GET", "/devices", nil)
 
 		handler.ListDevices(c)
 
 		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "2", w.Header().Get("X-Total-Count"))
 
 		var response []models.Device
 		json.Unmarshal(w.Body.Bytes(), &response)
@@ -88,6 +176,74 @@ func TestDeviceHandler_ListDevices(t *testing.T) {
 		mockDeviceService.AssertExpectations(t)
 	})
 
+	t.Run("Success - Query parameters forwarded", func(t *testing.T) {
+		mockDeviceService := new(MockDeviceService)
+		handler := NewDeviceHandler(mockDeviceService)
+
+		devices := []models.Device{
+			{UUID: uuid.New(), Name: "Gateway", Location: "SP", UserID: userID},
+		}
+		query := dto.DeviceListQuery{
+			Search:   "gate",
+			Location: "SP",
+			Sort:     "last_seen",
+			Order:    "desc",
+			Limit:    10,
+			Offset:   20,
+		}
+
+		mockDeviceService.On("SearchDevices", userID, query).Return(devices, int64(21), nil)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Set("userID", userID)
+		c.Request, _ = http.NewRequest("GET", "/devices?q=gate&location=SP&sort=last_seen&order=desc&limit=10&offset=20", nil)
+
+		handler.ListDevices(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "21", w.Header().Get("X-Total-Count"))
+
+		var response []models.Device
+		json.Unmarshal(w.Body.Bytes(), &response)
+
+		assert.Len(t, response, 1)
+		mockDeviceService.AssertExpectations(t)
+	})
+
+	t.Run("Error - Invalid limit", func(t *testing.T) {
+		mockDeviceService := new(MockDeviceService)
+		handler := NewDeviceHandler(mockDeviceService)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Set("userID", userID)
+		c.Request, _ = http.NewRequest("GET", "/devices?limit=abc", nil)
+
+		handler.ListDevices(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		mockDeviceService.AssertNotCalled(t, "SearchDevices", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Error - Invalid sort", func(t *testing.T) {
+		mockDeviceService := new(MockDeviceService)
+		handler := NewDeviceHandler(mockDeviceService)
+
+		query := dto.DeviceListQuery{Sort: "sn"}
+		mockDeviceService.On("SearchDevices", userID, query).Return(nil, int64(0), custom_errors.NewValidationError("sort must be one of name, created_at, last_seen"))
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Set("userID", userID)
+		c.Request, _ = http.NewRequest("GET", "/devices?sort=sn", nil)
+
+		handler.ListDevices(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		mockDeviceService.AssertExpectations(t)
+	})
+
 	t.Run("Error - User ID not found in context", func(t *testing.T) {
 		mockDeviceService := new(MockDeviceService)
 		handler := NewDeviceHandler(mockDeviceService)
@@ -109,11 +265,12 @@ func TestDeviceHandler_ListDevices(t *testing.T) {
 		mockDeviceService := new(MockDeviceService)
 		handler := NewDeviceHandler(mockDeviceService)
 
-		mockDeviceService.On("ListDevices", userID).Return(([]models.Device)(nil), custom_errors.ErrDatabaseError)
+		mockDeviceService.On("SearchDevices", userID, dto.DeviceListQuery{}).Return(nil, int64(0), custom_errors.ErrDatabaseError)
 
 		w := httptest.NewRecorder()
 		c, _ := gin.CreateTestContext(w)
 		c.Set("userID", userID)
+		c.Request, _ = http.NewRequest("GET", "/devices", nil)
 
 		handler.ListDevices(c)
 
@@ -154,7 +311,7 @@ func TestDeviceHandler_CreateDevice(t *testing.T) {
 			Description: "Test Description",
 		}
 
-		mockDeviceService.On("CreateDevice", userID, createReq.Name, createReq.Location, createReq.SN, createReq.Description).Return(device, nil)
+		mockDeviceService.On("CreateDevice", userID, (*uuid.UUID)(nil), createReq.Name, createReq.Location, createReq.SN, createReq.Description).Return(device, nil)
 
 		jsonData, _ := json.Marshal(createReq)
 
@@ -207,7 +364,7 @@ func TestDeviceHandler_CreateDevice(t *testing.T) {
 			Description: "Test Description",
 		}
 
-		mockDeviceService.On("CreateDevice", userID, createReq.Name, createReq.Location, createReq.SN, createReq.Description).Return((*models.Device)(nil), custom_errors.ErrDeviceAlreadyExists)
+		mockDeviceService.On("CreateDevice", userID, (*uuid.UUID)(nil), createReq.Name, createReq.Location, createReq.SN, createReq.Description).Return((*models.Device)(nil), custom_errors.ErrDeviceAlreadyExists)
 
 		jsonData, _ := json.Marshal(createReq)
 
@@ -477,4 +634,433 @@ func TestDeviceHandler_DeleteDevice(t *testing.T) {
 
 		assert.Equal(t, "Unauthorized", response.Message)
 	})
-}
\ No newline at end of file
+}
+
+func TestDeviceHandler_ImportDevices(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	userID := uuid.New()
+	rows := []dto.CreateDeviceRequest{
+		{Name: "Device 1", Location: "SP", SN: "123456789012", Description: "Rack 1"},
+		{Name: "Device 2", Location: "RJ", SN: "123456789013"},
+	}
+
+	t.Run("Success - JSON import", func(t *testing.T) {
+		mockDeviceService := new(MockDeviceService)
+		handler := NewDeviceHandler(mockDeviceService)
+
+		report := &dto.DeviceImportResponse{Mode: "all_or_nothing", Total: 2, Valid: 2, Created: 2}
+		mockDeviceService.On("ImportDevices", userID, (*uuid.UUID)(nil), rows, "", false).Return(report, nil)
+
+		body, _ := json.Marshal(rows)
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Set("userID", userID)
+		c.Request, _ = http.NewRequest("POST", "/devices/import", bytes.NewBuffer(body))
+		c.Request.Header.Set("Content-Type", "application/json")
+
+		handler.ImportDevices(c)
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+
+		var response dto.DeviceImportResponse
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, response.Created)
+		mockDeviceService.AssertExpectations(t)
+	})
+
+	t.Run("Success - CSV dry run", func(t *testing.T) {
+		mockDeviceService := new(MockDeviceService)
+		handler := NewDeviceHandler(mockDeviceService)
+
+		report := &dto.DeviceImportResponse{DryRun: true, Mode: "best_effort", Total: 2, Valid: 2}
+		mockDeviceService.On("ImportDevices", userID, (*uuid.UUID)(nil), rows, "best_effort", true).Return(report, nil)
+
+		body := "name,location,sn,description\nDevice 1,SP,123456789012,Rack 1\nDevice 2,RJ,123456789013,\n"
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Set("userID", userID)
+		c.Request, _ = http.NewRequest("POST", "/devices/import?mode=best_effort&dry_run=true", strings.NewReader(body))
+		c.Request.Header.Set("Content-Type", "text/csv")
+
+		handler.ImportDevices(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		mockDeviceService.AssertExpectations(t)
+	})
+
+	t.Run("Rejected batch returns 422 with the report", func(t *testing.T) {
+		mockDeviceService := new(MockDeviceService)
+		handler := NewDeviceHandler(mockDeviceService)
+
+		report := &dto.DeviceImportResponse{
+			Mode:   "all_or_nothing",
+			Total:  2,
+			Valid:  1,
+			Failed: 1,
+			Results: []dto.DeviceImportResult{
+				{Row: 1, SN: "123456789012", Status: "skipped"},
+				{Row: 2, SN: "123456789013", Status: "failed", Error: "device with this serial number already exists"},
+			},
+		}
+		mockDeviceService.On("ImportDevices", userID, (*uuid.UUID)(nil), rows, "", false).Return(report, nil)
+
+		body, _ := json.Marshal(rows)
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Set("userID", userID)
+		c.Request, _ = http.NewRequest("POST", "/devices/import", bytes.NewBuffer(body))
+		c.Request.Header.Set("Content-Type", "application/json")
+
+		handler.ImportDevices(c)
+
+		assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+
+		var response dto.DeviceImportResponse
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.Equal(t, "failed", response.Results[1].Status)
+	})
+
+	t.Run("Error - CSV missing required column", func(t *testing.T) {
+		mockDeviceService := new(MockDeviceService)
+		handler := NewDeviceHandler(mockDeviceService)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Set("userID", userID)
+		c.Request, _ = http.NewRequest("POST", "/devices/import", strings.NewReader("name,location\nDevice,SP\n"))
+		c.Request.Header.Set("Content-Type", "text/csv")
+
+		handler.ImportDevices(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		var response dto.DetailedErrorResponse
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.Equal(t, `missing required column "sn"`, response.Details)
+	})
+
+	t.Run("Error - CSV stops reading one row past the limit", func(t *testing.T) {
+		mockDeviceService := new(MockDeviceService)
+		handler := NewDeviceHandler(mockDeviceService)
+
+		mockDeviceService.On("ImportDevices", userID, (*uuid.UUID)(nil), mock.MatchedBy(func(rows []dto.CreateDeviceRequest) bool {
+			return len(rows) == services.MaxImportRows+1
+		}), "", false).Return(nil, custom_errors.NewValidationError("A single import accepts at most 1000 devices"))
+
+		var body strings.Builder
+		body.WriteString("name,location,sn\n")
+		for i := 0; i < services.MaxImportRows+10; i++ {
+			fmt.Fprintf(&body, "Device %d,SP,%012d\n", i, i)
+		}
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Set("userID", userID)
+		c.Request, _ = http.NewRequest("POST", "/devices/import", strings.NewReader(body.String()))
+		c.Request.Header.Set("Content-Type", "text/csv")
+
+		handler.ImportDevices(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		mockDeviceService.AssertExpectations(t)
+	})
+
+	t.Run("Error - Body larger than the limit", func(t *testing.T) {
+		mockDeviceService := new(MockDeviceService)
+		handler := NewDeviceHandler(mockDeviceService)
+
+		body := `[{"name":"` + strings.Repeat("x", services.MaxImportSize) + `"}]`
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Set("userID", userID)
+		c.Request, _ = http.NewRequest("POST", "/devices/import", strings.NewReader(body))
+		c.Request.Header.Set("Content-Type", "application/json")
+
+		handler.ImportDevices(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		var response dto.DetailedErrorResponse
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.Equal(t, "Request body must be at most 2 MB", response.Details)
+		mockDeviceService.AssertNotCalled(t, "ImportDevices", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Error - Validation error from service", func(t *testing.T) {
+		mockDeviceService := new(MockDeviceService)
+		handler := NewDeviceHandler(mockDeviceService)
+
+		mockDeviceService.On("ImportDevices", userID, (*uuid.UUID)(nil), rows, "partial", false).
+			Return(nil, custom_errors.NewValidationError("Import mode must be all_or_nothing or best_effort"))
+
+		body, _ := json.Marshal(rows)
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Set("userID", userID)
+		c.Request, _ = http.NewRequest("POST", "/devices/import?mode=partial", bytes.NewBuffer(body))
+		c.Request.Header.Set("Content-Type", "application/json")
+
+		handler.ImportDevices(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestDeviceHandler_ExportDevices(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	userID := uuid.New()
+	devices := []models.Device{
+		{UUID: uuid.New(), Name: "Device 1", Location: "SP", SN: "123456789012", UserID: userID},
+	}
+
+	t.Run("Success - CSV export", func(t *testing.T) {
+		mockDeviceService := new(MockDeviceService)
+		handler := NewDeviceHandler(mockDeviceService)
+
+		mockDeviceService.On("ListDevices", userID).Return(devices, nil)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Set("userID", userID)
+		c.Request, _ = http.NewRequest("GET", "/devices/export", nil)
+
+		handler.ExportDevices(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		records, err := csv.NewReader(w.Body).ReadAll()
+		assert.NoError(t, err)
+		assert.Len(t, records, 2)
+		assert.Equal(t, []string{"uuid", "name", "location", "sn", "description", "created_at"}, records[0])
+		assert.Equal(t, "123456789012", records[1][3])
+	})
+
+	t.Run("Success - JSON export", func(t *testing.T) {
+		mockDeviceService := new(MockDeviceService)
+		handler := NewDeviceHandler(mockDeviceService)
+
+		mockDeviceService.On("ListDevices", userID).Return(devices, nil)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Set("userID", userID)
+		c.Request, _ = http.NewRequest("GET", "/devices/export?format=json", nil)
+
+		handler.ExportDevices(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response []models.Device
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.Len(t, response, 1)
+	})
+
+	t.Run("Error - Invalid format", func(t *testing.T) {
+		mockDeviceService := new(MockDeviceService)
+		handler := NewDeviceHandler(mockDeviceService)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Set("userID", userID)
+		c.Request, _ = http.NewRequest("GET", "/devices/export?format=xml", nil)
+
+		handler.ExportDevices(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestDeviceHandler_DeviceLabels(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	userID := uuid.New()
+	deviceID := uuid.New()
+
+	t.Run("Success - Get labels", func(t *testing.T) {
+		mockDeviceService := new(MockDeviceService)
+		handler := NewDeviceHandler(mockDeviceService)
+
+		mockDeviceService.On("GetDeviceLabels", userID, deviceID).Return(models.Labels{"env": "prod"}, nil)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Set("userID", userID)
+		c.Params = gin.Params{{Key: "id", Value: deviceID.String()}}
+
+		handler.GetDeviceLabels(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response dto.DeviceLabelsRequest
+		json.Unmarshal(w.Body.Bytes(), &response)
+
+		assert.Equal(t, map[string]string{"env": "prod"}, response.Labels)
+		mockDeviceService.AssertExpectations(t)
+	})
+
+	t.Run("Success - Replace labels", func(t *testing.T) {
+		mockDeviceService := new(MockDeviceService)
+		handler := NewDeviceHandler(mockDeviceService)
+
+		labels := map[string]string{"env": "prod", "site": "sp"}
+		mockDeviceService.On("ReplaceDeviceLabels", userID, deviceID, labels).Return(models.Labels(labels), nil)
+
+		body, _ := json.Marshal(dto.DeviceLabelsRequest{Labels: labels})
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Set("userID", userID)
+		c.Params = gin.Params{{Key: "id", Value: deviceID.String()}}
+		c.Request, _ = http.NewRequest("PUT", "/devices/"+deviceID.String()+"/labels", bytes.NewBuffer(body))
+		c.Request.Header.Set("Content-Type", "application/json")
+
+		handler.ReplaceDeviceLabels(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		mockDeviceService.AssertExpectations(t)
+	})
+
+	t.Run("Success - Set label", func(t *testing.T) {
+		mockDeviceService := new(MockDeviceService)
+		handler := NewDeviceHandler(mockDeviceService)
+
+		mockDeviceService.On("SetDeviceLabel", userID, deviceID, "site", "sp").Return(models.Labels{"site": "sp"}, nil)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Set("userID", userID)
+		c.Params = gin.Params{{Key: "id", Value: deviceID.String()}, {Key: "key", Value: "site"}}
+		c.Request, _ = http.NewRequest("PUT", "/devices/"+deviceID.String()+"/labels/site", bytes.NewBufferString(`{"value":"sp"}`))
+		c.Request.Header.Set("Content-Type", "application/json")
+
+		handler.SetDeviceLabel(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		mockDeviceService.AssertExpectations(t)
+	})
+
+	t.Run("Error - Invalid label", func(t *testing.T) {
+		mockDeviceService := new(MockDeviceService)
+		handler := NewDeviceHandler(mockDeviceService)
+
+		mockDeviceService.On("SetDeviceLabel", userID, deviceID, "bad key", "x").Return(nil, custom_errors.NewValidationError("invalid label key"))
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Set("userID", userID)
+		c.Params = gin.Params{{Key: "id", Value: deviceID.String()}, {Key: "key", Value: "bad key"}}
+		c.Request, _ = http.NewRequest("PUT", "/devices/"+deviceID.String()+"/labels/bad%20key", bytes.NewBufferString(`{"value":"x"}`))
+		c.Request.Header.Set("Content-Type", "application/json")
+
+		handler.SetDeviceLabel(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		mockDeviceService.AssertExpectations(t)
+	})
+
+	t.Run("Error - Delete unknown label", func(t *testing.T) {
+		mockDeviceService := new(MockDeviceService)
+		handler := NewDeviceHandler(mockDeviceService)
+
+		mockDeviceService.On("DeleteDeviceLabel", userID, deviceID, "site").Return(nil, custom_errors.ErrLabelNotFound)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Set("userID", userID)
+		c.Params = gin.Params{{Key: "id", Value: deviceID.String()}, {Key: "key", Value: "site"}}
+
+		handler.DeleteDeviceLabel(c)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		mockDeviceService.AssertExpectations(t)
+	})
+
+	t.Run("Error - Invalid device ID", func(t *testing.T) {
+		mockDeviceService := new(MockDeviceService)
+		handler := NewDeviceHandler(mockDeviceService)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Set("userID", userID)
+		c.Params = gin.Params{{Key: "id", Value: "not-a-uuid"}}
+
+		handler.GetDeviceLabels(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestDeviceHandler_RestoreDevice(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	userID := uuid.New()
+	deviceID := uuid.New()
+
+	t.Run("Success - Restore device", func(t *testing.T) {
+		mockService := new(MockDeviceService)
+		handler := NewDeviceHandler(mockService)
+
+		mockService.On("RestoreDevice", userID, deviceID).Return(&models.Device{UUID: deviceID, Name: "Device 1", UserID: userID}, nil)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Set("userID", userID)
+		c.Params = gin.Params{{Key: "id", Value: deviceID.String()}}
+
+		handler.RestoreDevice(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response models.Device
+		json.Unmarshal(w.Body.Bytes(), &response)
+
+		assert.Equal(t, deviceID, response.UUID)
+		assert.False(t, response.DeletedAt.Valid)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("Error - Deleted device not found", func(t *testing.T) {
+		mockService := new(MockDeviceService)
+		handler := NewDeviceHandler(mockService)
+
+		mockService.On("RestoreDevice", userID, deviceID).Return(nil, custom_errors.ErrDeviceNotFound)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Set("userID", userID)
+		c.Params = gin.Params{{Key: "id", Value: deviceID.String()}}
+
+		handler.RestoreDevice(c)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
+
+func TestDeviceHandler_PurgeDevice(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	userID := uuid.New()
+	deviceID := uuid.New()
+
+	t.Run("Success - Purge device", func(t *testing.T) {
+		mockService := new(MockDeviceService)
+		handler := NewDeviceHandler(mockService)
+
+		mockService.On("PurgeDevice", userID, deviceID).Return(nil)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Set("userID", userID)
+		c.Params = gin.Params{{Key: "id", Value: deviceID.String()}}
+
+		handler.PurgeDevice(c)
+
+		assert.Equal(t, http.StatusNoContent, c.Writer.Status())
+		mockService.AssertExpectations(t)
+	})
+}
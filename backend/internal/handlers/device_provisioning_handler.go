@@ -0,0 +1,250 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/dto"
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/services"
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/utils/errors"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type DeviceProvisioningHandler struct {
+	provisioningService services.DeviceProvisioningService
+}
+
+func NewDeviceProvisioningHandler(provisioningService services.DeviceProvisioningService) *DeviceProvisioningHandler {
+	return &DeviceProvisioningHandler{provisioningService: provisioningService}
+}
+
+// RegisterDevices godoc
+// @Summary Pre-register devices
+// @Description Pre-register serial numbers for zero-touch provisioning and get one one-time claim code per device. Codes are only returned here. Registrations belong to the caller or the active organization (admin role), unless unassigned is set, in which case users claim them by serial number.
+// @Tags provisioning
+// @Accept  json
+// @Produce  json
+// @Param request body dto.DeviceRegistrationRequest true "Devices to register"
+// @Success 201 {array} dto.DeviceRegistrationResponse "Registrations with their claim codes"
+// @Failure 400 {object} dto.BadRequestErrorResponse "Invalid input"
+// @Failure 401 {object} dto.DetailedErrorResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenErrorResponse "Forbidden"
+// @Failure 500 {object} dto.InternalServerErrorResponse "Internal server error"
+// @Security ApiKeyAuth
+// @Router /v1/provisioning/registrations [post]
+func (h *DeviceProvisioningHandler) RegisterDevices(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, dto.DetailedErrorResponse{
+			Code:    dto.ErrorCodeInvalidCredentials,
+			Message: "Unauthorized",
+			Details: "User ID not found in context",
+		})
+		return
+	}
+
+	uuidUserID, ok := userID.(uuid.UUID)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, dto.DetailedErrorResponse{
+			Code:    dto.ErrorCodeInternalError,
+			Message: "Internal server error",
+			Details: "Invalid user ID type",
+		})
+		return
+	}
+
+	var req dto.DeviceRegistrationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.DetailedErrorResponse{
+			Code:    dto.ErrorCodeInvalidRequest,
+			Message: "Invalid request format",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	registrations, err := h.provisioningService.RegisterDevices(uuidUserID, activeOrganizationID(c), req)
+	if err != nil {
+		if customErr, ok := err.(errors.CustomError); ok {
+			c.JSON(customErr.StatusCode(), dto.DetailedErrorResponse{
+				Code:    dto.ErrorCodeFromStatusCode(customErr.StatusCode()),
+				Message: customErr.Message(),
+				Details: "Please check your input and try again",
+			})
+		} else {
+			c.JSON(http.StatusInternalServerError, dto.DetailedErrorResponse{
+				Code:    dto.ErrorCodeInternalError,
+				Message: "Internal server error",
+				Details: err.Error(),
+			})
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, registrations)
+}
+
+// ListDeviceRegistrations godoc
+// @Summary List device registrations
+// @Description List the serial numbers registered by the user or assigned to the user or their organizations, with their provisioning status
+// @Tags provisioning
+// @Accept  json
+// @Produce  json
+// @Success 200 {array} dto.DeviceRegistrationResponse "Registrations"
+// @Failure 401 {object} dto.DetailedErrorResponse "Unauthorized"
+// @Failure 500 {object} dto.InternalServerErrorResponse "Internal server error"
+// @Security ApiKeyAuth
+// @Router /v1/provisioning/registrations [get]
+func (h *DeviceProvisioningHandler) ListDeviceRegistrations(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, dto.DetailedErrorResponse{
+			Code:    dto.ErrorCodeInvalidCredentials,
+			Message: "Unauthorized",
+			Details: "User ID not found in context",
+		})
+		return
+	}
+
+	uuidUserID, ok := userID.(uuid.UUID)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, dto.DetailedErrorResponse{
+			Code:    dto.ErrorCodeInternalError,
+			Message: "Internal server error",
+			Details: "Invalid user ID type",
+		})
+		return
+	}
+
+	registrations, err := h.provisioningService.ListRegistrations(uuidUserID)
+	if err != nil {
+		if customErr, ok := err.(errors.CustomError); ok {
+			c.JSON(customErr.StatusCode(), dto.DetailedErrorResponse{
+				Code:    dto.ErrorCodeFromStatusCode(customErr.StatusCode()),
+				Message: customErr.Message(),
+				Details: "Failed to list registrations",
+			})
+		} else {
+			c.JSON(http.StatusInternalServerError, dto.DetailedErrorResponse{
+				Code:    dto.ErrorCodeInternalError,
+				Message: "Internal server error",
+				Details: err.Error(),
+			})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, registrations)
+}
+
+// ProvisionDevice godoc
+// @Summary Provision a device
+// @Description Called by the device on first boot with its serial number and claim code. Spends the claim code and returns the device UUID and a device token, which is shown only once. Does not require authentication.
+// @Tags provisioning
+// @Accept  json
+// @Produce  json
+// @Param request body dto.DeviceClaimRequest true "Serial number and claim code"
+// @Success 200 {object} dto.ProvisionResponse "Device credentials"
+// @Failure 400 {object} dto.BadRequestErrorResponse "Invalid input"
+// @Failure 403 {object} dto.ForbiddenErrorResponse "Invalid claim code"
+// @Failure 409 {object} dto.ConflictErrorResponse "Claim code already used or device not claimed yet"
+// @Failure 500 {object} dto.InternalServerErrorResponse "Internal server error"
+// @Router /v1/provision [post]
+func (h *DeviceProvisioningHandler) ProvisionDevice(c *gin.Context) {
+	var req dto.DeviceClaimRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.DetailedErrorResponse{
+			Code:    dto.ErrorCodeInvalidRequest,
+			Message: "Invalid request format",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	credentials, err := h.provisioningService.Provision(req)
+	if err != nil {
+		if customErr, ok := err.(errors.CustomError); ok {
+			c.JSON(customErr.StatusCode(), dto.DetailedErrorResponse{
+				Code:    dto.ErrorCodeFromStatusCode(customErr.StatusCode()),
+				Message: customErr.Message(),
+				Details: "Please check your serial number and claim code",
+			})
+		} else {
+			c.JSON(http.StatusInternalServerError, dto.DetailedErrorResponse{
+				Code:    dto.ErrorCodeInternalError,
+				Message: "Internal server error",
+				Details: err.Error(),
+			})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, credentials)
+}
+
+// ClaimDevice godoc
+// @Summary Claim a device
+// @Description Claim an unassigned pre-registered device by serial number and claim code. The device is created for the caller, or for the active organization with the admin role. The claim code stays valid for the device to provision itself.
+// @Tags provisioning
+// @Accept  json
+// @Produce  json
+// @Param request body dto.DeviceClaimRequest true "Serial number and claim code"
+// @Success 201 {object} dto.DeviceResponse "Claimed device"
+// @Failure 400 {object} dto.BadRequestErrorResponse "Invalid input"
+// @Failure 401 {object} dto.DetailedErrorResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenErrorResponse "Invalid claim code"
+// @Failure 409 {object} dto.ConflictErrorResponse "Device already claimed"
+// @Failure 500 {object} dto.InternalServerErrorResponse "Internal server error"
+// @Security ApiKeyAuth
+// @Router /v1/devices/claim [post]
+func (h *DeviceProvisioningHandler) ClaimDevice(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, dto.DetailedErrorResponse{
+			Code:    dto.ErrorCodeInvalidCredentials,
+			Message: "Unauthorized",
+			Details: "User ID not found in context",
+		})
+		return
+	}
+
+	uuidUserID, ok := userID.(uuid.UUID)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, dto.DetailedErrorResponse{
+			Code:    dto.ErrorCodeInternalError,
+			Message: "Internal server error",
+			Details: "Invalid user ID type",
+		})
+		return
+	}
+
+	var req dto.DeviceClaimRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.DetailedErrorResponse{
+			Code:    dto.ErrorCodeInvalidRequest,
+			Message: "Invalid request format",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	device, err := h.provisioningService.ClaimDevice(uuidUserID, activeOrganizationID(c), req)
+	if err != nil {
+		if customErr, ok := err.(errors.CustomError); ok {
+			c.JSON(customErr.StatusCode(), dto.DetailedErrorResponse{
+				Code:    dto.ErrorCodeFromStatusCode(customErr.StatusCode()),
+				Message: customErr.Message(),
+				Details: "Please check your serial number and claim code",
+			})
+		} else {
+			c.JSON(http.StatusInternalServerError, dto.DetailedErrorResponse{
+				Code:    dto.ErrorCodeInternalError,
+				Message: "Internal server error",
+				Details: err.Error(),
+			})
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, device)
+}
@@ -0,0 +1,208 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/dto"
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/models"
+	custom_errors "github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/utils/errors"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockDeviceProvisioningService struct {
+	mock.Mock
+}
+
+func (m *MockDeviceProvisioningService) RegisterDevices(userID uuid.UUID, orgID *uuid.UUID, req dto.DeviceRegistrationRequest) ([]dto.DeviceRegistrationResponse, error) {
+	args := m.Called(userID, orgID, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]dto.DeviceRegistrationResponse), args.Error(1)
+}
+
+func (m *MockDeviceProvisioningService) ListRegistrations(userID uuid.UUID) ([]dto.DeviceRegistrationResponse, error) {
+	args := m.Called(userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]dto.DeviceRegistrationResponse), args.Error(1)
+}
+
+func (m *MockDeviceProvisioningService) Provision(req dto.DeviceClaimRequest) (*dto.ProvisionResponse, error) {
+	args := m.Called(req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dto.ProvisionResponse), args.Error(1)
+}
+
+func (m *MockDeviceProvisioningService) ClaimDevice(userID uuid.UUID, orgID *uuid.UUID, req dto.DeviceClaimRequest) (*models.Device, error) {
+	args := m.Called(userID, orgID, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Device), args.Error(1)
+}
+
+func TestDeviceProvisioningHandler_RegisterDevices(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	userID := uuid.New()
+
+	t.Run("Success - Register devices", func(t *testing.T) {
+		mockService := new(MockDeviceProvisioningService)
+		handler := NewDeviceProvisioningHandler(mockService)
+
+		req := dto.DeviceRegistrationRequest{Devices: []dto.CreateDeviceRequest{{Name: "Gateway", Location: "Rack 1", SN: "123456789012"}}}
+		mockService.On("RegisterDevices", userID, (*uuid.UUID)(nil), req).Return([]dto.DeviceRegistrationResponse{{SN: "123456789012", ClaimCode: "7KQM-XR2P-9ZC4-HW3N"}}, nil)
+
+		body, _ := json.Marshal(req)
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Set("userID", userID)
+		c.Request, _ = http.NewRequest("POST", "/provisioning/registrations", bytes.NewBuffer(body))
+		c.Request.Header.Set("Content-Type", "application/json")
+
+		handler.RegisterDevices(c)
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+
+		var response []dto.DeviceRegistrationResponse
+		json.Unmarshal(w.Body.Bytes(), &response)
+
+		assert.Equal(t, "7KQM-XR2P-9ZC4-HW3N", response[0].ClaimCode)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("Error - Empty device list", func(t *testing.T) {
+		mockService := new(MockDeviceProvisioningService)
+		handler := NewDeviceProvisioningHandler(mockService)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Set("userID", userID)
+		c.Request, _ = http.NewRequest("POST", "/provisioning/registrations", bytes.NewBufferString(`{"devices":[]}`))
+		c.Request.Header.Set("Content-Type", "application/json")
+
+		handler.RegisterDevices(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		mockService.AssertNotCalled(t, "RegisterDevices", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestDeviceProvisioningHandler_ProvisionDevice(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	req := dto.DeviceClaimRequest{SN: "123456789012", ClaimCode: "7KQM-XR2P-9ZC4-HW3N"}
+
+	t.Run("Success - Without user token", func(t *testing.T) {
+		mockService := new(MockDeviceProvisioningService)
+		handler := NewDeviceProvisioningHandler(mockService)
+
+		deviceID := uuid.New()
+		mockService.On("Provision", req).Return(&dto.ProvisionResponse{DeviceID: deviceID, DeviceToken: "dt_secret"}, nil)
+
+		body, _ := json.Marshal(req)
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request, _ = http.NewRequest("POST", "/provision", bytes.NewBuffer(body))
+		c.Request.Header.Set("Content-Type", "application/json")
+
+		handler.ProvisionDevice(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response dto.ProvisionResponse
+		json.Unmarshal(w.Body.Bytes(), &response)
+
+		assert.Equal(t, deviceID, response.DeviceID)
+		assert.Equal(t, "dt_secret", response.DeviceToken)
+	})
+
+	t.Run("Error - Invalid claim code", func(t *testing.T) {
+		mockService := new(MockDeviceProvisioningService)
+		handler := NewDeviceProvisioningHandler(mockService)
+
+		mockService.On("Provision", req).Return(nil, custom_errors.ErrInvalidClaimCode)
+
+		body, _ := json.Marshal(req)
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request, _ = http.NewRequest("POST", "/provision", bytes.NewBuffer(body))
+		c.Request.Header.Set("Content-Type", "application/json")
+
+		handler.ProvisionDevice(c)
+
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+
+	t.Run("Error - Claim code already used", func(t *testing.T) {
+		mockService := new(MockDeviceProvisioningService)
+		handler := NewDeviceProvisioningHandler(mockService)
+
+		mockService.On("Provision", req).Return(nil, custom_errors.ErrClaimCodeUsed)
+
+		body, _ := json.Marshal(req)
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request, _ = http.NewRequest("POST", "/provision", bytes.NewBuffer(body))
+		c.Request.Header.Set("Content-Type", "application/json")
+
+		handler.ProvisionDevice(c)
+
+		assert.Equal(t, http.StatusConflict, w.Code)
+	})
+}
+
+func TestDeviceProvisioningHandler_ClaimDevice(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	userID := uuid.New()
+	req := dto.DeviceClaimRequest{SN: "123456789012", ClaimCode: "7KQM-XR2P-9ZC4-HW3N"}
+
+	t.Run("Success - Claim into active organization", func(t *testing.T) {
+		mockService := new(MockDeviceProvisioningService)
+		handler := NewDeviceProvisioningHandler(mockService)
+
+		orgID := uuid.New()
+		mockService.On("ClaimDevice", userID, &orgID, req).Return(&models.Device{UUID: uuid.New(), SN: req.SN, OrganizationID: &orgID}, nil)
+
+		body, _ := json.Marshal(req)
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Set("userID", userID)
+		c.Set("orgID", orgID)
+		c.Request, _ = http.NewRequest("POST", "/devices/claim", bytes.NewBuffer(body))
+		c.Request.Header.Set("Content-Type", "application/json")
+
+		handler.ClaimDevice(c)
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("Error - Unauthorized", func(t *testing.T) {
+		mockService := new(MockDeviceProvisioningService)
+		handler := NewDeviceProvisioningHandler(mockService)
+
+		body, _ := json.Marshal(req)
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request, _ = http.NewRequest("POST", "/devices/claim", bytes.NewBuffer(body))
+		c.Request.Header.Set("Content-Type", "application/json")
+
+		handler.ClaimDevice(c)
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+		mockService.AssertNotCalled(t, "ClaimDevice", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
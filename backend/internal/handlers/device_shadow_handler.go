@@ -0,0 +1,244 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/dto"
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/services"
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/utils/errors"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type DeviceShadowHandler struct {
+	shadowService services.DeviceShadowService
+}
+
+func NewDeviceShadowHandler(shadowService services.DeviceShadowService) *DeviceShadowHandler {
+	return &DeviceShadowHandler{shadowService: shadowService}
+}
+
+// GetDeviceShadow godoc
+// @Summary Get device shadow
+// @Description Get the desired and reported configuration of a device, their versions and the delta the device has not applied yet
+// @Tags devices
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Device ID"
+// @Success 200 {object} dto.DeviceShadowResponse "Device shadow"
+// @Failure 400 {object} dto.BadRequestErrorResponse "Invalid device ID"
+// @Failure 401 {object} dto.DetailedErrorResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenErrorResponse "Forbidden"
+// @Failure 404 {object} dto.DetailedErrorResponse "Device not found"
+// @Failure 500 {object} dto.InternalServerErrorResponse "Internal server error"
+// @Security ApiKeyAuth
+// @Router /v1/devices/{id}/shadow [get]
+func (h *DeviceShadowHandler) GetDeviceShadow(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, dto.DetailedErrorResponse{
+			Code:    dto.ErrorCodeInvalidCredentials,
+			Message: "Unauthorized",
+			Details: "User ID not found in context",
+		})
+		return
+	}
+
+	uuidUserID, ok := userID.(uuid.UUID)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, dto.DetailedErrorResponse{
+			Code:    dto.ErrorCodeInternalError,
+			Message: "Internal server error",
+			Details: "Invalid user ID type",
+		})
+		return
+	}
+
+	deviceID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.DetailedErrorResponse{
+			Code:    dto.ErrorCodeInvalidRequest,
+			Message: "Invalid device ID",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	shadow, err := h.shadowService.GetShadow(uuidUserID, deviceID)
+	if err != nil {
+		if customErr, ok := err.(errors.CustomError); ok {
+			c.JSON(customErr.StatusCode(), dto.DetailedErrorResponse{
+				Code:    dto.ErrorCodeFromStatusCode(customErr.StatusCode()),
+				Message: customErr.Message(),
+				Details: "Failed to get device shadow",
+			})
+		} else {
+			c.JSON(http.StatusInternalServerError, dto.DetailedErrorResponse{
+				Code:    dto.ErrorCodeInternalError,
+				Message: "Internal server error",
+				Details: err.Error(),
+			})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, shadow)
+}
+
+// ReplaceDesiredState godoc
+// @Summary Replace desired state
+// @Description Replace the desired configuration of a device and push the resulting delta to it. Known settings: heartbeat_interval_seconds (10-3600) and temperature_sample_rate_seconds (1-3600). Pass version to only apply the change to that desired_version. Requires the operator role.
+// @Tags devices
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Device ID"
+// @Param request body dto.DeviceShadowDesiredRequest true "Desired configuration"
+// @Success 200 {object} dto.DeviceShadowResponse "Updated shadow"
+// @Failure 400 {object} dto.BadRequestErrorResponse "Invalid input"
+// @Failure 401 {object} dto.DetailedErrorResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenErrorResponse "Forbidden"
+// @Failure 404 {object} dto.DetailedErrorResponse "Device not found"
+// @Failure 409 {object} dto.ConflictErrorResponse "Shadow version conflict"
+// @Failure 500 {object} dto.InternalServerErrorResponse "Internal server error"
+// @Security ApiKeyAuth
+// @Router /v1/devices/{id}/shadow/desired [put]
+func (h *DeviceShadowHandler) ReplaceDesiredState(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, dto.DetailedErrorResponse{
+			Code:    dto.ErrorCodeInvalidCredentials,
+			Message: "Unauthorized",
+			Details: "User ID not found in context",
+		})
+		return
+	}
+
+	uuidUserID, ok := userID.(uuid.UUID)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, dto.DetailedErrorResponse{
+			Code:    dto.ErrorCodeInternalError,
+			Message: "Internal server error",
+			Details: "Invalid user ID type",
+		})
+		return
+	}
+
+	deviceID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.DetailedErrorResponse{
+			Code:    dto.ErrorCodeInvalidRequest,
+			Message: "Invalid device ID",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	var req dto.DeviceShadowDesiredRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.DetailedErrorResponse{
+			Code:    dto.ErrorCodeInvalidRequest,
+			Message: "Invalid request format",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	shadow, err := h.shadowService.UpdateDesired(uuidUserID, deviceID, req, false)
+	if err != nil {
+		if customErr, ok := err.(errors.CustomError); ok {
+			c.JSON(customErr.StatusCode(), dto.DetailedErrorResponse{
+				Code:    dto.ErrorCodeFromStatusCode(customErr.StatusCode()),
+				Message: customErr.Message(),
+				Details: "Please check your input and try again",
+			})
+		} else {
+			c.JSON(http.StatusInternalServerError, dto.DetailedErrorResponse{
+				Code:    dto.ErrorCodeInternalError,
+				Message: "Internal server error",
+				Details: err.Error(),
+			})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, shadow)
+}
+
+// UpdateDesiredState godoc
+// @Summary Update desired state
+// @Description Merge into the desired configuration of a device and push the resulting delta to it. Nested objects are merged key by key and null removes a key. Pass version to only apply the change to that desired_version. Requires the operator role.
+// @Tags devices
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Device ID"
+// @Param request body dto.DeviceShadowDesiredRequest true "Desired configuration"
+// @Success 200 {object} dto.DeviceShadowResponse "Updated shadow"
+// @Failure 400 {object} dto.BadRequestErrorResponse "Invalid input"
+// @Failure 401 {object} dto.DetailedErrorResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenErrorResponse "Forbidden"
+// @Failure 404 {object} dto.DetailedErrorResponse "Device not found"
+// @Failure 409 {object} dto.ConflictErrorResponse "Shadow version conflict"
+// @Failure 500 {object} dto.InternalServerErrorResponse "Internal server error"
+// @Security ApiKeyAuth
+// @Router /v1/devices/{id}/shadow/desired [patch]
+func (h *DeviceShadowHandler) UpdateDesiredState(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, dto.DetailedErrorResponse{
+			Code:    dto.ErrorCodeInvalidCredentials,
+			Message: "Unauthorized",
+			Details: "User ID not found in context",
+		})
+		return
+	}
+
+	uuidUserID, ok := userID.(uuid.UUID)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, dto.DetailedErrorResponse{
+			Code:    dto.ErrorCodeInternalError,
+			Message: "Internal server error",
+			Details: "Invalid user ID type",
+		})
+		return
+	}
+
+	deviceID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.DetailedErrorResponse{
+			Code:    dto.ErrorCodeInvalidRequest,
+			Message: "Invalid device ID",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	var req dto.DeviceShadowDesiredRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.DetailedErrorResponse{
+			Code:    dto.ErrorCodeInvalidRequest,
+			Message: "Invalid request format",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	shadow, err := h.shadowService.UpdateDesired(uuidUserID, deviceID, req, true)
+	if err != nil {
+		if customErr, ok := err.(errors.CustomError); ok {
+			c.JSON(customErr.StatusCode(), dto.DetailedErrorResponse{
+				Code:    dto.ErrorCodeFromStatusCode(customErr.StatusCode()),
+				Message: customErr.Message(),
+				Details: "Please check your input and try again",
+			})
+		} else {
+			c.JSON(http.StatusInternalServerError, dto.DetailedErrorResponse{
+				Code:    dto.ErrorCodeInternalError,
+				Message: "Internal server error",
+				Details: err.Error(),
+			})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, shadow)
+}
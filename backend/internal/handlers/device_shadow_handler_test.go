@@ -0,0 +1,177 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/dto"
+	custom_errors "github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/utils/errors"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockDeviceShadowService struct {
+	mock.Mock
+}
+
+func (m *MockDeviceShadowService) GetShadow(userID, deviceID uuid.UUID) (*dto.DeviceShadowResponse, error) {
+	args := m.Called(userID, deviceID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dto.DeviceShadowResponse), args.Error(1)
+}
+
+func (m *MockDeviceShadowService) UpdateDesired(userID, deviceID uuid.UUID, req dto.DeviceShadowDesiredRequest, merge bool) (*dto.DeviceShadowResponse, error) {
+	args := m.Called(userID, deviceID, req, merge)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dto.DeviceShadowResponse), args.Error(1)
+}
+
+func (m *MockDeviceShadowService) UpdateReported(deviceID uuid.UUID, reported map[string]interface{}) error {
+	args := m.Called(deviceID, reported)
+	return args.Error(0)
+}
+
+func TestDeviceShadowHandler_GetDeviceShadow(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	userID := uuid.New()
+	deviceID := uuid.New()
+
+	t.Run("Success - Get shadow", func(t *testing.T) {
+		mockShadowService := new(MockDeviceShadowService)
+		handler := NewDeviceShadowHandler(mockShadowService)
+
+		mockShadowService.On("GetShadow", userID, deviceID).Return(&dto.DeviceShadowResponse{
+			DeviceID:       deviceID,
+			Desired:        map[string]interface{}{"heartbeat_interval_seconds": float64(30)},
+			Reported:       map[string]interface{}{},
+			Delta:          map[string]interface{}{"heartbeat_interval_seconds": float64(30)},
+			DesiredVersion: 1,
+		}, nil)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Set("userID", userID)
+		c.Params = gin.Params{{Key: "id", Value: deviceID.String()}}
+		c.Request, _ = http.NewRequest("GET", "/devices/"+deviceID.String()+"/shadow", nil)
+
+		handler.GetDeviceShadow(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response dto.DeviceShadowResponse
+		json.Unmarshal(w.Body.Bytes(), &response)
+
+		assert.Equal(t, float64(30), response.Delta["heartbeat_interval_seconds"])
+		mockShadowService.AssertExpectations(t)
+	})
+
+	t.Run("Error - Invalid device ID", func(t *testing.T) {
+		mockShadowService := new(MockDeviceShadowService)
+		handler := NewDeviceShadowHandler(mockShadowService)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Set("userID", userID)
+		c.Params = gin.Params{{Key: "id", Value: "invalid"}}
+		c.Request, _ = http.NewRequest("GET", "/devices/invalid/shadow", nil)
+
+		handler.GetDeviceShadow(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		mockShadowService.AssertNotCalled(t, "GetShadow", mock.Anything, mock.Anything)
+	})
+}
+
+func TestDeviceShadowHandler_UpdateDesiredState(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	userID := uuid.New()
+	deviceID := uuid.New()
+	req := dto.DeviceShadowDesiredRequest{Desired: map[string]interface{}{"heartbeat_interval_seconds": float64(30)}}
+
+	t.Run("Success - Patch merges", func(t *testing.T) {
+		mockShadowService := new(MockDeviceShadowService)
+		handler := NewDeviceShadowHandler(mockShadowService)
+
+		mockShadowService.On("UpdateDesired", userID, deviceID, req, true).Return(&dto.DeviceShadowResponse{DeviceID: deviceID, DesiredVersion: 2}, nil)
+
+		body, _ := json.Marshal(req)
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Set("userID", userID)
+		c.Params = gin.Params{{Key: "id", Value: deviceID.String()}}
+		c.Request, _ = http.NewRequest("PATCH", "/devices/"+deviceID.String()+"/shadow/desired", bytes.NewBuffer(body))
+		c.Request.Header.Set("Content-Type", "application/json")
+
+		handler.UpdateDesiredState(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		mockShadowService.AssertExpectations(t)
+	})
+
+	t.Run("Success - Put replaces", func(t *testing.T) {
+		mockShadowService := new(MockDeviceShadowService)
+		handler := NewDeviceShadowHandler(mockShadowService)
+
+		mockShadowService.On("UpdateDesired", userID, deviceID, req, false).Return(&dto.DeviceShadowResponse{DeviceID: deviceID, DesiredVersion: 2}, nil)
+
+		body, _ := json.Marshal(req)
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Set("userID", userID)
+		c.Params = gin.Params{{Key: "id", Value: deviceID.String()}}
+		c.Request, _ = http.NewRequest("PUT", "/devices/"+deviceID.String()+"/shadow/desired", bytes.NewBuffer(body))
+		c.Request.Header.Set("Content-Type", "application/json")
+
+		handler.ReplaceDesiredState(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		mockShadowService.AssertExpectations(t)
+	})
+
+	t.Run("Error - Missing desired", func(t *testing.T) {
+		mockShadowService := new(MockDeviceShadowService)
+		handler := NewDeviceShadowHandler(mockShadowService)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Set("userID", userID)
+		c.Params = gin.Params{{Key: "id", Value: deviceID.String()}}
+		c.Request, _ = http.NewRequest("PATCH", "/devices/"+deviceID.String()+"/shadow/desired", bytes.NewBufferString(`{"version":1}`))
+		c.Request.Header.Set("Content-Type", "application/json")
+
+		handler.UpdateDesiredState(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		mockShadowService.AssertNotCalled(t, "UpdateDesired", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Error - Version conflict", func(t *testing.T) {
+		mockShadowService := new(MockDeviceShadowService)
+		handler := NewDeviceShadowHandler(mockShadowService)
+
+		mockShadowService.On("UpdateDesired", userID, deviceID, req, true).Return(nil, custom_errors.ErrShadowVersionConflict)
+
+		body, _ := json.Marshal(req)
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Set("userID", userID)
+		c.Params = gin.Params{{Key: "id", Value: deviceID.String()}}
+		c.Request, _ = http.NewRequest("PATCH", "/devices/"+deviceID.String()+"/shadow/desired", bytes.NewBuffer(body))
+		c.Request.Header.Set("Content-Type", "application/json")
+
+		handler.UpdateDesiredState(c)
+
+		assert.Equal(t, http.StatusConflict, w.Code)
+	})
+}
@@ -0,0 +1,167 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/dto"
+	custom_errors "github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/utils/errors"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockDeviceShareService struct {
+	mock.Mock
+}
+
+func (m *MockDeviceShareService) ShareDevice(userID, deviceID uuid.UUID, req dto.DeviceShareRequest) (*dto.DeviceShareResponse, error) {
+	args := m.Called(userID, deviceID, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dto.DeviceShareResponse), args.Error(1)
+}
+
+func (m *MockDeviceShareService) ListShares(userID, deviceID uuid.UUID) ([]dto.DeviceShareResponse, error) {
+	args := m.Called(userID, deviceID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]dto.DeviceShareResponse), args.Error(1)
+}
+
+func (m *MockDeviceShareService) RevokeShare(userID, deviceID, shareID uuid.UUID) error {
+	args := m.Called(userID, deviceID, shareID)
+	return args.Error(0)
+}
+
+func TestDeviceShareHandler_ShareDevice(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	userID := uuid.New()
+	deviceID := uuid.New()
+
+	t.Run("Success - Share device", func(t *testing.T) {
+		mockShareService := new(MockDeviceShareService)
+		handler := NewDeviceShareHandler(mockShareService)
+
+		req := dto.DeviceShareRequest{Email: "contractor@example.com", Permission: "viewer"}
+		mockShareService.On("ShareDevice", userID, deviceID, req).Return(&dto.DeviceShareResponse{ID: uuid.New(), DeviceID: deviceID, Email: req.Email, Permission: "viewer"}, nil)
+
+		body, _ := json.Marshal(req)
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Set("userID", userID)
+		c.Params = gin.Params{{Key: "id", Value: deviceID.String()}}
+		c.Request, _ = http.NewRequest("POST", "/devices/"+deviceID.String()+"/shares", bytes.NewBuffer(body))
+		c.Request.Header.Set("Content-Type", "application/json")
+
+		handler.ShareDevice(c)
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+
+		var response dto.DeviceShareResponse
+		json.Unmarshal(w.Body.Bytes(), &response)
+
+		assert.Equal(t, "contractor@example.com", response.Email)
+		mockShareService.AssertExpectations(t)
+	})
+
+	t.Run("Error - Missing email", func(t *testing.T) {
+		mockShareService := new(MockDeviceShareService)
+		handler := NewDeviceShareHandler(mockShareService)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Set("userID", userID)
+		c.Params = gin.Params{{Key: "id", Value: deviceID.String()}}
+		c.Request, _ = http.NewRequest("POST", "/devices/"+deviceID.String()+"/shares", bytes.NewBufferString(`{"permission":"viewer"}`))
+		c.Request.Header.Set("Content-Type", "application/json")
+
+		handler.ShareDevice(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		mockShareService.AssertNotCalled(t, "ShareDevice", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Error - Forbidden", func(t *testing.T) {
+		mockShareService := new(MockDeviceShareService)
+		handler := NewDeviceShareHandler(mockShareService)
+
+		req := dto.DeviceShareRequest{Email: "contractor@example.com", Permission: "operator"}
+		mockShareService.On("ShareDevice", userID, deviceID, req).Return(nil, custom_errors.ErrForbidden)
+
+		body, _ := json.Marshal(req)
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Set("userID", userID)
+		c.Params = gin.Params{{Key: "id", Value: deviceID.String()}}
+		c.Request, _ = http.NewRequest("POST", "/devices/"+deviceID.String()+"/shares", bytes.NewBuffer(body))
+		c.Request.Header.Set("Content-Type", "application/json")
+
+		handler.ShareDevice(c)
+
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+}
+
+func TestDeviceShareHandler_RevokeDeviceShare(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	userID := uuid.New()
+	deviceID := uuid.New()
+	shareID := uuid.New()
+
+	t.Run("Success - Revoke share", func(t *testing.T) {
+		mockShareService := new(MockDeviceShareService)
+		handler := NewDeviceShareHandler(mockShareService)
+
+		mockShareService.On("RevokeShare", userID, deviceID, shareID).Return(nil)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Set("userID", userID)
+		c.Params = gin.Params{{Key: "id", Value: deviceID.String()}, {Key: "share_id", Value: shareID.String()}}
+
+		handler.RevokeDeviceShare(c)
+
+		assert.Equal(t, http.StatusNoContent, c.Writer.Status())
+		mockShareService.AssertExpectations(t)
+	})
+
+	t.Run("Error - Share not found", func(t *testing.T) {
+		mockShareService := new(MockDeviceShareService)
+		handler := NewDeviceShareHandler(mockShareService)
+
+		mockShareService.On("RevokeShare", userID, deviceID, shareID).Return(custom_errors.ErrDeviceShareNotFound)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Set("userID", userID)
+		c.Params = gin.Params{{Key: "id", Value: deviceID.String()}, {Key: "share_id", Value: shareID.String()}}
+
+		handler.RevokeDeviceShare(c)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("Error - Invalid share ID", func(t *testing.T) {
+		mockShareService := new(MockDeviceShareService)
+		handler := NewDeviceShareHandler(mockShareService)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Set("userID", userID)
+		c.Params = gin.Params{{Key: "id", Value: deviceID.String()}, {Key: "share_id", Value: "not-a-uuid"}}
+
+		handler.RevokeDeviceShare(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		mockShareService.AssertNotCalled(t, "RevokeShare", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
@@ -0,0 +1,213 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/dto"
+	custom_errors "github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/utils/errors"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockDeviceTransferService struct {
+	mock.Mock
+}
+
+func (m *MockDeviceTransferService) RequestTransfer(userID, deviceID uuid.UUID, req dto.DeviceTransferRequest) (*dto.DeviceTransferResponse, error) {
+	args := m.Called(userID, deviceID, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dto.DeviceTransferResponse), args.Error(1)
+}
+
+func (m *MockDeviceTransferService) ListTransfers(userID uuid.UUID) ([]dto.DeviceTransferResponse, error) {
+	args := m.Called(userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]dto.DeviceTransferResponse), args.Error(1)
+}
+
+func (m *MockDeviceTransferService) AcceptTransfer(userID, transferID uuid.UUID) (*dto.DeviceTransferResponse, error) {
+	args := m.Called(userID, transferID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dto.DeviceTransferResponse), args.Error(1)
+}
+
+func (m *MockDeviceTransferService) DeclineTransfer(userID, transferID uuid.UUID) error {
+	args := m.Called(userID, transferID)
+	return args.Error(0)
+}
+
+func (m *MockDeviceTransferService) CancelTransfer(userID, transferID uuid.UUID) error {
+	args := m.Called(userID, transferID)
+	return args.Error(0)
+}
+
+func (m *MockDeviceTransferService) GetAuditLog(userID, deviceID uuid.UUID) ([]dto.AuditLogResponse, error) {
+	args := m.Called(userID, deviceID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]dto.AuditLogResponse), args.Error(1)
+}
+
+func TestDeviceTransferHandler_RequestDeviceTransfer(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	userID := uuid.New()
+	deviceID := uuid.New()
+
+	t.Run("Success - Request transfer", func(t *testing.T) {
+		mockTransferService := new(MockDeviceTransferService)
+		handler := NewDeviceTransferHandler(mockTransferService)
+
+		req := dto.DeviceTransferRequest{Email: "buyer@example.com"}
+		mockTransferService.On("RequestTransfer", userID, deviceID, req).Return(&dto.DeviceTransferResponse{ID: uuid.New(), DeviceID: deviceID, ToEmail: req.Email, Status: "pending"}, nil)
+
+		body, _ := json.Marshal(req)
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Set("userID", userID)
+		c.Params = gin.Params{{Key: "id", Value: deviceID.String()}}
+		c.Request, _ = http.NewRequest("POST", "/devices/"+deviceID.String()+"/transfers", bytes.NewBuffer(body))
+		c.Request.Header.Set("Content-Type", "application/json")
+
+		handler.RequestDeviceTransfer(c)
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+
+		var response dto.DeviceTransferResponse
+		json.Unmarshal(w.Body.Bytes(), &response)
+
+		assert.Equal(t, "pending", response.Status)
+		mockTransferService.AssertExpectations(t)
+	})
+
+	t.Run("Error - Invalid email", func(t *testing.T) {
+		mockTransferService := new(MockDeviceTransferService)
+		handler := NewDeviceTransferHandler(mockTransferService)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Set("userID", userID)
+		c.Params = gin.Params{{Key: "id", Value: deviceID.String()}}
+		c.Request, _ = http.NewRequest("POST", "/devices/"+deviceID.String()+"/transfers", bytes.NewBufferString(`{"email":"not-an-email"}`))
+		c.Request.Header.Set("Content-Type", "application/json")
+
+		handler.RequestDeviceTransfer(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		mockTransferService.AssertNotCalled(t, "RequestTransfer", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Error - Transfer already pending", func(t *testing.T) {
+		mockTransferService := new(MockDeviceTransferService)
+		handler := NewDeviceTransferHandler(mockTransferService)
+
+		req := dto.DeviceTransferRequest{Email: "buyer@example.com"}
+		mockTransferService.On("RequestTransfer", userID, deviceID, req).Return(nil, custom_errors.ErrDeviceTransferPending)
+
+		body, _ := json.Marshal(req)
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Set("userID", userID)
+		c.Params = gin.Params{{Key: "id", Value: deviceID.String()}}
+		c.Request, _ = http.NewRequest("POST", "/devices/"+deviceID.String()+"/transfers", bytes.NewBuffer(body))
+		c.Request.Header.Set("Content-Type", "application/json")
+
+		handler.RequestDeviceTransfer(c)
+
+		assert.Equal(t, http.StatusConflict, w.Code)
+	})
+}
+
+func TestDeviceTransferHandler_AcceptDeviceTransfer(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	userID := uuid.New()
+	transferID := uuid.New()
+
+	t.Run("Success - Accept transfer", func(t *testing.T) {
+		mockTransferService := new(MockDeviceTransferService)
+		handler := NewDeviceTransferHandler(mockTransferService)
+
+		mockTransferService.On("AcceptTransfer", userID, transferID).Return(&dto.DeviceTransferResponse{ID: transferID, Status: "accepted", ToUserID: &userID}, nil)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Set("userID", userID)
+		c.Params = gin.Params{{Key: "id", Value: transferID.String()}}
+
+		handler.AcceptDeviceTransfer(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response dto.DeviceTransferResponse
+		json.Unmarshal(w.Body.Bytes(), &response)
+
+		assert.Equal(t, "accepted", response.Status)
+		mockTransferService.AssertExpectations(t)
+	})
+
+	t.Run("Error - Transfer expired", func(t *testing.T) {
+		mockTransferService := new(MockDeviceTransferService)
+		handler := NewDeviceTransferHandler(mockTransferService)
+
+		mockTransferService.On("AcceptTransfer", userID, transferID).Return(nil, custom_errors.ErrDeviceTransferExpired)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Set("userID", userID)
+		c.Params = gin.Params{{Key: "id", Value: transferID.String()}}
+
+		handler.AcceptDeviceTransfer(c)
+
+		assert.Equal(t, http.StatusConflict, w.Code)
+	})
+
+	t.Run("Error - User ID not found in context", func(t *testing.T) {
+		handler := NewDeviceTransferHandler(new(MockDeviceTransferService))
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Params = gin.Params{{Key: "id", Value: transferID.String()}}
+
+		handler.AcceptDeviceTransfer(c)
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+}
+
+func TestDeviceTransferHandler_CancelDeviceTransfer(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	userID := uuid.New()
+	transferID := uuid.New()
+
+	t.Run("Success - Cancel transfer", func(t *testing.T) {
+		mockTransferService := new(MockDeviceTransferService)
+		handler := NewDeviceTransferHandler(mockTransferService)
+
+		mockTransferService.On("CancelTransfer", userID, transferID).Return(nil)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Set("userID", userID)
+		c.Params = gin.Params{{Key: "id", Value: transferID.String()}}
+
+		handler.CancelDeviceTransfer(c)
+
+		assert.Equal(t, http.StatusNoContent, c.Writer.Status())
+		mockTransferService.AssertExpectations(t)
+	})
+}
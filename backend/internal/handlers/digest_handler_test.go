@@ -0,0 +1,92 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/dto"
+	custom_errors "github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/utils/errors"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockDigestService struct {
+	mock.Mock
+}
+
+func (m *MockDigestService) PreviewDigest(userID uuid.UUID, period string) (*dto.DigestReport, error) {
+	args := m.Called(userID, period)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dto.DigestReport), args.Error(1)
+}
+
+func (m *MockDigestService) SendDueDigests(now time.Time) (int, error) {
+	args := m.Called(now)
+	return args.Int(0), args.Error(1)
+}
+
+func TestDigestHandler_PreviewDigest(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	userID := uuid.New()
+
+	t.Run("Success - Weekly digest", func(t *testing.T) {
+		mockService := new(MockDigestService)
+		handler := NewDigestHandler(mockService)
+
+		mockService.On("PreviewDigest", userID, "weekly").Return(&dto.DigestReport{Period: "weekly", AlertsFired: 5, DevicesOfflineCount: 1}, nil)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Set("userID", userID)
+		c.Request, _ = http.NewRequest("GET", "/digests/preview?period=weekly", nil)
+
+		handler.PreviewDigest(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response dto.DigestReport
+		json.Unmarshal(w.Body.Bytes(), &response)
+
+		assert.Equal(t, "weekly", response.Period)
+		assert.Equal(t, int64(5), response.AlertsFired)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("Error - Invalid period", func(t *testing.T) {
+		mockService := new(MockDigestService)
+		handler := NewDigestHandler(mockService)
+
+		mockService.On("PreviewDigest", userID, "monthly").Return(nil, custom_errors.NewValidationError("period must be daily or weekly"))
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Set("userID", userID)
+		c.Request, _ = http.NewRequest("GET", "/digests/preview?period=monthly", nil)
+
+		handler.PreviewDigest(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("Error - Unauthorized", func(t *testing.T) {
+		mockService := new(MockDigestService)
+		handler := NewDigestHandler(mockService)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request, _ = http.NewRequest("GET", "/digests/preview", nil)
+
+		handler.PreviewDigest(c)
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+		mockService.AssertNotCalled(t, "PreviewDigest", mock.Anything, mock.Anything)
+	})
+}
@@ -0,0 +1,104 @@
+package handlers
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/dto"
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/services"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockEscalationPolicyService struct {
+	mock.Mock
+}
+
+func (m *MockEscalationPolicyService) CreatePolicy(userID uuid.UUID, orgID *uuid.UUID, req dto.EscalationPolicyRequest) (*dto.EscalationPolicyResponse, error) {
+	args := m.Called(userID, orgID, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dto.EscalationPolicyResponse), args.Error(1)
+}
+
+func (m *MockEscalationPolicyService) ListPolicies(userID uuid.UUID) ([]dto.EscalationPolicyResponse, error) {
+	args := m.Called(userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]dto.EscalationPolicyResponse), args.Error(1)
+}
+
+func (m *MockEscalationPolicyService) GetPolicy(userID, policyID uuid.UUID) (*dto.EscalationPolicyResponse, error) {
+	args := m.Called(userID, policyID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dto.EscalationPolicyResponse), args.Error(1)
+}
+
+func (m *MockEscalationPolicyService) UpdatePolicy(userID, policyID uuid.UUID, req dto.EscalationPolicyRequest) (*dto.EscalationPolicyResponse, error) {
+	args := m.Called(userID, policyID, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dto.EscalationPolicyResponse), args.Error(1)
+}
+
+func (m *MockEscalationPolicyService) DeletePolicy(userID, policyID uuid.UUID) error {
+	args := m.Called(userID, policyID)
+	return args.Error(0)
+}
+
+func (m *MockEscalationPolicyService) ResolvePolicy(owner services.Ownership, policyID uuid.UUID) error {
+	args := m.Called(owner, policyID)
+	return args.Error(0)
+}
+
+func TestEscalationPolicyHandler_CreateEscalationPolicy(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	userID := uuid.New()
+
+	t.Run("Success - Create policy", func(t *testing.T) {
+		mockPolicyService := new(MockEscalationPolicyService)
+		handler := NewEscalationPolicyHandler(mockPolicyService)
+
+		mockPolicyService.On("CreatePolicy", userID, (*uuid.UUID)(nil), mock.MatchedBy(func(req dto.EscalationPolicyRequest) bool {
+			return len(req.Steps) == 1 && req.Steps[0].DelayMinutes == 10
+		})).Return(&dto.EscalationPolicyResponse{ID: uuid.New(), Name: "On-call"}, nil)
+
+		body := `{"name":"On-call","steps":[{"delay_minutes":10,"user_ids":["` + userID.String() + `"]}]}`
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Set("userID", userID)
+		c.Request, _ = http.NewRequest("POST", "/escalation-policies", bytes.NewBufferString(body))
+		c.Request.Header.Set("Content-Type", "application/json")
+
+		handler.CreateEscalationPolicy(c)
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+		mockPolicyService.AssertExpectations(t)
+	})
+
+	t.Run("Error - Missing steps", func(t *testing.T) {
+		mockPolicyService := new(MockEscalationPolicyService)
+		handler := NewEscalationPolicyHandler(mockPolicyService)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Set("userID", userID)
+		c.Request, _ = http.NewRequest("POST", "/escalation-policies", bytes.NewBufferString(`{"name":"On-call"}`))
+		c.Request.Header.Set("Content-Type", "application/json")
+
+		handler.CreateEscalationPolicy(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		mockPolicyService.AssertNotCalled(t, "CreatePolicy", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
@@ -0,0 +1,464 @@
+package handlers
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/dto"
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/models"
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/services"
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/utils/errors"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type FirmwareHandler struct {
+	firmwareService services.FirmwareService
+}
+
+func NewFirmwareHandler(firmwareService services.FirmwareService) *FirmwareHandler {
+	return &FirmwareHandler{firmwareService: firmwareService}
+}
+
+// UploadFirmware godoc
+// @Summary Upload a firmware artifact
+// @Description Add a firmware version to the registry of the user or the active organization (admin role). The file is sent as multipart form data; its SHA-256 checksum is computed on upload and, when checksum is given, must match.
+// @Tags firmware
+// @Accept  multipart/form-data
+// @Produce  json
+// @Param file formData file true "Firmware file"
+// @Param version formData string true "Firmware version"
+// @Param notes formData string false "Release notes"
+// @Param checksum formData string false "Expected SHA-256 of the file"
+// @Success 201 {object} dto.FirmwareArtifactResponse "Uploaded artifact"
+// @Failure 400 {object} dto.BadRequestErrorResponse "Invalid input"
+// @Failure 401 {object} dto.DetailedErrorResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenErrorResponse "Forbidden"
+// @Failure 409 {object} dto.ConflictErrorResponse "Version already exists"
+// @Failure 500 {object} dto.InternalServerErrorResponse "Internal server error"
+// @Security ApiKeyAuth
+// @Router /v1/firmware/artifacts [post]
+func (h *FirmwareHandler) UploadFirmware(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, dto.DetailedErrorResponse{
+			Code:    dto.ErrorCodeInvalidCredentials,
+			Message: "Unauthorized",
+			Details: "User ID not found in context",
+		})
+		return
+	}
+
+	uuidUserID, ok := userID.(uuid.UUID)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, dto.DetailedErrorResponse{
+			Code:    dto.ErrorCodeInternalError,
+			Message: "Internal server error",
+			Details: "Invalid user ID type",
+		})
+		return
+	}
+
+	var req dto.FirmwareUploadRequest
+	if err := c.ShouldBind(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.DetailedErrorResponse{
+			Code:    dto.ErrorCodeInvalidRequest,
+			Message: "Invalid request format",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.DetailedErrorResponse{
+			Code:    dto.ErrorCodeInvalidRequest,
+			Message: "Firmware file is required",
+			Details: err.Error(),
+		})
+		return
+	}
+	file, err := fileHeader.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.DetailedErrorResponse{
+			Code:    dto.ErrorCodeInvalidRequest,
+			Message: "Invalid firmware file",
+			Details: err.Error(),
+		})
+		return
+	}
+	defer file.Close()
+
+	artifact, err := h.firmwareService.UploadFirmware(uuidUserID, activeOrganizationID(c), req, fileHeader.Filename, file)
+	if err != nil {
+		if customErr, ok := err.(errors.CustomError); ok {
+			c.JSON(customErr.StatusCode(), dto.DetailedErrorResponse{
+				Code:    dto.ErrorCodeFromStatusCode(customErr.StatusCode()),
+				Message: customErr.Message(),
+				Details: "Please check your input and try again",
+			})
+		} else {
+			c.JSON(http.StatusInternalServerError, dto.DetailedErrorResponse{
+				Code:    dto.ErrorCodeInternalError,
+				Message: "Internal server error",
+				Details: err.Error(),
+			})
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, artifact)
+}
+
+// ListFirmware godoc
+// @Summary List firmware artifacts
+// @Description List the firmware artifacts of the user and of their organizations
+// @Tags firmware
+// @Accept  json
+// @Produce  json
+// @Success 200 {array} dto.FirmwareArtifactResponse "Artifacts"
+// @Failure 401 {object} dto.DetailedErrorResponse "Unauthorized"
+// @Failure 500 {object} dto.InternalServerErrorResponse "Internal server error"
+// @Security ApiKeyAuth
+// @Router /v1/firmware/artifacts [get]
+func (h *FirmwareHandler) ListFirmware(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, dto.DetailedErrorResponse{
+			Code:    dto.ErrorCodeInvalidCredentials,
+			Message: "Unauthorized",
+			Details: "User ID not found in context",
+		})
+		return
+	}
+
+	uuidUserID, ok := userID.(uuid.UUID)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, dto.DetailedErrorResponse{
+			Code:    dto.ErrorCodeInternalError,
+			Message: "Internal server error",
+			Details: "Invalid user ID type",
+		})
+		return
+	}
+
+	artifacts, err := h.firmwareService.ListFirmware(uuidUserID)
+	if err != nil {
+		if customErr, ok := err.(errors.CustomError); ok {
+			c.JSON(customErr.StatusCode(), dto.DetailedErrorResponse{
+				Code:    dto.ErrorCodeFromStatusCode(customErr.StatusCode()),
+				Message: customErr.Message(),
+				Details: "Failed to list firmware artifacts",
+			})
+		} else {
+			c.JSON(http.StatusInternalServerError, dto.DetailedErrorResponse{
+				Code:    dto.ErrorCodeInternalError,
+				Message: "Internal server error",
+				Details: err.Error(),
+			})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, artifacts)
+}
+
+// GetFirmware godoc
+// @Summary Get a firmware artifact
+// @Description Get the metadata of a firmware artifact
+// @Tags firmware
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Artifact ID"
+// @Success 200 {object} dto.FirmwareArtifactResponse "Artifact"
+// @Failure 400 {object} dto.BadRequestErrorResponse "Invalid artifact ID"
+// @Failure 401 {object} dto.DetailedErrorResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenErrorResponse "Forbidden"
+// @Failure 404 {object} dto.DetailedErrorResponse "Artifact not found"
+// @Failure 500 {object} dto.InternalServerErrorResponse "Internal server error"
+// @Security ApiKeyAuth
+// @Router /v1/firmware/artifacts/{id} [get]
+func (h *FirmwareHandler) GetFirmware(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, dto.DetailedErrorResponse{
+			Code:    dto.ErrorCodeInvalidCredentials,
+			Message: "Unauthorized",
+			Details: "User ID not found in context",
+		})
+		return
+	}
+
+	uuidUserID, ok := userID.(uuid.UUID)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, dto.DetailedErrorResponse{
+			Code:    dto.ErrorCodeInternalError,
+			Message: "Internal server error",
+			Details: "Invalid user ID type",
+		})
+		return
+	}
+
+	artifactID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.DetailedErrorResponse{
+			Code:    dto.ErrorCodeInvalidRequest,
+			Message: "Invalid artifact ID",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	artifact, err := h.firmwareService.GetFirmware(uuidUserID, artifactID)
+	if err != nil {
+		if customErr, ok := err.(errors.CustomError); ok {
+			c.JSON(customErr.StatusCode(), dto.DetailedErrorResponse{
+				Code:    dto.ErrorCodeFromStatusCode(customErr.StatusCode()),
+				Message: customErr.Message(),
+				Details: "Failed to get firmware artifact",
+			})
+		} else {
+			c.JSON(http.StatusInternalServerError, dto.DetailedErrorResponse{
+				Code:    dto.ErrorCodeInternalError,
+				Message: "Internal server error",
+				Details: err.Error(),
+			})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, artifact)
+}
+
+// DownloadFirmware godoc
+// @Summary Download a firmware artifact
+// @Description Download the file of a firmware artifact. The SHA-256 checksum is sent in the X-Checksum-Sha256 header.
+// @Tags firmware
+// @Accept  json
+// @Produce  octet-stream
+// @Param id path string true "Artifact ID"
+// @Success 200 {file} binary "Firmware file"
+// @Failure 400 {object} dto.BadRequestErrorResponse "Invalid artifact ID"
+// @Failure 401 {object} dto.DetailedErrorResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenErrorResponse "Forbidden"
+// @Failure 404 {object} dto.DetailedErrorResponse "Artifact not found"
+// @Failure 500 {object} dto.InternalServerErrorResponse "Internal server error"
+// @Security ApiKeyAuth
+// @Router /v1/firmware/artifacts/{id}/download [get]
+func (h *FirmwareHandler) DownloadFirmware(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, dto.DetailedErrorResponse{
+			Code:    dto.ErrorCodeInvalidCredentials,
+			Message: "Unauthorized",
+			Details: "User ID not found in context",
+		})
+		return
+	}
+
+	uuidUserID, ok := userID.(uuid.UUID)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, dto.DetailedErrorResponse{
+			Code:    dto.ErrorCodeInternalError,
+			Message: "Internal server error",
+			Details: "Invalid user ID type",
+		})
+		return
+	}
+
+	artifactID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.DetailedErrorResponse{
+			Code:    dto.ErrorCodeInvalidRequest,
+			Message: "Invalid artifact ID",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	artifact, file, err := h.firmwareService.OpenFirmware(uuidUserID, artifactID)
+	if err != nil {
+		if customErr, ok := err.(errors.CustomError); ok {
+			c.JSON(customErr.StatusCode(), dto.DetailedErrorResponse{
+				Code:    dto.ErrorCodeFromStatusCode(customErr.StatusCode()),
+				Message: customErr.Message(),
+				Details: "Failed to download firmware artifact",
+			})
+		} else {
+			c.JSON(http.StatusInternalServerError, dto.DetailedErrorResponse{
+				Code:    dto.ErrorCodeInternalError,
+				Message: "Internal server error",
+				Details: err.Error(),
+			})
+		}
+		return
+	}
+
+	serveFirmware(c, artifact, file)
+}
+
+// DownloadDeviceFirmware godoc
+// @Summary Download firmware as a device
+// @Description Download the firmware a rollout told the device to install. The token comes in the url parameter of the update_firmware command and is valid while the device is updating. No authentication is required.
+// @Tags firmware
+// @Accept  json
+// @Produce  octet-stream
+// @Param token path string true "Download token"
+// @Success 200 {file} binary "Firmware file"
+// @Failure 404 {object} dto.DetailedErrorResponse "Unknown or expired token"
+// @Failure 500 {object} dto.InternalServerErrorResponse "Internal server error"
+// @Router /v1/firmware/download/{token} [get]
+func (h *FirmwareHandler) DownloadDeviceFirmware(c *gin.Context) {
+	artifact, file, err := h.firmwareService.OpenFirmwareForDevice(c.Param("token"))
+	if err != nil {
+		if customErr, ok := err.(errors.CustomError); ok {
+			c.JSON(customErr.StatusCode(), dto.DetailedErrorResponse{
+				Code:    dto.ErrorCodeFromStatusCode(customErr.StatusCode()),
+				Message: customErr.Message(),
+				Details: "Failed to download firmware",
+			})
+		} else {
+			c.JSON(http.StatusInternalServerError, dto.DetailedErrorResponse{
+				Code:    dto.ErrorCodeInternalError,
+				Message: "Internal server error",
+				Details: err.Error(),
+			})
+		}
+		return
+	}
+
+	serveFirmware(c, artifact, file)
+}
+
+// DeleteFirmware godoc
+// @Summary Delete a firmware artifact
+// @Description Delete a firmware artifact and its file. Artifacts used by a running or paused rollout cannot be deleted. Requires the admin role.
+// @Tags firmware
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Artifact ID"
+// @Success 204 "Artifact deleted"
+// @Failure 400 {object} dto.BadRequestErrorResponse "Invalid artifact ID"
+// @Failure 401 {object} dto.DetailedErrorResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenErrorResponse "Forbidden"
+// @Failure 404 {object} dto.DetailedErrorResponse "Artifact not found"
+// @Failure 409 {object} dto.ConflictErrorResponse "Artifact in use"
+// @Failure 500 {object} dto.InternalServerErrorResponse "Internal server error"
+// @Security ApiKeyAuth
+// @Router /v1/firmware/artifacts/{id} [delete]
+func (h *FirmwareHandler) DeleteFirmware(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, dto.DetailedErrorResponse{
+			Code:    dto.ErrorCodeInvalidCredentials,
+			Message: "Unauthorized",
+			Details: "User ID not found in context",
+		})
+		return
+	}
+
+	uuidUserID, ok := userID.(uuid.UUID)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, dto.DetailedErrorResponse{
+			Code:    dto.ErrorCodeInternalError,
+			Message: "Internal server error",
+			Details: "Invalid user ID type",
+		})
+		return
+	}
+
+	artifactID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.DetailedErrorResponse{
+			Code:    dto.ErrorCodeInvalidRequest,
+			Message: "Invalid artifact ID",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	err = h.firmwareService.DeleteFirmware(uuidUserID, artifactID)
+	if err != nil {
+		if customErr, ok := err.(errors.CustomError); ok {
+			c.JSON(customErr.StatusCode(), dto.DetailedErrorResponse{
+				Code:    dto.ErrorCodeFromStatusCode(customErr.StatusCode()),
+				Message: customErr.Message(),
+				Details: "Failed to delete firmware artifact",
+			})
+		} else {
+			c.JSON(http.StatusInternalServerError, dto.DetailedErrorResponse{
+				Code:    dto.ErrorCodeInternalError,
+				Message: "Internal server error",
+				Details: err.Error(),
+			})
+		}
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// GetFirmwareInventory godoc
+// @Summary Get the firmware inventory
+// @Description Count the devices the user can reach per firmware version reported in their heartbeats. Devices that never reported one are counted under an empty version.
+// @Tags firmware
+// @Accept  json
+// @Produce  json
+// @Success 200 {object} dto.FirmwareInventoryResponse "Inventory"
+// @Failure 401 {object} dto.DetailedErrorResponse "Unauthorized"
+// @Failure 500 {object} dto.InternalServerErrorResponse "Internal server error"
+// @Security ApiKeyAuth
+// @Router /v1/firmware/inventory [get]
+func (h *FirmwareHandler) GetFirmwareInventory(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, dto.DetailedErrorResponse{
+			Code:    dto.ErrorCodeInvalidCredentials,
+			Message: "Unauthorized",
+			Details: "User ID not found in context",
+		})
+		return
+	}
+
+	uuidUserID, ok := userID.(uuid.UUID)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, dto.DetailedErrorResponse{
+			Code:    dto.ErrorCodeInternalError,
+			Message: "Internal server error",
+			Details: "Invalid user ID type",
+		})
+		return
+	}
+
+	inventory, err := h.firmwareService.GetInventory(uuidUserID)
+	if err != nil {
+		if customErr, ok := err.(errors.CustomError); ok {
+			c.JSON(customErr.StatusCode(), dto.DetailedErrorResponse{
+				Code:    dto.ErrorCodeFromStatusCode(customErr.StatusCode()),
+				Message: customErr.Message(),
+				Details: "Failed to get firmware inventory",
+			})
+		} else {
+			c.JSON(http.StatusInternalServerError, dto.DetailedErrorResponse{
+				Code:    dto.ErrorCodeInternalError,
+				Message: "Internal server error",
+				Details: err.Error(),
+			})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, inventory)
+}
+
+// serveFirmware streams a firmware file, honouring range requests so that
+// devices can resume interrupted downloads.
+func serveFirmware(c *gin.Context, artifact *models.FirmwareArtifact, file io.ReadSeekCloser) {
+	defer file.Close()
+
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", artifact.FileName))
+	c.Header("Content-Type", "application/octet-stream")
+	c.Header("X-Checksum-Sha256", artifact.Checksum)
+	http.ServeContent(c.Writer, c.Request, artifact.FileName, artifact.CreatedAt, file)
+}
@@ -0,0 +1,203 @@
+package handlers
+
+import (
+	"bytes"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/dto"
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/models"
+	custom_errors "github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/utils/errors"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockFirmwareService struct {
+	mock.Mock
+}
+
+func (m *MockFirmwareService) UploadFirmware(userID uuid.UUID, orgID *uuid.UUID, req dto.FirmwareUploadRequest, fileName string, file io.Reader) (*dto.FirmwareArtifactResponse, error) {
+	args := m.Called(userID, orgID, req, fileName, file)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dto.FirmwareArtifactResponse), args.Error(1)
+}
+
+func (m *MockFirmwareService) ListFirmware(userID uuid.UUID) ([]dto.FirmwareArtifactResponse, error) {
+	args := m.Called(userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]dto.FirmwareArtifactResponse), args.Error(1)
+}
+
+func (m *MockFirmwareService) GetFirmware(userID, artifactID uuid.UUID) (*dto.FirmwareArtifactResponse, error) {
+	args := m.Called(userID, artifactID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dto.FirmwareArtifactResponse), args.Error(1)
+}
+
+func (m *MockFirmwareService) OpenFirmware(userID, artifactID uuid.UUID) (*models.FirmwareArtifact, io.ReadSeekCloser, error) {
+	args := m.Called(userID, artifactID)
+	if args.Get(0) == nil {
+		return nil, nil, args.Error(2)
+	}
+	return args.Get(0).(*models.FirmwareArtifact), args.Get(1).(io.ReadSeekCloser), args.Error(2)
+}
+
+func (m *MockFirmwareService) OpenFirmwareForDevice(token string) (*models.FirmwareArtifact, io.ReadSeekCloser, error) {
+	args := m.Called(token)
+	if args.Get(0) == nil {
+		return nil, nil, args.Error(2)
+	}
+	return args.Get(0).(*models.FirmwareArtifact), args.Get(1).(io.ReadSeekCloser), args.Error(2)
+}
+
+func (m *MockFirmwareService) DeleteFirmware(userID, artifactID uuid.UUID) error {
+	args := m.Called(userID, artifactID)
+	return args.Error(0)
+}
+
+func (m *MockFirmwareService) GetInventory(userID uuid.UUID) (*dto.FirmwareInventoryResponse, error) {
+	args := m.Called(userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dto.FirmwareInventoryResponse), args.Error(1)
+}
+
+func (m *MockFirmwareService) ReportVersion(deviceID uuid.UUID, version string) error {
+	args := m.Called(deviceID, version)
+	return args.Error(0)
+}
+
+type firmwareFile struct {
+	*strings.Reader
+}
+
+func (firmwareFile) Close() error { return nil }
+
+func TestFirmwareHandler_UploadFirmware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	userID := uuid.New()
+
+	t.Run("Success - Upload artifact", func(t *testing.T) {
+		mockFirmwareService := new(MockFirmwareService)
+		handler := NewFirmwareHandler(mockFirmwareService)
+
+		mockFirmwareService.On("UploadFirmware", userID, (*uuid.UUID)(nil), dto.FirmwareUploadRequest{Version: "1.4.0"}, "gateway.bin", mock.Anything).
+			Return(&dto.FirmwareArtifactResponse{ID: uuid.New(), Version: "1.4.0"}, nil)
+
+		body := &bytes.Buffer{}
+		writer := multipart.NewWriter(body)
+		writer.WriteField("version", "1.4.0")
+		part, _ := writer.CreateFormFile("file", "gateway.bin")
+		part.Write([]byte("image"))
+		writer.Close()
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Set("userID", userID)
+		c.Request, _ = http.NewRequest("POST", "/firmware/artifacts", body)
+		c.Request.Header.Set("Content-Type", writer.FormDataContentType())
+
+		handler.UploadFirmware(c)
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+		mockFirmwareService.AssertExpectations(t)
+	})
+
+	t.Run("Error - Missing file", func(t *testing.T) {
+		mockFirmwareService := new(MockFirmwareService)
+		handler := NewFirmwareHandler(mockFirmwareService)
+
+		body := &bytes.Buffer{}
+		writer := multipart.NewWriter(body)
+		writer.WriteField("version", "1.4.0")
+		writer.Close()
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Set("userID", userID)
+		c.Request, _ = http.NewRequest("POST", "/firmware/artifacts", body)
+		c.Request.Header.Set("Content-Type", writer.FormDataContentType())
+
+		handler.UploadFirmware(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		mockFirmwareService.AssertNotCalled(t, "UploadFirmware", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestFirmwareHandler_DownloadDeviceFirmware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	t.Run("Success - Stream file", func(t *testing.T) {
+		mockFirmwareService := new(MockFirmwareService)
+		handler := NewFirmwareHandler(mockFirmwareService)
+
+		artifact := &models.FirmwareArtifact{ID: uuid.New(), FileName: "gateway.bin", Checksum: "abc"}
+		mockFirmwareService.On("OpenFirmwareForDevice", "fw_token").Return(artifact, firmwareFile{strings.NewReader("image")}, nil)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Params = gin.Params{{Key: "token", Value: "fw_token"}}
+		c.Request, _ = http.NewRequest("GET", "/firmware/download/fw_token", nil)
+
+		handler.DownloadDeviceFirmware(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "image", w.Body.String())
+		assert.Equal(t, "abc", w.Header().Get("X-Checksum-Sha256"))
+	})
+
+	t.Run("Error - Unknown token", func(t *testing.T) {
+		mockFirmwareService := new(MockFirmwareService)
+		handler := NewFirmwareHandler(mockFirmwareService)
+
+		mockFirmwareService.On("OpenFirmwareForDevice", "fw_unknown").Return(nil, nil, custom_errors.ErrFirmwareNotFound)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Params = gin.Params{{Key: "token", Value: "fw_unknown"}}
+		c.Request, _ = http.NewRequest("GET", "/firmware/download/fw_unknown", nil)
+
+		handler.DownloadDeviceFirmware(c)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
+
+func TestFirmwareHandler_DeleteFirmware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	userID := uuid.New()
+	artifactID := uuid.New()
+
+	t.Run("Error - Artifact in use", func(t *testing.T) {
+		mockFirmwareService := new(MockFirmwareService)
+		handler := NewFirmwareHandler(mockFirmwareService)
+
+		mockFirmwareService.On("DeleteFirmware", userID, artifactID).Return(custom_errors.ErrFirmwareInUse)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Set("userID", userID)
+		c.Params = gin.Params{{Key: "id", Value: artifactID.String()}}
+		c.Request, _ = http.NewRequest("DELETE", "/firmware/artifacts/"+artifactID.String(), nil)
+
+		handler.DeleteFirmware(c)
+
+		assert.Equal(t, http.StatusConflict, w.Code)
+	})
+}
@@ -0,0 +1,410 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/dto"
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/services"
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/utils/errors"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type FirmwareRolloutHandler struct {
+	rolloutService services.FirmwareRolloutService
+}
+
+func NewFirmwareRolloutHandler(rolloutService services.FirmwareRolloutService) *FirmwareRolloutHandler {
+	return &FirmwareRolloutHandler{rolloutService: rolloutService}
+}
+
+// CreateFirmwareRollout godoc
+// @Summary Start a firmware rollout
+// @Description Roll a firmware artifact out to the devices of a group (and its subgroups) and/or matching a label selector, in waves given as cumulative percentages (10,50,100 by default). Each device gets an update_firmware command and must report the new version in a heartbeat within wave_timeout_seconds. The rollout pauses itself when more than max_failure_percent of the devices of a wave fail to update, or update and then fire an alert or stop sending heartbeats. Requires the admin role in the active organization.
+// @Tags firmware
+// @Accept  json
+// @Produce  json
+// @Param request body dto.FirmwareRolloutRequest true "Rollout"
+// @Success 201 {object} dto.FirmwareRolloutResponse "Started rollout"
+// @Failure 400 {object} dto.BadRequestErrorResponse "Invalid input"
+// @Failure 401 {object} dto.DetailedErrorResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenErrorResponse "Forbidden"
+// @Failure 404 {object} dto.DetailedErrorResponse "Artifact or group not found"
+// @Failure 500 {object} dto.InternalServerErrorResponse "Internal server error"
+// @Security ApiKeyAuth
+// @Router /v1/firmware/rollouts [post]
+func (h *FirmwareRolloutHandler) CreateFirmwareRollout(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, dto.DetailedErrorResponse{
+			Code:    dto.ErrorCodeInvalidCredentials,
+			Message: "Unauthorized",
+			Details: "User ID not found in context",
+		})
+		return
+	}
+
+	uuidUserID, ok := userID.(uuid.UUID)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, dto.DetailedErrorResponse{
+			Code:    dto.ErrorCodeInternalError,
+			Message: "Internal server error",
+			Details: "Invalid user ID type",
+		})
+		return
+	}
+
+	var req dto.FirmwareRolloutRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.DetailedErrorResponse{
+			Code:    dto.ErrorCodeInvalidRequest,
+			Message: "Invalid request format",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	rollout, err := h.rolloutService.CreateRollout(uuidUserID, activeOrganizationID(c), req)
+	if err != nil {
+		if customErr, ok := err.(errors.CustomError); ok {
+			c.JSON(customErr.StatusCode(), dto.DetailedErrorResponse{
+				Code:    dto.ErrorCodeFromStatusCode(customErr.StatusCode()),
+				Message: customErr.Message(),
+				Details: "Please check your input and try again",
+			})
+		} else {
+			c.JSON(http.StatusInternalServerError, dto.DetailedErrorResponse{
+				Code:    dto.ErrorCodeInternalError,
+				Message: "Internal server error",
+				Details: err.Error(),
+			})
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, rollout)
+}
+
+// ListFirmwareRollouts godoc
+// @Summary List firmware rollouts
+// @Description List the firmware rollouts of the user and of their organizations, newest first
+// @Tags firmware
+// @Accept  json
+// @Produce  json
+// @Success 200 {array} dto.FirmwareRolloutResponse "Rollouts"
+// @Failure 401 {object} dto.DetailedErrorResponse "Unauthorized"
+// @Failure 500 {object} dto.InternalServerErrorResponse "Internal server error"
+// @Security ApiKeyAuth
+// @Router /v1/firmware/rollouts [get]
+func (h *FirmwareRolloutHandler) ListFirmwareRollouts(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, dto.DetailedErrorResponse{
+			Code:    dto.ErrorCodeInvalidCredentials,
+			Message: "Unauthorized",
+			Details: "User ID not found in context",
+		})
+		return
+	}
+
+	uuidUserID, ok := userID.(uuid.UUID)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, dto.DetailedErrorResponse{
+			Code:    dto.ErrorCodeInternalError,
+			Message: "Internal server error",
+			Details: "Invalid user ID type",
+		})
+		return
+	}
+
+	rollouts, err := h.rolloutService.ListRollouts(uuidUserID)
+	if err != nil {
+		if customErr, ok := err.(errors.CustomError); ok {
+			c.JSON(customErr.StatusCode(), dto.DetailedErrorResponse{
+				Code:    dto.ErrorCodeFromStatusCode(customErr.StatusCode()),
+				Message: customErr.Message(),
+				Details: "Failed to list firmware rollouts",
+			})
+		} else {
+			c.JSON(http.StatusInternalServerError, dto.DetailedErrorResponse{
+				Code:    dto.ErrorCodeInternalError,
+				Message: "Internal server error",
+				Details: err.Error(),
+			})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, rollouts)
+}
+
+// GetFirmwareRollout godoc
+// @Summary Get a firmware rollout
+// @Description Get a rollout with its progress and the status of every targeted device
+// @Tags firmware
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Rollout ID"
+// @Success 200 {object} dto.FirmwareRolloutResponse "Rollout"
+// @Failure 400 {object} dto.BadRequestErrorResponse "Invalid rollout ID"
+// @Failure 401 {object} dto.DetailedErrorResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenErrorResponse "Forbidden"
+// @Failure 404 {object} dto.DetailedErrorResponse "Rollout not found"
+// @Failure 500 {object} dto.InternalServerErrorResponse "Internal server error"
+// @Security ApiKeyAuth
+// @Router /v1/firmware/rollouts/{id} [get]
+func (h *FirmwareRolloutHandler) GetFirmwareRollout(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, dto.DetailedErrorResponse{
+			Code:    dto.ErrorCodeInvalidCredentials,
+			Message: "Unauthorized",
+			Details: "User ID not found in context",
+		})
+		return
+	}
+
+	uuidUserID, ok := userID.(uuid.UUID)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, dto.DetailedErrorResponse{
+			Code:    dto.ErrorCodeInternalError,
+			Message: "Internal server error",
+			Details: "Invalid user ID type",
+		})
+		return
+	}
+
+	rolloutID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.DetailedErrorResponse{
+			Code:    dto.ErrorCodeInvalidRequest,
+			Message: "Invalid rollout ID",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	rollout, err := h.rolloutService.GetRollout(uuidUserID, rolloutID)
+	if err != nil {
+		if customErr, ok := err.(errors.CustomError); ok {
+			c.JSON(customErr.StatusCode(), dto.DetailedErrorResponse{
+				Code:    dto.ErrorCodeFromStatusCode(customErr.StatusCode()),
+				Message: customErr.Message(),
+				Details: "Failed to get firmware rollout",
+			})
+		} else {
+			c.JSON(http.StatusInternalServerError, dto.DetailedErrorResponse{
+				Code:    dto.ErrorCodeInternalError,
+				Message: "Internal server error",
+				Details: err.Error(),
+			})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, rollout)
+}
+
+// PauseFirmwareRollout godoc
+// @Summary Pause a firmware rollout
+// @Description Stop a running rollout from updating more devices. Devices already updating keep being tracked. Requires the admin role.
+// @Tags firmware
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Rollout ID"
+// @Success 200 {object} dto.FirmwareRolloutResponse "Rollout"
+// @Failure 400 {object} dto.BadRequestErrorResponse "Invalid rollout ID"
+// @Failure 401 {object} dto.DetailedErrorResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenErrorResponse "Forbidden"
+// @Failure 404 {object} dto.DetailedErrorResponse "Rollout not found"
+// @Failure 409 {object} dto.ConflictErrorResponse "Rollout is not running"
+// @Failure 500 {object} dto.InternalServerErrorResponse "Internal server error"
+// @Security ApiKeyAuth
+// @Router /v1/firmware/rollouts/{id}/pause [post]
+func (h *FirmwareRolloutHandler) PauseFirmwareRollout(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, dto.DetailedErrorResponse{
+			Code:    dto.ErrorCodeInvalidCredentials,
+			Message: "Unauthorized",
+			Details: "User ID not found in context",
+		})
+		return
+	}
+
+	uuidUserID, ok := userID.(uuid.UUID)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, dto.DetailedErrorResponse{
+			Code:    dto.ErrorCodeInternalError,
+			Message: "Internal server error",
+			Details: "Invalid user ID type",
+		})
+		return
+	}
+
+	rolloutID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.DetailedErrorResponse{
+			Code:    dto.ErrorCodeInvalidRequest,
+			Message: "Invalid rollout ID",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	rollout, err := h.rolloutService.PauseRollout(uuidUserID, rolloutID)
+	if err != nil {
+		if customErr, ok := err.(errors.CustomError); ok {
+			c.JSON(customErr.StatusCode(), dto.DetailedErrorResponse{
+				Code:    dto.ErrorCodeFromStatusCode(customErr.StatusCode()),
+				Message: customErr.Message(),
+				Details: "Failed to pause firmware rollout",
+			})
+		} else {
+			c.JSON(http.StatusInternalServerError, dto.DetailedErrorResponse{
+				Code:    dto.ErrorCodeInternalError,
+				Message: "Internal server error",
+				Details: err.Error(),
+			})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, rollout)
+}
+
+// ResumeFirmwareRollout godoc
+// @Summary Resume a firmware rollout
+// @Description Resume a paused rollout. The failed devices of the current wave are retried and the updated ones are watched again from now on. Requires the admin role.
+// @Tags firmware
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Rollout ID"
+// @Success 200 {object} dto.FirmwareRolloutResponse "Rollout"
+// @Failure 400 {object} dto.BadRequestErrorResponse "Invalid rollout ID"
+// @Failure 401 {object} dto.DetailedErrorResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenErrorResponse "Forbidden"
+// @Failure 404 {object} dto.DetailedErrorResponse "Rollout not found"
+// @Failure 409 {object} dto.ConflictErrorResponse "Rollout is not paused"
+// @Failure 500 {object} dto.InternalServerErrorResponse "Internal server error"
+// @Security ApiKeyAuth
+// @Router /v1/firmware/rollouts/{id}/resume [post]
+func (h *FirmwareRolloutHandler) ResumeFirmwareRollout(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, dto.DetailedErrorResponse{
+			Code:    dto.ErrorCodeInvalidCredentials,
+			Message: "Unauthorized",
+			Details: "User ID not found in context",
+		})
+		return
+	}
+
+	uuidUserID, ok := userID.(uuid.UUID)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, dto.DetailedErrorResponse{
+			Code:    dto.ErrorCodeInternalError,
+			Message: "Internal server error",
+			Details: "Invalid user ID type",
+		})
+		return
+	}
+
+	rolloutID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.DetailedErrorResponse{
+			Code:    dto.ErrorCodeInvalidRequest,
+			Message: "Invalid rollout ID",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	rollout, err := h.rolloutService.ResumeRollout(uuidUserID, rolloutID)
+	if err != nil {
+		if customErr, ok := err.(errors.CustomError); ok {
+			c.JSON(customErr.StatusCode(), dto.DetailedErrorResponse{
+				Code:    dto.ErrorCodeFromStatusCode(customErr.StatusCode()),
+				Message: customErr.Message(),
+				Details: "Failed to resume firmware rollout",
+			})
+		} else {
+			c.JSON(http.StatusInternalServerError, dto.DetailedErrorResponse{
+				Code:    dto.ErrorCodeInternalError,
+				Message: "Internal server error",
+				Details: err.Error(),
+			})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, rollout)
+}
+
+// CancelFirmwareRollout godoc
+// @Summary Cancel a firmware rollout
+// @Description Stop a running or paused rollout for good. Devices already updated are not rolled back. Requires the admin role.
+// @Tags firmware
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Rollout ID"
+// @Success 200 {object} dto.FirmwareRolloutResponse "Rollout"
+// @Failure 400 {object} dto.BadRequestErrorResponse "Invalid rollout ID"
+// @Failure 401 {object} dto.DetailedErrorResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenErrorResponse "Forbidden"
+// @Failure 404 {object} dto.DetailedErrorResponse "Rollout not found"
+// @Failure 409 {object} dto.ConflictErrorResponse "Rollout already finished"
+// @Failure 500 {object} dto.InternalServerErrorResponse "Internal server error"
+// @Security ApiKeyAuth
+// @Router /v1/firmware/rollouts/{id} [delete]
+func (h *FirmwareRolloutHandler) CancelFirmwareRollout(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, dto.DetailedErrorResponse{
+			Code:    dto.ErrorCodeInvalidCredentials,
+			Message: "Unauthorized",
+			Details: "User ID not found in context",
+		})
+		return
+	}
+
+	uuidUserID, ok := userID.(uuid.UUID)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, dto.DetailedErrorResponse{
+			Code:    dto.ErrorCodeInternalError,
+			Message: "Internal server error",
+			Details: "Invalid user ID type",
+		})
+		return
+	}
+
+	rolloutID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.DetailedErrorResponse{
+			Code:    dto.ErrorCodeInvalidRequest,
+			Message: "Invalid rollout ID",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	rollout, err := h.rolloutService.CancelRollout(uuidUserID, rolloutID)
+	if err != nil {
+		if customErr, ok := err.(errors.CustomError); ok {
+			c.JSON(customErr.StatusCode(), dto.DetailedErrorResponse{
+				Code:    dto.ErrorCodeFromStatusCode(customErr.StatusCode()),
+				Message: customErr.Message(),
+				Details: "Failed to cancel firmware rollout",
+			})
+		} else {
+			c.JSON(http.StatusInternalServerError, dto.DetailedErrorResponse{
+				Code:    dto.ErrorCodeInternalError,
+				Message: "Internal server error",
+				Details: err.Error(),
+			})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, rollout)
+}
@@ -0,0 +1,145 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/dto"
+	custom_errors "github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/utils/errors"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockFirmwareRolloutService struct {
+	mock.Mock
+}
+
+func (m *MockFirmwareRolloutService) CreateRollout(userID uuid.UUID, orgID *uuid.UUID, req dto.FirmwareRolloutRequest) (*dto.FirmwareRolloutResponse, error) {
+	args := m.Called(userID, orgID, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dto.FirmwareRolloutResponse), args.Error(1)
+}
+
+func (m *MockFirmwareRolloutService) ListRollouts(userID uuid.UUID) ([]dto.FirmwareRolloutResponse, error) {
+	args := m.Called(userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]dto.FirmwareRolloutResponse), args.Error(1)
+}
+
+func (m *MockFirmwareRolloutService) GetRollout(userID, rolloutID uuid.UUID) (*dto.FirmwareRolloutResponse, error) {
+	args := m.Called(userID, rolloutID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dto.FirmwareRolloutResponse), args.Error(1)
+}
+
+func (m *MockFirmwareRolloutService) PauseRollout(userID, rolloutID uuid.UUID) (*dto.FirmwareRolloutResponse, error) {
+	args := m.Called(userID, rolloutID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dto.FirmwareRolloutResponse), args.Error(1)
+}
+
+func (m *MockFirmwareRolloutService) ResumeRollout(userID, rolloutID uuid.UUID) (*dto.FirmwareRolloutResponse, error) {
+	args := m.Called(userID, rolloutID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dto.FirmwareRolloutResponse), args.Error(1)
+}
+
+func (m *MockFirmwareRolloutService) CancelRollout(userID, rolloutID uuid.UUID) (*dto.FirmwareRolloutResponse, error) {
+	args := m.Called(userID, rolloutID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dto.FirmwareRolloutResponse), args.Error(1)
+}
+
+func (m *MockFirmwareRolloutService) ProcessRollouts() (int, int, error) {
+	args := m.Called()
+	return args.Int(0), args.Int(1), args.Error(2)
+}
+
+func TestFirmwareRolloutHandler_CreateFirmwareRollout(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	userID := uuid.New()
+	artifactID := uuid.New()
+
+	t.Run("Success - Create rollout", func(t *testing.T) {
+		mockRolloutService := new(MockFirmwareRolloutService)
+		handler := NewFirmwareRolloutHandler(mockRolloutService)
+
+		req := dto.FirmwareRolloutRequest{ArtifactID: artifactID, LabelSelector: "site=lab", Waves: []int{25, 100}}
+		mockRolloutService.On("CreateRollout", userID, (*uuid.UUID)(nil), req).Return(&dto.FirmwareRolloutResponse{ID: uuid.New(), Status: "running", CurrentWave: 1}, nil)
+
+		body, _ := json.Marshal(req)
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Set("userID", userID)
+		c.Request, _ = http.NewRequest("POST", "/firmware/rollouts", bytes.NewBuffer(body))
+		c.Request.Header.Set("Content-Type", "application/json")
+
+		handler.CreateFirmwareRollout(c)
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+
+		var response dto.FirmwareRolloutResponse
+		json.Unmarshal(w.Body.Bytes(), &response)
+
+		assert.Equal(t, "running", response.Status)
+		mockRolloutService.AssertExpectations(t)
+	})
+
+	t.Run("Error - Missing artifact", func(t *testing.T) {
+		mockRolloutService := new(MockFirmwareRolloutService)
+		handler := NewFirmwareRolloutHandler(mockRolloutService)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Set("userID", userID)
+		c.Request, _ = http.NewRequest("POST", "/firmware/rollouts", bytes.NewBufferString(`{"label_selector":"site=lab"}`))
+		c.Request.Header.Set("Content-Type", "application/json")
+
+		handler.CreateFirmwareRollout(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		mockRolloutService.AssertNotCalled(t, "CreateRollout", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestFirmwareRolloutHandler_ResumeFirmwareRollout(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	userID := uuid.New()
+	rolloutID := uuid.New()
+
+	t.Run("Error - Rollout not paused", func(t *testing.T) {
+		mockRolloutService := new(MockFirmwareRolloutService)
+		handler := NewFirmwareRolloutHandler(mockRolloutService)
+
+		mockRolloutService.On("ResumeRollout", userID, rolloutID).Return(nil, custom_errors.ErrRolloutNotPaused)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Set("userID", userID)
+		c.Params = gin.Params{{Key: "id", Value: rolloutID.String()}}
+		c.Request, _ = http.NewRequest("POST", "/firmware/rollouts/"+rolloutID.String()+"/resume", nil)
+
+		handler.ResumeFirmwareRollout(c)
+
+		assert.Equal(t, http.StatusConflict, w.Code)
+	})
+}
@@ -1,12 +1,18 @@
 package handlers
 
 import (
+	"encoding/csv"
+	"encoding/json"
 	"net/http"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/dto"
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/models"
 	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/services"
 	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/utils/errors"
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/utils/logger"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 )
@@ -177,4 +183,250 @@ func (h *HeartbeatHandler) GetLatestDeviceHeartbeat(c *gin.Context) {
     }
 
     c.JSON(http.StatusOK, heartbeat)
-}
\ No newline at end of file
+}
+
+const (
+    exportFormatCSV    = "csv"
+    exportFormatNDJSON = "ndjson"
+
+    // exportFlushEvery controls how many rows are buffered before being
+    // pushed to the client during a streaming export.
+    exportFlushEvery = 500
+)
+
+// ExportDeviceHeartbeats godoc
+// @Summary Export device heartbeats
+// @Description Stream the heartbeat history of a device as CSV or NDJSON. The format is taken from the format parameter or the Accept header and defaults to CSV.
+// @Tags heartbeats
+// @Produce  text/csv
+// @Produce  application/x-ndjson
+// @Param id path string true "Device ID"
+// @Param start query string false "Start time (RFC3339 format)" default(24 hours ago)
+// @Param end query string false "End time (RFC3339 format)" default(now)
+// @Param format query string false "Export format: csv or ndjson"
+// @Success 200 {array} dto.HeartbeatResponse "Heartbeat rows"
+// @Failure 400 {object} dto.BadRequestErrorResponse "Invalid device ID, time or format"
+// @Failure 401 {object} dto.DetailedErrorResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenErrorResponse "Forbidden"
+// @Failure 404 {object} dto.DetailedErrorResponse "Device not found"
+// @Failure 500 {object} dto.InternalServerErrorResponse "Internal server error"
+// @Security ApiKeyAuth
+// @Router /v1/devices/{id}/heartbeats/export [get]
+func (h *HeartbeatHandler) ExportDeviceHeartbeats(c *gin.Context) {
+    userID, exists := c.Get("userID")
+    if !exists {
+        c.JSON(http.StatusUnauthorized, dto.DetailedErrorResponse{
+            Code:    dto.ErrorCodeInvalidCredentials,
+            Message: "Unauthorized",
+            Details: "User ID not found in context",
+        })
+        return
+    }
+
+    uuidUserID, ok := userID.(uuid.UUID)
+    if !ok {
+        c.JSON(http.StatusInternalServerError, dto.DetailedErrorResponse{
+            Code:    dto.ErrorCodeInternalError,
+            Message: "Internal server error",
+            Details: "Invalid user ID type",
+        })
+        return
+    }
+
+    deviceID, err := uuid.Parse(c.Param("id"))
+    if err != nil {
+        c.JSON(http.StatusBadRequest, dto.DetailedErrorResponse{
+            Code:    dto.ErrorCodeInvalidRequest,
+            Message: "Invalid device ID",
+            Details: err.Error(),
+        })
+        return
+    }
+
+    h.streamHeartbeats(c, uuidUserID, &deviceID)
+}
+
+// ExportHeartbeats godoc
+// @Summary Export heartbeats of all devices
+// @Description Stream the heartbeat history of every device of the authenticated user as CSV or NDJSON. The format is taken from the format parameter or the Accept header and defaults to CSV.
+// @Tags heartbeats
+// @Produce  text/csv
+// @Produce  application/x-ndjson
+// @Param start query string false "Start time (RFC3339 format)" default(24 hours ago)
+// @Param end query string false "End time (RFC3339 format)" default(now)
+// @Param format query string false "Export format: csv or ndjson"
+// @Success 200 {array} dto.HeartbeatResponse "Heartbeat rows"
+// @Failure 400 {object} dto.BadRequestErrorResponse "Invalid time or format"
+// @Failure 401 {object} dto.DetailedErrorResponse "Unauthorized"
+// @Failure 500 {object} dto.InternalServerErrorResponse "Internal server error"
+// @Security ApiKeyAuth
+// @Router /v1/heartbeats/export [get]
+func (h *HeartbeatHandler) ExportHeartbeats(c *gin.Context) {
+    userID, exists := c.Get("userID")
+    if !exists {
+        c.JSON(http.StatusUnauthorized, dto.DetailedErrorResponse{
+            Code:    dto.ErrorCodeInvalidCredentials,
+            Message: "Unauthorized",
+            Details: "User ID not found in context",
+        })
+        return
+    }
+
+    uuidUserID, ok := userID.(uuid.UUID)
+    if !ok {
+        c.JSON(http.StatusInternalServerError, dto.DetailedErrorResponse{
+            Code:    dto.ErrorCodeInternalError,
+            Message: "Internal server error",
+            Details: "Invalid user ID type",
+        })
+        return
+    }
+
+    h.streamHeartbeats(c, uuidUserID, nil)
+}
+
+func (h *HeartbeatHandler) streamHeartbeats(c *gin.Context, userID uuid.UUID, deviceID *uuid.UUID) {
+    format, ok := heartbeatExportFormat(c)
+    if !ok {
+        c.JSON(http.StatusBadRequest, dto.DetailedErrorResponse{
+            Code:    dto.ErrorCodeInvalidRequest,
+            Message: "Invalid export format",
+            Details: "Supported formats are csv and ndjson",
+        })
+        return
+    }
+
+    startTimeStr := c.DefaultQuery("start", time.Now().Add(-24*time.Hour).Format(time.RFC3339))
+    endTimeStr := c.DefaultQuery("end", time.Now().Format(time.RFC3339))
+
+    startTime, err := time.Parse(time.RFC3339, startTimeStr)
+    if err != nil {
+        c.JSON(http.StatusBadRequest, dto.DetailedErrorResponse{
+            Code:    dto.ErrorCodeInvalidRequest,
+            Message: "Invalid start time format",
+            Details: "Use RFC3339 format (e.g., 2023-01-01T00:00:00Z)",
+        })
+        return
+    }
+
+    endTime, err := time.Parse(time.RFC3339, endTimeStr)
+    if err != nil {
+        c.JSON(http.StatusBadRequest, dto.DetailedErrorResponse{
+            Code:    dto.ErrorCodeInvalidRequest,
+            Message: "Invalid end time format",
+            Details: "Use RFC3339 format (e.g., 2023-01-01T00:00:00Z)",
+        })
+        return
+    }
+
+    // Headers are only written once the first row is ready, so access and
+    // validation errors can still be returned as regular JSON errors.
+    started := false
+    rows := 0
+    var csvWriter *csv.Writer
+    encoder := json.NewEncoder(c.Writer)
+
+    begin := func() {
+        started = true
+        if format == exportFormatCSV {
+            c.Header("Content-Type", "text/csv; charset=utf-8")
+            c.Header("Content-Disposition", "attachment; filename=heartbeats.csv")
+        } else {
+            c.Header("Content-Type", "application/x-ndjson")
+            c.Header("Content-Disposition", "attachment; filename=heartbeats.ndjson")
+        }
+        c.Status(http.StatusOK)
+
+        if format == exportFormatCSV {
+            csvWriter = csv.NewWriter(c.Writer)
+            _ = csvWriter.Write([]string{
+                "id", "device_id", "cpu", "ram", "disk_free", "temperature",
+                "latency", "connectivity", "boot_time", "created_at",
+            })
+        }
+    }
+
+    write := func(heartbeat *models.Heartbeat) error {
+        if !started {
+            begin()
+        }
+
+        if format == exportFormatCSV {
+            if err := csvWriter.Write(heartbeatCSVRecord(heartbeat)); err != nil {
+                return err
+            }
+        } else if err := encoder.Encode(heartbeat); err != nil {
+            return err
+        }
+
+        rows++
+        if rows%exportFlushEvery == 0 {
+            if csvWriter != nil {
+                csvWriter.Flush()
+                if err := csvWriter.Error(); err != nil {
+                    return err
+                }
+            }
+            c.Writer.Flush()
+        }
+        return nil
+    }
+
+    err = h.heartbeatService.ExportHeartbeats(userID, deviceID, startTime, endTime, write)
+    if err != nil && !started {
+        if customErr, ok := err.(errors.CustomError); ok {
+            c.JSON(customErr.StatusCode(), dto.DetailedErrorResponse{
+                Code:    dto.ErrorCodeFromStatusCode(customErr.StatusCode()),
+                Message: customErr.Message(),
+                Details: "Failed to export heartbeats",
+            })
+        } else {
+            c.JSON(http.StatusInternalServerError, dto.DetailedErrorResponse{
+                Code:    dto.ErrorCodeInternalError,
+                Message: "Internal server error",
+                Details: err.Error(),
+            })
+        }
+        return
+    }
+    if err != nil {
+        logger.Logger.Error("Heartbeat export aborted", "user_id", userID, "rows", rows, "error", err)
+        return
+    }
+
+    if !started {
+        begin()
+    }
+    if csvWriter != nil {
+        csvWriter.Flush()
+    }
+}
+
+// heartbeatExportFormat resolves the export format from the format query
+// parameter, then the Accept header, defaulting to CSV.
+func heartbeatExportFormat(c *gin.Context) (string, bool) {
+    if format := strings.ToLower(c.Query("format")); format != "" {
+        return format, format == exportFormatCSV || format == exportFormatNDJSON
+    }
+
+    accept := c.GetHeader("Accept")
+    if strings.Contains(accept, "application/x-ndjson") || strings.Contains(accept, "application/jsonl") {
+        return exportFormatNDJSON, true
+    }
+    return exportFormatCSV, true
+}
+
+func heartbeatCSVRecord(heartbeat *models.Heartbeat) []string {
+    return []string{
+        heartbeat.ID.String(),
+        heartbeat.DeviceID.String(),
+        strconv.FormatFloat(heartbeat.CPU, 'f', -1, 64),
+        strconv.FormatFloat(heartbeat.RAM, 'f', -1, 64),
+        strconv.FormatFloat(heartbeat.DiskFree, 'f', -1, 64),
+        strconv.FormatFloat(heartbeat.Temperature, 'f', -1, 64),
+        strconv.Itoa(heartbeat.Latency),
+        strconv.Itoa(heartbeat.Connectivity),
+        heartbeat.BootTime.UTC().Format(time.RFC3339),
+        heartbeat.CreatedAt.UTC().Format(time.RFC3339Nano),
+    }
+}
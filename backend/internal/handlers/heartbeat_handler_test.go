@@ -1,9 +1,11 @@
 package handlers
 
 import (
+	"encoding/csv"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
@@ -44,6 +46,18 @@ func (m *MockHeartbeatService) GetLatestDeviceHeartbeat(userID, deviceID uuid.UU
 	return args.Get(0).(*models.Heartbeat), args.Error(1)
 }
 
+func (m *MockHeartbeatService) ExportHeartbeats(userID uuid.UUID, deviceID *uuid.UUID, startTime, endTime time.Time, fn func(*models.Heartbeat) error) error {
+	args := m.Called(userID, deviceID, startTime, endTime)
+	if rows, ok := args.Get(0).([]models.Heartbeat); ok {
+		for i := range rows {
+			if err := fn(&rows[i]); err != nil {
+				return err
+			}
+		}
+	}
+	return args.Error(1)
+}
+
 func TestHeartbeatHandler_GetDeviceHeartbeats(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
@@ -585,6 +599,142 @@ func TestHeartbeatHandler_GetLatestDeviceHeartbeat(t *testing.T) {
 	})
 }
 
+func TestHeartbeatHandler_ExportHeartbeats(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	userID := uuid.New()
+	deviceID := uuid.New()
+	rows := []models.Heartbeat{
+		{ID: uuid.New(), DeviceID: deviceID, CPU: 45.5, Connectivity: 1, CreatedAt: time.Now().UTC().Add(-time.Minute)},
+		{ID: uuid.New(), DeviceID: deviceID, CPU: 50, Connectivity: 0, CreatedAt: time.Now().UTC()},
+	}
+
+	t.Run("Success - CSV export for a device", func(t *testing.T) {
+		mockHeartbeatService := new(MockHeartbeatService)
+		handler := NewHeartbeatHandler(mockHeartbeatService)
+
+		mockHeartbeatService.On("ExportHeartbeats", userID, &deviceID, mock.AnythingOfType("time.Time"), mock.AnythingOfType("time.Time")).Return(rows, nil)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Set("userID", userID)
+		c.Params = gin.Params{gin.Param{Key: "id", Value: deviceID.String()}}
+		c.Request, _ = http.NewRequest("GET", "/devices/"+deviceID.String()+"/heartbeats/export", nil)
+
+		handler.ExportDeviceHeartbeats(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.True(t, strings.HasPrefix(w.Header().Get("Content-Type"), "text/csv"))
+
+		records, err := csv.NewReader(w.Body).ReadAll()
+		assert.NoError(t, err)
+		assert.Len(t, records, 3)
+		assert.Equal(t, "id", records[0][0])
+		assert.Equal(t, rows[0].ID.String(), records[1][0])
+		assert.Equal(t, "45.5", records[1][2])
+		mockHeartbeatService.AssertExpectations(t)
+	})
+
+	t.Run("Success - NDJSON export for all devices", func(t *testing.T) {
+		mockHeartbeatService := new(MockHeartbeatService)
+		handler := NewHeartbeatHandler(mockHeartbeatService)
+
+		mockHeartbeatService.On("ExportHeartbeats", userID, (*uuid.UUID)(nil), mock.AnythingOfType("time.Time"), mock.AnythingOfType("time.Time")).Return(rows, nil)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Set("userID", userID)
+		c.Request, _ = http.NewRequest("GET", "/heartbeats/export", nil)
+		c.Request.Header.Set("Accept", "application/x-ndjson")
+
+		handler.ExportHeartbeats(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "application/x-ndjson", w.Header().Get("Content-Type"))
+
+		lines := strings.Split(strings.TrimSpace(w.Body.String()), "\n")
+		assert.Len(t, lines, 2)
+		var first models.Heartbeat
+		assert.NoError(t, json.Unmarshal([]byte(lines[0]), &first))
+		assert.Equal(t, rows[0].ID, first.ID)
+	})
+
+	t.Run("Success - Empty export still returns a CSV header", func(t *testing.T) {
+		mockHeartbeatService := new(MockHeartbeatService)
+		handler := NewHeartbeatHandler(mockHeartbeatService)
+
+		mockHeartbeatService.On("ExportHeartbeats", userID, (*uuid.UUID)(nil), mock.AnythingOfType("time.Time"), mock.AnythingOfType("time.Time")).Return([]models.Heartbeat{}, nil)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Set("userID", userID)
+		c.Request, _ = http.NewRequest("GET", "/heartbeats/export?format=csv", nil)
+
+		handler.ExportHeartbeats(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		records, err := csv.NewReader(w.Body).ReadAll()
+		assert.NoError(t, err)
+		assert.Len(t, records, 1)
+	})
+
+	t.Run("Error - Invalid format", func(t *testing.T) {
+		mockHeartbeatService := new(MockHeartbeatService)
+		handler := NewHeartbeatHandler(mockHeartbeatService)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Set("userID", userID)
+		c.Request, _ = http.NewRequest("GET", "/heartbeats/export?format=xml", nil)
+
+		handler.ExportHeartbeats(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		var response dto.DetailedErrorResponse
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.Equal(t, "Invalid export format", response.Message)
+	})
+
+	t.Run("Error - Forbidden before streaming starts", func(t *testing.T) {
+		mockHeartbeatService := new(MockHeartbeatService)
+		handler := NewHeartbeatHandler(mockHeartbeatService)
+
+		mockHeartbeatService.On("ExportHeartbeats", userID, &deviceID, mock.AnythingOfType("time.Time"), mock.AnythingOfType("time.Time")).Return(nil, custom_errors.ErrForbidden)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Set("userID", userID)
+		c.Params = gin.Params{gin.Param{Key: "id", Value: deviceID.String()}}
+		c.Request, _ = http.NewRequest("GET", "/devices/"+deviceID.String()+"/heartbeats/export", nil)
+
+		handler.ExportDeviceHeartbeats(c)
+
+		assert.Equal(t, http.StatusForbidden, w.Code)
+
+		var response dto.DetailedErrorResponse
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.Equal(t, dto.ErrorCodeForbidden, response.Code)
+	})
+
+	t.Run("Error - Invalid device ID", func(t *testing.T) {
+		mockHeartbeatService := new(MockHeartbeatService)
+		handler := NewHeartbeatHandler(mockHeartbeatService)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Set("userID", userID)
+		c.Params = gin.Params{gin.Param{Key: "id", Value: "invalid-uuid"}}
+		c.Request, _ = http.NewRequest("GET", "/devices/invalid-uuid/heartbeats/export", nil)
+
+		handler.ExportDeviceHeartbeats(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
 func TestHeartbeatHandler_EdgeCases(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
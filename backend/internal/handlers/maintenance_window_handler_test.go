@@ -0,0 +1,175 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/dto"
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/models"
+	custom_errors "github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/utils/errors"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockMaintenanceWindowService struct {
+	mock.Mock
+}
+
+func (m *MockMaintenanceWindowService) CreateWindow(userID uuid.UUID, orgID *uuid.UUID, req dto.MaintenanceWindowRequest) (*dto.MaintenanceWindowResponse, error) {
+	args := m.Called(userID, orgID, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dto.MaintenanceWindowResponse), args.Error(1)
+}
+
+func (m *MockMaintenanceWindowService) ListWindows(userID uuid.UUID, status string) ([]dto.MaintenanceWindowResponse, error) {
+	args := m.Called(userID, status)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]dto.MaintenanceWindowResponse), args.Error(1)
+}
+
+func (m *MockMaintenanceWindowService) GetWindow(userID, windowID uuid.UUID) (*dto.MaintenanceWindowResponse, error) {
+	args := m.Called(userID, windowID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dto.MaintenanceWindowResponse), args.Error(1)
+}
+
+func (m *MockMaintenanceWindowService) DeleteWindow(userID, windowID uuid.UUID) error {
+	args := m.Called(userID, windowID)
+	return args.Error(0)
+}
+
+func (m *MockMaintenanceWindowService) ListSuppressedAlerts(userID, windowID uuid.UUID) ([]dto.SuppressedAlertResponse, error) {
+	args := m.Called(userID, windowID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]dto.SuppressedAlertResponse), args.Error(1)
+}
+
+func (m *MockMaintenanceWindowService) ActiveWindow(device *models.Device, now time.Time) (*models.MaintenanceWindow, error) {
+	args := m.Called(device, now)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.MaintenanceWindow), args.Error(1)
+}
+
+func (m *MockMaintenanceWindowService) CoveringWindows(device *models.Device, from, to time.Time) ([]models.MaintenanceWindow, error) {
+	args := m.Called(device, from, to)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.MaintenanceWindow), args.Error(1)
+}
+
+func (m *MockMaintenanceWindowService) RecordSuppressedAlert(window *models.MaintenanceWindow, notification models.Notification, device *models.Device, triggeredValue float64) error {
+	args := m.Called(window, notification, device, triggeredValue)
+	return args.Error(0)
+}
+
+func TestMaintenanceWindowHandler_CreateMaintenanceWindow(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	userID := uuid.New()
+
+	t.Run("Success - Create recurring window", func(t *testing.T) {
+		mockWindowService := new(MockMaintenanceWindowService)
+		handler := NewMaintenanceWindowHandler(mockWindowService)
+
+		mockWindowService.On("CreateWindow", userID, (*uuid.UUID)(nil), mock.MatchedBy(func(req dto.MaintenanceWindowRequest) bool {
+			return req.Schedule == "0 2 * * 0" && req.DurationMinutes == 120
+		})).Return(&dto.MaintenanceWindowResponse{ID: uuid.New(), Name: "Weekly patching"}, nil)
+
+		body := `{"name":"Weekly patching","starts_at":"2025-10-01T00:00:00Z","schedule":"0 2 * * 0","duration_minutes":120,"locations":["Lab"]}`
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Set("userID", userID)
+		c.Request, _ = http.NewRequest("POST", "/maintenance-windows", bytes.NewBufferString(body))
+		c.Request.Header.Set("Content-Type", "application/json")
+
+		handler.CreateMaintenanceWindow(c)
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+		mockWindowService.AssertExpectations(t)
+	})
+
+	t.Run("Error - Missing starts_at", func(t *testing.T) {
+		mockWindowService := new(MockMaintenanceWindowService)
+		handler := NewMaintenanceWindowHandler(mockWindowService)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Set("userID", userID)
+		c.Request, _ = http.NewRequest("POST", "/maintenance-windows", bytes.NewBufferString(`{"name":"Patching"}`))
+		c.Request.Header.Set("Content-Type", "application/json")
+
+		handler.CreateMaintenanceWindow(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		mockWindowService.AssertNotCalled(t, "CreateWindow", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestMaintenanceWindowHandler_ListMaintenanceWindows(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	userID := uuid.New()
+
+	t.Run("Success - Filter active windows", func(t *testing.T) {
+		mockWindowService := new(MockMaintenanceWindowService)
+		handler := NewMaintenanceWindowHandler(mockWindowService)
+
+		mockWindowService.On("ListWindows", userID, "active").Return([]dto.MaintenanceWindowResponse{{ID: uuid.New(), Active: true}}, nil)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Set("userID", userID)
+		c.Request, _ = http.NewRequest("GET", "/maintenance-windows?status=active", nil)
+
+		handler.ListMaintenanceWindows(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response []dto.MaintenanceWindowResponse
+		json.Unmarshal(w.Body.Bytes(), &response)
+
+		assert.Len(t, response, 1)
+		assert.True(t, response[0].Active)
+	})
+}
+
+func TestMaintenanceWindowHandler_DeleteMaintenanceWindow(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	userID := uuid.New()
+	windowID := uuid.New()
+
+	t.Run("Error - Window not found", func(t *testing.T) {
+		mockWindowService := new(MockMaintenanceWindowService)
+		handler := NewMaintenanceWindowHandler(mockWindowService)
+
+		mockWindowService.On("DeleteWindow", userID, windowID).Return(custom_errors.ErrMaintenanceWindowNotFound)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Set("userID", userID)
+		c.Params = gin.Params{{Key: "id", Value: windowID.String()}}
+		c.Request, _ = http.NewRequest("DELETE", "/maintenance-windows/"+windowID.String(), nil)
+
+		handler.DeleteMaintenanceWindow(c)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
@@ -0,0 +1,186 @@
+package handlers
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/dto"
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/models"
+	custom_errors "github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/utils/errors"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockMuteService struct {
+	mock.Mock
+}
+
+func (m *MockMuteService) CreateMute(userID uuid.UUID, req dto.MuteRequest) (*dto.MuteResponse, error) {
+	args := m.Called(userID, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dto.MuteResponse), args.Error(1)
+}
+
+func (m *MockMuteService) ListMutes(userID uuid.UUID, status string) ([]dto.MuteResponse, error) {
+	args := m.Called(userID, status)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]dto.MuteResponse), args.Error(1)
+}
+
+func (m *MockMuteService) CancelMute(userID, muteID uuid.UUID) (*dto.MuteResponse, error) {
+	args := m.Called(userID, muteID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dto.MuteResponse), args.Error(1)
+}
+
+func (m *MockMuteService) ActiveMutes(device *models.Device, now time.Time) ([]models.Mute, error) {
+	args := m.Called(device, now)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Mute), args.Error(1)
+}
+
+func (m *MockMuteService) CoveringMutes(device *models.Device, from, to time.Time) ([]models.Mute, error) {
+	args := m.Called(device, from, to)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Mute), args.Error(1)
+}
+
+func TestMuteHandler_CreateMute(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	userID := uuid.New()
+	notificationID := uuid.New()
+	deviceID := uuid.New()
+
+	t.Run("Success - Rule muted on a device for 2 hours", func(t *testing.T) {
+		mockService := new(MockMuteService)
+		handler := NewMuteHandler(mockService)
+
+		mockService.On("CreateMute", userID, dto.MuteRequest{NotificationID: &notificationID, DeviceID: &deviceID, DurationMinutes: 120}).
+			Return(&dto.MuteResponse{ID: uuid.New(), NotificationID: &notificationID, DeviceID: &deviceID, Active: true}, nil)
+
+		body := `{"notification_id":"` + notificationID.String() + `","device_id":"` + deviceID.String() + `","duration_minutes":120}`
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Set("userID", userID)
+		c.Request, _ = http.NewRequest("POST", "/mutes", bytes.NewBufferString(body))
+		c.Request.Header.Set("Content-Type", "application/json")
+
+		handler.CreateMute(c)
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("Error - Rule not found", func(t *testing.T) {
+		mockService := new(MockMuteService)
+		handler := NewMuteHandler(mockService)
+
+		mockService.On("CreateMute", userID, mock.Anything).Return(nil, custom_errors.ErrNotificationNotFound)
+
+		body := `{"notification_id":"` + notificationID.String() + `","duration_minutes":60}`
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Set("userID", userID)
+		c.Request, _ = http.NewRequest("POST", "/mutes", bytes.NewBufferString(body))
+		c.Request.Header.Set("Content-Type", "application/json")
+
+		handler.CreateMute(c)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
+
+func TestMuteHandler_ListMutes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	userID := uuid.New()
+
+	t.Run("Success - Ended mutes", func(t *testing.T) {
+		mockService := new(MockMuteService)
+		handler := NewMuteHandler(mockService)
+
+		mockService.On("ListMutes", userID, "ended").Return([]dto.MuteResponse{{ID: uuid.New()}}, nil)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Set("userID", userID)
+		c.Request, _ = http.NewRequest("GET", "/mutes?status=ended", nil)
+
+		handler.ListMutes(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		mockService.AssertExpectations(t)
+	})
+}
+
+func TestMuteHandler_CancelMute(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	userID := uuid.New()
+	muteID := uuid.New()
+
+	t.Run("Success - Mute cancelled", func(t *testing.T) {
+		mockService := new(MockMuteService)
+		handler := NewMuteHandler(mockService)
+
+		mockService.On("CancelMute", userID, muteID).Return(&dto.MuteResponse{ID: muteID}, nil)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Set("userID", userID)
+		c.Params = gin.Params{{Key: "id", Value: muteID.String()}}
+		c.Request, _ = http.NewRequest("DELETE", "/mutes/"+muteID.String(), nil)
+
+		handler.CancelMute(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("Error - Mute already ended", func(t *testing.T) {
+		mockService := new(MockMuteService)
+		handler := NewMuteHandler(mockService)
+
+		mockService.On("CancelMute", userID, muteID).Return(nil, custom_errors.ErrMuteEnded)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Set("userID", userID)
+		c.Params = gin.Params{{Key: "id", Value: muteID.String()}}
+		c.Request, _ = http.NewRequest("DELETE", "/mutes/"+muteID.String(), nil)
+
+		handler.CancelMute(c)
+
+		assert.Equal(t, http.StatusConflict, w.Code)
+	})
+
+	t.Run("Error - Invalid mute ID", func(t *testing.T) {
+		mockService := new(MockMuteService)
+		handler := NewMuteHandler(mockService)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Set("userID", userID)
+		c.Params = gin.Params{{Key: "id", Value: "not-a-uuid"}}
+		c.Request, _ = http.NewRequest("DELETE", "/mutes/not-a-uuid", nil)
+
+		handler.CancelMute(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
@@ -0,0 +1,182 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/dto"
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/models"
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/services"
+	custom_errors "github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/utils/errors"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockNotificationChannelService struct {
+	mock.Mock
+}
+
+func (m *MockNotificationChannelService) CreateChannel(userID uuid.UUID, orgID *uuid.UUID, req dto.NotificationChannelRequest) (*dto.NotificationChannelResponse, error) {
+	args := m.Called(userID, orgID, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dto.NotificationChannelResponse), args.Error(1)
+}
+
+func (m *MockNotificationChannelService) ListChannels(userID uuid.UUID) ([]dto.NotificationChannelResponse, error) {
+	args := m.Called(userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]dto.NotificationChannelResponse), args.Error(1)
+}
+
+func (m *MockNotificationChannelService) GetChannel(userID, channelID uuid.UUID) (*dto.NotificationChannelResponse, error) {
+	args := m.Called(userID, channelID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dto.NotificationChannelResponse), args.Error(1)
+}
+
+func (m *MockNotificationChannelService) UpdateChannel(userID, channelID uuid.UUID, req dto.NotificationChannelRequest) (*dto.NotificationChannelResponse, error) {
+	args := m.Called(userID, channelID, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dto.NotificationChannelResponse), args.Error(1)
+}
+
+func (m *MockNotificationChannelService) DeleteChannel(userID, channelID uuid.UUID) error {
+	args := m.Called(userID, channelID)
+	return args.Error(0)
+}
+
+func (m *MockNotificationChannelService) TestChannel(userID, channelID uuid.UUID) (*dto.NotificationDeliveryResponse, error) {
+	args := m.Called(userID, channelID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dto.NotificationDeliveryResponse), args.Error(1)
+}
+
+func (m *MockNotificationChannelService) ListDeliveries(userID, channelID uuid.UUID) ([]dto.NotificationDeliveryResponse, error) {
+	args := m.Called(userID, channelID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]dto.NotificationDeliveryResponse), args.Error(1)
+}
+
+func (m *MockNotificationChannelService) ResolveChannels(owner services.Ownership, channelIDs []uuid.UUID) ([]uuid.UUID, error) {
+	args := m.Called(owner, channelIDs)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]uuid.UUID), args.Error(1)
+}
+
+func (m *MockNotificationChannelService) Enqueue(notification models.Notification, alert dto.NotificationAlert) error {
+	args := m.Called(notification, alert)
+	return args.Error(0)
+}
+
+func (m *MockNotificationChannelService) ProcessDeliveries() (int, int, error) {
+	args := m.Called()
+	return args.Int(0), args.Int(1), args.Error(2)
+}
+
+func TestNotificationChannelHandler_CreateNotificationChannel(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	userID := uuid.New()
+
+	t.Run("Success - Create webhook channel", func(t *testing.T) {
+		mockChannelService := new(MockNotificationChannelService)
+		handler := NewNotificationChannelHandler(mockChannelService)
+
+		mockChannelService.On("CreateChannel", userID, (*uuid.UUID)(nil), mock.MatchedBy(func(req dto.NotificationChannelRequest) bool {
+			return req.Type == "webhook" && string(req.Config) == `{"url":"https://example.com/hook"}`
+		})).Return(&dto.NotificationChannelResponse{ID: uuid.New(), Type: "webhook", Enabled: true}, nil)
+
+		body := `{"name":"On-call","type":"webhook","config":{"url":"https://example.com/hook"}}`
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Set("userID", userID)
+		c.Request, _ = http.NewRequest("POST", "/notification-channels", bytes.NewBufferString(body))
+		c.Request.Header.Set("Content-Type", "application/json")
+
+		handler.CreateNotificationChannel(c)
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+		mockChannelService.AssertExpectations(t)
+	})
+
+	t.Run("Error - Missing config", func(t *testing.T) {
+		mockChannelService := new(MockNotificationChannelService)
+		handler := NewNotificationChannelHandler(mockChannelService)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Set("userID", userID)
+		c.Request, _ = http.NewRequest("POST", "/notification-channels", bytes.NewBufferString(`{"name":"On-call","type":"webhook"}`))
+		c.Request.Header.Set("Content-Type", "application/json")
+
+		handler.CreateNotificationChannel(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		mockChannelService.AssertNotCalled(t, "CreateChannel", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestNotificationChannelHandler_TestNotificationChannel(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	userID := uuid.New()
+	channelID := uuid.New()
+
+	t.Run("Success - Returns the delivery", func(t *testing.T) {
+		mockChannelService := new(MockNotificationChannelService)
+		handler := NewNotificationChannelHandler(mockChannelService)
+
+		mockChannelService.On("TestChannel", userID, channelID).Return(&dto.NotificationDeliveryResponse{ID: uuid.New(), Status: models.DeliveryDelivered}, nil)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Set("userID", userID)
+		c.Params = gin.Params{{Key: "id", Value: channelID.String()}}
+		c.Request, _ = http.NewRequest("POST", "/notification-channels/"+channelID.String()+"/test", nil)
+
+		handler.TestNotificationChannel(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response dto.NotificationDeliveryResponse
+		json.Unmarshal(w.Body.Bytes(), &response)
+
+		assert.Equal(t, models.DeliveryDelivered, response.Status)
+	})
+
+	t.Run("Error - Channel not found", func(t *testing.T) {
+		mockChannelService := new(MockNotificationChannelService)
+		handler := NewNotificationChannelHandler(mockChannelService)
+
+		mockChannelService.On("TestChannel", userID, channelID).Return(nil, custom_errors.ErrNotificationChannelNotFound)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Set("userID", userID)
+		c.Params = gin.Params{{Key: "id", Value: channelID.String()}}
+		c.Request, _ = http.NewRequest("POST", "/notification-channels/"+channelID.String()+"/test", nil)
+
+		handler.TestNotificationChannel(c)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
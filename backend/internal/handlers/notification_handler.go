@@ -20,7 +20,7 @@ func NewNotificationHandler(notificationService services.NotificationService) *N
 
 // CreateNotification godoc
 // @Summary Create a notification rule
-// @Description Create a new notification rule for the authenticated user. Notifications will trigger in real-time when heartbeat conditions are met.
+// @Description Create a new notification rule for the authenticated user, or for the organization of an organization token. Notifications will trigger in real-time when heartbeat conditions are met. Optional title_template and body_template (Go text/template syntax) replace the default alert text; templates that do not render are rejected. A rule has at most 20 conditions.
 // @Tags notifications
 // @Accept  json
 // @Produce  json
@@ -28,6 +28,7 @@ func NewNotificationHandler(notificationService services.NotificationService) *N
 // @Success 201 {object} dto.NotificationResponse "Notification rule created successfully"
 // @Failure 400 {object} dto.BadRequestErrorResponse "Invalid request body or validation error"
 // @Failure 401 {object} dto.DetailedErrorResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenErrorResponse "Operator role required in the organization"
 // @Failure 500 {object} dto.InternalServerErrorResponse "Internal server error"
 // @Security ApiKeyAuth
 // @Router /v1/notifications [post]
@@ -62,7 +63,7 @@ func (h *NotificationHandler) CreateNotification(c *gin.Context) {
 		return
 	}
 
-	notification, err := h.notificationService.CreateNotification(uuidUserID, req)
+	notification, err := h.notificationService.CreateNotification(uuidUserID, activeOrganizationID(c), req)
 	if err != nil {
 		if customErr, ok := err.(errors.CustomError); ok {
 			c.JSON(customErr.StatusCode(), dto.DetailedErrorResponse{
@@ -134,4 +135,137 @@ func (h *NotificationHandler) GetNotifications(c *gin.Context) {
 	}
 
 	c.JSON(http.StatusOK, notifications)
-}
\ No newline at end of file
+}
+// PreviewNotificationTemplate godoc
+// @Summary Preview notification templates
+// @Description Render title and body templates as a rule with the given conditions would when triggered. The heartbeat and device default to samples; pass heartbeat values or a device_id the user can view to render against them. Nothing is saved or sent.
+// @Tags notifications
+// @Accept  json
+// @Produce  json
+// @Param request body dto.TemplatePreviewRequest true "Templates and the rule they belong to"
+// @Success 200 {object} dto.TemplatePreviewResponse "Rendered title and body with condition results"
+// @Failure 400 {object} dto.BadRequestErrorResponse "Invalid request body or template"
+// @Failure 401 {object} dto.DetailedErrorResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenErrorResponse "No access to the device"
+// @Failure 404 {object} dto.DetailedErrorResponse "Device not found"
+// @Failure 500 {object} dto.InternalServerErrorResponse "Internal server error"
+// @Security ApiKeyAuth
+// @Router /v1/notifications/preview [post]
+func (h *NotificationHandler) PreviewNotificationTemplate(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, dto.DetailedErrorResponse{
+			Code:    dto.ErrorCodeInvalidCredentials,
+			Message: "Unauthorized",
+			Details: "User ID not found in context",
+		})
+		return
+	}
+
+	uuidUserID, ok := userID.(uuid.UUID)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, dto.DetailedErrorResponse{
+			Code:    dto.ErrorCodeInternalError,
+			Message: "Internal server error",
+			Details: "Invalid user ID type",
+		})
+		return
+	}
+
+	var req dto.TemplatePreviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.DetailedErrorResponse{
+			Code:    dto.ErrorCodeInvalidRequest,
+			Message: "Invalid request body",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	preview, err := h.notificationService.PreviewTemplate(uuidUserID, req)
+	if err != nil {
+		if customErr, ok := err.(errors.CustomError); ok {
+			c.JSON(customErr.StatusCode(), dto.DetailedErrorResponse{
+				Code:    dto.ErrorCodeFromStatusCode(customErr.StatusCode()),
+				Message: customErr.Message(),
+				Details: "Please check your templates and try again",
+			})
+		} else {
+			c.JSON(http.StatusInternalServerError, dto.DetailedErrorResponse{
+				Code:    dto.ErrorCodeInternalError,
+				Message: "Internal server error",
+				Details: err.Error(),
+			})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, preview)
+}
+
+// TestNotificationRule godoc
+// @Summary Test a notification rule against past heartbeats
+// @Description Replay the stored heartbeats of a period (up to 31 days) through a rule, given by notification_id or as a definition, and return how often it would have fired. The rule is evaluated as it is live: every triggering heartbeat sends a message, the first one opens an alert and the next heartbeat that does not trigger the rule resolves it; maintenance windows hold the triggers of the devices they cover, and mutes hold the triggers that fire while they are in effect. Nothing is recorded or sent.
+// @Tags notifications
+// @Accept  json
+// @Produce  json
+// @Param request body dto.RuleTestRequest true "Rule, devices and period to test"
+// @Success 200 {object} dto.RuleTestResponse "Counts and timeline of the alerts the rule would have opened"
+// @Failure 400 {object} dto.BadRequestErrorResponse "Invalid request body, rule or period"
+// @Failure 401 {object} dto.DetailedErrorResponse "Unauthorized"
+// @Failure 403 {object} dto.ForbiddenErrorResponse "No access to the rule or a device"
+// @Failure 404 {object} dto.DetailedErrorResponse "Rule or device not found"
+// @Failure 500 {object} dto.InternalServerErrorResponse "Internal server error"
+// @Security ApiKeyAuth
+// @Router /v1/notifications/test [post]
+func (h *NotificationHandler) TestNotificationRule(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, dto.DetailedErrorResponse{
+			Code:    dto.ErrorCodeInvalidCredentials,
+			Message: "Unauthorized",
+			Details: "User ID not found in context",
+		})
+		return
+	}
+
+	uuidUserID, ok := userID.(uuid.UUID)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, dto.DetailedErrorResponse{
+			Code:    dto.ErrorCodeInternalError,
+			Message: "Internal server error",
+			Details: "Invalid user ID type",
+		})
+		return
+	}
+
+	var req dto.RuleTestRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.DetailedErrorResponse{
+			Code:    dto.ErrorCodeInvalidRequest,
+			Message: "Invalid request body",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	result, err := h.notificationService.TestRule(uuidUserID, activeOrganizationID(c), req)
+	if err != nil {
+		if customErr, ok := err.(errors.CustomError); ok {
+			c.JSON(customErr.StatusCode(), dto.DetailedErrorResponse{
+				Code:    dto.ErrorCodeFromStatusCode(customErr.StatusCode()),
+				Message: customErr.Message(),
+				Details: "Please check your input and try again",
+			})
+		} else {
+			c.JSON(http.StatusInternalServerError, dto.DetailedErrorResponse{
+				Code:    dto.ErrorCodeInternalError,
+				Message: "Internal server error",
+				Details: err.Error(),
+			})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
@@ -6,6 +6,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/dto"
 	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/models"
@@ -20,8 +21,8 @@ type MockNotificationService struct {
 	mock.Mock
 }
 
-func (m *MockNotificationService) CreateNotification(userID uuid.UUID, req dto.CreateNotificationRequest) (*models.Notification, error) {
-	args := m.Called(userID, req)
+func (m *MockNotificationService) CreateNotification(userID uuid.UUID, orgID *uuid.UUID, req dto.CreateNotificationRequest) (*models.Notification, error) {
+	args := m.Called(userID, orgID, req)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
@@ -41,6 +42,22 @@ func (m *MockNotificationService) CheckHeartbeat(heartbeat *models.Heartbeat) er
 	return args.Error(0)
 }
 
+func (m *MockNotificationService) PreviewTemplate(userID uuid.UUID, req dto.TemplatePreviewRequest) (*dto.TemplatePreviewResponse, error) {
+	args := m.Called(userID, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dto.TemplatePreviewResponse), args.Error(1)
+}
+
+func (m *MockNotificationService) TestRule(userID uuid.UUID, orgID *uuid.UUID, req dto.RuleTestRequest) (*dto.RuleTestResponse, error) {
+	args := m.Called(userID, orgID, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dto.RuleTestResponse), args.Error(1)
+}
+
 func TestNotificationHandler_CreateNotification(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
@@ -69,7 +86,7 @@ func TestNotificationHandler_CreateNotification(t *testing.T) {
 			Enabled:     true,
 		}
 
-		mockNotificationService.On("CreateNotification", userID, createReq).Return(notification, nil)
+		mockNotificationService.On("CreateNotification", userID, (*uuid.UUID)(nil), createReq).Return(notification, nil)
 
 		jsonData, _ := json.Marshal(createReq)
 
@@ -161,7 +178,7 @@ func TestNotificationHandler_CreateNotification(t *testing.T) {
 
 		validationError := custom_errors.NewValidationError("Invalid parameter: invalid_param")
 
-		mockNotificationService.On("CreateNotification", userID, createReq).Return((*models.Notification)(nil), validationError)
+		mockNotificationService.On("CreateNotification", userID, (*uuid.UUID)(nil), createReq).Return((*models.Notification)(nil), validationError)
 
 		jsonData, _ := json.Marshal(createReq)
 
@@ -259,7 +276,7 @@ func TestNotificationHandler_CreateNotification(t *testing.T) {
 			DeviceIDs: []uuid.UUID{uuid.New()},
 		}
 
-		mockNotificationService.On("CreateNotification", userID, createReq).Return((*models.Notification)(nil), custom_errors.ErrDatabaseError)
+		mockNotificationService.On("CreateNotification", userID, (*uuid.UUID)(nil), createReq).Return((*models.Notification)(nil), custom_errors.ErrDatabaseError)
 
 		jsonData, _ := json.Marshal(createReq)
 
@@ -448,4 +465,125 @@ func TestNotificationHandler_GetNotifications(t *testing.T) {
 		assert.Equal(t, "access to this resource is forbidden", response.Message)
 		mockNotificationService.AssertExpectations(t)
 	})
-}
\ No newline at end of file
+}
+func TestNotificationHandler_PreviewNotificationTemplate(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	userID := uuid.New()
+
+	t.Run("Success - Templates rendered", func(t *testing.T) {
+		mockNotificationService := new(MockNotificationService)
+		handler := NewNotificationHandler(mockNotificationService)
+
+		previewReq := dto.TemplatePreviewRequest{
+			Name:          "High CPU",
+			Conditions:    []dto.NotificationCondition{{Parameter: "cpu", Operator: ">", Value: 80.0}},
+			TitleTemplate: "{{.Rule.Name}} on {{.Device.Name}}",
+		}
+		mockNotificationService.On("PreviewTemplate", userID, previewReq).
+			Return(&dto.TemplatePreviewResponse{Title: "High CPU on Sample device", Matched: true}, nil)
+
+		jsonData, _ := json.Marshal(previewReq)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Set("userID", userID)
+		c.Request, _ = http.NewRequest("POST", "/notifications/preview", bytes.NewBuffer(jsonData))
+		c.Request.Header.Set("Content-Type", "application/json")
+
+		handler.PreviewNotificationTemplate(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response dto.TemplatePreviewResponse
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.Equal(t, "High CPU on Sample device", response.Title)
+		mockNotificationService.AssertExpectations(t)
+	})
+
+	t.Run("Error - Invalid template", func(t *testing.T) {
+		mockNotificationService := new(MockNotificationService)
+		handler := NewNotificationHandler(mockNotificationService)
+
+		mockNotificationService.On("PreviewTemplate", userID, mock.Anything).
+			Return(nil, custom_errors.NewValidationError("Invalid title_template: unclosed action"))
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Set("userID", userID)
+		c.Request, _ = http.NewRequest("POST", "/notifications/preview", bytes.NewBufferString(`{"title_template":"{{.Rule.Name"}`))
+		c.Request.Header.Set("Content-Type", "application/json")
+
+		handler.PreviewNotificationTemplate(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestNotificationHandler_TestNotificationRule(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	userID := uuid.New()
+	notificationID := uuid.New()
+
+	t.Run("Success - Stored rule tested", func(t *testing.T) {
+		mockNotificationService := new(MockNotificationService)
+		handler := NewNotificationHandler(mockNotificationService)
+
+		mockNotificationService.On("TestRule", userID, (*uuid.UUID)(nil), mock.MatchedBy(func(req dto.RuleTestRequest) bool {
+			return *req.NotificationID == notificationID && req.From.Equal(time.Date(2025, 9, 1, 0, 0, 0, 0, time.UTC)) && req.To == nil
+		})).Return(&dto.RuleTestResponse{Heartbeats: 10, Matched: 3, Notifications: 3, Alerts: 1}, nil)
+
+		body := `{"notification_id":"` + notificationID.String() + `","from":"2025-09-01T00:00:00Z"}`
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Set("userID", userID)
+		c.Request, _ = http.NewRequest("POST", "/notifications/test", bytes.NewBufferString(body))
+		c.Request.Header.Set("Content-Type", "application/json")
+
+		handler.TestNotificationRule(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response dto.RuleTestResponse
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, response.Alerts)
+		mockNotificationService.AssertExpectations(t)
+	})
+
+	t.Run("Error - Missing from", func(t *testing.T) {
+		mockNotificationService := new(MockNotificationService)
+		handler := NewNotificationHandler(mockNotificationService)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Set("userID", userID)
+		c.Request, _ = http.NewRequest("POST", "/notifications/test", bytes.NewBufferString(`{"notification_id":"`+notificationID.String()+`"}`))
+		c.Request.Header.Set("Content-Type", "application/json")
+
+		handler.TestNotificationRule(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		mockNotificationService.AssertNotCalled(t, "TestRule", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Error - Rule not found", func(t *testing.T) {
+		mockNotificationService := new(MockNotificationService)
+		handler := NewNotificationHandler(mockNotificationService)
+
+		mockNotificationService.On("TestRule", userID, (*uuid.UUID)(nil), mock.Anything).Return(nil, custom_errors.ErrNotificationNotFound)
+
+		body := `{"notification_id":"` + notificationID.String() + `","from":"2025-09-01T00:00:00Z"}`
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Set("userID", userID)
+		c.Request, _ = http.NewRequest("POST", "/notifications/test", bytes.NewBufferString(body))
+		c.Request.Header.Set("Content-Type", "application/json")
+
+		handler.TestNotificationRule(c)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
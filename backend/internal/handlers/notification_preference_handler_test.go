@@ -0,0 +1,122 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/dto"
+	custom_errors "github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/utils/errors"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockNotificationPreferenceService struct {
+	mock.Mock
+}
+
+func (m *MockNotificationPreferenceService) GetPreferences(userID uuid.UUID) (*dto.NotificationPreferenceResponse, error) {
+	args := m.Called(userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dto.NotificationPreferenceResponse), args.Error(1)
+}
+
+func (m *MockNotificationPreferenceService) UpdatePreferences(userID uuid.UUID, req dto.NotificationPreferenceRequest) (*dto.NotificationPreferenceResponse, error) {
+	args := m.Called(userID, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dto.NotificationPreferenceResponse), args.Error(1)
+}
+
+func (m *MockNotificationPreferenceService) Deliver(userID uuid.UUID, msg dto.NotificationAlert, channelIDs []uuid.UUID, now time.Time) error {
+	args := m.Called(userID, msg, channelIDs, now)
+	return args.Error(0)
+}
+
+func (m *MockNotificationPreferenceService) ReleaseHeld(now time.Time) (int, error) {
+	args := m.Called(now)
+	return args.Int(0), args.Error(1)
+}
+
+func TestNotificationPreferenceHandler_GetNotificationPreferences(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	userID := uuid.New()
+
+	t.Run("Success - Default preferences", func(t *testing.T) {
+		mockService := new(MockNotificationPreferenceService)
+		handler := NewNotificationPreferenceHandler(mockService)
+
+		mockService.On("GetPreferences", userID).Return(&dto.NotificationPreferenceResponse{Timezone: "UTC", QuietHoursAction: "defer"}, nil)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Set("userID", userID)
+		c.Request, _ = http.NewRequest("GET", "/notification-preferences", nil)
+
+		handler.GetNotificationPreferences(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response dto.NotificationPreferenceResponse
+		json.Unmarshal(w.Body.Bytes(), &response)
+
+		assert.Equal(t, "UTC", response.Timezone)
+	})
+}
+
+func TestNotificationPreferenceHandler_UpdateNotificationPreferences(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	userID := uuid.New()
+	req := dto.NotificationPreferenceRequest{
+		Timezone:         "America/Sao_Paulo",
+		QuietHours:       []dto.QuietHours{{Weekday: 1, Start: "22:00", End: "07:00"}},
+		QuietHoursAction: "defer",
+	}
+
+	t.Run("Success - Preferences updated", func(t *testing.T) {
+		mockService := new(MockNotificationPreferenceService)
+		handler := NewNotificationPreferenceHandler(mockService)
+
+		mockService.On("UpdatePreferences", userID, req).Return(&dto.NotificationPreferenceResponse{Timezone: req.Timezone, QuietHoursAction: "defer"}, nil)
+
+		body, _ := json.Marshal(req)
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Set("userID", userID)
+		c.Request, _ = http.NewRequest("PUT", "/notification-preferences", bytes.NewBuffer(body))
+		c.Request.Header.Set("Content-Type", "application/json")
+
+		handler.UpdateNotificationPreferences(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("Error - Invalid timezone", func(t *testing.T) {
+		mockService := new(MockNotificationPreferenceService)
+		handler := NewNotificationPreferenceHandler(mockService)
+
+		mockService.On("UpdatePreferences", userID, req).Return(nil, custom_errors.NewValidationError("Invalid timezone: America/Sao_Paulo"))
+
+		body, _ := json.Marshal(req)
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Set("userID", userID)
+		c.Request, _ = http.NewRequest("PUT", "/notification-preferences", bytes.NewBuffer(body))
+		c.Request.Header.Set("Content-Type", "application/json")
+
+		handler.UpdateNotificationPreferences(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
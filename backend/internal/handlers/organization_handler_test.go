@@ -0,0 +1,276 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/dto"
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/models"
+	custom_errors "github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/utils/errors"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockOrganizationService struct {
+	mock.Mock
+}
+
+func (m *MockOrganizationService) CreateOrganization(userID uuid.UUID, name string) (*dto.OrganizationResponse, error) {
+	args := m.Called(userID, name)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dto.OrganizationResponse), args.Error(1)
+}
+
+func (m *MockOrganizationService) ListOrganizations(userID uuid.UUID) ([]dto.OrganizationResponse, error) {
+	args := m.Called(userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]dto.OrganizationResponse), args.Error(1)
+}
+
+func (m *MockOrganizationService) GetOrganization(userID, orgID uuid.UUID) (*dto.OrganizationResponse, error) {
+	args := m.Called(userID, orgID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dto.OrganizationResponse), args.Error(1)
+}
+
+func (m *MockOrganizationService) ListMembers(userID, orgID uuid.UUID) ([]dto.OrganizationMemberResponse, error) {
+	args := m.Called(userID, orgID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]dto.OrganizationMemberResponse), args.Error(1)
+}
+
+func (m *MockOrganizationService) AddMember(userID, orgID uuid.UUID, email, role string) (*dto.OrganizationMemberResponse, error) {
+	args := m.Called(userID, orgID, email, role)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dto.OrganizationMemberResponse), args.Error(1)
+}
+
+func (m *MockOrganizationService) UpdateMemberRole(userID, orgID, memberID uuid.UUID, role string) error {
+	args := m.Called(userID, orgID, memberID, role)
+	return args.Error(0)
+}
+
+func (m *MockOrganizationService) RemoveMember(userID, orgID, memberID uuid.UUID) error {
+	args := m.Called(userID, orgID, memberID)
+	return args.Error(0)
+}
+
+func (m *MockOrganizationService) IssueToken(userID, orgID uuid.UUID) (string, error) {
+	args := m.Called(userID, orgID)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockOrganizationService) MoveDevices(userID, orgID uuid.UUID, deviceIDs []uuid.UUID) error {
+	args := m.Called(userID, orgID, deviceIDs)
+	return args.Error(0)
+}
+
+func TestOrganizationHandler_CreateOrganization(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	userID := uuid.New()
+
+	t.Run("Success - Create organization", func(t *testing.T) {
+		mockOrgService := new(MockOrganizationService)
+		handler := NewOrganizationHandler(mockOrgService)
+
+		mockOrgService.On("CreateOrganization", userID, "Acme").Return(&dto.OrganizationResponse{ID: uuid.New(), Name: "Acme", Role: models.RoleOwner}, nil)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Set("userID", userID)
+		c.Request, _ = http.NewRequest("POST", "/orgs", bytes.NewBufferString(`{"name":"Acme"}`))
+		c.Request.Header.Set("Content-Type", "application/json")
+
+		handler.CreateOrganization(c)
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+
+		var response dto.OrganizationResponse
+		json.Unmarshal(w.Body.Bytes(), &response)
+
+		assert.Equal(t, models.RoleOwner, response.Role)
+		mockOrgService.AssertExpectations(t)
+	})
+
+	t.Run("Error - User ID not found in context", func(t *testing.T) {
+		handler := NewOrganizationHandler(new(MockOrganizationService))
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+
+		handler.CreateOrganization(c)
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+}
+
+func TestOrganizationHandler_AddMember(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	userID := uuid.New()
+	orgID := uuid.New()
+
+	t.Run("Success - Add member", func(t *testing.T) {
+		mockOrgService := new(MockOrganizationService)
+		handler := NewOrganizationHandler(mockOrgService)
+
+		mockOrgService.On("AddMember", userID, orgID, "ops@example.com", models.RoleOperator).
+			Return(&dto.OrganizationMemberResponse{UserID: uuid.New(), Email: "ops@example.com", Role: models.RoleOperator}, nil)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Set("userID", userID)
+		c.Params = gin.Params{{Key: "id", Value: orgID.String()}}
+		c.Request, _ = http.NewRequest("POST", "/orgs/"+orgID.String()+"/members", bytes.NewBufferString(`{"email":"ops@example.com","role":"operator"}`))
+		c.Request.Header.Set("Content-Type", "application/json")
+
+		handler.AddMember(c)
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+		mockOrgService.AssertExpectations(t)
+	})
+
+	t.Run("Error - Already a member", func(t *testing.T) {
+		mockOrgService := new(MockOrganizationService)
+		handler := NewOrganizationHandler(mockOrgService)
+
+		mockOrgService.On("AddMember", userID, orgID, "ops@example.com", models.RoleViewer).Return(nil, custom_errors.ErrMemberAlreadyExists)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Set("userID", userID)
+		c.Params = gin.Params{{Key: "id", Value: orgID.String()}}
+		c.Request, _ = http.NewRequest("POST", "/orgs/"+orgID.String()+"/members", bytes.NewBufferString(`{"email":"ops@example.com","role":"viewer"}`))
+		c.Request.Header.Set("Content-Type", "application/json")
+
+		handler.AddMember(c)
+
+		assert.Equal(t, http.StatusConflict, w.Code)
+	})
+
+	t.Run("Error - Invalid organization ID", func(t *testing.T) {
+		mockOrgService := new(MockOrganizationService)
+		handler := NewOrganizationHandler(mockOrgService)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Set("userID", userID)
+		c.Params = gin.Params{{Key: "id", Value: "invalid"}}
+
+		handler.AddMember(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		mockOrgService.AssertNotCalled(t, "AddMember", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestOrganizationHandler_RemoveMember(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	userID := uuid.New()
+	orgID := uuid.New()
+
+	t.Run("Success - Leave organization", func(t *testing.T) {
+		mockOrgService := new(MockOrganizationService)
+		handler := NewOrganizationHandler(mockOrgService)
+
+		mockOrgService.On("RemoveMember", userID, orgID, userID).Return(nil)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Set("userID", userID)
+		c.Params = gin.Params{{Key: "id", Value: orgID.String()}, {Key: "user_id", Value: userID.String()}}
+
+		handler.RemoveMember(c)
+
+		assert.Equal(t, http.StatusNoContent, w.Code)
+		mockOrgService.AssertExpectations(t)
+	})
+
+	t.Run("Error - Last owner", func(t *testing.T) {
+		mockOrgService := new(MockOrganizationService)
+		handler := NewOrganizationHandler(mockOrgService)
+
+		mockOrgService.On("RemoveMember", userID, orgID, userID).Return(custom_errors.ErrLastOwner)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Set("userID", userID)
+		c.Params = gin.Params{{Key: "id", Value: orgID.String()}, {Key: "user_id", Value: userID.String()}}
+
+		handler.RemoveMember(c)
+
+		assert.Equal(t, http.StatusConflict, w.Code)
+	})
+}
+
+func TestOrganizationHandler_SwitchOrganization(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	userID := uuid.New()
+	orgID := uuid.New()
+
+	t.Run("Success - Organization token issued", func(t *testing.T) {
+		mockOrgService := new(MockOrganizationService)
+		handler := NewOrganizationHandler(mockOrgService)
+
+		mockOrgService.On("IssueToken", userID, orgID).Return("org-token", nil)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Set("userID", userID)
+		c.Params = gin.Params{{Key: "id", Value: orgID.String()}}
+
+		handler.SwitchOrganization(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response dto.TokenResponse
+		json.Unmarshal(w.Body.Bytes(), &response)
+
+		assert.Equal(t, "org-token", response.Token)
+	})
+
+	t.Run("Error - Not a member", func(t *testing.T) {
+		mockOrgService := new(MockOrganizationService)
+		handler := NewOrganizationHandler(mockOrgService)
+
+		mockOrgService.On("IssueToken", userID, orgID).Return("", custom_errors.ErrForbidden)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Set("userID", userID)
+		c.Params = gin.Params{{Key: "id", Value: orgID.String()}}
+
+		handler.SwitchOrganization(c)
+
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+}
+
+func TestActiveOrganizationID(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	assert.Nil(t, activeOrganizationID(c))
+
+	orgID := uuid.New()
+	c.Set("orgID", orgID)
+	assert.Equal(t, &orgID, activeOrganizationID(c))
+}
@@ -0,0 +1,163 @@
+package handlers
+
+import (
+	"encoding/csv"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/dto"
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/services"
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/utils/errors"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type ReportHandler struct {
+	reportService services.ReportService
+}
+
+func NewReportHandler(reportService services.ReportService) *ReportHandler {
+	return &ReportHandler{reportService: reportService}
+}
+
+// GetAvailabilityReport godoc
+// @Summary Device availability report
+// @Description Percentage of expected heartbeats received, percentage of time connected, mean time between outages and longest outage, per device or per location. Expected heartbeats follow the heartbeat_interval_seconds of each device shadow, reported before desired, 1 minute when unset
+// @Tags reports
+// @Accept  json
+// @Produce  json
+// @Produce  text/csv
+// @Param from query string false "Start time (RFC3339 format)" default(30 days ago)
+// @Param to query string false "End time (RFC3339 format)" default(now)
+// @Param group_by query string false "Grouping: device or location" default(device)
+// @Param format query string false "Response format: json or csv (defaults to the Accept header)"
+// @Success 200 {object} dto.AvailabilityReportResponse "Availability report"
+// @Failure 400 {object} dto.BadRequestErrorResponse "Invalid time range or grouping"
+// @Failure 401 {object} dto.DetailedErrorResponse "Unauthorized"
+// @Failure 500 {object} dto.InternalServerErrorResponse "Internal server error"
+// @Security ApiKeyAuth
+// @Router /v1/reports/availability [get]
+func (h *ReportHandler) GetAvailabilityReport(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, dto.DetailedErrorResponse{
+			Code:    dto.ErrorCodeInvalidCredentials,
+			Message: "Unauthorized",
+			Details: "User ID not found in context",
+		})
+		return
+	}
+
+	uuidUserID, ok := userID.(uuid.UUID)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, dto.DetailedErrorResponse{
+			Code:    dto.ErrorCodeInternalError,
+			Message: "Internal server error",
+			Details: "Invalid user ID type",
+		})
+		return
+	}
+
+	fromStr := c.DefaultQuery("from", time.Now().Add(-30*24*time.Hour).Format(time.RFC3339))
+	toStr := c.DefaultQuery("to", time.Now().Format(time.RFC3339))
+
+	from, err := time.Parse(time.RFC3339, fromStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.DetailedErrorResponse{
+			Code:    dto.ErrorCodeInvalidRequest,
+			Message: "Invalid from time format",
+			Details: "Use RFC3339 format (e.g., 2023-01-01T00:00:00Z)",
+		})
+		return
+	}
+
+	to, err := time.Parse(time.RFC3339, toStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.DetailedErrorResponse{
+			Code:    dto.ErrorCodeInvalidRequest,
+			Message: "Invalid to time format",
+			Details: "Use RFC3339 format (e.g., 2023-01-01T00:00:00Z)",
+		})
+		return
+	}
+
+	report, err := h.reportService.GetAvailabilityReport(uuidUserID, from, to, c.Query("group_by"))
+	if err != nil {
+		if customErr, ok := err.(errors.CustomError); ok {
+			c.JSON(customErr.StatusCode(), dto.DetailedErrorResponse{
+				Code:    dto.ErrorCodeFromStatusCode(customErr.StatusCode()),
+				Message: customErr.Message(),
+				Details: "Failed to build availability report",
+			})
+		} else {
+			c.JSON(http.StatusInternalServerError, dto.DetailedErrorResponse{
+				Code:    dto.ErrorCodeInternalError,
+				Message: "Internal server error",
+				Details: err.Error(),
+			})
+		}
+		return
+	}
+
+	if wantsCSV(c) {
+		writeAvailabilityCSV(c, report)
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+// wantsCSV resolves the response format from the format query parameter,
+// falling back to the Accept header.
+func wantsCSV(c *gin.Context) bool {
+	if format := c.Query("format"); format != "" {
+		return strings.EqualFold(format, "csv")
+	}
+	return strings.Contains(c.GetHeader("Accept"), "text/csv")
+}
+
+func writeAvailabilityCSV(c *gin.Context, report *dto.AvailabilityReportResponse) {
+	c.Header("Content-Type", "text/csv; charset=utf-8")
+	c.Header("Content-Disposition", "attachment; filename=availability_"+report.GroupBy+".csv")
+	c.Status(http.StatusOK)
+
+	w := csv.NewWriter(c.Writer)
+	_ = w.Write([]string{
+		"device_id", "device_name", "device_sn", "location", "device_count", "period_seconds",
+		"expected_heartbeats", "received_heartbeats", "heartbeat_percentage", "connectivity_percentage",
+		"outage_count", "mean_time_between_outages_seconds", "longest_outage_seconds",
+	})
+
+	for _, item := range report.Items {
+		deviceID := ""
+		if item.DeviceID != nil {
+			deviceID = item.DeviceID.String()
+		}
+		mtbo := ""
+		if item.MeanTimeBetweenOutagesSeconds != nil {
+			mtbo = formatFloat(*item.MeanTimeBetweenOutagesSeconds)
+		}
+		_ = w.Write([]string{
+			deviceID,
+			item.DeviceName,
+			item.DeviceSN,
+			item.Location,
+			strconv.Itoa(item.DeviceCount),
+			formatFloat(item.PeriodSeconds),
+			strconv.FormatInt(item.ExpectedHeartbeats, 10),
+			strconv.FormatInt(item.ReceivedHeartbeats, 10),
+			formatFloat(item.HeartbeatPercentage),
+			formatFloat(item.ConnectivityPercentage),
+			strconv.Itoa(item.OutageCount),
+			mtbo,
+			formatFloat(item.LongestOutageSeconds),
+		})
+	}
+	w.Flush()
+}
+
+func formatFloat(value float64) string {
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
@@ -0,0 +1,177 @@
+package handlers
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/dto"
+	custom_errors "github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/utils/errors"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockReportService struct {
+	mock.Mock
+}
+
+func (m *MockReportService) GetAvailabilityReport(userID uuid.UUID, from, to time.Time, groupBy string) (*dto.AvailabilityReportResponse, error) {
+	args := m.Called(userID, from, to, groupBy)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dto.AvailabilityReportResponse), args.Error(1)
+}
+
+func TestReportHandler_GetAvailabilityReport(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	userID := uuid.New()
+	deviceID := uuid.New()
+	mtbo := 480.0
+	report := &dto.AvailabilityReportResponse{
+		GroupBy: "device",
+		Items: []dto.AvailabilityReportItem{
+			{
+				DeviceID:                      &deviceID,
+				DeviceSN:                      "123456789012",
+				Location:                      "SP",
+				DeviceCount:                   1,
+				ExpectedHeartbeats:            10,
+				ReceivedHeartbeats:            10,
+				HeartbeatPercentage:           100,
+				ConnectivityPercentage:        80,
+				OutageCount:                   1,
+				MeanTimeBetweenOutagesSeconds: &mtbo,
+				LongestOutageSeconds:          120,
+			},
+		},
+	}
+
+	t.Run("Success - JSON report", func(t *testing.T) {
+		mockReportService := new(MockReportService)
+		handler := NewReportHandler(mockReportService)
+
+		mockReportService.On("GetAvailabilityReport", userID, mock.AnythingOfType("time.Time"), mock.AnythingOfType("time.Time"), "location").Return(report, nil)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Set("userID", userID)
+		c.Request, _ = http.NewRequest("GET", "/reports/availability?group_by=location", nil)
+
+		handler.GetAvailabilityReport(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response dto.AvailabilityReportResponse
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.Len(t, response.Items, 1)
+		assert.Equal(t, float64(80), response.Items[0].ConnectivityPercentage)
+
+		mockReportService.AssertExpectations(t)
+	})
+
+	t.Run("Success - CSV report via format param", func(t *testing.T) {
+		mockReportService := new(MockReportService)
+		handler := NewReportHandler(mockReportService)
+
+		mockReportService.On("GetAvailabilityReport", userID, mock.AnythingOfType("time.Time"), mock.AnythingOfType("time.Time"), "").Return(report, nil)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Set("userID", userID)
+		c.Request, _ = http.NewRequest("GET", "/reports/availability?format=csv", nil)
+
+		handler.GetAvailabilityReport(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.True(t, strings.HasPrefix(w.Header().Get("Content-Type"), "text/csv"))
+
+		records, err := csv.NewReader(w.Body).ReadAll()
+		assert.NoError(t, err)
+		assert.Len(t, records, 2)
+		assert.Equal(t, "device_id", records[0][0])
+		assert.Equal(t, deviceID.String(), records[1][0])
+		assert.Equal(t, "80", records[1][9])
+		assert.Equal(t, "480", records[1][11])
+	})
+
+	t.Run("Success - CSV report via Accept header", func(t *testing.T) {
+		mockReportService := new(MockReportService)
+		handler := NewReportHandler(mockReportService)
+
+		mockReportService.On("GetAvailabilityReport", userID, mock.AnythingOfType("time.Time"), mock.AnythingOfType("time.Time"), "").Return(report, nil)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Set("userID", userID)
+		c.Request, _ = http.NewRequest("GET", "/reports/availability", nil)
+		c.Request.Header.Set("Accept", "text/csv")
+
+		handler.GetAvailabilityReport(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.True(t, strings.HasPrefix(w.Header().Get("Content-Type"), "text/csv"))
+	})
+
+	t.Run("Error - User ID not found in context", func(t *testing.T) {
+		mockReportService := new(MockReportService)
+		handler := NewReportHandler(mockReportService)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request, _ = http.NewRequest("GET", "/reports/availability", nil)
+
+		handler.GetAvailabilityReport(c)
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("Error - Invalid from time", func(t *testing.T) {
+		mockReportService := new(MockReportService)
+		handler := NewReportHandler(mockReportService)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Set("userID", userID)
+		c.Request, _ = http.NewRequest("GET", "/reports/availability?from=yesterday", nil)
+
+		handler.GetAvailabilityReport(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		var response dto.DetailedErrorResponse
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.Equal(t, "Invalid from time format", response.Message)
+	})
+
+	t.Run("Error - Validation error from service", func(t *testing.T) {
+		mockReportService := new(MockReportService)
+		handler := NewReportHandler(mockReportService)
+
+		mockReportService.On("GetAvailabilityReport", userID, mock.AnythingOfType("time.Time"), mock.AnythingOfType("time.Time"), "rack").
+			Return(nil, custom_errors.NewValidationError("group_by must be device or location"))
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Set("userID", userID)
+		c.Request, _ = http.NewRequest("GET", "/reports/availability?group_by=rack", nil)
+
+		handler.GetAvailabilityReport(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		var response dto.DetailedErrorResponse
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.Equal(t, "group_by must be device or location", response.Message)
+	})
+}
@@ -0,0 +1,189 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/dto"
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/models"
+	custom_errors "github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/utils/errors"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockWebhookService struct {
+	mock.Mock
+}
+
+func (m *MockWebhookService) CreateWebhook(userID uuid.UUID, orgID *uuid.UUID, req dto.WebhookRequest) (*dto.WebhookResponse, error) {
+	args := m.Called(userID, orgID, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dto.WebhookResponse), args.Error(1)
+}
+
+func (m *MockWebhookService) ListWebhooks(userID uuid.UUID) ([]dto.WebhookResponse, error) {
+	args := m.Called(userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]dto.WebhookResponse), args.Error(1)
+}
+
+func (m *MockWebhookService) GetWebhook(userID, webhookID uuid.UUID) (*dto.WebhookResponse, error) {
+	args := m.Called(userID, webhookID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dto.WebhookResponse), args.Error(1)
+}
+
+func (m *MockWebhookService) UpdateWebhook(userID, webhookID uuid.UUID, req dto.WebhookRequest) (*dto.WebhookResponse, error) {
+	args := m.Called(userID, webhookID, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dto.WebhookResponse), args.Error(1)
+}
+
+func (m *MockWebhookService) DeleteWebhook(userID, webhookID uuid.UUID) error {
+	args := m.Called(userID, webhookID)
+	return args.Error(0)
+}
+
+func (m *MockWebhookService) RotateSecret(userID, webhookID uuid.UUID) (*dto.WebhookResponse, error) {
+	args := m.Called(userID, webhookID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dto.WebhookResponse), args.Error(1)
+}
+
+func (m *MockWebhookService) ListDeliveries(userID, webhookID uuid.UUID) ([]dto.NotificationDeliveryResponse, error) {
+	args := m.Called(userID, webhookID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]dto.NotificationDeliveryResponse), args.Error(1)
+}
+
+func (m *MockWebhookService) Redeliver(userID, webhookID, deliveryID uuid.UUID) (*dto.NotificationDeliveryResponse, error) {
+	args := m.Called(userID, webhookID, deliveryID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dto.NotificationDeliveryResponse), args.Error(1)
+}
+
+func TestWebhookHandler_CreateWebhook(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	userID := uuid.New()
+
+	t.Run("Success - Secret returned", func(t *testing.T) {
+		mockWebhookService := new(MockWebhookService)
+		handler := NewWebhookHandler(mockWebhookService)
+
+		mockWebhookService.On("CreateWebhook", userID, (*uuid.UUID)(nil), mock.MatchedBy(func(req dto.WebhookRequest) bool {
+			return req.URL == "https://hooks.example.com/alerts"
+		})).Return(&dto.WebhookResponse{ID: uuid.New(), Name: "Ops", Secret: "whsec_abc"}, nil)
+
+		body := `{"name":"Ops","url":"https://hooks.example.com/alerts"}`
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Set("userID", userID)
+		c.Request, _ = http.NewRequest("POST", "/webhooks", bytes.NewBufferString(body))
+		c.Request.Header.Set("Content-Type", "application/json")
+
+		handler.CreateWebhook(c)
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+
+		var response dto.WebhookResponse
+		json.Unmarshal(w.Body.Bytes(), &response)
+
+		assert.Equal(t, "whsec_abc", response.Secret)
+		mockWebhookService.AssertExpectations(t)
+	})
+
+	t.Run("Error - Missing URL", func(t *testing.T) {
+		mockWebhookService := new(MockWebhookService)
+		handler := NewWebhookHandler(mockWebhookService)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Set("userID", userID)
+		c.Request, _ = http.NewRequest("POST", "/webhooks", bytes.NewBufferString(`{"name":"Ops"}`))
+		c.Request.Header.Set("Content-Type", "application/json")
+
+		handler.CreateWebhook(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		mockWebhookService.AssertNotCalled(t, "CreateWebhook", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestWebhookHandler_RedeliverWebhookDelivery(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	userID := uuid.New()
+	webhookID := uuid.New()
+	deliveryID := uuid.New()
+
+	t.Run("Success - Redelivered", func(t *testing.T) {
+		mockWebhookService := new(MockWebhookService)
+		handler := NewWebhookHandler(mockWebhookService)
+
+		mockWebhookService.On("Redeliver", userID, webhookID, deliveryID).Return(&dto.NotificationDeliveryResponse{ID: deliveryID, Status: models.DeliveryDelivered}, nil)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Set("userID", userID)
+		c.Params = gin.Params{{Key: "id", Value: webhookID.String()}, {Key: "delivery_id", Value: deliveryID.String()}}
+		c.Request, _ = http.NewRequest("POST", "/webhooks/"+webhookID.String()+"/deliveries/"+deliveryID.String()+"/redeliver", nil)
+
+		handler.RedeliverWebhookDelivery(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		mockWebhookService.AssertExpectations(t)
+	})
+
+	t.Run("Error - Delivery not found", func(t *testing.T) {
+		mockWebhookService := new(MockWebhookService)
+		handler := NewWebhookHandler(mockWebhookService)
+
+		mockWebhookService.On("Redeliver", userID, webhookID, deliveryID).Return(nil, custom_errors.ErrNotificationDeliveryNotFound)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Set("userID", userID)
+		c.Params = gin.Params{{Key: "id", Value: webhookID.String()}, {Key: "delivery_id", Value: deliveryID.String()}}
+		c.Request, _ = http.NewRequest("POST", "/webhooks/"+webhookID.String()+"/deliveries/"+deliveryID.String()+"/redeliver", nil)
+
+		handler.RedeliverWebhookDelivery(c)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("Error - Invalid delivery ID", func(t *testing.T) {
+		mockWebhookService := new(MockWebhookService)
+		handler := NewWebhookHandler(mockWebhookService)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Set("userID", userID)
+		c.Params = gin.Params{{Key: "id", Value: webhookID.String()}, {Key: "delivery_id", Value: "abc"}}
+		c.Request, _ = http.NewRequest("POST", "/webhooks/"+webhookID.String()+"/deliveries/abc/redeliver", nil)
+
+		handler.RedeliverWebhookDelivery(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		mockWebhookService.AssertNotCalled(t, "Redeliver", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
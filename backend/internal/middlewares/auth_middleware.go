@@ -32,6 +32,10 @@ func AuthMiddleware(jwtService services.JWTService) gin.HandlerFunc {
 		}
 
 		c.Set("userID", claims.UserID)
+		if claims.OrganizationID != nil {
+			c.Set("orgID", *claims.OrganizationID)
+			c.Set("role", claims.Role)
+		}
 		c.Next()
 	}
 }
\ No newline at end of file
@@ -0,0 +1,52 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/datatypes"
+)
+
+const (
+	AlertFiring       = "firing"
+	AlertAcknowledged = "acknowledged"
+	AlertResolved     = "resolved"
+)
+
+// Alert is one episode of a notification rule firing for a device. It opens
+// when the rule first triggers, stays open while later heartbeats keep
+// triggering it and is resolved by the first heartbeat that does not. An
+// acknowledged alert stays open but is no longer escalated.
+//
+// When the rule has an escalation policy, EscalationStep is the step to
+// notify next, EscalationRepeats how many times it was notified already and
+// NextEscalationAt when; it is nil once the policy is exhausted or the alert
+// is acknowledged. Payload is the last message sent for the alert, used for
+// the escalation notifications, and Conditions the result of each condition
+// of the rule for the last triggering heartbeat.
+type Alert struct {
+	ID                 uuid.UUID      `json:"id" gorm:"type:uuid;primary_key"`
+	NotificationID     uuid.UUID      `json:"notification_id" gorm:"type:uuid;not null;index:idx_alerts_rule_device,priority:1"`
+	DeviceID           uuid.UUID      `json:"device_id" gorm:"type:uuid;not null;index:idx_alerts_rule_device,priority:2"`
+	UserID             uuid.UUID      `json:"user_id" gorm:"type:uuid;not null;index"`
+	OrganizationID     *uuid.UUID     `json:"organization_id" gorm:"type:uuid;index"`
+	Name               string         `json:"name"`
+	DeviceSN           string         `json:"device_sn"`
+	Status             string         `json:"status" gorm:"not null;index"`
+	Severity           string         `json:"severity" gorm:"not null;default:warning;index"`
+	TriggeredValue     float64        `json:"triggered_value"`
+	TriggerCount       int            `json:"trigger_count" gorm:"not null"`
+	Conditions         datatypes.JSON `json:"conditions" gorm:"type:jsonb"`
+	Payload            datatypes.JSON `json:"-" gorm:"type:jsonb"`
+	FiredAt            time.Time      `json:"fired_at" gorm:"not null;index"`
+	LastTriggeredAt    time.Time      `json:"last_triggered_at"`
+	AcknowledgedAt     *time.Time     `json:"acknowledged_at"`
+	AcknowledgedBy     *uuid.UUID     `json:"acknowledged_by" gorm:"type:uuid"`
+	ResolvedAt         *time.Time     `json:"resolved_at"`
+	EscalationPolicyID *uuid.UUID     `json:"escalation_policy_id" gorm:"type:uuid;index"`
+	EscalationStep     int            `json:"escalation_step" gorm:"not null"`
+	EscalationRepeats  int            `json:"escalation_repeats" gorm:"not null"`
+	NextEscalationAt   *time.Time     `json:"next_escalation_at" gorm:"index"`
+	CreatedAt          time.Time      `json:"created_at"`
+	UpdatedAt          time.Time      `json:"updated_at"`
+}
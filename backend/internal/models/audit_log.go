@@ -0,0 +1,30 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/datatypes"
+)
+
+const AuditEntityDevice = "device"
+
+const (
+	AuditTransferRequested = "device.transfer.requested"
+	AuditTransferAccepted  = "device.transfer.accepted"
+	AuditTransferDeclined  = "device.transfer.declined"
+	AuditTransferCancelled = "device.transfer.cancelled"
+	AuditTransferExpired   = "device.transfer.expired"
+)
+
+// AuditLog records an action taken by ActorID on an entity. Entries are
+// append-only; Details holds action specific data.
+type AuditLog struct {
+	ID         uuid.UUID      `json:"id" gorm:"type:uuid;primary_key"`
+	ActorID    uuid.UUID      `json:"actor_id" gorm:"type:uuid;not null;index"`
+	Action     string         `json:"action" gorm:"not null"`
+	EntityType string         `json:"entity_type" gorm:"not null;index:idx_audit_logs_entity,priority:1"`
+	EntityID   uuid.UUID      `json:"entity_id" gorm:"type:uuid;not null;index:idx_audit_logs_entity,priority:2"`
+	Details    datatypes.JSON `json:"details" gorm:"type:jsonb"`
+	CreatedAt  time.Time      `json:"created_at" gorm:"index"`
+}
@@ -1,18 +1,38 @@
 package models
 
 import (
+	"crypto/subtle"
 	"time"
 
 	"github.com/google/uuid"
+	"gorm.io/gorm"
 )
 
 type Device struct {
-	UUID        uuid.UUID `json:"uuid" db:"uuid"`
-	Name        string    `json:"name" db:"name"`
-	Location    string    `json:"location" db:"location"`
-	SN          string    `json:"sn" db:"sn"`
-	Description string    `json:"description" db:"description"`
-	UserID      uuid.UUID `json:"user_id" db:"user_id"`
-	CreatedAt   time.Time `json:"created_at" db:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at" db:"updated_at"`
-}
\ No newline at end of file
+	UUID            uuid.UUID      `json:"uuid" db:"uuid"`
+	Name            string         `json:"name" db:"name" gorm:"index"`
+	Location        string         `json:"location" db:"location" gorm:"index:idx_devices_user_location,priority:2"`
+	SN              string         `json:"sn" db:"sn"`
+	Description     string         `json:"description" db:"description"`
+	Labels          Labels         `json:"labels" db:"labels" gorm:"type:jsonb;not null;default:'{}';index:,type:gin"`
+	UserID          uuid.UUID      `json:"user_id" db:"user_id" gorm:"index:idx_devices_user_location,priority:1"`
+	GroupID         *uuid.UUID     `json:"group_id" db:"group_id" gorm:"type:uuid;index"`
+	OrganizationID  *uuid.UUID     `json:"organization_id" db:"organization_id" gorm:"type:uuid;index"`
+	Shared          bool           `json:"shared" db:"-" gorm:"->;-:migration"`
+	TokenHash       string         `json:"-" db:"token_hash"`
+	FirmwareVersion string         `json:"firmware_version" db:"firmware_version" gorm:"index"`
+	LastSeenAt      *time.Time     `json:"last_seen_at" db:"last_seen_at" gorm:"index"`
+	CreatedAt       time.Time      `json:"created_at" db:"created_at" gorm:"index"`
+	UpdatedAt       time.Time      `json:"updated_at" db:"updated_at"`
+	DeletedAt       gorm.DeletedAt `json:"deleted_at" db:"deleted_at" gorm:"index"`
+}
+
+// CheckToken reports whether token is the device token issued when the
+// device was provisioned. Devices created through the API were never
+// provisioned and have no token, so any message for them is accepted.
+func (d *Device) CheckToken(token string) bool {
+	if d.TokenHash == "" {
+		return true
+	}
+	return subtle.ConstantTimeCompare([]byte(d.TokenHash), []byte(HashSecret(token))) == 1
+}
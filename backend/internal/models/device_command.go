@@ -0,0 +1,59 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/datatypes"
+)
+
+const (
+	CommandPending      = "pending"
+	CommandSent         = "sent"
+	CommandAcknowledged = "acknowledged"
+	CommandSucceeded    = "succeeded"
+	CommandFailed       = "failed"
+	CommandExpired      = "expired"
+	CommandCancelled    = "cancelled"
+)
+
+const (
+	CommandReboot         = "reboot"
+	CommandUploadLogs     = "upload_logs"
+	CommandRunDiagnostics = "run_diagnostics"
+)
+
+// DeviceCommand is a request for a device to run an action. It is pending
+// until published to the device, sent until the device acknowledges it and
+// then ends as succeeded or failed. A command that does not finish before
+// ExpiresAt expires; pending and sent commands can be cancelled.
+type DeviceCommand struct {
+	ID             uuid.UUID      `json:"id" gorm:"type:uuid;primary_key"`
+	DeviceID       uuid.UUID      `json:"device_id" gorm:"type:uuid;not null;index:idx_device_commands_device,priority:1"`
+	IssuedBy       uuid.UUID      `json:"issued_by" gorm:"type:uuid;not null"`
+	Name           string         `json:"name" gorm:"not null"`
+	Params         datatypes.JSON `json:"params" gorm:"type:jsonb;not null;default:'{}'"`
+	Status         string         `json:"status" gorm:"not null;index"`
+	Result         datatypes.JSON `json:"result" gorm:"type:jsonb"`
+	Error          string         `json:"error"`
+	ExpiresAt      time.Time      `json:"expires_at" gorm:"not null;index"`
+	SentAt         *time.Time     `json:"sent_at"`
+	AcknowledgedAt *time.Time     `json:"acknowledged_at"`
+	CompletedAt    *time.Time     `json:"completed_at"`
+	CreatedAt      time.Time      `json:"created_at" gorm:"index:idx_device_commands_device,priority:2"`
+	UpdatedAt      time.Time      `json:"updated_at"`
+}
+
+// ValidCommandName reports whether devices know how to run name.
+func ValidCommandName(name string) bool {
+	return name == CommandReboot || name == CommandUploadLogs || name == CommandRunDiagnostics
+}
+
+// CommandFinished reports whether status is a final command status.
+func CommandFinished(status string) bool {
+	switch status {
+	case CommandSucceeded, CommandFailed, CommandExpired, CommandCancelled:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,20 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DeviceGroup organizes devices in a tree (e.g. region > site > rack). A group
+// without ParentID is a root group.
+type DeviceGroup struct {
+	ID             uuid.UUID  `json:"id" gorm:"type:uuid;primary_key"`
+	UserID         uuid.UUID  `json:"user_id" gorm:"type:uuid;not null;index"`
+	OrganizationID *uuid.UUID `json:"organization_id" gorm:"type:uuid;index"`
+	ParentID       *uuid.UUID `json:"parent_id" gorm:"type:uuid;index"`
+	Name           string     `json:"name" gorm:"not null"`
+	Description    string     `json:"description"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+}
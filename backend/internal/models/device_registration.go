@@ -0,0 +1,46 @@
+package models
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DeviceRegistration pre-registers a serial number for zero-touch
+// provisioning. A registration without UserID is unassigned until a user
+// claims it. The device presents its one-time claim code to /provision to
+// receive its credentials; ProvisionedAt is set once the code was used.
+type DeviceRegistration struct {
+	ID             uuid.UUID  `json:"id" gorm:"type:uuid;primary_key"`
+	SN             string     `json:"sn" gorm:"not null;uniqueIndex"`
+	Name           string     `json:"name" gorm:"not null"`
+	Location       string     `json:"location" gorm:"not null"`
+	Description    string     `json:"description"`
+	ClaimCodeHash  string     `json:"-" gorm:"not null"`
+	RegisteredBy   uuid.UUID  `json:"registered_by" gorm:"type:uuid;not null;index"`
+	UserID         *uuid.UUID `json:"user_id" gorm:"type:uuid;index"`
+	OrganizationID *uuid.UUID `json:"organization_id" gorm:"type:uuid;index"`
+	DeviceID       *uuid.UUID `json:"device_id" gorm:"type:uuid"`
+	ProvisionedAt  *time.Time `json:"provisioned_at"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+}
+
+func (r *DeviceRegistration) SetClaimCode(code string) {
+	r.ClaimCodeHash = HashSecret(code)
+}
+
+func (r *DeviceRegistration) CheckClaimCode(code string) bool {
+	return subtle.ConstantTimeCompare([]byte(r.ClaimCodeHash), []byte(HashSecret(code))) == 1
+}
+
+// HashSecret hashes a randomly generated secret such as a claim code or a
+// device token. These carry enough entropy that, unlike passwords, they do
+// not need a slow hash.
+func HashSecret(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
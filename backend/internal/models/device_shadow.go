@@ -0,0 +1,23 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/datatypes"
+)
+
+// DeviceShadow holds the configuration a device should run with (Desired)
+// next to the configuration it last reported (Reported). Each section has
+// its own version, incremented on every change.
+type DeviceShadow struct {
+	DeviceID          uuid.UUID      `json:"device_id" gorm:"type:uuid;primary_key"`
+	Desired           datatypes.JSON `json:"desired" gorm:"type:jsonb;not null;default:'{}'"`
+	Reported          datatypes.JSON `json:"reported" gorm:"type:jsonb;not null;default:'{}'"`
+	DesiredVersion    int64          `json:"desired_version" gorm:"not null;default:0"`
+	ReportedVersion   int64          `json:"reported_version" gorm:"not null;default:0"`
+	DesiredUpdatedAt  *time.Time     `json:"desired_updated_at"`
+	ReportedUpdatedAt *time.Time     `json:"reported_updated_at"`
+	CreatedAt         time.Time      `json:"created_at"`
+	UpdatedAt         time.Time      `json:"updated_at"`
+}
@@ -0,0 +1,27 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DeviceShare grants a single user access to one device without making them
+// a member of the owning organization. Permission is RoleViewer (read-only)
+// or RoleOperator; a grant past ExpiresAt no longer gives access.
+type DeviceShare struct {
+	ID         uuid.UUID  `json:"id" gorm:"type:uuid;primary_key"`
+	DeviceID   uuid.UUID  `json:"device_id" gorm:"type:uuid;not null;uniqueIndex:idx_device_shares_device_grantee,priority:1"`
+	GranteeID  uuid.UUID  `json:"grantee_id" gorm:"type:uuid;not null;uniqueIndex:idx_device_shares_device_grantee,priority:2;index"`
+	GrantedBy  uuid.UUID  `json:"granted_by" gorm:"type:uuid;not null"`
+	Permission string     `json:"permission" gorm:"not null"`
+	ExpiresAt  *time.Time `json:"expires_at" gorm:"index"`
+	Grantee    User       `json:"-" gorm:"foreignKey:GranteeID"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+}
+
+// ValidSharePermission reports whether permission can be granted on a share.
+func ValidSharePermission(permission string) bool {
+	return permission == RoleViewer || permission == RoleOperator
+}
@@ -0,0 +1,41 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	TransferPending   = "pending"
+	TransferAccepted  = "accepted"
+	TransferDeclined  = "declined"
+	TransferCancelled = "cancelled"
+	TransferExpired   = "expired"
+)
+
+// DeviceTransfer hands a personal device over to another user. The owner
+// addresses the transfer to an email; the user registered under that email
+// accepts it before ExpiresAt. A pending transfer past ExpiresAt is expired
+// even if Status has not been updated yet.
+type DeviceTransfer struct {
+	ID          uuid.UUID  `json:"id" gorm:"type:uuid;primary_key"`
+	DeviceID    uuid.UUID  `json:"device_id" gorm:"type:uuid;not null;index"`
+	FromUserID  uuid.UUID  `json:"from_user_id" gorm:"type:uuid;not null;index"`
+	ToEmail     string     `json:"to_email" gorm:"not null;index"`
+	ToUserID    *uuid.UUID `json:"to_user_id" gorm:"type:uuid"`
+	Status      string     `json:"status" gorm:"not null;index"`
+	ExpiresAt   time.Time  `json:"expires_at" gorm:"not null"`
+	CompletedAt *time.Time `json:"completed_at"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+// StatusAt returns the status of the transfer at now, reporting pending
+// transfers past their expiry as expired.
+func (t *DeviceTransfer) StatusAt(now time.Time) string {
+	if t.Status == TransferPending && !t.ExpiresAt.After(now) {
+		return TransferExpired
+	}
+	return t.Status
+}
@@ -0,0 +1,31 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/datatypes"
+)
+
+// EscalationPolicy pages more people the longer an alert of a rule stays
+// unacknowledged. Its Steps run in order.
+type EscalationPolicy struct {
+	ID             uuid.UUID                           `json:"id" gorm:"type:uuid;primary_key"`
+	UserID         uuid.UUID                           `json:"user_id" gorm:"type:uuid;not null;index"`
+	OrganizationID *uuid.UUID                          `json:"organization_id" gorm:"type:uuid;index"`
+	Name           string                              `json:"name" gorm:"not null"`
+	Description    string                              `json:"description"`
+	Steps          datatypes.JSONSlice[EscalationStep] `json:"steps" gorm:"type:jsonb;not null"`
+	CreatedAt      time.Time                           `json:"created_at"`
+	UpdatedAt      time.Time                           `json:"updated_at"`
+}
+
+// EscalationStep notifies its users over WebSocket and its channels
+// DelayMinutes after the previous step (or after the alert fired, for the
+// first step), then Repeat more times, DelayMinutes apart.
+type EscalationStep struct {
+	DelayMinutes int         `json:"delay_minutes"`
+	UserIDs      []uuid.UUID `json:"user_ids"`
+	ChannelIDs   []uuid.UUID `json:"channel_ids"`
+	Repeat       int         `json:"repeat"`
+}
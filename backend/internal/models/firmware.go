@@ -0,0 +1,93 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/datatypes"
+)
+
+const (
+	RolloutRunning   = "running"
+	RolloutPaused    = "paused"
+	RolloutCompleted = "completed"
+	RolloutCancelled = "cancelled"
+)
+
+const (
+	RolloutDevicePending   = "pending"
+	RolloutDeviceUpdating  = "updating"
+	RolloutDeviceSucceeded = "succeeded"
+	RolloutDeviceFailed    = "failed"
+	RolloutDeviceSkipped   = "skipped"
+)
+
+// CommandUpdateFirmware is the device command rollouts use to install a
+// firmware artifact. It is not accepted by the commands API.
+const CommandUpdateFirmware = "update_firmware"
+
+// FirmwareArtifact is a firmware image uploaded to the registry. The file is
+// kept by the firmware storage under the artifact ID; Checksum is its SHA-256.
+type FirmwareArtifact struct {
+	ID             uuid.UUID  `json:"id" gorm:"type:uuid;primary_key"`
+	UserID         uuid.UUID  `json:"user_id" gorm:"type:uuid;not null;index"`
+	OrganizationID *uuid.UUID `json:"organization_id" gorm:"type:uuid;index"`
+	Version        string     `json:"version" gorm:"not null;index"`
+	FileName       string     `json:"file_name" gorm:"not null"`
+	Size           int64      `json:"size" gorm:"not null"`
+	Checksum       string     `json:"checksum" gorm:"not null"`
+	Notes          string     `json:"notes"`
+	CreatedAt      time.Time  `json:"created_at"`
+}
+
+// FirmwareRollout installs an artifact on the devices of a group and/or a
+// label selector in waves. Waves holds the cumulative percentage of the
+// targeted devices each wave reaches; the last one is always 100. A running
+// rollout pauses itself when more than MaxFailurePercent of the devices of the
+// current wave fail to update, or update but then fire alerts or stop sending
+// heartbeats. ResumedAt restarts that health check for the devices already
+// updated.
+type FirmwareRollout struct {
+	ID                 uuid.UUID                `json:"id" gorm:"type:uuid;primary_key"`
+	UserID             uuid.UUID                `json:"user_id" gorm:"type:uuid;not null;index"`
+	OrganizationID     *uuid.UUID               `json:"organization_id" gorm:"type:uuid;index"`
+	ArtifactID         uuid.UUID                `json:"artifact_id" gorm:"type:uuid;not null;index"`
+	Version            string                   `json:"version" gorm:"not null"`
+	GroupID            *uuid.UUID               `json:"group_id" gorm:"type:uuid"`
+	LabelSelector      string                   `json:"label_selector"`
+	Waves              datatypes.JSONSlice[int] `json:"waves" gorm:"type:jsonb;not null"`
+	CurrentWave        int                      `json:"current_wave" gorm:"not null"`
+	WaveTimeoutSeconds int                      `json:"wave_timeout_seconds" gorm:"not null"`
+	MaxFailurePercent  int                      `json:"max_failure_percent" gorm:"not null"`
+	Status             string                   `json:"status" gorm:"not null;index"`
+	PauseReason        string                   `json:"pause_reason"`
+	WaveStartedAt      *time.Time               `json:"wave_started_at"`
+	ResumedAt          *time.Time               `json:"resumed_at"`
+	CompletedAt        *time.Time               `json:"completed_at"`
+	CreatedAt          time.Time                `json:"created_at"`
+	UpdatedAt          time.Time                `json:"updated_at"`
+}
+
+// FirmwareRolloutDevice tracks one targeted device of a rollout. A device is
+// updating from the moment its update_firmware command is issued until it
+// reports the new version in a heartbeat (succeeded), or its command fails or
+// the wave timeout passes (failed). Devices already running the version are
+// skipped.
+type FirmwareRolloutDevice struct {
+	RolloutID         uuid.UUID  `json:"rollout_id" gorm:"type:uuid;primary_key"`
+	DeviceID          uuid.UUID  `json:"device_id" gorm:"type:uuid;primary_key;index"`
+	Wave              int        `json:"wave" gorm:"not null"`
+	Status            string     `json:"status" gorm:"not null;index"`
+	PreviousVersion   string     `json:"previous_version"`
+	CommandID         *uuid.UUID `json:"command_id" gorm:"type:uuid"`
+	DownloadTokenHash string     `json:"-" gorm:"index"`
+	Error             string     `json:"error"`
+	StartedAt         *time.Time `json:"started_at"`
+	CompletedAt       *time.Time `json:"completed_at"`
+}
+
+// RolloutDeviceFinished reports whether status is a final rollout device
+// status.
+func RolloutDeviceFinished(status string) bool {
+	return status == RolloutDeviceSucceeded || status == RolloutDeviceFailed || status == RolloutDeviceSkipped
+}
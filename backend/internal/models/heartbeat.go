@@ -9,7 +9,7 @@ import (
 
 type Heartbeat struct {
     ID           uuid.UUID `json:"id" gorm:"type:uuid;primary_key"`
-    DeviceID     uuid.UUID `json:"device_id" gorm:"type:uuid;not null"`
+    DeviceID     uuid.UUID `json:"device_id" gorm:"type:uuid;not null;index:idx_heartbeats_device_created,priority:1"`
     CPU          float64   `json:"cpu" gorm:"not null"`
     RAM          float64   `json:"ram" gorm:"not null"`                 
     DiskFree     float64   `json:"disk_free" gorm:"not null"`              
@@ -17,7 +17,7 @@ type Heartbeat struct {
     Latency      int       `json:"latency" gorm:"not null"`              
     Connectivity int       `json:"connectivity" gorm:"not null"`           
     BootTime     time.Time `json:"boot_time" gorm:"not null"`              
-    CreatedAt    time.Time `json:"created_at" gorm:"not null"`
+    CreatedAt    time.Time `json:"created_at" gorm:"not null;index:idx_heartbeats_device_created,priority:2"`
 }
 
 func (h *Heartbeat) BeforeCreate(tx *gorm.DB) error {
@@ -0,0 +1,49 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/datatypes"
+)
+
+// MaintenanceWindow suppresses the alerts of the devices in its scope while
+// it is active. A one-off window runs from StartsAt to EndsAt. A recurring
+// window runs for DurationMinutes from every occurrence of Schedule (a cron
+// expression evaluated in Timezone) between StartsAt and the optional
+// EndsAt. The scope is the union of DeviceIDs, the devices of GroupIDs (with
+// their subgroups) and the devices at Locations.
+type MaintenanceWindow struct {
+	ID              uuid.UUID                      `json:"id" gorm:"type:uuid;primary_key"`
+	UserID          uuid.UUID                      `json:"user_id" gorm:"type:uuid;not null;index"`
+	OrganizationID  *uuid.UUID                     `json:"organization_id" gorm:"type:uuid;index"`
+	Name            string                         `json:"name" gorm:"not null"`
+	Description     string                         `json:"description"`
+	StartsAt        time.Time                      `json:"starts_at" gorm:"not null;index"`
+	EndsAt          *time.Time                     `json:"ends_at" gorm:"index"`
+	Schedule        string                         `json:"schedule"`
+	DurationMinutes int                            `json:"duration_minutes"`
+	Timezone        string                         `json:"timezone"`
+	DeviceIDs       datatypes.JSONSlice[uuid.UUID] `json:"device_ids" gorm:"type:jsonb;not null"`
+	GroupIDs        datatypes.JSONSlice[uuid.UUID] `json:"group_ids" gorm:"type:jsonb;not null"`
+	Locations       datatypes.JSONSlice[string]    `json:"locations" gorm:"type:jsonb;not null"`
+	CreatedAt       time.Time                      `json:"created_at"`
+	UpdatedAt       time.Time                      `json:"updated_at"`
+}
+
+// Recurring reports whether the window repeats on a schedule.
+func (w *MaintenanceWindow) Recurring() bool {
+	return w.Schedule != ""
+}
+
+// SuppressedAlert records a notification rule that triggered for a device
+// while a maintenance window was active and was therefore not sent.
+type SuppressedAlert struct {
+	ID             uuid.UUID `json:"id" gorm:"type:uuid;primary_key"`
+	WindowID       uuid.UUID `json:"window_id" gorm:"type:uuid;not null;index"`
+	NotificationID uuid.UUID `json:"notification_id" gorm:"type:uuid;not null"`
+	DeviceID       uuid.UUID `json:"device_id" gorm:"type:uuid;not null;index"`
+	Name           string    `json:"name"`
+	TriggeredValue float64   `json:"triggered_value"`
+	CreatedAt      time.Time `json:"created_at" gorm:"index"`
+}
@@ -0,0 +1,46 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Mute silences a notification rule, a device, or a rule on a single device
+// until ExpiresAt or until it is cancelled. NotificationID and DeviceID set
+// the scope; at least one of them is set. UserID and OrganizationID are the
+// owner of the muted rule or device, CreatedBy the user who muted it.
+type Mute struct {
+	ID             uuid.UUID  `json:"id" gorm:"type:uuid;primary_key"`
+	UserID         uuid.UUID  `json:"user_id" gorm:"type:uuid;not null;index"`
+	OrganizationID *uuid.UUID `json:"organization_id" gorm:"type:uuid;index"`
+	NotificationID *uuid.UUID `json:"notification_id" gorm:"type:uuid;index"`
+	DeviceID       *uuid.UUID `json:"device_id" gorm:"type:uuid;index"`
+	Reason         string     `json:"reason"`
+	ExpiresAt      time.Time  `json:"expires_at" gorm:"not null;index"`
+	CancelledAt    *time.Time `json:"cancelled_at"`
+	CreatedBy      uuid.UUID  `json:"created_by" gorm:"type:uuid;not null"`
+	CreatedAt      time.Time  `json:"created_at"`
+}
+
+// Active reports whether the mute is in effect at now.
+func (m *Mute) Active(now time.Time) bool {
+	return m.CancelledAt == nil && now.Before(m.ExpiresAt)
+}
+
+// ActiveAt reports whether the mute was in effect at t, which may be in the
+// past: it had been created and had neither expired nor been cancelled.
+func (m *Mute) ActiveAt(t time.Time) bool {
+	return !t.Before(m.CreatedAt) && t.Before(m.ExpiresAt) && (m.CancelledAt == nil || t.Before(*m.CancelledAt))
+}
+
+// Covers reports whether the mute silences the rule on the device.
+func (m *Mute) Covers(notificationID, deviceID uuid.UUID) bool {
+	if m.NotificationID != nil && *m.NotificationID != notificationID {
+		return false
+	}
+	if m.DeviceID != nil && *m.DeviceID != deviceID {
+		return false
+	}
+	return true
+}
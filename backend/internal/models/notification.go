@@ -7,14 +7,60 @@ import (
 	"gorm.io/datatypes"
 )
 
+const (
+	SeverityInfo     = "info"
+	SeverityWarning  = "warning"
+	SeverityCritical = "critical"
+)
+
+// severityRanks orders severities from the least to the most urgent.
+var severityRanks = map[string]int{
+	SeverityInfo:     1,
+	SeverityWarning:  2,
+	SeverityCritical: 3,
+}
+
+// IsValidSeverity reports whether severity is info, warning or critical.
+func IsValidSeverity(severity string) bool {
+	_, ok := severityRanks[severity]
+	return ok
+}
+
+// SeverityAtLeast reports whether severity is at least minimum. An empty
+// minimum is met by every severity.
+func SeverityAtLeast(severity, minimum string) bool {
+	if minimum == "" {
+		return true
+	}
+	return severityRanks[severity] >= severityRanks[minimum]
+}
+
+// SeveritiesAtLeast returns the severities that are at least minimum.
+func SeveritiesAtLeast(minimum string) []string {
+	var severities []string
+	for _, severity := range []string{SeverityInfo, SeverityWarning, SeverityCritical} {
+		if SeverityAtLeast(severity, minimum) {
+			severities = append(severities, severity)
+		}
+	}
+	return severities
+}
+
 type Notification struct {
-	ID          uuid.UUID      `json:"id" gorm:"type:uuid;primary_key"`
-	UserID      uuid.UUID      `json:"user_id"`
-	Name        string         `json:"name"`
-	Description string         `json:"description"`
-	Enabled     bool           `json:"enabled"`
-	Conditions  datatypes.JSON `json:"conditions" gorm:"type:jsonb"`
-	DeviceIDs   datatypes.JSON `json:"device_ids" gorm:"type:jsonb"`
-	CreatedAt   time.Time      `json:"created_at"`
-	UpdatedAt   time.Time      `json:"updated_at"`
-}
\ No newline at end of file
+	ID                 uuid.UUID                      `json:"id" gorm:"type:uuid;primary_key"`
+	UserID             uuid.UUID                      `json:"user_id"`
+	OrganizationID     *uuid.UUID                     `json:"organization_id" gorm:"type:uuid;index"`
+	Name               string                         `json:"name"`
+	Description        string                         `json:"description"`
+	Enabled            bool                           `json:"enabled"`
+	Severity           string                         `json:"severity" gorm:"not null;default:warning"`
+	Conditions         datatypes.JSON                 `json:"conditions" gorm:"type:jsonb"`
+	DeviceIDs          datatypes.JSON                 `json:"device_ids" gorm:"type:jsonb"`
+	LabelSelector      string                         `json:"label_selector"`
+	ChannelIDs         datatypes.JSONSlice[uuid.UUID] `json:"channel_ids" gorm:"type:jsonb"`
+	EscalationPolicyID *uuid.UUID                     `json:"escalation_policy_id" gorm:"type:uuid;index"`
+	TitleTemplate      string                         `json:"title_template"`
+	BodyTemplate       string                         `json:"body_template"`
+	CreatedAt          time.Time                      `json:"created_at"`
+	UpdatedAt          time.Time                      `json:"updated_at"`
+}
@@ -0,0 +1,92 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/datatypes"
+)
+
+const (
+	ChannelWebhook = "webhook"
+	ChannelEmail   = "email"
+	ChannelSlack   = "slack"
+	ChannelTeams   = "teams"
+)
+
+const (
+	DeliveryPending   = "pending"
+	DeliveryDelivered = "delivered"
+	DeliveryFailed    = "failed"
+)
+
+// NotificationChannel is a destination notification rules deliver their
+// alerts to, besides the real-time WebSocket. Config holds the settings of
+// its Type (the URL of a webhook, the recipients of an email...). Alerts
+// below MinSeverity are not delivered to it; an empty MinSeverity accepts
+// them all.
+//
+// Webhook payloads are signed with Secret. After a rotation the previous
+// secret keeps signing them until PreviousSecretExpiresAt, so receivers can
+// switch without rejecting deliveries. A channel that keeps failing is
+// disabled automatically; DisabledAt and DisabledReason record why.
+type NotificationChannel struct {
+	ID                      uuid.UUID      `json:"id" gorm:"type:uuid;primary_key"`
+	UserID                  uuid.UUID      `json:"user_id" gorm:"type:uuid;not null;index"`
+	OrganizationID          *uuid.UUID     `json:"organization_id" gorm:"type:uuid;index"`
+	Name                    string         `json:"name" gorm:"not null"`
+	Type                    string         `json:"type" gorm:"not null"`
+	Config                  datatypes.JSON `json:"config" gorm:"type:jsonb;not null"`
+	Enabled                 bool           `json:"enabled"`
+	MinSeverity             string         `json:"min_severity"`
+	Secret                  string         `json:"-"`
+	PreviousSecret          string         `json:"-"`
+	PreviousSecretExpiresAt *time.Time     `json:"-"`
+	ConsecutiveFailures     int            `json:"consecutive_failures" gorm:"not null;default:0"`
+	DisabledAt              *time.Time     `json:"disabled_at"`
+	DisabledReason          string         `json:"disabled_reason"`
+	CreatedAt               time.Time      `json:"created_at"`
+	UpdatedAt               time.Time      `json:"updated_at"`
+}
+
+// SigningSecrets returns the secrets payloads are signed with at now: the
+// current one and, during the grace period of a rotation, the previous one.
+func (c *NotificationChannel) SigningSecrets(now time.Time) []string {
+	var secrets []string
+	if c.Secret != "" {
+		secrets = append(secrets, c.Secret)
+	}
+	if c.PreviousSecret != "" && c.PreviousSecretExpiresAt != nil && now.Before(*c.PreviousSecretExpiresAt) {
+		secrets = append(secrets, c.PreviousSecret)
+	}
+	return secrets
+}
+
+// NotificationDelivery is an alert queued for a channel. Deliveries are
+// retried with a growing delay until they succeed or run out of attempts.
+// NotificationID and DeviceID are nil for test messages and digests.
+type NotificationDelivery struct {
+	ID             uuid.UUID      `json:"id" gorm:"type:uuid;primary_key"`
+	ChannelID      uuid.UUID      `json:"channel_id" gorm:"type:uuid;not null;index"`
+	NotificationID *uuid.UUID     `json:"notification_id" gorm:"type:uuid"`
+	DeviceID       *uuid.UUID     `json:"device_id" gorm:"type:uuid;index"`
+	Payload        datatypes.JSON `json:"payload" gorm:"type:jsonb;not null"`
+	Status         string         `json:"status" gorm:"not null;index:idx_notification_deliveries_due,priority:1"`
+	Attempts       int            `json:"attempts" gorm:"not null"`
+	NextAttemptAt  time.Time      `json:"next_attempt_at" gorm:"index:idx_notification_deliveries_due,priority:2"`
+	LastError      string         `json:"last_error"`
+	DeliveredAt    *time.Time     `json:"delivered_at"`
+	CreatedAt      time.Time      `json:"created_at" gorm:"index"`
+	UpdatedAt      time.Time      `json:"updated_at"`
+}
+
+// DeliveryAttempt logs one try to send a delivery.
+type DeliveryAttempt struct {
+	ID         uuid.UUID `json:"id" gorm:"type:uuid;primary_key"`
+	DeliveryID uuid.UUID `json:"delivery_id" gorm:"type:uuid;not null;index"`
+	Attempt    int       `json:"attempt" gorm:"not null"`
+	Success    bool      `json:"success"`
+	Error      string    `json:"error"`
+	DurationMs int64     `json:"duration_ms"`
+	CreatedAt  time.Time `json:"created_at"`
+}
@@ -0,0 +1,163 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/datatypes"
+)
+
+// What happens to the messages of a user during quiet hours.
+const (
+	QuietHoursDefer  = "defer"
+	QuietHoursDigest = "digest"
+	QuietHoursDrop   = "drop"
+)
+
+// QuietHours is a quiet period starting on Weekday (0 is Sunday) at Start
+// and ending at End, both "HH:MM" in the user's timezone. An End not after
+// Start ends on the following day.
+type QuietHours struct {
+	Weekday int    `json:"weekday"`
+	Start   string `json:"start"`
+	End     string `json:"end"`
+}
+
+// NotificationPreference holds how a user wants to be notified. During
+// quiet hours messages below critical severity (and critical ones too unless
+// CriticalBypassesQuietHours) are deferred until the quiet hours end, held
+// for the daily digest or dropped, according to QuietHoursAction.
+// PreferredChannels maps a severity to a personal channel the user's
+// messages of that severity are also delivered to.
+//
+// The daily digest, when DailyDigest is set, is sent at DigestHour in
+// Timezone; the weekly digest, when WeeklyDigest is set, at the same hour on
+// DigestWeekday. Both report on the user's fleet and are emailed to
+// DigestChannelID.
+type NotificationPreference struct {
+	UserID                     uuid.UUID                                `json:"user_id" gorm:"type:uuid;primary_key"`
+	Timezone                   string                                   `json:"timezone" gorm:"not null"`
+	QuietHours                 datatypes.JSONSlice[QuietHours]          `json:"quiet_hours" gorm:"type:jsonb;not null"`
+	QuietHoursAction           string                                   `json:"quiet_hours_action" gorm:"not null"`
+	CriticalBypassesQuietHours bool                                     `json:"critical_bypasses_quiet_hours"`
+	PreferredChannels          datatypes.JSONType[map[string]uuid.UUID] `json:"preferred_channels" gorm:"type:jsonb;not null"`
+	DailyDigest                bool                                     `json:"daily_digest"`
+	WeeklyDigest               bool                                     `json:"weekly_digest"`
+	DigestHour                 int                                      `json:"digest_hour"`
+	DigestWeekday              int                                      `json:"digest_weekday"`
+	DigestChannelID            *uuid.UUID                               `json:"digest_channel_id" gorm:"type:uuid"`
+	DailyDigestSentAt          *time.Time                               `json:"daily_digest_sent_at"`
+	WeeklyDigestSentAt         *time.Time                               `json:"weekly_digest_sent_at"`
+	CreatedAt                  time.Time                                `json:"created_at"`
+	UpdatedAt                  time.Time                                `json:"updated_at"`
+}
+
+// QuietUntil reports whether now falls in the quiet hours and, if so, when
+// they end. Back-to-back periods are merged.
+func (p *NotificationPreference) QuietUntil(now time.Time) (time.Time, bool) {
+	loc, err := time.LoadLocation(p.Timezone)
+	if err != nil {
+		loc = time.UTC
+	}
+
+	end, quiet := p.quietPeriodEnd(now.In(loc))
+	if !quiet {
+		return time.Time{}, false
+	}
+	for i := 0; i < 7; i++ {
+		next, ok := p.quietPeriodEnd(end)
+		if !ok || !next.After(end) {
+			break
+		}
+		end = next
+	}
+	return end, true
+}
+
+// quietPeriodEnd returns the latest end of the quiet periods covering local.
+// Periods starting the day before are checked too, since they may span
+// midnight.
+func (p *NotificationPreference) quietPeriodEnd(local time.Time) (time.Time, bool) {
+	var end time.Time
+	quiet := false
+	for _, daysAgo := range []int{0, 1} {
+		day := time.Date(local.Year(), local.Month(), local.Day()-daysAgo, 0, 0, 0, 0, local.Location())
+		for _, period := range p.QuietHours {
+			if period.Weekday != int(day.Weekday()) {
+				continue
+			}
+			startMinutes, ok := ParseClock(period.Start)
+			if !ok {
+				continue
+			}
+			endMinutes, ok := ParseClock(period.End)
+			if !ok {
+				continue
+			}
+			if endMinutes <= startMinutes {
+				endMinutes += 24 * 60
+			}
+			start := day.Add(time.Duration(startMinutes) * time.Minute)
+			periodEnd := day.Add(time.Duration(endMinutes) * time.Minute)
+			if !local.Before(start) && local.Before(periodEnd) && periodEnd.After(end) {
+				end, quiet = periodEnd, true
+			}
+		}
+	}
+	return end, quiet
+}
+
+// NextDigestAt returns the first digest time after now.
+func (p *NotificationPreference) NextDigestAt(now time.Time) time.Time {
+	loc, err := time.LoadLocation(p.Timezone)
+	if err != nil {
+		loc = time.UTC
+	}
+	local := now.In(loc)
+	next := time.Date(local.Year(), local.Month(), local.Day(), p.DigestHour, 0, 0, 0, loc)
+	if !next.After(local) {
+		next = time.Date(local.Year(), local.Month(), local.Day()+1, p.DigestHour, 0, 0, 0, loc)
+	}
+	return next
+}
+
+// LastDigestSlot returns the latest time at or before now the daily digest,
+// or the weekly one when weekly is set, is scheduled for.
+func (p *NotificationPreference) LastDigestSlot(now time.Time, weekly bool) time.Time {
+	loc, err := time.LoadLocation(p.Timezone)
+	if err != nil {
+		loc = time.UTC
+	}
+	local := now.In(loc)
+	slot := time.Date(local.Year(), local.Month(), local.Day(), p.DigestHour, 0, 0, 0, loc)
+	if slot.After(local) {
+		slot = time.Date(local.Year(), local.Month(), local.Day()-1, p.DigestHour, 0, 0, 0, loc)
+	}
+	if weekly {
+		daysSince := (int(slot.Weekday()) - p.DigestWeekday + 7) % 7
+		slot = time.Date(slot.Year(), slot.Month(), slot.Day()-daysSince, p.DigestHour, 0, 0, 0, loc)
+	}
+	return slot
+}
+
+// ParseClock parses an "HH:MM" time of day into minutes since midnight.
+func ParseClock(clock string) (int, bool) {
+	t, err := time.Parse("15:04", clock)
+	if err != nil {
+		return 0, false
+	}
+	return t.Hour()*60 + t.Minute(), true
+}
+
+// HeldNotification is a message kept back by the quiet hours of UserID. At
+// ReleaseAt it is delivered to the WebSocket and ChannelIDs on its own or,
+// when Digest is set, together with the other held messages of the user.
+type HeldNotification struct {
+	ID         uuid.UUID                      `json:"id" gorm:"type:uuid;primary_key"`
+	UserID     uuid.UUID                      `json:"user_id" gorm:"type:uuid;not null;index"`
+	ChannelIDs datatypes.JSONSlice[uuid.UUID] `json:"channel_ids" gorm:"type:jsonb;not null"`
+	Payload    datatypes.JSON                 `json:"payload" gorm:"type:jsonb;not null"`
+	Digest     bool                           `json:"digest"`
+	ReleaseAt  time.Time                      `json:"release_at" gorm:"not null;index"`
+	CreatedAt  time.Time                      `json:"created_at"`
+}
@@ -0,0 +1,96 @@
+package mq
+
+import (
+	"encoding/json"
+
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/dto"
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/services"
+	logger "github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/utils/logger"
+	"github.com/rabbitmq/amqp091-go"
+)
+
+// CommandReplyConsumer records the replies devices publish while running
+// commands.
+type CommandReplyConsumer struct {
+	commandService services.DeviceCommandService
+	conn           *amqp091.Connection
+	channel        *amqp091.Channel
+	queueName      string
+}
+
+func NewCommandReplyConsumer(amqpURL, queueName string, commandService services.DeviceCommandService) (*CommandReplyConsumer, error) {
+	conn, err := amqp091.Dial(amqpURL)
+	if err != nil {
+		logger.Logger.Error("Failed to connect to RabbitMQ", "error", err)
+		return nil, err
+	}
+
+	ch, err := conn.Channel()
+	if err != nil {
+		logger.Logger.Error("Failed to open channel", "error", err)
+		conn.Close()
+		return nil, err
+	}
+
+	_, err = ch.QueueDeclare(
+		queueName,
+		true,  // durable
+		false, // delete when unused
+		false, // exclusive
+		false, // no-wait
+		nil,   // arguments
+	)
+	if err != nil {
+		logger.Logger.Error("Failed to declare queue", "error", err)
+		ch.Close()
+		conn.Close()
+		return nil, err
+	}
+
+	return &CommandReplyConsumer{
+		commandService: commandService,
+		conn:           conn,
+		channel:        ch,
+		queueName:      queueName,
+	}, nil
+}
+
+func (c *CommandReplyConsumer) Start() error {
+	msgs, err := c.channel.Consume(
+		c.queueName,
+		"",    // consumer
+		true,  // auto-ack
+		false, // exclusive
+		false, // no-local
+		false, // no-wait
+		nil,   // args
+	)
+	if err != nil {
+		logger.Logger.Error("Failed to start consuming", "error", err)
+		return err
+	}
+
+	go func() {
+		for d := range msgs {
+			var reply dto.DeviceCommandReply
+			if err := json.Unmarshal(d.Body, &reply); err != nil {
+				logger.Logger.Error("Error decoding command reply", "error", err)
+				continue
+			}
+
+			if err := c.commandService.HandleReply(reply); err != nil {
+				logger.Logger.Warn("Command reply rejected", "command_id", reply.CommandID, "device_id", reply.DeviceID, "status", reply.Status, "error", err)
+				continue
+			}
+
+			logger.Logger.Info("Processed command reply", "command_id", reply.CommandID, "device_id", reply.DeviceID, "status", reply.Status)
+		}
+	}()
+
+	return nil
+}
+
+func (c *CommandReplyConsumer) Close() {
+	c.channel.Close()
+	c.conn.Close()
+}
@@ -0,0 +1,132 @@
+package mq
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/dto"
+	logger "github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/utils/logger"
+	"github.com/google/uuid"
+	"github.com/rabbitmq/amqp091-go"
+)
+
+// DeviceExchange is the topic exchange messages addressed to a single device
+// are published to. Devices bind a queue with their own routing keys.
+const DeviceExchange = "devices"
+
+// CommandReplyQueue is the queue devices publish their command replies to.
+const CommandReplyQueue = "command_results"
+
+// ShadowDeltaRoutingKey is the routing key shadow deltas for deviceID are
+// published with.
+func ShadowDeltaRoutingKey(deviceID uuid.UUID) string {
+	return fmt.Sprintf("device.%s.shadow.delta", deviceID)
+}
+
+// CommandRoutingKey is the routing key commands for deviceID are published
+// with.
+func CommandRoutingKey(deviceID uuid.UUID) string {
+	return fmt.Sprintf("device.%s.command", deviceID)
+}
+
+// DevicePublisher publishes messages addressed to a single device on the
+// device exchange.
+type DevicePublisher struct {
+	conn     *amqp091.Connection
+	channel  *amqp091.Channel
+	exchange string
+}
+
+func NewDevicePublisher(amqpURL, exchange string) (*DevicePublisher, error) {
+	conn, err := amqp091.Dial(amqpURL)
+	if err != nil {
+		logger.Logger.Error("Failed to connect to RabbitMQ", "error", err)
+		return nil, err
+	}
+
+	ch, err := conn.Channel()
+	if err != nil {
+		logger.Logger.Error("Failed to open channel", "error", err)
+		conn.Close()
+		return nil, err
+	}
+
+	err = ch.ExchangeDeclare(
+		exchange,
+		"topic",
+		true,  // durable
+		false, // auto-deleted
+		false, // internal
+		false, // no-wait
+		nil,   // arguments
+	)
+	if err != nil {
+		logger.Logger.Error("Failed to declare exchange", "exchange", exchange, "error", err)
+		ch.Close()
+		conn.Close()
+		return nil, err
+	}
+
+	return &DevicePublisher{
+		conn:     conn,
+		channel:  ch,
+		exchange: exchange,
+	}, nil
+}
+
+func (p *DevicePublisher) PublishDelta(msg dto.ShadowDeltaMessage) error {
+	deviceID, err := uuid.Parse(msg.DeviceID)
+	if err != nil {
+		return err
+	}
+	return p.publish(ShadowDeltaRoutingKey(deviceID), msg, msg.Timestamp, "")
+}
+
+// PublishCommand publishes a command to its device. The message expires
+// with the command so that a device coming back online does not run a
+// command nobody waits for anymore.
+func (p *DevicePublisher) PublishCommand(msg dto.DeviceCommandMessage) error {
+	deviceID, err := uuid.Parse(msg.DeviceID)
+	if err != nil {
+		return err
+	}
+
+	ttl := time.Until(msg.ExpiresAt).Milliseconds()
+	if ttl <= 0 {
+		return fmt.Errorf("command %s has expired", msg.ID)
+	}
+	msg.ReplyTo = CommandReplyQueue
+	return p.publish(CommandRoutingKey(deviceID), msg, time.Now().UTC(), fmt.Sprintf("%d", ttl))
+}
+
+func (p *DevicePublisher) publish(routingKey string, msg interface{}, timestamp time.Time, expiration string) error {
+	body, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	return p.channel.PublishWithContext(
+		ctx,
+		p.exchange,
+		routingKey,
+		false, // mandatory
+		false, // immediate
+		amqp091.Publishing{
+			ContentType:  "application/json",
+			DeliveryMode: amqp091.Persistent,
+			Timestamp:    timestamp,
+			Expiration:   expiration,
+			Body:         body,
+		},
+	)
+}
+
+func (p *DevicePublisher) Close() {
+	p.channel.Close()
+	p.conn.Close()
+}
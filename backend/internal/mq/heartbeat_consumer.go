@@ -17,13 +17,15 @@ import (
 type HeartbeatConsumer struct {
 	heartbeatService   services.HeartbeatService
 	notificationService services.NotificationService
+	shadowService      services.DeviceShadowService
+	firmwareService    services.FirmwareService
 	deviceRepo         repository.DeviceRepository
 	conn               *amqp091.Connection
 	channel            *amqp091.Channel
 	queueName          string
 }
 
-func NewHeartbeatConsumer(amqpURL, queueName string, heartbeatService services.HeartbeatService, notificationService services.NotificationService, deviceRepo repository.DeviceRepository) (*HeartbeatConsumer, error) {
+func NewHeartbeatConsumer(amqpURL, queueName string, heartbeatService services.HeartbeatService, notificationService services.NotificationService, shadowService services.DeviceShadowService, firmwareService services.FirmwareService, deviceRepo repository.DeviceRepository) (*HeartbeatConsumer, error) {
 	logger.Logger.Info("Connecting to RabbitMQ", "url", amqpURL)
 
 	conn, err := amqp091.Dial(amqpURL)
@@ -54,6 +56,8 @@ func NewHeartbeatConsumer(amqpURL, queueName string, heartbeatService services.H
 	return &HeartbeatConsumer{
 		heartbeatService:   heartbeatService,
 		notificationService: notificationService,
+		shadowService:      shadowService,
+		firmwareService:    firmwareService,
 		deviceRepo:         deviceRepo,
 		conn:               conn,
 		channel:            ch,
@@ -95,6 +99,22 @@ func (c *HeartbeatConsumer) Start() error {
 				logger.Logger.Error("Error getting device", "device_id", msg.DeviceID, "error", err)
 				continue
 			}
+			if !device.CheckToken(msg.Token) {
+				logger.Logger.Warn("Heartbeat rejected: invalid device token", "device_id", deviceID)
+				continue
+			}
+
+			if msg.Reported != nil {
+				if err := c.shadowService.UpdateReported(deviceID, msg.Reported); err != nil {
+					logger.Logger.Error("Error updating reported state", "device_id", deviceID, "error", err)
+				}
+			}
+
+			if msg.FirmwareVersion != "" {
+				if err := c.firmwareService.ReportVersion(deviceID, msg.FirmwareVersion); err != nil {
+					logger.Logger.Error("Error updating firmware version", "device_id", deviceID, "error", err)
+				}
+			}
 
 			_, err = c.heartbeatService.CreateHeartbeat(
 				deviceID,
@@ -0,0 +1,208 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/models"
+	"github.com/google/uuid"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+// openAlertStatuses are the statuses of alerts whose rule is still firing.
+var openAlertStatuses = []string{models.AlertFiring, models.AlertAcknowledged}
+
+type AlertRepository interface {
+	Create(alert *models.Alert) error
+	FindByID(id uuid.UUID) (*models.Alert, error)
+	FindOpen(notificationID, deviceID uuid.UUID) (*models.Alert, error)
+	FindByUserID(userID uuid.UUID, filter AlertFilter) ([]models.Alert, error)
+	FindFiredByDevices(deviceIDs []uuid.UUID, since time.Time) ([]models.Alert, error)
+	CountFiredBySeverity(userID uuid.UUID, from, to time.Time) (map[string]int64, error)
+	Retrigger(id uuid.UUID, triggeredValue float64, conditions, payload datatypes.JSON, now time.Time) error
+	Resolve(deviceID uuid.UUID, notificationIDs []uuid.UUID, now time.Time) (int64, error)
+	Transition(id uuid.UUID, from []string, updates map[string]interface{}) error
+	FindDueEscalations(now time.Time, limit int) ([]models.Alert, error)
+}
+
+// AlertFilter narrows an alert listing. Empty fields match every alert.
+type AlertFilter struct {
+	Status         string
+	Severities     []string
+	NotificationID *uuid.UUID
+	DeviceID       *uuid.UUID
+	FiredFrom      *time.Time
+	FiredTo        *time.Time
+	Limit          int
+}
+
+type alertRepository struct {
+	db *gorm.DB
+}
+
+func NewAlertRepository(db *gorm.DB) AlertRepository {
+	return &alertRepository{db: db}
+}
+
+func (r *alertRepository) Create(alert *models.Alert) error {
+	return r.db.Create(alert).Error
+}
+
+func (r *alertRepository) FindByID(id uuid.UUID) (*models.Alert, error) {
+	var alert models.Alert
+	err := r.db.Where("id = ?", id).First(&alert).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, gorm.ErrRecordNotFound
+		}
+		return nil, err
+	}
+	return &alert, nil
+}
+
+// FindOpen returns the unresolved alert of the rule for the device.
+func (r *alertRepository) FindOpen(notificationID, deviceID uuid.UUID) (*models.Alert, error) {
+	var alert models.Alert
+	err := r.db.Where("notification_id = ? AND device_id = ? AND status IN ?", notificationID, deviceID, openAlertStatuses).
+		Order("fired_at DESC").
+		First(&alert).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, gorm.ErrRecordNotFound
+		}
+		return nil, err
+	}
+	return &alert, nil
+}
+
+// FindByUserID returns the latest alerts of the user's personal rules and of
+// the rules of the organizations the user belongs to, newest first.
+func (r *alertRepository) FindByUserID(userID uuid.UUID, filter AlertFilter) ([]models.Alert, error) {
+	query := r.db.Scopes(accessibleBy(userID))
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+	if len(filter.Severities) > 0 {
+		query = query.Where("severity IN ?", filter.Severities)
+	}
+	if filter.NotificationID != nil {
+		query = query.Where("notification_id = ?", *filter.NotificationID)
+	}
+	if filter.DeviceID != nil {
+		query = query.Where("device_id = ?", *filter.DeviceID)
+	}
+	if filter.FiredFrom != nil {
+		query = query.Where("fired_at >= ?", *filter.FiredFrom)
+	}
+	if filter.FiredTo != nil {
+		query = query.Where("fired_at < ?", *filter.FiredTo)
+	}
+	if filter.Limit > 0 {
+		query = query.Limit(filter.Limit)
+	}
+
+	var alerts []models.Alert
+	if err := query.Order("fired_at DESC").Find(&alerts).Error; err != nil {
+		return nil, err
+	}
+	return alerts, nil
+}
+
+// FindFiredByDevices returns the alerts of the devices that fired at or
+// after since.
+func (r *alertRepository) FindFiredByDevices(deviceIDs []uuid.UUID, since time.Time) ([]models.Alert, error) {
+	var alerts []models.Alert
+	if len(deviceIDs) == 0 {
+		return alerts, nil
+	}
+	err := r.db.Where("device_id IN ? AND fired_at >= ?", deviceIDs, since).Find(&alerts).Error
+	if err != nil {
+		return nil, err
+	}
+	return alerts, nil
+}
+
+// CountFiredBySeverity counts the alerts the user can see that fired between
+// from and to, by severity.
+func (r *alertRepository) CountFiredBySeverity(userID uuid.UUID, from, to time.Time) (map[string]int64, error) {
+	var rows []struct {
+		Severity string
+		Count    int64
+	}
+	err := r.db.Model(&models.Alert{}).
+		Scopes(accessibleBy(userID)).
+		Select("severity, COUNT(*) AS count").
+		Where("fired_at >= ? AND fired_at < ?", from, to).
+		Group("severity").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make(map[string]int64, len(rows))
+	for _, row := range rows {
+		counts[row.Severity] = row.Count
+	}
+	return counts, nil
+}
+
+// Retrigger records another heartbeat triggering an open alert.
+func (r *alertRepository) Retrigger(id uuid.UUID, triggeredValue float64, conditions, payload datatypes.JSON, now time.Time) error {
+	return r.db.Model(&models.Alert{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"triggered_value":   triggeredValue,
+			"conditions":        conditions,
+			"trigger_count":     gorm.Expr("trigger_count + 1"),
+			"payload":           payload,
+			"last_triggered_at": now,
+			"updated_at":        now,
+		}).Error
+}
+
+// Resolve resolves the open alerts of the rules for the device, which no
+// longer trigger them, and returns how many there were.
+func (r *alertRepository) Resolve(deviceID uuid.UUID, notificationIDs []uuid.UUID, now time.Time) (int64, error) {
+	if len(notificationIDs) == 0 {
+		return 0, nil
+	}
+	result := r.db.Model(&models.Alert{}).
+		Where("device_id = ? AND notification_id IN ? AND status IN ?", deviceID, notificationIDs, openAlertStatuses).
+		Updates(map[string]interface{}{
+			"status":             models.AlertResolved,
+			"resolved_at":        now,
+			"next_escalation_at": nil,
+			"updated_at":         now,
+		})
+	return result.RowsAffected, result.Error
+}
+
+// Transition applies updates to the alert if its status is one of from. It
+// returns gorm.ErrRecordNotFound when the alert is in another status.
+func (r *alertRepository) Transition(id uuid.UUID, from []string, updates map[string]interface{}) error {
+	result := r.db.Model(&models.Alert{}).
+		Where("id = ? AND status IN ?", id, from).
+		Updates(updates)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// FindDueEscalations returns the firing alerts whose next escalation step is
+// due, oldest first.
+func (r *alertRepository) FindDueEscalations(now time.Time, limit int) ([]models.Alert, error) {
+	var alerts []models.Alert
+	err := r.db.Where("status = ? AND next_escalation_at <= ?", models.AlertFiring, now).
+		Order("next_escalation_at").
+		Limit(limit).
+		Find(&alerts).Error
+	if err != nil {
+		return nil, err
+	}
+	return alerts, nil
+}
@@ -0,0 +1,36 @@
+package repository
+
+import (
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type AuditLogRepository interface {
+	Create(entry *models.AuditLog) error
+	FindByEntity(entityType string, entityID uuid.UUID) ([]models.AuditLog, error)
+}
+
+type auditLogRepository struct {
+	db *gorm.DB
+}
+
+func NewAuditLogRepository(db *gorm.DB) AuditLogRepository {
+	return &auditLogRepository{db: db}
+}
+
+func (r *auditLogRepository) Create(entry *models.AuditLog) error {
+	return r.db.Create(entry).Error
+}
+
+// FindByEntity returns the history of an entity, oldest entry first.
+func (r *auditLogRepository) FindByEntity(entityType string, entityID uuid.UUID) ([]models.AuditLog, error) {
+	var entries []models.AuditLog
+	err := r.db.Where("entity_type = ? AND entity_id = ?", entityType, entityID).
+		Order("created_at ASC").
+		Find(&entries).Error
+	if err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
@@ -0,0 +1,101 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// MaxDeviceCommands caps how many commands of a device are listed.
+const MaxDeviceCommands = 100
+
+type DeviceCommandRepository interface {
+	Create(command *models.DeviceCommand) error
+	FindByID(id uuid.UUID) (*models.DeviceCommand, error)
+	FindByDeviceID(deviceID uuid.UUID, status string) ([]models.DeviceCommand, error)
+	FindUnsent(now time.Time) ([]models.DeviceCommand, error)
+	Transition(id uuid.UUID, from []string, updates map[string]interface{}) error
+	ExpireOverdue(now time.Time) (int64, error)
+}
+
+type deviceCommandRepository struct {
+	db *gorm.DB
+}
+
+func NewDeviceCommandRepository(db *gorm.DB) DeviceCommandRepository {
+	return &deviceCommandRepository{db: db}
+}
+
+func (r *deviceCommandRepository) Create(command *models.DeviceCommand) error {
+	return r.db.Create(command).Error
+}
+
+func (r *deviceCommandRepository) FindByID(id uuid.UUID) (*models.DeviceCommand, error) {
+	var command models.DeviceCommand
+	err := r.db.Where("id = ?", id).First(&command).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, gorm.ErrRecordNotFound
+		}
+		return nil, err
+	}
+	return &command, nil
+}
+
+// FindByDeviceID returns the latest commands of the device, optionally only
+// those in status.
+func (r *deviceCommandRepository) FindByDeviceID(deviceID uuid.UUID, status string) ([]models.DeviceCommand, error) {
+	var commands []models.DeviceCommand
+	query := r.db.Where("device_id = ?", deviceID)
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	err := query.Order("created_at DESC").Limit(MaxDeviceCommands).Find(&commands).Error
+	if err != nil {
+		return nil, err
+	}
+	return commands, nil
+}
+
+// FindUnsent returns the pending commands that have not expired yet.
+func (r *deviceCommandRepository) FindUnsent(now time.Time) ([]models.DeviceCommand, error) {
+	var commands []models.DeviceCommand
+	err := r.db.Where("status = ? AND expires_at > ?", models.CommandPending, now).
+		Order("created_at").
+		Find(&commands).Error
+	if err != nil {
+		return nil, err
+	}
+	return commands, nil
+}
+
+// Transition applies updates to the command if its status is one of from.
+// It returns gorm.ErrRecordNotFound when the command is in another status.
+func (r *deviceCommandRepository) Transition(id uuid.UUID, from []string, updates map[string]interface{}) error {
+	result := r.db.Model(&models.DeviceCommand{}).
+		Where("id = ? AND status IN ?", id, from).
+		Updates(updates)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// ExpireOverdue marks the unfinished commands past their expiry as expired
+// and returns how many there were.
+func (r *deviceCommandRepository) ExpireOverdue(now time.Time) (int64, error) {
+	result := r.db.Model(&models.DeviceCommand{}).
+		Where("status IN ? AND expires_at <= ?", []string{models.CommandPending, models.CommandSent, models.CommandAcknowledged}, now).
+		Updates(map[string]interface{}{
+			"status":       models.CommandExpired,
+			"completed_at": now,
+			"updated_at":   now,
+		})
+	return result.RowsAffected, result.Error
+}
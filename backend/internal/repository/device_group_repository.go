@@ -0,0 +1,110 @@
+package repository
+
+import (
+	"errors"
+
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type DeviceGroupRepository interface {
+	Create(group *models.DeviceGroup) error
+	FindByID(id uuid.UUID) (*models.DeviceGroup, error)
+	FindByUserID(userID uuid.UUID) ([]models.DeviceGroup, error)
+	Update(group *models.DeviceGroup) error
+	Delete(id uuid.UUID) error
+	CountChildren(id uuid.UUID) (int64, error)
+	FindDescendantIDs(id uuid.UUID) ([]uuid.UUID, error)
+}
+
+type deviceGroupRepository struct {
+	db *gorm.DB
+}
+
+func NewDeviceGroupRepository(db *gorm.DB) DeviceGroupRepository {
+	return &deviceGroupRepository{db: db}
+}
+
+func (r *deviceGroupRepository) Create(group *models.DeviceGroup) error {
+	return r.db.Create(group).Error
+}
+
+func (r *deviceGroupRepository) FindByID(id uuid.UUID) (*models.DeviceGroup, error) {
+	var group models.DeviceGroup
+	err := r.db.Where("id = ?", id).First(&group).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, gorm.ErrRecordNotFound
+		}
+		return nil, err
+	}
+	return &group, nil
+}
+
+// FindByUserID returns the personal groups of the user plus the groups of the
+// organizations the user belongs to.
+func (r *deviceGroupRepository) FindByUserID(userID uuid.UUID) ([]models.DeviceGroup, error) {
+	var groups []models.DeviceGroup
+	err := r.db.Scopes(accessibleBy(userID)).Order("name ASC").Find(&groups).Error
+	if err != nil {
+		return nil, err
+	}
+	return groups, nil
+}
+
+// Update writes the editable columns explicitly so a group can also be moved
+// back to the root (ParentID nil).
+func (r *deviceGroupRepository) Update(group *models.DeviceGroup) error {
+	result := r.db.Model(&models.DeviceGroup{}).
+		Where("id = ?", group.ID).
+		Select("parent_id", "name", "description", "updated_at").
+		Updates(group)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// Delete removes the group and detaches its member devices in one transaction.
+func (r *deviceGroupRepository) Delete(id uuid.UUID) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.Device{}).Where("group_id = ?", id).Update("group_id", nil).Error; err != nil {
+			return err
+		}
+		result := tx.Delete(&models.DeviceGroup{}, "id = ?", id)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return nil
+	})
+}
+
+func (r *deviceGroupRepository) CountChildren(id uuid.UUID) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.DeviceGroup{}).Where("parent_id = ?", id).Count(&count).Error
+	return count, err
+}
+
+// FindDescendantIDs returns the group itself followed by every group below it.
+// UNION (rather than UNION ALL) stops the recursion should a cycle ever exist.
+func (r *deviceGroupRepository) FindDescendantIDs(id uuid.UUID) ([]uuid.UUID, error) {
+	var ids []uuid.UUID
+	err := r.db.Raw(`
+		WITH RECURSIVE tree AS (
+			SELECT id FROM device_groups WHERE id = ?
+			UNION
+			SELECT g.id FROM device_groups g JOIN tree t ON g.parent_id = t.id
+		)
+		SELECT id FROM tree`, id).Scan(&ids).Error
+	if err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
@@ -0,0 +1,135 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type DeviceRegistrationRepository interface {
+	CreateBatch(registrations []*models.DeviceRegistration) error
+	FindBySN(sn string) (*models.DeviceRegistration, error)
+	FindBySNs(sns []string) ([]models.DeviceRegistration, error)
+	FindByUserID(userID uuid.UUID) ([]models.DeviceRegistration, error)
+	Claim(registration *models.DeviceRegistration, device *models.Device) error
+	Provision(registration *models.DeviceRegistration, device *models.Device, provisionedAt time.Time) error
+}
+
+type deviceRegistrationRepository struct {
+	db *gorm.DB
+}
+
+func NewDeviceRegistrationRepository(db *gorm.DB) DeviceRegistrationRepository {
+	return &deviceRegistrationRepository{db: db}
+}
+
+func (r *deviceRegistrationRepository) CreateBatch(registrations []*models.DeviceRegistration) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		for _, registration := range registrations {
+			if err := tx.Create(registration).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (r *deviceRegistrationRepository) FindBySN(sn string) (*models.DeviceRegistration, error) {
+	var registration models.DeviceRegistration
+	err := r.db.Where("sn = ?", sn).First(&registration).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, gorm.ErrRecordNotFound
+		}
+		return nil, err
+	}
+	return &registration, nil
+}
+
+func (r *deviceRegistrationRepository) FindBySNs(sns []string) ([]models.DeviceRegistration, error) {
+	var registrations []models.DeviceRegistration
+	if len(sns) == 0 {
+		return registrations, nil
+	}
+	err := r.db.Where("sn IN ?", sns).Find(&registrations).Error
+	if err != nil {
+		return nil, err
+	}
+	return registrations, nil
+}
+
+// FindByUserID returns the registrations made by the user plus those
+// assigned to the user or to one of the user's organizations.
+func (r *deviceRegistrationRepository) FindByUserID(userID uuid.UUID) ([]models.DeviceRegistration, error) {
+	var registrations []models.DeviceRegistration
+	err := r.db.Where("registered_by = ? OR (organization_id IS NULL AND user_id = ?) OR organization_id IN ("+memberOrganizations+")", userID, userID, userID).
+		Order("created_at DESC").
+		Find(&registrations).Error
+	if err != nil {
+		return nil, err
+	}
+	return registrations, nil
+}
+
+// Claim assigns an unassigned registration to the owner of device and
+// creates the device. It returns gorm.ErrRecordNotFound when the
+// registration has been claimed in the meantime.
+func (r *deviceRegistrationRepository) Claim(registration *models.DeviceRegistration, device *models.Device) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.DeviceRegistration{}).
+			Where("id = ? AND user_id IS NULL", registration.ID).
+			Updates(map[string]interface{}{
+				"user_id":         device.UserID,
+				"organization_id": device.OrganizationID,
+				"device_id":       device.UUID,
+				"updated_at":      time.Now(),
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return tx.Create(device).Error
+	})
+}
+
+// Provision spends the claim code and stores the device credentials. The
+// device is created when the registration has none yet, otherwise only its
+// token is replaced. It returns gorm.ErrRecordNotFound when the claim code
+// has been used in the meantime.
+func (r *deviceRegistrationRepository) Provision(registration *models.DeviceRegistration, device *models.Device, provisionedAt time.Time) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.DeviceRegistration{}).
+			Where("id = ? AND provisioned_at IS NULL", registration.ID).
+			Updates(map[string]interface{}{
+				"device_id":      device.UUID,
+				"provisioned_at": provisionedAt,
+				"updated_at":     provisionedAt,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+
+		if registration.DeviceID == nil {
+			return tx.Create(device).Error
+		}
+		result = tx.Model(&models.Device{}).Where("uuid = ?", device.UUID).Updates(map[string]interface{}{
+			"token_hash": device.TokenHash,
+			"updated_at": provisionedAt,
+		})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return nil
+	})
+}
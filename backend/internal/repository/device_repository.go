@@ -1,9 +1,13 @@
 package repository
 
 import (
+	"encoding/json"
 	"errors"
+	"strings"
+	"time"
 
 	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/models"
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/utils"
 	"github.com/google/uuid"
 	"gorm.io/gorm"
 )
@@ -12,9 +16,54 @@ type DeviceRepository interface {
 	FindBySN(sn string) (*models.Device, error)
 	Create(device *models.Device) error
 	FindByID(id uuid.UUID) (*models.Device, error)
+	FindByIDs(ids []uuid.UUID) ([]models.Device, error)
 	FindByUserID(userID uuid.UUID) ([]models.Device, error)
 	Update(device *models.Device) error
-	Delete(id uuid.UUID) error
+	Delete(id uuid.UUID, deleteHeartbeats bool) error
+	FindDeleted(userID uuid.UUID, deletedAfter time.Time) ([]models.Device, error)
+	FindDeletedByID(id uuid.UUID) (*models.Device, error)
+	Restore(id uuid.UUID) error
+	Purge(id uuid.UUID) error
+	FindPurgeable(deletedBefore time.Time) ([]uuid.UUID, error)
+	FindBySNs(sns []string) ([]models.Device, error)
+	CreateBatch(devices []*models.Device) error
+	Search(userID uuid.UUID, filter DeviceFilter) ([]models.Device, int64, error)
+	UpdateLastSeen(id uuid.UUID, seenAt time.Time) error
+	UpdateLabels(id uuid.UUID, labels models.Labels) error
+	FindByGroupIDs(userID uuid.UUID, groupIDs []uuid.UUID) ([]models.Device, error)
+	SetGroup(deviceIDs []uuid.UUID, groupID *uuid.UUID) error
+	SetOrganization(deviceIDs []uuid.UUID, organizationID uuid.UUID) error
+	UpdateFirmwareVersion(id uuid.UUID, version string) error
+	CountByFirmwareVersion(userID uuid.UUID) ([]FirmwareVersionCount, error)
+}
+
+// FirmwareVersionCount is a row of the firmware inventory. Version is empty
+// for devices that never reported one.
+type FirmwareVersionCount struct {
+	Version string
+	Devices int64
+}
+
+// DeviceFilter narrows and orders a device listing. SortBy must be one of the
+// columns in deviceSortColumns; Limit 0 returns every matching device.
+type DeviceFilter struct {
+	Search   string
+	Location string
+	SortBy   string
+	Desc     bool
+	Labels   utils.LabelSelector
+	Limit    int
+	Offset   int
+}
+
+// DeviceSearchExpression is the expression matched by Search. It is kept in
+// sync with the trigram index created in database.AutoMigrate.
+const DeviceSearchExpression = "(name || ' ' || description || ' ' || sn)"
+
+var deviceSortColumns = map[string]string{
+	"name":       "name",
+	"created_at": "created_at",
+	"last_seen":  "last_seen_at",
 }
 
 type deviceRepository struct {
@@ -25,9 +74,11 @@ func NewDeviceRepository(db *gorm.DB) DeviceRepository {
 	return &deviceRepository{db: db}
 }
 
+// FindBySN also returns deleted devices: a serial number only becomes
+// available again once its device has been purged.
 func (r *deviceRepository) FindBySN(sn string) (*models.Device, error) {
 	var device models.Device
-	err := r.db.Where("sn = ?", sn).First(&device).Error
+	err := r.db.Unscoped().Where("sn = ?", sn).First(&device).Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, gorm.ErrRecordNotFound
@@ -53,9 +104,12 @@ func (r *deviceRepository) FindByID(id uuid.UUID) (*models.Device, error) {
 	return &device, nil
 }
 
+// FindByUserID returns every device the user can see: personal devices, the
+// devices of the organizations the user belongs to and devices shared with
+// the user, the latter flagged as Shared.
 func (r *deviceRepository) FindByUserID(userID uuid.UUID) ([]models.Device, error) {
 	var devices []models.Device
-	err := r.db.Where("user_id = ?", userID).Find(&devices).Error
+	err := r.db.Scopes(deviceAccessibleBy(userID), selectShared(userID)).Find(&devices).Error
 	if err != nil {
 		return nil, err
 	}
@@ -73,8 +127,68 @@ func (r *deviceRepository) Update(device *models.Device) error {
 	return nil
 }
 
-func (r *deviceRepository) Delete(id uuid.UUID) error {
-	result := r.db.Where("uuid = ?", id).Delete(&models.Device{})
+// Delete soft-deletes the device. The device is removed from the target
+// lists of every rule right away and its pending alerts are closed: open
+// alerts are resolved and no longer escalated, and the messages held by
+// quiet hours and the deliveries not sent yet are dropped. Its heartbeats
+// are deleted too when deleteHeartbeats is set, otherwise they are kept until
+// the device is purged.
+func (r *deviceRepository) Delete(id uuid.UUID, deleteHeartbeats bool) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("uuid = ?", id).Delete(&models.Device{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+
+		if deleteHeartbeats {
+			if err := tx.Where("device_id = ?", id).Delete(&models.Heartbeat{}).Error; err != nil {
+				return err
+			}
+		}
+		now := time.Now()
+		if err := closePendingAlerts(tx, id, now); err != nil {
+			return err
+		}
+		return detachFromRules(tx, id, now)
+	})
+}
+
+// FindDeleted returns the devices of userID, personal or through an
+// organization, that were deleted after deletedAfter.
+func (r *deviceRepository) FindDeleted(userID uuid.UUID, deletedAfter time.Time) ([]models.Device, error) {
+	var devices []models.Device
+	err := r.db.Unscoped().Scopes(accessibleBy(userID)).
+		Where("deleted_at IS NOT NULL AND deleted_at > ?", deletedAfter).
+		Order("deleted_at DESC").
+		Find(&devices).Error
+	if err != nil {
+		return nil, err
+	}
+	return devices, nil
+}
+
+func (r *deviceRepository) FindDeletedByID(id uuid.UUID) (*models.Device, error) {
+	var device models.Device
+	err := r.db.Unscoped().Where("uuid = ? AND deleted_at IS NOT NULL", id).First(&device).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, gorm.ErrRecordNotFound
+		}
+		return nil, err
+	}
+	return &device, nil
+}
+
+func (r *deviceRepository) Restore(id uuid.UUID) error {
+	result := r.db.Unscoped().Model(&models.Device{}).
+		Where("uuid = ? AND deleted_at IS NOT NULL", id).
+		Updates(map[string]interface{}{
+			"deleted_at": nil,
+			"updated_at": time.Now(),
+		})
 	if result.Error != nil {
 		return result.Error
 	}
@@ -83,3 +197,307 @@ func (r *deviceRepository) Delete(id uuid.UUID) error {
 	}
 	return nil
 }
+
+// Purge permanently removes a deleted device with its heartbeats, shadow,
+// commands, rollout progress, suppressed alerts, channel deliveries, alerts and
+// share grants, freeing its serial number.
+func (r *deviceRepository) Purge(id uuid.UUID) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Unscoped().Where("uuid = ? AND deleted_at IS NOT NULL", id).Delete(&models.Device{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+
+		if err := tx.Where("device_id = ?", id).Delete(&models.Heartbeat{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("device_id = ?", id).Delete(&models.DeviceShadow{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("device_id = ?", id).Delete(&models.DeviceCommand{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("device_id = ?", id).Delete(&models.FirmwareRolloutDevice{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("device_id = ?", id).Delete(&models.SuppressedAlert{}).Error; err != nil {
+			return err
+		}
+		deliveries := tx.Model(&models.NotificationDelivery{}).Select("id").Where("device_id = ?", id)
+		if err := tx.Where("delivery_id IN (?)", deliveries).Delete(&models.DeliveryAttempt{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("device_id = ?", id).Delete(&models.NotificationDelivery{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("device_id = ?", id).Delete(&models.Alert{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("device_id = ?", id).Delete(&models.DeviceShare{}).Error
+	})
+}
+
+// FindPurgeable returns the devices deleted before deletedBefore.
+func (r *deviceRepository) FindPurgeable(deletedBefore time.Time) ([]uuid.UUID, error) {
+	var ids []uuid.UUID
+	err := r.db.Unscoped().Model(&models.Device{}).
+		Where("deleted_at IS NOT NULL AND deleted_at <= ?", deletedBefore).
+		Pluck("uuid", &ids).Error
+	if err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+// FindByIDs returns the devices of ids that were not deleted.
+func (r *deviceRepository) FindByIDs(ids []uuid.UUID) ([]models.Device, error) {
+	var devices []models.Device
+	if len(ids) == 0 {
+		return devices, nil
+	}
+	if err := r.db.Where("uuid IN ?", ids).Find(&devices).Error; err != nil {
+		return nil, err
+	}
+	return devices, nil
+}
+
+func (r *deviceRepository) FindBySNs(sns []string) ([]models.Device, error) {
+	var devices []models.Device
+	if len(sns) == 0 {
+		return devices, nil
+	}
+	err := r.db.Unscoped().Where("sn IN ?", sns).Find(&devices).Error
+	if err != nil {
+		return nil, err
+	}
+	return devices, nil
+}
+
+// CreateBatch inserts all devices in a single transaction: either every
+// device is created or none is.
+func (r *deviceRepository) CreateBatch(devices []*models.Device) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		for _, device := range devices {
+			if err := tx.Create(device).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (r *deviceRepository) Search(userID uuid.UUID, filter DeviceFilter) ([]models.Device, int64, error) {
+	query := r.db.Model(&models.Device{}).Scopes(deviceAccessibleBy(userID))
+	if filter.Location != "" {
+		query = query.Where("location = ?", filter.Location)
+	}
+	if filter.Search != "" {
+		query = query.Where(DeviceSearchExpression+" ILIKE ?", "%"+escapeLike(filter.Search)+"%")
+	}
+	query = whereLabels(query, filter.Labels)
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	column, ok := deviceSortColumns[filter.SortBy]
+	if !ok {
+		column = "created_at"
+	}
+	direction := "ASC"
+	if filter.Desc {
+		direction = "DESC"
+	}
+	query = query.Order(column + " " + direction + " NULLS LAST").Order("uuid ASC")
+
+	if filter.Limit > 0 {
+		query = query.Limit(filter.Limit)
+	}
+	if filter.Offset > 0 {
+		query = query.Offset(filter.Offset)
+	}
+
+	var devices []models.Device
+	if err := query.Scopes(selectShared(userID)).Find(&devices).Error; err != nil {
+		return nil, 0, err
+	}
+	return devices, total, nil
+}
+
+func (r *deviceRepository) UpdateLastSeen(id uuid.UUID, seenAt time.Time) error {
+	return r.db.Model(&models.Device{}).
+		Where("uuid = ? AND (last_seen_at IS NULL OR last_seen_at < ?)", id, seenAt).
+		Update("last_seen_at", seenAt).Error
+}
+
+// UpdateLabels replaces the whole label set of a device.
+func (r *deviceRepository) UpdateLabels(id uuid.UUID, labels models.Labels) error {
+	result := r.db.Model(&models.Device{}).Where("uuid = ?", id).Updates(map[string]interface{}{
+		"labels":     labels,
+		"updated_at": time.Now(),
+	})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *deviceRepository) FindByGroupIDs(userID uuid.UUID, groupIDs []uuid.UUID) ([]models.Device, error) {
+	var devices []models.Device
+	if len(groupIDs) == 0 {
+		return devices, nil
+	}
+	err := r.db.Scopes(accessibleBy(userID)).Where("group_id IN ?", groupIDs).
+		Order("name ASC").
+		Find(&devices).Error
+	if err != nil {
+		return nil, err
+	}
+	return devices, nil
+}
+
+// SetGroup moves the devices into groupID, or out of any group when it is nil.
+func (r *deviceRepository) SetGroup(deviceIDs []uuid.UUID, groupID *uuid.UUID) error {
+	if len(deviceIDs) == 0 {
+		return nil
+	}
+	return r.db.Model(&models.Device{}).Where("uuid IN ?", deviceIDs).Updates(map[string]interface{}{
+		"group_id":   groupID,
+		"updated_at": time.Now(),
+	}).Error
+}
+
+// SetOrganization hands the devices over to an organization. They leave their
+// personal group, which the organization cannot see, and their pending alerts
+// are closed in the same transaction.
+func (r *deviceRepository) SetOrganization(deviceIDs []uuid.UUID, organizationID uuid.UUID) error {
+	if len(deviceIDs) == 0 {
+		return nil
+	}
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		err := tx.Model(&models.Device{}).Where("uuid IN ?", deviceIDs).Updates(map[string]interface{}{
+			"organization_id": organizationID,
+			"group_id":        nil,
+			"updated_at":      now,
+		}).Error
+		if err != nil {
+			return err
+		}
+
+		for _, deviceID := range deviceIDs {
+			if err := closePendingAlerts(tx, deviceID, now); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// UpdateFirmwareVersion records the firmware version a device reported.
+func (r *deviceRepository) UpdateFirmwareVersion(id uuid.UUID, version string) error {
+	return r.db.Model(&models.Device{}).
+		Where("uuid = ? AND firmware_version IS DISTINCT FROM ?", id, version).
+		Update("firmware_version", version).Error
+}
+
+// CountByFirmwareVersion counts the devices userID can reach per firmware
+// version, most common first.
+func (r *deviceRepository) CountByFirmwareVersion(userID uuid.UUID) ([]FirmwareVersionCount, error) {
+	var counts []FirmwareVersionCount
+	err := r.db.Model(&models.Device{}).Scopes(deviceAccessibleBy(userID)).
+		Select("firmware_version AS version, COUNT(*) AS devices").
+		Group("firmware_version").
+		Order("devices DESC, version ASC").
+		Scan(&counts).Error
+	if err != nil {
+		return nil, err
+	}
+	return counts, nil
+}
+
+// deviceAccessibleBy widens accessibleBy with the devices shared with userID
+// through an unexpired grant.
+func deviceAccessibleBy(userID uuid.UUID) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where("((organization_id IS NULL AND user_id = ?) OR organization_id IN ("+memberOrganizations+") OR uuid IN ("+activeShares+"))", userID, userID, userID)
+	}
+}
+
+// selectShared fills Device.Shared: true when userID only reaches the device
+// through a share grant.
+func selectShared(userID uuid.UUID) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Select("devices.*, NOT ((organization_id IS NULL AND user_id = ?) OR organization_id IN ("+memberOrganizations+")) AS shared", userID, userID)
+	}
+}
+
+// whereLabels translates a label selector into jsonb conditions. Equality
+// uses containment so it can be served by the GIN index on labels.
+func whereLabels(query *gorm.DB, selector utils.LabelSelector) *gorm.DB {
+	for _, req := range selector {
+		switch req.Operator {
+		case utils.LabelOpEquals:
+			containment, _ := json.Marshal(map[string]string{req.Key: req.Value})
+			query = query.Where("labels @> ?::jsonb", string(containment))
+		case utils.LabelOpNotEquals:
+			query = query.Where("(labels ->> ?) IS DISTINCT FROM ?", req.Key, req.Value)
+		case utils.LabelOpExists:
+			query = query.Where("(labels ->> ?) IS NOT NULL", req.Key)
+		case utils.LabelOpNotExists:
+			query = query.Where("(labels ->> ?) IS NULL", req.Key)
+		}
+	}
+	return query
+}
+
+func escapeLike(value string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(value)
+}
+
+// closePendingAlerts resolves the open alerts of a device, stopping their
+// escalation, and drops its held messages and undelivered deliveries.
+func closePendingAlerts(db *gorm.DB, deviceID uuid.UUID, now time.Time) error {
+	err := db.Model(&models.Alert{}).
+		Where("device_id = ? AND status IN ?", deviceID, []string{models.AlertFiring, models.AlertAcknowledged}).
+		Updates(map[string]interface{}{
+			"status":             models.AlertResolved,
+			"resolved_at":        now,
+			"next_escalation_at": nil,
+			"updated_at":         now,
+		}).Error
+	if err != nil {
+		return err
+	}
+
+	if err := db.Where("payload->>'device_id' = ?", deviceID.String()).Delete(&models.HeldNotification{}).Error; err != nil {
+		return err
+	}
+
+	pending := db.Model(&models.NotificationDelivery{}).Select("id").Where("device_id = ? AND status = ?", deviceID, models.DeliveryPending)
+	if err := db.Where("delivery_id IN (?)", pending).Delete(&models.DeliveryAttempt{}).Error; err != nil {
+		return err
+	}
+	return db.Where("device_id = ? AND status = ?", deviceID, models.DeliveryPending).Delete(&models.NotificationDelivery{}).Error
+}
+
+// detachFromRules removes the device from the target lists of the rules
+// matched by db. A rule left without devices or label selector would target
+// every device of its owner, so it is disabled instead.
+func detachFromRules(db *gorm.DB, deviceID uuid.UUID, now time.Time) error {
+	id := deviceID.String()
+	return db.Model(&models.Notification{}).
+		Where("device_ids @> CAST(? AS jsonb)", `["`+id+`"]`).
+		Updates(map[string]interface{}{
+			"device_ids": gorm.Expr("device_ids - CAST(? AS text)", id),
+			"enabled":    gorm.Expr("enabled AND (jsonb_array_length(device_ids - CAST(? AS text)) > 0 OR label_selector <> '')", id),
+			"updated_at": now,
+		}).Error
+}
@@ -0,0 +1,122 @@
+package repository
+
+import (
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+func newMockDB(t *testing.T) (*gorm.DB, sqlmock.Sqlmock) {
+	sqlDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { sqlDB.Close() })
+
+	db, err := gorm.Open(postgres.New(postgres.Config{Conn: sqlDB}), &gorm.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return db, mock
+}
+
+func TestDeviceRepository_Delete(t *testing.T) {
+	deviceID := uuid.New()
+
+	t.Run("Success - Pending alerts of the device are closed", func(t *testing.T) {
+		db, mock := newMockDB(t)
+		repo := NewDeviceRepository(db)
+
+		mock.ExpectBegin()
+		mock.ExpectExec(`UPDATE "devices" SET "deleted_at"=.* WHERE uuid = .*`).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(`UPDATE "alerts" SET "next_escalation_at"=.*"resolved_at"=.*"status"=.* WHERE device_id = .* AND status IN \(.*,.*\)`).
+			WithArgs(nil, sqlmock.AnyArg(), "resolved", sqlmock.AnyArg(), deviceID, "firing", "acknowledged").
+			WillReturnResult(sqlmock.NewResult(0, 2))
+		mock.ExpectExec(`DELETE FROM "held_notifications" WHERE payload->>'device_id' = .*`).
+			WithArgs(deviceID.String()).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(`DELETE FROM "delivery_attempts" WHERE delivery_id IN \(SELECT "id" FROM "notification_deliveries" WHERE device_id = .* AND status = .*\)`).
+			WithArgs(deviceID, "pending").
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(`DELETE FROM "notification_deliveries" WHERE device_id = .* AND status = .*`).
+			WithArgs(deviceID, "pending").
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(`UPDATE "notifications" SET .*`).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectCommit()
+
+		err := repo.Delete(deviceID, false)
+
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Error - Device not found", func(t *testing.T) {
+		db, mock := newMockDB(t)
+		repo := NewDeviceRepository(db)
+
+		mock.ExpectBegin()
+		mock.ExpectExec(`UPDATE "devices" SET "deleted_at"=.*`).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectRollback()
+
+		err := repo.Delete(deviceID, false)
+
+		assert.Equal(t, gorm.ErrRecordNotFound, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestDeviceRepository_SetOrganization(t *testing.T) {
+	deviceID := uuid.New()
+	orgID := uuid.New()
+
+	t.Run("Success - Pending alerts of the moved devices are closed", func(t *testing.T) {
+		db, mock := newMockDB(t)
+		repo := NewDeviceRepository(db)
+
+		mock.ExpectBegin()
+		mock.ExpectExec(`UPDATE "devices" SET "group_id"=.*"organization_id"=.* WHERE uuid IN .*`).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(`UPDATE "alerts" SET "next_escalation_at"=.*"resolved_at"=.*"status"=.* WHERE device_id = .* AND status IN \(.*,.*\)`).
+			WithArgs(nil, sqlmock.AnyArg(), "resolved", sqlmock.AnyArg(), deviceID, "firing", "acknowledged").
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(`DELETE FROM "held_notifications" WHERE payload->>'device_id' = .*`).
+			WithArgs(deviceID.String()).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(`DELETE FROM "delivery_attempts" WHERE delivery_id IN \(SELECT "id" FROM "notification_deliveries" WHERE device_id = .* AND status = .*\)`).
+			WithArgs(deviceID, "pending").
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(`DELETE FROM "notification_deliveries" WHERE device_id = .* AND status = .*`).
+			WithArgs(deviceID, "pending").
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		err := repo.SetOrganization([]uuid.UUID{deviceID}, orgID)
+
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Error - Alerts cannot be closed", func(t *testing.T) {
+		db, mock := newMockDB(t)
+		repo := NewDeviceRepository(db)
+
+		mock.ExpectBegin()
+		mock.ExpectExec(`UPDATE "devices" SET .*`).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(`UPDATE "alerts" SET .*`).
+			WillReturnError(gorm.ErrInvalidDB)
+		mock.ExpectRollback()
+
+		err := repo.SetOrganization([]uuid.UUID{deviceID}, orgID)
+
+		assert.Equal(t, gorm.ErrInvalidDB, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
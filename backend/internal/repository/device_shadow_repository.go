@@ -0,0 +1,95 @@
+package repository
+
+import (
+	"errors"
+
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type DeviceShadowRepository interface {
+	FindByDeviceID(deviceID uuid.UUID) (*models.DeviceShadow, error)
+	FindByDeviceIDs(deviceIDs []uuid.UUID) ([]models.DeviceShadow, error)
+	SaveDesired(shadow *models.DeviceShadow, previousVersion int64) error
+	SaveReported(shadow *models.DeviceShadow, previousVersion int64) error
+}
+
+type deviceShadowRepository struct {
+	db *gorm.DB
+}
+
+func NewDeviceShadowRepository(db *gorm.DB) DeviceShadowRepository {
+	return &deviceShadowRepository{db: db}
+}
+
+func (r *deviceShadowRepository) FindByDeviceID(deviceID uuid.UUID) (*models.DeviceShadow, error) {
+	var shadow models.DeviceShadow
+	err := r.db.Where("device_id = ?", deviceID).First(&shadow).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, gorm.ErrRecordNotFound
+		}
+		return nil, err
+	}
+	return &shadow, nil
+}
+
+// FindByDeviceIDs returns the shadows of the given devices. Devices without
+// a shadow are left out.
+func (r *deviceShadowRepository) FindByDeviceIDs(deviceIDs []uuid.UUID) ([]models.DeviceShadow, error) {
+	var shadows []models.DeviceShadow
+	if len(deviceIDs) == 0 {
+		return shadows, nil
+	}
+	if err := r.db.Where("device_id IN ?", deviceIDs).Find(&shadows).Error; err != nil {
+		return nil, err
+	}
+	return shadows, nil
+}
+
+// SaveDesired stores the desired section of shadow if its version is still
+// previousVersion, creating the shadow when previousVersion is 0. It returns
+// gorm.ErrRecordNotFound when the section was changed in the meantime.
+func (r *deviceShadowRepository) SaveDesired(shadow *models.DeviceShadow, previousVersion int64) error {
+	return r.save(shadow, "desired_version", previousVersion, map[string]interface{}{
+		"desired":            shadow.Desired,
+		"desired_version":    shadow.DesiredVersion,
+		"desired_updated_at": shadow.DesiredUpdatedAt,
+		"updated_at":         shadow.UpdatedAt,
+	})
+}
+
+// SaveReported is SaveDesired for the reported section.
+func (r *deviceShadowRepository) SaveReported(shadow *models.DeviceShadow, previousVersion int64) error {
+	return r.save(shadow, "reported_version", previousVersion, map[string]interface{}{
+		"reported":            shadow.Reported,
+		"reported_version":    shadow.ReportedVersion,
+		"reported_updated_at": shadow.ReportedUpdatedAt,
+		"updated_at":          shadow.UpdatedAt,
+	})
+}
+
+func (r *deviceShadowRepository) save(shadow *models.DeviceShadow, versionColumn string, previousVersion int64, columns map[string]interface{}) error {
+	if previousVersion == 0 {
+		result := r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(shadow)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 1 {
+			return nil
+		}
+	}
+
+	result := r.db.Model(&models.DeviceShadow{}).
+		Where("device_id = ? AND "+versionColumn+" = ?", shadow.DeviceID, previousVersion).
+		Updates(columns)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
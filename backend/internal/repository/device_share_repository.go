@@ -0,0 +1,114 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type DeviceShareRepository interface {
+	Create(share *models.DeviceShare) error
+	Update(share *models.DeviceShare) error
+	FindByID(id uuid.UUID) (*models.DeviceShare, error)
+	FindByDeviceID(deviceID uuid.UUID) ([]models.DeviceShare, error)
+	FindByDeviceAndGrantee(deviceID, granteeID uuid.UUID) (*models.DeviceShare, error)
+	FindActive(deviceID, granteeID uuid.UUID, now time.Time) (*models.DeviceShare, error)
+	Delete(id uuid.UUID) error
+}
+
+// activeShares selects the devices shared with a user through a grant that
+// has not expired.
+const activeShares = "SELECT device_id FROM device_shares WHERE grantee_id = ? AND (expires_at IS NULL OR expires_at > NOW())"
+
+type deviceShareRepository struct {
+	db *gorm.DB
+}
+
+func NewDeviceShareRepository(db *gorm.DB) DeviceShareRepository {
+	return &deviceShareRepository{db: db}
+}
+
+func (r *deviceShareRepository) Create(share *models.DeviceShare) error {
+	return r.db.Omit("Grantee").Create(share).Error
+}
+
+func (r *deviceShareRepository) Update(share *models.DeviceShare) error {
+	result := r.db.Model(&models.DeviceShare{}).Where("id = ?", share.ID).Updates(map[string]interface{}{
+		"permission": share.Permission,
+		"expires_at": share.ExpiresAt,
+		"granted_by": share.GrantedBy,
+		"updated_at": share.UpdatedAt,
+	})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *deviceShareRepository) FindByID(id uuid.UUID) (*models.DeviceShare, error) {
+	var share models.DeviceShare
+	err := r.db.Preload("Grantee").Where("id = ?", id).First(&share).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, gorm.ErrRecordNotFound
+		}
+		return nil, err
+	}
+	return &share, nil
+}
+
+func (r *deviceShareRepository) FindByDeviceID(deviceID uuid.UUID) ([]models.DeviceShare, error) {
+	var shares []models.DeviceShare
+	err := r.db.Preload("Grantee").
+		Where("device_id = ?", deviceID).
+		Order("created_at ASC").
+		Find(&shares).Error
+	if err != nil {
+		return nil, err
+	}
+	return shares, nil
+}
+
+func (r *deviceShareRepository) FindByDeviceAndGrantee(deviceID, granteeID uuid.UUID) (*models.DeviceShare, error) {
+	var share models.DeviceShare
+	err := r.db.Where("device_id = ? AND grantee_id = ?", deviceID, granteeID).First(&share).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, gorm.ErrRecordNotFound
+		}
+		return nil, err
+	}
+	return &share, nil
+}
+
+// FindActive returns the grant of granteeID on the device if it has not
+// expired at now.
+func (r *deviceShareRepository) FindActive(deviceID, granteeID uuid.UUID, now time.Time) (*models.DeviceShare, error) {
+	var share models.DeviceShare
+	err := r.db.Where("device_id = ? AND grantee_id = ? AND (expires_at IS NULL OR expires_at > ?)", deviceID, granteeID, now).
+		First(&share).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, gorm.ErrRecordNotFound
+		}
+		return nil, err
+	}
+	return &share, nil
+}
+
+func (r *deviceShareRepository) Delete(id uuid.UUID) error {
+	result := r.db.Where("id = ?", id).Delete(&models.DeviceShare{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
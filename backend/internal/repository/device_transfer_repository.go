@@ -0,0 +1,135 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type DeviceTransferRepository interface {
+	Create(transfer *models.DeviceTransfer) error
+	FindByID(id uuid.UUID) (*models.DeviceTransfer, error)
+	FindPendingByDeviceID(deviceID uuid.UUID) (*models.DeviceTransfer, error)
+	FindByUser(userID uuid.UUID, email string) ([]models.DeviceTransfer, error)
+	UpdateStatus(id uuid.UUID, fromStatus, toStatus string) error
+	Complete(transfer *models.DeviceTransfer, toUserID uuid.UUID, completedAt time.Time) error
+}
+
+type deviceTransferRepository struct {
+	db *gorm.DB
+}
+
+func NewDeviceTransferRepository(db *gorm.DB) DeviceTransferRepository {
+	return &deviceTransferRepository{db: db}
+}
+
+func (r *deviceTransferRepository) Create(transfer *models.DeviceTransfer) error {
+	return r.db.Create(transfer).Error
+}
+
+func (r *deviceTransferRepository) FindByID(id uuid.UUID) (*models.DeviceTransfer, error) {
+	var transfer models.DeviceTransfer
+	err := r.db.Where("id = ?", id).First(&transfer).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, gorm.ErrRecordNotFound
+		}
+		return nil, err
+	}
+	return &transfer, nil
+}
+
+func (r *deviceTransferRepository) FindPendingByDeviceID(deviceID uuid.UUID) (*models.DeviceTransfer, error) {
+	var transfer models.DeviceTransfer
+	err := r.db.Where("device_id = ? AND status = ?", deviceID, models.TransferPending).First(&transfer).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, gorm.ErrRecordNotFound
+		}
+		return nil, err
+	}
+	return &transfer, nil
+}
+
+// FindByUser returns the transfers sent by userID and those addressed to
+// email, newest first.
+func (r *deviceTransferRepository) FindByUser(userID uuid.UUID, email string) ([]models.DeviceTransfer, error) {
+	var transfers []models.DeviceTransfer
+	err := r.db.Where("from_user_id = ? OR LOWER(to_email) = LOWER(?)", userID, email).
+		Order("created_at DESC").
+		Find(&transfers).Error
+	if err != nil {
+		return nil, err
+	}
+	return transfers, nil
+}
+
+// UpdateStatus moves the transfer from fromStatus to toStatus. It returns
+// gorm.ErrRecordNotFound when the transfer is no longer in fromStatus.
+func (r *deviceTransferRepository) UpdateStatus(id uuid.UUID, fromStatus, toStatus string) error {
+	result := r.db.Model(&models.DeviceTransfer{}).
+		Where("id = ? AND status = ?", id, fromStatus).
+		Updates(map[string]interface{}{
+			"status":     toStatus,
+			"updated_at": time.Now(),
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// Complete accepts a pending transfer and hands the device to toUserID in a
+// single transaction. Heartbeats reference the device and move with it. What
+// belonged to the previous owner is detached: the device leaves its group,
+// its share grants are revoked, its pending alerts are closed and it is
+// removed from the previous owner's rules. It returns gorm.ErrRecordNotFound
+// when the transfer is no longer pending or the sender no longer owns the
+// device personally.
+func (r *deviceTransferRepository) Complete(transfer *models.DeviceTransfer, toUserID uuid.UUID, completedAt time.Time) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.DeviceTransfer{}).
+			Where("id = ? AND status = ?", transfer.ID, models.TransferPending).
+			Updates(map[string]interface{}{
+				"status":       models.TransferAccepted,
+				"to_user_id":   toUserID,
+				"completed_at": completedAt,
+				"updated_at":   completedAt,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+
+		result = tx.Model(&models.Device{}).
+			Where("uuid = ? AND user_id = ? AND organization_id IS NULL", transfer.DeviceID, transfer.FromUserID).
+			Updates(map[string]interface{}{
+				"user_id":    toUserID,
+				"group_id":   nil,
+				"updated_at": completedAt,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+
+		if err := tx.Where("device_id = ?", transfer.DeviceID).Delete(&models.DeviceShare{}).Error; err != nil {
+			return err
+		}
+		if err := closePendingAlerts(tx, transfer.DeviceID, completedAt); err != nil {
+			return err
+		}
+
+		return detachFromRules(tx.Where("user_id = ? AND organization_id IS NULL", transfer.FromUserID), transfer.DeviceID, completedAt)
+	})
+}
@@ -0,0 +1,72 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/models"
+)
+
+func TestDeviceTransferRepository_Complete(t *testing.T) {
+	transfer := &models.DeviceTransfer{
+		ID:         uuid.New(),
+		DeviceID:   uuid.New(),
+		FromUserID: uuid.New(),
+	}
+	toUserID := uuid.New()
+	completedAt := time.Now()
+
+	t.Run("Success - Pending alerts of the device are closed", func(t *testing.T) {
+		db, mock := newMockDB(t)
+		repo := NewDeviceTransferRepository(db)
+
+		mock.ExpectBegin()
+		mock.ExpectExec(`UPDATE "device_transfers" SET .* WHERE id = .* AND status = .*`).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(`UPDATE "devices" SET .* WHERE \(uuid = .* AND user_id = .* AND organization_id IS NULL\)`).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(`DELETE FROM "device_shares" WHERE device_id = .*`).
+			WithArgs(transfer.DeviceID).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(`UPDATE "alerts" SET "next_escalation_at"=.*"resolved_at"=.*"status"=.* WHERE device_id = .* AND status IN \(.*,.*\)`).
+			WithArgs(nil, completedAt, "resolved", completedAt, transfer.DeviceID, "firing", "acknowledged").
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(`DELETE FROM "held_notifications" WHERE payload->>'device_id' = .*`).
+			WithArgs(transfer.DeviceID.String()).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(`DELETE FROM "delivery_attempts" WHERE delivery_id IN \(SELECT "id" FROM "notification_deliveries" WHERE device_id = .* AND status = .*\)`).
+			WithArgs(transfer.DeviceID, "pending").
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(`DELETE FROM "notification_deliveries" WHERE device_id = .* AND status = .*`).
+			WithArgs(transfer.DeviceID, "pending").
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(`UPDATE "notifications" SET .*`).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectCommit()
+
+		err := repo.Complete(transfer, toUserID, completedAt)
+
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Error - Transfer no longer pending", func(t *testing.T) {
+		db, mock := newMockDB(t)
+		repo := NewDeviceTransferRepository(db)
+
+		mock.ExpectBegin()
+		mock.ExpectExec(`UPDATE "device_transfers" SET .*`).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectRollback()
+
+		err := repo.Complete(transfer, toUserID, completedAt)
+
+		assert.Equal(t, gorm.ErrRecordNotFound, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
@@ -0,0 +1,80 @@
+package repository
+
+import (
+	"errors"
+
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type EscalationPolicyRepository interface {
+	Create(policy *models.EscalationPolicy) error
+	FindByID(id uuid.UUID) (*models.EscalationPolicy, error)
+	FindByUserID(userID uuid.UUID) ([]models.EscalationPolicy, error)
+	Update(policy *models.EscalationPolicy) error
+	Delete(id uuid.UUID) error
+}
+
+type escalationPolicyRepository struct {
+	db *gorm.DB
+}
+
+func NewEscalationPolicyRepository(db *gorm.DB) EscalationPolicyRepository {
+	return &escalationPolicyRepository{db: db}
+}
+
+func (r *escalationPolicyRepository) Create(policy *models.EscalationPolicy) error {
+	return r.db.Create(policy).Error
+}
+
+func (r *escalationPolicyRepository) FindByID(id uuid.UUID) (*models.EscalationPolicy, error) {
+	var policy models.EscalationPolicy
+	err := r.db.Where("id = ?", id).First(&policy).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, gorm.ErrRecordNotFound
+		}
+		return nil, err
+	}
+	return &policy, nil
+}
+
+// FindByUserID returns the personal policies of the user and the policies of
+// the organizations the user belongs to.
+func (r *escalationPolicyRepository) FindByUserID(userID uuid.UUID) ([]models.EscalationPolicy, error) {
+	var policies []models.EscalationPolicy
+	err := r.db.Scopes(accessibleBy(userID)).
+		Order("created_at").
+		Find(&policies).Error
+	if err != nil {
+		return nil, err
+	}
+	return policies, nil
+}
+
+func (r *escalationPolicyRepository) Update(policy *models.EscalationPolicy) error {
+	return r.db.Save(policy).Error
+}
+
+// Delete removes the policy, detaches it from the rules using it and stops
+// escalating their open alerts.
+func (r *escalationPolicyRepository) Delete(id uuid.UUID) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("id = ?", id).Delete(&models.EscalationPolicy{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		if err := tx.Model(&models.Notification{}).
+			Where("escalation_policy_id = ?", id).
+			Update("escalation_policy_id", nil).Error; err != nil {
+			return err
+		}
+		return tx.Model(&models.Alert{}).
+			Where("escalation_policy_id = ? AND next_escalation_at IS NOT NULL", id).
+			Update("next_escalation_at", nil).Error
+	})
+}
@@ -0,0 +1,75 @@
+package repository
+
+import (
+	"errors"
+
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type FirmwareRepository interface {
+	Create(artifact *models.FirmwareArtifact) error
+	FindByID(id uuid.UUID) (*models.FirmwareArtifact, error)
+	FindByUserID(userID uuid.UUID) ([]models.FirmwareArtifact, error)
+	FindByVersion(userID uuid.UUID, organizationID *uuid.UUID, version string) (*models.FirmwareArtifact, error)
+	Delete(id uuid.UUID) error
+}
+
+type firmwareRepository struct {
+	db *gorm.DB
+}
+
+func NewFirmwareRepository(db *gorm.DB) FirmwareRepository {
+	return &firmwareRepository{db: db}
+}
+
+func (r *firmwareRepository) Create(artifact *models.FirmwareArtifact) error {
+	return r.db.Create(artifact).Error
+}
+
+func (r *firmwareRepository) FindByID(id uuid.UUID) (*models.FirmwareArtifact, error) {
+	var artifact models.FirmwareArtifact
+	err := r.db.Where("id = ?", id).First(&artifact).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, gorm.ErrRecordNotFound
+		}
+		return nil, err
+	}
+	return &artifact, nil
+}
+
+func (r *firmwareRepository) FindByUserID(userID uuid.UUID) ([]models.FirmwareArtifact, error) {
+	var artifacts []models.FirmwareArtifact
+	err := r.db.Scopes(accessibleBy(userID)).Order("created_at DESC").Find(&artifacts).Error
+	if err != nil {
+		return nil, err
+	}
+	return artifacts, nil
+}
+
+// FindByVersion looks up an artifact by version within one ownership scope:
+// the organization when organizationID is set, otherwise the personal
+// artifacts of userID.
+func (r *firmwareRepository) FindByVersion(userID uuid.UUID, organizationID *uuid.UUID, version string) (*models.FirmwareArtifact, error) {
+	query := r.db.Where("version = ?", version)
+	if organizationID != nil {
+		query = query.Where("organization_id = ?", *organizationID)
+	} else {
+		query = query.Where("organization_id IS NULL AND user_id = ?", userID)
+	}
+
+	var artifact models.FirmwareArtifact
+	if err := query.First(&artifact).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, gorm.ErrRecordNotFound
+		}
+		return nil, err
+	}
+	return &artifact, nil
+}
+
+func (r *firmwareRepository) Delete(id uuid.UUID) error {
+	return r.db.Where("id = ?", id).Delete(&models.FirmwareArtifact{}).Error
+}
@@ -0,0 +1,154 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type FirmwareRolloutRepository interface {
+	Create(rollout *models.FirmwareRollout, devices []*models.FirmwareRolloutDevice) error
+	FindByID(id uuid.UUID) (*models.FirmwareRollout, error)
+	FindByUserID(userID uuid.UUID) ([]models.FirmwareRollout, error)
+	FindActive() ([]models.FirmwareRollout, error)
+	CountActiveByArtifact(artifactID uuid.UUID) (int64, error)
+	Transition(id uuid.UUID, from []string, updates map[string]interface{}) error
+	FindDevices(rolloutID uuid.UUID) ([]models.FirmwareRolloutDevice, error)
+	FindDeviceByDownloadToken(tokenHash string) (*models.FirmwareRolloutDevice, error)
+	TransitionDevice(rolloutID, deviceID uuid.UUID, from []string, updates map[string]interface{}) error
+	CompleteDevice(deviceID uuid.UUID, version string, now time.Time) (int64, error)
+}
+
+type firmwareRolloutRepository struct {
+	db *gorm.DB
+}
+
+func NewFirmwareRolloutRepository(db *gorm.DB) FirmwareRolloutRepository {
+	return &firmwareRolloutRepository{db: db}
+}
+
+// Create stores the rollout with every targeted device in a single
+// transaction.
+func (r *firmwareRolloutRepository) Create(rollout *models.FirmwareRollout, devices []*models.FirmwareRolloutDevice) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(rollout).Error; err != nil {
+			return err
+		}
+		if len(devices) == 0 {
+			return nil
+		}
+		return tx.CreateInBatches(devices, 500).Error
+	})
+}
+
+func (r *firmwareRolloutRepository) FindByID(id uuid.UUID) (*models.FirmwareRollout, error) {
+	var rollout models.FirmwareRollout
+	err := r.db.Where("id = ?", id).First(&rollout).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, gorm.ErrRecordNotFound
+		}
+		return nil, err
+	}
+	return &rollout, nil
+}
+
+func (r *firmwareRolloutRepository) FindByUserID(userID uuid.UUID) ([]models.FirmwareRollout, error) {
+	var rollouts []models.FirmwareRollout
+	err := r.db.Scopes(accessibleBy(userID)).Order("created_at DESC").Find(&rollouts).Error
+	if err != nil {
+		return nil, err
+	}
+	return rollouts, nil
+}
+
+// FindActive returns the running and paused rollouts.
+func (r *firmwareRolloutRepository) FindActive() ([]models.FirmwareRollout, error) {
+	var rollouts []models.FirmwareRollout
+	err := r.db.Where("status IN ?", []string{models.RolloutRunning, models.RolloutPaused}).
+		Order("created_at").
+		Find(&rollouts).Error
+	if err != nil {
+		return nil, err
+	}
+	return rollouts, nil
+}
+
+func (r *firmwareRolloutRepository) CountActiveByArtifact(artifactID uuid.UUID) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.FirmwareRollout{}).
+		Where("artifact_id = ? AND status IN ?", artifactID, []string{models.RolloutRunning, models.RolloutPaused}).
+		Count(&count).Error
+	return count, err
+}
+
+// Transition applies updates to the rollout if its status is one of from.
+// It returns gorm.ErrRecordNotFound when the rollout is in another status.
+func (r *firmwareRolloutRepository) Transition(id uuid.UUID, from []string, updates map[string]interface{}) error {
+	result := r.db.Model(&models.FirmwareRollout{}).
+		Where("id = ? AND status IN ?", id, from).
+		Updates(updates)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *firmwareRolloutRepository) FindDevices(rolloutID uuid.UUID) ([]models.FirmwareRolloutDevice, error) {
+	var devices []models.FirmwareRolloutDevice
+	err := r.db.Where("rollout_id = ?", rolloutID).Order("wave, device_id").Find(&devices).Error
+	if err != nil {
+		return nil, err
+	}
+	return devices, nil
+}
+
+// FindDeviceByDownloadToken returns the updating device holding the download
+// token.
+func (r *firmwareRolloutRepository) FindDeviceByDownloadToken(tokenHash string) (*models.FirmwareRolloutDevice, error) {
+	var device models.FirmwareRolloutDevice
+	err := r.db.Where("download_token_hash = ? AND status = ?", tokenHash, models.RolloutDeviceUpdating).
+		First(&device).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, gorm.ErrRecordNotFound
+		}
+		return nil, err
+	}
+	return &device, nil
+}
+
+// TransitionDevice applies updates to a rollout device if its status is one
+// of from. It returns gorm.ErrRecordNotFound when it is in another status.
+func (r *firmwareRolloutRepository) TransitionDevice(rolloutID, deviceID uuid.UUID, from []string, updates map[string]interface{}) error {
+	result := r.db.Model(&models.FirmwareRolloutDevice{}).
+		Where("rollout_id = ? AND device_id = ? AND status IN ?", rolloutID, deviceID, from).
+		Updates(updates)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// CompleteDevice marks the device succeeded in every rollout updating it to
+// version and returns how many there were.
+func (r *firmwareRolloutRepository) CompleteDevice(deviceID uuid.UUID, version string, now time.Time) (int64, error) {
+	result := r.db.Model(&models.FirmwareRolloutDevice{}).
+		Where("device_id = ? AND status = ?", deviceID, models.RolloutDeviceUpdating).
+		Where("rollout_id IN (SELECT id FROM firmware_rollouts WHERE version = ?)", version).
+		Updates(map[string]interface{}{
+			"status":              models.RolloutDeviceSucceeded,
+			"download_token_hash": "",
+			"completed_at":        now,
+		})
+	return result.RowsAffected, result.Error
+}
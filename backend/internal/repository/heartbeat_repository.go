@@ -12,6 +12,23 @@ type HeartbeatRepository interface {
     Create(heartbeat *models.Heartbeat) error
     FindByDeviceID(deviceID uuid.UUID, startTime, endTime time.Time) ([]models.Heartbeat, error)
     FindLatestByDeviceID(deviceID uuid.UUID) (*models.Heartbeat, error)
+    FindConnectivityByDeviceID(deviceID uuid.UUID, startTime, endTime time.Time) ([]models.Heartbeat, error)
+    StreamByDeviceIDs(deviceIDs []uuid.UUID, startTime, endTime time.Time, fn func(*models.Heartbeat) error) error
+    FindLatestByDeviceIDs(deviceIDs []uuid.UUID) ([]models.Heartbeat, error)
+    SummarizeByDeviceIDs(deviceIDs []uuid.UUID, startTime, endTime time.Time) ([]HeartbeatSummary, error)
+}
+
+// HeartbeatSummary aggregates the heartbeats of a device over a period.
+// Reboots counts the distinct boot times within the period, to the minute.
+type HeartbeatSummary struct {
+    DeviceID       uuid.UUID
+    Samples        int64
+    AvgCPU         float64
+    AvgRAM         float64
+    MaxTemperature float64
+    MinDiskFree    float64
+    Reboots        int64
+    LastBootTime   *time.Time
 }
 
 type heartbeatRepository struct {
@@ -43,4 +60,80 @@ func (r *heartbeatRepository) FindLatestByDeviceID(deviceID uuid.UUID) (*models.
         return nil, err
     }
     return &heartbeat, nil
-}
\ No newline at end of file
+}
+
+// FindLatestByDeviceIDs returns the most recent heartbeat of each device that
+// has reported at least once.
+func (r *heartbeatRepository) FindLatestByDeviceIDs(deviceIDs []uuid.UUID) ([]models.Heartbeat, error) {
+    var heartbeats []models.Heartbeat
+    if len(deviceIDs) == 0 {
+        return heartbeats, nil
+    }
+    err := r.db.Raw(`SELECT DISTINCT ON (device_id) * FROM heartbeats
+        WHERE device_id IN ?
+        ORDER BY device_id, created_at DESC`, deviceIDs).
+        Scan(&heartbeats).Error
+    return heartbeats, err
+}
+
+// SummarizeByDeviceIDs returns a summary of each device that reported
+// between startTime and endTime.
+func (r *heartbeatRepository) SummarizeByDeviceIDs(deviceIDs []uuid.UUID, startTime, endTime time.Time) ([]HeartbeatSummary, error) {
+    var summaries []HeartbeatSummary
+    if len(deviceIDs) == 0 {
+        return summaries, nil
+    }
+    err := r.db.Raw(`SELECT device_id,
+            COUNT(*) AS samples,
+            AVG(cpu) AS avg_cpu,
+            AVG(ram) AS avg_ram,
+            MAX(temperature) AS max_temperature,
+            MIN(disk_free) AS min_disk_free,
+            COUNT(DISTINCT date_trunc('minute', boot_time)) FILTER (WHERE boot_time >= ?) AS reboots,
+            MAX(boot_time) FILTER (WHERE boot_time >= ?) AS last_boot_time
+        FROM heartbeats
+        WHERE device_id IN ? AND created_at BETWEEN ? AND ?
+        GROUP BY device_id`, startTime, startTime, deviceIDs, startTime, endTime).
+        Scan(&summaries).Error
+    return summaries, err
+}
+
+// FindConnectivityByDeviceID loads only the timestamp and connectivity flag of
+// each heartbeat, oldest first, which is all availability reports need.
+func (r *heartbeatRepository) FindConnectivityByDeviceID(deviceID uuid.UUID, startTime, endTime time.Time) ([]models.Heartbeat, error) {
+    var heartbeats []models.Heartbeat
+    err := r.db.Select("device_id", "connectivity", "created_at").
+        Where("device_id = ? AND created_at BETWEEN ? AND ?", deviceID, startTime, endTime).
+        Order("created_at ASC").
+        Find(&heartbeats).Error
+    return heartbeats, err
+}
+
+// StreamByDeviceIDs iterates over the matching heartbeats with a database
+// cursor, oldest first, so large exports never sit in memory. Iteration stops
+// at the first error returned by fn.
+func (r *heartbeatRepository) StreamByDeviceIDs(deviceIDs []uuid.UUID, startTime, endTime time.Time, fn func(*models.Heartbeat) error) error {
+    if len(deviceIDs) == 0 {
+        return nil
+    }
+
+    rows, err := r.db.Model(&models.Heartbeat{}).
+        Where("device_id IN ? AND created_at BETWEEN ? AND ?", deviceIDs, startTime, endTime).
+        Order("created_at ASC").
+        Rows()
+    if err != nil {
+        return err
+    }
+    defer rows.Close()
+
+    for rows.Next() {
+        var heartbeat models.Heartbeat
+        if err := r.db.ScanRows(rows, &heartbeat); err != nil {
+            return err
+        }
+        if err := fn(&heartbeat); err != nil {
+            return err
+        }
+    }
+    return rows.Err()
+}
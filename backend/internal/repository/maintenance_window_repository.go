@@ -0,0 +1,128 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type MaintenanceWindowRepository interface {
+	Create(window *models.MaintenanceWindow) error
+	FindByID(id uuid.UUID) (*models.MaintenanceWindow, error)
+	FindUnfinishedByUserID(userID uuid.UUID, now time.Time) ([]models.MaintenanceWindow, error)
+	FindStartedByOwner(userID uuid.UUID, organizationID *uuid.UUID, now time.Time) ([]models.MaintenanceWindow, error)
+	FindOverlappingByOwner(userID uuid.UUID, organizationID *uuid.UUID, from, to time.Time) ([]models.MaintenanceWindow, error)
+	Delete(id uuid.UUID) error
+	CreateSuppressedAlert(alert *models.SuppressedAlert) error
+	FindSuppressedAlerts(windowID uuid.UUID, limit int) ([]models.SuppressedAlert, error)
+}
+
+type maintenanceWindowRepository struct {
+	db *gorm.DB
+}
+
+func NewMaintenanceWindowRepository(db *gorm.DB) MaintenanceWindowRepository {
+	return &maintenanceWindowRepository{db: db}
+}
+
+func (r *maintenanceWindowRepository) Create(window *models.MaintenanceWindow) error {
+	return r.db.Create(window).Error
+}
+
+func (r *maintenanceWindowRepository) FindByID(id uuid.UUID) (*models.MaintenanceWindow, error) {
+	var window models.MaintenanceWindow
+	err := r.db.Where("id = ?", id).First(&window).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, gorm.ErrRecordNotFound
+		}
+		return nil, err
+	}
+	return &window, nil
+}
+
+// FindUnfinishedByUserID returns the windows the user can see that have not
+// ended by now, whether they already started or not.
+func (r *maintenanceWindowRepository) FindUnfinishedByUserID(userID uuid.UUID, now time.Time) ([]models.MaintenanceWindow, error) {
+	var windows []models.MaintenanceWindow
+	err := r.db.Scopes(accessibleBy(userID)).
+		Where("ends_at IS NULL OR ends_at > ?", now).
+		Order("starts_at").
+		Find(&windows).Error
+	if err != nil {
+		return nil, err
+	}
+	return windows, nil
+}
+
+// FindStartedByOwner returns the windows of an organization, or the personal
+// windows of the user when organizationID is nil, that started and have not
+// ended by now. Recurring windows still have to be checked against their
+// schedule.
+func (r *maintenanceWindowRepository) FindStartedByOwner(userID uuid.UUID, organizationID *uuid.UUID, now time.Time) ([]models.MaintenanceWindow, error) {
+	query := r.db.Where("starts_at <= ? AND (ends_at IS NULL OR ends_at > ?)", now, now)
+	if organizationID != nil {
+		query = query.Where("organization_id = ?", *organizationID)
+	} else {
+		query = query.Where("user_id = ? AND organization_id IS NULL", userID)
+	}
+
+	var windows []models.MaintenanceWindow
+	if err := query.Find(&windows).Error; err != nil {
+		return nil, err
+	}
+	return windows, nil
+}
+
+// FindOverlappingByOwner returns the windows of an organization, or the
+// personal windows of the user when organizationID is nil, that started by
+// to and had not ended by from.
+func (r *maintenanceWindowRepository) FindOverlappingByOwner(userID uuid.UUID, organizationID *uuid.UUID, from, to time.Time) ([]models.MaintenanceWindow, error) {
+	query := r.db.Where("starts_at <= ? AND (ends_at IS NULL OR ends_at > ?)", to, from)
+	if organizationID != nil {
+		query = query.Where("organization_id = ?", *organizationID)
+	} else {
+		query = query.Where("user_id = ? AND organization_id IS NULL", userID)
+	}
+
+	var windows []models.MaintenanceWindow
+	if err := query.Find(&windows).Error; err != nil {
+		return nil, err
+	}
+	return windows, nil
+}
+
+// Delete removes the window together with the alerts it suppressed.
+func (r *maintenanceWindowRepository) Delete(id uuid.UUID) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("id = ?", id).Delete(&models.MaintenanceWindow{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return tx.Where("window_id = ?", id).Delete(&models.SuppressedAlert{}).Error
+	})
+}
+
+func (r *maintenanceWindowRepository) CreateSuppressedAlert(alert *models.SuppressedAlert) error {
+	return r.db.Create(alert).Error
+}
+
+// FindSuppressedAlerts returns the latest alerts suppressed by the window,
+// newest first.
+func (r *maintenanceWindowRepository) FindSuppressedAlerts(windowID uuid.UUID, limit int) ([]models.SuppressedAlert, error) {
+	var alerts []models.SuppressedAlert
+	err := r.db.Where("window_id = ?", windowID).
+		Order("created_at DESC").
+		Limit(limit).
+		Find(&alerts).Error
+	if err != nil {
+		return nil, err
+	}
+	return alerts, nil
+}
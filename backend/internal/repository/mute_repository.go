@@ -0,0 +1,110 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type MuteRepository interface {
+	Create(mute *models.Mute) error
+	FindByID(id uuid.UUID) (*models.Mute, error)
+	FindByUserID(userID uuid.UUID, activeAt *time.Time) ([]models.Mute, error)
+	FindActiveForDevice(userID uuid.UUID, organizationID *uuid.UUID, deviceID uuid.UUID, now time.Time) ([]models.Mute, error)
+	FindOverlappingForDevice(userID uuid.UUID, organizationID *uuid.UUID, deviceID uuid.UUID, from, to time.Time) ([]models.Mute, error)
+	Cancel(id uuid.UUID, at time.Time) error
+}
+
+type muteRepository struct {
+	db *gorm.DB
+}
+
+func NewMuteRepository(db *gorm.DB) MuteRepository {
+	return &muteRepository{db: db}
+}
+
+func (r *muteRepository) Create(mute *models.Mute) error {
+	return r.db.Create(mute).Error
+}
+
+func (r *muteRepository) FindByID(id uuid.UUID) (*models.Mute, error) {
+	var mute models.Mute
+	err := r.db.Where("id = ?", id).First(&mute).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, gorm.ErrRecordNotFound
+		}
+		return nil, err
+	}
+	return &mute, nil
+}
+
+// FindByUserID returns the mutes the user can see, newest first. With
+// activeAt only the mutes in effect at that time are returned.
+func (r *muteRepository) FindByUserID(userID uuid.UUID, activeAt *time.Time) ([]models.Mute, error) {
+	query := r.db.Scopes(accessibleBy(userID))
+	if activeAt != nil {
+		query = query.Where("cancelled_at IS NULL AND expires_at > ?", *activeAt)
+	}
+
+	var mutes []models.Mute
+	if err := query.Order("created_at DESC").Find(&mutes).Error; err != nil {
+		return nil, err
+	}
+	return mutes, nil
+}
+
+// FindActiveForDevice returns the mutes of an organization, or the personal
+// mutes of the user when organizationID is nil, that are in effect at now
+// and either target the device or are not scoped to a device.
+func (r *muteRepository) FindActiveForDevice(userID uuid.UUID, organizationID *uuid.UUID, deviceID uuid.UUID, now time.Time) ([]models.Mute, error) {
+	query := r.db.Where("cancelled_at IS NULL AND expires_at > ?", now).
+		Where("(device_id = ? OR device_id IS NULL)", deviceID)
+	if organizationID != nil {
+		query = query.Where("organization_id = ?", *organizationID)
+	} else {
+		query = query.Where("user_id = ? AND organization_id IS NULL", userID)
+	}
+
+	var mutes []models.Mute
+	if err := query.Find(&mutes).Error; err != nil {
+		return nil, err
+	}
+	return mutes, nil
+}
+
+// FindOverlappingForDevice is FindActiveForDevice for a period: the mutes
+// created by to that had neither expired nor been cancelled by from.
+func (r *muteRepository) FindOverlappingForDevice(userID uuid.UUID, organizationID *uuid.UUID, deviceID uuid.UUID, from, to time.Time) ([]models.Mute, error) {
+	query := r.db.Where("created_at <= ? AND expires_at > ? AND (cancelled_at IS NULL OR cancelled_at > ?)", to, from, from).
+		Where("(device_id = ? OR device_id IS NULL)", deviceID)
+	if organizationID != nil {
+		query = query.Where("organization_id = ?", *organizationID)
+	} else {
+		query = query.Where("user_id = ? AND organization_id IS NULL", userID)
+	}
+
+	var mutes []models.Mute
+	if err := query.Find(&mutes).Error; err != nil {
+		return nil, err
+	}
+	return mutes, nil
+}
+
+// Cancel ends the mute at the given time. It returns gorm.ErrRecordNotFound
+// when the mute is not in effect anymore.
+func (r *muteRepository) Cancel(id uuid.UUID, at time.Time) error {
+	result := r.db.Model(&models.Mute{}).
+		Where("id = ? AND cancelled_at IS NULL AND expires_at > ?", id, at).
+		Update("cancelled_at", at)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
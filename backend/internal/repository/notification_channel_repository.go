@@ -0,0 +1,206 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type NotificationChannelRepository interface {
+	Create(channel *models.NotificationChannel) error
+	FindByID(id uuid.UUID) (*models.NotificationChannel, error)
+	FindByIDs(ids []uuid.UUID) ([]models.NotificationChannel, error)
+	FindByUserID(userID uuid.UUID) ([]models.NotificationChannel, error)
+	Update(channel *models.NotificationChannel) error
+	Delete(id uuid.UUID) error
+	CreateDeliveries(deliveries []models.NotificationDelivery) error
+	FindDueDeliveries(now time.Time, limit int) ([]models.NotificationDelivery, error)
+	FindDeliveries(channelID uuid.UUID, limit int) ([]models.NotificationDelivery, error)
+	FindDeliveryByID(id uuid.UUID) (*models.NotificationDelivery, error)
+	UpdateDelivery(delivery *models.NotificationDelivery) error
+	RecordDeliveryResult(channelID uuid.UUID, success bool, failureLimit int, disabledReason string, now time.Time) (disabled bool, err error)
+	CreateAttempt(attempt *models.DeliveryAttempt) error
+	FindAttempts(deliveryIDs []uuid.UUID) ([]models.DeliveryAttempt, error)
+}
+
+type notificationChannelRepository struct {
+	db *gorm.DB
+}
+
+func NewNotificationChannelRepository(db *gorm.DB) NotificationChannelRepository {
+	return &notificationChannelRepository{db: db}
+}
+
+func (r *notificationChannelRepository) Create(channel *models.NotificationChannel) error {
+	return r.db.Create(channel).Error
+}
+
+func (r *notificationChannelRepository) FindByID(id uuid.UUID) (*models.NotificationChannel, error) {
+	var channel models.NotificationChannel
+	err := r.db.Where("id = ?", id).First(&channel).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, gorm.ErrRecordNotFound
+		}
+		return nil, err
+	}
+	return &channel, nil
+}
+
+func (r *notificationChannelRepository) FindByIDs(ids []uuid.UUID) ([]models.NotificationChannel, error) {
+	var channels []models.NotificationChannel
+	if len(ids) == 0 {
+		return channels, nil
+	}
+	if err := r.db.Where("id IN ?", ids).Find(&channels).Error; err != nil {
+		return nil, err
+	}
+	return channels, nil
+}
+
+// FindByUserID returns the personal channels of the user and the channels of
+// the organizations the user belongs to.
+func (r *notificationChannelRepository) FindByUserID(userID uuid.UUID) ([]models.NotificationChannel, error) {
+	var channels []models.NotificationChannel
+	err := r.db.Scopes(accessibleBy(userID)).
+		Order("created_at").
+		Find(&channels).Error
+	if err != nil {
+		return nil, err
+	}
+	return channels, nil
+}
+
+func (r *notificationChannelRepository) Update(channel *models.NotificationChannel) error {
+	return r.db.Save(channel).Error
+}
+
+// Delete removes the channel together with its deliveries and their
+// attempts.
+func (r *notificationChannelRepository) Delete(id uuid.UUID) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("id = ?", id).Delete(&models.NotificationChannel{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		deliveries := tx.Model(&models.NotificationDelivery{}).Select("id").Where("channel_id = ?", id)
+		if err := tx.Where("delivery_id IN (?)", deliveries).Delete(&models.DeliveryAttempt{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("channel_id = ?", id).Delete(&models.NotificationDelivery{}).Error
+	})
+}
+
+func (r *notificationChannelRepository) CreateDeliveries(deliveries []models.NotificationDelivery) error {
+	if len(deliveries) == 0 {
+		return nil
+	}
+	return r.db.Create(&deliveries).Error
+}
+
+// FindDueDeliveries returns the pending deliveries whose next attempt is
+// due, oldest first.
+func (r *notificationChannelRepository) FindDueDeliveries(now time.Time, limit int) ([]models.NotificationDelivery, error) {
+	var deliveries []models.NotificationDelivery
+	err := r.db.Where("status = ? AND next_attempt_at <= ?", models.DeliveryPending, now).
+		Order("next_attempt_at").
+		Limit(limit).
+		Find(&deliveries).Error
+	if err != nil {
+		return nil, err
+	}
+	return deliveries, nil
+}
+
+// FindDeliveries returns the latest deliveries of the channel, newest first.
+func (r *notificationChannelRepository) FindDeliveries(channelID uuid.UUID, limit int) ([]models.NotificationDelivery, error) {
+	var deliveries []models.NotificationDelivery
+	err := r.db.Where("channel_id = ?", channelID).
+		Order("created_at DESC").
+		Limit(limit).
+		Find(&deliveries).Error
+	if err != nil {
+		return nil, err
+	}
+	return deliveries, nil
+}
+
+func (r *notificationChannelRepository) FindDeliveryByID(id uuid.UUID) (*models.NotificationDelivery, error) {
+	var delivery models.NotificationDelivery
+	err := r.db.Where("id = ?", id).First(&delivery).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, gorm.ErrRecordNotFound
+		}
+		return nil, err
+	}
+	return &delivery, nil
+}
+
+func (r *notificationChannelRepository) UpdateDelivery(delivery *models.NotificationDelivery) error {
+	return r.db.Save(delivery).Error
+}
+
+// RecordDeliveryResult resets the consecutive failures of the channel after
+// a successful attempt, or counts a failed one. The channel is disabled with
+// disabledReason when the count reaches failureLimit; disabled reports
+// whether this call disabled it.
+func (r *notificationChannelRepository) RecordDeliveryResult(channelID uuid.UUID, success bool, failureLimit int, disabledReason string, now time.Time) (bool, error) {
+	if success {
+		err := r.db.Model(&models.NotificationChannel{}).
+			Where("id = ? AND consecutive_failures > 0", channelID).
+			Update("consecutive_failures", 0).Error
+		return false, err
+	}
+
+	disabled := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&models.NotificationChannel{}).
+			Where("id = ?", channelID).
+			Update("consecutive_failures", gorm.Expr("consecutive_failures + 1")).Error
+		if err != nil {
+			return err
+		}
+
+		result := tx.Model(&models.NotificationChannel{}).
+			Where("id = ? AND enabled AND consecutive_failures >= ?", channelID, failureLimit).
+			Updates(map[string]interface{}{
+				"enabled":         false,
+				"disabled_at":     now,
+				"disabled_reason": disabledReason,
+				"updated_at":      now,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		disabled = result.RowsAffected > 0
+		return nil
+	})
+	return disabled, err
+}
+
+func (r *notificationChannelRepository) CreateAttempt(attempt *models.DeliveryAttempt) error {
+	return r.db.Create(attempt).Error
+}
+
+// FindAttempts returns the attempts of the deliveries in the order they were
+// made.
+func (r *notificationChannelRepository) FindAttempts(deliveryIDs []uuid.UUID) ([]models.DeliveryAttempt, error) {
+	var attempts []models.DeliveryAttempt
+	if len(deliveryIDs) == 0 {
+		return attempts, nil
+	}
+	err := r.db.Where("delivery_id IN ?", deliveryIDs).
+		Order("delivery_id, attempt").
+		Find(&attempts).Error
+	if err != nil {
+		return nil, err
+	}
+	return attempts, nil
+}
@@ -0,0 +1,92 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type NotificationPreferenceRepository interface {
+	FindByUserID(userID uuid.UUID) (*models.NotificationPreference, error)
+	Save(preference *models.NotificationPreference) error
+	FindDigestSubscribers() ([]models.NotificationPreference, error)
+	MarkDigestSent(userID uuid.UUID, weekly bool, at time.Time) error
+	CreateHeld(held *models.HeldNotification) error
+	FindDueHeld(now time.Time, limit int) ([]models.HeldNotification, error)
+	DeleteHeld(ids []uuid.UUID) error
+}
+
+type notificationPreferenceRepository struct {
+	db *gorm.DB
+}
+
+func NewNotificationPreferenceRepository(db *gorm.DB) NotificationPreferenceRepository {
+	return &notificationPreferenceRepository{db: db}
+}
+
+func (r *notificationPreferenceRepository) FindByUserID(userID uuid.UUID) (*models.NotificationPreference, error) {
+	var preference models.NotificationPreference
+	err := r.db.Where("user_id = ?", userID).First(&preference).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, gorm.ErrRecordNotFound
+		}
+		return nil, err
+	}
+	return &preference, nil
+}
+
+// Save creates or replaces the preferences of the user.
+func (r *notificationPreferenceRepository) Save(preference *models.NotificationPreference) error {
+	return r.db.Save(preference).Error
+}
+
+// FindDigestSubscribers returns the preferences of the users who get a
+// daily or weekly digest by email.
+func (r *notificationPreferenceRepository) FindDigestSubscribers() ([]models.NotificationPreference, error) {
+	var preferences []models.NotificationPreference
+	err := r.db.Where("digest_channel_id IS NOT NULL AND (daily_digest OR weekly_digest)").
+		Find(&preferences).Error
+	if err != nil {
+		return nil, err
+	}
+	return preferences, nil
+}
+
+func (r *notificationPreferenceRepository) MarkDigestSent(userID uuid.UUID, weekly bool, at time.Time) error {
+	column := "daily_digest_sent_at"
+	if weekly {
+		column = "weekly_digest_sent_at"
+	}
+	return r.db.Model(&models.NotificationPreference{}).
+		Where("user_id = ?", userID).
+		Update(column, at).Error
+}
+
+func (r *notificationPreferenceRepository) CreateHeld(held *models.HeldNotification) error {
+	return r.db.Create(held).Error
+}
+
+// FindDueHeld returns the held messages to release at now, grouped by user
+// and oldest first.
+func (r *notificationPreferenceRepository) FindDueHeld(now time.Time, limit int) ([]models.HeldNotification, error) {
+	var held []models.HeldNotification
+	err := r.db.Where("release_at <= ?", now).
+		Order("user_id, created_at").
+		Limit(limit).
+		Find(&held).Error
+	if err != nil {
+		return nil, err
+	}
+	return held, nil
+}
+
+func (r *notificationPreferenceRepository) DeleteHeld(ids []uuid.UUID) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	return r.db.Where("id IN ?", ids).Delete(&models.HeldNotification{}).Error
+}
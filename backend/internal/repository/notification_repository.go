@@ -1,6 +1,8 @@
 package repository
 
 import (
+	"errors"
+
 	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/models"
 	"github.com/google/uuid"
 	"gorm.io/gorm"
@@ -8,8 +10,10 @@ import (
 
 type NotificationRepository interface {
 	Create(notification *models.Notification) error
+	FindByID(id uuid.UUID) (*models.Notification, error)
 	FindByUserID(userID uuid.UUID) ([]models.Notification, error)
 	FindActiveByUserID(userID uuid.UUID) ([]models.Notification, error)
+	FindActiveByOrganizationID(organizationID uuid.UUID) ([]models.Notification, error)
 }
 
 type notificationRepository struct {
@@ -24,18 +28,43 @@ func (r *notificationRepository) Create(notification *models.Notification) error
 	return r.db.Create(notification).Error
 }
 
+func (r *notificationRepository) FindByID(id uuid.UUID) (*models.Notification, error) {
+	var notification models.Notification
+	err := r.db.Where("id = ?", id).First(&notification).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, gorm.ErrRecordNotFound
+		}
+		return nil, err
+	}
+	return &notification, nil
+}
+
+// FindByUserID returns the personal rules of the user plus the rules of the
+// organizations the user belongs to.
 func (r *notificationRepository) FindByUserID(userID uuid.UUID) ([]models.Notification, error) {
 	var notifications []models.Notification
-	err := r.db.Where("user_id = ?", userID).Find(&notifications).Error
+	err := r.db.Scopes(accessibleBy(userID)).Find(&notifications).Error
 	if err != nil {
 		return nil, err
 	}
 	return notifications, nil
 }
 
+// FindActiveByUserID returns the enabled personal rules of the user; they
+// apply to the user's personal devices.
 func (r *notificationRepository) FindActiveByUserID(userID uuid.UUID) ([]models.Notification, error) {
 	var notifications []models.Notification
-	err := r.db.Where("user_id = ? AND enabled = ?", userID, true).Find(&notifications).Error
+	err := r.db.Where("user_id = ? AND organization_id IS NULL AND enabled = ?", userID, true).Find(&notifications).Error
+	if err != nil {
+		return nil, err
+	}
+	return notifications, nil
+}
+
+func (r *notificationRepository) FindActiveByOrganizationID(organizationID uuid.UUID) ([]models.Notification, error) {
+	var notifications []models.Notification
+	err := r.db.Where("organization_id = ? AND enabled = ?", organizationID, true).Find(&notifications).Error
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,132 @@
+package repository
+
+import (
+	"errors"
+
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type OrganizationRepository interface {
+	Create(organization *models.Organization, owner *models.Membership) error
+	FindByID(id uuid.UUID) (*models.Organization, error)
+	FindMembershipsByUserID(userID uuid.UUID) ([]models.Membership, error)
+	FindMembership(organizationID, userID uuid.UUID) (*models.Membership, error)
+	FindMembers(organizationID uuid.UUID) ([]models.Membership, error)
+	CreateMembership(membership *models.Membership) error
+	UpdateMembershipRole(organizationID, userID uuid.UUID, role string) error
+	DeleteMembership(organizationID, userID uuid.UUID) error
+	CountMembersWithRole(organizationID uuid.UUID, role string) (int64, error)
+}
+
+// memberOrganizations selects the organizations a user belongs to; it backs
+// the visibility scope of devices, groups and rules.
+const memberOrganizations = "SELECT organization_id FROM memberships WHERE user_id = ?"
+
+// accessibleBy restricts a query to the personal resources of userID plus the
+// resources of every organization the user is a member of.
+func accessibleBy(userID uuid.UUID) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where("((organization_id IS NULL AND user_id = ?) OR organization_id IN ("+memberOrganizations+"))", userID, userID)
+	}
+}
+
+type organizationRepository struct {
+	db *gorm.DB
+}
+
+func NewOrganizationRepository(db *gorm.DB) OrganizationRepository {
+	return &organizationRepository{db: db}
+}
+
+// Create inserts the organization together with its first owner.
+func (r *organizationRepository) Create(organization *models.Organization, owner *models.Membership) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(organization).Error; err != nil {
+			return err
+		}
+		return tx.Omit("User").Create(owner).Error
+	})
+}
+
+func (r *organizationRepository) FindByID(id uuid.UUID) (*models.Organization, error) {
+	var organization models.Organization
+	err := r.db.Where("id = ?", id).First(&organization).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, gorm.ErrRecordNotFound
+		}
+		return nil, err
+	}
+	return &organization, nil
+}
+
+func (r *organizationRepository) FindMembershipsByUserID(userID uuid.UUID) ([]models.Membership, error) {
+	var memberships []models.Membership
+	err := r.db.Where("user_id = ?", userID).Order("created_at ASC").Find(&memberships).Error
+	if err != nil {
+		return nil, err
+	}
+	return memberships, nil
+}
+
+func (r *organizationRepository) FindMembership(organizationID, userID uuid.UUID) (*models.Membership, error) {
+	var membership models.Membership
+	err := r.db.Where("organization_id = ? AND user_id = ?", organizationID, userID).First(&membership).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, gorm.ErrRecordNotFound
+		}
+		return nil, err
+	}
+	return &membership, nil
+}
+
+func (r *organizationRepository) FindMembers(organizationID uuid.UUID) ([]models.Membership, error) {
+	var memberships []models.Membership
+	err := r.db.Preload("User").
+		Where("organization_id = ?", organizationID).
+		Order("created_at ASC").
+		Find(&memberships).Error
+	if err != nil {
+		return nil, err
+	}
+	return memberships, nil
+}
+
+func (r *organizationRepository) CreateMembership(membership *models.Membership) error {
+	return r.db.Omit("User").Create(membership).Error
+}
+
+func (r *organizationRepository) UpdateMembershipRole(organizationID, userID uuid.UUID, role string) error {
+	result := r.db.Model(&models.Membership{}).
+		Where("organization_id = ? AND user_id = ?", organizationID, userID).
+		Update("role", role)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *organizationRepository) DeleteMembership(organizationID, userID uuid.UUID) error {
+	result := r.db.Where("organization_id = ? AND user_id = ?", organizationID, userID).Delete(&models.Membership{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *organizationRepository) CountMembersWithRole(organizationID uuid.UUID, role string) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.Membership{}).
+		Where("organization_id = ? AND role = ?", organizationID, role).
+		Count(&count).Error
+	return count, err
+}
@@ -0,0 +1,19 @@
+package routers
+
+import (
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/handlers"
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/middlewares"
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+func SetupAlertRoutes(router *gin.Engine, alertHandler *handlers.AlertHandler, jwtService services.JWTService) {
+	authMiddleware := middlewares.AuthMiddleware(jwtService)
+	alertRoutes := router.Group("/api/v1/alerts")
+	alertRoutes.Use(authMiddleware)
+	{
+		alertRoutes.GET("", alertHandler.ListAlerts)
+		alertRoutes.GET("/:id", alertHandler.GetAlert)
+		alertRoutes.POST("/:id/acknowledge", alertHandler.AcknowledgeAlert)
+	}
+}
@@ -0,0 +1,20 @@
+package routers
+
+import (
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/handlers"
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/middlewares"
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+func SetupDeviceCommandRoutes(router *gin.Engine, deviceCommandHandler *handlers.DeviceCommandHandler, jwtService services.JWTService) {
+	authMiddleware := middlewares.AuthMiddleware(jwtService)
+	commandRoutes := router.Group("/api/v1/devices/:id/commands")
+	commandRoutes.Use(authMiddleware)
+	{
+		commandRoutes.GET("", deviceCommandHandler.ListDeviceCommands)
+		commandRoutes.POST("", deviceCommandHandler.IssueDeviceCommand)
+		commandRoutes.GET("/:command_id", deviceCommandHandler.GetDeviceCommand)
+		commandRoutes.DELETE("/:command_id", deviceCommandHandler.CancelDeviceCommand)
+	}
+}
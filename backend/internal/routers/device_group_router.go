@@ -0,0 +1,25 @@
+package routers
+
+import (
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/handlers"
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/middlewares"
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+func SetupDeviceGroupRoutes(router *gin.Engine, deviceGroupHandler *handlers.DeviceGroupHandler, jwtService services.JWTService) {
+	authMiddleware := middlewares.AuthMiddleware(jwtService)
+	groupRoutes := router.Group("/api/v1/groups")
+	groupRoutes.Use(authMiddleware)
+	{
+		groupRoutes.GET("", deviceGroupHandler.ListGroups)
+		groupRoutes.POST("", deviceGroupHandler.CreateGroup)
+		groupRoutes.GET("/:id", deviceGroupHandler.GetGroup)
+		groupRoutes.PUT("/:id", deviceGroupHandler.UpdateGroup)
+		groupRoutes.DELETE("/:id", deviceGroupHandler.DeleteGroup)
+		groupRoutes.GET("/:id/summary", deviceGroupHandler.GetGroupSummary)
+		groupRoutes.GET("/:id/devices", deviceGroupHandler.ListGroupDevices)
+		groupRoutes.POST("/:id/devices", deviceGroupHandler.AddDevices)
+		groupRoutes.DELETE("/:id/devices/:device_id", deviceGroupHandler.RemoveDevice)
+	}
+}
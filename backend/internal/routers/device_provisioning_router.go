@@ -0,0 +1,24 @@
+package routers
+
+import (
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/handlers"
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/middlewares"
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+func SetupDeviceProvisioningRoutes(router *gin.Engine, provisioningHandler *handlers.DeviceProvisioningHandler, jwtService services.JWTService) {
+	authMiddleware := middlewares.AuthMiddleware(jwtService)
+
+	// Devices call this on first boot; the claim code is their credential.
+	router.POST("/api/v1/provision", provisioningHandler.ProvisionDevice)
+
+	registrationRoutes := router.Group("/api/v1/provisioning/registrations")
+	registrationRoutes.Use(authMiddleware)
+	{
+		registrationRoutes.GET("", provisioningHandler.ListDeviceRegistrations)
+		registrationRoutes.POST("", provisioningHandler.RegisterDevices)
+	}
+
+	router.POST("/api/v1/devices/claim", authMiddleware, provisioningHandler.ClaimDevice)
+}
@@ -14,8 +14,17 @@ func SetupDeviceRoutes(router *gin.Engine, deviceHandler *handlers.DeviceHandler
     {
         deviceRoutes.GET("", deviceHandler.ListDevices)
         deviceRoutes.POST("", deviceHandler.CreateDevice)
+        deviceRoutes.POST("/import", deviceHandler.ImportDevices)
+        deviceRoutes.GET("/export", deviceHandler.ExportDevices)
+        deviceRoutes.GET("/deleted", deviceHandler.ListDeletedDevices)
         deviceRoutes.GET("/:id", deviceHandler.GetDevice)
         deviceRoutes.PUT("/:id", deviceHandler.UpdateDevice)
         deviceRoutes.DELETE("/:id", deviceHandler.DeleteDevice)
+        deviceRoutes.POST("/:id/restore", deviceHandler.RestoreDevice)
+        deviceRoutes.DELETE("/:id/purge", deviceHandler.PurgeDevice)
+        deviceRoutes.GET("/:id/labels", deviceHandler.GetDeviceLabels)
+        deviceRoutes.PUT("/:id/labels", deviceHandler.ReplaceDeviceLabels)
+        deviceRoutes.PUT("/:id/labels/:key", deviceHandler.SetDeviceLabel)
+        deviceRoutes.DELETE("/:id/labels/:key", deviceHandler.DeleteDeviceLabel)
     }
 }
\ No newline at end of file
@@ -0,0 +1,19 @@
+package routers
+
+import (
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/handlers"
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/middlewares"
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+func SetupDeviceShadowRoutes(router *gin.Engine, deviceShadowHandler *handlers.DeviceShadowHandler, jwtService services.JWTService) {
+	authMiddleware := middlewares.AuthMiddleware(jwtService)
+	shadowRoutes := router.Group("/api/v1/devices/:id/shadow")
+	shadowRoutes.Use(authMiddleware)
+	{
+		shadowRoutes.GET("", deviceShadowHandler.GetDeviceShadow)
+		shadowRoutes.PUT("/desired", deviceShadowHandler.ReplaceDesiredState)
+		shadowRoutes.PATCH("/desired", deviceShadowHandler.UpdateDesiredState)
+	}
+}
@@ -0,0 +1,19 @@
+package routers
+
+import (
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/handlers"
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/middlewares"
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+func SetupDeviceShareRoutes(router *gin.Engine, deviceShareHandler *handlers.DeviceShareHandler, jwtService services.JWTService) {
+	authMiddleware := middlewares.AuthMiddleware(jwtService)
+	shareRoutes := router.Group("/api/v1/devices/:id/shares")
+	shareRoutes.Use(authMiddleware)
+	{
+		shareRoutes.GET("", deviceShareHandler.ListDeviceShares)
+		shareRoutes.POST("", deviceShareHandler.ShareDevice)
+		shareRoutes.DELETE("/:share_id", deviceShareHandler.RevokeDeviceShare)
+	}
+}
@@ -0,0 +1,27 @@
+package routers
+
+import (
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/handlers"
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/middlewares"
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+func SetupDeviceTransferRoutes(router *gin.Engine, deviceTransferHandler *handlers.DeviceTransferHandler, jwtService services.JWTService) {
+	authMiddleware := middlewares.AuthMiddleware(jwtService)
+	deviceRoutes := router.Group("/api/v1/devices/:id")
+	deviceRoutes.Use(authMiddleware)
+	{
+		deviceRoutes.POST("/transfers", deviceTransferHandler.RequestDeviceTransfer)
+		deviceRoutes.GET("/audit", deviceTransferHandler.GetDeviceAuditLog)
+	}
+
+	transferRoutes := router.Group("/api/v1/transfers")
+	transferRoutes.Use(authMiddleware)
+	{
+		transferRoutes.GET("", deviceTransferHandler.ListDeviceTransfers)
+		transferRoutes.POST("/:id/accept", deviceTransferHandler.AcceptDeviceTransfer)
+		transferRoutes.POST("/:id/decline", deviceTransferHandler.DeclineDeviceTransfer)
+		transferRoutes.DELETE("/:id", deviceTransferHandler.CancelDeviceTransfer)
+	}
+}
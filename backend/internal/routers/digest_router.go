@@ -0,0 +1,17 @@
+package routers
+
+import (
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/handlers"
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/middlewares"
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+func SetupDigestRoutes(router *gin.Engine, digestHandler *handlers.DigestHandler, jwtService services.JWTService) {
+	authMiddleware := middlewares.AuthMiddleware(jwtService)
+	digestRoutes := router.Group("/api/v1/digests")
+	digestRoutes.Use(authMiddleware)
+	{
+		digestRoutes.GET("/preview", digestHandler.PreviewDigest)
+	}
+}
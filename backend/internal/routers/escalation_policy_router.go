@@ -0,0 +1,21 @@
+package routers
+
+import (
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/handlers"
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/middlewares"
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+func SetupEscalationPolicyRoutes(router *gin.Engine, policyHandler *handlers.EscalationPolicyHandler, jwtService services.JWTService) {
+	authMiddleware := middlewares.AuthMiddleware(jwtService)
+	policyRoutes := router.Group("/api/v1/escalation-policies")
+	policyRoutes.Use(authMiddleware)
+	{
+		policyRoutes.GET("", policyHandler.ListEscalationPolicies)
+		policyRoutes.POST("", policyHandler.CreateEscalationPolicy)
+		policyRoutes.GET("/:id", policyHandler.GetEscalationPolicy)
+		policyRoutes.PUT("/:id", policyHandler.UpdateEscalationPolicy)
+		policyRoutes.DELETE("/:id", policyHandler.DeleteEscalationPolicy)
+	}
+}
@@ -0,0 +1,35 @@
+package routers
+
+import (
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/handlers"
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/middlewares"
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+func SetupFirmwareRoutes(router *gin.Engine, firmwareHandler *handlers.FirmwareHandler, rolloutHandler *handlers.FirmwareRolloutHandler, jwtService services.JWTService) {
+	authMiddleware := middlewares.AuthMiddleware(jwtService)
+
+	// Devices download the firmware of a rollout with the token sent in
+	// their update_firmware command.
+	router.GET("/api/v1/firmware/download/:token", firmwareHandler.DownloadDeviceFirmware)
+
+	firmwareRoutes := router.Group("/api/v1/firmware")
+	firmwareRoutes.Use(authMiddleware)
+	{
+		firmwareRoutes.GET("/inventory", firmwareHandler.GetFirmwareInventory)
+
+		firmwareRoutes.GET("/artifacts", firmwareHandler.ListFirmware)
+		firmwareRoutes.POST("/artifacts", firmwareHandler.UploadFirmware)
+		firmwareRoutes.GET("/artifacts/:id", firmwareHandler.GetFirmware)
+		firmwareRoutes.GET("/artifacts/:id/download", firmwareHandler.DownloadFirmware)
+		firmwareRoutes.DELETE("/artifacts/:id", firmwareHandler.DeleteFirmware)
+
+		firmwareRoutes.GET("/rollouts", rolloutHandler.ListFirmwareRollouts)
+		firmwareRoutes.POST("/rollouts", rolloutHandler.CreateFirmwareRollout)
+		firmwareRoutes.GET("/rollouts/:id", rolloutHandler.GetFirmwareRollout)
+		firmwareRoutes.POST("/rollouts/:id/pause", rolloutHandler.PauseFirmwareRollout)
+		firmwareRoutes.POST("/rollouts/:id/resume", rolloutHandler.ResumeFirmwareRollout)
+		firmwareRoutes.DELETE("/rollouts/:id", rolloutHandler.CancelFirmwareRollout)
+	}
+}
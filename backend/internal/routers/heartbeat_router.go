@@ -14,5 +14,12 @@ func SetupHeartbeatRoutes(router *gin.Engine, heartbeatHandler *handlers.Heartbe
     {
         heartbeatRoutes.GET("", heartbeatHandler.GetDeviceHeartbeats)
         heartbeatRoutes.GET("/latest", heartbeatHandler.GetLatestDeviceHeartbeat)
+        heartbeatRoutes.GET("/export", heartbeatHandler.ExportDeviceHeartbeats)
+    }
+
+    exportRoutes := router.Group("/api/v1/heartbeats")
+    exportRoutes.Use(authMiddleware)
+    {
+        exportRoutes.GET("/export", heartbeatHandler.ExportHeartbeats)
     }
 }
\ No newline at end of file
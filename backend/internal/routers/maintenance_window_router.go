@@ -0,0 +1,21 @@
+package routers
+
+import (
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/handlers"
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/middlewares"
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+func SetupMaintenanceWindowRoutes(router *gin.Engine, windowHandler *handlers.MaintenanceWindowHandler, jwtService services.JWTService) {
+	authMiddleware := middlewares.AuthMiddleware(jwtService)
+	windowRoutes := router.Group("/api/v1/maintenance-windows")
+	windowRoutes.Use(authMiddleware)
+	{
+		windowRoutes.GET("", windowHandler.ListMaintenanceWindows)
+		windowRoutes.POST("", windowHandler.CreateMaintenanceWindow)
+		windowRoutes.GET("/:id", windowHandler.GetMaintenanceWindow)
+		windowRoutes.DELETE("/:id", windowHandler.DeleteMaintenanceWindow)
+		windowRoutes.GET("/:id/suppressed-alerts", windowHandler.ListSuppressedAlerts)
+	}
+}
@@ -0,0 +1,19 @@
+package routers
+
+import (
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/handlers"
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/middlewares"
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+func SetupMuteRoutes(router *gin.Engine, muteHandler *handlers.MuteHandler, jwtService services.JWTService) {
+	authMiddleware := middlewares.AuthMiddleware(jwtService)
+	muteRoutes := router.Group("/api/v1/mutes")
+	muteRoutes.Use(authMiddleware)
+	{
+		muteRoutes.GET("", muteHandler.ListMutes)
+		muteRoutes.POST("", muteHandler.CreateMute)
+		muteRoutes.DELETE("/:id", muteHandler.CancelMute)
+	}
+}
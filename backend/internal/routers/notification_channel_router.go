@@ -0,0 +1,23 @@
+package routers
+
+import (
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/handlers"
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/middlewares"
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+func SetupNotificationChannelRoutes(router *gin.Engine, channelHandler *handlers.NotificationChannelHandler, jwtService services.JWTService) {
+	authMiddleware := middlewares.AuthMiddleware(jwtService)
+	channelRoutes := router.Group("/api/v1/notification-channels")
+	channelRoutes.Use(authMiddleware)
+	{
+		channelRoutes.GET("", channelHandler.ListNotificationChannels)
+		channelRoutes.POST("", channelHandler.CreateNotificationChannel)
+		channelRoutes.GET("/:id", channelHandler.GetNotificationChannel)
+		channelRoutes.PUT("/:id", channelHandler.UpdateNotificationChannel)
+		channelRoutes.DELETE("/:id", channelHandler.DeleteNotificationChannel)
+		channelRoutes.POST("/:id/test", channelHandler.TestNotificationChannel)
+		channelRoutes.GET("/:id/deliveries", channelHandler.ListNotificationDeliveries)
+	}
+}
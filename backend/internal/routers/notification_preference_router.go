@@ -0,0 +1,18 @@
+package routers
+
+import (
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/handlers"
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/middlewares"
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+func SetupNotificationPreferenceRoutes(router *gin.Engine, preferenceHandler *handlers.NotificationPreferenceHandler, jwtService services.JWTService) {
+	authMiddleware := middlewares.AuthMiddleware(jwtService)
+	preferenceRoutes := router.Group("/api/v1/notification-preferences")
+	preferenceRoutes.Use(authMiddleware)
+	{
+		preferenceRoutes.GET("", preferenceHandler.GetNotificationPreferences)
+		preferenceRoutes.PUT("", preferenceHandler.UpdateNotificationPreferences)
+	}
+}
@@ -14,5 +14,7 @@ func SetupNotificationRoutes(router *gin.Engine, notificationHandler *handlers.N
 	{
 		notificationRoutes.GET("", notificationHandler.GetNotifications)
 		notificationRoutes.POST("", notificationHandler.CreateNotification)
+		notificationRoutes.POST("/preview", notificationHandler.PreviewNotificationTemplate)
+		notificationRoutes.POST("/test", notificationHandler.TestNotificationRule)
 	}
 }
\ No newline at end of file
@@ -0,0 +1,25 @@
+package routers
+
+import (
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/handlers"
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/middlewares"
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+func SetupOrganizationRoutes(router *gin.Engine, organizationHandler *handlers.OrganizationHandler, jwtService services.JWTService) {
+	authMiddleware := middlewares.AuthMiddleware(jwtService)
+	orgRoutes := router.Group("/api/v1/orgs")
+	orgRoutes.Use(authMiddleware)
+	{
+		orgRoutes.GET("", organizationHandler.ListOrganizations)
+		orgRoutes.POST("", organizationHandler.CreateOrganization)
+		orgRoutes.GET("/:id", organizationHandler.GetOrganization)
+		orgRoutes.POST("/:id/token", organizationHandler.SwitchOrganization)
+		orgRoutes.GET("/:id/members", organizationHandler.ListMembers)
+		orgRoutes.POST("/:id/members", organizationHandler.AddMember)
+		orgRoutes.PUT("/:id/members/:user_id", organizationHandler.UpdateMember)
+		orgRoutes.DELETE("/:id/members/:user_id", organizationHandler.RemoveMember)
+		orgRoutes.POST("/:id/devices", organizationHandler.MoveDevices)
+	}
+}
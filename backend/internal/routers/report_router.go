@@ -0,0 +1,17 @@
+package routers
+
+import (
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/handlers"
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/middlewares"
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+func SetupReportRoutes(router *gin.Engine, reportHandler *handlers.ReportHandler, jwtService services.JWTService) {
+	authMiddleware := middlewares.AuthMiddleware(jwtService)
+	reportRoutes := router.Group("/api/v1/reports")
+	reportRoutes.Use(authMiddleware)
+	{
+		reportRoutes.GET("/availability", reportHandler.GetAvailabilityReport)
+	}
+}
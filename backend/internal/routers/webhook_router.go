@@ -0,0 +1,24 @@
+package routers
+
+import (
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/handlers"
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/middlewares"
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+func SetupWebhookRoutes(router *gin.Engine, webhookHandler *handlers.WebhookHandler, jwtService services.JWTService) {
+	authMiddleware := middlewares.AuthMiddleware(jwtService)
+	webhookRoutes := router.Group("/api/v1/webhooks")
+	webhookRoutes.Use(authMiddleware)
+	{
+		webhookRoutes.GET("", webhookHandler.ListWebhooks)
+		webhookRoutes.POST("", webhookHandler.CreateWebhook)
+		webhookRoutes.GET("/:id", webhookHandler.GetWebhook)
+		webhookRoutes.PUT("/:id", webhookHandler.UpdateWebhook)
+		webhookRoutes.DELETE("/:id", webhookHandler.DeleteWebhook)
+		webhookRoutes.POST("/:id/rotate-secret", webhookHandler.RotateWebhookSecret)
+		webhookRoutes.GET("/:id/deliveries", webhookHandler.ListWebhookDeliveries)
+		webhookRoutes.POST("/:id/deliveries/:delivery_id/redeliver", webhookHandler.RedeliverWebhookDelivery)
+	}
+}
@@ -0,0 +1,58 @@
+package services
+
+import (
+	"time"
+
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/utils/logger"
+)
+
+// AlertEscalationJob periodically walks the firing alerts that nobody
+// acknowledged through the steps of their escalation policy.
+type AlertEscalationJob struct {
+	alertService AlertService
+	interval     time.Duration
+	shutdown     chan struct{}
+}
+
+func NewAlertEscalationJob(alertService AlertService, interval time.Duration) *AlertEscalationJob {
+	return &AlertEscalationJob{
+		alertService: alertService,
+		interval:     interval,
+		shutdown:     make(chan struct{}),
+	}
+}
+
+// Run processes escalations once immediately and then every interval until
+// Stop is called.
+func (j *AlertEscalationJob) Run() {
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	for {
+		j.process()
+		select {
+		case <-ticker.C:
+		case <-j.shutdown:
+			return
+		}
+	}
+}
+
+func (j *AlertEscalationJob) Stop() {
+	select {
+	case <-j.shutdown:
+	default:
+		close(j.shutdown)
+	}
+}
+
+func (j *AlertEscalationJob) process() {
+	escalated, err := j.alertService.ProcessEscalations(time.Now())
+	if err != nil {
+		logger.Logger.Error("Error processing alert escalations", "error", err)
+		return
+	}
+	if escalated > 0 {
+		logger.Logger.Info("Escalated alerts", "escalated", escalated)
+	}
+}
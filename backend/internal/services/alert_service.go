@@ -0,0 +1,359 @@
+package services
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/dto"
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/models"
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/repository"
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/utils/errors"
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/utils/logger"
+	"github.com/google/uuid"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+const (
+	// MaxAlerts is the number of alerts returned by a listing.
+	MaxAlerts = 200
+	// EscalationBatchSize is the number of due escalations handled per run.
+	EscalationBatchSize = 100
+)
+
+type AlertService interface {
+	ListAlerts(userID uuid.UUID, query dto.AlertListQuery) ([]dto.AlertResponse, error)
+	GetAlert(userID, alertID uuid.UUID) (*dto.AlertResponse, error)
+	AcknowledgeAlert(userID, alertID uuid.UUID) (*dto.AlertResponse, error)
+	Fire(notification models.Notification, device *models.Device, alert *dto.NotificationAlert, now time.Time) (opened bool, err error)
+	Resolve(device *models.Device, notificationIDs []uuid.UUID, now time.Time) error
+	ProcessEscalations(now time.Time) (escalated int, err error)
+}
+
+type alertService struct {
+	alertRepo         repository.AlertRepository
+	policyRepo        repository.EscalationPolicyRepository
+	channelService    NotificationChannelService
+	preferenceService NotificationPreferenceService
+	authz             Authorizer
+}
+
+func NewAlertService(alertRepo repository.AlertRepository, policyRepo repository.EscalationPolicyRepository, channelService NotificationChannelService, preferenceService NotificationPreferenceService, authz Authorizer) AlertService {
+	return &alertService{
+		alertRepo:         alertRepo,
+		policyRepo:        policyRepo,
+		channelService:    channelService,
+		preferenceService: preferenceService,
+		authz:             authz,
+	}
+}
+
+// ListAlerts returns the latest alerts of the rules the user can see, newest
+// first, optionally narrowed by status, minimum severity, rule and device.
+func (s *alertService) ListAlerts(userID uuid.UUID, query dto.AlertListQuery) ([]dto.AlertResponse, error) {
+	filter := repository.AlertFilter{Status: query.Status, Limit: MaxAlerts}
+	switch query.Status {
+	case "", models.AlertFiring, models.AlertAcknowledged, models.AlertResolved:
+	default:
+		return nil, errors.NewValidationError("status must be firing, acknowledged or resolved")
+	}
+	if query.MinSeverity != "" {
+		if !models.IsValidSeverity(query.MinSeverity) {
+			return nil, errors.NewValidationError("min_severity must be info, warning or critical")
+		}
+		filter.Severities = models.SeveritiesAtLeast(query.MinSeverity)
+	}
+	if query.NotificationID != "" {
+		id, err := uuid.Parse(query.NotificationID)
+		if err != nil {
+			return nil, errors.NewValidationError("Invalid notification_id")
+		}
+		filter.NotificationID = &id
+	}
+	if query.DeviceID != "" {
+		id, err := uuid.Parse(query.DeviceID)
+		if err != nil {
+			return nil, errors.NewValidationError("Invalid device_id")
+		}
+		filter.DeviceID = &id
+	}
+
+	alerts, err := s.alertRepo.FindByUserID(userID, filter)
+	if err != nil {
+		return nil, errors.ErrDatabaseError
+	}
+
+	responses := make([]dto.AlertResponse, 0, len(alerts))
+	for i := range alerts {
+		responses = append(responses, *alertResponse(&alerts[i]))
+	}
+	return responses, nil
+}
+
+func (s *alertService) GetAlert(userID, alertID uuid.UUID) (*dto.AlertResponse, error) {
+	alert, err := s.findAlert(userID, alertID)
+	if err != nil {
+		return nil, err
+	}
+	return alertResponse(alert), nil
+}
+
+// AcknowledgeAlert stops the escalation of a firing alert. Any member who
+// can see the alert can acknowledge it, so that the people paged by a policy
+// can do so whatever their role.
+func (s *alertService) AcknowledgeAlert(userID, alertID uuid.UUID) (*dto.AlertResponse, error) {
+	alert, err := s.findAlert(userID, alertID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	err = s.alertRepo.Transition(alert.ID, []string{models.AlertFiring}, map[string]interface{}{
+		"status":             models.AlertAcknowledged,
+		"acknowledged_at":    now,
+		"acknowledged_by":    userID,
+		"next_escalation_at": nil,
+		"updated_at":         now,
+	})
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.ErrAlertNotFiring
+		}
+		return nil, errors.ErrDatabaseError
+	}
+
+	alert.Status = models.AlertAcknowledged
+	alert.AcknowledgedAt = &now
+	alert.AcknowledgedBy = &userID
+	alert.NextEscalationAt = nil
+	alert.UpdatedAt = now
+	return alertResponse(alert), nil
+}
+
+// Fire records that the rule triggered for the device: it retriggers the
+// open alert of the pair or opens a new one, which starts the escalation
+// policy of the rule, and reports whether it opened one. It sets the AlertID
+// of msg, which is stored as the payload of the escalation notifications.
+func (s *alertService) Fire(notification models.Notification, device *models.Device, msg *dto.NotificationAlert, now time.Time) (bool, error) {
+	open, err := s.alertRepo.FindOpen(notification.ID, device.UUID)
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return false, errors.ErrDatabaseError
+	}
+
+	conditions, err := json.Marshal(msg.Conditions)
+	if err != nil {
+		return false, errors.ErrDatabaseError
+	}
+
+	if open != nil {
+		msg.AlertID = open.ID
+		payload, err := json.Marshal(msg)
+		if err != nil {
+			return false, errors.ErrDatabaseError
+		}
+		if err := s.alertRepo.Retrigger(open.ID, msg.TriggeredValue, datatypes.JSON(conditions), datatypes.JSON(payload), now); err != nil {
+			return false, errors.ErrDatabaseError
+		}
+		return false, nil
+	}
+
+	alert := &models.Alert{
+		ID:              uuid.New(),
+		NotificationID:  notification.ID,
+		DeviceID:        device.UUID,
+		UserID:          notification.UserID,
+		OrganizationID:  notification.OrganizationID,
+		Name:            notification.Name,
+		DeviceSN:        device.SN,
+		Status:          models.AlertFiring,
+		Severity:        notification.Severity,
+		TriggeredValue:  msg.TriggeredValue,
+		TriggerCount:    1,
+		Conditions:      datatypes.JSON(conditions),
+		FiredAt:         now,
+		LastTriggeredAt: now,
+		CreatedAt:       now,
+		UpdatedAt:       now,
+	}
+	msg.AlertID = alert.ID
+	payload, err := json.Marshal(msg)
+	if err != nil {
+		return false, errors.ErrDatabaseError
+	}
+	alert.Payload = datatypes.JSON(payload)
+
+	if notification.EscalationPolicyID != nil {
+		policy, err := s.policyRepo.FindByID(*notification.EscalationPolicyID)
+		if err != nil && err != gorm.ErrRecordNotFound {
+			return false, errors.ErrDatabaseError
+		}
+		if policy != nil && len(policy.Steps) > 0 {
+			nextAt := now.Add(time.Duration(policy.Steps[0].DelayMinutes) * time.Minute)
+			alert.EscalationPolicyID = &policy.ID
+			alert.NextEscalationAt = &nextAt
+		}
+	}
+
+	if err := s.alertRepo.Create(alert); err != nil {
+		return false, errors.ErrDatabaseError
+	}
+	return true, nil
+}
+
+// Resolve resolves the open alerts of the rules, which applied to the device
+// but did not trigger for its last heartbeat.
+func (s *alertService) Resolve(device *models.Device, notificationIDs []uuid.UUID, now time.Time) error {
+	if _, err := s.alertRepo.Resolve(device.UUID, notificationIDs, now); err != nil {
+		return errors.ErrDatabaseError
+	}
+	return nil
+}
+
+// ProcessEscalations notifies the due steps of the firing alerts and
+// schedules their next notification. It returns how many were sent.
+func (s *alertService) ProcessEscalations(now time.Time) (int, error) {
+	alerts, err := s.alertRepo.FindDueEscalations(now, EscalationBatchSize)
+	if err != nil {
+		return 0, errors.ErrDatabaseError
+	}
+
+	policies := make(map[uuid.UUID]*models.EscalationPolicy)
+	escalated := 0
+	for i := range alerts {
+		alert := &alerts[i]
+		policy, err := s.escalationPolicy(policies, alert)
+		if err != nil {
+			return escalated, err
+		}
+
+		updates := map[string]interface{}{"next_escalation_at": nil, "updated_at": now}
+		if policy != nil && alert.EscalationStep < len(policy.Steps) {
+			step := policy.Steps[alert.EscalationStep]
+			s.notifyStep(alert, step, now)
+			escalated++
+
+			nextStep, repeats := alert.EscalationStep, alert.EscalationRepeats+1
+			delay := step.DelayMinutes
+			if repeats > step.Repeat {
+				nextStep, repeats = nextStep+1, 0
+				if nextStep < len(policy.Steps) {
+					delay = policy.Steps[nextStep].DelayMinutes
+				}
+			}
+			updates["escalation_step"] = nextStep
+			updates["escalation_repeats"] = repeats
+			if nextStep < len(policy.Steps) {
+				updates["next_escalation_at"] = now.Add(time.Duration(delay) * time.Minute)
+			}
+		}
+
+		// The alert may have been acknowledged or resolved meanwhile.
+		err = s.alertRepo.Transition(alert.ID, []string{models.AlertFiring}, updates)
+		if err != nil && err != gorm.ErrRecordNotFound {
+			return escalated, errors.ErrDatabaseError
+		}
+	}
+	return escalated, nil
+}
+
+// escalationPolicy returns the policy of the alert, caching it in policies,
+// or nil when it was deleted.
+func (s *alertService) escalationPolicy(policies map[uuid.UUID]*models.EscalationPolicy, alert *models.Alert) (*models.EscalationPolicy, error) {
+	if alert.EscalationPolicyID == nil {
+		return nil, nil
+	}
+	if policy, ok := policies[*alert.EscalationPolicyID]; ok {
+		return policy, nil
+	}
+
+	policy, err := s.policyRepo.FindByID(*alert.EscalationPolicyID)
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return nil, errors.ErrDatabaseError
+	}
+	policies[*alert.EscalationPolicyID] = policy
+	return policy, nil
+}
+
+// notifyStep queues the alert for the channels of the step and delivers it
+// to the users of the step according to their preferences. Failures are logged: the step is
+// not retried.
+func (s *alertService) notifyStep(alert *models.Alert, step models.EscalationStep, now time.Time) {
+	var msg dto.NotificationAlert
+	if err := json.Unmarshal(alert.Payload, &msg); err != nil {
+		logger.Logger.Error("Invalid alert payload", "alert_id", alert.ID.String(), "error", err)
+		return
+	}
+	msg.EscalationStep = alert.EscalationStep + 1
+	msg.Timestamp = now.Format(time.RFC3339)
+
+	// Enqueue only uses the owner and channels of the rule.
+	rule := models.Notification{
+		ID:             alert.NotificationID,
+		UserID:         alert.UserID,
+		OrganizationID: alert.OrganizationID,
+		ChannelIDs:     step.ChannelIDs,
+	}
+	if err := s.channelService.Enqueue(rule, msg); err != nil {
+		logger.Logger.Error("Error queueing escalation deliveries", "alert_id", alert.ID.String(), "error", err)
+	}
+
+	for _, userID := range step.UserIDs {
+		if err := s.preferenceService.Deliver(userID, msg, nil, now); err != nil {
+			logger.Logger.Error("Error publishing escalation", "alert_id", alert.ID.String(), "user_id", userID.String(), "error", err)
+		}
+	}
+
+	logger.Logger.Info("Alert escalated",
+		"alert_id", alert.ID.String(),
+		"step", msg.EscalationStep,
+		"users", len(step.UserIDs),
+		"channels", len(step.ChannelIDs))
+}
+
+func (s *alertService) findAlert(userID, alertID uuid.UUID) (*models.Alert, error) {
+	alert, err := s.alertRepo.FindByID(alertID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.ErrAlertNotFound
+		}
+		return nil, errors.ErrDatabaseError
+	}
+	if err := s.authz.Authorize(userID, alertOwnership(alert), models.RoleViewer); err != nil {
+		return nil, err
+	}
+	return alert, nil
+}
+
+func alertResponse(alert *models.Alert) *dto.AlertResponse {
+	return &dto.AlertResponse{
+		ID:                 alert.ID,
+		NotificationID:     alert.NotificationID,
+		OrganizationID:     alert.OrganizationID,
+		DeviceID:           alert.DeviceID,
+		DeviceSN:           alert.DeviceSN,
+		Name:               alert.Name,
+		Status:             alert.Status,
+		Severity:           alert.Severity,
+		TriggeredValue:     alert.TriggeredValue,
+		TriggerCount:       alert.TriggerCount,
+		Conditions:         alertConditions(alert),
+		FiredAt:            alert.FiredAt,
+		LastTriggeredAt:    alert.LastTriggeredAt,
+		AcknowledgedAt:     alert.AcknowledgedAt,
+		AcknowledgedBy:     alert.AcknowledgedBy,
+		ResolvedAt:         alert.ResolvedAt,
+		EscalationPolicyID: alert.EscalationPolicyID,
+		EscalationStep:     alert.EscalationStep,
+		NextEscalationAt:   alert.NextEscalationAt,
+	}
+}
+
+// alertConditions decodes the condition results stored with the alert.
+// Alerts recorded before they were stored have none.
+func alertConditions(alert *models.Alert) []dto.ConditionResult {
+	var conditions []dto.ConditionResult
+	if len(alert.Conditions) > 0 {
+		json.Unmarshal(alert.Conditions, &conditions)
+	}
+	return conditions
+}
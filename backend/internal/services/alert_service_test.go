@@ -0,0 +1,322 @@
+package services
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/dto"
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/models"
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/repository"
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/utils/errors"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+type MockAlertRepository struct {
+	mock.Mock
+}
+
+func (m *MockAlertRepository) Create(alert *models.Alert) error {
+	args := m.Called(alert)
+	return args.Error(0)
+}
+
+func (m *MockAlertRepository) FindByID(id uuid.UUID) (*models.Alert, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Alert), args.Error(1)
+}
+
+func (m *MockAlertRepository) FindOpen(notificationID, deviceID uuid.UUID) (*models.Alert, error) {
+	args := m.Called(notificationID, deviceID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Alert), args.Error(1)
+}
+
+func (m *MockAlertRepository) FindByUserID(userID uuid.UUID, filter repository.AlertFilter) ([]models.Alert, error) {
+	args := m.Called(userID, filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Alert), args.Error(1)
+}
+
+func (m *MockAlertRepository) FindFiredByDevices(deviceIDs []uuid.UUID, since time.Time) ([]models.Alert, error) {
+	args := m.Called(deviceIDs, since)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Alert), args.Error(1)
+}
+
+func (m *MockAlertRepository) Retrigger(id uuid.UUID, triggeredValue float64, conditions, payload datatypes.JSON, now time.Time) error {
+	args := m.Called(id, triggeredValue, conditions, payload, now)
+	return args.Error(0)
+}
+
+func (m *MockAlertRepository) Resolve(deviceID uuid.UUID, notificationIDs []uuid.UUID, now time.Time) (int64, error) {
+	args := m.Called(deviceID, notificationIDs, now)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockAlertRepository) Transition(id uuid.UUID, from []string, updates map[string]interface{}) error {
+	args := m.Called(id, from, updates)
+	return args.Error(0)
+}
+
+func (m *MockAlertRepository) FindDueEscalations(now time.Time, limit int) ([]models.Alert, error) {
+	args := m.Called(now, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Alert), args.Error(1)
+}
+
+func (m *MockAlertRepository) CountFiredBySeverity(userID uuid.UUID, from, to time.Time) (map[string]int64, error) {
+	args := m.Called(userID, from, to)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(map[string]int64), args.Error(1)
+}
+
+// noAlerts returns an alert service that opens a new alert every time a rule
+// fires, for tests that do not look at alerts.
+func noAlerts() AlertService {
+	alertRepo := new(MockAlertRepository)
+	alertRepo.On("FindOpen", mock.Anything, mock.Anything).Return(nil, gorm.ErrRecordNotFound).Maybe()
+	alertRepo.On("Create", mock.Anything).Return(nil).Maybe()
+	alertRepo.On("Resolve", mock.Anything, mock.Anything, mock.Anything).Return(int64(0), nil).Maybe()
+	return NewAlertService(alertRepo, new(MockEscalationPolicyRepository), noNotificationChannels(), defaultPreferences(new(MockRedisPublisher), noNotificationChannels()), NewAuthorizer(new(MockOrganizationRepository), noDeviceShares()))
+}
+
+func newTestAlertService() (AlertService, *MockAlertRepository, *MockEscalationPolicyRepository, *MockRedisPublisher) {
+	alertRepo := new(MockAlertRepository)
+	policyRepo := new(MockEscalationPolicyRepository)
+	redis := new(MockRedisPublisher)
+	authz := NewAuthorizer(new(MockOrganizationRepository), noDeviceShares())
+	return NewAlertService(alertRepo, policyRepo, noNotificationChannels(), defaultPreferences(redis, noNotificationChannels()), authz), alertRepo, policyRepo, redis
+}
+
+func TestAlertService_Fire(t *testing.T) {
+	userID := uuid.New()
+	device := &models.Device{UUID: uuid.New(), UserID: userID, SN: "123456789012"}
+	now := time.Now()
+
+	t.Run("Success - New alert starts the escalation policy", func(t *testing.T) {
+		service, alertRepo, policyRepo, _ := newTestAlertService()
+
+		policy := &models.EscalationPolicy{ID: uuid.New(), UserID: userID, Steps: []models.EscalationStep{{DelayMinutes: 10, UserIDs: []uuid.UUID{userID}}}}
+		rule := models.Notification{ID: uuid.New(), UserID: userID, Name: "High CPU", EscalationPolicyID: &policy.ID}
+		msg := &dto.NotificationAlert{ID: rule.ID, TriggeredValue: 91, Conditions: []dto.ConditionResult{{Parameter: "cpu", Operator: ">", Threshold: 80, Actual: 91, Matched: true}}}
+
+		alertRepo.On("FindOpen", rule.ID, device.UUID).Return(nil, gorm.ErrRecordNotFound)
+		policyRepo.On("FindByID", policy.ID).Return(policy, nil)
+		alertRepo.On("Create", mock.MatchedBy(func(alert *models.Alert) bool {
+			var payload dto.NotificationAlert
+			json.Unmarshal(alert.Payload, &payload)
+			response := alertResponse(alert)
+			return alert.Status == models.AlertFiring && alert.TriggerCount == 1 &&
+				len(response.Conditions) == 1 && response.Conditions[0].Actual == 91 && response.Conditions[0].Matched &&
+				*alert.EscalationPolicyID == policy.ID && alert.NextEscalationAt.Equal(now.Add(10*time.Minute)) &&
+				payload.AlertID == alert.ID
+		})).Return(nil)
+
+		opened, err := service.Fire(rule, device, msg, now)
+
+		assert.NoError(t, err)
+		assert.True(t, opened)
+		assert.NotEqual(t, uuid.Nil, msg.AlertID)
+		alertRepo.AssertExpectations(t)
+	})
+
+	t.Run("Success - Open alert is retriggered", func(t *testing.T) {
+		service, alertRepo, _, _ := newTestAlertService()
+
+		rule := models.Notification{ID: uuid.New(), UserID: userID}
+		open := &models.Alert{ID: uuid.New(), NotificationID: rule.ID, DeviceID: device.UUID, Status: models.AlertAcknowledged}
+		msg := &dto.NotificationAlert{ID: rule.ID, TriggeredValue: 95, Conditions: []dto.ConditionResult{{Parameter: "cpu", Operator: ">", Threshold: 80, Actual: 95, Matched: true}}}
+
+		alertRepo.On("FindOpen", rule.ID, device.UUID).Return(open, nil)
+		alertRepo.On("Retrigger", open.ID, 95.0, mock.MatchedBy(func(conditions datatypes.JSON) bool {
+			return strings.Contains(string(conditions), `"actual":95`)
+		}), mock.Anything, now).Return(nil)
+
+		opened, err := service.Fire(rule, device, msg, now)
+
+		assert.NoError(t, err)
+		assert.False(t, opened)
+		assert.Equal(t, open.ID, msg.AlertID)
+		alertRepo.AssertNotCalled(t, "Create", mock.Anything)
+	})
+}
+
+func TestAlertService_ListAlerts(t *testing.T) {
+	userID := uuid.New()
+
+	t.Run("Success - Minimum severity filter", func(t *testing.T) {
+		service, alertRepo, _, _ := newTestAlertService()
+
+		alertRepo.On("FindByUserID", userID, repository.AlertFilter{
+			Severities: []string{models.SeverityWarning, models.SeverityCritical},
+			Limit:      MaxAlerts,
+		}).Return([]models.Alert{{ID: uuid.New(), Severity: models.SeverityCritical}}, nil)
+
+		alerts, err := service.ListAlerts(userID, dto.AlertListQuery{MinSeverity: models.SeverityWarning})
+
+		assert.NoError(t, err)
+		assert.Len(t, alerts, 1)
+		assert.Equal(t, models.SeverityCritical, alerts[0].Severity)
+	})
+
+	t.Run("Error - Invalid severity", func(t *testing.T) {
+		service, alertRepo, _, _ := newTestAlertService()
+
+		_, err := service.ListAlerts(userID, dto.AlertListQuery{MinSeverity: "urgent"})
+
+		assert.Error(t, err)
+		alertRepo.AssertNotCalled(t, "FindByUserID", mock.Anything, mock.Anything)
+	})
+}
+
+func TestAlertService_AcknowledgeAlert(t *testing.T) {
+	userID := uuid.New()
+	nextAt := time.Now().Add(time.Minute)
+
+	t.Run("Success - Escalation stops", func(t *testing.T) {
+		service, alertRepo, _, _ := newTestAlertService()
+
+		alert := &models.Alert{ID: uuid.New(), UserID: userID, Status: models.AlertFiring, NextEscalationAt: &nextAt}
+		alertRepo.On("FindByID", alert.ID).Return(alert, nil)
+		alertRepo.On("Transition", alert.ID, []string{models.AlertFiring}, mock.MatchedBy(func(updates map[string]interface{}) bool {
+			return updates["status"] == models.AlertAcknowledged && updates["acknowledged_by"] == userID && updates["next_escalation_at"] == nil
+		})).Return(nil)
+
+		response, err := service.AcknowledgeAlert(userID, alert.ID)
+
+		assert.NoError(t, err)
+		assert.Equal(t, models.AlertAcknowledged, response.Status)
+		assert.Nil(t, response.NextEscalationAt)
+	})
+
+	t.Run("Error - Alert already resolved", func(t *testing.T) {
+		service, alertRepo, _, _ := newTestAlertService()
+
+		alert := &models.Alert{ID: uuid.New(), UserID: userID, Status: models.AlertResolved}
+		alertRepo.On("FindByID", alert.ID).Return(alert, nil)
+		alertRepo.On("Transition", alert.ID, []string{models.AlertFiring}, mock.Anything).Return(gorm.ErrRecordNotFound)
+
+		response, err := service.AcknowledgeAlert(userID, alert.ID)
+
+		assert.Nil(t, response)
+		assert.Equal(t, errors.ErrAlertNotFiring, err)
+	})
+
+	t.Run("Error - Alert of another user", func(t *testing.T) {
+		service, alertRepo, _, _ := newTestAlertService()
+
+		alert := &models.Alert{ID: uuid.New(), UserID: uuid.New(), Status: models.AlertFiring}
+		alertRepo.On("FindByID", alert.ID).Return(alert, nil)
+
+		_, err := service.AcknowledgeAlert(userID, alert.ID)
+
+		assert.Equal(t, errors.ErrForbidden, err)
+		alertRepo.AssertNotCalled(t, "Transition", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestAlertService_ProcessEscalations(t *testing.T) {
+	userID := uuid.New()
+	onCallID := uuid.New()
+	leadID := uuid.New()
+	now := time.Now()
+	payload, _ := json.Marshal(dto.NotificationAlert{Name: "High CPU"})
+	policy := &models.EscalationPolicy{ID: uuid.New(), UserID: userID, Steps: []models.EscalationStep{
+		{DelayMinutes: 5, UserIDs: []uuid.UUID{onCallID}, Repeat: 1},
+		{DelayMinutes: 15, UserIDs: []uuid.UUID{leadID}},
+	}}
+
+	t.Run("Success - Step is repeated before moving on", func(t *testing.T) {
+		service, alertRepo, policyRepo, redis := newTestAlertService()
+
+		alert := models.Alert{ID: uuid.New(), UserID: userID, Status: models.AlertFiring, Payload: payload, EscalationPolicyID: &policy.ID}
+		alertRepo.On("FindDueEscalations", now, EscalationBatchSize).Return([]models.Alert{alert}, nil)
+		policyRepo.On("FindByID", policy.ID).Return(policy, nil)
+		redis.On("Publish", mock.Anything, "notifications:"+onCallID.String(), mock.MatchedBy(func(message []byte) bool {
+			var msg dto.NotificationAlert
+			json.Unmarshal(message, &msg)
+			return msg.EscalationStep == 1
+		})).Return(nil)
+		alertRepo.On("Transition", alert.ID, []string{models.AlertFiring}, mock.MatchedBy(func(updates map[string]interface{}) bool {
+			return updates["escalation_step"] == 0 && updates["escalation_repeats"] == 1 && updates["next_escalation_at"] == now.Add(5*time.Minute)
+		})).Return(nil)
+
+		escalated, err := service.ProcessEscalations(now)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 1, escalated)
+		redis.AssertExpectations(t)
+		alertRepo.AssertExpectations(t)
+	})
+
+	t.Run("Success - Next step uses its own delay", func(t *testing.T) {
+		service, alertRepo, policyRepo, redis := newTestAlertService()
+
+		alert := models.Alert{ID: uuid.New(), UserID: userID, Status: models.AlertFiring, Payload: payload, EscalationPolicyID: &policy.ID, EscalationRepeats: 1}
+		alertRepo.On("FindDueEscalations", now, EscalationBatchSize).Return([]models.Alert{alert}, nil)
+		policyRepo.On("FindByID", policy.ID).Return(policy, nil)
+		redis.On("Publish", mock.Anything, "notifications:"+onCallID.String(), mock.Anything).Return(nil)
+		alertRepo.On("Transition", alert.ID, []string{models.AlertFiring}, mock.MatchedBy(func(updates map[string]interface{}) bool {
+			return updates["escalation_step"] == 1 && updates["escalation_repeats"] == 0 && updates["next_escalation_at"] == now.Add(15*time.Minute)
+		})).Return(nil)
+
+		_, err := service.ProcessEscalations(now)
+
+		assert.NoError(t, err)
+		alertRepo.AssertExpectations(t)
+	})
+
+	t.Run("Success - Last step ends the escalation", func(t *testing.T) {
+		service, alertRepo, policyRepo, redis := newTestAlertService()
+
+		alert := models.Alert{ID: uuid.New(), UserID: userID, Status: models.AlertFiring, Payload: payload, EscalationPolicyID: &policy.ID, EscalationStep: 1}
+		alertRepo.On("FindDueEscalations", now, EscalationBatchSize).Return([]models.Alert{alert}, nil)
+		policyRepo.On("FindByID", policy.ID).Return(policy, nil)
+		redis.On("Publish", mock.Anything, "notifications:"+leadID.String(), mock.Anything).Return(nil)
+		alertRepo.On("Transition", alert.ID, []string{models.AlertFiring}, mock.MatchedBy(func(updates map[string]interface{}) bool {
+			return updates["escalation_step"] == 2 && updates["next_escalation_at"] == nil
+		})).Return(nil)
+
+		_, err := service.ProcessEscalations(now)
+
+		assert.NoError(t, err)
+		redis.AssertExpectations(t)
+		alertRepo.AssertExpectations(t)
+	})
+
+	t.Run("Success - Deleted policy stops the escalation", func(t *testing.T) {
+		service, alertRepo, policyRepo, redis := newTestAlertService()
+
+		alert := models.Alert{ID: uuid.New(), UserID: userID, Status: models.AlertFiring, Payload: payload, EscalationPolicyID: &policy.ID}
+		alertRepo.On("FindDueEscalations", now, EscalationBatchSize).Return([]models.Alert{alert}, nil)
+		policyRepo.On("FindByID", policy.ID).Return(nil, gorm.ErrRecordNotFound)
+		alertRepo.On("Transition", alert.ID, []string{models.AlertFiring}, mock.MatchedBy(func(updates map[string]interface{}) bool {
+			return updates["next_escalation_at"] == nil
+		})).Return(nil)
+
+		escalated, err := service.ProcessEscalations(now)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 0, escalated)
+		redis.AssertNotCalled(t, "Publish", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
@@ -0,0 +1,294 @@
+package services
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"text/template"
+	"text/template/parse"
+	"time"
+
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/dto"
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/models"
+)
+
+const (
+	// MaxTemplateLength caps the source of a title or body template.
+	MaxTemplateLength = 2000
+	// MaxRenderedLength caps what a template renders; longer output fails.
+	MaxRenderedLength = 4000
+	// MaxTemplateNodes caps the actions, text and branches of a template.
+	MaxTemplateNodes = 200
+)
+
+// templateRenderTimeout bounds how long a template may run. Rendering is
+// abandoned past it, and the template stops at its next write.
+var templateRenderTimeout = 100 * time.Millisecond
+
+// alertTemplateData is what title and body templates see. It only exposes
+// plain copies of the rule, device and heartbeat so templates cannot reach
+// anything else, such as device tokens.
+type alertTemplateData struct {
+	Rule           alertTemplateRule
+	Device         alertTemplateDevice
+	Heartbeat      alertTemplateHeartbeat
+	Conditions     []dto.ConditionResult
+	TriggeredValue float64
+	Time           time.Time
+}
+
+type alertTemplateRule struct {
+	Name        string
+	Description string
+	Severity    string
+}
+
+type alertTemplateDevice struct {
+	ID              string
+	Name            string
+	SN              string
+	Location        string
+	Labels          map[string]string
+	FirmwareVersion string
+}
+
+type alertTemplateHeartbeat struct {
+	CPU          float64
+	RAM          float64
+	DiskFree     float64
+	Temperature  float64
+	Latency      int
+	Connectivity int
+	BootTime     time.Time
+}
+
+// alertTemplateFuncs are the only functions templates can call besides the
+// text/template builtins.
+var alertTemplateFuncs = template.FuncMap{
+	"upper": strings.ToUpper,
+	"lower": strings.ToLower,
+	"trim":  strings.TrimSpace,
+	"round": func(places int, value float64) float64 {
+		if places < 0 || places > 6 {
+			return value
+		}
+		scale := math.Pow(10, float64(places))
+		return math.Round(value*scale) / scale
+	},
+}
+
+// parseAlertTemplate parses a title or body template. Templates cannot
+// define or include other templates, which keeps them from recursing, and
+// can only range over the condition results and the device labels, outside
+// of any other range, which keeps the work of a template linear in its size.
+func parseAlertTemplate(name, text string) (*template.Template, error) {
+	if len(text) > MaxTemplateLength {
+		return nil, fmt.Errorf("longer than %d characters", MaxTemplateLength)
+	}
+	tmpl, err := template.New(name).Funcs(alertTemplateFuncs).Option("missingkey=zero").Parse(text)
+	if err != nil {
+		return nil, err
+	}
+	if len(tmpl.Templates()) > 1 {
+		return nil, fmt.Errorf("define and block are not allowed")
+	}
+	if tmpl.Tree != nil {
+		var checker templateChecker
+		if err := checker.check(tmpl.Tree.Root); err != nil {
+			return nil, err
+		}
+	}
+	return tmpl, nil
+}
+
+// templateChecker walks a parsed template, counting its nodes and tracking
+// whether it is inside a range.
+type templateChecker struct {
+	nodes   int
+	inRange bool
+}
+
+func (c *templateChecker) check(node parse.Node) error {
+	if node == nil {
+		return nil
+	}
+	c.nodes++
+	if c.nodes > MaxTemplateNodes {
+		return fmt.Errorf("more than %d actions", MaxTemplateNodes)
+	}
+
+	switch n := node.(type) {
+	case *parse.TemplateNode:
+		return fmt.Errorf("template actions are not allowed")
+	case *parse.ListNode:
+		if n == nil {
+			return nil
+		}
+		for _, child := range n.Nodes {
+			if err := c.check(child); err != nil {
+				return err
+			}
+		}
+	case *parse.IfNode:
+		return c.checkBranch(&n.BranchNode)
+	case *parse.WithNode:
+		return c.checkBranch(&n.BranchNode)
+	case *parse.RangeNode:
+		if c.inRange {
+			return fmt.Errorf("range cannot be nested in another range")
+		}
+		if !rangesOverCollection(n.Pipe) {
+			return fmt.Errorf("range is only allowed over .Conditions and .Device.Labels")
+		}
+		c.inRange = true
+		defer func() { c.inRange = false }()
+		return c.checkBranch(&n.BranchNode)
+	}
+	return nil
+}
+
+func (c *templateChecker) checkBranch(branch *parse.BranchNode) error {
+	if err := c.check(branch.List); err != nil {
+		return err
+	}
+	if branch.ElseList == nil {
+		return nil
+	}
+	return c.check(branch.ElseList)
+}
+
+func rangesOverCollection(pipe *parse.PipeNode) bool {
+	if pipe == nil || len(pipe.Cmds) != 1 || len(pipe.Cmds[0].Args) != 1 {
+		return false
+	}
+	field, ok := pipe.Cmds[0].Args[0].(*parse.FieldNode)
+	if !ok {
+		return false
+	}
+	path := strings.Join(field.Ident, ".")
+	return path == "Conditions" || path == "Device.Labels"
+}
+
+// renderAlertTemplate parses and executes a template, failing when its
+// output exceeds MaxRenderedLength or it runs longer than
+// templateRenderTimeout.
+func renderAlertTemplate(name, text string, data *alertTemplateData) (string, error) {
+	tmpl, err := parseAlertTemplate(name, text)
+	if err != nil {
+		return "", err
+	}
+
+	out := &limitedBuilder{deadline: time.Now().Add(templateRenderTimeout)}
+	done := make(chan error, 1)
+	go func() {
+		done <- tmpl.Execute(out, data)
+	}()
+
+	timer := time.NewTimer(templateRenderTimeout)
+	defer timer.Stop()
+	select {
+	case err := <-done:
+		if err != nil {
+			return "", err
+		}
+		return strings.TrimSpace(out.String()), nil
+	case <-timer.C:
+		return "", fmt.Errorf("takes longer than %s to render", templateRenderTimeout)
+	}
+}
+
+// renderAlertTemplates renders the title and body templates of a rule. An
+// empty template renders to an empty string, which channels replace with
+// their default text.
+func renderAlertTemplates(titleTemplate, bodyTemplate string, data *alertTemplateData) (*dto.RenderedMessage, error) {
+	rendered := &dto.RenderedMessage{}
+	var err error
+	if titleTemplate != "" {
+		if rendered.Subject, err = renderAlertTemplate("title_template", titleTemplate, data); err != nil {
+			return nil, fmt.Errorf("title_template: %w", err)
+		}
+		// Titles are single line.
+		rendered.Subject = strings.Join(strings.Fields(rendered.Subject), " ")
+	}
+	if bodyTemplate != "" {
+		if rendered.Text, err = renderAlertTemplate("body_template", bodyTemplate, data); err != nil {
+			return nil, fmt.Errorf("body_template: %w", err)
+		}
+	}
+	return rendered, nil
+}
+
+func newAlertTemplateData(notification models.Notification, device *models.Device, heartbeat *models.Heartbeat, conditions []dto.ConditionResult, triggeredValue float64, now time.Time) *alertTemplateData {
+	labels := make(map[string]string, len(device.Labels))
+	for key, value := range device.Labels {
+		labels[key] = value
+	}
+	return &alertTemplateData{
+		Rule: alertTemplateRule{
+			Name:        notification.Name,
+			Description: notification.Description,
+			Severity:    notification.Severity,
+		},
+		Device: alertTemplateDevice{
+			ID:              device.UUID.String(),
+			Name:            device.Name,
+			SN:              device.SN,
+			Location:        device.Location,
+			Labels:          labels,
+			FirmwareVersion: device.FirmwareVersion,
+		},
+		Heartbeat: alertTemplateHeartbeat{
+			CPU:          heartbeat.CPU,
+			RAM:          heartbeat.RAM,
+			DiskFree:     heartbeat.DiskFree,
+			Temperature:  heartbeat.Temperature,
+			Latency:      heartbeat.Latency,
+			Connectivity: heartbeat.Connectivity,
+			BootTime:     heartbeat.BootTime,
+		},
+		Conditions:     conditions,
+		TriggeredValue: triggeredValue,
+		Time:           now,
+	}
+}
+
+// limitedBuilder is a strings.Builder that fails once more than
+// MaxRenderedLength bytes are written or past its deadline.
+type limitedBuilder struct {
+	strings.Builder
+	deadline time.Time
+}
+
+func (b *limitedBuilder) Write(p []byte) (int, error) {
+	if time.Now().After(b.deadline) {
+		return 0, fmt.Errorf("render deadline exceeded")
+	}
+	if b.Len()+len(p) > MaxRenderedLength {
+		return 0, fmt.Errorf("renders more than %d characters", MaxRenderedLength)
+	}
+	return b.Builder.Write(p)
+}
+
+// sampleTemplateDevice and sampleTemplateHeartbeat stand in for a real
+// device and heartbeat when templates are validated or previewed.
+func sampleTemplateDevice() *models.Device {
+	return &models.Device{
+		Name:            "Sample device",
+		SN:              "000000000000",
+		Location:        "Sample location",
+		Labels:          models.Labels{"env": "prod"},
+		FirmwareVersion: "1.0.0",
+	}
+}
+
+func sampleTemplateHeartbeat() *models.Heartbeat {
+	return &models.Heartbeat{
+		CPU:          92.5,
+		RAM:          71.3,
+		DiskFree:     18.2,
+		Temperature:  68.4,
+		Latency:      45,
+		Connectivity: 1,
+		BootTime:     time.Now().Add(-72 * time.Hour).UTC(),
+	}
+}
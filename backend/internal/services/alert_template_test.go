@@ -0,0 +1,101 @@
+package services
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/dto"
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRenderAlertTemplates(t *testing.T) {
+	rule := models.Notification{Name: "High CPU", Severity: models.SeverityCritical}
+	conditions := []dto.ConditionResult{{Parameter: "cpu", Operator: ">", Threshold: 80, Actual: 92.5, Matched: true}}
+	data := newAlertTemplateData(rule, sampleTemplateDevice(), sampleTemplateHeartbeat(), conditions, 92.5, time.Date(2025, 10, 1, 12, 0, 0, 0, time.UTC))
+
+	t.Run("Success - Title and body rendered", func(t *testing.T) {
+		rendered, err := renderAlertTemplates(
+			"{{upper .Rule.Severity}}: {{.Rule.Name}}\n  on {{.Device.Name}}",
+			`{{range .Conditions}}{{.Parameter}}={{.Actual}}{{end}} env={{index .Device.Labels "env"}} at {{.Time.Format "15:04"}}`,
+			data,
+		)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "CRITICAL: High CPU on Sample device", rendered.Subject)
+		assert.Equal(t, "cpu=92.5 env=prod at 12:00", rendered.Text)
+	})
+
+	t.Run("Success - Empty template left empty", func(t *testing.T) {
+		rendered, err := renderAlertTemplates("{{.Rule.Name}}", "", data)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "High CPU", rendered.Subject)
+		assert.Empty(t, rendered.Text)
+	})
+
+	t.Run("Error - Unknown field", func(t *testing.T) {
+		_, err := renderAlertTemplates("{{.Device.Token}}", "", data)
+
+		assert.Error(t, err)
+		assert.True(t, strings.HasPrefix(err.Error(), "title_template:"))
+	})
+
+	t.Run("Error - Template definitions and calls", func(t *testing.T) {
+		_, err := renderAlertTemplates("", `{{define "loop"}}{{template "loop"}}{{end}}{{template "loop"}}`, data)
+		assert.Error(t, err)
+
+		_, err = renderAlertTemplates("", `{{template "body_template"}}`, data)
+		assert.Error(t, err)
+	})
+
+	t.Run("Error - Range over anything but conditions and labels", func(t *testing.T) {
+		_, err := renderAlertTemplates("", "{{range 1000000000}}x{{end}}", data)
+		assert.Error(t, err)
+
+		_, err = renderAlertTemplates("", "{{range .Heartbeat.Latency}}x{{end}}", data)
+		assert.Error(t, err)
+
+		_, err = renderAlertTemplates("", "{{if .Conditions}}{{range $k, $v := .Device.Labels}}{{$k}}{{end}}{{end}}", data)
+		assert.NoError(t, err)
+	})
+
+	t.Run("Error - Nested ranges", func(t *testing.T) {
+		_, err := renderAlertTemplates("", "{{with $}}{{range .Conditions}}{{with $}}{{range .Conditions}}{{end}}{{end}}{{end}}{{end}}", data)
+		assert.Error(t, err)
+
+		_, err = renderAlertTemplates("", "{{range .Conditions}}{{end}}{{range .Device.Labels}}{{end}}", data)
+		assert.NoError(t, err)
+	})
+
+	t.Run("Error - Too many actions", func(t *testing.T) {
+		_, err := renderAlertTemplates("", strings.Repeat("{{.Rule.Name}}", MaxTemplateNodes), data)
+
+		assert.Error(t, err)
+	})
+
+	t.Run("Error - Render deadline exceeded", func(t *testing.T) {
+		timeout := templateRenderTimeout
+		templateRenderTimeout = 0
+		defer func() { templateRenderTimeout = timeout }()
+
+		_, err := renderAlertTemplates("{{.Rule.Name}}", "", data)
+
+		assert.Error(t, err)
+	})
+
+	t.Run("Error - Template too long", func(t *testing.T) {
+		_, err := renderAlertTemplates(strings.Repeat("x", MaxTemplateLength+1), "", data)
+
+		assert.Error(t, err)
+	})
+
+	t.Run("Error - Output too long", func(t *testing.T) {
+		body := "{{range .Conditions}}" + strings.Repeat("x", MaxTemplateLength-50) + "{{end}}"
+		many := make([]dto.ConditionResult, 3)
+		_, err := renderAlertTemplates("", body, newAlertTemplateData(rule, sampleTemplateDevice(), sampleTemplateHeartbeat(), many, 0, time.Now()))
+
+		assert.Error(t, err)
+	})
+}
@@ -46,6 +46,11 @@ func (m *MockJWTService) GenerateToken(userID uuid.UUID) (string, error) {
 	return args.String(0), args.Error(1)
 }
 
+func (m *MockJWTService) GenerateOrganizationToken(userID, organizationID uuid.UUID, role string) (string, error) {
+	args := m.Called(userID, organizationID, role)
+	return args.String(0), args.Error(1)
+}
+
 func (m *MockJWTService) ValidateToken(tokenString string) (*Claims, error) {
 	args := m.Called(tokenString)
 	if args.Get(0) == nil {
@@ -0,0 +1,117 @@
+package services
+
+import (
+	"time"
+
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/models"
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/utils/errors"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type membershipRepository interface {
+	FindMembership(organizationID, userID uuid.UUID) (*models.Membership, error)
+}
+
+type shareRepository interface {
+	FindActive(deviceID, granteeID uuid.UUID, now time.Time) (*models.DeviceShare, error)
+}
+
+// Ownership identifies who a resource belongs to: an organization when
+// OrganizationID is set, otherwise the user UserID. DeviceID is set for
+// devices so that share grants can be honoured.
+type Ownership struct {
+	UserID         uuid.UUID
+	OrganizationID *uuid.UUID
+	DeviceID       *uuid.UUID
+}
+
+// SameOwner reports whether two resources live in the same ownership scope.
+func (o Ownership) SameOwner(other Ownership) bool {
+	if o.OrganizationID != nil || other.OrganizationID != nil {
+		return o.OrganizationID != nil && other.OrganizationID != nil && *o.OrganizationID == *other.OrganizationID
+	}
+	return o.UserID == other.UserID
+}
+
+// Authorizer is the single place where access to devices, groups and rules is
+// decided. Personal resources are only reachable by their owner; organization
+// resources by members holding at least the required role. A device can also
+// be reached through an unexpired share grant whose permission covers the
+// required role.
+type Authorizer interface {
+	Authorize(userID uuid.UUID, resource Ownership, requiredRole string) error
+}
+
+type authorizer struct {
+	membershipRepo membershipRepository
+	shareRepo      shareRepository
+}
+
+func NewAuthorizer(membershipRepo membershipRepository, shareRepo shareRepository) Authorizer {
+	return &authorizer{
+		membershipRepo: membershipRepo,
+		shareRepo:      shareRepo,
+	}
+}
+
+func (a *authorizer) Authorize(userID uuid.UUID, resource Ownership, requiredRole string) error {
+	err := a.authorizeOwner(userID, resource, requiredRole)
+	if err != errors.ErrForbidden || resource.DeviceID == nil {
+		return err
+	}
+
+	share, shareErr := a.shareRepo.FindActive(*resource.DeviceID, userID, time.Now())
+	if shareErr != nil {
+		if shareErr == gorm.ErrRecordNotFound {
+			return errors.ErrForbidden
+		}
+		return errors.ErrDatabaseError
+	}
+	if !models.RoleAtLeast(share.Permission, requiredRole) {
+		return errors.ErrForbidden
+	}
+	return nil
+}
+
+func (a *authorizer) authorizeOwner(userID uuid.UUID, resource Ownership, requiredRole string) error {
+	if resource.OrganizationID == nil {
+		if resource.UserID != userID {
+			return errors.ErrForbidden
+		}
+		return nil
+	}
+
+	membership, err := a.membershipRepo.FindMembership(*resource.OrganizationID, userID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return errors.ErrForbidden
+		}
+		return errors.ErrDatabaseError
+	}
+
+	if !models.RoleAtLeast(membership.Role, requiredRole) {
+		return errors.ErrForbidden
+	}
+	return nil
+}
+
+func deviceOwnership(device *models.Device) Ownership {
+	return Ownership{UserID: device.UserID, OrganizationID: device.OrganizationID, DeviceID: &device.UUID}
+}
+
+func groupOwnership(group *models.DeviceGroup) Ownership {
+	return Ownership{UserID: group.UserID, OrganizationID: group.OrganizationID}
+}
+
+func channelOwnership(channel *models.NotificationChannel) Ownership {
+	return Ownership{UserID: channel.UserID, OrganizationID: channel.OrganizationID}
+}
+
+func policyOwnership(policy *models.EscalationPolicy) Ownership {
+	return Ownership{UserID: policy.UserID, OrganizationID: policy.OrganizationID}
+}
+
+func alertOwnership(alert *models.Alert) Ownership {
+	return Ownership{UserID: alert.UserID, OrganizationID: alert.OrganizationID}
+}
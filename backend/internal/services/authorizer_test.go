@@ -0,0 +1,143 @@
+package services
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/models"
+	custom_errors "github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/utils/errors"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+func TestAuthorizer_Authorize(t *testing.T) {
+	userID := uuid.New()
+	orgID := uuid.New()
+	orgResource := Ownership{UserID: uuid.New(), OrganizationID: &orgID}
+
+	t.Run("Success - Personal resource of the caller", func(t *testing.T) {
+		repo := new(MockOrganizationRepository)
+		authz := NewAuthorizer(repo, noDeviceShares())
+
+		err := authz.Authorize(userID, Ownership{UserID: userID}, models.RoleOwner)
+
+		assert.NoError(t, err)
+		repo.AssertNotCalled(t, "FindMembership")
+	})
+
+	t.Run("Error - Personal resource of another user", func(t *testing.T) {
+		authz := NewAuthorizer(new(MockOrganizationRepository), noDeviceShares())
+
+		err := authz.Authorize(userID, Ownership{UserID: uuid.New()}, models.RoleViewer)
+
+		assert.Equal(t, custom_errors.ErrForbidden, err)
+	})
+
+	t.Run("Success - Role above the required one", func(t *testing.T) {
+		repo := new(MockOrganizationRepository)
+		repo.On("FindMembership", orgID, userID).Return(&models.Membership{Role: models.RoleAdmin}, nil)
+		authz := NewAuthorizer(repo, noDeviceShares())
+
+		err := authz.Authorize(userID, orgResource, models.RoleOperator)
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("Error - Role below the required one", func(t *testing.T) {
+		repo := new(MockOrganizationRepository)
+		repo.On("FindMembership", orgID, userID).Return(&models.Membership{Role: models.RoleViewer}, nil)
+		authz := NewAuthorizer(repo, noDeviceShares())
+
+		err := authz.Authorize(userID, orgResource, models.RoleOperator)
+
+		assert.Equal(t, custom_errors.ErrForbidden, err)
+	})
+
+	t.Run("Error - Not a member", func(t *testing.T) {
+		repo := new(MockOrganizationRepository)
+		repo.On("FindMembership", orgID, userID).Return(nil, gorm.ErrRecordNotFound)
+		authz := NewAuthorizer(repo, noDeviceShares())
+
+		err := authz.Authorize(userID, orgResource, models.RoleViewer)
+
+		assert.Equal(t, custom_errors.ErrForbidden, err)
+	})
+
+	t.Run("Error - Database error", func(t *testing.T) {
+		repo := new(MockOrganizationRepository)
+		repo.On("FindMembership", orgID, userID).Return(nil, errors.New("db down"))
+		authz := NewAuthorizer(repo, noDeviceShares())
+
+		err := authz.Authorize(userID, orgResource, models.RoleViewer)
+
+		assert.Equal(t, custom_errors.ErrDatabaseError, err)
+	})
+}
+
+func TestOwnership_SameOwner(t *testing.T) {
+	userID := uuid.New()
+	orgID := uuid.New()
+	otherOrgID := uuid.New()
+
+	assert.True(t, Ownership{UserID: userID}.SameOwner(Ownership{UserID: userID}))
+	assert.False(t, Ownership{UserID: userID}.SameOwner(Ownership{UserID: uuid.New()}))
+	assert.True(t, Ownership{UserID: userID, OrganizationID: &orgID}.SameOwner(Ownership{UserID: uuid.New(), OrganizationID: &orgID}))
+	assert.False(t, Ownership{UserID: userID, OrganizationID: &orgID}.SameOwner(Ownership{UserID: userID}))
+	assert.False(t, Ownership{UserID: userID, OrganizationID: &orgID}.SameOwner(Ownership{UserID: userID, OrganizationID: &otherOrgID}))
+}
+
+func TestAuthorizer_AuthorizeSharedDevice(t *testing.T) {
+	userID := uuid.New()
+	deviceID := uuid.New()
+	device := Ownership{UserID: uuid.New(), DeviceID: &deviceID}
+
+	t.Run("Success - Viewer grant allows reads", func(t *testing.T) {
+		shareRepo := new(MockDeviceShareRepository)
+		shareRepo.On("FindActive", deviceID, userID, mock.Anything).Return(&models.DeviceShare{Permission: models.RoleViewer}, nil)
+		authz := NewAuthorizer(new(MockOrganizationRepository), shareRepo)
+
+		err := authz.Authorize(userID, device, models.RoleViewer)
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("Error - Viewer grant denies writes", func(t *testing.T) {
+		shareRepo := new(MockDeviceShareRepository)
+		shareRepo.On("FindActive", deviceID, userID, mock.Anything).Return(&models.DeviceShare{Permission: models.RoleViewer}, nil)
+		authz := NewAuthorizer(new(MockOrganizationRepository), shareRepo)
+
+		err := authz.Authorize(userID, device, models.RoleOperator)
+
+		assert.Equal(t, custom_errors.ErrForbidden, err)
+	})
+
+	t.Run("Error - Operator grant never allows admin actions", func(t *testing.T) {
+		shareRepo := new(MockDeviceShareRepository)
+		shareRepo.On("FindActive", deviceID, userID, mock.Anything).Return(&models.DeviceShare{Permission: models.RoleOperator}, nil)
+		authz := NewAuthorizer(new(MockOrganizationRepository), shareRepo)
+
+		err := authz.Authorize(userID, device, models.RoleAdmin)
+
+		assert.Equal(t, custom_errors.ErrForbidden, err)
+	})
+
+	t.Run("Error - No active grant", func(t *testing.T) {
+		authz := NewAuthorizer(new(MockOrganizationRepository), noDeviceShares())
+
+		err := authz.Authorize(userID, device, models.RoleViewer)
+
+		assert.Equal(t, custom_errors.ErrForbidden, err)
+	})
+
+	t.Run("Success - Owner does not need a grant", func(t *testing.T) {
+		shareRepo := new(MockDeviceShareRepository)
+		authz := NewAuthorizer(new(MockOrganizationRepository), shareRepo)
+
+		err := authz.Authorize(device.UserID, device, models.RoleAdmin)
+
+		assert.NoError(t, err)
+		shareRepo.AssertNotCalled(t, "FindActive", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
@@ -0,0 +1,58 @@
+package services
+
+import (
+	"time"
+
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/utils/logger"
+)
+
+// DeviceCommandJob periodically expires the commands past their timeout and
+// retries publishing the pending ones.
+type DeviceCommandJob struct {
+	commandService DeviceCommandService
+	interval       time.Duration
+	shutdown       chan struct{}
+}
+
+func NewDeviceCommandJob(commandService DeviceCommandService, interval time.Duration) *DeviceCommandJob {
+	return &DeviceCommandJob{
+		commandService: commandService,
+		interval:       interval,
+		shutdown:       make(chan struct{}),
+	}
+}
+
+// Run processes commands once immediately and then every interval until
+// Stop is called.
+func (j *DeviceCommandJob) Run() {
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	for {
+		j.process()
+		select {
+		case <-ticker.C:
+		case <-j.shutdown:
+			return
+		}
+	}
+}
+
+func (j *DeviceCommandJob) Stop() {
+	select {
+	case <-j.shutdown:
+	default:
+		close(j.shutdown)
+	}
+}
+
+func (j *DeviceCommandJob) process() {
+	expired, sent, err := j.commandService.ProcessCommands()
+	if err != nil {
+		logger.Logger.Error("Error processing device commands", "error", err)
+		return
+	}
+	if expired > 0 || sent > 0 {
+		logger.Logger.Info("Processed device commands", "expired", expired, "sent", sent)
+	}
+}
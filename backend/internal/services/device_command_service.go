@@ -0,0 +1,352 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/dto"
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/models"
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/repository"
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/utils/errors"
+	logger "github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/utils/logger"
+	"github.com/google/uuid"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+const (
+	DefaultCommandTimeout = 5 * time.Minute
+	MinCommandTimeout     = 10 * time.Second
+	MaxCommandTimeout     = 24 * time.Hour
+)
+
+// DeviceCommandPublisher delivers a command to its device.
+type DeviceCommandPublisher interface {
+	PublishCommand(msg dto.DeviceCommandMessage) error
+}
+
+type DeviceCommandService interface {
+	IssueCommand(userID, deviceID uuid.UUID, req dto.DeviceCommandRequest) (*dto.DeviceCommandResponse, error)
+	DispatchCommand(issuedBy, deviceID uuid.UUID, name string, params map[string]interface{}, timeout time.Duration) (*models.DeviceCommand, error)
+	ListCommands(userID, deviceID uuid.UUID, status string) ([]dto.DeviceCommandResponse, error)
+	GetCommand(userID, deviceID, commandID uuid.UUID) (*dto.DeviceCommandResponse, error)
+	CancelCommand(userID, deviceID, commandID uuid.UUID) (*dto.DeviceCommandResponse, error)
+	HandleReply(reply dto.DeviceCommandReply) error
+	ProcessCommands() (expired int64, sent int, err error)
+}
+
+type deviceCommandService struct {
+	commandRepo repository.DeviceCommandRepository
+	deviceRepo  repository.DeviceRepository
+	publisher   DeviceCommandPublisher
+	authz       Authorizer
+}
+
+func NewDeviceCommandService(commandRepo repository.DeviceCommandRepository, deviceRepo repository.DeviceRepository, publisher DeviceCommandPublisher, authz Authorizer) DeviceCommandService {
+	return &deviceCommandService{
+		commandRepo: commandRepo,
+		deviceRepo:  deviceRepo,
+		publisher:   publisher,
+		authz:       authz,
+	}
+}
+
+// IssueCommand stores the command and publishes it to the device. A command
+// that cannot be published right away stays pending and is retried by
+// ProcessCommands until it expires. Requires the operator role.
+func (s *deviceCommandService) IssueCommand(userID, deviceID uuid.UUID, req dto.DeviceCommandRequest) (*dto.DeviceCommandResponse, error) {
+	if err := s.authorizeDevice(userID, deviceID, models.RoleOperator); err != nil {
+		return nil, err
+	}
+
+	if !models.ValidCommandName(req.Name) {
+		return nil, errors.NewValidationError("Command must be reboot, upload_logs or run_diagnostics")
+	}
+	timeout := DefaultCommandTimeout
+	if req.TimeoutSeconds != 0 {
+		timeout = time.Duration(req.TimeoutSeconds) * time.Second
+		if timeout < MinCommandTimeout || timeout > MaxCommandTimeout {
+			return nil, errors.NewValidationError(fmt.Sprintf("timeout_seconds must be between %d and %d", int(MinCommandTimeout.Seconds()), int(MaxCommandTimeout.Seconds())))
+		}
+	}
+
+	command, err := s.DispatchCommand(userID, deviceID, req.Name, req.Params, timeout)
+	if err != nil {
+		return nil, err
+	}
+	return deviceCommandResponse(command), nil
+}
+
+// DispatchCommand stores a command and publishes it to the device without
+// checking access or the command name. It is meant for callers that already
+// authorized the work, such as firmware rollouts.
+func (s *deviceCommandService) DispatchCommand(issuedBy, deviceID uuid.UUID, name string, params map[string]interface{}, timeout time.Duration) (*models.DeviceCommand, error) {
+	if params == nil {
+		params = map[string]interface{}{}
+	}
+	encoded, err := json.Marshal(params)
+	if err != nil {
+		return nil, errors.NewValidationError("Params must be a JSON object")
+	}
+
+	now := time.Now()
+	command := &models.DeviceCommand{
+		ID:        uuid.New(),
+		DeviceID:  deviceID,
+		IssuedBy:  issuedBy,
+		Name:      name,
+		Params:    datatypes.JSON(encoded),
+		Status:    models.CommandPending,
+		ExpiresAt: now.Add(timeout),
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := s.commandRepo.Create(command); err != nil {
+		return nil, errors.ErrDatabaseError
+	}
+
+	if err := s.send(command); err != nil {
+		logger.Logger.Warn("Failed to send device command, will retry", "command_id", command.ID, "device_id", deviceID, "error", err)
+	}
+	return command, nil
+}
+
+func (s *deviceCommandService) ListCommands(userID, deviceID uuid.UUID, status string) ([]dto.DeviceCommandResponse, error) {
+	if err := s.authorizeDevice(userID, deviceID, models.RoleViewer); err != nil {
+		return nil, err
+	}
+
+	commands, err := s.commandRepo.FindByDeviceID(deviceID, status)
+	if err != nil {
+		return nil, errors.ErrDatabaseError
+	}
+
+	responses := make([]dto.DeviceCommandResponse, 0, len(commands))
+	for i := range commands {
+		responses = append(responses, *deviceCommandResponse(&commands[i]))
+	}
+	return responses, nil
+}
+
+func (s *deviceCommandService) GetCommand(userID, deviceID, commandID uuid.UUID) (*dto.DeviceCommandResponse, error) {
+	if err := s.authorizeDevice(userID, deviceID, models.RoleViewer); err != nil {
+		return nil, err
+	}
+
+	command, err := s.findCommand(deviceID, commandID)
+	if err != nil {
+		return nil, err
+	}
+	return deviceCommandResponse(command), nil
+}
+
+// CancelCommand cancels a command the device has not acknowledged yet. A
+// device that already received it ignores its replies from then on.
+func (s *deviceCommandService) CancelCommand(userID, deviceID, commandID uuid.UUID) (*dto.DeviceCommandResponse, error) {
+	if err := s.authorizeDevice(userID, deviceID, models.RoleOperator); err != nil {
+		return nil, err
+	}
+
+	command, err := s.findCommand(deviceID, commandID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	err = s.commandRepo.Transition(command.ID, []string{models.CommandPending, models.CommandSent}, map[string]interface{}{
+		"status":       models.CommandCancelled,
+		"completed_at": now,
+		"updated_at":   now,
+	})
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.ErrDeviceCommandFinished
+		}
+		return nil, errors.ErrDatabaseError
+	}
+
+	command.Status = models.CommandCancelled
+	command.CompletedAt = &now
+	command.UpdatedAt = now
+	return deviceCommandResponse(command), nil
+}
+
+// HandleReply records a reply a device published for one of its commands.
+// Replies without the token of the device are rejected with
+// ErrInvalidDeviceToken; replies for commands that were cancelled, timed out
+// or already finished are rejected with ErrDeviceCommandFinished.
+func (s *deviceCommandService) HandleReply(reply dto.DeviceCommandReply) error {
+	commandID, err := uuid.Parse(reply.CommandID)
+	if err != nil {
+		return errors.NewValidationError("Invalid command ID")
+	}
+	deviceID, err := uuid.Parse(reply.DeviceID)
+	if err != nil {
+		return errors.NewValidationError("Invalid device ID")
+	}
+	device, err := s.deviceRepo.FindByID(deviceID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return errors.ErrDeviceNotFound
+		}
+		return errors.ErrDatabaseError
+	}
+	if !device.CheckToken(reply.Token) {
+		return errors.ErrInvalidDeviceToken
+	}
+
+	command, err := s.findCommand(deviceID, commandID)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	if !now.Before(command.ExpiresAt) {
+		return errors.ErrDeviceCommandFinished
+	}
+	updates := map[string]interface{}{
+		"status":     reply.Status,
+		"updated_at": now,
+	}
+	from := []string{models.CommandPending, models.CommandSent, models.CommandAcknowledged}
+	switch reply.Status {
+	case models.CommandAcknowledged:
+		from = []string{models.CommandPending, models.CommandSent}
+		updates["acknowledged_at"] = now
+	case models.CommandSucceeded, models.CommandFailed:
+		if reply.Result != nil {
+			encoded, err := json.Marshal(reply.Result)
+			if err != nil {
+				return errors.NewValidationError("Result must be a JSON object")
+			}
+			updates["result"] = datatypes.JSON(encoded)
+		}
+		if command.AcknowledgedAt == nil {
+			updates["acknowledged_at"] = now
+		}
+		updates["error"] = reply.Error
+		updates["completed_at"] = now
+	default:
+		return errors.NewValidationError("Reply status must be acknowledged, succeeded or failed")
+	}
+
+	if err := s.commandRepo.Transition(command.ID, from, updates); err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return errors.ErrDeviceCommandFinished
+		}
+		return errors.ErrDatabaseError
+	}
+	return nil
+}
+
+// ProcessCommands expires the commands past their timeout and publishes the
+// pending ones again.
+func (s *deviceCommandService) ProcessCommands() (int64, int, error) {
+	now := time.Now()
+	expired, err := s.commandRepo.ExpireOverdue(now)
+	if err != nil {
+		return 0, 0, errors.ErrDatabaseError
+	}
+
+	commands, err := s.commandRepo.FindUnsent(now)
+	if err != nil {
+		return expired, 0, errors.ErrDatabaseError
+	}
+
+	sent := 0
+	for i := range commands {
+		if err := s.send(&commands[i]); err != nil {
+			logger.Logger.Warn("Failed to send device command", "command_id", commands[i].ID, "device_id", commands[i].DeviceID, "error", err)
+			continue
+		}
+		sent++
+	}
+	return expired, sent, nil
+}
+
+// send publishes a pending command and marks it sent.
+func (s *deviceCommandService) send(command *models.DeviceCommand) error {
+	params := make(map[string]interface{})
+	_ = json.Unmarshal(command.Params, &params)
+
+	err := s.publisher.PublishCommand(dto.DeviceCommandMessage{
+		ID:        command.ID.String(),
+		DeviceID:  command.DeviceID.String(),
+		Name:      command.Name,
+		Params:    params,
+		ExpiresAt: command.ExpiresAt.UTC(),
+	})
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	err = s.commandRepo.Transition(command.ID, []string{models.CommandPending}, map[string]interface{}{
+		"status":     models.CommandSent,
+		"sent_at":    now,
+		"updated_at": now,
+	})
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return err
+	}
+	// The device may have replied before the command was marked sent; its
+	// status then already moved on.
+	if err == nil {
+		command.Status = models.CommandSent
+		command.SentAt = &now
+		command.UpdatedAt = now
+	}
+	return nil
+}
+
+func (s *deviceCommandService) authorizeDevice(userID, deviceID uuid.UUID, role string) error {
+	device, err := s.deviceRepo.FindByID(deviceID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return errors.ErrDeviceNotFound
+		}
+		return errors.ErrDatabaseError
+	}
+	return s.authz.Authorize(userID, deviceOwnership(device), role)
+}
+
+func (s *deviceCommandService) findCommand(deviceID, commandID uuid.UUID) (*models.DeviceCommand, error) {
+	command, err := s.commandRepo.FindByID(commandID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.ErrDeviceCommandNotFound
+		}
+		return nil, errors.ErrDatabaseError
+	}
+	if command.DeviceID != deviceID {
+		return nil, errors.ErrDeviceCommandNotFound
+	}
+	return command, nil
+}
+
+func deviceCommandResponse(command *models.DeviceCommand) *dto.DeviceCommandResponse {
+	params := make(map[string]interface{})
+	_ = json.Unmarshal(command.Params, &params)
+
+	var result map[string]interface{}
+	if len(command.Result) > 0 {
+		_ = json.Unmarshal(command.Result, &result)
+	}
+
+	return &dto.DeviceCommandResponse{
+		ID:             command.ID,
+		DeviceID:       command.DeviceID,
+		IssuedBy:       command.IssuedBy,
+		Name:           command.Name,
+		Params:         params,
+		Status:         command.Status,
+		Result:         result,
+		Error:          command.Error,
+		ExpiresAt:      command.ExpiresAt,
+		SentAt:         command.SentAt,
+		AcknowledgedAt: command.AcknowledgedAt,
+		CompletedAt:    command.CompletedAt,
+		CreatedAt:      command.CreatedAt,
+	}
+}
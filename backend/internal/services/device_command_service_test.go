@@ -0,0 +1,326 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/dto"
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/models"
+	custom_errors "github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/utils/errors"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+type MockDeviceCommandRepository struct {
+	mock.Mock
+}
+
+func (m *MockDeviceCommandRepository) Create(command *models.DeviceCommand) error {
+	args := m.Called(command)
+	return args.Error(0)
+}
+
+func (m *MockDeviceCommandRepository) FindByID(id uuid.UUID) (*models.DeviceCommand, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.DeviceCommand), args.Error(1)
+}
+
+func (m *MockDeviceCommandRepository) FindByDeviceID(deviceID uuid.UUID, status string) ([]models.DeviceCommand, error) {
+	args := m.Called(deviceID, status)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.DeviceCommand), args.Error(1)
+}
+
+func (m *MockDeviceCommandRepository) FindUnsent(now time.Time) ([]models.DeviceCommand, error) {
+	args := m.Called(now)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.DeviceCommand), args.Error(1)
+}
+
+func (m *MockDeviceCommandRepository) Transition(id uuid.UUID, from []string, updates map[string]interface{}) error {
+	args := m.Called(id, from, updates)
+	return args.Error(0)
+}
+
+func (m *MockDeviceCommandRepository) ExpireOverdue(now time.Time) (int64, error) {
+	args := m.Called(now)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+type MockDeviceCommandPublisher struct {
+	mock.Mock
+}
+
+func (m *MockDeviceCommandPublisher) PublishCommand(msg dto.DeviceCommandMessage) error {
+	args := m.Called(msg)
+	return args.Error(0)
+}
+
+func newTestDeviceCommandService() (DeviceCommandService, *MockDeviceCommandRepository, *MockDeviceRepository, *MockDeviceCommandPublisher) {
+	commandRepo := new(MockDeviceCommandRepository)
+	deviceRepo := new(MockDeviceRepository)
+	publisher := new(MockDeviceCommandPublisher)
+	authz := NewAuthorizer(new(MockOrganizationRepository), noDeviceShares())
+	return NewDeviceCommandService(commandRepo, deviceRepo, publisher, authz), commandRepo, deviceRepo, publisher
+}
+
+func toStatus(status string) interface{} {
+	return mock.MatchedBy(func(updates map[string]interface{}) bool {
+		return updates["status"] == status
+	})
+}
+
+func TestDeviceCommandService_IssueCommand(t *testing.T) {
+	userID := uuid.New()
+	deviceID := uuid.New()
+	device := &models.Device{UUID: deviceID, UserID: userID}
+
+	t.Run("Success - Published and marked sent", func(t *testing.T) {
+		service, commandRepo, deviceRepo, publisher := newTestDeviceCommandService()
+		deviceRepo.On("FindByID", deviceID).Return(device, nil)
+		commandRepo.On("Create", mock.MatchedBy(func(c *models.DeviceCommand) bool {
+			return c.Name == models.CommandReboot && c.Status == models.CommandPending && time.Until(c.ExpiresAt) > 4*time.Minute
+		})).Return(nil)
+		publisher.On("PublishCommand", mock.MatchedBy(func(msg dto.DeviceCommandMessage) bool {
+			return msg.DeviceID == deviceID.String() && msg.Name == models.CommandReboot
+		})).Return(nil)
+		commandRepo.On("Transition", mock.Anything, []string{models.CommandPending}, toStatus(models.CommandSent)).Return(nil)
+
+		command, err := service.IssueCommand(userID, deviceID, dto.DeviceCommandRequest{Name: models.CommandReboot})
+
+		assert.NoError(t, err)
+		assert.Equal(t, models.CommandSent, command.Status)
+		assert.NotNil(t, command.SentAt)
+		publisher.AssertExpectations(t)
+	})
+
+	t.Run("Success - Stays pending when publishing fails", func(t *testing.T) {
+		service, commandRepo, deviceRepo, publisher := newTestDeviceCommandService()
+		deviceRepo.On("FindByID", deviceID).Return(device, nil)
+		commandRepo.On("Create", mock.Anything).Return(nil)
+		publisher.On("PublishCommand", mock.Anything).Return(errors.New("channel closed"))
+
+		command, err := service.IssueCommand(userID, deviceID, dto.DeviceCommandRequest{Name: models.CommandUploadLogs, TimeoutSeconds: 60})
+
+		assert.NoError(t, err)
+		assert.Equal(t, models.CommandPending, command.Status)
+		commandRepo.AssertNotCalled(t, "Transition", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Error - Unknown command", func(t *testing.T) {
+		service, commandRepo, deviceRepo, _ := newTestDeviceCommandService()
+		deviceRepo.On("FindByID", deviceID).Return(device, nil)
+
+		command, err := service.IssueCommand(userID, deviceID, dto.DeviceCommandRequest{Name: "format_disk"})
+
+		assert.Nil(t, command)
+		assert.ErrorContains(t, err, "reboot")
+		commandRepo.AssertNotCalled(t, "Create", mock.Anything)
+	})
+
+	t.Run("Error - Timeout out of range", func(t *testing.T) {
+		service, commandRepo, deviceRepo, _ := newTestDeviceCommandService()
+		deviceRepo.On("FindByID", deviceID).Return(device, nil)
+
+		command, err := service.IssueCommand(userID, deviceID, dto.DeviceCommandRequest{Name: models.CommandReboot, TimeoutSeconds: 1})
+
+		assert.Nil(t, command)
+		assert.ErrorContains(t, err, "timeout_seconds")
+		commandRepo.AssertNotCalled(t, "Create", mock.Anything)
+	})
+
+	t.Run("Error - Viewer cannot issue commands", func(t *testing.T) {
+		commandRepo := new(MockDeviceCommandRepository)
+		deviceRepo := new(MockDeviceRepository)
+		shareRepo := new(MockDeviceShareRepository)
+		viewerID := uuid.New()
+		shareRepo.On("FindActive", deviceID, viewerID, mock.Anything).Return(&models.DeviceShare{Permission: models.RoleViewer}, nil)
+		service := NewDeviceCommandService(commandRepo, deviceRepo, new(MockDeviceCommandPublisher), NewAuthorizer(new(MockOrganizationRepository), shareRepo))
+		deviceRepo.On("FindByID", deviceID).Return(device, nil)
+
+		command, err := service.IssueCommand(viewerID, deviceID, dto.DeviceCommandRequest{Name: models.CommandReboot})
+
+		assert.Nil(t, command)
+		assert.Equal(t, custom_errors.ErrForbidden, err)
+	})
+}
+
+func TestDeviceCommandService_CancelCommand(t *testing.T) {
+	userID := uuid.New()
+	deviceID := uuid.New()
+	device := &models.Device{UUID: deviceID, UserID: userID}
+	commandID := uuid.New()
+
+	t.Run("Success - Cancel sent command", func(t *testing.T) {
+		service, commandRepo, deviceRepo, _ := newTestDeviceCommandService()
+		deviceRepo.On("FindByID", deviceID).Return(device, nil)
+		commandRepo.On("FindByID", commandID).Return(&models.DeviceCommand{ID: commandID, DeviceID: deviceID, Status: models.CommandSent}, nil)
+		commandRepo.On("Transition", commandID, []string{models.CommandPending, models.CommandSent}, toStatus(models.CommandCancelled)).Return(nil)
+
+		command, err := service.CancelCommand(userID, deviceID, commandID)
+
+		assert.NoError(t, err)
+		assert.Equal(t, models.CommandCancelled, command.Status)
+	})
+
+	t.Run("Error - Already acknowledged", func(t *testing.T) {
+		service, commandRepo, deviceRepo, _ := newTestDeviceCommandService()
+		deviceRepo.On("FindByID", deviceID).Return(device, nil)
+		commandRepo.On("FindByID", commandID).Return(&models.DeviceCommand{ID: commandID, DeviceID: deviceID, Status: models.CommandAcknowledged}, nil)
+		commandRepo.On("Transition", commandID, mock.Anything, mock.Anything).Return(gorm.ErrRecordNotFound)
+
+		command, err := service.CancelCommand(userID, deviceID, commandID)
+
+		assert.Nil(t, command)
+		assert.Equal(t, custom_errors.ErrDeviceCommandFinished, err)
+	})
+
+	t.Run("Error - Command of another device", func(t *testing.T) {
+		service, commandRepo, deviceRepo, _ := newTestDeviceCommandService()
+		deviceRepo.On("FindByID", deviceID).Return(device, nil)
+		commandRepo.On("FindByID", commandID).Return(&models.DeviceCommand{ID: commandID, DeviceID: uuid.New(), Status: models.CommandSent}, nil)
+
+		command, err := service.CancelCommand(userID, deviceID, commandID)
+
+		assert.Nil(t, command)
+		assert.Equal(t, custom_errors.ErrDeviceCommandNotFound, err)
+	})
+}
+
+func TestDeviceCommandService_HandleReply(t *testing.T) {
+	deviceID := uuid.New()
+	commandID := uuid.New()
+	token := "dt_secret"
+	device := &models.Device{UUID: deviceID, TokenHash: models.HashSecret(token)}
+	sent := func() *models.DeviceCommand {
+		return &models.DeviceCommand{ID: commandID, DeviceID: deviceID, Status: models.CommandSent, ExpiresAt: time.Now().Add(time.Minute)}
+	}
+
+	t.Run("Success - Acknowledged", func(t *testing.T) {
+		service, commandRepo, deviceRepo, _ := newTestDeviceCommandService()
+		deviceRepo.On("FindByID", mock.Anything).Return(device, nil)
+		commandRepo.On("FindByID", commandID).Return(sent(), nil)
+		commandRepo.On("Transition", commandID, []string{models.CommandPending, models.CommandSent}, toStatus(models.CommandAcknowledged)).Return(nil)
+
+		err := service.HandleReply(dto.DeviceCommandReply{CommandID: commandID.String(), DeviceID: deviceID.String(), Token: token, Status: models.CommandAcknowledged})
+
+		assert.NoError(t, err)
+		commandRepo.AssertExpectations(t)
+	})
+
+	t.Run("Success - Succeeded with result", func(t *testing.T) {
+		service, commandRepo, deviceRepo, _ := newTestDeviceCommandService()
+		deviceRepo.On("FindByID", mock.Anything).Return(device, nil)
+		commandRepo.On("FindByID", commandID).Return(sent(), nil)
+		commandRepo.On("Transition", commandID, []string{models.CommandPending, models.CommandSent, models.CommandAcknowledged}, mock.MatchedBy(func(updates map[string]interface{}) bool {
+			return updates["status"] == models.CommandSucceeded && string(updates["result"].(datatypes.JSON)) == `{"uptime":12}` && updates["completed_at"] != nil
+		})).Return(nil)
+
+		err := service.HandleReply(dto.DeviceCommandReply{
+			CommandID: commandID.String(),
+			DeviceID:  deviceID.String(),
+			Token:     token,
+			Status:    models.CommandSucceeded,
+			Result:    map[string]interface{}{"uptime": float64(12)},
+		})
+
+		assert.NoError(t, err)
+		commandRepo.AssertExpectations(t)
+	})
+
+	t.Run("Success - Device without a token", func(t *testing.T) {
+		service, commandRepo, deviceRepo, _ := newTestDeviceCommandService()
+		deviceRepo.On("FindByID", deviceID).Return(&models.Device{UUID: deviceID}, nil)
+		commandRepo.On("FindByID", commandID).Return(sent(), nil)
+		commandRepo.On("Transition", commandID, mock.Anything, toStatus(models.CommandAcknowledged)).Return(nil)
+
+		err := service.HandleReply(dto.DeviceCommandReply{CommandID: commandID.String(), DeviceID: deviceID.String(), Status: models.CommandAcknowledged})
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("Error - Invalid device token", func(t *testing.T) {
+		service, commandRepo, deviceRepo, _ := newTestDeviceCommandService()
+		deviceRepo.On("FindByID", deviceID).Return(device, nil)
+
+		err := service.HandleReply(dto.DeviceCommandReply{CommandID: commandID.String(), DeviceID: deviceID.String(), Token: "dt_forged", Status: models.CommandSucceeded})
+
+		assert.Equal(t, custom_errors.ErrInvalidDeviceToken, err)
+		commandRepo.AssertNotCalled(t, "FindByID", mock.Anything)
+	})
+
+	t.Run("Error - Reply after timeout", func(t *testing.T) {
+		service, commandRepo, deviceRepo, _ := newTestDeviceCommandService()
+		deviceRepo.On("FindByID", mock.Anything).Return(device, nil)
+		command := sent()
+		command.ExpiresAt = time.Now().Add(-time.Second)
+		commandRepo.On("FindByID", commandID).Return(command, nil)
+
+		err := service.HandleReply(dto.DeviceCommandReply{CommandID: commandID.String(), DeviceID: deviceID.String(), Token: token, Status: models.CommandSucceeded})
+
+		assert.Equal(t, custom_errors.ErrDeviceCommandFinished, err)
+		commandRepo.AssertNotCalled(t, "Transition", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Error - Reply from another device", func(t *testing.T) {
+		service, commandRepo, deviceRepo, _ := newTestDeviceCommandService()
+		deviceRepo.On("FindByID", mock.Anything).Return(device, nil)
+		commandRepo.On("FindByID", commandID).Return(sent(), nil)
+
+		err := service.HandleReply(dto.DeviceCommandReply{CommandID: commandID.String(), DeviceID: uuid.New().String(), Token: token, Status: models.CommandSucceeded})
+
+		assert.Equal(t, custom_errors.ErrDeviceCommandNotFound, err)
+	})
+
+	t.Run("Error - Unknown status", func(t *testing.T) {
+		service, commandRepo, deviceRepo, _ := newTestDeviceCommandService()
+		deviceRepo.On("FindByID", mock.Anything).Return(device, nil)
+		commandRepo.On("FindByID", commandID).Return(sent(), nil)
+
+		err := service.HandleReply(dto.DeviceCommandReply{CommandID: commandID.String(), DeviceID: deviceID.String(), Token: token, Status: models.CommandCancelled})
+
+		assert.ErrorContains(t, err, "Reply status")
+	})
+}
+
+func TestDeviceCommandService_ProcessCommands(t *testing.T) {
+	t.Run("Success - Expires overdue and resends pending", func(t *testing.T) {
+		service, commandRepo, _, publisher := newTestDeviceCommandService()
+		pending := []models.DeviceCommand{
+			{ID: uuid.New(), DeviceID: uuid.New(), Name: models.CommandReboot, Status: models.CommandPending, ExpiresAt: time.Now().Add(time.Minute)},
+			{ID: uuid.New(), DeviceID: uuid.New(), Name: models.CommandReboot, Status: models.CommandPending, ExpiresAt: time.Now().Add(time.Minute)},
+		}
+		commandRepo.On("ExpireOverdue", mock.Anything).Return(int64(3), nil)
+		commandRepo.On("FindUnsent", mock.Anything).Return(pending, nil)
+		publisher.On("PublishCommand", mock.MatchedBy(func(msg dto.DeviceCommandMessage) bool { return msg.ID == pending[0].ID.String() })).Return(nil)
+		publisher.On("PublishCommand", mock.MatchedBy(func(msg dto.DeviceCommandMessage) bool { return msg.ID == pending[1].ID.String() })).Return(errors.New("channel closed"))
+		commandRepo.On("Transition", pending[0].ID, []string{models.CommandPending}, toStatus(models.CommandSent)).Return(nil)
+
+		expired, sent, err := service.ProcessCommands()
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(3), expired)
+		assert.Equal(t, 1, sent)
+		commandRepo.AssertExpectations(t)
+	})
+
+	t.Run("Error - Database failure", func(t *testing.T) {
+		service, commandRepo, _, _ := newTestDeviceCommandService()
+		commandRepo.On("ExpireOverdue", mock.Anything).Return(int64(0), errors.New("db down"))
+
+		_, _, err := service.ProcessCommands()
+
+		assert.Equal(t, custom_errors.ErrDatabaseError, err)
+	})
+}
@@ -0,0 +1,342 @@
+package services
+
+import (
+	"math"
+	"strings"
+	"time"
+
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/dto"
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/models"
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/repository"
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/utils/errors"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type DeviceGroupService interface {
+	CreateGroup(userID uuid.UUID, orgID *uuid.UUID, req dto.DeviceGroupRequest) (*models.DeviceGroup, error)
+	GetGroup(userID, groupID uuid.UUID) (*models.DeviceGroup, error)
+	ListGroups(userID uuid.UUID) ([]models.DeviceGroup, error)
+	UpdateGroup(userID, groupID uuid.UUID, req dto.DeviceGroupRequest) (*models.DeviceGroup, error)
+	DeleteGroup(userID, groupID uuid.UUID) error
+	AddDevices(userID, groupID uuid.UUID, deviceIDs []uuid.UUID) error
+	RemoveDevice(userID, groupID, deviceID uuid.UUID) error
+	GetGroupSummary(userID, groupID uuid.UUID) (*dto.DeviceGroupSummaryResponse, error)
+}
+
+type deviceGroupService struct {
+	groupRepo         repository.DeviceGroupRepository
+	deviceRepo        repository.DeviceRepository
+	heartbeatRepo     repository.HeartbeatRepository
+	shadowRepo        repository.DeviceShadowRepository
+	heartbeatInterval time.Duration
+	authz             Authorizer
+}
+
+// NewDeviceGroupService builds the device group service. heartbeatInterval is
+// the expected heartbeat period of devices whose shadow does not set
+// heartbeat_interval_seconds; a device silent for more than two of its
+// intervals is reported as offline.
+func NewDeviceGroupService(groupRepo repository.DeviceGroupRepository, deviceRepo repository.DeviceRepository, heartbeatRepo repository.HeartbeatRepository, shadowRepo repository.DeviceShadowRepository, heartbeatInterval time.Duration, authz Authorizer) DeviceGroupService {
+	return &deviceGroupService{
+		groupRepo:         groupRepo,
+		deviceRepo:        deviceRepo,
+		heartbeatRepo:     heartbeatRepo,
+		shadowRepo:        shadowRepo,
+		heartbeatInterval: heartbeatInterval,
+		authz:             authz,
+	}
+}
+
+// CreateGroup creates a personal group, or an organization group when orgID
+// is set; the latter requires the operator role in that organization.
+func (s *deviceGroupService) CreateGroup(userID uuid.UUID, orgID *uuid.UUID, req dto.DeviceGroupRequest) (*models.DeviceGroup, error) {
+	owner := Ownership{UserID: userID, OrganizationID: orgID}
+	if err := s.authz.Authorize(userID, owner, models.RoleOperator); err != nil {
+		return nil, err
+	}
+
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return nil, errors.NewValidationError("Group name is required")
+	}
+	if req.ParentID != nil {
+		if err := s.checkParent(owner, *req.ParentID); err != nil {
+			return nil, err
+		}
+	}
+
+	group := &models.DeviceGroup{
+		ID:             uuid.New(),
+		UserID:         userID,
+		OrganizationID: orgID,
+		ParentID:       req.ParentID,
+		Name:           name,
+		Description:    req.Description,
+		CreatedAt:      time.Now(),
+		UpdatedAt:      time.Now(),
+	}
+
+	if err := s.groupRepo.Create(group); err != nil {
+		return nil, errors.ErrDatabaseError
+	}
+	return group, nil
+}
+
+func (s *deviceGroupService) GetGroup(userID, groupID uuid.UUID) (*models.DeviceGroup, error) {
+	return s.findGroup(userID, groupID, models.RoleViewer)
+}
+
+// findGroup loads a group and checks that userID holds at least role on it.
+func (s *deviceGroupService) findGroup(userID, groupID uuid.UUID, role string) (*models.DeviceGroup, error) {
+	group, err := s.groupRepo.FindByID(groupID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.ErrDeviceGroupNotFound
+		}
+		return nil, errors.ErrDatabaseError
+	}
+
+	if err := s.authz.Authorize(userID, groupOwnership(group), role); err != nil {
+		return nil, err
+	}
+	return group, nil
+}
+
+func (s *deviceGroupService) ListGroups(userID uuid.UUID) ([]models.DeviceGroup, error) {
+	groups, err := s.groupRepo.FindByUserID(userID)
+	if err != nil {
+		return nil, errors.ErrDatabaseError
+	}
+	return groups, nil
+}
+
+func (s *deviceGroupService) UpdateGroup(userID, groupID uuid.UUID, req dto.DeviceGroupRequest) (*models.DeviceGroup, error) {
+	group, err := s.findGroup(userID, groupID, models.RoleOperator)
+	if err != nil {
+		return nil, err
+	}
+
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return nil, errors.NewValidationError("Group name is required")
+	}
+
+	if req.ParentID != nil {
+		if err := s.checkParent(groupOwnership(group), *req.ParentID); err != nil {
+			return nil, err
+		}
+
+		descendants, err := s.groupRepo.FindDescendantIDs(groupID)
+		if err != nil {
+			return nil, errors.ErrDatabaseError
+		}
+		for _, id := range descendants {
+			if id == *req.ParentID {
+				return nil, errors.NewValidationError("A group cannot be moved under itself or one of its subgroups")
+			}
+		}
+	}
+
+	group.Name = name
+	group.Description = req.Description
+	group.ParentID = req.ParentID
+	group.UpdatedAt = time.Now()
+
+	if err := s.groupRepo.Update(group); err != nil {
+		return nil, errors.ErrDatabaseError
+	}
+	return group, nil
+}
+
+// DeleteGroup removes an empty branch of the tree. Groups with subgroups must
+// be emptied first; member devices are simply detached.
+func (s *deviceGroupService) DeleteGroup(userID, groupID uuid.UUID) error {
+	if _, err := s.findGroup(userID, groupID, models.RoleOperator); err != nil {
+		return err
+	}
+
+	children, err := s.groupRepo.CountChildren(groupID)
+	if err != nil {
+		return errors.ErrDatabaseError
+	}
+	if children > 0 {
+		return errors.ErrDeviceGroupHasChildren
+	}
+
+	if err := s.groupRepo.Delete(groupID); err != nil {
+		return errors.ErrDatabaseError
+	}
+	return nil
+}
+
+// AddDevices moves the devices into the group. A device belongs to at most
+// one group, so devices already in another group are moved.
+func (s *deviceGroupService) AddDevices(userID, groupID uuid.UUID, deviceIDs []uuid.UUID) error {
+	group, err := s.findGroup(userID, groupID, models.RoleOperator)
+	if err != nil {
+		return err
+	}
+	if len(deviceIDs) == 0 {
+		return errors.NewValidationError("At least one device ID is required")
+	}
+
+	for _, deviceID := range deviceIDs {
+		device, err := s.deviceRepo.FindByID(deviceID)
+		if err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return errors.ErrDeviceNotFound
+			}
+			return errors.ErrDatabaseError
+		}
+		if !deviceOwnership(device).SameOwner(groupOwnership(group)) {
+			return errors.ErrForbidden
+		}
+	}
+
+	if err := s.deviceRepo.SetGroup(deviceIDs, &groupID); err != nil {
+		return errors.ErrDatabaseError
+	}
+	return nil
+}
+
+func (s *deviceGroupService) RemoveDevice(userID, groupID, deviceID uuid.UUID) error {
+	group, err := s.findGroup(userID, groupID, models.RoleOperator)
+	if err != nil {
+		return err
+	}
+
+	device, err := s.deviceRepo.FindByID(deviceID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return errors.ErrDeviceNotFound
+		}
+		return errors.ErrDatabaseError
+	}
+	if !deviceOwnership(device).SameOwner(groupOwnership(group)) {
+		return errors.ErrForbidden
+	}
+	if device.GroupID == nil || *device.GroupID != groupID {
+		return errors.ErrDeviceNotInGroup
+	}
+
+	if err := s.deviceRepo.SetGroup([]uuid.UUID{deviceID}, nil); err != nil {
+		return errors.ErrDatabaseError
+	}
+	return nil
+}
+
+// GetGroupSummary aggregates the devices of the group and all its subgroups:
+// status counts from each device's last heartbeat time and interval, and the latest metrics
+// of every member rolled up into average, min and max.
+func (s *deviceGroupService) GetGroupSummary(userID, groupID uuid.UUID) (*dto.DeviceGroupSummaryResponse, error) {
+	group, err := s.GetGroup(userID, groupID)
+	if err != nil {
+		return nil, err
+	}
+
+	groupIDs, err := s.groupRepo.FindDescendantIDs(groupID)
+	if err != nil {
+		return nil, errors.ErrDatabaseError
+	}
+
+	devices, err := s.deviceRepo.FindByGroupIDs(userID, groupIDs)
+	if err != nil {
+		return nil, errors.ErrDatabaseError
+	}
+
+	deviceIDs := make([]uuid.UUID, 0, len(devices))
+	for _, device := range devices {
+		deviceIDs = append(deviceIDs, device.UUID)
+	}
+	intervals, err := heartbeatIntervals(s.shadowRepo, deviceIDs, s.heartbeatInterval)
+	if err != nil {
+		return nil, errors.ErrDatabaseError
+	}
+
+	status := dto.DeviceGroupStatusCounts{Total: len(devices)}
+	now := time.Now()
+	for _, device := range devices {
+		switch {
+		case device.LastSeenAt == nil:
+			status.NeverSeen++
+		case now.Sub(*device.LastSeenAt) <= 2*intervals[device.UUID]:
+			status.Online++
+		default:
+			status.Offline++
+		}
+	}
+
+	latest, err := s.heartbeatRepo.FindLatestByDeviceIDs(deviceIDs)
+	if err != nil {
+		return nil, errors.ErrDatabaseError
+	}
+
+	subgroups := len(groupIDs) - 1
+	if subgroups < 0 {
+		subgroups = 0
+	}
+
+	return &dto.DeviceGroupSummaryResponse{
+		Group: dto.DeviceGroupResponse{
+			ID:             group.ID,
+			UserID:         group.UserID,
+			OrganizationID: group.OrganizationID,
+			ParentID:       group.ParentID,
+			Name:           group.Name,
+			Description:    group.Description,
+			CreatedAt:      group.CreatedAt,
+			UpdatedAt:      group.UpdatedAt,
+		},
+		SubgroupCount: subgroups,
+		Status:        status,
+		LatestMetrics: rollupMetrics(latest),
+	}, nil
+}
+
+// checkParent verifies that a parent group exists and lives in the same
+// ownership scope as the child.
+func (s *deviceGroupService) checkParent(owner Ownership, parentID uuid.UUID) error {
+	parent, err := s.groupRepo.FindByID(parentID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return errors.NewValidationError("Parent group not found")
+		}
+		return errors.ErrDatabaseError
+	}
+	if !groupOwnership(parent).SameOwner(owner) {
+		return errors.ErrForbidden
+	}
+	return nil
+}
+
+// rollupMetrics aggregates one heartbeat per device. It returns nil when no
+// member device has reported yet.
+func rollupMetrics(heartbeats []models.Heartbeat) *dto.DeviceGroupMetrics {
+	if len(heartbeats) == 0 {
+		return nil
+	}
+
+	values := func(get func(models.Heartbeat) float64) dto.MetricRollup {
+		rollup := dto.MetricRollup{Min: math.Inf(1), Max: math.Inf(-1)}
+		sum := 0.0
+		for _, hb := range heartbeats {
+			v := get(hb)
+			sum += v
+			rollup.Min = math.Min(rollup.Min, v)
+			rollup.Max = math.Max(rollup.Max, v)
+		}
+		rollup.Average = math.Round(sum/float64(len(heartbeats))*100) / 100
+		return rollup
+	}
+
+	return &dto.DeviceGroupMetrics{
+		Devices:      len(heartbeats),
+		CPU:          values(func(hb models.Heartbeat) float64 { return hb.CPU }),
+		RAM:          values(func(hb models.Heartbeat) float64 { return hb.RAM }),
+		DiskFree:     values(func(hb models.Heartbeat) float64 { return hb.DiskFree }),
+		Temperature:  values(func(hb models.Heartbeat) float64 { return hb.Temperature }),
+		Latency:      values(func(hb models.Heartbeat) float64 { return float64(hb.Latency) }),
+		Connectivity: values(func(hb models.Heartbeat) float64 { return float64(hb.Connectivity) }),
+	}
+}
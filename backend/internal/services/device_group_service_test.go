@@ -0,0 +1,380 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/dto"
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/models"
+	custom_errors "github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/utils/errors"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+type MockDeviceGroupRepository struct {
+	mock.Mock
+}
+
+func (m *MockDeviceGroupRepository) Create(group *models.DeviceGroup) error {
+	args := m.Called(group)
+	return args.Error(0)
+}
+
+func (m *MockDeviceGroupRepository) FindByID(id uuid.UUID) (*models.DeviceGroup, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.DeviceGroup), args.Error(1)
+}
+
+func (m *MockDeviceGroupRepository) FindByUserID(userID uuid.UUID) ([]models.DeviceGroup, error) {
+	args := m.Called(userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.DeviceGroup), args.Error(1)
+}
+
+func (m *MockDeviceGroupRepository) Update(group *models.DeviceGroup) error {
+	args := m.Called(group)
+	return args.Error(0)
+}
+
+func (m *MockDeviceGroupRepository) Delete(id uuid.UUID) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MockDeviceGroupRepository) CountChildren(id uuid.UUID) (int64, error) {
+	args := m.Called(id)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockDeviceGroupRepository) FindDescendantIDs(id uuid.UUID) ([]uuid.UUID, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]uuid.UUID), args.Error(1)
+}
+
+func newDeviceGroupServiceWithMocks() (DeviceGroupService, *MockDeviceGroupRepository, *MockDeviceRepository, *MockHeartbeatRepository) {
+	groupRepo := new(MockDeviceGroupRepository)
+	deviceRepo := new(MockDeviceRepository)
+	heartbeatRepo := new(MockHeartbeatRepository)
+	return NewDeviceGroupService(groupRepo, deviceRepo, heartbeatRepo, noShadows(), time.Minute, NewAuthorizer(new(MockOrganizationRepository), noDeviceShares())), groupRepo, deviceRepo, heartbeatRepo
+}
+
+func TestDeviceGroupService_CreateGroup(t *testing.T) {
+	userID := uuid.New()
+	parentID := uuid.New()
+
+	t.Run("Success - Root group", func(t *testing.T) {
+		service, groupRepo, _, _ := newDeviceGroupServiceWithMocks()
+
+		groupRepo.On("Create", mock.AnythingOfType("*models.DeviceGroup")).Return(nil)
+
+		group, err := service.CreateGroup(userID, nil, dto.DeviceGroupRequest{Name: " Region South "})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "Region South", group.Name)
+		assert.Nil(t, group.ParentID)
+		assert.Equal(t, userID, group.UserID)
+		groupRepo.AssertExpectations(t)
+	})
+
+	t.Run("Success - Nested group", func(t *testing.T) {
+		service, groupRepo, _, _ := newDeviceGroupServiceWithMocks()
+
+		groupRepo.On("FindByID", parentID).Return(&models.DeviceGroup{ID: parentID, UserID: userID}, nil)
+		groupRepo.On("Create", mock.AnythingOfType("*models.DeviceGroup")).Return(nil)
+
+		group, err := service.CreateGroup(userID, nil, dto.DeviceGroupRequest{Name: "Site SP", ParentID: &parentID})
+
+		assert.NoError(t, err)
+		assert.Equal(t, parentID, *group.ParentID)
+		groupRepo.AssertExpectations(t)
+	})
+
+	t.Run("Error - Empty name", func(t *testing.T) {
+		service, groupRepo, _, _ := newDeviceGroupServiceWithMocks()
+
+		group, err := service.CreateGroup(userID, nil, dto.DeviceGroupRequest{Name: "  "})
+
+		assert.Equal(t, custom_errors.NewValidationError("Group name is required"), err)
+		assert.Nil(t, group)
+		groupRepo.AssertNotCalled(t, "Create", mock.Anything)
+	})
+
+	t.Run("Error - Parent not found", func(t *testing.T) {
+		service, groupRepo, _, _ := newDeviceGroupServiceWithMocks()
+
+		groupRepo.On("FindByID", parentID).Return(nil, gorm.ErrRecordNotFound)
+
+		group, err := service.CreateGroup(userID, nil, dto.DeviceGroupRequest{Name: "Site SP", ParentID: &parentID})
+
+		assert.Equal(t, custom_errors.NewValidationError("Parent group not found"), err)
+		assert.Nil(t, group)
+	})
+
+	t.Run("Error - Parent owned by another user", func(t *testing.T) {
+		service, groupRepo, _, _ := newDeviceGroupServiceWithMocks()
+
+		groupRepo.On("FindByID", parentID).Return(&models.DeviceGroup{ID: parentID, UserID: uuid.New()}, nil)
+
+		group, err := service.CreateGroup(userID, nil, dto.DeviceGroupRequest{Name: "Site SP", ParentID: &parentID})
+
+		assert.Equal(t, custom_errors.ErrForbidden, err)
+		assert.Nil(t, group)
+	})
+}
+
+func TestDeviceGroupService_UpdateGroup(t *testing.T) {
+	userID := uuid.New()
+	groupID := uuid.New()
+	childID := uuid.New()
+	otherID := uuid.New()
+
+	t.Run("Success - Move under another group", func(t *testing.T) {
+		service, groupRepo, _, _ := newDeviceGroupServiceWithMocks()
+
+		groupRepo.On("FindByID", groupID).Return(&models.DeviceGroup{ID: groupID, UserID: userID, Name: "Rack"}, nil)
+		groupRepo.On("FindByID", otherID).Return(&models.DeviceGroup{ID: otherID, UserID: userID}, nil)
+		groupRepo.On("FindDescendantIDs", groupID).Return([]uuid.UUID{groupID, childID}, nil)
+		groupRepo.On("Update", mock.AnythingOfType("*models.DeviceGroup")).Return(nil)
+
+		group, err := service.UpdateGroup(userID, groupID, dto.DeviceGroupRequest{Name: "Rack 2", ParentID: &otherID})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "Rack 2", group.Name)
+		assert.Equal(t, otherID, *group.ParentID)
+		groupRepo.AssertExpectations(t)
+	})
+
+	t.Run("Error - Move under own subgroup", func(t *testing.T) {
+		service, groupRepo, _, _ := newDeviceGroupServiceWithMocks()
+
+		groupRepo.On("FindByID", groupID).Return(&models.DeviceGroup{ID: groupID, UserID: userID, Name: "Rack"}, nil)
+		groupRepo.On("FindByID", childID).Return(&models.DeviceGroup{ID: childID, UserID: userID}, nil)
+		groupRepo.On("FindDescendantIDs", groupID).Return([]uuid.UUID{groupID, childID}, nil)
+
+		group, err := service.UpdateGroup(userID, groupID, dto.DeviceGroupRequest{Name: "Rack", ParentID: &childID})
+
+		assert.Error(t, err)
+		assert.IsType(t, &custom_errors.BusinessError{}, err)
+		assert.Nil(t, group)
+		groupRepo.AssertNotCalled(t, "Update", mock.Anything)
+	})
+
+	t.Run("Error - Group not found", func(t *testing.T) {
+		service, groupRepo, _, _ := newDeviceGroupServiceWithMocks()
+
+		groupRepo.On("FindByID", groupID).Return(nil, gorm.ErrRecordNotFound)
+
+		group, err := service.UpdateGroup(userID, groupID, dto.DeviceGroupRequest{Name: "Rack"})
+
+		assert.Equal(t, custom_errors.ErrDeviceGroupNotFound, err)
+		assert.Nil(t, group)
+	})
+}
+
+func TestDeviceGroupService_DeleteGroup(t *testing.T) {
+	userID := uuid.New()
+	groupID := uuid.New()
+	group := &models.DeviceGroup{ID: groupID, UserID: userID}
+
+	t.Run("Success - Delete leaf group", func(t *testing.T) {
+		service, groupRepo, _, _ := newDeviceGroupServiceWithMocks()
+
+		groupRepo.On("FindByID", groupID).Return(group, nil)
+		groupRepo.On("CountChildren", groupID).Return(int64(0), nil)
+		groupRepo.On("Delete", groupID).Return(nil)
+
+		err := service.DeleteGroup(userID, groupID)
+
+		assert.NoError(t, err)
+		groupRepo.AssertExpectations(t)
+	})
+
+	t.Run("Error - Group has subgroups", func(t *testing.T) {
+		service, groupRepo, _, _ := newDeviceGroupServiceWithMocks()
+
+		groupRepo.On("FindByID", groupID).Return(group, nil)
+		groupRepo.On("CountChildren", groupID).Return(int64(2), nil)
+
+		err := service.DeleteGroup(userID, groupID)
+
+		assert.Equal(t, custom_errors.ErrDeviceGroupHasChildren, err)
+		groupRepo.AssertNotCalled(t, "Delete", mock.Anything)
+	})
+
+	t.Run("Error - Forbidden (different user)", func(t *testing.T) {
+		service, groupRepo, _, _ := newDeviceGroupServiceWithMocks()
+
+		groupRepo.On("FindByID", groupID).Return(group, nil)
+
+		err := service.DeleteGroup(uuid.New(), groupID)
+
+		assert.Equal(t, custom_errors.ErrForbidden, err)
+	})
+}
+
+func TestDeviceGroupService_Membership(t *testing.T) {
+	userID := uuid.New()
+	groupID := uuid.New()
+	deviceID := uuid.New()
+	group := &models.DeviceGroup{ID: groupID, UserID: userID}
+
+	t.Run("Success - Add devices", func(t *testing.T) {
+		service, groupRepo, deviceRepo, _ := newDeviceGroupServiceWithMocks()
+
+		groupRepo.On("FindByID", groupID).Return(group, nil)
+		deviceRepo.On("FindByID", deviceID).Return(&models.Device{UUID: deviceID, UserID: userID}, nil)
+		deviceRepo.On("SetGroup", []uuid.UUID{deviceID}, &groupID).Return(nil)
+
+		err := service.AddDevices(userID, groupID, []uuid.UUID{deviceID})
+
+		assert.NoError(t, err)
+		deviceRepo.AssertExpectations(t)
+	})
+
+	t.Run("Error - Add device of another user", func(t *testing.T) {
+		service, groupRepo, deviceRepo, _ := newDeviceGroupServiceWithMocks()
+
+		groupRepo.On("FindByID", groupID).Return(group, nil)
+		deviceRepo.On("FindByID", deviceID).Return(&models.Device{UUID: deviceID, UserID: uuid.New()}, nil)
+
+		err := service.AddDevices(userID, groupID, []uuid.UUID{deviceID})
+
+		assert.Equal(t, custom_errors.ErrForbidden, err)
+		deviceRepo.AssertNotCalled(t, "SetGroup", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Error - No devices", func(t *testing.T) {
+		service, groupRepo, _, _ := newDeviceGroupServiceWithMocks()
+
+		groupRepo.On("FindByID", groupID).Return(group, nil)
+
+		err := service.AddDevices(userID, groupID, nil)
+
+		assert.Error(t, err)
+		assert.IsType(t, &custom_errors.BusinessError{}, err)
+	})
+
+	t.Run("Success - Remove device", func(t *testing.T) {
+		service, groupRepo, deviceRepo, _ := newDeviceGroupServiceWithMocks()
+
+		groupRepo.On("FindByID", groupID).Return(group, nil)
+		deviceRepo.On("FindByID", deviceID).Return(&models.Device{UUID: deviceID, UserID: userID, GroupID: &groupID}, nil)
+		deviceRepo.On("SetGroup", []uuid.UUID{deviceID}, (*uuid.UUID)(nil)).Return(nil)
+
+		err := service.RemoveDevice(userID, groupID, deviceID)
+
+		assert.NoError(t, err)
+		deviceRepo.AssertExpectations(t)
+	})
+
+	t.Run("Error - Remove device not in group", func(t *testing.T) {
+		service, groupRepo, deviceRepo, _ := newDeviceGroupServiceWithMocks()
+
+		groupRepo.On("FindByID", groupID).Return(group, nil)
+		deviceRepo.On("FindByID", deviceID).Return(&models.Device{UUID: deviceID, UserID: userID}, nil)
+
+		err := service.RemoveDevice(userID, groupID, deviceID)
+
+		assert.Equal(t, custom_errors.ErrDeviceNotInGroup, err)
+		deviceRepo.AssertNotCalled(t, "SetGroup", mock.Anything, mock.Anything)
+	})
+}
+
+func TestDeviceGroupService_GetGroupSummary(t *testing.T) {
+	userID := uuid.New()
+	groupID := uuid.New()
+	childID := uuid.New()
+	group := &models.DeviceGroup{ID: groupID, UserID: userID, Name: "Site SP"}
+
+	recent := time.Now().Add(-30 * time.Second)
+	stale := time.Now().Add(-time.Hour)
+	online := models.Device{UUID: uuid.New(), UserID: userID, GroupID: &groupID, LastSeenAt: &recent}
+	offline := models.Device{UUID: uuid.New(), UserID: userID, GroupID: &childID, LastSeenAt: &stale}
+	neverSeen := models.Device{UUID: uuid.New(), UserID: userID, GroupID: &childID}
+
+	t.Run("Success - Counts and rollup across subgroups", func(t *testing.T) {
+		service, groupRepo, deviceRepo, heartbeatRepo := newDeviceGroupServiceWithMocks()
+
+		groupRepo.On("FindByID", groupID).Return(group, nil)
+		groupRepo.On("FindDescendantIDs", groupID).Return([]uuid.UUID{groupID, childID}, nil)
+		deviceRepo.On("FindByGroupIDs", userID, []uuid.UUID{groupID, childID}).Return([]models.Device{online, offline, neverSeen}, nil)
+		heartbeatRepo.On("FindLatestByDeviceIDs", []uuid.UUID{online.UUID, offline.UUID, neverSeen.UUID}).Return([]models.Heartbeat{
+			{DeviceID: online.UUID, CPU: 20, RAM: 40, Temperature: 30, Latency: 100, Connectivity: 1},
+			{DeviceID: offline.UUID, CPU: 80, RAM: 60, Temperature: 50, Latency: 300, Connectivity: 0},
+		}, nil)
+
+		summary, err := service.GetGroupSummary(userID, groupID)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 1, summary.SubgroupCount)
+		assert.Equal(t, dto.DeviceGroupStatusCounts{Total: 3, Online: 1, Offline: 1, NeverSeen: 1}, summary.Status)
+		assert.NotNil(t, summary.LatestMetrics)
+		assert.Equal(t, 2, summary.LatestMetrics.Devices)
+		assert.Equal(t, dto.MetricRollup{Average: 50, Min: 20, Max: 80}, summary.LatestMetrics.CPU)
+		assert.Equal(t, dto.MetricRollup{Average: 200, Min: 100, Max: 300}, summary.LatestMetrics.Latency)
+		assert.Equal(t, 0.5, summary.LatestMetrics.Connectivity.Average)
+	})
+
+	t.Run("Success - Interval from the device shadow", func(t *testing.T) {
+		groupRepo := new(MockDeviceGroupRepository)
+		deviceRepo := new(MockDeviceRepository)
+		heartbeatRepo := new(MockHeartbeatRepository)
+		shadowRepo := new(MockDeviceShadowRepository)
+		service := NewDeviceGroupService(groupRepo, deviceRepo, heartbeatRepo, shadowRepo, time.Minute, NewAuthorizer(new(MockOrganizationRepository), noDeviceShares()))
+
+		lastSeen := time.Now().Add(-5 * time.Minute)
+		slow := models.Device{UUID: uuid.New(), UserID: userID, GroupID: &groupID, LastSeenAt: &lastSeen}
+		groupRepo.On("FindByID", groupID).Return(group, nil)
+		groupRepo.On("FindDescendantIDs", groupID).Return([]uuid.UUID{groupID}, nil)
+		deviceRepo.On("FindByGroupIDs", userID, []uuid.UUID{groupID}).Return([]models.Device{slow}, nil)
+		shadowRepo.On("FindByDeviceIDs", []uuid.UUID{slow.UUID}).Return([]models.DeviceShadow{{
+			DeviceID: slow.UUID,
+			Desired:  datatypes.JSON(`{"heartbeat_interval_seconds": 300}`),
+		}}, nil)
+		heartbeatRepo.On("FindLatestByDeviceIDs", []uuid.UUID{slow.UUID}).Return([]models.Heartbeat{}, nil)
+
+		summary, err := service.GetGroupSummary(userID, groupID)
+
+		assert.NoError(t, err)
+		assert.Equal(t, dto.DeviceGroupStatusCounts{Total: 1, Online: 1}, summary.Status)
+	})
+
+	t.Run("Success - Empty group has no metrics", func(t *testing.T) {
+		service, groupRepo, deviceRepo, heartbeatRepo := newDeviceGroupServiceWithMocks()
+
+		groupRepo.On("FindByID", groupID).Return(group, nil)
+		groupRepo.On("FindDescendantIDs", groupID).Return([]uuid.UUID{groupID}, nil)
+		deviceRepo.On("FindByGroupIDs", userID, []uuid.UUID{groupID}).Return([]models.Device{}, nil)
+		heartbeatRepo.On("FindLatestByDeviceIDs", []uuid.UUID{}).Return([]models.Heartbeat{}, nil)
+
+		summary, err := service.GetGroupSummary(userID, groupID)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 0, summary.Status.Total)
+		assert.Nil(t, summary.LatestMetrics)
+	})
+
+	t.Run("Error - Database error", func(t *testing.T) {
+		service, groupRepo, _, _ := newDeviceGroupServiceWithMocks()
+
+		groupRepo.On("FindByID", groupID).Return(group, nil)
+		groupRepo.On("FindDescendantIDs", groupID).Return(nil, errors.New("database error"))
+
+		summary, err := service.GetGroupSummary(userID, groupID)
+
+		assert.Equal(t, custom_errors.ErrDatabaseError, err)
+		assert.Nil(t, summary)
+	})
+}
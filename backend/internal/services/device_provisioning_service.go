@@ -0,0 +1,331 @@
+package services
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/dto"
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/models"
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/repository"
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/utils/errors"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	RegistrationUnassigned  = "unassigned"
+	RegistrationAssigned    = "assigned"
+	RegistrationProvisioned = "provisioned"
+)
+
+// claimCodeAlphabet leaves out characters that are easily confused when a
+// code is printed on a label (0/O, 1/I/L).
+const claimCodeAlphabet = "ABCDEFGHJKMNPQRSTUVWXYZ23456789"
+
+type DeviceProvisioningService interface {
+	RegisterDevices(userID uuid.UUID, orgID *uuid.UUID, req dto.DeviceRegistrationRequest) ([]dto.DeviceRegistrationResponse, error)
+	ListRegistrations(userID uuid.UUID) ([]dto.DeviceRegistrationResponse, error)
+	Provision(req dto.DeviceClaimRequest) (*dto.ProvisionResponse, error)
+	ClaimDevice(userID uuid.UUID, orgID *uuid.UUID, req dto.DeviceClaimRequest) (*models.Device, error)
+}
+
+type deviceProvisioningService struct {
+	registrationRepo repository.DeviceRegistrationRepository
+	deviceRepo       repository.DeviceRepository
+	authz            Authorizer
+}
+
+func NewDeviceProvisioningService(registrationRepo repository.DeviceRegistrationRepository, deviceRepo repository.DeviceRepository, authz Authorizer) DeviceProvisioningService {
+	return &deviceProvisioningService{
+		registrationRepo: registrationRepo,
+		deviceRepo:       deviceRepo,
+		authz:            authz,
+	}
+}
+
+// RegisterDevices pre-registers serial numbers and returns their one-time
+// claim codes, which are not stored in clear and cannot be retrieved again.
+// Registrations are assigned to the caller (or the active organization,
+// which requires the admin role) unless req.Unassigned is set, in which case
+// users claim them by serial number.
+func (s *deviceProvisioningService) RegisterDevices(userID uuid.UUID, orgID *uuid.UUID, req dto.DeviceRegistrationRequest) ([]dto.DeviceRegistrationResponse, error) {
+	if err := s.authz.Authorize(userID, Ownership{UserID: userID, OrganizationID: orgID}, models.RoleAdmin); err != nil {
+		return nil, err
+	}
+	if len(req.Devices) == 0 {
+		return nil, errors.NewValidationError("At least one device is required")
+	}
+	if len(req.Devices) > MaxImportRows {
+		return nil, errors.NewValidationError(fmt.Sprintf("A single registration accepts at most %d devices", MaxImportRows))
+	}
+
+	sns := make([]string, 0, len(req.Devices))
+	seen := make(map[string]bool, len(req.Devices))
+	for _, device := range req.Devices {
+		if err := validateDeviceFields(device.Name, device.Location, device.SN); err != nil {
+			return nil, err
+		}
+		if seen[device.SN] {
+			return nil, errors.NewValidationError(fmt.Sprintf("Serial number %s appears more than once", device.SN))
+		}
+		seen[device.SN] = true
+		sns = append(sns, device.SN)
+	}
+
+	registered, err := s.registrationRepo.FindBySNs(sns)
+	if err != nil {
+		return nil, errors.ErrDatabaseError
+	}
+	if len(registered) > 0 {
+		return nil, errors.NewValidationError(fmt.Sprintf("Serial number %s is already registered", registered[0].SN))
+	}
+	existing, err := s.deviceRepo.FindBySNs(sns)
+	if err != nil {
+		return nil, errors.ErrDatabaseError
+	}
+	if len(existing) > 0 {
+		return nil, errors.NewValidationError(fmt.Sprintf("Serial number %s belongs to an existing device", existing[0].SN))
+	}
+
+	now := time.Now()
+	registrations := make([]*models.DeviceRegistration, 0, len(req.Devices))
+	codes := make([]string, 0, len(req.Devices))
+	for _, device := range req.Devices {
+		code, err := newClaimCode()
+		if err != nil {
+			return nil, errors.ErrTokenGeneration
+		}
+		registration := &models.DeviceRegistration{
+			ID:           uuid.New(),
+			SN:           device.SN,
+			Name:         device.Name,
+			Location:     device.Location,
+			Description:  device.Description,
+			RegisteredBy: userID,
+			CreatedAt:    now,
+			UpdatedAt:    now,
+		}
+		if !req.Unassigned {
+			registration.UserID = &userID
+			registration.OrganizationID = orgID
+		}
+		registration.SetClaimCode(code)
+		registrations = append(registrations, registration)
+		codes = append(codes, code)
+	}
+
+	if err := s.registrationRepo.CreateBatch(registrations); err != nil {
+		return nil, errors.ErrDatabaseError
+	}
+
+	responses := make([]dto.DeviceRegistrationResponse, 0, len(registrations))
+	for i, registration := range registrations {
+		response := deviceRegistrationResponse(registration)
+		response.ClaimCode = codes[i]
+		responses = append(responses, response)
+	}
+	return responses, nil
+}
+
+func (s *deviceProvisioningService) ListRegistrations(userID uuid.UUID) ([]dto.DeviceRegistrationResponse, error) {
+	registrations, err := s.registrationRepo.FindByUserID(userID)
+	if err != nil {
+		return nil, errors.ErrDatabaseError
+	}
+
+	responses := make([]dto.DeviceRegistrationResponse, 0, len(registrations))
+	for i := range registrations {
+		responses = append(responses, deviceRegistrationResponse(&registrations[i]))
+	}
+	return responses, nil
+}
+
+// Provision is called by the device itself, without a user token. It spends
+// the claim code and returns the device UUID with a device token that is
+// shown only once. The device is created on first provisioning in the scope
+// the registration is assigned to; unassigned registrations must be claimed
+// first.
+func (s *deviceProvisioningService) Provision(req dto.DeviceClaimRequest) (*dto.ProvisionResponse, error) {
+	registration, err := s.findByClaimCode(req)
+	if err != nil {
+		return nil, err
+	}
+	if registration.ProvisionedAt != nil {
+		return nil, errors.ErrClaimCodeUsed
+	}
+	if registration.UserID == nil {
+		return nil, errors.ErrDeviceNotClaimed
+	}
+
+	var device *models.Device
+	if registration.DeviceID != nil {
+		device, err = s.deviceRepo.FindByID(*registration.DeviceID)
+		if err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return nil, errors.ErrDeviceNotFound
+			}
+			return nil, errors.ErrDatabaseError
+		}
+	} else {
+		if err := s.checkSNAvailable(registration.SN); err != nil {
+			return nil, err
+		}
+		device = registeredDevice(registration, *registration.UserID, registration.OrganizationID)
+	}
+
+	token, err := newDeviceToken()
+	if err != nil {
+		return nil, errors.ErrTokenGeneration
+	}
+	device.TokenHash = models.HashSecret(token)
+
+	if err := s.registrationRepo.Provision(registration, device, time.Now()); err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.ErrClaimCodeUsed
+		}
+		return nil, errors.ErrDatabaseError
+	}
+
+	return &dto.ProvisionResponse{DeviceID: device.UUID, DeviceToken: token}, nil
+}
+
+// ClaimDevice assigns an unassigned registration to the caller, or to the
+// active organization with the admin role, and creates its device. The claim
+// code proves possession of the hardware; it stays valid for the device to
+// provision itself.
+func (s *deviceProvisioningService) ClaimDevice(userID uuid.UUID, orgID *uuid.UUID, req dto.DeviceClaimRequest) (*models.Device, error) {
+	if err := s.authz.Authorize(userID, Ownership{UserID: userID, OrganizationID: orgID}, models.RoleAdmin); err != nil {
+		return nil, err
+	}
+
+	registration, err := s.findByClaimCode(req)
+	if err != nil {
+		return nil, err
+	}
+	if registration.UserID != nil {
+		return nil, errors.ErrDeviceAlreadyClaimed
+	}
+	if err := s.checkSNAvailable(registration.SN); err != nil {
+		return nil, err
+	}
+
+	device := registeredDevice(registration, userID, orgID)
+	if err := s.registrationRepo.Claim(registration, device); err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.ErrDeviceAlreadyClaimed
+		}
+		return nil, errors.ErrDatabaseError
+	}
+	return device, nil
+}
+
+// findByClaimCode loads the registration of req.SN if the claim code
+// matches. An unknown serial number and a wrong code fail the same way so
+// that the endpoint cannot be used to probe serial numbers.
+func (s *deviceProvisioningService) findByClaimCode(req dto.DeviceClaimRequest) (*models.DeviceRegistration, error) {
+	registration, err := s.registrationRepo.FindBySN(strings.TrimSpace(req.SN))
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.ErrInvalidClaimCode
+		}
+		return nil, errors.ErrDatabaseError
+	}
+	if !registration.CheckClaimCode(normalizeClaimCode(req.ClaimCode)) {
+		return nil, errors.ErrInvalidClaimCode
+	}
+	return registration, nil
+}
+
+func (s *deviceProvisioningService) checkSNAvailable(sn string) error {
+	existing, err := s.deviceRepo.FindBySN(sn)
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return errors.ErrDatabaseError
+	}
+	if existing != nil {
+		if existing.DeletedAt.Valid {
+			return errors.ErrDeviceAwaitingPurge
+		}
+		return errors.ErrDeviceAlreadyExists
+	}
+	return nil
+}
+
+func registeredDevice(registration *models.DeviceRegistration, userID uuid.UUID, orgID *uuid.UUID) *models.Device {
+	now := time.Now()
+	return &models.Device{
+		UUID:           uuid.New(),
+		Name:           registration.Name,
+		Location:       registration.Location,
+		SN:             registration.SN,
+		Description:    registration.Description,
+		Labels:         models.Labels{},
+		UserID:         userID,
+		OrganizationID: orgID,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+}
+
+func deviceRegistrationResponse(registration *models.DeviceRegistration) dto.DeviceRegistrationResponse {
+	status := RegistrationUnassigned
+	switch {
+	case registration.ProvisionedAt != nil:
+		status = RegistrationProvisioned
+	case registration.UserID != nil:
+		status = RegistrationAssigned
+	}
+
+	return dto.DeviceRegistrationResponse{
+		ID:             registration.ID,
+		SN:             registration.SN,
+		Name:           registration.Name,
+		Location:       registration.Location,
+		Status:         status,
+		OrganizationID: registration.OrganizationID,
+		DeviceID:       registration.DeviceID,
+		ProvisionedAt:  registration.ProvisionedAt,
+		CreatedAt:      registration.CreatedAt,
+	}
+}
+
+// newClaimCode returns a random code of four groups of four characters,
+// e.g. 7KQM-XR2P-9ZC4-HW3N.
+func newClaimCode() (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+
+	var code strings.Builder
+	for i, b := range buf {
+		if i > 0 && i%4 == 0 {
+			code.WriteByte('-')
+		}
+		code.WriteByte(claimCodeAlphabet[int(b)%len(claimCodeAlphabet)])
+	}
+	return code.String(), nil
+}
+
+// normalizeClaimCode accepts codes typed in lower case or without dashes.
+func normalizeClaimCode(code string) string {
+	code = strings.ToUpper(strings.ReplaceAll(strings.TrimSpace(code), "-", ""))
+	var normalized strings.Builder
+	for i, c := range code {
+		if i > 0 && i%4 == 0 {
+			normalized.WriteByte('-')
+		}
+		normalized.WriteRune(c)
+	}
+	return normalized.String()
+}
+
+func newDeviceToken() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return "dt_" + hex.EncodeToString(buf), nil
+}
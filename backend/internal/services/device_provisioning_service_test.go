@@ -0,0 +1,303 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/dto"
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/models"
+	custom_errors "github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/utils/errors"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+type MockDeviceRegistrationRepository struct {
+	mock.Mock
+}
+
+func (m *MockDeviceRegistrationRepository) CreateBatch(registrations []*models.DeviceRegistration) error {
+	args := m.Called(registrations)
+	return args.Error(0)
+}
+
+func (m *MockDeviceRegistrationRepository) FindBySN(sn string) (*models.DeviceRegistration, error) {
+	args := m.Called(sn)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.DeviceRegistration), args.Error(1)
+}
+
+func (m *MockDeviceRegistrationRepository) FindBySNs(sns []string) ([]models.DeviceRegistration, error) {
+	args := m.Called(sns)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.DeviceRegistration), args.Error(1)
+}
+
+func (m *MockDeviceRegistrationRepository) FindByUserID(userID uuid.UUID) ([]models.DeviceRegistration, error) {
+	args := m.Called(userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.DeviceRegistration), args.Error(1)
+}
+
+func (m *MockDeviceRegistrationRepository) Claim(registration *models.DeviceRegistration, device *models.Device) error {
+	args := m.Called(registration, device)
+	return args.Error(0)
+}
+
+func (m *MockDeviceRegistrationRepository) Provision(registration *models.DeviceRegistration, device *models.Device, provisionedAt time.Time) error {
+	args := m.Called(registration, device, provisionedAt)
+	return args.Error(0)
+}
+
+func newTestDeviceProvisioningService() (DeviceProvisioningService, *MockDeviceRegistrationRepository, *MockDeviceRepository, *MockOrganizationRepository) {
+	registrationRepo := new(MockDeviceRegistrationRepository)
+	deviceRepo := new(MockDeviceRepository)
+	orgRepo := new(MockOrganizationRepository)
+	authz := NewAuthorizer(orgRepo, noDeviceShares())
+	return NewDeviceProvisioningService(registrationRepo, deviceRepo, authz), registrationRepo, deviceRepo, orgRepo
+}
+
+func newTestRegistration(sn, code string, userID *uuid.UUID) *models.DeviceRegistration {
+	registration := &models.DeviceRegistration{
+		ID:       uuid.New(),
+		SN:       sn,
+		Name:     "Gateway",
+		Location: "Rack 1",
+		UserID:   userID,
+	}
+	registration.SetClaimCode(code)
+	return registration
+}
+
+func TestDeviceProvisioningService_RegisterDevices(t *testing.T) {
+	adminID := uuid.New()
+	req := dto.DeviceRegistrationRequest{Devices: []dto.CreateDeviceRequest{
+		{Name: "Gateway 01", Location: "Rack 1", SN: "123456789012"},
+		{Name: "Gateway 02", Location: "Rack 1", SN: "123456789013"},
+	}}
+	sns := []string{"123456789012", "123456789013"}
+
+	t.Run("Success - Returns one claim code per device", func(t *testing.T) {
+		service, registrationRepo, deviceRepo, _ := newTestDeviceProvisioningService()
+		registrationRepo.On("FindBySNs", sns).Return([]models.DeviceRegistration{}, nil)
+		deviceRepo.On("FindBySNs", sns).Return([]models.Device{}, nil)
+		registrationRepo.On("CreateBatch", mock.MatchedBy(func(registrations []*models.DeviceRegistration) bool {
+			return len(registrations) == 2 && *registrations[0].UserID == adminID && registrations[0].ClaimCodeHash != ""
+		})).Return(nil)
+
+		registrations, err := service.RegisterDevices(adminID, nil, req)
+
+		assert.NoError(t, err)
+		assert.Len(t, registrations, 2)
+		assert.Regexp(t, `^[A-Z2-9]{4}-[A-Z2-9]{4}-[A-Z2-9]{4}-[A-Z2-9]{4}$`, registrations[0].ClaimCode)
+		assert.NotEqual(t, registrations[0].ClaimCode, registrations[1].ClaimCode)
+		assert.Equal(t, RegistrationAssigned, registrations[0].Status)
+		registrationRepo.AssertExpectations(t)
+	})
+
+	t.Run("Success - Unassigned devices", func(t *testing.T) {
+		service, registrationRepo, deviceRepo, _ := newTestDeviceProvisioningService()
+		registrationRepo.On("FindBySNs", sns).Return([]models.DeviceRegistration{}, nil)
+		deviceRepo.On("FindBySNs", sns).Return([]models.Device{}, nil)
+		registrationRepo.On("CreateBatch", mock.MatchedBy(func(registrations []*models.DeviceRegistration) bool {
+			return registrations[0].UserID == nil && registrations[0].RegisteredBy == adminID
+		})).Return(nil)
+
+		registrations, err := service.RegisterDevices(adminID, nil, dto.DeviceRegistrationRequest{Devices: req.Devices, Unassigned: true})
+
+		assert.NoError(t, err)
+		assert.Equal(t, RegistrationUnassigned, registrations[0].Status)
+	})
+
+	t.Run("Error - Duplicate serial number in request", func(t *testing.T) {
+		service, registrationRepo, _, _ := newTestDeviceProvisioningService()
+		duplicated := dto.DeviceRegistrationRequest{Devices: []dto.CreateDeviceRequest{req.Devices[0], req.Devices[0]}}
+
+		registrations, err := service.RegisterDevices(adminID, nil, duplicated)
+
+		assert.Nil(t, registrations)
+		assert.ErrorContains(t, err, "more than once")
+		registrationRepo.AssertNotCalled(t, "CreateBatch", mock.Anything)
+	})
+
+	t.Run("Error - Serial number already registered", func(t *testing.T) {
+		service, registrationRepo, _, _ := newTestDeviceProvisioningService()
+		registrationRepo.On("FindBySNs", sns).Return([]models.DeviceRegistration{{SN: "123456789013"}}, nil)
+
+		registrations, err := service.RegisterDevices(adminID, nil, req)
+
+		assert.Nil(t, registrations)
+		assert.ErrorContains(t, err, "already registered")
+	})
+
+	t.Run("Error - Organization viewer", func(t *testing.T) {
+		service, registrationRepo, _, orgRepo := newTestDeviceProvisioningService()
+		orgID := uuid.New()
+		orgRepo.On("FindMembership", orgID, adminID).Return(&models.Membership{Role: models.RoleViewer}, nil)
+
+		registrations, err := service.RegisterDevices(adminID, &orgID, req)
+
+		assert.Nil(t, registrations)
+		assert.Equal(t, custom_errors.ErrForbidden, err)
+		registrationRepo.AssertNotCalled(t, "FindBySNs", mock.Anything)
+	})
+}
+
+func TestDeviceProvisioningService_Provision(t *testing.T) {
+	ownerID := uuid.New()
+	sn := "123456789012"
+	code := "7KQM-XR2P-9ZC4-HW3N"
+
+	t.Run("Success - Creates the device and returns a token", func(t *testing.T) {
+		service, registrationRepo, deviceRepo, _ := newTestDeviceProvisioningService()
+		registration := newTestRegistration(sn, code, &ownerID)
+		registrationRepo.On("FindBySN", sn).Return(registration, nil)
+		deviceRepo.On("FindBySN", sn).Return(nil, gorm.ErrRecordNotFound)
+		var created *models.Device
+		registrationRepo.On("Provision", registration, mock.AnythingOfType("*models.Device"), mock.AnythingOfType("time.Time")).
+			Run(func(args mock.Arguments) { created = args.Get(1).(*models.Device) }).
+			Return(nil)
+
+		credentials, err := service.Provision(dto.DeviceClaimRequest{SN: sn, ClaimCode: "7kqmxr2p9zc4hw3n"})
+
+		assert.NoError(t, err)
+		assert.Equal(t, created.UUID, credentials.DeviceID)
+		assert.Equal(t, ownerID, created.UserID)
+		assert.Equal(t, models.HashSecret(credentials.DeviceToken), created.TokenHash)
+		assert.Regexp(t, `^dt_[0-9a-f]{64}$`, credentials.DeviceToken)
+	})
+
+	t.Run("Success - Device created by a claim", func(t *testing.T) {
+		service, registrationRepo, deviceRepo, _ := newTestDeviceProvisioningService()
+		registration := newTestRegistration(sn, code, &ownerID)
+		device := &models.Device{UUID: uuid.New(), SN: sn, UserID: ownerID}
+		registration.DeviceID = &device.UUID
+		registrationRepo.On("FindBySN", sn).Return(registration, nil)
+		deviceRepo.On("FindByID", device.UUID).Return(device, nil)
+		registrationRepo.On("Provision", registration, device, mock.AnythingOfType("time.Time")).Return(nil)
+
+		credentials, err := service.Provision(dto.DeviceClaimRequest{SN: sn, ClaimCode: code})
+
+		assert.NoError(t, err)
+		assert.Equal(t, device.UUID, credentials.DeviceID)
+		deviceRepo.AssertNotCalled(t, "FindBySN", mock.Anything)
+	})
+
+	t.Run("Error - Unknown serial number", func(t *testing.T) {
+		service, registrationRepo, _, _ := newTestDeviceProvisioningService()
+		registrationRepo.On("FindBySN", sn).Return(nil, gorm.ErrRecordNotFound)
+
+		credentials, err := service.Provision(dto.DeviceClaimRequest{SN: sn, ClaimCode: code})
+
+		assert.Nil(t, credentials)
+		assert.Equal(t, custom_errors.ErrInvalidClaimCode, err)
+	})
+
+	t.Run("Error - Wrong claim code", func(t *testing.T) {
+		service, registrationRepo, _, _ := newTestDeviceProvisioningService()
+		registrationRepo.On("FindBySN", sn).Return(newTestRegistration(sn, code, &ownerID), nil)
+
+		credentials, err := service.Provision(dto.DeviceClaimRequest{SN: sn, ClaimCode: "AAAA-BBBB-CCCC-DDDD"})
+
+		assert.Nil(t, credentials)
+		assert.Equal(t, custom_errors.ErrInvalidClaimCode, err)
+		registrationRepo.AssertNotCalled(t, "Provision", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Error - Claim code already used", func(t *testing.T) {
+		service, registrationRepo, _, _ := newTestDeviceProvisioningService()
+		registration := newTestRegistration(sn, code, &ownerID)
+		provisionedAt := time.Now()
+		registration.ProvisionedAt = &provisionedAt
+		registrationRepo.On("FindBySN", sn).Return(registration, nil)
+
+		credentials, err := service.Provision(dto.DeviceClaimRequest{SN: sn, ClaimCode: code})
+
+		assert.Nil(t, credentials)
+		assert.Equal(t, custom_errors.ErrClaimCodeUsed, err)
+	})
+
+	t.Run("Error - Not claimed yet", func(t *testing.T) {
+		service, registrationRepo, _, _ := newTestDeviceProvisioningService()
+		registrationRepo.On("FindBySN", sn).Return(newTestRegistration(sn, code, nil), nil)
+
+		credentials, err := service.Provision(dto.DeviceClaimRequest{SN: sn, ClaimCode: code})
+
+		assert.Nil(t, credentials)
+		assert.Equal(t, custom_errors.ErrDeviceNotClaimed, err)
+	})
+
+	t.Run("Error - Concurrent provisioning", func(t *testing.T) {
+		service, registrationRepo, deviceRepo, _ := newTestDeviceProvisioningService()
+		registration := newTestRegistration(sn, code, &ownerID)
+		registrationRepo.On("FindBySN", sn).Return(registration, nil)
+		deviceRepo.On("FindBySN", sn).Return(nil, gorm.ErrRecordNotFound)
+		registrationRepo.On("Provision", registration, mock.Anything, mock.Anything).Return(gorm.ErrRecordNotFound)
+
+		credentials, err := service.Provision(dto.DeviceClaimRequest{SN: sn, ClaimCode: code})
+
+		assert.Nil(t, credentials)
+		assert.Equal(t, custom_errors.ErrClaimCodeUsed, err)
+	})
+}
+
+func TestDeviceProvisioningService_ClaimDevice(t *testing.T) {
+	userID := uuid.New()
+	sn := "123456789012"
+	code := "7KQM-XR2P-9ZC4-HW3N"
+
+	t.Run("Success - Unassigned device", func(t *testing.T) {
+		service, registrationRepo, deviceRepo, _ := newTestDeviceProvisioningService()
+		registration := newTestRegistration(sn, code, nil)
+		registrationRepo.On("FindBySN", sn).Return(registration, nil)
+		deviceRepo.On("FindBySN", sn).Return(nil, gorm.ErrRecordNotFound)
+		registrationRepo.On("Claim", registration, mock.MatchedBy(func(d *models.Device) bool {
+			return d.UserID == userID && d.SN == sn && d.Name == registration.Name
+		})).Return(nil)
+
+		device, err := service.ClaimDevice(userID, nil, dto.DeviceClaimRequest{SN: sn, ClaimCode: code})
+
+		assert.NoError(t, err)
+		assert.Equal(t, userID, device.UserID)
+		registrationRepo.AssertExpectations(t)
+	})
+
+	t.Run("Error - Already claimed", func(t *testing.T) {
+		service, registrationRepo, _, _ := newTestDeviceProvisioningService()
+		otherID := uuid.New()
+		registrationRepo.On("FindBySN", sn).Return(newTestRegistration(sn, code, &otherID), nil)
+
+		device, err := service.ClaimDevice(userID, nil, dto.DeviceClaimRequest{SN: sn, ClaimCode: code})
+
+		assert.Nil(t, device)
+		assert.Equal(t, custom_errors.ErrDeviceAlreadyClaimed, err)
+	})
+
+	t.Run("Error - Wrong claim code", func(t *testing.T) {
+		service, registrationRepo, _, _ := newTestDeviceProvisioningService()
+		registrationRepo.On("FindBySN", sn).Return(newTestRegistration(sn, code, nil), nil)
+
+		device, err := service.ClaimDevice(userID, nil, dto.DeviceClaimRequest{SN: sn, ClaimCode: "AAAA-BBBB-CCCC-DDDD"})
+
+		assert.Nil(t, device)
+		assert.Equal(t, custom_errors.ErrInvalidClaimCode, err)
+	})
+
+	t.Run("Error - Database failure", func(t *testing.T) {
+		service, registrationRepo, _, _ := newTestDeviceProvisioningService()
+		registrationRepo.On("FindBySN", sn).Return(nil, errors.New("db down"))
+
+		device, err := service.ClaimDevice(userID, nil, dto.DeviceClaimRequest{SN: sn, ClaimCode: code})
+
+		assert.Nil(t, device)
+		assert.Equal(t, custom_errors.ErrDatabaseError, err)
+	})
+}
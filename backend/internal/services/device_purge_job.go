@@ -0,0 +1,57 @@
+package services
+
+import (
+	"time"
+
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/utils/logger"
+)
+
+// DevicePurgeJob periodically purges the devices whose restore window is
+// over.
+type DevicePurgeJob struct {
+	deviceService DeviceService
+	interval      time.Duration
+	shutdown      chan struct{}
+}
+
+func NewDevicePurgeJob(deviceService DeviceService, interval time.Duration) *DevicePurgeJob {
+	return &DevicePurgeJob{
+		deviceService: deviceService,
+		interval:      interval,
+		shutdown:      make(chan struct{}),
+	}
+}
+
+// Run purges once immediately and then every interval until Stop is called.
+func (j *DevicePurgeJob) Run() {
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	for {
+		j.purge()
+		select {
+		case <-ticker.C:
+		case <-j.shutdown:
+			return
+		}
+	}
+}
+
+func (j *DevicePurgeJob) Stop() {
+	select {
+	case <-j.shutdown:
+	default:
+		close(j.shutdown)
+	}
+}
+
+func (j *DevicePurgeJob) purge() {
+	purged, err := j.deviceService.PurgeDeletedDevices()
+	if err != nil {
+		logger.Logger.Error("Error purging deleted devices", "purged", purged, "error", err)
+		return
+	}
+	if purged > 0 {
+		logger.Logger.Info("Purged deleted devices", "purged", purged)
+	}
+}
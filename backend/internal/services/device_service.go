@@ -1,45 +1,97 @@
 package services
 
 import (
+	"fmt"
 	"regexp"
+	"strings"
 	"time"
 
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/dto"
 	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/models"
 	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/repository"
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/utils"
 	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/utils/errors"
 	"github.com/google/uuid"
 	"gorm.io/gorm"
 )
 
 type DeviceService interface {
-    CreateDevice(userID uuid.UUID, name, location, sn, description string) (*models.Device, error)
+    CreateDevice(userID uuid.UUID, orgID *uuid.UUID, name, location, sn, description string) (*models.Device, error)
     GetDevice(userID, deviceID uuid.UUID) (*models.Device, error)
     ListDevices(userID uuid.UUID) ([]models.Device, error)
+    SearchDevices(userID uuid.UUID, query dto.DeviceListQuery) ([]models.Device, int64, error)
     UpdateDevice(userID, deviceID uuid.UUID, name, location, description string) (*models.Device, error)
     DeleteDevice(userID, deviceID uuid.UUID) error
+    ListDeletedDevices(userID uuid.UUID) ([]models.Device, error)
+    RestoreDevice(userID, deviceID uuid.UUID) (*models.Device, error)
+    PurgeDevice(userID, deviceID uuid.UUID) error
+    PurgeDeletedDevices() (int, error)
+    ImportDevices(userID uuid.UUID, orgID *uuid.UUID, rows []dto.CreateDeviceRequest, mode string, dryRun bool) (*dto.DeviceImportResponse, error)
+    GetDeviceLabels(userID, deviceID uuid.UUID) (models.Labels, error)
+    ReplaceDeviceLabels(userID, deviceID uuid.UUID, labels map[string]string) (models.Labels, error)
+    SetDeviceLabel(userID, deviceID uuid.UUID, key, value string) (models.Labels, error)
+    DeleteDeviceLabel(userID, deviceID uuid.UUID, key string) (models.Labels, error)
+    ListDevicesByGroup(userID, groupID uuid.UUID, recursive bool) ([]models.Device, error)
 }
 
-type deviceService struct {
-    deviceRepo repository.DeviceRepository
+const (
+    ImportModeAllOrNothing = "all_or_nothing"
+    ImportModeBestEffort   = "best_effort"
+
+    ImportStatusValid   = "valid"
+    ImportStatusCreated = "created"
+    ImportStatusFailed  = "failed"
+    ImportStatusSkipped = "skipped"
+
+    MaxImportRows = 1000
+    MaxImportSize = 2 << 20
+
+    MaxDevicePageSize = 500
+)
+
+const (
+    HeartbeatsKeep   = "keep"
+    HeartbeatsDelete = "delete"
+)
+
+// DeviceDeletePolicy controls what deleting a device cascades to. A deleted
+// device can be restored during RestoreWindow and is purged afterwards.
+// Heartbeats is HeartbeatsKeep to keep heartbeats until the purge, or
+// HeartbeatsDelete to drop them as soon as the device is deleted.
+type DeviceDeletePolicy struct {
+    RestoreWindow time.Duration
+    Heartbeats    string
 }
 
-func NewDeviceService(deviceRepo repository.DeviceRepository) DeviceService {
-    return &deviceService{deviceRepo: deviceRepo}
+var DefaultDeviceDeletePolicy = DeviceDeletePolicy{
+    RestoreWindow: 30 * 24 * time.Hour,
+    Heartbeats:    HeartbeatsKeep,
 }
 
-func (s *deviceService) CreateDevice(userID uuid.UUID, name, location, sn, description string) (*models.Device, error) {
-    if name == "" {
-        return nil, errors.NewValidationError("Device name is required")
-    }
-    if location == "" {
-        return nil, errors.NewValidationError("Device location is required")
-    }
-    if sn == "" {
-        return nil, errors.NewValidationError("Device serial number is required")
+type deviceService struct {
+    deviceRepo   repository.DeviceRepository
+    groupRepo    repository.DeviceGroupRepository
+    deletePolicy DeviceDeletePolicy
+    authz        Authorizer
+}
+
+func NewDeviceService(deviceRepo repository.DeviceRepository, groupRepo repository.DeviceGroupRepository, deletePolicy DeviceDeletePolicy, authz Authorizer) DeviceService {
+    return &deviceService{
+        deviceRepo:   deviceRepo,
+        groupRepo:    groupRepo,
+        deletePolicy: deletePolicy,
+        authz:        authz,
     }
+}
 
-    if !isValidSN(sn) {
-        return nil, errors.NewValidationError("Serial number must be exactly 12 digits")
+// CreateDevice registers a personal device, or an organization device when
+// orgID is set; the latter requires the admin role in that organization.
+func (s *deviceService) CreateDevice(userID uuid.UUID, orgID *uuid.UUID, name, location, sn, description string) (*models.Device, error) {
+    if err := s.authz.Authorize(userID, Ownership{UserID: userID, OrganizationID: orgID}, models.RoleAdmin); err != nil {
+        return nil, err
+    }
+    if err := validateDeviceFields(name, location, sn); err != nil {
+        return nil, err
     }
 
     existing, err := s.deviceRepo.FindBySN(sn)
@@ -47,18 +99,23 @@ func (s *deviceService) CreateDevice(userID uuid.UUID, name, location, sn, descr
         return nil, errors.ErrDatabaseError
     }
     if existing != nil {
+        if existing.DeletedAt.Valid {
+            return nil, errors.ErrDeviceAwaitingPurge
+        }
         return nil, errors.ErrDeviceAlreadyExists
     }
 
     device := &models.Device{
-        UUID:        uuid.New(),
-        Name:        name,
-        Location:    location,
-        SN:          sn,
-        Description: description,
-        UserID:      userID,
-        CreatedAt:   time.Now(),
-        UpdatedAt:   time.Now(),
+        UUID:           uuid.New(),
+        Name:           name,
+        Location:       location,
+        SN:             sn,
+        Description:    description,
+        Labels:         models.Labels{},
+        UserID:         userID,
+        OrganizationID: orgID,
+        CreatedAt:      time.Now(),
+        UpdatedAt:      time.Now(),
     }
 
     if err := s.deviceRepo.Create(device); err != nil {
@@ -69,6 +126,11 @@ func (s *deviceService) CreateDevice(userID uuid.UUID, name, location, sn, descr
 }
 
 func (s *deviceService) GetDevice(userID, deviceID uuid.UUID) (*models.Device, error) {
+    return s.findDevice(userID, deviceID, models.RoleViewer)
+}
+
+// findDevice loads a device and checks that userID holds at least role on it.
+func (s *deviceService) findDevice(userID, deviceID uuid.UUID, role string) (*models.Device, error) {
     device, err := s.deviceRepo.FindByID(deviceID)
     if err != nil {
         if err == gorm.ErrRecordNotFound {
@@ -77,8 +139,8 @@ func (s *deviceService) GetDevice(userID, deviceID uuid.UUID) (*models.Device, e
         return nil, errors.ErrDatabaseError
     }
 
-    if device.UserID != userID {
-        return nil, errors.ErrForbidden
+    if err := s.authz.Authorize(userID, deviceOwnership(device), role); err != nil {
+        return nil, err
     }
 
     return device, nil
@@ -92,11 +154,152 @@ func (s *deviceService) ListDevices(userID uuid.UUID) ([]models.Device, error) {
     return devices, nil
 }
 
-func (s *deviceService) UpdateDevice(userID, deviceID uuid.UUID, name, location, description string) (*models.Device, error) {
+func (s *deviceService) SearchDevices(userID uuid.UUID, query dto.DeviceListQuery) ([]models.Device, int64, error) {
+    filter := repository.DeviceFilter{
+        Search:   strings.TrimSpace(query.Search),
+        Location: query.Location,
+        SortBy:   query.Sort,
+        Limit:    query.Limit,
+        Offset:   query.Offset,
+    }
+
+    if filter.SortBy == "" {
+        filter.SortBy = "created_at"
+    }
+    if filter.SortBy != "name" && filter.SortBy != "created_at" && filter.SortBy != "last_seen" {
+        return nil, 0, errors.NewValidationError("sort must be one of name, created_at, last_seen")
+    }
+
+    switch strings.ToLower(query.Order) {
+    case "", "asc":
+    case "desc":
+        filter.Desc = true
+    default:
+        return nil, 0, errors.NewValidationError("order must be asc or desc")
+    }
+
+    selector, err := utils.ParseLabelSelector(query.Labels)
+    if err != nil {
+        return nil, 0, errors.NewValidationError(err.Error())
+    }
+    filter.Labels = selector
+
+    if filter.Limit < 0 || filter.Offset < 0 {
+        return nil, 0, errors.NewValidationError("limit and offset must not be negative")
+    }
+    if filter.Limit > MaxDevicePageSize {
+        return nil, 0, errors.NewValidationError(fmt.Sprintf("limit must not exceed %d", MaxDevicePageSize))
+    }
+
+    devices, total, err := s.deviceRepo.Search(userID, filter)
+    if err != nil {
+        return nil, 0, errors.ErrDatabaseError
+    }
+    return devices, total, nil
+}
+
+func (s *deviceService) GetDeviceLabels(userID, deviceID uuid.UUID) (models.Labels, error) {
     device, err := s.GetDevice(userID, deviceID)
     if err != nil {
         return nil, err
     }
+    return deviceLabels(device), nil
+}
+
+func (s *deviceService) ReplaceDeviceLabels(userID, deviceID uuid.UUID, labels map[string]string) (models.Labels, error) {
+    if _, err := s.findDevice(userID, deviceID, models.RoleOperator); err != nil {
+        return nil, err
+    }
+    if err := utils.ValidateLabels(labels); err != nil {
+        return nil, errors.NewValidationError(err.Error())
+    }
+
+    replaced := models.Labels{}
+    for key, value := range labels {
+        replaced[key] = value
+    }
+    return s.saveLabels(deviceID, replaced)
+}
+
+func (s *deviceService) SetDeviceLabel(userID, deviceID uuid.UUID, key, value string) (models.Labels, error) {
+    device, err := s.findDevice(userID, deviceID, models.RoleOperator)
+    if err != nil {
+        return nil, err
+    }
+    labels := deviceLabels(device)
+
+    labels[key] = value
+    if err := utils.ValidateLabels(labels); err != nil {
+        return nil, errors.NewValidationError(err.Error())
+    }
+    return s.saveLabels(deviceID, labels)
+}
+
+func (s *deviceService) DeleteDeviceLabel(userID, deviceID uuid.UUID, key string) (models.Labels, error) {
+    device, err := s.findDevice(userID, deviceID, models.RoleOperator)
+    if err != nil {
+        return nil, err
+    }
+    labels := deviceLabels(device)
+
+    if _, ok := labels[key]; !ok {
+        return nil, errors.ErrLabelNotFound
+    }
+    delete(labels, key)
+    return s.saveLabels(deviceID, labels)
+}
+
+func deviceLabels(device *models.Device) models.Labels {
+    if device.Labels == nil {
+        return models.Labels{}
+    }
+    return device.Labels
+}
+
+func (s *deviceService) saveLabels(deviceID uuid.UUID, labels models.Labels) (models.Labels, error) {
+    if err := s.deviceRepo.UpdateLabels(deviceID, labels); err != nil {
+        if err == gorm.ErrRecordNotFound {
+            return nil, errors.ErrDeviceNotFound
+        }
+        return nil, errors.ErrDatabaseError
+    }
+    return labels, nil
+}
+
+// ListDevicesByGroup lists the devices of a group and, when recursive is set,
+// the devices of all its subgroups.
+func (s *deviceService) ListDevicesByGroup(userID, groupID uuid.UUID, recursive bool) ([]models.Device, error) {
+    group, err := s.groupRepo.FindByID(groupID)
+    if err != nil {
+        if err == gorm.ErrRecordNotFound {
+            return nil, errors.ErrDeviceGroupNotFound
+        }
+        return nil, errors.ErrDatabaseError
+    }
+    if err := s.authz.Authorize(userID, groupOwnership(group), models.RoleViewer); err != nil {
+        return nil, err
+    }
+
+    groupIDs := []uuid.UUID{groupID}
+    if recursive {
+        groupIDs, err = s.groupRepo.FindDescendantIDs(groupID)
+        if err != nil {
+            return nil, errors.ErrDatabaseError
+        }
+    }
+
+    devices, err := s.deviceRepo.FindByGroupIDs(userID, groupIDs)
+    if err != nil {
+        return nil, errors.ErrDatabaseError
+    }
+    return devices, nil
+}
+
+func (s *deviceService) UpdateDevice(userID, deviceID uuid.UUID, name, location, description string) (*models.Device, error) {
+    device, err := s.findDevice(userID, deviceID, models.RoleOperator)
+    if err != nil {
+        return nil, err
+    }
 
     if name == "" {
         return nil, errors.NewValidationError("Device name is required")
@@ -117,19 +320,249 @@ func (s *deviceService) UpdateDevice(userID, deviceID uuid.UUID, name, location,
     return device, nil
 }
 
+// DeleteDevice soft-deletes the device according to the delete policy. The
+// device keeps its serial number until it is purged.
 func (s *deviceService) DeleteDevice(userID, deviceID uuid.UUID) error {
-    _, err := s.GetDevice(userID, deviceID)
+    _, err := s.findDevice(userID, deviceID, models.RoleAdmin)
     if err != nil {
         return err
     }
 
-    if err := s.deviceRepo.Delete(deviceID); err != nil {
+    if err := s.deviceRepo.Delete(deviceID, s.deletePolicy.Heartbeats == HeartbeatsDelete); err != nil {
         return errors.ErrDatabaseError
     }
 
     return nil
 }
 
+// ListDeletedDevices returns the deleted devices of the user that can still
+// be restored.
+func (s *deviceService) ListDeletedDevices(userID uuid.UUID) ([]models.Device, error) {
+    devices, err := s.deviceRepo.FindDeleted(userID, time.Now().Add(-s.deletePolicy.RestoreWindow))
+    if err != nil {
+        return nil, errors.ErrDatabaseError
+    }
+    return devices, nil
+}
+
+// RestoreDevice brings back a device deleted within the restore window. It
+// does not rejoin the rules it was removed from.
+func (s *deviceService) RestoreDevice(userID, deviceID uuid.UUID) (*models.Device, error) {
+    device, err := s.findDeletedDevice(userID, deviceID)
+    if err != nil {
+        return nil, err
+    }
+    if !device.DeletedAt.Time.After(time.Now().Add(-s.deletePolicy.RestoreWindow)) {
+        return nil, errors.ErrDeviceNotFound
+    }
+
+    if err := s.deviceRepo.Restore(deviceID); err != nil {
+        if err == gorm.ErrRecordNotFound {
+            return nil, errors.ErrDeviceNotFound
+        }
+        return nil, errors.ErrDatabaseError
+    }
+
+    device.DeletedAt = gorm.DeletedAt{}
+    return device, nil
+}
+
+// PurgeDevice permanently removes a deleted device before the end of its
+// restore window, e.g. to register its serial number again.
+func (s *deviceService) PurgeDevice(userID, deviceID uuid.UUID) error {
+    if _, err := s.findDeletedDevice(userID, deviceID); err != nil {
+        return err
+    }
+
+    if err := s.deviceRepo.Purge(deviceID); err != nil {
+        if err == gorm.ErrRecordNotFound {
+            return errors.ErrDeviceNotFound
+        }
+        return errors.ErrDatabaseError
+    }
+    return nil
+}
+
+// PurgeDeletedDevices permanently removes every device whose restore window
+// is over and returns how many were purged.
+func (s *deviceService) PurgeDeletedDevices() (int, error) {
+    deviceIDs, err := s.deviceRepo.FindPurgeable(time.Now().Add(-s.deletePolicy.RestoreWindow))
+    if err != nil {
+        return 0, errors.ErrDatabaseError
+    }
+
+    purged := 0
+    for _, deviceID := range deviceIDs {
+        if err := s.deviceRepo.Purge(deviceID); err != nil {
+            if err == gorm.ErrRecordNotFound {
+                continue
+            }
+            return purged, errors.ErrDatabaseError
+        }
+        purged++
+    }
+    return purged, nil
+}
+
+func (s *deviceService) findDeletedDevice(userID, deviceID uuid.UUID) (*models.Device, error) {
+    device, err := s.deviceRepo.FindDeletedByID(deviceID)
+    if err != nil {
+        if err == gorm.ErrRecordNotFound {
+            return nil, errors.ErrDeviceNotFound
+        }
+        return nil, errors.ErrDatabaseError
+    }
+
+    if err := s.authz.Authorize(userID, deviceOwnership(device), models.RoleAdmin); err != nil {
+        return nil, err
+    }
+    return device, nil
+}
+
+// ImportDevices validates every row with the same rules as CreateDevice and,
+// unless dryRun is set, creates the valid ones. In all_or_nothing mode a
+// single invalid row rejects the whole batch and creation runs in one
+// transaction; in best_effort mode valid rows are created independently.
+// Like CreateDevice, importing into an organization requires the admin role.
+func (s *deviceService) ImportDevices(userID uuid.UUID, orgID *uuid.UUID, rows []dto.CreateDeviceRequest, mode string, dryRun bool) (*dto.DeviceImportResponse, error) {
+    if err := s.authz.Authorize(userID, Ownership{UserID: userID, OrganizationID: orgID}, models.RoleAdmin); err != nil {
+        return nil, err
+    }
+    if mode == "" {
+        mode = ImportModeAllOrNothing
+    }
+    if mode != ImportModeAllOrNothing && mode != ImportModeBestEffort {
+        return nil, errors.NewValidationError("Import mode must be all_or_nothing or best_effort")
+    }
+    if len(rows) == 0 {
+        return nil, errors.NewValidationError("No devices to import")
+    }
+    if len(rows) > MaxImportRows {
+        return nil, errors.NewValidationError(fmt.Sprintf("A single import accepts at most %d devices", MaxImportRows))
+    }
+
+    sns := make([]string, 0, len(rows))
+    for _, row := range rows {
+        sns = append(sns, row.SN)
+    }
+    existing, err := s.deviceRepo.FindBySNs(sns)
+    if err != nil {
+        return nil, errors.ErrDatabaseError
+    }
+    taken := make(map[string]bool, len(existing))
+    for _, device := range existing {
+        taken[device.SN] = true
+    }
+
+    response := &dto.DeviceImportResponse{
+        DryRun:  dryRun,
+        Mode:    mode,
+        Total:   len(rows),
+        Results: make([]dto.DeviceImportResult, len(rows)),
+    }
+
+    seen := make(map[string]int, len(rows))
+    pending := make([]*models.Device, 0, len(rows))
+    pendingRows := make([]int, 0, len(rows))
+
+    for i, row := range rows {
+        result := dto.DeviceImportResult{Row: i + 1, SN: row.SN}
+
+        err := validateDeviceFields(row.Name, row.Location, row.SN)
+        if err == nil && taken[row.SN] {
+            err = errors.ErrDeviceAlreadyExists
+        }
+        if err == nil {
+            if previous, duplicated := seen[row.SN]; duplicated {
+                err = errors.NewValidationError(fmt.Sprintf("Serial number already used in row %d", previous))
+            }
+        }
+
+        if err != nil {
+            result.Status = ImportStatusFailed
+            result.Error = err.Error()
+            response.Failed++
+        } else {
+            seen[row.SN] = i + 1
+            result.Status = ImportStatusValid
+            response.Valid++
+            pending = append(pending, &models.Device{
+                UUID:        uuid.New(),
+                Name:        row.Name,
+                Location:    row.Location,
+                SN:          row.SN,
+                Description: row.Description,
+                Labels:         models.Labels{},
+                UserID:         userID,
+                OrganizationID: orgID,
+                CreatedAt:      time.Now(),
+                UpdatedAt:      time.Now(),
+            })
+            pendingRows = append(pendingRows, i)
+        }
+
+        response.Results[i] = result
+    }
+
+    if dryRun {
+        return response, nil
+    }
+
+    if mode == ImportModeAllOrNothing {
+        if response.Failed > 0 {
+            for _, i := range pendingRows {
+                response.Results[i].Status = ImportStatusSkipped
+            }
+            return response, nil
+        }
+
+        if err := s.deviceRepo.CreateBatch(pending); err != nil {
+            return nil, errors.ErrDatabaseError
+        }
+        for n, i := range pendingRows {
+            deviceID := pending[n].UUID
+            response.Results[i].Status = ImportStatusCreated
+            response.Results[i].DeviceID = &deviceID
+        }
+        response.Created = len(pending)
+        return response, nil
+    }
+
+    for n, i := range pendingRows {
+        if err := s.deviceRepo.Create(pending[n]); err != nil {
+            response.Results[i].Status = ImportStatusFailed
+            response.Results[i].Error = errors.ErrDatabaseError.Error()
+            // The row was counted as valid; it is now failed instead.
+            response.Valid--
+            response.Failed++
+            continue
+        }
+        deviceID := pending[n].UUID
+        response.Results[i].Status = ImportStatusCreated
+        response.Results[i].DeviceID = &deviceID
+        response.Created++
+    }
+
+    return response, nil
+}
+
+func validateDeviceFields(name, location, sn string) error {
+    if name == "" {
+        return errors.NewValidationError("Device name is required")
+    }
+    if location == "" {
+        return errors.NewValidationError("Device location is required")
+    }
+    if sn == "" {
+        return errors.NewValidationError("Device serial number is required")
+    }
+
+    if !isValidSN(sn) {
+        return errors.NewValidationError("Serial number must be exactly 12 digits")
+    }
+    return nil
+}
+
 func isValidSN(sn string) bool {
     match, _ := regexp.MatchString(`^\d{12}$`, sn)
     return match
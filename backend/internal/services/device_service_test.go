@@ -3,8 +3,12 @@ package services
 import (
 	"errors"
 	"testing"
+	"time"
 
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/dto"
 	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/models"
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/repository"
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/utils"
 	custom_errors "github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/utils/errors"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
@@ -37,6 +41,14 @@ func (m *MockDeviceRepository) FindByID(id uuid.UUID) (*models.Device, error) {
 	return args.Get(0).(*models.Device), args.Error(1)
 }
 
+func (m *MockDeviceRepository) FindByIDs(ids []uuid.UUID) ([]models.Device, error) {
+	args := m.Called(ids)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Device), args.Error(1)
+}
+
 func (m *MockDeviceRepository) FindByUserID(userID uuid.UUID) ([]models.Device, error) {
 	args := m.Called(userID)
 	if args.Get(0) == nil {
@@ -50,23 +62,119 @@ func (m *MockDeviceRepository) Update(device *models.Device) error {
 	return args.Error(0)
 }
 
-func (m *MockDeviceRepository) Delete(id uuid.UUID) error {
+func (m *MockDeviceRepository) Delete(id uuid.UUID, deleteHeartbeats bool) error {
+	args := m.Called(id, deleteHeartbeats)
+	return args.Error(0)
+}
+
+func (m *MockDeviceRepository) FindDeleted(userID uuid.UUID, deletedAfter time.Time) ([]models.Device, error) {
+	args := m.Called(userID, deletedAfter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Device), args.Error(1)
+}
+
+func (m *MockDeviceRepository) FindDeletedByID(id uuid.UUID) (*models.Device, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Device), args.Error(1)
+}
+
+func (m *MockDeviceRepository) Restore(id uuid.UUID) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MockDeviceRepository) Purge(id uuid.UUID) error {
 	args := m.Called(id)
 	return args.Error(0)
 }
 
+func (m *MockDeviceRepository) FindPurgeable(deletedBefore time.Time) ([]uuid.UUID, error) {
+	args := m.Called(deletedBefore)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]uuid.UUID), args.Error(1)
+}
+
+func (m *MockDeviceRepository) FindBySNs(sns []string) ([]models.Device, error) {
+	args := m.Called(sns)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Device), args.Error(1)
+}
+
+func (m *MockDeviceRepository) CreateBatch(devices []*models.Device) error {
+	args := m.Called(devices)
+	return args.Error(0)
+}
+
+func (m *MockDeviceRepository) Search(userID uuid.UUID, filter repository.DeviceFilter) ([]models.Device, int64, error) {
+	args := m.Called(userID, filter)
+	if args.Get(0) == nil {
+		return nil, 0, args.Error(2)
+	}
+	return args.Get(0).([]models.Device), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockDeviceRepository) UpdateLastSeen(id uuid.UUID, seenAt time.Time) error {
+	args := m.Called(id, seenAt)
+	return args.Error(0)
+}
+
+func (m *MockDeviceRepository) UpdateLabels(id uuid.UUID, labels models.Labels) error {
+	args := m.Called(id, labels)
+	return args.Error(0)
+}
+
+func (m *MockDeviceRepository) FindByGroupIDs(userID uuid.UUID, groupIDs []uuid.UUID) ([]models.Device, error) {
+	args := m.Called(userID, groupIDs)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Device), args.Error(1)
+}
+
+func (m *MockDeviceRepository) SetGroup(deviceIDs []uuid.UUID, groupID *uuid.UUID) error {
+	args := m.Called(deviceIDs, groupID)
+	return args.Error(0)
+}
+
+func (m *MockDeviceRepository) SetOrganization(deviceIDs []uuid.UUID, organizationID uuid.UUID) error {
+	args := m.Called(deviceIDs, organizationID)
+	return args.Error(0)
+}
+
+func (m *MockDeviceRepository) UpdateFirmwareVersion(id uuid.UUID, version string) error {
+	args := m.Called(id, version)
+	return args.Error(0)
+}
+
+func (m *MockDeviceRepository) CountByFirmwareVersion(userID uuid.UUID) ([]repository.FirmwareVersionCount, error) {
+	args := m.Called(userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]repository.FirmwareVersionCount), args.Error(1)
+}
+
 func TestDeviceService_CreateDevice(t *testing.T) {
 	userID := uuid.New()
 	validSN := "123456789012"
 
 	t.Run("Success - Valid device creation", func(t *testing.T) {
 		mockRepo := new(MockDeviceRepository)
-		service := NewDeviceService(mockRepo)
+		service := NewDeviceService(mockRepo, new(MockDeviceGroupRepository), DefaultDeviceDeletePolicy, NewAuthorizer(new(MockOrganizationRepository), noDeviceShares()))
 
 		mockRepo.On("FindBySN", validSN).Return((*models.Device)(nil), gorm.ErrRecordNotFound)
 		mockRepo.On("Create", mock.AnythingOfType("*models.Device")).Return(nil)
 
-		device, err := service.CreateDevice(userID, "Test Device", "Test Location", validSN, "Test Description")
+		device, err := service.CreateDevice(userID, nil, "Test Device", "Test Location", validSN, "Test Description")
 
 		assert.NoError(t, err)
 		assert.NotNil(t, device)
@@ -81,9 +189,9 @@ func TestDeviceService_CreateDevice(t *testing.T) {
 
 	t.Run("Error - Empty device name", func(t *testing.T) {
 		mockRepo := new(MockDeviceRepository)
-		service := NewDeviceService(mockRepo)
+		service := NewDeviceService(mockRepo, new(MockDeviceGroupRepository), DefaultDeviceDeletePolicy, NewAuthorizer(new(MockOrganizationRepository), noDeviceShares()))
 
-		device, err := service.CreateDevice(userID, "", "Test Location", validSN, "Test Description")
+		device, err := service.CreateDevice(userID, nil, "", "Test Location", validSN, "Test Description")
 
 		assert.Error(t, err)
 		assert.Equal(t, custom_errors.NewValidationError("Device name is required"), err)
@@ -92,9 +200,9 @@ func TestDeviceService_CreateDevice(t *testing.T) {
 
 	t.Run("Error - Empty location", func(t *testing.T) {
 		mockRepo := new(MockDeviceRepository)
-		service := NewDeviceService(mockRepo)
+		service := NewDeviceService(mockRepo, new(MockDeviceGroupRepository), DefaultDeviceDeletePolicy, NewAuthorizer(new(MockOrganizationRepository), noDeviceShares()))
 
-		device, err := service.CreateDevice(userID, "Test Device", "", validSN, "Test Description")
+		device, err := service.CreateDevice(userID, nil, "Test Device", "", validSN, "Test Description")
 
 		assert.Error(t, err)
 		assert.Equal(t, custom_errors.NewValidationError("Device location is required"), err)
@@ -103,9 +211,9 @@ func TestDeviceService_CreateDevice(t *testing.T) {
 
 	t.Run("Error - Empty SN", func(t *testing.T) {
 		mockRepo := new(MockDeviceRepository)
-		service := NewDeviceService(mockRepo)
+		service := NewDeviceService(mockRepo, new(MockDeviceGroupRepository), DefaultDeviceDeletePolicy, NewAuthorizer(new(MockOrganizationRepository), noDeviceShares()))
 
-		device, err := service.CreateDevice(userID, "Test Device", "Test Location", "", "Test Description")
+		device, err := service.CreateDevice(userID, nil, "Test Device", "Test Location", "", "Test Description")
 
 		assert.Error(t, err)
 		assert.Equal(t, custom_errors.NewValidationError("Device serial number is required"), err)
@@ -114,9 +222,9 @@ func TestDeviceService_CreateDevice(t *testing.T) {
 
 	t.Run("Error - Invalid SN format", func(t *testing.T) {
 		mockRepo := new(MockDeviceRepository)
-		service := NewDeviceService(mockRepo)
+		service := NewDeviceService(mockRepo, new(MockDeviceGroupRepository), DefaultDeviceDeletePolicy, NewAuthorizer(new(MockOrganizationRepository), noDeviceShares()))
 
-		device, err := service.CreateDevice(userID, "Test Device", "Test Location", "123", "Test Description")
+		device, err := service.CreateDevice(userID, nil, "Test Device", "Test Location", "123", "Test Description")
 
 		assert.Error(t, err)
 		assert.Equal(t, custom_errors.NewValidationError("Serial number must be exactly 12 digits"), err)
@@ -125,12 +233,12 @@ func TestDeviceService_CreateDevice(t *testing.T) {
 
 	t.Run("Error - SN already exists", func(t *testing.T) {
 		mockRepo := new(MockDeviceRepository)
-		service := NewDeviceService(mockRepo)
+		service := NewDeviceService(mockRepo, new(MockDeviceGroupRepository), DefaultDeviceDeletePolicy, NewAuthorizer(new(MockOrganizationRepository), noDeviceShares()))
 
 		existingDevice := &models.Device{SN: validSN}
 		mockRepo.On("FindBySN", validSN).Return(existingDevice, nil)
 
-		device, err := service.CreateDevice(userID, "Test Device", "Test Location", validSN, "Test Description")
+		device, err := service.CreateDevice(userID, nil, "Test Device", "Test Location", validSN, "Test Description")
 
 		assert.Error(t, err)
 		assert.Equal(t, custom_errors.ErrDeviceAlreadyExists, err)
@@ -141,11 +249,11 @@ func TestDeviceService_CreateDevice(t *testing.T) {
 
 	t.Run("Error - Database error on FindBySN", func(t *testing.T) {
 		mockRepo := new(MockDeviceRepository)
-		service := NewDeviceService(mockRepo)
+		service := NewDeviceService(mockRepo, new(MockDeviceGroupRepository), DefaultDeviceDeletePolicy, NewAuthorizer(new(MockOrganizationRepository), noDeviceShares()))
 
 		mockRepo.On("FindBySN", validSN).Return((*models.Device)(nil), errors.New("database error"))
 
-		device, err := service.CreateDevice(userID, "Test Device", "Test Location", validSN, "Test Description")
+		device, err := service.CreateDevice(userID, nil, "Test Device", "Test Location", validSN, "Test Description")
 
 		assert.Error(t, err)
 		assert.Equal(t, custom_errors.ErrDatabaseError, err)
@@ -156,12 +264,12 @@ func TestDeviceService_CreateDevice(t *testing.T) {
 
 	t.Run("Error - Database error on Create", func(t *testing.T) {
 		mockRepo := new(MockDeviceRepository)
-		service := NewDeviceService(mockRepo)
+		service := NewDeviceService(mockRepo, new(MockDeviceGroupRepository), DefaultDeviceDeletePolicy, NewAuthorizer(new(MockOrganizationRepository), noDeviceShares()))
 
 		mockRepo.On("FindBySN", validSN).Return((*models.Device)(nil), gorm.ErrRecordNotFound)
 		mockRepo.On("Create", mock.AnythingOfType("*models.Device")).Return(errors.New("database error"))
 
-		device, err := service.CreateDevice(userID, "Test Device", "Test Location", validSN, "Test Description")
+		device, err := service.CreateDevice(userID, nil, "Test Device", "Test Location", validSN, "Test Description")
 
 		assert.Error(t, err)
 		assert.Equal(t, custom_errors.ErrDatabaseError, err)
@@ -169,6 +277,39 @@ func TestDeviceService_CreateDevice(t *testing.T) {
 
 		mockRepo.AssertExpectations(t)
 	})
+
+	t.Run("Success - Organization device created by admin", func(t *testing.T) {
+		mockRepo := new(MockDeviceRepository)
+		mockOrgRepo := new(MockOrganizationRepository)
+		service := NewDeviceService(mockRepo, new(MockDeviceGroupRepository), DefaultDeviceDeletePolicy, NewAuthorizer(mockOrgRepo, noDeviceShares()))
+
+		orgID := uuid.New()
+		mockOrgRepo.On("FindMembership", orgID, userID).Return(&models.Membership{Role: models.RoleAdmin}, nil)
+		mockRepo.On("FindBySN", validSN).Return((*models.Device)(nil), gorm.ErrRecordNotFound)
+		mockRepo.On("Create", mock.AnythingOfType("*models.Device")).Return(nil)
+
+		device, err := service.CreateDevice(userID, &orgID, "Test Device", "Test Location", validSN, "Test Description")
+
+		assert.NoError(t, err)
+		assert.Equal(t, &orgID, device.OrganizationID)
+
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Error - Operator cannot create organization device", func(t *testing.T) {
+		mockRepo := new(MockDeviceRepository)
+		mockOrgRepo := new(MockOrganizationRepository)
+		service := NewDeviceService(mockRepo, new(MockDeviceGroupRepository), DefaultDeviceDeletePolicy, NewAuthorizer(mockOrgRepo, noDeviceShares()))
+
+		orgID := uuid.New()
+		mockOrgRepo.On("FindMembership", orgID, userID).Return(&models.Membership{Role: models.RoleOperator}, nil)
+
+		device, err := service.CreateDevice(userID, &orgID, "Test Device", "Test Location", validSN, "Test Description")
+
+		assert.Equal(t, custom_errors.ErrForbidden, err)
+		assert.Nil(t, device)
+		mockRepo.AssertNotCalled(t, "Create", mock.Anything)
+	})
 }
 
 func TestDeviceService_GetDevice(t *testing.T) {
@@ -183,7 +324,7 @@ func TestDeviceService_GetDevice(t *testing.T) {
 
 	t.Run("Success - Get device", func(t *testing.T) {
 		mockRepo := new(MockDeviceRepository)
-		service := NewDeviceService(mockRepo)
+		service := NewDeviceService(mockRepo, new(MockDeviceGroupRepository), DefaultDeviceDeletePolicy, NewAuthorizer(new(MockOrganizationRepository), noDeviceShares()))
 
 		mockRepo.On("FindByID", deviceID).Return(device, nil)
 
@@ -198,7 +339,7 @@ func TestDeviceService_GetDevice(t *testing.T) {
 
 	t.Run("Error - Device not found", func(t *testing.T) {
 		mockRepo := new(MockDeviceRepository)
-		service := NewDeviceService(mockRepo)
+		service := NewDeviceService(mockRepo, new(MockDeviceGroupRepository), DefaultDeviceDeletePolicy, NewAuthorizer(new(MockOrganizationRepository), noDeviceShares()))
 
 		mockRepo.On("FindByID", deviceID).Return((*models.Device)(nil), gorm.ErrRecordNotFound)
 
@@ -213,7 +354,7 @@ func TestDeviceService_GetDevice(t *testing.T) {
 
 	t.Run("Error - Database error", func(t *testing.T) {
 		mockRepo := new(MockDeviceRepository)
-		service := NewDeviceService(mockRepo)
+		service := NewDeviceService(mockRepo, new(MockDeviceGroupRepository), DefaultDeviceDeletePolicy, NewAuthorizer(new(MockOrganizationRepository), noDeviceShares()))
 
 		mockRepo.On("FindByID", deviceID).Return((*models.Device)(nil), errors.New("database error"))
 
@@ -228,7 +369,7 @@ func TestDeviceService_GetDevice(t *testing.T) {
 
 	t.Run("Error - Forbidden (different user)", func(t *testing.T) {
 		mockRepo := new(MockDeviceRepository)
-		service := NewDeviceService(mockRepo)
+		service := NewDeviceService(mockRepo, new(MockDeviceGroupRepository), DefaultDeviceDeletePolicy, NewAuthorizer(new(MockOrganizationRepository), noDeviceShares()))
 
 		mockRepo.On("FindByID", deviceID).Return(device, nil)
 
@@ -251,7 +392,7 @@ func TestDeviceService_ListDevices(t *testing.T) {
 
 	t.Run("Success - List devices", func(t *testing.T) {
 		mockRepo := new(MockDeviceRepository)
-		service := NewDeviceService(mockRepo)
+		service := NewDeviceService(mockRepo, new(MockDeviceGroupRepository), DefaultDeviceDeletePolicy, NewAuthorizer(new(MockOrganizationRepository), noDeviceShares()))
 
 		mockRepo.On("FindByUserID", userID).Return(devices, nil)
 
@@ -266,7 +407,7 @@ func TestDeviceService_ListDevices(t *testing.T) {
 
 	t.Run("Error - Database error", func(t *testing.T) {
 		mockRepo := new(MockDeviceRepository)
-		service := NewDeviceService(mockRepo)
+		service := NewDeviceService(mockRepo, new(MockDeviceGroupRepository), DefaultDeviceDeletePolicy, NewAuthorizer(new(MockOrganizationRepository), noDeviceShares()))
 
 		mockRepo.On("FindByUserID", userID).Return(([]models.Device)(nil), errors.New("database error"))
 
@@ -280,6 +421,311 @@ func TestDeviceService_ListDevices(t *testing.T) {
 	})
 }
 
+func TestDeviceService_SearchDevices(t *testing.T) {
+	userID := uuid.New()
+	devices := []models.Device{
+		{UUID: uuid.New(), Name: "Gateway 1", Location: "SP", UserID: userID},
+	}
+
+	t.Run("Success - Defaults applied", func(t *testing.T) {
+		mockRepo := new(MockDeviceRepository)
+		service := NewDeviceService(mockRepo, new(MockDeviceGroupRepository), DefaultDeviceDeletePolicy, NewAuthorizer(new(MockOrganizationRepository), noDeviceShares()))
+
+		filter := repository.DeviceFilter{SortBy: "created_at"}
+		mockRepo.On("Search", userID, filter).Return(devices, int64(1), nil)
+
+		result, total, err := service.SearchDevices(userID, dto.DeviceListQuery{})
+
+		assert.NoError(t, err)
+		assert.Len(t, result, 1)
+		assert.Equal(t, int64(1), total)
+
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Success - Filters, sort and page forwarded", func(t *testing.T) {
+		mockRepo := new(MockDeviceRepository)
+		service := NewDeviceService(mockRepo, new(MockDeviceGroupRepository), DefaultDeviceDeletePolicy, NewAuthorizer(new(MockOrganizationRepository), noDeviceShares()))
+
+		filter := repository.DeviceFilter{
+			Search:   "gate",
+			Location: "SP",
+			SortBy:   "last_seen",
+			Desc:     true,
+			Limit:    10,
+			Offset:   20,
+		}
+		mockRepo.On("Search", userID, filter).Return(devices, int64(21), nil)
+
+		result, total, err := service.SearchDevices(userID, dto.DeviceListQuery{
+			Search:   "  gate ",
+			Location: "SP",
+			Sort:     "last_seen",
+			Order:    "DESC",
+			Limit:    10,
+			Offset:   20,
+		})
+
+		assert.NoError(t, err)
+		assert.Len(t, result, 1)
+		assert.Equal(t, int64(21), total)
+
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Success - Label selector parsed", func(t *testing.T) {
+		mockRepo := new(MockDeviceRepository)
+		service := NewDeviceService(mockRepo, new(MockDeviceGroupRepository), DefaultDeviceDeletePolicy, NewAuthorizer(new(MockOrganizationRepository), noDeviceShares()))
+
+		filter := repository.DeviceFilter{
+			SortBy: "created_at",
+			Labels: utils.LabelSelector{
+				{Key: "env", Operator: utils.LabelOpEquals, Value: "prod"},
+				{Key: "site", Operator: utils.LabelOpNotEquals, Value: "rj"},
+			},
+		}
+		mockRepo.On("Search", userID, filter).Return(devices, int64(1), nil)
+
+		result, _, err := service.SearchDevices(userID, dto.DeviceListQuery{Labels: "site!=rj,env=prod"})
+
+		assert.NoError(t, err)
+		assert.Len(t, result, 1)
+
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Error - Invalid parameters", func(t *testing.T) {
+		queries := []dto.DeviceListQuery{
+			{Sort: "sn"},
+			{Order: "up"},
+			{Limit: -1},
+			{Offset: -5},
+			{Limit: MaxDevicePageSize + 1},
+			{Labels: "env=prod,=sp"},
+		}
+
+		for _, query := range queries {
+			mockRepo := new(MockDeviceRepository)
+			service := NewDeviceService(mockRepo, new(MockDeviceGroupRepository), DefaultDeviceDeletePolicy, NewAuthorizer(new(MockOrganizationRepository), noDeviceShares()))
+
+			result, total, err := service.SearchDevices(userID, query)
+
+			assert.Error(t, err)
+			assert.IsType(t, &custom_errors.BusinessError{}, err)
+			assert.Nil(t, result)
+			assert.Zero(t, total)
+			mockRepo.AssertNotCalled(t, "Search", mock.Anything, mock.Anything)
+		}
+	})
+
+	t.Run("Error - Database error", func(t *testing.T) {
+		mockRepo := new(MockDeviceRepository)
+		service := NewDeviceService(mockRepo, new(MockDeviceGroupRepository), DefaultDeviceDeletePolicy, NewAuthorizer(new(MockOrganizationRepository), noDeviceShares()))
+
+		mockRepo.On("Search", userID, mock.Anything).Return(nil, int64(0), errors.New("database error"))
+
+		result, _, err := service.SearchDevices(userID, dto.DeviceListQuery{})
+
+		assert.Equal(t, custom_errors.ErrDatabaseError, err)
+		assert.Nil(t, result)
+
+		mockRepo.AssertExpectations(t)
+	})
+}
+
+func TestDeviceService_DeviceLabels(t *testing.T) {
+	userID := uuid.New()
+	deviceID := uuid.New()
+	newDevice := func() *models.Device {
+		return &models.Device{
+			UUID:   deviceID,
+			UserID: userID,
+			Labels: models.Labels{"env": "prod"},
+		}
+	}
+
+	t.Run("Success - Get labels", func(t *testing.T) {
+		mockRepo := new(MockDeviceRepository)
+		service := NewDeviceService(mockRepo, new(MockDeviceGroupRepository), DefaultDeviceDeletePolicy, NewAuthorizer(new(MockOrganizationRepository), noDeviceShares()))
+
+		mockRepo.On("FindByID", deviceID).Return(newDevice(), nil)
+
+		labels, err := service.GetDeviceLabels(userID, deviceID)
+
+		assert.NoError(t, err)
+		assert.Equal(t, models.Labels{"env": "prod"}, labels)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Success - Replace labels", func(t *testing.T) {
+		mockRepo := new(MockDeviceRepository)
+		service := NewDeviceService(mockRepo, new(MockDeviceGroupRepository), DefaultDeviceDeletePolicy, NewAuthorizer(new(MockOrganizationRepository), noDeviceShares()))
+
+		expected := models.Labels{"site": "sp", "rack": "r1"}
+		mockRepo.On("FindByID", deviceID).Return(newDevice(), nil)
+		mockRepo.On("UpdateLabels", deviceID, expected).Return(nil)
+
+		labels, err := service.ReplaceDeviceLabels(userID, deviceID, map[string]string{"site": "sp", "rack": "r1"})
+
+		assert.NoError(t, err)
+		assert.Equal(t, expected, labels)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Success - Set label keeps existing ones", func(t *testing.T) {
+		mockRepo := new(MockDeviceRepository)
+		service := NewDeviceService(mockRepo, new(MockDeviceGroupRepository), DefaultDeviceDeletePolicy, NewAuthorizer(new(MockOrganizationRepository), noDeviceShares()))
+
+		expected := models.Labels{"env": "prod", "site": "sp"}
+		mockRepo.On("FindByID", deviceID).Return(newDevice(), nil)
+		mockRepo.On("UpdateLabels", deviceID, expected).Return(nil)
+
+		labels, err := service.SetDeviceLabel(userID, deviceID, "site", "sp")
+
+		assert.NoError(t, err)
+		assert.Equal(t, expected, labels)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Success - Delete label", func(t *testing.T) {
+		mockRepo := new(MockDeviceRepository)
+		service := NewDeviceService(mockRepo, new(MockDeviceGroupRepository), DefaultDeviceDeletePolicy, NewAuthorizer(new(MockOrganizationRepository), noDeviceShares()))
+
+		mockRepo.On("FindByID", deviceID).Return(newDevice(), nil)
+		mockRepo.On("UpdateLabels", deviceID, models.Labels{}).Return(nil)
+
+		labels, err := service.DeleteDeviceLabel(userID, deviceID, "env")
+
+		assert.NoError(t, err)
+		assert.Empty(t, labels)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Error - Delete unknown label", func(t *testing.T) {
+		mockRepo := new(MockDeviceRepository)
+		service := NewDeviceService(mockRepo, new(MockDeviceGroupRepository), DefaultDeviceDeletePolicy, NewAuthorizer(new(MockOrganizationRepository), noDeviceShares()))
+
+		mockRepo.On("FindByID", deviceID).Return(newDevice(), nil)
+
+		labels, err := service.DeleteDeviceLabel(userID, deviceID, "site")
+
+		assert.Equal(t, custom_errors.ErrLabelNotFound, err)
+		assert.Nil(t, labels)
+		mockRepo.AssertNotCalled(t, "UpdateLabels", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Error - Invalid label key", func(t *testing.T) {
+		mockRepo := new(MockDeviceRepository)
+		service := NewDeviceService(mockRepo, new(MockDeviceGroupRepository), DefaultDeviceDeletePolicy, NewAuthorizer(new(MockOrganizationRepository), noDeviceShares()))
+
+		mockRepo.On("FindByID", deviceID).Return(newDevice(), nil)
+
+		labels, err := service.SetDeviceLabel(userID, deviceID, "bad key", "x")
+
+		assert.Error(t, err)
+		assert.IsType(t, &custom_errors.BusinessError{}, err)
+		assert.Nil(t, labels)
+		mockRepo.AssertNotCalled(t, "UpdateLabels", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Error - Forbidden (different user)", func(t *testing.T) {
+		mockRepo := new(MockDeviceRepository)
+		service := NewDeviceService(mockRepo, new(MockDeviceGroupRepository), DefaultDeviceDeletePolicy, NewAuthorizer(new(MockOrganizationRepository), noDeviceShares()))
+
+		mockRepo.On("FindByID", deviceID).Return(newDevice(), nil)
+
+		labels, err := service.ReplaceDeviceLabels(uuid.New(), deviceID, map[string]string{"env": "dev"})
+
+		assert.Equal(t, custom_errors.ErrForbidden, err)
+		assert.Nil(t, labels)
+		mockRepo.AssertNotCalled(t, "UpdateLabels", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Error - Database error on update", func(t *testing.T) {
+		mockRepo := new(MockDeviceRepository)
+		service := NewDeviceService(mockRepo, new(MockDeviceGroupRepository), DefaultDeviceDeletePolicy, NewAuthorizer(new(MockOrganizationRepository), noDeviceShares()))
+
+		mockRepo.On("FindByID", deviceID).Return(newDevice(), nil)
+		mockRepo.On("UpdateLabels", deviceID, mock.Anything).Return(errors.New("database error"))
+
+		labels, err := service.SetDeviceLabel(userID, deviceID, "site", "sp")
+
+		assert.Equal(t, custom_errors.ErrDatabaseError, err)
+		assert.Nil(t, labels)
+	})
+}
+
+func TestDeviceService_ListDevicesByGroup(t *testing.T) {
+	userID := uuid.New()
+	groupID := uuid.New()
+	childID := uuid.New()
+	group := &models.DeviceGroup{ID: groupID, UserID: userID, Name: "Site SP"}
+	devices := []models.Device{
+		{UUID: uuid.New(), Name: "Device 1", UserID: userID, GroupID: &groupID},
+		{UUID: uuid.New(), Name: "Device 2", UserID: userID, GroupID: &childID},
+	}
+
+	t.Run("Success - Direct members only", func(t *testing.T) {
+		mockRepo := new(MockDeviceRepository)
+		mockGroupRepo := new(MockDeviceGroupRepository)
+		service := NewDeviceService(mockRepo, mockGroupRepo, DefaultDeviceDeletePolicy, NewAuthorizer(new(MockOrganizationRepository), noDeviceShares()))
+
+		mockGroupRepo.On("FindByID", groupID).Return(group, nil)
+		mockRepo.On("FindByGroupIDs", userID, []uuid.UUID{groupID}).Return(devices[:1], nil)
+
+		result, err := service.ListDevicesByGroup(userID, groupID, false)
+
+		assert.NoError(t, err)
+		assert.Len(t, result, 1)
+		mockGroupRepo.AssertNotCalled(t, "FindDescendantIDs", mock.Anything)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Success - Recursive", func(t *testing.T) {
+		mockRepo := new(MockDeviceRepository)
+		mockGroupRepo := new(MockDeviceGroupRepository)
+		service := NewDeviceService(mockRepo, mockGroupRepo, DefaultDeviceDeletePolicy, NewAuthorizer(new(MockOrganizationRepository), noDeviceShares()))
+
+		mockGroupRepo.On("FindByID", groupID).Return(group, nil)
+		mockGroupRepo.On("FindDescendantIDs", groupID).Return([]uuid.UUID{groupID, childID}, nil)
+		mockRepo.On("FindByGroupIDs", userID, []uuid.UUID{groupID, childID}).Return(devices, nil)
+
+		result, err := service.ListDevicesByGroup(userID, groupID, true)
+
+		assert.NoError(t, err)
+		assert.Len(t, result, 2)
+		mockGroupRepo.AssertExpectations(t)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Error - Group not found", func(t *testing.T) {
+		mockRepo := new(MockDeviceRepository)
+		mockGroupRepo := new(MockDeviceGroupRepository)
+		service := NewDeviceService(mockRepo, mockGroupRepo, DefaultDeviceDeletePolicy, NewAuthorizer(new(MockOrganizationRepository), noDeviceShares()))
+
+		mockGroupRepo.On("FindByID", groupID).Return(nil, gorm.ErrRecordNotFound)
+
+		result, err := service.ListDevicesByGroup(userID, groupID, true)
+
+		assert.Equal(t, custom_errors.ErrDeviceGroupNotFound, err)
+		assert.Nil(t, result)
+	})
+
+	t.Run("Error - Forbidden (different user)", func(t *testing.T) {
+		mockRepo := new(MockDeviceRepository)
+		mockGroupRepo := new(MockDeviceGroupRepository)
+		service := NewDeviceService(mockRepo, mockGroupRepo, DefaultDeviceDeletePolicy, NewAuthorizer(new(MockOrganizationRepository), noDeviceShares()))
+
+		mockGroupRepo.On("FindByID", groupID).Return(group, nil)
+
+		result, err := service.ListDevicesByGroup(uuid.New(), groupID, false)
+
+		assert.Equal(t, custom_errors.ErrForbidden, err)
+		assert.Nil(t, result)
+		mockRepo.AssertNotCalled(t, "FindByGroupIDs", mock.Anything, mock.Anything)
+	})
+}
+
 func TestDeviceService_UpdateDevice(t *testing.T) {
 	userID := uuid.New()
 	deviceID := uuid.New()
@@ -293,7 +739,7 @@ func TestDeviceService_UpdateDevice(t *testing.T) {
 
 	t.Run("Success - Update device", func(t *testing.T) {
 		mockRepo := new(MockDeviceRepository)
-		service := NewDeviceService(mockRepo)
+		service := NewDeviceService(mockRepo, new(MockDeviceGroupRepository), DefaultDeviceDeletePolicy, NewAuthorizer(new(MockOrganizationRepository), noDeviceShares()))
 
 		mockRepo.On("FindByID", deviceID).Return(device, nil)
 		mockRepo.On("Update", mock.AnythingOfType("*models.Device")).Return(nil)
@@ -311,7 +757,7 @@ func TestDeviceService_UpdateDevice(t *testing.T) {
 
 	t.Run("Error - Empty device name", func(t *testing.T) {
 		mockRepo := new(MockDeviceRepository)
-		service := NewDeviceService(mockRepo)
+		service := NewDeviceService(mockRepo, new(MockDeviceGroupRepository), DefaultDeviceDeletePolicy, NewAuthorizer(new(MockOrganizationRepository), noDeviceShares()))
 
 		mockRepo.On("FindByID", deviceID).Return(device, nil)
 
@@ -326,7 +772,7 @@ func TestDeviceService_UpdateDevice(t *testing.T) {
 
 	t.Run("Error - Empty location", func(t *testing.T) {
 		mockRepo := new(MockDeviceRepository)
-		service := NewDeviceService(mockRepo)
+		service := NewDeviceService(mockRepo, new(MockDeviceGroupRepository), DefaultDeviceDeletePolicy, NewAuthorizer(new(MockOrganizationRepository), noDeviceShares()))
 
 		mockRepo.On("FindByID", deviceID).Return(device, nil)
 
@@ -341,7 +787,7 @@ func TestDeviceService_UpdateDevice(t *testing.T) {
 
 	t.Run("Error - Device not found", func(t *testing.T) {
 		mockRepo := new(MockDeviceRepository)
-		service := NewDeviceService(mockRepo)
+		service := NewDeviceService(mockRepo, new(MockDeviceGroupRepository), DefaultDeviceDeletePolicy, NewAuthorizer(new(MockOrganizationRepository), noDeviceShares()))
 
 		mockRepo.On("FindByID", deviceID).Return((*models.Device)(nil), gorm.ErrRecordNotFound)
 
@@ -356,7 +802,7 @@ func TestDeviceService_UpdateDevice(t *testing.T) {
 
 	t.Run("Error - Database error on update", func(t *testing.T) {
 		mockRepo := new(MockDeviceRepository)
-		service := NewDeviceService(mockRepo)
+		service := NewDeviceService(mockRepo, new(MockDeviceGroupRepository), DefaultDeviceDeletePolicy, NewAuthorizer(new(MockOrganizationRepository), noDeviceShares()))
 
 		mockRepo.On("FindByID", deviceID).Return(device, nil)
 		mockRepo.On("Update", mock.AnythingOfType("*models.Device")).Return(errors.New("database error"))
@@ -369,6 +815,43 @@ func TestDeviceService_UpdateDevice(t *testing.T) {
 
 		mockRepo.AssertExpectations(t)
 	})
+
+	t.Run("Error - Viewer cannot update organization device", func(t *testing.T) {
+		mockRepo := new(MockDeviceRepository)
+		mockOrgRepo := new(MockOrganizationRepository)
+		service := NewDeviceService(mockRepo, new(MockDeviceGroupRepository), DefaultDeviceDeletePolicy, NewAuthorizer(mockOrgRepo, noDeviceShares()))
+
+		orgID := uuid.New()
+		viewerID := uuid.New()
+		orgDevice := &models.Device{UUID: deviceID, Name: "Old Name", UserID: userID, OrganizationID: &orgID}
+		mockRepo.On("FindByID", deviceID).Return(orgDevice, nil)
+		mockOrgRepo.On("FindMembership", orgID, viewerID).Return(&models.Membership{Role: models.RoleViewer}, nil)
+
+		result, err := service.UpdateDevice(viewerID, deviceID, "New Name", "New Location", "New Description")
+
+		assert.Equal(t, custom_errors.ErrForbidden, err)
+		assert.Nil(t, result)
+		mockRepo.AssertNotCalled(t, "Update", mock.Anything)
+	})
+
+	t.Run("Success - Operator updates organization device", func(t *testing.T) {
+		mockRepo := new(MockDeviceRepository)
+		mockOrgRepo := new(MockOrganizationRepository)
+		service := NewDeviceService(mockRepo, new(MockDeviceGroupRepository), DefaultDeviceDeletePolicy, NewAuthorizer(mockOrgRepo, noDeviceShares()))
+
+		orgID := uuid.New()
+		operatorID := uuid.New()
+		orgDevice := &models.Device{UUID: deviceID, Name: "Old Name", UserID: userID, OrganizationID: &orgID}
+		mockRepo.On("FindByID", deviceID).Return(orgDevice, nil)
+		mockRepo.On("Update", mock.AnythingOfType("*models.Device")).Return(nil)
+		mockOrgRepo.On("FindMembership", orgID, operatorID).Return(&models.Membership{Role: models.RoleOperator}, nil)
+
+		result, err := service.UpdateDevice(operatorID, deviceID, "New Name", "New Location", "New Description")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "New Name", result.Name)
+		mockRepo.AssertExpectations(t)
+	})
 }
 
 func TestDeviceService_DeleteDevice(t *testing.T) {
@@ -382,10 +865,10 @@ func TestDeviceService_DeleteDevice(t *testing.T) {
 
 	t.Run("Success - Delete device", func(t *testing.T) {
 		mockRepo := new(MockDeviceRepository)
-		service := NewDeviceService(mockRepo)
+		service := NewDeviceService(mockRepo, new(MockDeviceGroupRepository), DefaultDeviceDeletePolicy, NewAuthorizer(new(MockOrganizationRepository), noDeviceShares()))
 
 		mockRepo.On("FindByID", deviceID).Return(device, nil)
-		mockRepo.On("Delete", deviceID).Return(nil)
+		mockRepo.On("Delete", deviceID, false).Return(nil)
 
 		err := service.DeleteDevice(userID, deviceID)
 
@@ -396,7 +879,7 @@ func TestDeviceService_DeleteDevice(t *testing.T) {
 
 	t.Run("Error - Device not found", func(t *testing.T) {
 		mockRepo := new(MockDeviceRepository)
-		service := NewDeviceService(mockRepo)
+		service := NewDeviceService(mockRepo, new(MockDeviceGroupRepository), DefaultDeviceDeletePolicy, NewAuthorizer(new(MockOrganizationRepository), noDeviceShares()))
 
 		mockRepo.On("FindByID", deviceID).Return((*models.Device)(nil), gorm.ErrRecordNotFound)
 
@@ -410,10 +893,10 @@ func TestDeviceService_DeleteDevice(t *testing.T) {
 
 	t.Run("Error - Database error on delete", func(t *testing.T) {
 		mockRepo := new(MockDeviceRepository)
-		service := NewDeviceService(mockRepo)
+		service := NewDeviceService(mockRepo, new(MockDeviceGroupRepository), DefaultDeviceDeletePolicy, NewAuthorizer(new(MockOrganizationRepository), noDeviceShares()))
 
 		mockRepo.On("FindByID", deviceID).Return(device, nil)
-		mockRepo.On("Delete", deviceID).Return(errors.New("database error"))
+		mockRepo.On("Delete", deviceID, false).Return(errors.New("database error"))
 
 		err := service.DeleteDevice(userID, deviceID)
 
@@ -424,6 +907,245 @@ func TestDeviceService_DeleteDevice(t *testing.T) {
 	})
 }
 
+func TestDeviceService_DeleteDevicePolicy(t *testing.T) {
+	userID := uuid.New()
+	deviceID := uuid.New()
+	device := &models.Device{UUID: deviceID, UserID: userID}
+
+	t.Run("Success - Heartbeats deleted with the device", func(t *testing.T) {
+		mockRepo := new(MockDeviceRepository)
+		policy := DeviceDeletePolicy{RestoreWindow: time.Hour, Heartbeats: HeartbeatsDelete}
+		service := NewDeviceService(mockRepo, new(MockDeviceGroupRepository), policy, NewAuthorizer(new(MockOrganizationRepository), noDeviceShares()))
+
+		mockRepo.On("FindByID", deviceID).Return(device, nil)
+		mockRepo.On("Delete", deviceID, true).Return(nil)
+
+		err := service.DeleteDevice(userID, deviceID)
+
+		assert.NoError(t, err)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Error - Serial number of a deleted device", func(t *testing.T) {
+		mockRepo := new(MockDeviceRepository)
+		service := NewDeviceService(mockRepo, new(MockDeviceGroupRepository), DefaultDeviceDeletePolicy, NewAuthorizer(new(MockOrganizationRepository), noDeviceShares()))
+
+		deleted := &models.Device{UUID: deviceID, UserID: userID, SN: "123456789012", DeletedAt: gorm.DeletedAt{Time: time.Now(), Valid: true}}
+		mockRepo.On("FindBySN", "123456789012").Return(deleted, nil)
+
+		result, err := service.CreateDevice(userID, nil, "Device", "Lab", "123456789012", "")
+
+		assert.Nil(t, result)
+		assert.Equal(t, custom_errors.ErrDeviceAwaitingPurge, err)
+		mockRepo.AssertNotCalled(t, "Create", mock.Anything)
+	})
+}
+
+func TestDeviceService_RestoreDevice(t *testing.T) {
+	userID := uuid.New()
+	deviceID := uuid.New()
+	policy := DeviceDeletePolicy{RestoreWindow: 24 * time.Hour, Heartbeats: HeartbeatsKeep}
+
+	t.Run("Success - Restore within window", func(t *testing.T) {
+		mockRepo := new(MockDeviceRepository)
+		service := NewDeviceService(mockRepo, new(MockDeviceGroupRepository), policy, NewAuthorizer(new(MockOrganizationRepository), noDeviceShares()))
+
+		deleted := &models.Device{UUID: deviceID, UserID: userID, DeletedAt: gorm.DeletedAt{Time: time.Now().Add(-time.Hour), Valid: true}}
+		mockRepo.On("FindDeletedByID", deviceID).Return(deleted, nil)
+		mockRepo.On("Restore", deviceID).Return(nil)
+
+		result, err := service.RestoreDevice(userID, deviceID)
+
+		assert.NoError(t, err)
+		assert.False(t, result.DeletedAt.Valid)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Error - Restore window is over", func(t *testing.T) {
+		mockRepo := new(MockDeviceRepository)
+		service := NewDeviceService(mockRepo, new(MockDeviceGroupRepository), policy, NewAuthorizer(new(MockOrganizationRepository), noDeviceShares()))
+
+		deleted := &models.Device{UUID: deviceID, UserID: userID, DeletedAt: gorm.DeletedAt{Time: time.Now().Add(-48 * time.Hour), Valid: true}}
+		mockRepo.On("FindDeletedByID", deviceID).Return(deleted, nil)
+
+		result, err := service.RestoreDevice(userID, deviceID)
+
+		assert.Nil(t, result)
+		assert.Equal(t, custom_errors.ErrDeviceNotFound, err)
+		mockRepo.AssertNotCalled(t, "Restore", mock.Anything)
+	})
+
+	t.Run("Error - Deleted device of another user", func(t *testing.T) {
+		mockRepo := new(MockDeviceRepository)
+		service := NewDeviceService(mockRepo, new(MockDeviceGroupRepository), policy, NewAuthorizer(new(MockOrganizationRepository), noDeviceShares()))
+
+		deleted := &models.Device{UUID: deviceID, UserID: uuid.New(), DeletedAt: gorm.DeletedAt{Time: time.Now(), Valid: true}}
+		mockRepo.On("FindDeletedByID", deviceID).Return(deleted, nil)
+
+		result, err := service.RestoreDevice(userID, deviceID)
+
+		assert.Nil(t, result)
+		assert.Equal(t, custom_errors.ErrForbidden, err)
+	})
+}
+
+func TestDeviceService_PurgeDeletedDevices(t *testing.T) {
+	t.Run("Success - Purge devices past the restore window", func(t *testing.T) {
+		mockRepo := new(MockDeviceRepository)
+		policy := DeviceDeletePolicy{RestoreWindow: 24 * time.Hour, Heartbeats: HeartbeatsKeep}
+		service := NewDeviceService(mockRepo, new(MockDeviceGroupRepository), policy, NewAuthorizer(new(MockOrganizationRepository), noDeviceShares()))
+
+		first, second := uuid.New(), uuid.New()
+		mockRepo.On("FindPurgeable", mock.MatchedBy(func(before time.Time) bool {
+			return before.Before(time.Now().Add(-23 * time.Hour))
+		})).Return([]uuid.UUID{first, second}, nil)
+		mockRepo.On("Purge", first).Return(nil)
+		mockRepo.On("Purge", second).Return(gorm.ErrRecordNotFound)
+
+		purged, err := service.PurgeDeletedDevices()
+
+		assert.NoError(t, err)
+		assert.Equal(t, 1, purged)
+		mockRepo.AssertExpectations(t)
+	})
+}
+
+func TestDeviceService_ImportDevices(t *testing.T) {
+	userID := uuid.New()
+	rows := []dto.CreateDeviceRequest{
+		{Name: "Device 1", Location: "SP", SN: "123456789012"},
+		{Name: "Device 2", Location: "SP", SN: "123456789013"},
+	}
+	sns := []string{"123456789012", "123456789013"}
+
+	t.Run("Success - All or nothing creates every row", func(t *testing.T) {
+		mockRepo := new(MockDeviceRepository)
+		service := NewDeviceService(mockRepo, new(MockDeviceGroupRepository), DefaultDeviceDeletePolicy, NewAuthorizer(new(MockOrganizationRepository), noDeviceShares()))
+
+		mockRepo.On("FindBySNs", sns).Return([]models.Device{}, nil)
+		mockRepo.On("CreateBatch", mock.AnythingOfType("[]*models.Device")).Return(nil)
+
+		report, err := service.ImportDevices(userID, nil, rows, "", false)
+
+		assert.NoError(t, err)
+		assert.Equal(t, ImportModeAllOrNothing, report.Mode)
+		assert.Equal(t, 2, report.Created)
+		assert.Equal(t, 0, report.Failed)
+		assert.Equal(t, ImportStatusCreated, report.Results[0].Status)
+		assert.NotNil(t, report.Results[0].DeviceID)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Success - Dry run only validates", func(t *testing.T) {
+		mockRepo := new(MockDeviceRepository)
+		service := NewDeviceService(mockRepo, new(MockDeviceGroupRepository), DefaultDeviceDeletePolicy, NewAuthorizer(new(MockOrganizationRepository), noDeviceShares()))
+
+		mockRepo.On("FindBySNs", sns).Return([]models.Device{}, nil)
+
+		report, err := service.ImportDevices(userID, nil, rows, ImportModeBestEffort, true)
+
+		assert.NoError(t, err)
+		assert.True(t, report.DryRun)
+		assert.Equal(t, 2, report.Valid)
+		assert.Equal(t, 0, report.Created)
+		assert.Equal(t, ImportStatusValid, report.Results[1].Status)
+		mockRepo.AssertNotCalled(t, "CreateBatch", mock.Anything)
+		mockRepo.AssertNotCalled(t, "Create", mock.Anything)
+	})
+
+	t.Run("All or nothing rejects the batch on an invalid row", func(t *testing.T) {
+		mockRepo := new(MockDeviceRepository)
+		service := NewDeviceService(mockRepo, new(MockDeviceGroupRepository), DefaultDeviceDeletePolicy, NewAuthorizer(new(MockOrganizationRepository), noDeviceShares()))
+
+		invalid := append([]dto.CreateDeviceRequest{}, rows...)
+		invalid = append(invalid, dto.CreateDeviceRequest{Name: "Device 3", Location: "SP", SN: "12345"})
+		mockRepo.On("FindBySNs", []string{"123456789012", "123456789013", "12345"}).Return([]models.Device{}, nil)
+
+		report, err := service.ImportDevices(userID, nil, invalid, ImportModeAllOrNothing, false)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 0, report.Created)
+		assert.Equal(t, 1, report.Failed)
+		assert.Equal(t, ImportStatusSkipped, report.Results[0].Status)
+		assert.Equal(t, ImportStatusFailed, report.Results[2].Status)
+		assert.Equal(t, "Serial number must be exactly 12 digits", report.Results[2].Error)
+		mockRepo.AssertNotCalled(t, "CreateBatch", mock.Anything)
+	})
+
+	t.Run("Best effort creates valid rows and reports duplicates", func(t *testing.T) {
+		mockRepo := new(MockDeviceRepository)
+		service := NewDeviceService(mockRepo, new(MockDeviceGroupRepository), DefaultDeviceDeletePolicy, NewAuthorizer(new(MockOrganizationRepository), noDeviceShares()))
+
+		batch := []dto.CreateDeviceRequest{
+			rows[0],
+			rows[1],
+			{Name: "Device 1 again", Location: "RJ", SN: "123456789012"},
+			{Name: "Existing", Location: "RJ", SN: "999999999999"},
+		}
+		mockRepo.On("FindBySNs", []string{"123456789012", "123456789013", "123456789012", "999999999999"}).
+			Return([]models.Device{{SN: "999999999999"}}, nil)
+		mockRepo.On("Create", mock.AnythingOfType("*models.Device")).Return(nil)
+
+		report, err := service.ImportDevices(userID, nil, batch, ImportModeBestEffort, false)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 2, report.Created)
+		assert.Equal(t, 2, report.Failed)
+		assert.Equal(t, "Serial number already used in row 1", report.Results[2].Error)
+		assert.Equal(t, custom_errors.ErrDeviceAlreadyExists.Error(), report.Results[3].Error)
+		mockRepo.AssertNumberOfCalls(t, "Create", 2)
+	})
+
+	t.Run("Best effort reports database errors per row", func(t *testing.T) {
+		mockRepo := new(MockDeviceRepository)
+		service := NewDeviceService(mockRepo, new(MockDeviceGroupRepository), DefaultDeviceDeletePolicy, NewAuthorizer(new(MockOrganizationRepository), noDeviceShares()))
+
+		mockRepo.On("FindBySNs", sns).Return([]models.Device{}, nil)
+		mockRepo.On("Create", mock.AnythingOfType("*models.Device")).Return(errors.New("db error")).Once()
+		mockRepo.On("Create", mock.AnythingOfType("*models.Device")).Return(nil).Once()
+
+		report, err := service.ImportDevices(userID, nil, rows, ImportModeBestEffort, false)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 1, report.Created)
+		assert.Equal(t, 1, report.Failed)
+		assert.Equal(t, ImportStatusFailed, report.Results[0].Status)
+		assert.Equal(t, 1, report.Valid)
+		assert.Equal(t, report.Total, report.Valid+report.Failed)
+	})
+
+	t.Run("Error - Invalid mode", func(t *testing.T) {
+		service := NewDeviceService(new(MockDeviceRepository), new(MockDeviceGroupRepository), DefaultDeviceDeletePolicy, NewAuthorizer(new(MockOrganizationRepository), noDeviceShares()))
+
+		report, err := service.ImportDevices(userID, nil, rows, "partial", false)
+
+		assert.Nil(t, report)
+		assert.Equal(t, custom_errors.NewValidationError("Import mode must be all_or_nothing or best_effort"), err)
+	})
+
+	t.Run("Error - Empty import", func(t *testing.T) {
+		service := NewDeviceService(new(MockDeviceRepository), new(MockDeviceGroupRepository), DefaultDeviceDeletePolicy, NewAuthorizer(new(MockOrganizationRepository), noDeviceShares()))
+
+		report, err := service.ImportDevices(userID, nil, nil, "", false)
+
+		assert.Nil(t, report)
+		assert.Equal(t, custom_errors.NewValidationError("No devices to import"), err)
+	})
+
+	t.Run("Error - Database error on batch create", func(t *testing.T) {
+		mockRepo := new(MockDeviceRepository)
+		service := NewDeviceService(mockRepo, new(MockDeviceGroupRepository), DefaultDeviceDeletePolicy, NewAuthorizer(new(MockOrganizationRepository), noDeviceShares()))
+
+		mockRepo.On("FindBySNs", sns).Return([]models.Device{}, nil)
+		mockRepo.On("CreateBatch", mock.AnythingOfType("[]*models.Device")).Return(errors.New("duplicate key"))
+
+		report, err := service.ImportDevices(userID, nil, rows, ImportModeAllOrNothing, false)
+
+		assert.Nil(t, report)
+		assert.Equal(t, custom_errors.ErrDatabaseError, err)
+	})
+}
+
 func TestIsValidSN(t *testing.T) {
 	t.Run("Valid SN", func(t *testing.T) {
 		assert.True(t, isValidSN("123456789012"))
@@ -0,0 +1,330 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/dto"
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/models"
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/repository"
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/utils/errors"
+	logger "github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/utils/logger"
+	"github.com/google/uuid"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+const (
+	// maxShadowDocumentSize bounds the encoded size of a desired document.
+	maxShadowDocumentSize = 8 * 1024
+	// reportedSaveAttempts is how often a reported update is retried when
+	// it races with another update of the same shadow.
+	reportedSaveAttempts = 3
+)
+
+// shadowSettingRanges lists the settings devices are known to understand and
+// the values they accept. Other keys are passed through to the device as is.
+var shadowSettingRanges = map[string][2]float64{
+	"heartbeat_interval_seconds":      {10, 3600},
+	"temperature_sample_rate_seconds": {1, 3600},
+}
+
+// ShadowDeltaPublisher delivers the pending delta of a shadow to its device.
+type ShadowDeltaPublisher interface {
+	PublishDelta(msg dto.ShadowDeltaMessage) error
+}
+
+type DeviceShadowService interface {
+	GetShadow(userID, deviceID uuid.UUID) (*dto.DeviceShadowResponse, error)
+	UpdateDesired(userID, deviceID uuid.UUID, req dto.DeviceShadowDesiredRequest, merge bool) (*dto.DeviceShadowResponse, error)
+	UpdateReported(deviceID uuid.UUID, reported map[string]interface{}) error
+}
+
+type deviceShadowService struct {
+	shadowRepo repository.DeviceShadowRepository
+	deviceRepo repository.DeviceRepository
+	publisher  ShadowDeltaPublisher
+	authz      Authorizer
+}
+
+func NewDeviceShadowService(shadowRepo repository.DeviceShadowRepository, deviceRepo repository.DeviceRepository, publisher ShadowDeltaPublisher, authz Authorizer) DeviceShadowService {
+	return &deviceShadowService{
+		shadowRepo: shadowRepo,
+		deviceRepo: deviceRepo,
+		publisher:  publisher,
+		authz:      authz,
+	}
+}
+
+// GetShadow returns the shadow of the device. A device whose configuration
+// was never set or reported has an empty shadow at version 0.
+func (s *deviceShadowService) GetShadow(userID, deviceID uuid.UUID) (*dto.DeviceShadowResponse, error) {
+	if err := s.authorizeDevice(userID, deviceID, models.RoleViewer); err != nil {
+		return nil, err
+	}
+
+	shadow, err := s.findShadow(deviceID)
+	if err != nil {
+		return nil, err
+	}
+	return deviceShadowResponse(shadow), nil
+}
+
+// UpdateDesired replaces the desired section, or merges req.Desired into it
+// when merge is set; in a merge a null value removes the key. When
+// req.Version is given the update only applies to that desired version. The
+// resulting delta is pushed to the device.
+func (s *deviceShadowService) UpdateDesired(userID, deviceID uuid.UUID, req dto.DeviceShadowDesiredRequest, merge bool) (*dto.DeviceShadowResponse, error) {
+	if err := s.authorizeDevice(userID, deviceID, models.RoleOperator); err != nil {
+		return nil, err
+	}
+
+	shadow, err := s.findShadow(deviceID)
+	if err != nil {
+		return nil, err
+	}
+	if req.Version != nil && *req.Version != shadow.DesiredVersion {
+		return nil, errors.ErrShadowVersionConflict
+	}
+
+	current := decodeShadowState(shadow.Desired)
+	desired := make(map[string]interface{})
+	if merge {
+		mergeShadowState(desired, current)
+	}
+	mergeShadowState(desired, req.Desired)
+
+	if err := validateDesiredState(desired); err != nil {
+		return nil, err
+	}
+	if reflect.DeepEqual(desired, current) {
+		return deviceShadowResponse(shadow), nil
+	}
+
+	encoded, err := json.Marshal(desired)
+	if err != nil {
+		return nil, errors.NewValidationError("Desired state must be a JSON object")
+	}
+	if len(encoded) > maxShadowDocumentSize {
+		return nil, errors.NewValidationError(fmt.Sprintf("Desired state must not exceed %d bytes", maxShadowDocumentSize))
+	}
+
+	now := time.Now()
+	previousVersion := shadow.DesiredVersion
+	shadow.Desired = datatypes.JSON(encoded)
+	shadow.DesiredVersion++
+	shadow.DesiredUpdatedAt = &now
+	shadow.UpdatedAt = now
+	if err := s.shadowRepo.SaveDesired(shadow, previousVersion); err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.ErrShadowVersionConflict
+		}
+		return nil, errors.ErrDatabaseError
+	}
+
+	response := deviceShadowResponse(shadow)
+	s.publishDelta(shadow, response.Delta)
+	return response, nil
+}
+
+// UpdateReported merges the state a device reported into its shadow. It is
+// called from the ingestion path, so the device is trusted and not
+// authorized. Devices report on boot and after applying a delta; whatever
+// is still pending afterwards is pushed again, which also catches up devices
+// that were offline when the desired state changed.
+func (s *deviceShadowService) UpdateReported(deviceID uuid.UUID, reported map[string]interface{}) error {
+	for attempt := 1; ; attempt++ {
+		shadow, err := s.findShadow(deviceID)
+		if err != nil {
+			return err
+		}
+
+		current := decodeShadowState(shadow.Reported)
+		state := make(map[string]interface{})
+		mergeShadowState(state, current)
+		mergeShadowState(state, reported)
+
+		if !reflect.DeepEqual(state, current) {
+			encoded, err := json.Marshal(state)
+			if err != nil {
+				return errors.NewValidationError("Reported state must be a JSON object")
+			}
+
+			now := time.Now()
+			previousVersion := shadow.ReportedVersion
+			shadow.Reported = datatypes.JSON(encoded)
+			shadow.ReportedVersion++
+			shadow.ReportedUpdatedAt = &now
+			shadow.UpdatedAt = now
+			err = s.shadowRepo.SaveReported(shadow, previousVersion)
+			if err == gorm.ErrRecordNotFound && attempt < reportedSaveAttempts {
+				continue
+			}
+			if err != nil {
+				if err == gorm.ErrRecordNotFound {
+					return errors.ErrShadowVersionConflict
+				}
+				return errors.ErrDatabaseError
+			}
+		}
+
+		s.publishDelta(shadow, shadowDelta(decodeShadowState(shadow.Desired), state))
+		return nil
+	}
+}
+
+func (s *deviceShadowService) authorizeDevice(userID, deviceID uuid.UUID, role string) error {
+	device, err := s.deviceRepo.FindByID(deviceID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return errors.ErrDeviceNotFound
+		}
+		return errors.ErrDatabaseError
+	}
+	return s.authz.Authorize(userID, deviceOwnership(device), role)
+}
+
+func (s *deviceShadowService) findShadow(deviceID uuid.UUID) (*models.DeviceShadow, error) {
+	shadow, err := s.shadowRepo.FindByDeviceID(deviceID)
+	if err == gorm.ErrRecordNotFound {
+		now := time.Now()
+		return &models.DeviceShadow{
+			DeviceID:  deviceID,
+			Desired:   datatypes.JSON("{}"),
+			Reported:  datatypes.JSON("{}"),
+			CreatedAt: now,
+			UpdatedAt: now,
+		}, nil
+	}
+	if err != nil {
+		return nil, errors.ErrDatabaseError
+	}
+	return shadow, nil
+}
+
+// publishDelta pushes a non-empty delta to the device. Delivery is best
+// effort: the desired state is already stored and the delta is pushed again
+// on the next report.
+func (s *deviceShadowService) publishDelta(shadow *models.DeviceShadow, delta map[string]interface{}) {
+	if len(delta) == 0 {
+		return
+	}
+
+	err := s.publisher.PublishDelta(dto.ShadowDeltaMessage{
+		DeviceID:  shadow.DeviceID.String(),
+		Version:   shadow.DesiredVersion,
+		Delta:     delta,
+		Timestamp: time.Now().UTC(),
+	})
+	if err != nil {
+		logger.Logger.Warn("Failed to publish shadow delta", "device_id", shadow.DeviceID, "version", shadow.DesiredVersion, "error", err)
+	}
+}
+
+func validateDesiredState(desired map[string]interface{}) error {
+	for key, bounds := range shadowSettingRanges {
+		value, ok := desired[key]
+		if !ok {
+			continue
+		}
+		number, ok := value.(float64)
+		if !ok || number != float64(int64(number)) || number < bounds[0] || number > bounds[1] {
+			return errors.NewValidationError(fmt.Sprintf("%s must be a whole number between %g and %g", key, bounds[0], bounds[1]))
+		}
+	}
+	return nil
+}
+
+// mergeShadowState merges patch into state. Nested objects are merged key by
+// key and a null value removes the key.
+func mergeShadowState(state, patch map[string]interface{}) {
+	for key, value := range patch {
+		if value == nil {
+			delete(state, key)
+			continue
+		}
+		if nested, ok := value.(map[string]interface{}); ok {
+			current, ok := state[key].(map[string]interface{})
+			if !ok {
+				current = make(map[string]interface{})
+			}
+			mergeShadowState(current, nested)
+			state[key] = current
+			continue
+		}
+		state[key] = value
+	}
+}
+
+// shadowDelta returns the desired values that differ from the reported ones.
+// Nested objects are compared key by key.
+func shadowDelta(desired, reported map[string]interface{}) map[string]interface{} {
+	delta := make(map[string]interface{})
+	for key, value := range desired {
+		current, ok := reported[key]
+		if nested, isMap := value.(map[string]interface{}); isMap {
+			if currentNested, isMap := current.(map[string]interface{}); isMap {
+				if nestedDelta := shadowDelta(nested, currentNested); len(nestedDelta) > 0 {
+					delta[key] = nestedDelta
+				}
+				continue
+			}
+		}
+		if !ok || !reflect.DeepEqual(value, current) {
+			delta[key] = value
+		}
+	}
+	return delta
+}
+
+func decodeShadowState(document datatypes.JSON) map[string]interface{} {
+	state := make(map[string]interface{})
+	if len(document) > 0 {
+		if err := json.Unmarshal(document, &state); err != nil || state == nil {
+			return make(map[string]interface{})
+		}
+	}
+	return state
+}
+
+// heartbeatIntervals resolves the heartbeat period of each device from its
+// shadow: the heartbeat_interval_seconds the device reported, else the one it
+// was asked to use. Devices that set neither get fallback.
+func heartbeatIntervals(shadowRepo repository.DeviceShadowRepository, deviceIDs []uuid.UUID, fallback time.Duration) (map[uuid.UUID]time.Duration, error) {
+	shadows, err := shadowRepo.FindByDeviceIDs(deviceIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	intervals := make(map[uuid.UUID]time.Duration, len(deviceIDs))
+	for _, deviceID := range deviceIDs {
+		intervals[deviceID] = fallback
+	}
+	for _, shadow := range shadows {
+		for _, document := range []datatypes.JSON{shadow.Reported, shadow.Desired} {
+			seconds, ok := decodeShadowState(document)["heartbeat_interval_seconds"].(float64)
+			if ok && seconds > 0 {
+				intervals[shadow.DeviceID] = time.Duration(seconds * float64(time.Second))
+				break
+			}
+		}
+	}
+	return intervals, nil
+}
+
+func deviceShadowResponse(shadow *models.DeviceShadow) *dto.DeviceShadowResponse {
+	desired := decodeShadowState(shadow.Desired)
+	reported := decodeShadowState(shadow.Reported)
+	return &dto.DeviceShadowResponse{
+		DeviceID:          shadow.DeviceID,
+		Desired:           desired,
+		Reported:          reported,
+		Delta:             shadowDelta(desired, reported),
+		DesiredVersion:    shadow.DesiredVersion,
+		ReportedVersion:   shadow.ReportedVersion,
+		DesiredUpdatedAt:  shadow.DesiredUpdatedAt,
+		ReportedUpdatedAt: shadow.ReportedUpdatedAt,
+	}
+}
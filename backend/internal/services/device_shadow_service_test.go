@@ -0,0 +1,325 @@
+package services
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/dto"
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/models"
+	custom_errors "github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/utils/errors"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+type MockDeviceShadowRepository struct {
+	mock.Mock
+}
+
+func (m *MockDeviceShadowRepository) FindByDeviceID(deviceID uuid.UUID) (*models.DeviceShadow, error) {
+	args := m.Called(deviceID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.DeviceShadow), args.Error(1)
+}
+
+func (m *MockDeviceShadowRepository) FindByDeviceIDs(deviceIDs []uuid.UUID) ([]models.DeviceShadow, error) {
+	args := m.Called(deviceIDs)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.DeviceShadow), args.Error(1)
+}
+
+func (m *MockDeviceShadowRepository) SaveDesired(shadow *models.DeviceShadow, previousVersion int64) error {
+	args := m.Called(shadow, previousVersion)
+	return args.Error(0)
+}
+
+func (m *MockDeviceShadowRepository) SaveReported(shadow *models.DeviceShadow, previousVersion int64) error {
+	args := m.Called(shadow, previousVersion)
+	return args.Error(0)
+}
+
+func noShadows() *MockDeviceShadowRepository {
+	shadowRepo := new(MockDeviceShadowRepository)
+	shadowRepo.On("FindByDeviceIDs", mock.Anything).Return([]models.DeviceShadow{}, nil).Maybe()
+	return shadowRepo
+}
+
+type MockShadowDeltaPublisher struct {
+	mock.Mock
+}
+
+func (m *MockShadowDeltaPublisher) PublishDelta(msg dto.ShadowDeltaMessage) error {
+	args := m.Called(msg)
+	return args.Error(0)
+}
+
+func newTestDeviceShadowService() (DeviceShadowService, *MockDeviceShadowRepository, *MockDeviceRepository, *MockShadowDeltaPublisher) {
+	shadowRepo := new(MockDeviceShadowRepository)
+	deviceRepo := new(MockDeviceRepository)
+	publisher := new(MockShadowDeltaPublisher)
+	authz := NewAuthorizer(new(MockOrganizationRepository), noDeviceShares())
+	return NewDeviceShadowService(shadowRepo, deviceRepo, publisher, authz), shadowRepo, deviceRepo, publisher
+}
+
+func TestShadowDelta(t *testing.T) {
+	desired := map[string]interface{}{
+		"heartbeat_interval_seconds": float64(30),
+		"sampling":                   map[string]interface{}{"temperature": float64(5), "cpu": float64(10)},
+		"mode":                       "eco",
+	}
+	reported := map[string]interface{}{
+		"heartbeat_interval_seconds": float64(60),
+		"sampling":                   map[string]interface{}{"temperature": float64(5), "cpu": float64(20)},
+		"mode":                       "eco",
+		"firmware":                   "1.2.0",
+	}
+
+	delta := shadowDelta(desired, reported)
+
+	assert.Equal(t, map[string]interface{}{
+		"heartbeat_interval_seconds": float64(30),
+		"sampling":                   map[string]interface{}{"cpu": float64(10)},
+	}, delta)
+	assert.Empty(t, shadowDelta(reported, reported))
+}
+
+func TestDeviceShadowService_GetShadow(t *testing.T) {
+	userID := uuid.New()
+	deviceID := uuid.New()
+	device := &models.Device{UUID: deviceID, UserID: userID}
+
+	t.Run("Success - Empty shadow for a new device", func(t *testing.T) {
+		service, shadowRepo, deviceRepo, _ := newTestDeviceShadowService()
+		deviceRepo.On("FindByID", deviceID).Return(device, nil)
+		shadowRepo.On("FindByDeviceID", deviceID).Return(nil, gorm.ErrRecordNotFound)
+
+		shadow, err := service.GetShadow(userID, deviceID)
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(0), shadow.DesiredVersion)
+		assert.Empty(t, shadow.Desired)
+		assert.Empty(t, shadow.Delta)
+	})
+
+	t.Run("Error - Other user's device", func(t *testing.T) {
+		service, shadowRepo, deviceRepo, _ := newTestDeviceShadowService()
+		deviceRepo.On("FindByID", deviceID).Return(device, nil)
+
+		shadow, err := service.GetShadow(uuid.New(), deviceID)
+
+		assert.Nil(t, shadow)
+		assert.Equal(t, custom_errors.ErrForbidden, err)
+		shadowRepo.AssertNotCalled(t, "FindByDeviceID", mock.Anything)
+	})
+}
+
+func TestDeviceShadowService_UpdateDesired(t *testing.T) {
+	userID := uuid.New()
+	deviceID := uuid.New()
+	device := &models.Device{UUID: deviceID, UserID: userID}
+	existing := func() *models.DeviceShadow {
+		return &models.DeviceShadow{
+			DeviceID:        deviceID,
+			Desired:         datatypes.JSON(`{"heartbeat_interval_seconds":60,"mode":"eco"}`),
+			Reported:        datatypes.JSON(`{"heartbeat_interval_seconds":60,"mode":"eco"}`),
+			DesiredVersion:  2,
+			ReportedVersion: 4,
+		}
+	}
+
+	t.Run("Success - Merge publishes the delta", func(t *testing.T) {
+		service, shadowRepo, deviceRepo, publisher := newTestDeviceShadowService()
+		deviceRepo.On("FindByID", deviceID).Return(device, nil)
+		shadowRepo.On("FindByDeviceID", deviceID).Return(existing(), nil)
+		shadowRepo.On("SaveDesired", mock.MatchedBy(func(s *models.DeviceShadow) bool {
+			return s.DesiredVersion == 3
+		}), int64(2)).Return(nil)
+		publisher.On("PublishDelta", mock.MatchedBy(func(msg dto.ShadowDeltaMessage) bool {
+			return msg.Version == 3 && msg.Delta["heartbeat_interval_seconds"] == float64(30) && len(msg.Delta) == 1
+		})).Return(nil)
+
+		shadow, err := service.UpdateDesired(userID, deviceID, dto.DeviceShadowDesiredRequest{
+			Desired: map[string]interface{}{"heartbeat_interval_seconds": float64(30)},
+		}, true)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "eco", shadow.Desired["mode"])
+		assert.Equal(t, map[string]interface{}{"heartbeat_interval_seconds": float64(30)}, shadow.Delta)
+		publisher.AssertExpectations(t)
+	})
+
+	t.Run("Success - Replace removes other keys", func(t *testing.T) {
+		service, shadowRepo, deviceRepo, publisher := newTestDeviceShadowService()
+		deviceRepo.On("FindByID", deviceID).Return(device, nil)
+		shadowRepo.On("FindByDeviceID", deviceID).Return(existing(), nil)
+		shadowRepo.On("SaveDesired", mock.Anything, int64(2)).Return(nil)
+		publisher.On("PublishDelta", mock.Anything).Return(nil)
+
+		shadow, err := service.UpdateDesired(userID, deviceID, dto.DeviceShadowDesiredRequest{
+			Desired: map[string]interface{}{"heartbeat_interval_seconds": float64(120)},
+		}, false)
+
+		assert.NoError(t, err)
+		assert.NotContains(t, shadow.Desired, "mode")
+	})
+
+	t.Run("Success - Unchanged state is not saved", func(t *testing.T) {
+		service, shadowRepo, deviceRepo, publisher := newTestDeviceShadowService()
+		deviceRepo.On("FindByID", deviceID).Return(device, nil)
+		shadowRepo.On("FindByDeviceID", deviceID).Return(existing(), nil)
+
+		shadow, err := service.UpdateDesired(userID, deviceID, dto.DeviceShadowDesiredRequest{
+			Desired: map[string]interface{}{"mode": "eco"},
+		}, true)
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(2), shadow.DesiredVersion)
+		shadowRepo.AssertNotCalled(t, "SaveDesired", mock.Anything, mock.Anything)
+		publisher.AssertNotCalled(t, "PublishDelta", mock.Anything)
+	})
+
+	t.Run("Success - Publish failure does not fail the update", func(t *testing.T) {
+		service, shadowRepo, deviceRepo, publisher := newTestDeviceShadowService()
+		deviceRepo.On("FindByID", deviceID).Return(device, nil)
+		shadowRepo.On("FindByDeviceID", deviceID).Return(existing(), nil)
+		shadowRepo.On("SaveDesired", mock.Anything, int64(2)).Return(nil)
+		publisher.On("PublishDelta", mock.Anything).Return(errors.New("channel closed"))
+
+		shadow, err := service.UpdateDesired(userID, deviceID, dto.DeviceShadowDesiredRequest{
+			Desired: map[string]interface{}{"mode": "performance"},
+		}, true)
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(3), shadow.DesiredVersion)
+	})
+
+	t.Run("Error - Stale version", func(t *testing.T) {
+		service, shadowRepo, deviceRepo, _ := newTestDeviceShadowService()
+		deviceRepo.On("FindByID", deviceID).Return(device, nil)
+		shadowRepo.On("FindByDeviceID", deviceID).Return(existing(), nil)
+		version := int64(1)
+
+		shadow, err := service.UpdateDesired(userID, deviceID, dto.DeviceShadowDesiredRequest{
+			Desired: map[string]interface{}{"mode": "performance"},
+			Version: &version,
+		}, true)
+
+		assert.Nil(t, shadow)
+		assert.Equal(t, custom_errors.ErrShadowVersionConflict, err)
+	})
+
+	t.Run("Error - Concurrent update", func(t *testing.T) {
+		service, shadowRepo, deviceRepo, publisher := newTestDeviceShadowService()
+		deviceRepo.On("FindByID", deviceID).Return(device, nil)
+		shadowRepo.On("FindByDeviceID", deviceID).Return(existing(), nil)
+		shadowRepo.On("SaveDesired", mock.Anything, int64(2)).Return(gorm.ErrRecordNotFound)
+
+		shadow, err := service.UpdateDesired(userID, deviceID, dto.DeviceShadowDesiredRequest{
+			Desired: map[string]interface{}{"mode": "performance"},
+		}, true)
+
+		assert.Nil(t, shadow)
+		assert.Equal(t, custom_errors.ErrShadowVersionConflict, err)
+		publisher.AssertNotCalled(t, "PublishDelta", mock.Anything)
+	})
+
+	t.Run("Error - Setting out of range", func(t *testing.T) {
+		service, shadowRepo, deviceRepo, _ := newTestDeviceShadowService()
+		deviceRepo.On("FindByID", deviceID).Return(device, nil)
+		shadowRepo.On("FindByDeviceID", deviceID).Return(existing(), nil)
+
+		shadow, err := service.UpdateDesired(userID, deviceID, dto.DeviceShadowDesiredRequest{
+			Desired: map[string]interface{}{"heartbeat_interval_seconds": float64(1)},
+		}, true)
+
+		assert.Nil(t, shadow)
+		assert.ErrorContains(t, err, "heartbeat_interval_seconds")
+		shadowRepo.AssertNotCalled(t, "SaveDesired", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Error - Viewer cannot edit", func(t *testing.T) {
+		shadowRepo := new(MockDeviceShadowRepository)
+		deviceRepo := new(MockDeviceRepository)
+		shareRepo := new(MockDeviceShareRepository)
+		viewerID := uuid.New()
+		shareRepo.On("FindActive", deviceID, viewerID, mock.Anything).Return(&models.DeviceShare{Permission: models.RoleViewer}, nil)
+		service := NewDeviceShadowService(shadowRepo, deviceRepo, new(MockShadowDeltaPublisher), NewAuthorizer(new(MockOrganizationRepository), shareRepo))
+		deviceRepo.On("FindByID", deviceID).Return(device, nil)
+
+		shadow, err := service.UpdateDesired(viewerID, deviceID, dto.DeviceShadowDesiredRequest{
+			Desired: map[string]interface{}{"mode": "performance"},
+		}, true)
+
+		assert.Nil(t, shadow)
+		assert.Equal(t, custom_errors.ErrForbidden, err)
+	})
+}
+
+func TestDeviceShadowService_UpdateReported(t *testing.T) {
+	deviceID := uuid.New()
+	existing := func() *models.DeviceShadow {
+		return &models.DeviceShadow{
+			DeviceID:        deviceID,
+			Desired:         datatypes.JSON(`{"heartbeat_interval_seconds":30,"mode":"eco"}`),
+			Reported:        datatypes.JSON(`{"heartbeat_interval_seconds":60}`),
+			DesiredVersion:  3,
+			ReportedVersion: 1,
+		}
+	}
+
+	t.Run("Success - Applied state clears the delta", func(t *testing.T) {
+		service, shadowRepo, _, publisher := newTestDeviceShadowService()
+		shadowRepo.On("FindByDeviceID", deviceID).Return(existing(), nil)
+		shadowRepo.On("SaveReported", mock.MatchedBy(func(s *models.DeviceShadow) bool {
+			return s.ReportedVersion == 2
+		}), int64(1)).Return(nil)
+
+		err := service.UpdateReported(deviceID, map[string]interface{}{"heartbeat_interval_seconds": float64(30), "mode": "eco"})
+
+		assert.NoError(t, err)
+		shadowRepo.AssertExpectations(t)
+		publisher.AssertNotCalled(t, "PublishDelta", mock.Anything)
+	})
+
+	t.Run("Success - Pending delta is pushed again", func(t *testing.T) {
+		service, shadowRepo, _, publisher := newTestDeviceShadowService()
+		shadowRepo.On("FindByDeviceID", deviceID).Return(existing(), nil)
+		publisher.On("PublishDelta", mock.MatchedBy(func(msg dto.ShadowDeltaMessage) bool {
+			return msg.Version == 3 && len(msg.Delta) == 2
+		})).Return(nil)
+
+		err := service.UpdateReported(deviceID, map[string]interface{}{"heartbeat_interval_seconds": float64(60)})
+
+		assert.NoError(t, err)
+		shadowRepo.AssertNotCalled(t, "SaveReported", mock.Anything, mock.Anything)
+		publisher.AssertExpectations(t)
+	})
+
+	t.Run("Success - Retries after a concurrent update", func(t *testing.T) {
+		service, shadowRepo, _, _ := newTestDeviceShadowService()
+		shadowRepo.On("FindByDeviceID", deviceID).Return(existing(), nil).Once()
+		shadowRepo.On("FindByDeviceID", deviceID).Return(existing(), nil).Once()
+		shadowRepo.On("SaveReported", mock.Anything, int64(1)).Return(gorm.ErrRecordNotFound).Once()
+		shadowRepo.On("SaveReported", mock.Anything, int64(1)).Return(nil).Once()
+
+		err := service.UpdateReported(deviceID, map[string]interface{}{"heartbeat_interval_seconds": float64(30), "mode": "eco"})
+
+		assert.NoError(t, err)
+		shadowRepo.AssertNumberOfCalls(t, "SaveReported", 2)
+	})
+
+	t.Run("Error - Database failure", func(t *testing.T) {
+		service, shadowRepo, _, _ := newTestDeviceShadowService()
+		shadowRepo.On("FindByDeviceID", deviceID).Return(nil, errors.New("db down"))
+
+		err := service.UpdateReported(deviceID, map[string]interface{}{"mode": "eco"})
+
+		assert.Equal(t, custom_errors.ErrDatabaseError, err)
+	})
+}
@@ -0,0 +1,170 @@
+package services
+
+import (
+	"strings"
+	"time"
+
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/dto"
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/models"
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/repository"
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/utils/errors"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type DeviceShareService interface {
+	ShareDevice(userID, deviceID uuid.UUID, req dto.DeviceShareRequest) (*dto.DeviceShareResponse, error)
+	ListShares(userID, deviceID uuid.UUID) ([]dto.DeviceShareResponse, error)
+	RevokeShare(userID, deviceID, shareID uuid.UUID) error
+}
+
+type deviceShareService struct {
+	shareRepo  repository.DeviceShareRepository
+	deviceRepo repository.DeviceRepository
+	userRepo   userRepository
+	authz      Authorizer
+}
+
+func NewDeviceShareService(shareRepo repository.DeviceShareRepository, deviceRepo repository.DeviceRepository, userRepo userRepository, authz Authorizer) DeviceShareService {
+	return &deviceShareService{
+		shareRepo:  shareRepo,
+		deviceRepo: deviceRepo,
+		userRepo:   userRepo,
+		authz:      authz,
+	}
+}
+
+// ShareDevice grants the user registered under req.Email access to the
+// device. Sharing again with the same user replaces the permission and
+// expiry of the existing grant. Managing shares requires the admin role on
+// the device, which a grant never gives.
+func (s *deviceShareService) ShareDevice(userID, deviceID uuid.UUID, req dto.DeviceShareRequest) (*dto.DeviceShareResponse, error) {
+	device, err := s.findManagedDevice(userID, deviceID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !models.ValidSharePermission(req.Permission) {
+		return nil, errors.NewValidationError("Permission must be viewer or operator")
+	}
+	now := time.Now()
+	if req.ExpiresAt != nil && !req.ExpiresAt.After(now) {
+		return nil, errors.NewValidationError("expires_at must be in the future")
+	}
+
+	grantee, err := s.userRepo.FindByEmail(strings.TrimSpace(req.Email))
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.ErrUserNotFound
+		}
+		return nil, errors.ErrFailedToCheckUser
+	}
+	if device.OrganizationID == nil && grantee.ID == device.UserID {
+		return nil, errors.NewValidationError("A device cannot be shared with its owner")
+	}
+
+	share, err := s.shareRepo.FindByDeviceAndGrantee(deviceID, grantee.ID)
+	switch {
+	case err == nil:
+		share.Permission = req.Permission
+		share.ExpiresAt = req.ExpiresAt
+		share.GrantedBy = userID
+		share.UpdatedAt = now
+		err = s.shareRepo.Update(share)
+	case err == gorm.ErrRecordNotFound:
+		share = &models.DeviceShare{
+			ID:         uuid.New(),
+			DeviceID:   deviceID,
+			GranteeID:  grantee.ID,
+			GrantedBy:  userID,
+			Permission: req.Permission,
+			ExpiresAt:  req.ExpiresAt,
+			CreatedAt:  now,
+			UpdatedAt:  now,
+		}
+		err = s.shareRepo.Create(share)
+	}
+	if err != nil {
+		return nil, errors.ErrDatabaseError
+	}
+
+	share.Grantee = *grantee
+	response := deviceShareResponse(share, now)
+	return &response, nil
+}
+
+// ListShares returns every grant on the device, expired ones included so the
+// owner can see and clean them up.
+func (s *deviceShareService) ListShares(userID, deviceID uuid.UUID) ([]dto.DeviceShareResponse, error) {
+	if _, err := s.findManagedDevice(userID, deviceID); err != nil {
+		return nil, err
+	}
+
+	shares, err := s.shareRepo.FindByDeviceID(deviceID)
+	if err != nil {
+		return nil, errors.ErrDatabaseError
+	}
+
+	now := time.Now()
+	responses := make([]dto.DeviceShareResponse, 0, len(shares))
+	for i := range shares {
+		responses = append(responses, deviceShareResponse(&shares[i], now))
+	}
+	return responses, nil
+}
+
+// RevokeShare deletes a grant. Device admins can revoke any grant; a grantee
+// can give up their own.
+func (s *deviceShareService) RevokeShare(userID, deviceID, shareID uuid.UUID) error {
+	share, err := s.shareRepo.FindByID(shareID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return errors.ErrDeviceShareNotFound
+		}
+		return errors.ErrDatabaseError
+	}
+	if share.DeviceID != deviceID {
+		return errors.ErrDeviceShareNotFound
+	}
+
+	if share.GranteeID != userID {
+		if _, err := s.findManagedDevice(userID, deviceID); err != nil {
+			return err
+		}
+	}
+
+	if err := s.shareRepo.Delete(shareID); err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return errors.ErrDeviceShareNotFound
+		}
+		return errors.ErrDatabaseError
+	}
+	return nil
+}
+
+func (s *deviceShareService) findManagedDevice(userID, deviceID uuid.UUID) (*models.Device, error) {
+	device, err := s.deviceRepo.FindByID(deviceID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.ErrDeviceNotFound
+		}
+		return nil, errors.ErrDatabaseError
+	}
+	if err := s.authz.Authorize(userID, deviceOwnership(device), models.RoleAdmin); err != nil {
+		return nil, err
+	}
+	return device, nil
+}
+
+func deviceShareResponse(share *models.DeviceShare, now time.Time) dto.DeviceShareResponse {
+	return dto.DeviceShareResponse{
+		ID:         share.ID,
+		DeviceID:   share.DeviceID,
+		GranteeID:  share.GranteeID,
+		Email:      share.Grantee.Email,
+		Permission: share.Permission,
+		ExpiresAt:  share.ExpiresAt,
+		Expired:    share.ExpiresAt != nil && !share.ExpiresAt.After(now),
+		CreatedAt:  share.CreatedAt,
+	}
+}
@@ -0,0 +1,256 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/dto"
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/models"
+	custom_errors "github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/utils/errors"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+type MockDeviceShareRepository struct {
+	mock.Mock
+}
+
+func (m *MockDeviceShareRepository) Create(share *models.DeviceShare) error {
+	args := m.Called(share)
+	return args.Error(0)
+}
+
+func (m *MockDeviceShareRepository) Update(share *models.DeviceShare) error {
+	args := m.Called(share)
+	return args.Error(0)
+}
+
+func (m *MockDeviceShareRepository) FindByID(id uuid.UUID) (*models.DeviceShare, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.DeviceShare), args.Error(1)
+}
+
+func (m *MockDeviceShareRepository) FindByDeviceID(deviceID uuid.UUID) ([]models.DeviceShare, error) {
+	args := m.Called(deviceID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.DeviceShare), args.Error(1)
+}
+
+func (m *MockDeviceShareRepository) FindByDeviceAndGrantee(deviceID, granteeID uuid.UUID) (*models.DeviceShare, error) {
+	args := m.Called(deviceID, granteeID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.DeviceShare), args.Error(1)
+}
+
+func (m *MockDeviceShareRepository) FindActive(deviceID, granteeID uuid.UUID, now time.Time) (*models.DeviceShare, error) {
+	args := m.Called(deviceID, granteeID, now)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.DeviceShare), args.Error(1)
+}
+
+func (m *MockDeviceShareRepository) Delete(id uuid.UUID) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+// noDeviceShares returns a share repository in which nobody holds a grant,
+// for tests that exercise ownership checks only.
+func noDeviceShares() *MockDeviceShareRepository {
+	shareRepo := new(MockDeviceShareRepository)
+	shareRepo.On("FindActive", mock.Anything, mock.Anything, mock.Anything).Return(nil, gorm.ErrRecordNotFound).Maybe()
+	return shareRepo
+}
+
+func newTestDeviceShareService() (DeviceShareService, *MockDeviceShareRepository, *MockDeviceRepository, *MockUserRepository) {
+	shareRepo := new(MockDeviceShareRepository)
+	deviceRepo := new(MockDeviceRepository)
+	userRepo := new(MockUserRepository)
+	authz := NewAuthorizer(new(MockOrganizationRepository), shareRepo)
+	return NewDeviceShareService(shareRepo, deviceRepo, userRepo, authz), shareRepo, deviceRepo, userRepo
+}
+
+func TestDeviceShareService_ShareDevice(t *testing.T) {
+	ownerID := uuid.New()
+	deviceID := uuid.New()
+	device := &models.Device{UUID: deviceID, UserID: ownerID}
+	contractor := &models.User{ID: uuid.New(), Email: "contractor@example.com"}
+
+	t.Run("Success - New read-only grant", func(t *testing.T) {
+		service, shareRepo, deviceRepo, userRepo := newTestDeviceShareService()
+		deviceRepo.On("FindByID", deviceID).Return(device, nil)
+		userRepo.On("FindByEmail", contractor.Email).Return(contractor, nil)
+		shareRepo.On("FindByDeviceAndGrantee", deviceID, contractor.ID).Return(nil, gorm.ErrRecordNotFound)
+		shareRepo.On("Create", mock.MatchedBy(func(s *models.DeviceShare) bool {
+			return s.GranteeID == contractor.ID && s.Permission == models.RoleViewer && s.GrantedBy == ownerID
+		})).Return(nil)
+
+		share, err := service.ShareDevice(ownerID, deviceID, dto.DeviceShareRequest{Email: contractor.Email, Permission: models.RoleViewer})
+
+		assert.NoError(t, err)
+		assert.Equal(t, contractor.Email, share.Email)
+		assert.False(t, share.Expired)
+		shareRepo.AssertExpectations(t)
+	})
+
+	t.Run("Success - Existing grant is replaced", func(t *testing.T) {
+		service, shareRepo, deviceRepo, userRepo := newTestDeviceShareService()
+		expiresAt := time.Now().Add(24 * time.Hour)
+		existing := &models.DeviceShare{ID: uuid.New(), DeviceID: deviceID, GranteeID: contractor.ID, Permission: models.RoleViewer}
+		deviceRepo.On("FindByID", deviceID).Return(device, nil)
+		userRepo.On("FindByEmail", contractor.Email).Return(contractor, nil)
+		shareRepo.On("FindByDeviceAndGrantee", deviceID, contractor.ID).Return(existing, nil)
+		shareRepo.On("Update", existing).Return(nil)
+
+		share, err := service.ShareDevice(ownerID, deviceID, dto.DeviceShareRequest{Email: contractor.Email, Permission: models.RoleOperator, ExpiresAt: &expiresAt})
+
+		assert.NoError(t, err)
+		assert.Equal(t, existing.ID, share.ID)
+		assert.Equal(t, models.RoleOperator, share.Permission)
+		assert.Equal(t, &expiresAt, share.ExpiresAt)
+		shareRepo.AssertNotCalled(t, "Create", mock.Anything)
+	})
+
+	t.Run("Error - Invalid permission", func(t *testing.T) {
+		service, _, deviceRepo, userRepo := newTestDeviceShareService()
+		deviceRepo.On("FindByID", deviceID).Return(device, nil)
+
+		share, err := service.ShareDevice(ownerID, deviceID, dto.DeviceShareRequest{Email: contractor.Email, Permission: models.RoleAdmin})
+
+		assert.Nil(t, share)
+		assert.Equal(t, custom_errors.NewValidationError("Permission must be viewer or operator"), err)
+		userRepo.AssertNotCalled(t, "FindByEmail", mock.Anything)
+	})
+
+	t.Run("Error - Expiry in the past", func(t *testing.T) {
+		service, _, deviceRepo, _ := newTestDeviceShareService()
+		past := time.Now().Add(-time.Hour)
+		deviceRepo.On("FindByID", deviceID).Return(device, nil)
+
+		share, err := service.ShareDevice(ownerID, deviceID, dto.DeviceShareRequest{Email: contractor.Email, Permission: models.RoleViewer, ExpiresAt: &past})
+
+		assert.Nil(t, share)
+		assert.Error(t, err)
+	})
+
+	t.Run("Error - Sharing with the owner", func(t *testing.T) {
+		service, _, deviceRepo, userRepo := newTestDeviceShareService()
+		deviceRepo.On("FindByID", deviceID).Return(device, nil)
+		userRepo.On("FindByEmail", "owner@example.com").Return(&models.User{ID: ownerID, Email: "owner@example.com"}, nil)
+
+		share, err := service.ShareDevice(ownerID, deviceID, dto.DeviceShareRequest{Email: "owner@example.com", Permission: models.RoleViewer})
+
+		assert.Nil(t, share)
+		assert.Equal(t, custom_errors.NewValidationError("A device cannot be shared with its owner"), err)
+	})
+
+	t.Run("Error - Operator grantee cannot re-share", func(t *testing.T) {
+		service, shareRepo, deviceRepo, _ := newTestDeviceShareService()
+		deviceRepo.On("FindByID", deviceID).Return(device, nil)
+		shareRepo.On("FindActive", deviceID, contractor.ID, mock.Anything).Return(&models.DeviceShare{Permission: models.RoleOperator}, nil)
+
+		share, err := service.ShareDevice(contractor.ID, deviceID, dto.DeviceShareRequest{Email: "friend@example.com", Permission: models.RoleViewer})
+
+		assert.Nil(t, share)
+		assert.Equal(t, custom_errors.ErrForbidden, err)
+	})
+
+	t.Run("Error - Unknown email", func(t *testing.T) {
+		service, _, deviceRepo, userRepo := newTestDeviceShareService()
+		deviceRepo.On("FindByID", deviceID).Return(device, nil)
+		userRepo.On("FindByEmail", "nobody@example.com").Return(nil, gorm.ErrRecordNotFound)
+
+		share, err := service.ShareDevice(ownerID, deviceID, dto.DeviceShareRequest{Email: "nobody@example.com", Permission: models.RoleViewer})
+
+		assert.Nil(t, share)
+		assert.Equal(t, custom_errors.ErrUserNotFound, err)
+	})
+}
+
+func TestDeviceShareService_ListShares(t *testing.T) {
+	ownerID := uuid.New()
+	deviceID := uuid.New()
+	device := &models.Device{UUID: deviceID, UserID: ownerID}
+
+	t.Run("Success - Expired grants are flagged", func(t *testing.T) {
+		service, shareRepo, deviceRepo, _ := newTestDeviceShareService()
+		past := time.Now().Add(-time.Hour)
+		deviceRepo.On("FindByID", deviceID).Return(device, nil)
+		shareRepo.On("FindByDeviceID", deviceID).Return([]models.DeviceShare{
+			{ID: uuid.New(), DeviceID: deviceID, Permission: models.RoleViewer, Grantee: models.User{Email: "a@example.com"}},
+			{ID: uuid.New(), DeviceID: deviceID, Permission: models.RoleViewer, ExpiresAt: &past, Grantee: models.User{Email: "b@example.com"}},
+		}, nil)
+
+		shares, err := service.ListShares(ownerID, deviceID)
+
+		assert.NoError(t, err)
+		assert.Len(t, shares, 2)
+		assert.False(t, shares[0].Expired)
+		assert.True(t, shares[1].Expired)
+		assert.Equal(t, "b@example.com", shares[1].Email)
+	})
+
+	t.Run("Error - Database error", func(t *testing.T) {
+		service, shareRepo, deviceRepo, _ := newTestDeviceShareService()
+		deviceRepo.On("FindByID", deviceID).Return(device, nil)
+		shareRepo.On("FindByDeviceID", deviceID).Return(nil, errors.New("db down"))
+
+		shares, err := service.ListShares(ownerID, deviceID)
+
+		assert.Nil(t, shares)
+		assert.Equal(t, custom_errors.ErrDatabaseError, err)
+	})
+}
+
+func TestDeviceShareService_RevokeShare(t *testing.T) {
+	ownerID := uuid.New()
+	granteeID := uuid.New()
+	deviceID := uuid.New()
+	shareID := uuid.New()
+	device := &models.Device{UUID: deviceID, UserID: ownerID}
+	share := &models.DeviceShare{ID: shareID, DeviceID: deviceID, GranteeID: granteeID, Permission: models.RoleViewer}
+
+	t.Run("Success - Owner revokes", func(t *testing.T) {
+		service, shareRepo, deviceRepo, _ := newTestDeviceShareService()
+		shareRepo.On("FindByID", shareID).Return(share, nil)
+		deviceRepo.On("FindByID", deviceID).Return(device, nil)
+		shareRepo.On("Delete", shareID).Return(nil)
+
+		err := service.RevokeShare(ownerID, deviceID, shareID)
+
+		assert.NoError(t, err)
+		shareRepo.AssertExpectations(t)
+	})
+
+	t.Run("Success - Grantee gives up own grant", func(t *testing.T) {
+		service, shareRepo, deviceRepo, _ := newTestDeviceShareService()
+		shareRepo.On("FindByID", shareID).Return(share, nil)
+		shareRepo.On("Delete", shareID).Return(nil)
+
+		err := service.RevokeShare(granteeID, deviceID, shareID)
+
+		assert.NoError(t, err)
+		deviceRepo.AssertNotCalled(t, "FindByID", mock.Anything)
+	})
+
+	t.Run("Error - Share of another device", func(t *testing.T) {
+		service, shareRepo, _, _ := newTestDeviceShareService()
+		shareRepo.On("FindByID", shareID).Return(share, nil)
+
+		err := service.RevokeShare(ownerID, uuid.New(), shareID)
+
+		assert.Equal(t, custom_errors.ErrDeviceShareNotFound, err)
+		shareRepo.AssertNotCalled(t, "Delete", mock.Anything)
+	})
+}
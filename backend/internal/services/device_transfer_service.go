@@ -0,0 +1,342 @@
+package services
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/dto"
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/models"
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/repository"
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/utils/errors"
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/utils/logger"
+	"github.com/google/uuid"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+// defaultTransferTTL is how long a transfer can be accepted when the request
+// does not set an expiry.
+const defaultTransferTTL = 7 * 24 * time.Hour
+
+type DeviceTransferService interface {
+	RequestTransfer(userID, deviceID uuid.UUID, req dto.DeviceTransferRequest) (*dto.DeviceTransferResponse, error)
+	ListTransfers(userID uuid.UUID) ([]dto.DeviceTransferResponse, error)
+	AcceptTransfer(userID, transferID uuid.UUID) (*dto.DeviceTransferResponse, error)
+	DeclineTransfer(userID, transferID uuid.UUID) error
+	CancelTransfer(userID, transferID uuid.UUID) error
+	GetAuditLog(userID, deviceID uuid.UUID) ([]dto.AuditLogResponse, error)
+}
+
+type deviceTransferService struct {
+	transferRepo repository.DeviceTransferRepository
+	deviceRepo   repository.DeviceRepository
+	userRepo     userRepository
+	auditRepo    repository.AuditLogRepository
+	authz        Authorizer
+}
+
+func NewDeviceTransferService(transferRepo repository.DeviceTransferRepository, deviceRepo repository.DeviceRepository, userRepo userRepository, auditRepo repository.AuditLogRepository, authz Authorizer) DeviceTransferService {
+	return &deviceTransferService{
+		transferRepo: transferRepo,
+		deviceRepo:   deviceRepo,
+		userRepo:     userRepo,
+		auditRepo:    auditRepo,
+		authz:        authz,
+	}
+}
+
+// RequestTransfer starts handing a personal device over to the user
+// registered under req.Email. Only the owner can transfer a device, and a
+// device has at most one pending transfer.
+func (s *deviceTransferService) RequestTransfer(userID, deviceID uuid.UUID, req dto.DeviceTransferRequest) (*dto.DeviceTransferResponse, error) {
+	device, err := s.deviceRepo.FindByID(deviceID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.ErrDeviceNotFound
+		}
+		return nil, errors.ErrDatabaseError
+	}
+	if err := s.authz.Authorize(userID, deviceOwnership(device), models.RoleOwner); err != nil {
+		return nil, err
+	}
+	if device.OrganizationID != nil {
+		return nil, errors.NewValidationError("Organization devices cannot be transferred")
+	}
+
+	now := time.Now()
+	expiresAt := now.Add(defaultTransferTTL)
+	if req.ExpiresAt != nil {
+		if !req.ExpiresAt.After(now) {
+			return nil, errors.NewValidationError("expires_at must be in the future")
+		}
+		expiresAt = *req.ExpiresAt
+	}
+
+	owner, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, errors.ErrFailedToCheckUser
+	}
+	email := strings.TrimSpace(req.Email)
+	if strings.EqualFold(email, owner.Email) {
+		return nil, errors.NewValidationError("A device cannot be transferred to its owner")
+	}
+
+	pending, err := s.transferRepo.FindPendingByDeviceID(deviceID)
+	switch {
+	case err == nil:
+		if pending.StatusAt(now) == models.TransferPending {
+			return nil, errors.ErrDeviceTransferPending
+		}
+		if err := s.expire(pending); err != nil {
+			return nil, err
+		}
+	case err != gorm.ErrRecordNotFound:
+		return nil, errors.ErrDatabaseError
+	}
+
+	transfer := &models.DeviceTransfer{
+		ID:         uuid.New(),
+		DeviceID:   deviceID,
+		FromUserID: userID,
+		ToEmail:    email,
+		Status:     models.TransferPending,
+		ExpiresAt:  expiresAt,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+	if err := s.transferRepo.Create(transfer); err != nil {
+		return nil, errors.ErrDatabaseError
+	}
+	s.audit(userID, models.AuditTransferRequested, transfer)
+
+	response := deviceTransferResponse(transfer, now)
+	return &response, nil
+}
+
+// ListTransfers returns the transfers sent by the user and those addressed
+// to the user's email.
+func (s *deviceTransferService) ListTransfers(userID uuid.UUID) ([]dto.DeviceTransferResponse, error) {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, errors.ErrFailedToCheckUser
+	}
+
+	transfers, err := s.transferRepo.FindByUser(userID, user.Email)
+	if err != nil {
+		return nil, errors.ErrDatabaseError
+	}
+
+	now := time.Now()
+	responses := make([]dto.DeviceTransferResponse, 0, len(transfers))
+	for i := range transfers {
+		responses = append(responses, deviceTransferResponse(&transfers[i], now))
+	}
+	return responses, nil
+}
+
+// AcceptTransfer makes the recipient the owner of the device. See
+// DeviceTransferRepository.Complete for what moves with the device.
+func (s *deviceTransferService) AcceptTransfer(userID, transferID uuid.UUID) (*dto.DeviceTransferResponse, error) {
+	transfer, err := s.findIncoming(userID, transferID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if err := s.checkPending(transfer, now); err != nil {
+		return nil, err
+	}
+
+	if err := s.transferRepo.Complete(transfer, userID, now); err != nil {
+		if err != gorm.ErrRecordNotFound {
+			return nil, errors.ErrDatabaseError
+		}
+		// The sender gave the device away or deleted it in the meantime.
+		if err := s.transferRepo.UpdateStatus(transfer.ID, models.TransferPending, models.TransferCancelled); err == nil {
+			transfer.Status = models.TransferCancelled
+			s.audit(transfer.FromUserID, models.AuditTransferCancelled, transfer)
+		}
+		return nil, errors.ErrDeviceTransferNotPending
+	}
+
+	transfer.Status = models.TransferAccepted
+	transfer.ToUserID = &userID
+	transfer.CompletedAt = &now
+	s.audit(userID, models.AuditTransferAccepted, transfer)
+
+	response := deviceTransferResponse(transfer, now)
+	return &response, nil
+}
+
+func (s *deviceTransferService) DeclineTransfer(userID, transferID uuid.UUID) error {
+	transfer, err := s.findIncoming(userID, transferID)
+	if err != nil {
+		return err
+	}
+	return s.close(userID, transfer, models.TransferDeclined, models.AuditTransferDeclined)
+}
+
+func (s *deviceTransferService) CancelTransfer(userID, transferID uuid.UUID) error {
+	transfer, err := s.findTransfer(transferID)
+	if err != nil {
+		return err
+	}
+	if transfer.FromUserID != userID {
+		return errors.ErrDeviceTransferNotFound
+	}
+	return s.close(userID, transfer, models.TransferCancelled, models.AuditTransferCancelled)
+}
+
+// GetAuditLog returns the recorded history of a device. It requires the
+// admin role, which share grants never give.
+func (s *deviceTransferService) GetAuditLog(userID, deviceID uuid.UUID) ([]dto.AuditLogResponse, error) {
+	device, err := s.deviceRepo.FindByID(deviceID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.ErrDeviceNotFound
+		}
+		return nil, errors.ErrDatabaseError
+	}
+	if err := s.authz.Authorize(userID, deviceOwnership(device), models.RoleAdmin); err != nil {
+		return nil, err
+	}
+
+	entries, err := s.auditRepo.FindByEntity(models.AuditEntityDevice, deviceID)
+	if err != nil {
+		return nil, errors.ErrDatabaseError
+	}
+
+	responses := make([]dto.AuditLogResponse, 0, len(entries))
+	for _, entry := range entries {
+		var details map[string]interface{}
+		_ = json.Unmarshal(entry.Details, &details)
+		responses = append(responses, dto.AuditLogResponse{
+			ID:         entry.ID,
+			ActorID:    entry.ActorID,
+			Action:     entry.Action,
+			EntityType: entry.EntityType,
+			EntityID:   entry.EntityID,
+			Details:    details,
+			CreatedAt:  entry.CreatedAt,
+		})
+	}
+	return responses, nil
+}
+
+// close ends a pending transfer without moving the device.
+func (s *deviceTransferService) close(userID uuid.UUID, transfer *models.DeviceTransfer, status, action string) error {
+	if err := s.checkPending(transfer, time.Now()); err != nil {
+		return err
+	}
+	if err := s.transferRepo.UpdateStatus(transfer.ID, models.TransferPending, status); err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return errors.ErrDeviceTransferNotPending
+		}
+		return errors.ErrDatabaseError
+	}
+	transfer.Status = status
+	s.audit(userID, action, transfer)
+	return nil
+}
+
+// checkPending fails unless the transfer can still be acted on, recording
+// the expiry of transfers found past their deadline.
+func (s *deviceTransferService) checkPending(transfer *models.DeviceTransfer, now time.Time) error {
+	switch transfer.StatusAt(now) {
+	case models.TransferPending:
+		return nil
+	case models.TransferExpired:
+		if transfer.Status == models.TransferPending {
+			if err := s.expire(transfer); err != nil {
+				return err
+			}
+		}
+		return errors.ErrDeviceTransferExpired
+	default:
+		return errors.ErrDeviceTransferNotPending
+	}
+}
+
+func (s *deviceTransferService) expire(transfer *models.DeviceTransfer) error {
+	err := s.transferRepo.UpdateStatus(transfer.ID, models.TransferPending, models.TransferExpired)
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return errors.ErrDatabaseError
+	}
+	if err == nil {
+		transfer.Status = models.TransferExpired
+		s.audit(transfer.FromUserID, models.AuditTransferExpired, transfer)
+	}
+	return nil
+}
+
+func (s *deviceTransferService) findTransfer(transferID uuid.UUID) (*models.DeviceTransfer, error) {
+	transfer, err := s.transferRepo.FindByID(transferID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.ErrDeviceTransferNotFound
+		}
+		return nil, errors.ErrDatabaseError
+	}
+	return transfer, nil
+}
+
+// findIncoming loads a transfer addressed to the user's email. Transfers
+// addressed to someone else are reported as not found.
+func (s *deviceTransferService) findIncoming(userID, transferID uuid.UUID) (*models.DeviceTransfer, error) {
+	transfer, err := s.findTransfer(transferID)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, errors.ErrFailedToCheckUser
+	}
+	if !strings.EqualFold(user.Email, transfer.ToEmail) {
+		return nil, errors.ErrDeviceTransferNotFound
+	}
+	return transfer, nil
+}
+
+// audit records a transfer step in the device history. The step itself has
+// already happened, so a failure is logged rather than returned.
+func (s *deviceTransferService) audit(actorID uuid.UUID, action string, transfer *models.DeviceTransfer) {
+	details, _ := json.Marshal(map[string]interface{}{
+		"transfer_id":  transfer.ID,
+		"from_user_id": transfer.FromUserID,
+		"to_email":     transfer.ToEmail,
+		"to_user_id":   transfer.ToUserID,
+		"expires_at":   transfer.ExpiresAt,
+	})
+
+	entry := &models.AuditLog{
+		ID:         uuid.New(),
+		ActorID:    actorID,
+		Action:     action,
+		EntityType: models.AuditEntityDevice,
+		EntityID:   transfer.DeviceID,
+		Details:    datatypes.JSON(details),
+		CreatedAt:  time.Now(),
+	}
+	if err := s.auditRepo.Create(entry); err != nil {
+		logger.Logger.Error("Failed to record audit log entry",
+			"action", action,
+			"device_id", transfer.DeviceID.String(),
+			"error", err)
+	}
+}
+
+func deviceTransferResponse(transfer *models.DeviceTransfer, now time.Time) dto.DeviceTransferResponse {
+	return dto.DeviceTransferResponse{
+		ID:          transfer.ID,
+		DeviceID:    transfer.DeviceID,
+		FromUserID:  transfer.FromUserID,
+		ToEmail:     transfer.ToEmail,
+		ToUserID:    transfer.ToUserID,
+		Status:      transfer.StatusAt(now),
+		ExpiresAt:   transfer.ExpiresAt,
+		CompletedAt: transfer.CompletedAt,
+		CreatedAt:   transfer.CreatedAt,
+	}
+}
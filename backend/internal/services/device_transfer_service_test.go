@@ -0,0 +1,337 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/dto"
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/models"
+	custom_errors "github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/utils/errors"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+type MockDeviceTransferRepository struct {
+	mock.Mock
+}
+
+func (m *MockDeviceTransferRepository) Create(transfer *models.DeviceTransfer) error {
+	args := m.Called(transfer)
+	return args.Error(0)
+}
+
+func (m *MockDeviceTransferRepository) FindByID(id uuid.UUID) (*models.DeviceTransfer, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.DeviceTransfer), args.Error(1)
+}
+
+func (m *MockDeviceTransferRepository) FindPendingByDeviceID(deviceID uuid.UUID) (*models.DeviceTransfer, error) {
+	args := m.Called(deviceID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.DeviceTransfer), args.Error(1)
+}
+
+func (m *MockDeviceTransferRepository) FindByUser(userID uuid.UUID, email string) ([]models.DeviceTransfer, error) {
+	args := m.Called(userID, email)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.DeviceTransfer), args.Error(1)
+}
+
+func (m *MockDeviceTransferRepository) UpdateStatus(id uuid.UUID, fromStatus, toStatus string) error {
+	args := m.Called(id, fromStatus, toStatus)
+	return args.Error(0)
+}
+
+func (m *MockDeviceTransferRepository) Complete(transfer *models.DeviceTransfer, toUserID uuid.UUID, completedAt time.Time) error {
+	args := m.Called(transfer, toUserID, completedAt)
+	return args.Error(0)
+}
+
+type MockAuditLogRepository struct {
+	mock.Mock
+}
+
+func (m *MockAuditLogRepository) Create(entry *models.AuditLog) error {
+	args := m.Called(entry)
+	return args.Error(0)
+}
+
+func (m *MockAuditLogRepository) FindByEntity(entityType string, entityID uuid.UUID) ([]models.AuditLog, error) {
+	args := m.Called(entityType, entityID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.AuditLog), args.Error(1)
+}
+
+func newTestDeviceTransferService() (DeviceTransferService, *MockDeviceTransferRepository, *MockDeviceRepository, *MockUserRepository, *MockAuditLogRepository) {
+	transferRepo := new(MockDeviceTransferRepository)
+	deviceRepo := new(MockDeviceRepository)
+	userRepo := new(MockUserRepository)
+	auditRepo := new(MockAuditLogRepository)
+	authz := NewAuthorizer(new(MockOrganizationRepository), noDeviceShares())
+	return NewDeviceTransferService(transferRepo, deviceRepo, userRepo, auditRepo, authz), transferRepo, deviceRepo, userRepo, auditRepo
+}
+
+// expectAudit expects a single audit entry with the given action on deviceID.
+func expectAudit(auditRepo *MockAuditLogRepository, action string, deviceID uuid.UUID) {
+	auditRepo.On("Create", mock.MatchedBy(func(entry *models.AuditLog) bool {
+		return entry.Action == action && entry.EntityType == models.AuditEntityDevice && entry.EntityID == deviceID
+	})).Return(nil).Once()
+}
+
+func TestDeviceTransferService_RequestTransfer(t *testing.T) {
+	owner := &models.User{ID: uuid.New(), Email: "seller@example.com"}
+	deviceID := uuid.New()
+	device := &models.Device{UUID: deviceID, UserID: owner.ID}
+
+	t.Run("Success - Pending transfer with default expiry", func(t *testing.T) {
+		service, transferRepo, deviceRepo, userRepo, auditRepo := newTestDeviceTransferService()
+		deviceRepo.On("FindByID", deviceID).Return(device, nil)
+		userRepo.On("FindByID", owner.ID).Return(owner, nil)
+		transferRepo.On("FindPendingByDeviceID", deviceID).Return(nil, gorm.ErrRecordNotFound)
+		transferRepo.On("Create", mock.MatchedBy(func(transfer *models.DeviceTransfer) bool {
+			return transfer.FromUserID == owner.ID && transfer.ToEmail == "buyer@example.com" && transfer.Status == models.TransferPending
+		})).Return(nil)
+		expectAudit(auditRepo, models.AuditTransferRequested, deviceID)
+
+		transfer, err := service.RequestTransfer(owner.ID, deviceID, dto.DeviceTransferRequest{Email: "buyer@example.com"})
+
+		assert.NoError(t, err)
+		assert.Equal(t, models.TransferPending, transfer.Status)
+		assert.WithinDuration(t, time.Now().Add(defaultTransferTTL), transfer.ExpiresAt, time.Minute)
+		auditRepo.AssertExpectations(t)
+	})
+
+	t.Run("Success - Expired pending transfer is replaced", func(t *testing.T) {
+		service, transferRepo, deviceRepo, userRepo, auditRepo := newTestDeviceTransferService()
+		stale := &models.DeviceTransfer{ID: uuid.New(), DeviceID: deviceID, FromUserID: owner.ID, Status: models.TransferPending, ExpiresAt: time.Now().Add(-time.Hour)}
+		deviceRepo.On("FindByID", deviceID).Return(device, nil)
+		userRepo.On("FindByID", owner.ID).Return(owner, nil)
+		transferRepo.On("FindPendingByDeviceID", deviceID).Return(stale, nil)
+		transferRepo.On("UpdateStatus", stale.ID, models.TransferPending, models.TransferExpired).Return(nil)
+		transferRepo.On("Create", mock.Anything).Return(nil)
+		expectAudit(auditRepo, models.AuditTransferExpired, deviceID)
+		expectAudit(auditRepo, models.AuditTransferRequested, deviceID)
+
+		transfer, err := service.RequestTransfer(owner.ID, deviceID, dto.DeviceTransferRequest{Email: "buyer@example.com"})
+
+		assert.NoError(t, err)
+		assert.NotEqual(t, stale.ID, transfer.ID)
+		transferRepo.AssertExpectations(t)
+		auditRepo.AssertExpectations(t)
+	})
+
+	t.Run("Error - Transfer already pending", func(t *testing.T) {
+		service, transferRepo, deviceRepo, userRepo, _ := newTestDeviceTransferService()
+		pending := &models.DeviceTransfer{ID: uuid.New(), DeviceID: deviceID, Status: models.TransferPending, ExpiresAt: time.Now().Add(time.Hour)}
+		deviceRepo.On("FindByID", deviceID).Return(device, nil)
+		userRepo.On("FindByID", owner.ID).Return(owner, nil)
+		transferRepo.On("FindPendingByDeviceID", deviceID).Return(pending, nil)
+
+		transfer, err := service.RequestTransfer(owner.ID, deviceID, dto.DeviceTransferRequest{Email: "buyer@example.com"})
+
+		assert.Nil(t, transfer)
+		assert.Equal(t, custom_errors.ErrDeviceTransferPending, err)
+		transferRepo.AssertNotCalled(t, "Create", mock.Anything)
+	})
+
+	t.Run("Error - Not the owner", func(t *testing.T) {
+		service, _, deviceRepo, _, _ := newTestDeviceTransferService()
+		deviceRepo.On("FindByID", deviceID).Return(device, nil)
+
+		transfer, err := service.RequestTransfer(uuid.New(), deviceID, dto.DeviceTransferRequest{Email: "buyer@example.com"})
+
+		assert.Nil(t, transfer)
+		assert.Equal(t, custom_errors.ErrForbidden, err)
+	})
+
+	t.Run("Error - Transfer to self", func(t *testing.T) {
+		service, _, deviceRepo, userRepo, _ := newTestDeviceTransferService()
+		deviceRepo.On("FindByID", deviceID).Return(device, nil)
+		userRepo.On("FindByID", owner.ID).Return(owner, nil)
+
+		transfer, err := service.RequestTransfer(owner.ID, deviceID, dto.DeviceTransferRequest{Email: "Seller@Example.com"})
+
+		assert.Nil(t, transfer)
+		assert.Equal(t, custom_errors.NewValidationError("A device cannot be transferred to its owner"), err)
+	})
+
+	t.Run("Error - Organization device", func(t *testing.T) {
+		service, transferRepo, deviceRepo, _, _ := newTestDeviceTransferService()
+		orgID := uuid.New()
+		orgRepo := new(MockOrganizationRepository)
+		orgRepo.On("FindMembership", orgID, owner.ID).Return(&models.Membership{Role: models.RoleOwner}, nil)
+		service = NewDeviceTransferService(transferRepo, deviceRepo, new(MockUserRepository), new(MockAuditLogRepository), NewAuthorizer(orgRepo, noDeviceShares()))
+		deviceRepo.On("FindByID", deviceID).Return(&models.Device{UUID: deviceID, UserID: owner.ID, OrganizationID: &orgID}, nil)
+
+		transfer, err := service.RequestTransfer(owner.ID, deviceID, dto.DeviceTransferRequest{Email: "buyer@example.com"})
+
+		assert.Nil(t, transfer)
+		assert.Equal(t, custom_errors.NewValidationError("Organization devices cannot be transferred"), err)
+	})
+}
+
+func TestDeviceTransferService_AcceptTransfer(t *testing.T) {
+	sellerID := uuid.New()
+	buyer := &models.User{ID: uuid.New(), Email: "buyer@example.com"}
+	deviceID := uuid.New()
+
+	pendingTransfer := func() *models.DeviceTransfer {
+		return &models.DeviceTransfer{
+			ID:         uuid.New(),
+			DeviceID:   deviceID,
+			FromUserID: sellerID,
+			ToEmail:    "Buyer@example.com",
+			Status:     models.TransferPending,
+			ExpiresAt:  time.Now().Add(time.Hour),
+		}
+	}
+
+	t.Run("Success - Recipient becomes owner", func(t *testing.T) {
+		service, transferRepo, _, userRepo, auditRepo := newTestDeviceTransferService()
+		transfer := pendingTransfer()
+		transferRepo.On("FindByID", transfer.ID).Return(transfer, nil)
+		userRepo.On("FindByID", buyer.ID).Return(buyer, nil)
+		transferRepo.On("Complete", transfer, buyer.ID, mock.Anything).Return(nil)
+		expectAudit(auditRepo, models.AuditTransferAccepted, deviceID)
+
+		response, err := service.AcceptTransfer(buyer.ID, transfer.ID)
+
+		assert.NoError(t, err)
+		assert.Equal(t, models.TransferAccepted, response.Status)
+		assert.Equal(t, &buyer.ID, response.ToUserID)
+		assert.NotNil(t, response.CompletedAt)
+		auditRepo.AssertExpectations(t)
+	})
+
+	t.Run("Error - Addressed to someone else", func(t *testing.T) {
+		service, transferRepo, _, userRepo, _ := newTestDeviceTransferService()
+		transfer := pendingTransfer()
+		otherID := uuid.New()
+		transferRepo.On("FindByID", transfer.ID).Return(transfer, nil)
+		userRepo.On("FindByID", otherID).Return(&models.User{ID: otherID, Email: "other@example.com"}, nil)
+
+		response, err := service.AcceptTransfer(otherID, transfer.ID)
+
+		assert.Nil(t, response)
+		assert.Equal(t, custom_errors.ErrDeviceTransferNotFound, err)
+		transferRepo.AssertNotCalled(t, "Complete", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Error - Expired", func(t *testing.T) {
+		service, transferRepo, _, userRepo, auditRepo := newTestDeviceTransferService()
+		transfer := pendingTransfer()
+		transfer.ExpiresAt = time.Now().Add(-time.Minute)
+		transferRepo.On("FindByID", transfer.ID).Return(transfer, nil)
+		userRepo.On("FindByID", buyer.ID).Return(buyer, nil)
+		transferRepo.On("UpdateStatus", transfer.ID, models.TransferPending, models.TransferExpired).Return(nil)
+		expectAudit(auditRepo, models.AuditTransferExpired, deviceID)
+
+		response, err := service.AcceptTransfer(buyer.ID, transfer.ID)
+
+		assert.Nil(t, response)
+		assert.Equal(t, custom_errors.ErrDeviceTransferExpired, err)
+		transferRepo.AssertNotCalled(t, "Complete", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Error - Already declined", func(t *testing.T) {
+		service, transferRepo, _, userRepo, _ := newTestDeviceTransferService()
+		transfer := pendingTransfer()
+		transfer.Status = models.TransferDeclined
+		transferRepo.On("FindByID", transfer.ID).Return(transfer, nil)
+		userRepo.On("FindByID", buyer.ID).Return(buyer, nil)
+
+		response, err := service.AcceptTransfer(buyer.ID, transfer.ID)
+
+		assert.Nil(t, response)
+		assert.Equal(t, custom_errors.ErrDeviceTransferNotPending, err)
+	})
+
+	t.Run("Error - Sender no longer owns the device", func(t *testing.T) {
+		service, transferRepo, _, userRepo, auditRepo := newTestDeviceTransferService()
+		transfer := pendingTransfer()
+		transferRepo.On("FindByID", transfer.ID).Return(transfer, nil)
+		userRepo.On("FindByID", buyer.ID).Return(buyer, nil)
+		transferRepo.On("Complete", transfer, buyer.ID, mock.Anything).Return(gorm.ErrRecordNotFound)
+		transferRepo.On("UpdateStatus", transfer.ID, models.TransferPending, models.TransferCancelled).Return(nil)
+		expectAudit(auditRepo, models.AuditTransferCancelled, deviceID)
+
+		response, err := service.AcceptTransfer(buyer.ID, transfer.ID)
+
+		assert.Nil(t, response)
+		assert.Equal(t, custom_errors.ErrDeviceTransferNotPending, err)
+		auditRepo.AssertExpectations(t)
+	})
+}
+
+func TestDeviceTransferService_CancelTransfer(t *testing.T) {
+	sellerID := uuid.New()
+	transfer := &models.DeviceTransfer{ID: uuid.New(), DeviceID: uuid.New(), FromUserID: sellerID, ToEmail: "buyer@example.com", Status: models.TransferPending, ExpiresAt: time.Now().Add(time.Hour)}
+
+	t.Run("Success - Sender cancels", func(t *testing.T) {
+		service, transferRepo, _, _, auditRepo := newTestDeviceTransferService()
+		transferRepo.On("FindByID", transfer.ID).Return(transfer, nil)
+		transferRepo.On("UpdateStatus", transfer.ID, models.TransferPending, models.TransferCancelled).Return(nil)
+		expectAudit(auditRepo, models.AuditTransferCancelled, transfer.DeviceID)
+
+		err := service.CancelTransfer(sellerID, transfer.ID)
+
+		assert.NoError(t, err)
+		auditRepo.AssertExpectations(t)
+	})
+
+	t.Run("Error - Not the sender", func(t *testing.T) {
+		service, transferRepo, _, _, _ := newTestDeviceTransferService()
+		transferRepo.On("FindByID", transfer.ID).Return(transfer, nil)
+
+		err := service.CancelTransfer(uuid.New(), transfer.ID)
+
+		assert.Equal(t, custom_errors.ErrDeviceTransferNotFound, err)
+		transferRepo.AssertNotCalled(t, "UpdateStatus", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestDeviceTransferService_GetAuditLog(t *testing.T) {
+	ownerID := uuid.New()
+	deviceID := uuid.New()
+	device := &models.Device{UUID: deviceID, UserID: ownerID}
+
+	t.Run("Success - Device history", func(t *testing.T) {
+		service, _, deviceRepo, _, auditRepo := newTestDeviceTransferService()
+		deviceRepo.On("FindByID", deviceID).Return(device, nil)
+		auditRepo.On("FindByEntity", models.AuditEntityDevice, deviceID).Return([]models.AuditLog{
+			{ID: uuid.New(), ActorID: ownerID, Action: models.AuditTransferRequested, EntityType: models.AuditEntityDevice, EntityID: deviceID, Details: []byte(`{"to_email":"buyer@example.com"}`)},
+		}, nil)
+
+		entries, err := service.GetAuditLog(ownerID, deviceID)
+
+		assert.NoError(t, err)
+		assert.Len(t, entries, 1)
+		assert.Equal(t, "buyer@example.com", entries[0].Details["to_email"])
+	})
+
+	t.Run("Error - Share grantee cannot read history", func(t *testing.T) {
+		transferRepo := new(MockDeviceTransferRepository)
+		deviceRepo := new(MockDeviceRepository)
+		granteeID := uuid.New()
+		shareRepo := new(MockDeviceShareRepository)
+		shareRepo.On("FindActive", deviceID, granteeID, mock.Anything).Return(&models.DeviceShare{Permission: models.RoleOperator}, nil)
+		service := NewDeviceTransferService(transferRepo, deviceRepo, new(MockUserRepository), new(MockAuditLogRepository), NewAuthorizer(new(MockOrganizationRepository), shareRepo))
+		deviceRepo.On("FindByID", deviceID).Return(device, nil)
+
+		entries, err := service.GetAuditLog(granteeID, deviceID)
+
+		assert.Nil(t, entries)
+		assert.Equal(t, custom_errors.ErrForbidden, err)
+	})
+}
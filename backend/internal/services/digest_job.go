@@ -0,0 +1,57 @@
+package services
+
+import (
+	"time"
+
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/utils/logger"
+)
+
+// DigestJob periodically emails the daily and weekly digests that are due.
+type DigestJob struct {
+	digestService DigestService
+	interval      time.Duration
+	shutdown      chan struct{}
+}
+
+func NewDigestJob(digestService DigestService, interval time.Duration) *DigestJob {
+	return &DigestJob{
+		digestService: digestService,
+		interval:      interval,
+		shutdown:      make(chan struct{}),
+	}
+}
+
+// Run sends the due digests once immediately and then every interval until
+// Stop is called.
+func (j *DigestJob) Run() {
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	for {
+		j.process()
+		select {
+		case <-ticker.C:
+		case <-j.shutdown:
+			return
+		}
+	}
+}
+
+func (j *DigestJob) Stop() {
+	select {
+	case <-j.shutdown:
+	default:
+		close(j.shutdown)
+	}
+}
+
+func (j *DigestJob) process() {
+	sent, err := j.digestService.SendDueDigests(time.Now())
+	if err != nil {
+		logger.Logger.Error("Error sending digests", "error", err)
+		return
+	}
+	if sent > 0 {
+		logger.Logger.Info("Sent digests", "sent", sent)
+	}
+}
@@ -0,0 +1,370 @@
+package services
+
+import (
+	"fmt"
+	htmltemplate "html/template"
+	"math"
+	"sort"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/dto"
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/models"
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/repository"
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/utils/errors"
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/utils/logger"
+	"github.com/google/uuid"
+)
+
+const (
+	DigestDaily  = "daily"
+	DigestWeekly = "weekly"
+
+	// MaxDigestItems caps the alerts, offline devices and reboots listed in
+	// a digest.
+	MaxDigestItems = 10
+	// MaxDigestOffenders is the number of devices listed per metric.
+	MaxDigestOffenders = 3
+)
+
+type DigestService interface {
+	PreviewDigest(userID uuid.UUID, period string) (*dto.DigestReport, error)
+	SendDueDigests(now time.Time) (sent int, err error)
+}
+
+type digestService struct {
+	preferenceRepo    repository.NotificationPreferenceRepository
+	alertRepo         repository.AlertRepository
+	deviceRepo        repository.DeviceRepository
+	heartbeatRepo     repository.HeartbeatRepository
+	channelService    NotificationChannelService
+	heartbeatInterval time.Duration
+}
+
+// NewDigestService builds the digest service. heartbeatInterval is the
+// period at which devices are expected to publish heartbeats; devices silent
+// for two intervals are reported offline.
+func NewDigestService(preferenceRepo repository.NotificationPreferenceRepository, alertRepo repository.AlertRepository, deviceRepo repository.DeviceRepository, heartbeatRepo repository.HeartbeatRepository, channelService NotificationChannelService, heartbeatInterval time.Duration) DigestService {
+	return &digestService{
+		preferenceRepo:    preferenceRepo,
+		alertRepo:         alertRepo,
+		deviceRepo:        deviceRepo,
+		heartbeatRepo:     heartbeatRepo,
+		channelService:    channelService,
+		heartbeatInterval: heartbeatInterval,
+	}
+}
+
+// PreviewDigest compiles the digest of the user for the last day, or the
+// last week for the weekly period, without sending it.
+func (s *digestService) PreviewDigest(userID uuid.UUID, period string) (*dto.DigestReport, error) {
+	if period == "" {
+		period = DigestDaily
+	}
+	if period != DigestDaily && period != DigestWeekly {
+		return nil, errors.NewValidationError("period must be daily or weekly")
+	}
+
+	to := time.Now()
+	return s.buildReport(userID, period, digestStart(period, to), to)
+}
+
+// SendDueDigests emails the digests whose scheduled time passed since they
+// were last sent. A digest that fails is tried again on the next run.
+func (s *digestService) SendDueDigests(now time.Time) (int, error) {
+	preferences, err := s.preferenceRepo.FindDigestSubscribers()
+	if err != nil {
+		return 0, errors.ErrDatabaseError
+	}
+
+	sent := 0
+	for i := range preferences {
+		preference := &preferences[i]
+		for _, period := range []string{DigestDaily, DigestWeekly} {
+			weekly := period == DigestWeekly
+			enabled, sentAt := preference.DailyDigest, preference.DailyDigestSentAt
+			if weekly {
+				enabled, sentAt = preference.WeeklyDigest, preference.WeeklyDigestSentAt
+			}
+			last := preference.CreatedAt
+			if sentAt != nil {
+				last = *sentAt
+			}
+			slot := preference.LastDigestSlot(now, weekly)
+			if !enabled || !slot.After(last) {
+				continue
+			}
+
+			if err := s.sendDigest(preference, period, slot); err != nil {
+				logger.Logger.Error("Error sending digest", "user_id", preference.UserID.String(), "period", period, "error", err)
+				continue
+			}
+			if err := s.preferenceRepo.MarkDigestSent(preference.UserID, weekly, now); err != nil {
+				return sent, errors.ErrDatabaseError
+			}
+			sent++
+		}
+	}
+	return sent, nil
+}
+
+func (s *digestService) sendDigest(preference *models.NotificationPreference, period string, slot time.Time) error {
+	report, err := s.buildReport(preference.UserID, period, digestStart(period, slot), slot)
+	if err != nil {
+		return err
+	}
+	loc, err := time.LoadLocation(preference.Timezone)
+	if err != nil {
+		loc = time.UTC
+	}
+	rendered, err := renderDigest(report, loc)
+	if err != nil {
+		return err
+	}
+
+	// Enqueue only uses the owner and channels of the rule.
+	rule := models.Notification{UserID: preference.UserID, ChannelIDs: []uuid.UUID{*preference.DigestChannelID}}
+	return s.channelService.Enqueue(rule, dto.NotificationAlert{
+		UserID:    preference.UserID,
+		Name:      rendered.Subject,
+		Timestamp: slot.Format(time.RFC3339),
+		Rendered:  rendered,
+	})
+}
+
+func (s *digestService) buildReport(userID uuid.UUID, period string, from, to time.Time) (*dto.DigestReport, error) {
+	report := &dto.DigestReport{
+		Period:         period,
+		From:           from,
+		To:             to,
+		LatestAlerts:   []dto.DigestAlert{},
+		DevicesOffline: []dto.DigestDevice{},
+		TopOffenders:   []dto.DigestOffender{},
+		Reboots:        []dto.DigestReboot{},
+	}
+
+	counts, err := s.alertRepo.CountFiredBySeverity(userID, from, to)
+	if err != nil {
+		return nil, errors.ErrDatabaseError
+	}
+	report.AlertsBySeverity = counts
+	for _, count := range counts {
+		report.AlertsFired += count
+	}
+
+	alerts, err := s.alertRepo.FindByUserID(userID, repository.AlertFilter{FiredFrom: &from, FiredTo: &to, Limit: MaxDigestItems})
+	if err != nil {
+		return nil, errors.ErrDatabaseError
+	}
+	for _, alert := range alerts {
+		report.LatestAlerts = append(report.LatestAlerts, dto.DigestAlert{
+			ID:           alert.ID,
+			Name:         alert.Name,
+			Severity:     alert.Severity,
+			Status:       alert.Status,
+			DeviceSN:     alert.DeviceSN,
+			TriggerCount: alert.TriggerCount,
+			FiredAt:      alert.FiredAt,
+		})
+	}
+
+	devices, err := s.deviceRepo.FindByUserID(userID)
+	if err != nil {
+		return nil, errors.ErrDatabaseError
+	}
+	devicesByID := make(map[uuid.UUID]*models.Device, len(devices))
+	deviceIDs := make([]uuid.UUID, 0, len(devices))
+	for i := range devices {
+		device := &devices[i]
+		devicesByID[device.UUID] = device
+		deviceIDs = append(deviceIDs, device.UUID)
+		if device.LastSeenAt != nil && to.Sub(*device.LastSeenAt) > 2*s.heartbeatInterval {
+			report.DevicesOfflineCount++
+			report.DevicesOffline = append(report.DevicesOffline, dto.DigestDevice{
+				DeviceID:   device.UUID,
+				Name:       device.Name,
+				SN:         device.SN,
+				LastSeenAt: device.LastSeenAt,
+			})
+		}
+	}
+	// The devices that went offline last come first.
+	sort.Slice(report.DevicesOffline, func(i, j int) bool {
+		return report.DevicesOffline[i].LastSeenAt.After(*report.DevicesOffline[j].LastSeenAt)
+	})
+	if len(report.DevicesOffline) > MaxDigestItems {
+		report.DevicesOffline = report.DevicesOffline[:MaxDigestItems]
+	}
+
+	summaries, err := s.heartbeatRepo.SummarizeByDeviceIDs(deviceIDs, from, to)
+	if err != nil {
+		return nil, errors.ErrDatabaseError
+	}
+	report.TopOffenders = topOffenders(summaries, devicesByID)
+	report.Reboots = reboots(summaries, devicesByID)
+	return report, nil
+}
+
+// digestStart returns the start of the period of a digest ending at end.
+func digestStart(period string, end time.Time) time.Time {
+	if period == DigestWeekly {
+		return end.AddDate(0, 0, -7)
+	}
+	return end.AddDate(0, 0, -1)
+}
+
+// topOffenders lists, per metric, the devices with the highest average CPU
+// and RAM usage, the highest temperature and the lowest free disk.
+func topOffenders(summaries []repository.HeartbeatSummary, devices map[uuid.UUID]*models.Device) []dto.DigestOffender {
+	metrics := []struct {
+		name   string
+		value  func(repository.HeartbeatSummary) float64
+		lowest bool
+	}{
+		{"cpu", func(s repository.HeartbeatSummary) float64 { return s.AvgCPU }, false},
+		{"ram", func(s repository.HeartbeatSummary) float64 { return s.AvgRAM }, false},
+		{"temperature", func(s repository.HeartbeatSummary) float64 { return s.MaxTemperature }, false},
+		{"disk_free", func(s repository.HeartbeatSummary) float64 { return s.MinDiskFree }, true},
+	}
+
+	offenders := []dto.DigestOffender{}
+	for _, metric := range metrics {
+		ranked := append([]repository.HeartbeatSummary(nil), summaries...)
+		sort.SliceStable(ranked, func(i, j int) bool {
+			if metric.lowest {
+				return metric.value(ranked[i]) < metric.value(ranked[j])
+			}
+			return metric.value(ranked[i]) > metric.value(ranked[j])
+		})
+		for i := 0; i < len(ranked) && i < MaxDigestOffenders; i++ {
+			device := devices[ranked[i].DeviceID]
+			if device == nil {
+				continue
+			}
+			offenders = append(offenders, dto.DigestOffender{
+				Metric:   metric.name,
+				DeviceID: device.UUID,
+				Name:     device.Name,
+				SN:       device.SN,
+				Value:    math.Round(metric.value(ranked[i])*100) / 100,
+			})
+		}
+	}
+	return offenders
+}
+
+// reboots lists the devices that rebooted during the period, those that
+// rebooted the most first.
+func reboots(summaries []repository.HeartbeatSummary, devices map[uuid.UUID]*models.Device) []dto.DigestReboot {
+	rebooted := []dto.DigestReboot{}
+	for _, summary := range summaries {
+		device := devices[summary.DeviceID]
+		if summary.Reboots == 0 || summary.LastBootTime == nil || device == nil {
+			continue
+		}
+		rebooted = append(rebooted, dto.DigestReboot{
+			DeviceID:     device.UUID,
+			Name:         device.Name,
+			SN:           device.SN,
+			Reboots:      summary.Reboots,
+			LastBootTime: *summary.LastBootTime,
+		})
+	}
+	sort.SliceStable(rebooted, func(i, j int) bool {
+		if rebooted[i].Reboots != rebooted[j].Reboots {
+			return rebooted[i].Reboots > rebooted[j].Reboots
+		}
+		return rebooted[i].LastBootTime.After(rebooted[j].LastBootTime)
+	})
+	if len(rebooted) > MaxDigestItems {
+		rebooted = rebooted[:MaxDigestItems]
+	}
+	return rebooted
+}
+
+const digestText = `{{title .Period}} digest, {{time .From}} to {{time .To}}
+
+Alerts fired: {{.AlertsFired}}{{range $severity, $count := .AlertsBySeverity}} | {{$severity}}: {{$count}}{{end}}
+{{range .LatestAlerts}}- [{{upper .Severity}}] {{.Name}} on device {{.DeviceSN}} at {{time .FiredAt}}, triggered {{.TriggerCount}} times, {{.Status}}
+{{end}}
+Devices offline: {{.DevicesOfflineCount}}
+{{range .DevicesOffline}}- {{.Name}} ({{.SN}}), last seen {{time .LastSeenAt}}
+{{end}}
+Top offenders:
+{{range .TopOffenders}}- {{.Metric}}: {{.Name}} ({{.SN}}) {{.Value}}
+{{else}}- No heartbeats in this period
+{{end}}
+Reboots:
+{{range .Reboots}}- {{.Name}} ({{.SN}}): {{.Reboots}}, last at {{time .LastBootTime}}
+{{else}}- None
+{{end}}`
+
+const digestHTML = `<h2>{{title .Period}} digest</h2>
+<p>{{time .From}} to {{time .To}}</p>
+<h3>Alerts fired: {{.AlertsFired}}</h3>
+{{if .AlertsBySeverity}}<p>{{range $severity, $count := .AlertsBySeverity}}{{$severity}}: {{$count}} {{end}}</p>
+{{end}}{{if .LatestAlerts}}<table>
+<tr><th>Severity</th><th>Alert</th><th>Device</th><th>Fired at</th><th>Triggers</th><th>Status</th></tr>
+{{range .LatestAlerts}}<tr><td>{{upper .Severity}}</td><td>{{.Name}}</td><td>{{.DeviceSN}}</td><td>{{time .FiredAt}}</td><td>{{.TriggerCount}}</td><td>{{.Status}}</td></tr>
+{{end}}</table>
+{{end}}<h3>Devices offline: {{.DevicesOfflineCount}}</h3>
+{{if .DevicesOffline}}<ul>
+{{range .DevicesOffline}}<li>{{.Name}} ({{.SN}}), last seen {{time .LastSeenAt}}</li>
+{{end}}</ul>
+{{end}}<h3>Top offenders</h3>
+{{if .TopOffenders}}<table>
+<tr><th>Metric</th><th>Device</th><th>Value</th></tr>
+{{range .TopOffenders}}<tr><td>{{.Metric}}</td><td>{{.Name}} ({{.SN}})</td><td>{{.Value}}</td></tr>
+{{end}}</table>
+{{else}}<p>No heartbeats in this period</p>
+{{end}}<h3>Reboots</h3>
+{{if .Reboots}}<ul>
+{{range .Reboots}}<li>{{.Name}} ({{.SN}}): {{.Reboots}}, last at {{time .LastBootTime}}</li>
+{{end}}</ul>
+{{else}}<p>None</p>
+{{end}}`
+
+// renderDigest renders the digest as an email, with times in loc.
+func renderDigest(report *dto.DigestReport, loc *time.Location) (*dto.RenderedMessage, error) {
+	funcs := map[string]interface{}{
+		"title": func(s string) string {
+			if s == "" {
+				return s
+			}
+			return strings.ToUpper(s[:1]) + s[1:]
+		},
+		"upper": strings.ToUpper,
+		"time": func(t interface{}) string {
+			switch v := t.(type) {
+			case time.Time:
+				return v.In(loc).Format("2006-01-02 15:04 MST")
+			case *time.Time:
+				if v != nil {
+					return v.In(loc).Format("2006-01-02 15:04 MST")
+				}
+			}
+			return "never"
+		},
+	}
+
+	var text, html strings.Builder
+	textTemplate := template.Must(template.New("digest").Funcs(funcs).Parse(digestText))
+	if err := textTemplate.Execute(&text, report); err != nil {
+		return nil, err
+	}
+	htmlTemplate := htmltemplate.Must(htmltemplate.New("digest").Funcs(funcs).Parse(digestHTML))
+	if err := htmlTemplate.Execute(&html, report); err != nil {
+		return nil, err
+	}
+
+	periodName := "Daily"
+	if report.Period == DigestWeekly {
+		periodName = "Weekly"
+	}
+	return &dto.RenderedMessage{
+		Subject: fmt.Sprintf("%s digest: %d alerts fired, %d devices offline", periodName, report.AlertsFired, report.DevicesOfflineCount),
+		Text:    text.String(),
+		HTML:    html.String(),
+	}, nil
+}
@@ -0,0 +1,150 @@
+package services
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/dto"
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/models"
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/repository"
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/utils/errors"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type digestTestDeps struct {
+	preferenceRepo *MockNotificationPreferenceRepository
+	alertRepo      *MockAlertRepository
+	deviceRepo     *MockDeviceRepository
+	heartbeatRepo  *MockHeartbeatRepository
+	channelRepo    *MockNotificationChannelRepository
+}
+
+func newTestDigestService() (DigestService, digestTestDeps) {
+	deps := digestTestDeps{
+		preferenceRepo: new(MockNotificationPreferenceRepository),
+		alertRepo:      new(MockAlertRepository),
+		deviceRepo:     new(MockDeviceRepository),
+		heartbeatRepo:  new(MockHeartbeatRepository),
+		channelRepo:    new(MockNotificationChannelRepository),
+	}
+	channelService := NewNotificationChannelService(deps.channelRepo, map[string]ChannelSender{}, NewAuthorizer(new(MockOrganizationRepository), noDeviceShares()))
+	return NewDigestService(deps.preferenceRepo, deps.alertRepo, deps.deviceRepo, deps.heartbeatRepo, channelService, time.Minute), deps
+}
+
+func TestDigestService_PreviewDigest(t *testing.T) {
+	userID := uuid.New()
+
+	t.Run("Success - Report with offline devices, offenders and reboots", func(t *testing.T) {
+		service, deps := newTestDigestService()
+		now := time.Now()
+		online := now.Add(-30 * time.Second)
+		offline := now.Add(-2 * time.Hour)
+		bootTime := now.Add(-3 * time.Hour)
+		hot := models.Device{UUID: uuid.New(), Name: "Hot", SN: "000000000001", LastSeenAt: &online}
+		gone := models.Device{UUID: uuid.New(), Name: "Gone", SN: "000000000002", LastSeenAt: &offline}
+		alert := models.Alert{ID: uuid.New(), Name: "High CPU", Severity: models.SeverityCritical, Status: models.AlertFiring, DeviceSN: hot.SN, TriggerCount: 3, FiredAt: now.Add(-time.Hour)}
+
+		deps.alertRepo.On("CountFiredBySeverity", userID, mock.Anything, mock.Anything).Return(map[string]int64{models.SeverityCritical: 1, models.SeverityWarning: 2}, nil)
+		deps.alertRepo.On("FindByUserID", userID, mock.MatchedBy(func(f repository.AlertFilter) bool {
+			return f.FiredFrom != nil && f.FiredTo != nil && f.FiredTo.Sub(*f.FiredFrom) == 24*time.Hour && f.Limit == MaxDigestItems
+		})).Return([]models.Alert{alert}, nil)
+		deps.deviceRepo.On("FindByUserID", userID).Return([]models.Device{hot, gone}, nil)
+		deps.heartbeatRepo.On("SummarizeByDeviceIDs", []uuid.UUID{hot.UUID, gone.UUID}, mock.Anything, mock.Anything).Return([]repository.HeartbeatSummary{
+			{DeviceID: hot.UUID, Samples: 60, AvgCPU: 91.456, AvgRAM: 40, MaxTemperature: 80, MinDiskFree: 50, Reboots: 2, LastBootTime: &bootTime},
+			{DeviceID: gone.UUID, Samples: 10, AvgCPU: 10, AvgRAM: 70, MaxTemperature: 40, MinDiskFree: 5},
+		}, nil)
+
+		report, err := service.PreviewDigest(userID, "")
+
+		assert.NoError(t, err)
+		assert.Equal(t, DigestDaily, report.Period)
+		assert.Equal(t, int64(3), report.AlertsFired)
+		assert.Len(t, report.LatestAlerts, 1)
+		assert.Equal(t, 1, report.DevicesOfflineCount)
+		assert.Equal(t, gone.UUID, report.DevicesOffline[0].DeviceID)
+		assert.Len(t, report.TopOffenders, 8)
+		assert.Equal(t, dto.DigestOffender{Metric: "cpu", DeviceID: hot.UUID, Name: hot.Name, SN: hot.SN, Value: 91.46}, report.TopOffenders[0])
+		assert.Equal(t, "disk_free", report.TopOffenders[6].Metric)
+		assert.Equal(t, gone.UUID, report.TopOffenders[6].DeviceID)
+		assert.Len(t, report.Reboots, 1)
+		assert.Equal(t, int64(2), report.Reboots[0].Reboots)
+	})
+
+	t.Run("Error - Invalid period", func(t *testing.T) {
+		service, _ := newTestDigestService()
+
+		report, err := service.PreviewDigest(userID, "monthly")
+
+		assert.Nil(t, report)
+		assert.IsType(t, &errors.BusinessError{}, err)
+	})
+}
+
+func TestDigestService_SendDueDigests(t *testing.T) {
+	userID := uuid.New()
+	channelID := uuid.New()
+	// Monday 11:00 UTC, 08:00 in São Paulo.
+	now := time.Date(2025, 9, 15, 11, 0, 0, 0, time.UTC)
+
+	preference := func() models.NotificationPreference {
+		return models.NotificationPreference{
+			UserID:          userID,
+			Timezone:        "America/Sao_Paulo",
+			DailyDigest:     true,
+			WeeklyDigest:    true,
+			DigestHour:      8,
+			DigestWeekday:   int(time.Monday),
+			DigestChannelID: &channelID,
+			CreatedAt:       now.AddDate(0, -1, 0),
+		}
+	}
+
+	t.Run("Success - Daily and weekly digests emailed and marked sent", func(t *testing.T) {
+		service, deps := newTestDigestService()
+
+		deps.preferenceRepo.On("FindDigestSubscribers").Return([]models.NotificationPreference{preference()}, nil)
+		deps.alertRepo.On("CountFiredBySeverity", userID, mock.Anything, mock.Anything).Return(map[string]int64{models.SeverityWarning: 4}, nil)
+		deps.alertRepo.On("FindByUserID", userID, mock.Anything).Return([]models.Alert{}, nil)
+		deps.deviceRepo.On("FindByUserID", userID).Return([]models.Device{}, nil)
+		deps.heartbeatRepo.On("SummarizeByDeviceIDs", []uuid.UUID{}, mock.Anything, mock.Anything).Return([]repository.HeartbeatSummary{}, nil)
+		deps.channelRepo.On("FindByIDs", []uuid.UUID{channelID}).Return([]models.NotificationChannel{{ID: channelID, UserID: userID, Type: models.ChannelEmail, Enabled: true}}, nil)
+		deps.channelRepo.On("CreateDeliveries", mock.MatchedBy(func(deliveries []models.NotificationDelivery) bool {
+			var alert dto.NotificationAlert
+			if len(deliveries) != 1 || deliveries[0].ChannelID != channelID || json.Unmarshal(deliveries[0].Payload, &alert) != nil {
+				return false
+			}
+			return alert.Rendered != nil && strings.HasSuffix(alert.Rendered.Subject, "digest: 4 alerts fired, 0 devices offline") &&
+				strings.Contains(alert.Rendered.HTML, "<h3>Alerts fired: 4</h3>")
+		})).Return(nil).Twice()
+		deps.preferenceRepo.On("MarkDigestSent", userID, false, now).Return(nil)
+		deps.preferenceRepo.On("MarkDigestSent", userID, true, now).Return(nil)
+
+		sent, err := service.SendDueDigests(now)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 2, sent)
+		deps.channelRepo.AssertExpectations(t)
+		deps.preferenceRepo.AssertExpectations(t)
+	})
+
+	t.Run("Success - Digests already sent are skipped", func(t *testing.T) {
+		service, deps := newTestDigestService()
+		sentAt := now
+		subscriber := preference()
+		subscriber.DailyDigestSentAt = &sentAt
+		subscriber.WeeklyDigestSentAt = &sentAt
+
+		deps.preferenceRepo.On("FindDigestSubscribers").Return([]models.NotificationPreference{subscriber}, nil)
+
+		sent, err := service.SendDueDigests(now)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 0, sent)
+		deps.channelRepo.AssertNotCalled(t, "CreateDeliveries", mock.Anything)
+		deps.preferenceRepo.AssertNotCalled(t, "MarkDigestSent", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
@@ -0,0 +1,262 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/dto"
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/models"
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/repository"
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/utils/errors"
+	"github.com/google/uuid"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+const (
+	// MaxEscalationSteps caps the steps of a policy.
+	MaxEscalationSteps = 10
+	// MaxEscalationStepUsers caps the users notified by a step.
+	MaxEscalationStepUsers = 20
+	// MaxEscalationDelayMinutes caps the delay of a step at a day.
+	MaxEscalationDelayMinutes = 24 * 60
+	// MaxEscalationRepeat caps how many times a step is repeated.
+	MaxEscalationRepeat = 10
+)
+
+type EscalationPolicyService interface {
+	CreatePolicy(userID uuid.UUID, orgID *uuid.UUID, req dto.EscalationPolicyRequest) (*dto.EscalationPolicyResponse, error)
+	ListPolicies(userID uuid.UUID) ([]dto.EscalationPolicyResponse, error)
+	GetPolicy(userID, policyID uuid.UUID) (*dto.EscalationPolicyResponse, error)
+	UpdatePolicy(userID, policyID uuid.UUID, req dto.EscalationPolicyRequest) (*dto.EscalationPolicyResponse, error)
+	DeletePolicy(userID, policyID uuid.UUID) error
+	ResolvePolicy(owner Ownership, policyID uuid.UUID) error
+}
+
+type escalationPolicyService struct {
+	policyRepo     repository.EscalationPolicyRepository
+	membershipRepo membershipRepository
+	channelService NotificationChannelService
+	authz          Authorizer
+}
+
+func NewEscalationPolicyService(policyRepo repository.EscalationPolicyRepository, membershipRepo membershipRepository, channelService NotificationChannelService, authz Authorizer) EscalationPolicyService {
+	return &escalationPolicyService{
+		policyRepo:     policyRepo,
+		membershipRepo: membershipRepo,
+		channelService: channelService,
+		authz:          authz,
+	}
+}
+
+// CreatePolicy creates a personal policy, or an organization policy when
+// orgID is set. Like notification rules it requires the operator role.
+func (s *escalationPolicyService) CreatePolicy(userID uuid.UUID, orgID *uuid.UUID, req dto.EscalationPolicyRequest) (*dto.EscalationPolicyResponse, error) {
+	owner := Ownership{UserID: userID, OrganizationID: orgID}
+	if err := s.authz.Authorize(userID, owner, models.RoleOperator); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	policy := &models.EscalationPolicy{
+		ID:             uuid.New(),
+		UserID:         userID,
+		OrganizationID: orgID,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+	if err := s.apply(policy, req); err != nil {
+		return nil, err
+	}
+
+	if err := s.policyRepo.Create(policy); err != nil {
+		return nil, errors.ErrDatabaseError
+	}
+	return escalationPolicyResponse(policy), nil
+}
+
+func (s *escalationPolicyService) ListPolicies(userID uuid.UUID) ([]dto.EscalationPolicyResponse, error) {
+	policies, err := s.policyRepo.FindByUserID(userID)
+	if err != nil {
+		return nil, errors.ErrDatabaseError
+	}
+
+	responses := make([]dto.EscalationPolicyResponse, 0, len(policies))
+	for i := range policies {
+		responses = append(responses, *escalationPolicyResponse(&policies[i]))
+	}
+	return responses, nil
+}
+
+func (s *escalationPolicyService) GetPolicy(userID, policyID uuid.UUID) (*dto.EscalationPolicyResponse, error) {
+	policy, err := s.findPolicy(userID, policyID, models.RoleViewer)
+	if err != nil {
+		return nil, err
+	}
+	return escalationPolicyResponse(policy), nil
+}
+
+// UpdatePolicy replaces the name, description and steps of the policy.
+// Alerts already escalating continue from the step they reached. Requires
+// the operator role.
+func (s *escalationPolicyService) UpdatePolicy(userID, policyID uuid.UUID, req dto.EscalationPolicyRequest) (*dto.EscalationPolicyResponse, error) {
+	policy, err := s.findPolicy(userID, policyID, models.RoleOperator)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.apply(policy, req); err != nil {
+		return nil, err
+	}
+	policy.UpdatedAt = time.Now()
+
+	if err := s.policyRepo.Update(policy); err != nil {
+		return nil, errors.ErrDatabaseError
+	}
+	return escalationPolicyResponse(policy), nil
+}
+
+// DeletePolicy removes the policy. The rules using it keep firing without
+// escalation. Requires the operator role.
+func (s *escalationPolicyService) DeletePolicy(userID, policyID uuid.UUID) error {
+	policy, err := s.findPolicy(userID, policyID, models.RoleOperator)
+	if err != nil {
+		return err
+	}
+
+	if err := s.policyRepo.Delete(policy.ID); err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return errors.ErrEscalationPolicyNotFound
+		}
+		return errors.ErrDatabaseError
+	}
+	return nil
+}
+
+// ResolvePolicy checks that the policy exists and belongs to owner.
+func (s *escalationPolicyService) ResolvePolicy(owner Ownership, policyID uuid.UUID) error {
+	policy, err := s.policyRepo.FindByID(policyID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return errors.ErrEscalationPolicyNotFound
+		}
+		return errors.ErrDatabaseError
+	}
+	if !policyOwnership(policy).SameOwner(owner) {
+		return errors.ErrEscalationPolicyNotFound
+	}
+	return nil
+}
+
+// apply validates req and copies it into policy. Steps must notify someone:
+// members of the policy's organization, or only its owner for a personal
+// policy, and channels of the same owner.
+func (s *escalationPolicyService) apply(policy *models.EscalationPolicy, req dto.EscalationPolicyRequest) error {
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return errors.NewValidationError("Escalation policy name is required")
+	}
+	if len(req.Steps) == 0 {
+		return errors.NewValidationError("An escalation policy needs at least one step")
+	}
+	if len(req.Steps) > MaxEscalationSteps {
+		return errors.NewValidationError(fmt.Sprintf("An escalation policy can have at most %d steps", MaxEscalationSteps))
+	}
+
+	owner := policyOwnership(policy)
+	steps := make([]models.EscalationStep, 0, len(req.Steps))
+	for i, step := range req.Steps {
+		if step.DelayMinutes < 0 || step.DelayMinutes > MaxEscalationDelayMinutes {
+			return errors.NewValidationError(fmt.Sprintf("Step %d: delay_minutes must be between 0 and %d", i+1, MaxEscalationDelayMinutes))
+		}
+		if step.Repeat < 0 || step.Repeat > MaxEscalationRepeat {
+			return errors.NewValidationError(fmt.Sprintf("Step %d: repeat must be between 0 and %d", i+1, MaxEscalationRepeat))
+		}
+		if step.Repeat > 0 && step.DelayMinutes == 0 {
+			return errors.NewValidationError(fmt.Sprintf("Step %d: a repeated step needs a delay", i+1))
+		}
+
+		userIDs := uniqueIDs(step.UserIDs)
+		if len(userIDs) > MaxEscalationStepUsers {
+			return errors.NewValidationError(fmt.Sprintf("Step %d: a step can notify at most %d users", i+1, MaxEscalationStepUsers))
+		}
+		for _, id := range userIDs {
+			if err := s.checkRecipient(owner, id); err != nil {
+				return err
+			}
+		}
+		channelIDs, err := s.channelService.ResolveChannels(owner, step.ChannelIDs)
+		if err != nil {
+			return err
+		}
+		if len(userIDs) == 0 && len(channelIDs) == 0 {
+			return errors.NewValidationError(fmt.Sprintf("Step %d: list at least one user or channel", i+1))
+		}
+
+		steps = append(steps, models.EscalationStep{
+			DelayMinutes: step.DelayMinutes,
+			UserIDs:      userIDs,
+			ChannelIDs:   channelIDs,
+			Repeat:       step.Repeat,
+		})
+	}
+
+	policy.Name = name
+	policy.Description = strings.TrimSpace(req.Description)
+	policy.Steps = datatypes.JSONSlice[models.EscalationStep](steps)
+	return nil
+}
+
+// checkRecipient checks that userID can receive the alerts of owner.
+func (s *escalationPolicyService) checkRecipient(owner Ownership, userID uuid.UUID) error {
+	if owner.OrganizationID == nil {
+		if userID != owner.UserID {
+			return errors.NewValidationError("A personal escalation policy can only notify its owner")
+		}
+		return nil
+	}
+
+	if _, err := s.membershipRepo.FindMembership(*owner.OrganizationID, userID); err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return errors.NewValidationError("User " + userID.String() + " is not a member of the organization")
+		}
+		return errors.ErrDatabaseError
+	}
+	return nil
+}
+
+func (s *escalationPolicyService) findPolicy(userID, policyID uuid.UUID, role string) (*models.EscalationPolicy, error) {
+	policy, err := s.policyRepo.FindByID(policyID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.ErrEscalationPolicyNotFound
+		}
+		return nil, errors.ErrDatabaseError
+	}
+	if err := s.authz.Authorize(userID, policyOwnership(policy), role); err != nil {
+		return nil, err
+	}
+	return policy, nil
+}
+
+func escalationPolicyResponse(policy *models.EscalationPolicy) *dto.EscalationPolicyResponse {
+	response := &dto.EscalationPolicyResponse{
+		ID:             policy.ID,
+		OrganizationID: policy.OrganizationID,
+		Name:           policy.Name,
+		Description:    policy.Description,
+		Steps:          make([]dto.EscalationStep, 0, len(policy.Steps)),
+		CreatedAt:      policy.CreatedAt,
+		UpdatedAt:      policy.UpdatedAt,
+	}
+	for _, step := range policy.Steps {
+		response.Steps = append(response.Steps, dto.EscalationStep{
+			DelayMinutes: step.DelayMinutes,
+			UserIDs:      step.UserIDs,
+			ChannelIDs:   step.ChannelIDs,
+			Repeat:       step.Repeat,
+		})
+	}
+	return response
+}
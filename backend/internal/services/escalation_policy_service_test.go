@@ -0,0 +1,148 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/dto"
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/models"
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/utils/errors"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+type MockEscalationPolicyRepository struct {
+	mock.Mock
+}
+
+func (m *MockEscalationPolicyRepository) Create(policy *models.EscalationPolicy) error {
+	args := m.Called(policy)
+	return args.Error(0)
+}
+
+func (m *MockEscalationPolicyRepository) FindByID(id uuid.UUID) (*models.EscalationPolicy, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.EscalationPolicy), args.Error(1)
+}
+
+func (m *MockEscalationPolicyRepository) FindByUserID(userID uuid.UUID) ([]models.EscalationPolicy, error) {
+	args := m.Called(userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.EscalationPolicy), args.Error(1)
+}
+
+func (m *MockEscalationPolicyRepository) Update(policy *models.EscalationPolicy) error {
+	args := m.Called(policy)
+	return args.Error(0)
+}
+
+func (m *MockEscalationPolicyRepository) Delete(id uuid.UUID) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func noEscalationPolicies() EscalationPolicyService {
+	return NewEscalationPolicyService(new(MockEscalationPolicyRepository), new(MockOrganizationRepository), noNotificationChannels(), NewAuthorizer(new(MockOrganizationRepository), noDeviceShares()))
+}
+
+func newTestEscalationPolicyService() (EscalationPolicyService, *MockEscalationPolicyRepository, *MockOrganizationRepository) {
+	policyRepo := new(MockEscalationPolicyRepository)
+	orgRepo := new(MockOrganizationRepository)
+	authz := NewAuthorizer(orgRepo, noDeviceShares())
+	return NewEscalationPolicyService(policyRepo, orgRepo, noNotificationChannels(), authz), policyRepo, orgRepo
+}
+
+func TestEscalationPolicyService_CreatePolicy(t *testing.T) {
+	userID := uuid.New()
+	orgID := uuid.New()
+	memberID := uuid.New()
+
+	t.Run("Success - Organization policy notifying members", func(t *testing.T) {
+		service, policyRepo, orgRepo := newTestEscalationPolicyService()
+
+		orgRepo.On("FindMembership", orgID, userID).Return(&models.Membership{Role: models.RoleOperator}, nil)
+		orgRepo.On("FindMembership", orgID, memberID).Return(&models.Membership{Role: models.RoleViewer}, nil)
+		policyRepo.On("Create", mock.MatchedBy(func(policy *models.EscalationPolicy) bool {
+			return *policy.OrganizationID == orgID && len(policy.Steps) == 2 && len(policy.Steps[1].UserIDs) == 1
+		})).Return(nil)
+
+		req := dto.EscalationPolicyRequest{
+			Name: " On-call ",
+			Steps: []dto.EscalationStep{
+				{DelayMinutes: 5, UserIDs: []uuid.UUID{userID}, Repeat: 2},
+				{DelayMinutes: 15, UserIDs: []uuid.UUID{memberID, memberID}},
+			},
+		}
+		response, err := service.CreatePolicy(userID, &orgID, req)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "On-call", response.Name)
+		assert.Len(t, response.Steps, 2)
+		policyRepo.AssertExpectations(t)
+	})
+
+	t.Run("Error - Recipient outside the organization", func(t *testing.T) {
+		service, policyRepo, orgRepo := newTestEscalationPolicyService()
+
+		orgRepo.On("FindMembership", orgID, userID).Return(&models.Membership{Role: models.RoleOperator}, nil)
+		orgRepo.On("FindMembership", orgID, memberID).Return(nil, gorm.ErrRecordNotFound)
+
+		req := dto.EscalationPolicyRequest{Name: "On-call", Steps: []dto.EscalationStep{{UserIDs: []uuid.UUID{memberID}}}}
+		_, err := service.CreatePolicy(userID, &orgID, req)
+
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "not a member")
+		policyRepo.AssertNotCalled(t, "Create", mock.Anything)
+	})
+
+	t.Run("Error - Personal policy notifying another user", func(t *testing.T) {
+		service, policyRepo, _ := newTestEscalationPolicyService()
+
+		req := dto.EscalationPolicyRequest{Name: "On-call", Steps: []dto.EscalationStep{{UserIDs: []uuid.UUID{memberID}}}}
+		_, err := service.CreatePolicy(userID, nil, req)
+
+		assert.Error(t, err)
+		policyRepo.AssertNotCalled(t, "Create", mock.Anything)
+	})
+
+	t.Run("Error - Repeated step without delay", func(t *testing.T) {
+		service, policyRepo, _ := newTestEscalationPolicyService()
+
+		req := dto.EscalationPolicyRequest{Name: "On-call", Steps: []dto.EscalationStep{{UserIDs: []uuid.UUID{userID}, Repeat: 3}}}
+		_, err := service.CreatePolicy(userID, nil, req)
+
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "needs a delay")
+		policyRepo.AssertNotCalled(t, "Create", mock.Anything)
+	})
+
+	t.Run("Error - Step without recipients", func(t *testing.T) {
+		service, _, _ := newTestEscalationPolicyService()
+
+		req := dto.EscalationPolicyRequest{Name: "On-call", Steps: []dto.EscalationStep{{DelayMinutes: 5}}}
+		_, err := service.CreatePolicy(userID, nil, req)
+
+		assert.Error(t, err)
+	})
+}
+
+func TestEscalationPolicyService_ResolvePolicy(t *testing.T) {
+	userID := uuid.New()
+
+	t.Run("Error - Policy of another owner", func(t *testing.T) {
+		service, policyRepo, _ := newTestEscalationPolicyService()
+
+		policy := &models.EscalationPolicy{ID: uuid.New(), UserID: uuid.New()}
+		policyRepo.On("FindByID", policy.ID).Return(policy, nil)
+
+		err := service.ResolvePolicy(Ownership{UserID: userID}, policy.ID)
+
+		assert.Equal(t, errors.ErrEscalationPolicyNotFound, err)
+	})
+}
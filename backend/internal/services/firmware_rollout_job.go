@@ -0,0 +1,59 @@
+package services
+
+import (
+	"time"
+
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/utils/logger"
+)
+
+// FirmwareRolloutJob periodically checks the running rollouts: it fails the
+// devices that did not update in time, pauses rollouts that fail too often
+// and starts the next waves.
+type FirmwareRolloutJob struct {
+	rolloutService FirmwareRolloutService
+	interval       time.Duration
+	shutdown       chan struct{}
+}
+
+func NewFirmwareRolloutJob(rolloutService FirmwareRolloutService, interval time.Duration) *FirmwareRolloutJob {
+	return &FirmwareRolloutJob{
+		rolloutService: rolloutService,
+		interval:       interval,
+		shutdown:       make(chan struct{}),
+	}
+}
+
+// Run processes rollouts once immediately and then every interval until
+// Stop is called.
+func (j *FirmwareRolloutJob) Run() {
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	for {
+		j.process()
+		select {
+		case <-ticker.C:
+		case <-j.shutdown:
+			return
+		}
+	}
+}
+
+func (j *FirmwareRolloutJob) Stop() {
+	select {
+	case <-j.shutdown:
+	default:
+		close(j.shutdown)
+	}
+}
+
+func (j *FirmwareRolloutJob) process() {
+	paused, completed, err := j.rolloutService.ProcessRollouts()
+	if err != nil {
+		logger.Logger.Error("Error processing firmware rollouts", "error", err)
+		return
+	}
+	if paused > 0 || completed > 0 {
+		logger.Logger.Info("Processed firmware rollouts", "paused", paused, "completed", completed)
+	}
+}
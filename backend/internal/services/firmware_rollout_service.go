@@ -0,0 +1,813 @@
+package services
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	mathrand "math/rand"
+	"strings"
+	"time"
+
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/dto"
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/models"
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/repository"
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/utils"
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/utils/errors"
+	logger "github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/utils/logger"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	DefaultRolloutWaveTimeout       = 30 * time.Minute
+	MinRolloutWaveTimeout           = time.Minute
+	MaxRolloutWaveTimeout           = 24 * time.Hour
+	DefaultRolloutMaxFailurePercent = 10
+	MaxRolloutWaves                 = 10
+)
+
+// DefaultRolloutWaves are the cumulative percentages used when a rollout
+// does not set its own: a canary wave, half of the fleet, then everyone.
+var DefaultRolloutWaves = []int{10, 50, 100}
+
+// FirmwareDownloadPath is where devices download the artifact of a rollout,
+// followed by the token sent in their update_firmware command.
+const FirmwareDownloadPath = "/api/v1/firmware/download/"
+
+type FirmwareRolloutService interface {
+	CreateRollout(userID uuid.UUID, orgID *uuid.UUID, req dto.FirmwareRolloutRequest) (*dto.FirmwareRolloutResponse, error)
+	ListRollouts(userID uuid.UUID) ([]dto.FirmwareRolloutResponse, error)
+	GetRollout(userID, rolloutID uuid.UUID) (*dto.FirmwareRolloutResponse, error)
+	PauseRollout(userID, rolloutID uuid.UUID) (*dto.FirmwareRolloutResponse, error)
+	ResumeRollout(userID, rolloutID uuid.UUID) (*dto.FirmwareRolloutResponse, error)
+	CancelRollout(userID, rolloutID uuid.UUID) (*dto.FirmwareRolloutResponse, error)
+	ProcessRollouts() (paused int, completed int, err error)
+}
+
+type firmwareRolloutService struct {
+	rolloutRepo    repository.FirmwareRolloutRepository
+	firmwareRepo   repository.FirmwareRepository
+	deviceRepo     repository.DeviceRepository
+	groupRepo      repository.DeviceGroupRepository
+	commandRepo    repository.DeviceCommandRepository
+	alertRepo      repository.AlertRepository
+	commandService DeviceCommandService
+	healthWindow   time.Duration
+	authz          Authorizer
+	downloadURL    string
+}
+
+// NewFirmwareRolloutService creates the rollout service. heartbeatInterval
+// is the expected heartbeat period; updated devices are watched for two
+// intervals before their wave moves on, and one silent for longer counts as
+// unhealthy. baseURL is the address devices reach the API on; download links
+// sent to them start with it.
+func NewFirmwareRolloutService(rolloutRepo repository.FirmwareRolloutRepository, firmwareRepo repository.FirmwareRepository, deviceRepo repository.DeviceRepository, groupRepo repository.DeviceGroupRepository, commandRepo repository.DeviceCommandRepository, alertRepo repository.AlertRepository, commandService DeviceCommandService, heartbeatInterval time.Duration, authz Authorizer, baseURL string) FirmwareRolloutService {
+	return &firmwareRolloutService{
+		rolloutRepo:    rolloutRepo,
+		firmwareRepo:   firmwareRepo,
+		deviceRepo:     deviceRepo,
+		groupRepo:      groupRepo,
+		commandRepo:    commandRepo,
+		alertRepo:      alertRepo,
+		commandService: commandService,
+		healthWindow:   2 * heartbeatInterval,
+		authz:          authz,
+		downloadURL:    strings.TrimRight(baseURL, "/") + FirmwareDownloadPath,
+	}
+}
+
+// CreateRollout targets the devices of a group (with its subgroups) and/or
+// matching a label selector, splits them randomly into waves and starts the
+// first one. Devices already running the version are skipped. Requires the
+// admin role.
+func (s *firmwareRolloutService) CreateRollout(userID uuid.UUID, orgID *uuid.UUID, req dto.FirmwareRolloutRequest) (*dto.FirmwareRolloutResponse, error) {
+	owner := Ownership{UserID: userID, OrganizationID: orgID}
+	if err := s.authz.Authorize(userID, owner, models.RoleAdmin); err != nil {
+		return nil, err
+	}
+
+	artifact, err := s.firmwareRepo.FindByID(req.ArtifactID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.ErrFirmwareNotFound
+		}
+		return nil, errors.ErrDatabaseError
+	}
+	if !firmwareOwnership(artifact).SameOwner(owner) {
+		return nil, errors.ErrFirmwareNotFound
+	}
+
+	waves := req.Waves
+	if len(waves) == 0 {
+		waves = DefaultRolloutWaves
+	}
+	if err := validateRolloutWaves(waves); err != nil {
+		return nil, err
+	}
+	timeout := DefaultRolloutWaveTimeout
+	if req.WaveTimeoutSeconds != 0 {
+		timeout = time.Duration(req.WaveTimeoutSeconds) * time.Second
+		if timeout < MinRolloutWaveTimeout || timeout > MaxRolloutWaveTimeout {
+			return nil, errors.NewValidationError(fmt.Sprintf("wave_timeout_seconds must be between %d and %d", int(MinRolloutWaveTimeout.Seconds()), int(MaxRolloutWaveTimeout.Seconds())))
+		}
+	}
+	maxFailure := DefaultRolloutMaxFailurePercent
+	if req.MaxFailurePercent != nil {
+		maxFailure = *req.MaxFailurePercent
+		if maxFailure < 0 || maxFailure > 100 {
+			return nil, errors.NewValidationError("max_failure_percent must be between 0 and 100")
+		}
+	}
+
+	selector, err := utils.ParseLabelSelector(req.LabelSelector)
+	if err != nil {
+		return nil, errors.NewValidationError("Invalid label selector: " + err.Error())
+	}
+	if req.GroupID == nil && len(selector) == 0 {
+		return nil, errors.NewValidationError("Target a group_id and/or a label_selector")
+	}
+
+	targets, err := s.targetDevices(userID, owner, req.GroupID, selector)
+	if err != nil {
+		return nil, err
+	}
+	if len(targets) == 0 {
+		return nil, errors.NewValidationError("No devices match the rollout target")
+	}
+
+	now := time.Now()
+	rollout := &models.FirmwareRollout{
+		ID:                 uuid.New(),
+		UserID:             userID,
+		OrganizationID:     orgID,
+		ArtifactID:         artifact.ID,
+		Version:            artifact.Version,
+		GroupID:            req.GroupID,
+		LabelSelector:      selector.String(),
+		Waves:              waves,
+		WaveTimeoutSeconds: int(timeout.Seconds()),
+		MaxFailurePercent:  maxFailure,
+		Status:             models.RolloutRunning,
+		CreatedAt:          now,
+		UpdatedAt:          now,
+	}
+
+	mathrand.Shuffle(len(targets), func(i, j int) { targets[i], targets[j] = targets[j], targets[i] })
+	devices := make([]*models.FirmwareRolloutDevice, 0, len(targets))
+	wave := 1
+	for i, device := range targets {
+		for i >= waveCutoff(len(targets), waves[wave-1]) {
+			wave++
+		}
+		rolloutDevice := &models.FirmwareRolloutDevice{
+			RolloutID:       rollout.ID,
+			DeviceID:        device.UUID,
+			Wave:            wave,
+			Status:          models.RolloutDevicePending,
+			PreviousVersion: device.FirmwareVersion,
+		}
+		if device.FirmwareVersion == artifact.Version {
+			rolloutDevice.Status = models.RolloutDeviceSkipped
+			rolloutDevice.Error = "device already runs this version"
+			rolloutDevice.CompletedAt = &now
+		}
+		devices = append(devices, rolloutDevice)
+	}
+
+	if err := s.rolloutRepo.Create(rollout, devices); err != nil {
+		return nil, errors.ErrDatabaseError
+	}
+
+	tracked := make([]models.FirmwareRolloutDevice, len(devices))
+	for i, device := range devices {
+		tracked[i] = *device
+	}
+	if err := s.advance(rollout, tracked, artifact, now); err != nil {
+		logger.Logger.Warn("Failed to start firmware rollout, will retry", "rollout_id", rollout.ID, "error", err)
+	}
+	return firmwareRolloutResponse(rollout, tracked, false), nil
+}
+
+func (s *firmwareRolloutService) ListRollouts(userID uuid.UUID) ([]dto.FirmwareRolloutResponse, error) {
+	rollouts, err := s.rolloutRepo.FindByUserID(userID)
+	if err != nil {
+		return nil, errors.ErrDatabaseError
+	}
+
+	responses := make([]dto.FirmwareRolloutResponse, 0, len(rollouts))
+	for i := range rollouts {
+		responses = append(responses, *firmwareRolloutResponse(&rollouts[i], nil, false))
+	}
+	return responses, nil
+}
+
+// GetRollout returns the rollout with its progress and every targeted
+// device.
+func (s *firmwareRolloutService) GetRollout(userID, rolloutID uuid.UUID) (*dto.FirmwareRolloutResponse, error) {
+	rollout, err := s.findRollout(userID, rolloutID, models.RoleViewer)
+	if err != nil {
+		return nil, err
+	}
+
+	devices, err := s.rolloutRepo.FindDevices(rollout.ID)
+	if err != nil {
+		return nil, errors.ErrDatabaseError
+	}
+	return firmwareRolloutResponse(rollout, devices, true), nil
+}
+
+// PauseRollout stops a running rollout from starting more devices. Devices
+// already updating keep being tracked. Requires the admin role.
+func (s *firmwareRolloutService) PauseRollout(userID, rolloutID uuid.UUID) (*dto.FirmwareRolloutResponse, error) {
+	rollout, err := s.findRollout(userID, rolloutID, models.RoleAdmin)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.pause(rollout, "Paused manually", time.Now()); err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.ErrRolloutNotRunning
+		}
+		return nil, errors.ErrDatabaseError
+	}
+	return s.GetRollout(userID, rolloutID)
+}
+
+// ResumeRollout restarts a paused rollout. The devices of the current wave
+// that failed are retried and the updated ones are watched again from now
+// on. Requires the admin role.
+func (s *firmwareRolloutService) ResumeRollout(userID, rolloutID uuid.UUID) (*dto.FirmwareRolloutResponse, error) {
+	rollout, err := s.findRollout(userID, rolloutID, models.RoleAdmin)
+	if err != nil {
+		return nil, err
+	}
+	if rollout.Status != models.RolloutPaused {
+		return nil, errors.ErrRolloutNotPaused
+	}
+
+	devices, err := s.rolloutRepo.FindDevices(rollout.ID)
+	if err != nil {
+		return nil, errors.ErrDatabaseError
+	}
+	for i := range devices {
+		device := &devices[i]
+		if device.Wave != rollout.CurrentWave || device.Status != models.RolloutDeviceFailed {
+			continue
+		}
+		err := s.rolloutRepo.TransitionDevice(rollout.ID, device.DeviceID, []string{models.RolloutDeviceFailed}, map[string]interface{}{
+			"status":       models.RolloutDevicePending,
+			"error":        "",
+			"command_id":   nil,
+			"started_at":   nil,
+			"completed_at": nil,
+		})
+		if err != nil && err != gorm.ErrRecordNotFound {
+			return nil, errors.ErrDatabaseError
+		}
+	}
+
+	now := time.Now()
+	err = s.rolloutRepo.Transition(rollout.ID, []string{models.RolloutPaused}, map[string]interface{}{
+		"status":       models.RolloutRunning,
+		"pause_reason": "",
+		"resumed_at":   now,
+		"updated_at":   now,
+	})
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.ErrRolloutNotPaused
+		}
+		return nil, errors.ErrDatabaseError
+	}
+	rollout.Status = models.RolloutRunning
+	rollout.PauseReason = ""
+	rollout.ResumedAt = &now
+
+	if _, err := s.process(rollout, now); err != nil {
+		logger.Logger.Warn("Failed to resume firmware rollout, will retry", "rollout_id", rollout.ID, "error", err)
+	}
+	return s.GetRollout(userID, rolloutID)
+}
+
+// CancelRollout stops a running or paused rollout for good. Devices that
+// were already told to update are not rolled back. Requires the admin role.
+func (s *firmwareRolloutService) CancelRollout(userID, rolloutID uuid.UUID) (*dto.FirmwareRolloutResponse, error) {
+	rollout, err := s.findRollout(userID, rolloutID, models.RoleAdmin)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	err = s.rolloutRepo.Transition(rollout.ID, []string{models.RolloutRunning, models.RolloutPaused}, map[string]interface{}{
+		"status":       models.RolloutCancelled,
+		"completed_at": now,
+		"updated_at":   now,
+	})
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.ErrRolloutFinished
+		}
+		return nil, errors.ErrDatabaseError
+	}
+	return s.GetRollout(userID, rolloutID)
+}
+
+// ProcessRollouts settles the devices being updated by the running and
+// paused rollouts, pauses the rollouts whose current wave failed too often,
+// counting devices that became unhealthy after updating, and moves the
+// others on to their next wave.
+func (s *firmwareRolloutService) ProcessRollouts() (int, int, error) {
+	rollouts, err := s.rolloutRepo.FindActive()
+	if err != nil {
+		return 0, 0, errors.ErrDatabaseError
+	}
+
+	now := time.Now()
+	paused, completed := 0, 0
+	for i := range rollouts {
+		wasRunning := rollouts[i].Status == models.RolloutRunning
+		status, err := s.process(&rollouts[i], now)
+		if err != nil {
+			logger.Logger.Warn("Failed to process firmware rollout", "rollout_id", rollouts[i].ID, "error", err)
+			continue
+		}
+		switch {
+		case status == models.RolloutPaused && wasRunning:
+			paused++
+		case status == models.RolloutCompleted:
+			completed++
+		}
+	}
+	return paused, completed, nil
+}
+
+// process runs one evaluation of the rollout and returns its new status.
+func (s *firmwareRolloutService) process(rollout *models.FirmwareRollout, now time.Time) (string, error) {
+	devices, err := s.rolloutRepo.FindDevices(rollout.ID)
+	if err != nil {
+		return rollout.Status, err
+	}
+
+	timeout := time.Duration(rollout.WaveTimeoutSeconds) * time.Second
+	for i := range devices {
+		device := &devices[i]
+		if device.Status != models.RolloutDeviceUpdating {
+			continue
+		}
+		reason, err := s.updateFailure(rollout, device, timeout, now)
+		if err != nil {
+			return rollout.Status, err
+		}
+		if reason == "" {
+			continue
+		}
+		err = s.rolloutRepo.TransitionDevice(rollout.ID, device.DeviceID, []string{models.RolloutDeviceUpdating}, map[string]interface{}{
+			"status":              models.RolloutDeviceFailed,
+			"error":               reason,
+			"download_token_hash": "",
+			"completed_at":        now,
+		})
+		if err == gorm.ErrRecordNotFound {
+			// The device reported the new version in the meantime.
+			continue
+		}
+		if err != nil {
+			return rollout.Status, err
+		}
+		device.Status = models.RolloutDeviceFailed
+		device.Error = reason
+		device.CompletedAt = &now
+	}
+
+	if rollout.Status != models.RolloutRunning {
+		return rollout.Status, nil
+	}
+
+	unhealthy, err := s.unhealthyDevices(rollout, devices, now)
+	if err != nil {
+		return rollout.Status, err
+	}
+	if reason := waveFailure(rollout, devices, unhealthy); reason != "" {
+		if err := s.pause(rollout, reason, now); err != nil && err != gorm.ErrRecordNotFound {
+			return rollout.Status, err
+		}
+		logger.Logger.Warn("Firmware rollout paused", "rollout_id", rollout.ID, "reason", reason)
+		return rollout.Status, nil
+	}
+
+	artifact, err := s.firmwareRepo.FindByID(rollout.ArtifactID)
+	if err != nil {
+		return rollout.Status, err
+	}
+	if err := s.advance(rollout, devices, artifact, now); err != nil {
+		return rollout.Status, err
+	}
+	return rollout.Status, nil
+}
+
+// updateFailure explains why an updating device failed, or returns an empty
+// string while it can still succeed.
+func (s *firmwareRolloutService) updateFailure(rollout *models.FirmwareRollout, device *models.FirmwareRolloutDevice, timeout time.Duration, now time.Time) (string, error) {
+	if device.CommandID != nil {
+		command, err := s.commandRepo.FindByID(*device.CommandID)
+		if err != nil && err != gorm.ErrRecordNotFound {
+			return "", err
+		}
+		if err == nil {
+			switch command.Status {
+			case models.CommandFailed:
+				if command.Error != "" {
+					return "update failed: " + command.Error, nil
+				}
+				return "update failed", nil
+			case models.CommandExpired, models.CommandCancelled:
+				return "update command " + command.Status, nil
+			}
+		}
+	}
+	if device.StartedAt != nil && !now.Before(device.StartedAt.Add(timeout)) {
+		return fmt.Sprintf("device did not report version %s in time", rollout.Version), nil
+	}
+	return "", nil
+}
+
+// unhealthyDevices counts the devices of the current wave that updated but
+// fired an alert since their health check started or stopped sending
+// heartbeats for longer than the health window.
+func (s *firmwareRolloutService) unhealthyDevices(rollout *models.FirmwareRollout, devices []models.FirmwareRolloutDevice, now time.Time) (int, error) {
+	watchedFrom := make(map[uuid.UUID]time.Time)
+	var deviceIDs []uuid.UUID
+	var since time.Time
+	for i := range devices {
+		start, ok := healthWatchStart(rollout, &devices[i])
+		if !ok {
+			continue
+		}
+		watchedFrom[devices[i].DeviceID] = start
+		deviceIDs = append(deviceIDs, devices[i].DeviceID)
+		if since.IsZero() || start.Before(since) {
+			since = start
+		}
+	}
+	if len(deviceIDs) == 0 {
+		return 0, nil
+	}
+
+	unhealthy := make(map[uuid.UUID]bool)
+	alerts, err := s.alertRepo.FindFiredByDevices(deviceIDs, since)
+	if err != nil {
+		return 0, err
+	}
+	for _, alert := range alerts {
+		if !alert.FiredAt.Before(watchedFrom[alert.DeviceID]) {
+			unhealthy[alert.DeviceID] = true
+		}
+	}
+
+	targets, err := s.deviceRepo.FindByIDs(deviceIDs)
+	if err != nil {
+		return 0, err
+	}
+	for _, target := range targets {
+		lastSeen := watchedFrom[target.UUID]
+		if target.LastSeenAt != nil && target.LastSeenAt.After(lastSeen) {
+			lastSeen = *target.LastSeenAt
+		}
+		if now.Sub(lastSeen) > s.healthWindow {
+			unhealthy[target.UUID] = true
+		}
+	}
+	return len(unhealthy), nil
+}
+
+// advance starts the pending devices of the current wave and, once every
+// device of the wave finished and the updated ones were watched for the
+// health window, moves on to the next wave or completes the rollout.
+func (s *firmwareRolloutService) advance(rollout *models.FirmwareRollout, devices []models.FirmwareRolloutDevice, artifact *models.FirmwareArtifact, now time.Time) error {
+	for {
+		if rollout.CurrentWave > 0 {
+			finished := true
+			for i := range devices {
+				device := &devices[i]
+				if device.Wave != rollout.CurrentWave {
+					continue
+				}
+				if device.Status == models.RolloutDevicePending {
+					if err := s.startDevice(rollout, device, artifact, now); err != nil {
+						return err
+					}
+				}
+				if !models.RolloutDeviceFinished(device.Status) {
+					finished = false
+				}
+				if watchedFrom, ok := healthWatchStart(rollout, device); ok && now.Before(watchedFrom.Add(s.healthWindow)) {
+					finished = false
+				}
+			}
+			if !finished {
+				return nil
+			}
+		}
+
+		if rollout.CurrentWave >= len(rollout.Waves) {
+			err := s.rolloutRepo.Transition(rollout.ID, []string{models.RolloutRunning}, map[string]interface{}{
+				"status":       models.RolloutCompleted,
+				"completed_at": now,
+				"updated_at":   now,
+			})
+			if err != nil {
+				if err == gorm.ErrRecordNotFound {
+					return nil
+				}
+				return err
+			}
+			rollout.Status = models.RolloutCompleted
+			rollout.CompletedAt = &now
+			logger.Logger.Info("Firmware rollout completed", "rollout_id", rollout.ID, "version", rollout.Version)
+			return nil
+		}
+
+		next := rollout.CurrentWave + 1
+		err := s.rolloutRepo.Transition(rollout.ID, []string{models.RolloutRunning}, map[string]interface{}{
+			"current_wave":    next,
+			"wave_started_at": now,
+			"updated_at":      now,
+		})
+		if err != nil {
+			if err == gorm.ErrRecordNotFound {
+				// Paused or cancelled in the meantime.
+				return nil
+			}
+			return err
+		}
+		rollout.CurrentWave = next
+		rollout.WaveStartedAt = &now
+	}
+}
+
+// startDevice tells a device to install the artifact. The download token is
+// stored before the command goes out so the device can use it right away.
+func (s *firmwareRolloutService) startDevice(rollout *models.FirmwareRollout, device *models.FirmwareRolloutDevice, artifact *models.FirmwareArtifact, now time.Time) error {
+	target, err := s.deviceRepo.FindByID(device.DeviceID)
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return err
+	}
+	skip := ""
+	switch {
+	case err == gorm.ErrRecordNotFound:
+		skip = "device was deleted"
+	case target.FirmwareVersion == rollout.Version:
+		skip = "device already runs this version"
+	}
+	if skip != "" {
+		err := s.rolloutRepo.TransitionDevice(rollout.ID, device.DeviceID, []string{models.RolloutDevicePending}, map[string]interface{}{
+			"status":       models.RolloutDeviceSkipped,
+			"error":        skip,
+			"completed_at": now,
+		})
+		if err != nil && err != gorm.ErrRecordNotFound {
+			return err
+		}
+		device.Status = models.RolloutDeviceSkipped
+		device.Error = skip
+		device.CompletedAt = &now
+		return nil
+	}
+
+	token, err := newFirmwareDownloadToken()
+	if err != nil {
+		return err
+	}
+	err = s.rolloutRepo.TransitionDevice(rollout.ID, device.DeviceID, []string{models.RolloutDevicePending}, map[string]interface{}{
+		"status":              models.RolloutDeviceUpdating,
+		"previous_version":    target.FirmwareVersion,
+		"download_token_hash": models.HashSecret(token),
+		"started_at":          now,
+	})
+	if err != nil {
+		return err
+	}
+
+	timeout := time.Duration(rollout.WaveTimeoutSeconds) * time.Second
+	command, err := s.commandService.DispatchCommand(rollout.UserID, device.DeviceID, models.CommandUpdateFirmware, map[string]interface{}{
+		"version":  artifact.Version,
+		"url":      s.downloadURL + token,
+		"checksum": artifact.Checksum,
+		"size":     artifact.Size,
+	}, timeout)
+	if err != nil {
+		revertErr := s.rolloutRepo.TransitionDevice(rollout.ID, device.DeviceID, []string{models.RolloutDeviceUpdating}, map[string]interface{}{
+			"status":              models.RolloutDevicePending,
+			"download_token_hash": "",
+			"started_at":          nil,
+		})
+		if revertErr != nil && revertErr != gorm.ErrRecordNotFound {
+			logger.Logger.Error("Error reverting rollout device", "rollout_id", rollout.ID, "device_id", device.DeviceID, "error", revertErr)
+		}
+		return err
+	}
+
+	err = s.rolloutRepo.TransitionDevice(rollout.ID, device.DeviceID, []string{models.RolloutDeviceUpdating}, map[string]interface{}{
+		"command_id": command.ID,
+	})
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return err
+	}
+	device.Status = models.RolloutDeviceUpdating
+	device.PreviousVersion = target.FirmwareVersion
+	device.CommandID = &command.ID
+	device.StartedAt = &now
+	return nil
+}
+
+func (s *firmwareRolloutService) pause(rollout *models.FirmwareRollout, reason string, now time.Time) error {
+	err := s.rolloutRepo.Transition(rollout.ID, []string{models.RolloutRunning}, map[string]interface{}{
+		"status":       models.RolloutPaused,
+		"pause_reason": reason,
+		"updated_at":   now,
+	})
+	if err != nil {
+		return err
+	}
+	rollout.Status = models.RolloutPaused
+	rollout.PauseReason = reason
+	return nil
+}
+
+// targetDevices resolves the devices of the rollout target that belong to
+// owner. Shared devices are never targeted.
+func (s *firmwareRolloutService) targetDevices(userID uuid.UUID, owner Ownership, groupID *uuid.UUID, selector utils.LabelSelector) ([]models.Device, error) {
+	var candidates []models.Device
+	if groupID != nil {
+		group, err := s.groupRepo.FindByID(*groupID)
+		if err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return nil, errors.ErrDeviceGroupNotFound
+			}
+			return nil, errors.ErrDatabaseError
+		}
+		if !groupOwnership(group).SameOwner(owner) {
+			return nil, errors.ErrDeviceGroupNotFound
+		}
+
+		groupIDs, err := s.groupRepo.FindDescendantIDs(group.ID)
+		if err != nil {
+			return nil, errors.ErrDatabaseError
+		}
+		candidates, err = s.deviceRepo.FindByGroupIDs(userID, groupIDs)
+		if err != nil {
+			return nil, errors.ErrDatabaseError
+		}
+	} else {
+		var err error
+		candidates, _, err = s.deviceRepo.Search(userID, repository.DeviceFilter{Labels: selector})
+		if err != nil {
+			return nil, errors.ErrDatabaseError
+		}
+	}
+
+	targets := make([]models.Device, 0, len(candidates))
+	for i := range candidates {
+		device := &candidates[i]
+		if deviceOwnership(device).SameOwner(owner) && selector.Matches(device.Labels) {
+			targets = append(targets, *device)
+		}
+	}
+	return targets, nil
+}
+
+func (s *firmwareRolloutService) findRollout(userID, rolloutID uuid.UUID, role string) (*models.FirmwareRollout, error) {
+	rollout, err := s.rolloutRepo.FindByID(rolloutID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.ErrRolloutNotFound
+		}
+		return nil, errors.ErrDatabaseError
+	}
+	if err := s.authz.Authorize(userID, Ownership{UserID: rollout.UserID, OrganizationID: rollout.OrganizationID}, role); err != nil {
+		return nil, err
+	}
+	return rollout, nil
+}
+
+func validateRolloutWaves(waves []int) error {
+	if len(waves) > MaxRolloutWaves {
+		return errors.NewValidationError(fmt.Sprintf("A rollout can have at most %d waves", MaxRolloutWaves))
+	}
+	previous := 0
+	for _, percent := range waves {
+		if percent <= previous || percent > 100 {
+			return errors.NewValidationError("Waves must be increasing percentages between 1 and 100")
+		}
+		previous = percent
+	}
+	if previous != 100 {
+		return errors.NewValidationError("The last wave must reach 100 percent of the devices")
+	}
+	return nil
+}
+
+// waveCutoff is the number of devices the waves up to percent cover.
+func waveCutoff(total, percent int) int {
+	return (total*percent + 99) / 100
+}
+
+// healthWatchStart returns when the health check of an updated device of the
+// current wave starts: when it reported the new version, or when the rollout
+// was last resumed if that came later.
+func healthWatchStart(rollout *models.FirmwareRollout, device *models.FirmwareRolloutDevice) (time.Time, bool) {
+	if device.Wave != rollout.CurrentWave || device.Status != models.RolloutDeviceSucceeded || device.CompletedAt == nil {
+		return time.Time{}, false
+	}
+	if rollout.ResumedAt != nil && rollout.ResumedAt.After(*device.CompletedAt) {
+		return *rollout.ResumedAt, true
+	}
+	return *device.CompletedAt, true
+}
+
+// waveFailure explains why the current wave failed too often, or returns an
+// empty string while it is within the rollout's failure budget. unhealthy
+// devices updated but failed the health check afterwards.
+func waveFailure(rollout *models.FirmwareRollout, devices []models.FirmwareRolloutDevice, unhealthy int) string {
+	total, failed := 0, 0
+	for _, device := range devices {
+		if device.Wave != rollout.CurrentWave || device.Status == models.RolloutDeviceSkipped {
+			continue
+		}
+		total++
+		if device.Status == models.RolloutDeviceFailed {
+			failed++
+		}
+	}
+	failed += unhealthy
+	if total == 0 || failed*100 <= rollout.MaxFailurePercent*total {
+		return ""
+	}
+	if unhealthy > 0 {
+		return fmt.Sprintf("%d of %d devices in wave %d failed to update or became unhealthy after updating, %d silent or alerting (max %d%%)", failed, total, rollout.CurrentWave, unhealthy, rollout.MaxFailurePercent)
+	}
+	return fmt.Sprintf("%d of %d devices in wave %d failed to update (max %d%%)", failed, total, rollout.CurrentWave, rollout.MaxFailurePercent)
+}
+
+func newFirmwareDownloadToken() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return "fw_" + hex.EncodeToString(buf), nil
+}
+
+func firmwareRolloutResponse(rollout *models.FirmwareRollout, devices []models.FirmwareRolloutDevice, withDevices bool) *dto.FirmwareRolloutResponse {
+	response := &dto.FirmwareRolloutResponse{
+		ID:                 rollout.ID,
+		ArtifactID:         rollout.ArtifactID,
+		Version:            rollout.Version,
+		OrganizationID:     rollout.OrganizationID,
+		GroupID:            rollout.GroupID,
+		LabelSelector:      rollout.LabelSelector,
+		Waves:              rollout.Waves,
+		CurrentWave:        rollout.CurrentWave,
+		WaveTimeoutSeconds: rollout.WaveTimeoutSeconds,
+		MaxFailurePercent:  rollout.MaxFailurePercent,
+		Status:             rollout.Status,
+		PauseReason:        rollout.PauseReason,
+		WaveStartedAt:      rollout.WaveStartedAt,
+		ResumedAt:          rollout.ResumedAt,
+		CompletedAt:        rollout.CompletedAt,
+		CreatedAt:          rollout.CreatedAt,
+	}
+	if devices == nil {
+		return response
+	}
+
+	progress := &dto.FirmwareRolloutProgress{Total: len(devices)}
+	for _, device := range devices {
+		switch device.Status {
+		case models.RolloutDevicePending:
+			progress.Pending++
+		case models.RolloutDeviceUpdating:
+			progress.Updating++
+		case models.RolloutDeviceSucceeded:
+			progress.Succeeded++
+		case models.RolloutDeviceFailed:
+			progress.Failed++
+		case models.RolloutDeviceSkipped:
+			progress.Skipped++
+		}
+		if withDevices {
+			response.Devices = append(response.Devices, dto.FirmwareRolloutDeviceResponse{
+				DeviceID:        device.DeviceID,
+				Wave:            device.Wave,
+				Status:          device.Status,
+				PreviousVersion: device.PreviousVersion,
+				CommandID:       device.CommandID,
+				Error:           device.Error,
+				StartedAt:       device.StartedAt,
+				CompletedAt:     device.CompletedAt,
+			})
+		}
+	}
+	response.Progress = progress
+	return response
+}
@@ -0,0 +1,393 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/dto"
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/models"
+	custom_errors "github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/utils/errors"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+type MockFirmwareRolloutRepository struct {
+	mock.Mock
+}
+
+func (m *MockFirmwareRolloutRepository) Create(rollout *models.FirmwareRollout, devices []*models.FirmwareRolloutDevice) error {
+	args := m.Called(rollout, devices)
+	return args.Error(0)
+}
+
+func (m *MockFirmwareRolloutRepository) FindByID(id uuid.UUID) (*models.FirmwareRollout, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.FirmwareRollout), args.Error(1)
+}
+
+func (m *MockFirmwareRolloutRepository) FindByUserID(userID uuid.UUID) ([]models.FirmwareRollout, error) {
+	args := m.Called(userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.FirmwareRollout), args.Error(1)
+}
+
+func (m *MockFirmwareRolloutRepository) FindActive() ([]models.FirmwareRollout, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.FirmwareRollout), args.Error(1)
+}
+
+func (m *MockFirmwareRolloutRepository) CountActiveByArtifact(artifactID uuid.UUID) (int64, error) {
+	args := m.Called(artifactID)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockFirmwareRolloutRepository) Transition(id uuid.UUID, from []string, updates map[string]interface{}) error {
+	args := m.Called(id, from, updates)
+	return args.Error(0)
+}
+
+func (m *MockFirmwareRolloutRepository) FindDevices(rolloutID uuid.UUID) ([]models.FirmwareRolloutDevice, error) {
+	args := m.Called(rolloutID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.FirmwareRolloutDevice), args.Error(1)
+}
+
+func (m *MockFirmwareRolloutRepository) FindDeviceByDownloadToken(tokenHash string) (*models.FirmwareRolloutDevice, error) {
+	args := m.Called(tokenHash)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.FirmwareRolloutDevice), args.Error(1)
+}
+
+func (m *MockFirmwareRolloutRepository) TransitionDevice(rolloutID, deviceID uuid.UUID, from []string, updates map[string]interface{}) error {
+	args := m.Called(rolloutID, deviceID, from, updates)
+	return args.Error(0)
+}
+
+func (m *MockFirmwareRolloutRepository) CompleteDevice(deviceID uuid.UUID, version string, now time.Time) (int64, error) {
+	args := m.Called(deviceID, version, now)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+type firmwareRolloutMocks struct {
+	rolloutRepo  *MockFirmwareRolloutRepository
+	firmwareRepo *MockFirmwareRepository
+	deviceRepo   *MockDeviceRepository
+	groupRepo    *MockDeviceGroupRepository
+	commandRepo  *MockDeviceCommandRepository
+	alertRepo    *MockAlertRepository
+	publisher    *MockDeviceCommandPublisher
+}
+
+// newTestFirmwareRolloutService wires the rollout service to a real command
+// service so the update_firmware commands can be asserted on the publisher.
+func newTestFirmwareRolloutService() (FirmwareRolloutService, *firmwareRolloutMocks) {
+	commandService, commandRepo, deviceRepo, publisher := newTestDeviceCommandService()
+	mocks := &firmwareRolloutMocks{
+		rolloutRepo:  new(MockFirmwareRolloutRepository),
+		firmwareRepo: new(MockFirmwareRepository),
+		deviceRepo:   deviceRepo,
+		groupRepo:    new(MockDeviceGroupRepository),
+		commandRepo:  commandRepo,
+		alertRepo:    new(MockAlertRepository),
+		publisher:    publisher,
+	}
+	authz := NewAuthorizer(new(MockOrganizationRepository), noDeviceShares())
+	service := NewFirmwareRolloutService(mocks.rolloutRepo, mocks.firmwareRepo, deviceRepo, mocks.groupRepo, commandRepo, mocks.alertRepo, commandService, time.Minute, authz, "http://fleet.local/")
+	return service, mocks
+}
+
+func toWave(wave int) interface{} {
+	return mock.MatchedBy(func(updates map[string]interface{}) bool {
+		return updates["current_wave"] == wave
+	})
+}
+
+func TestFirmwareRolloutService_CreateRollout(t *testing.T) {
+	userID := uuid.New()
+	groupID := uuid.New()
+	artifact := &models.FirmwareArtifact{ID: uuid.New(), UserID: userID, Version: "2.0.0", Checksum: "abc", Size: 42}
+
+	t.Run("Success - Splits targets into waves and starts the first", func(t *testing.T) {
+		service, mocks := newTestFirmwareRolloutService()
+		devices := make([]models.Device, 10)
+		for i := range devices {
+			devices[i] = models.Device{UUID: uuid.New(), UserID: userID, FirmwareVersion: "1.0.0"}
+		}
+		mocks.firmwareRepo.On("FindByID", artifact.ID).Return(artifact, nil)
+		mocks.groupRepo.On("FindByID", groupID).Return(&models.DeviceGroup{ID: groupID, UserID: userID}, nil)
+		mocks.groupRepo.On("FindDescendantIDs", groupID).Return([]uuid.UUID{groupID}, nil)
+		mocks.deviceRepo.On("FindByGroupIDs", userID, []uuid.UUID{groupID}).Return(devices, nil)
+
+		var created []*models.FirmwareRolloutDevice
+		mocks.rolloutRepo.On("Create", mock.AnythingOfType("*models.FirmwareRollout"), mock.Anything).Run(func(args mock.Arguments) {
+			created = args.Get(1).([]*models.FirmwareRolloutDevice)
+		}).Return(nil)
+		mocks.rolloutRepo.On("Transition", mock.Anything, []string{models.RolloutRunning}, toWave(1)).Return(nil)
+		mocks.deviceRepo.On("FindByID", mock.Anything).Return(&models.Device{FirmwareVersion: "1.0.0"}, nil)
+		mocks.rolloutRepo.On("TransitionDevice", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+		mocks.commandRepo.On("Create", mock.AnythingOfType("*models.DeviceCommand")).Return(nil)
+		mocks.commandRepo.On("Transition", mock.Anything, []string{models.CommandPending}, toStatus(models.CommandSent)).Return(nil)
+		mocks.publisher.On("PublishCommand", mock.MatchedBy(func(msg dto.DeviceCommandMessage) bool {
+			url, _ := msg.Params["url"].(string)
+			return msg.Name == models.CommandUpdateFirmware &&
+				msg.Params["version"] == "2.0.0" &&
+				msg.Params["checksum"] == "abc" &&
+				len(url) > len("http://fleet.local"+FirmwareDownloadPath) &&
+				url[:len("http://fleet.local"+FirmwareDownloadPath)] == "http://fleet.local"+FirmwareDownloadPath
+		})).Return(nil).Once()
+
+		rollout, err := service.CreateRollout(userID, nil, dto.FirmwareRolloutRequest{ArtifactID: artifact.ID, GroupID: &groupID})
+
+		assert.NoError(t, err)
+		assert.Equal(t, models.RolloutRunning, rollout.Status)
+		assert.Equal(t, 1, rollout.CurrentWave)
+		assert.Equal(t, []int{10, 50, 100}, []int(rollout.Waves))
+
+		perWave := map[int]int{}
+		for _, device := range created {
+			perWave[device.Wave]++
+		}
+		assert.Equal(t, map[int]int{1: 1, 2: 4, 3: 5}, perWave)
+		mocks.publisher.AssertExpectations(t)
+	})
+
+	t.Run("Error - Artifact of another user", func(t *testing.T) {
+		service, mocks := newTestFirmwareRolloutService()
+		mocks.firmwareRepo.On("FindByID", artifact.ID).Return(artifact, nil)
+
+		rollout, err := service.CreateRollout(uuid.New(), nil, dto.FirmwareRolloutRequest{ArtifactID: artifact.ID, GroupID: &groupID})
+
+		assert.Nil(t, rollout)
+		assert.Equal(t, custom_errors.ErrFirmwareNotFound, err)
+	})
+
+	t.Run("Error - Waves must reach 100 percent", func(t *testing.T) {
+		service, mocks := newTestFirmwareRolloutService()
+		mocks.firmwareRepo.On("FindByID", artifact.ID).Return(artifact, nil)
+
+		rollout, err := service.CreateRollout(userID, nil, dto.FirmwareRolloutRequest{ArtifactID: artifact.ID, GroupID: &groupID, Waves: []int{20, 60}})
+
+		assert.Nil(t, rollout)
+		assert.Error(t, err)
+		mocks.groupRepo.AssertNotCalled(t, "FindByID", mock.Anything)
+	})
+
+	t.Run("Error - No target", func(t *testing.T) {
+		service, mocks := newTestFirmwareRolloutService()
+		mocks.firmwareRepo.On("FindByID", artifact.ID).Return(artifact, nil)
+
+		rollout, err := service.CreateRollout(userID, nil, dto.FirmwareRolloutRequest{ArtifactID: artifact.ID})
+
+		assert.Nil(t, rollout)
+		assert.Error(t, err)
+	})
+
+	t.Run("Error - No devices match", func(t *testing.T) {
+		service, mocks := newTestFirmwareRolloutService()
+		mocks.firmwareRepo.On("FindByID", artifact.ID).Return(artifact, nil)
+		mocks.groupRepo.On("FindByID", groupID).Return(&models.DeviceGroup{ID: groupID, UserID: userID}, nil)
+		mocks.groupRepo.On("FindDescendantIDs", groupID).Return([]uuid.UUID{groupID}, nil)
+		mocks.deviceRepo.On("FindByGroupIDs", userID, []uuid.UUID{groupID}).Return([]models.Device{}, nil)
+
+		rollout, err := service.CreateRollout(userID, nil, dto.FirmwareRolloutRequest{ArtifactID: artifact.ID, GroupID: &groupID})
+
+		assert.Nil(t, rollout)
+		assert.Error(t, err)
+		mocks.rolloutRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+}
+
+func TestFirmwareRolloutService_ProcessRollouts(t *testing.T) {
+	artifact := &models.FirmwareArtifact{ID: uuid.New(), Version: "2.0.0"}
+
+	t.Run("Success - Pauses a wave that failed too often", func(t *testing.T) {
+		service, mocks := newTestFirmwareRolloutService()
+		startedAt := time.Now().Add(-time.Hour)
+		rollout := models.FirmwareRollout{ID: uuid.New(), ArtifactID: artifact.ID, Version: "2.0.0", Waves: []int{50, 100}, CurrentWave: 1, WaveTimeoutSeconds: 600, MaxFailurePercent: 10, Status: models.RolloutRunning}
+		late := uuid.New()
+		mocks.rolloutRepo.On("FindActive").Return([]models.FirmwareRollout{rollout}, nil)
+		mocks.rolloutRepo.On("FindDevices", rollout.ID).Return([]models.FirmwareRolloutDevice{
+			{RolloutID: rollout.ID, DeviceID: late, Wave: 1, Status: models.RolloutDeviceUpdating, StartedAt: &startedAt},
+			{RolloutID: rollout.ID, DeviceID: uuid.New(), Wave: 1, Status: models.RolloutDeviceSucceeded},
+			{RolloutID: rollout.ID, DeviceID: uuid.New(), Wave: 2, Status: models.RolloutDevicePending},
+		}, nil)
+		mocks.rolloutRepo.On("TransitionDevice", rollout.ID, late, []string{models.RolloutDeviceUpdating}, toStatus(models.RolloutDeviceFailed)).Return(nil)
+		mocks.rolloutRepo.On("Transition", rollout.ID, []string{models.RolloutRunning}, toStatus(models.RolloutPaused)).Return(nil)
+
+		paused, completed, err := service.ProcessRollouts()
+
+		assert.NoError(t, err)
+		assert.Equal(t, 1, paused)
+		assert.Equal(t, 0, completed)
+		mocks.rolloutRepo.AssertExpectations(t)
+		mocks.publisher.AssertNotCalled(t, "PublishCommand", mock.Anything)
+	})
+
+	t.Run("Success - Pauses a wave whose updated devices became unhealthy", func(t *testing.T) {
+		service, mocks := newTestFirmwareRolloutService()
+		now := time.Now()
+		updatedAt := now.Add(-30 * time.Minute)
+		rollout := models.FirmwareRollout{ID: uuid.New(), ArtifactID: artifact.ID, Version: "2.0.0", Waves: []int{50, 100}, CurrentWave: 1, WaveTimeoutSeconds: 600, MaxFailurePercent: 50, Status: models.RolloutRunning}
+		alerting, silent, healthy := uuid.New(), uuid.New(), uuid.New()
+		mocks.rolloutRepo.On("FindActive").Return([]models.FirmwareRollout{rollout}, nil)
+		mocks.rolloutRepo.On("FindDevices", rollout.ID).Return([]models.FirmwareRolloutDevice{
+			{RolloutID: rollout.ID, DeviceID: alerting, Wave: 1, Status: models.RolloutDeviceSucceeded, CompletedAt: &updatedAt},
+			{RolloutID: rollout.ID, DeviceID: silent, Wave: 1, Status: models.RolloutDeviceSucceeded, CompletedAt: &updatedAt},
+			{RolloutID: rollout.ID, DeviceID: healthy, Wave: 1, Status: models.RolloutDeviceSucceeded, CompletedAt: &updatedAt},
+			{RolloutID: rollout.ID, DeviceID: uuid.New(), Wave: 2, Status: models.RolloutDevicePending},
+		}, nil)
+		mocks.alertRepo.On("FindFiredByDevices", []uuid.UUID{alerting, silent, healthy}, updatedAt).Return([]models.Alert{
+			{DeviceID: alerting, FiredAt: updatedAt.Add(5 * time.Minute)},
+			{DeviceID: healthy, FiredAt: updatedAt.Add(-time.Minute)},
+		}, nil)
+		mocks.deviceRepo.On("FindByIDs", []uuid.UUID{alerting, silent, healthy}).Return([]models.Device{
+			{UUID: alerting, LastSeenAt: &now},
+			{UUID: silent, LastSeenAt: &updatedAt},
+			{UUID: healthy, LastSeenAt: &now},
+		}, nil)
+		mocks.rolloutRepo.On("Transition", rollout.ID, []string{models.RolloutRunning}, mock.MatchedBy(func(updates map[string]interface{}) bool {
+			return updates["status"] == models.RolloutPaused && updates["pause_reason"] == "2 of 3 devices in wave 1 failed to update or became unhealthy after updating, 2 silent or alerting (max 50%)"
+		})).Return(nil)
+
+		paused, _, err := service.ProcessRollouts()
+
+		assert.NoError(t, err)
+		assert.Equal(t, 1, paused)
+		mocks.rolloutRepo.AssertExpectations(t)
+	})
+
+	t.Run("Success - Fails devices whose command failed", func(t *testing.T) {
+		service, mocks := newTestFirmwareRolloutService()
+		startedAt := time.Now()
+		commandID := uuid.New()
+		rollout := models.FirmwareRollout{ID: uuid.New(), ArtifactID: artifact.ID, Version: "2.0.0", Waves: []int{100}, CurrentWave: 1, WaveTimeoutSeconds: 600, MaxFailurePercent: 100, Status: models.RolloutRunning}
+		deviceID := uuid.New()
+		mocks.rolloutRepo.On("FindActive").Return([]models.FirmwareRollout{rollout}, nil)
+		mocks.rolloutRepo.On("FindDevices", rollout.ID).Return([]models.FirmwareRolloutDevice{
+			{RolloutID: rollout.ID, DeviceID: deviceID, Wave: 1, Status: models.RolloutDeviceUpdating, CommandID: &commandID, StartedAt: &startedAt},
+		}, nil)
+		mocks.commandRepo.On("FindByID", commandID).Return(&models.DeviceCommand{ID: commandID, Status: models.CommandFailed, Error: "flash error"}, nil)
+		mocks.rolloutRepo.On("TransitionDevice", rollout.ID, deviceID, []string{models.RolloutDeviceUpdating}, mock.MatchedBy(func(updates map[string]interface{}) bool {
+			return updates["status"] == models.RolloutDeviceFailed && updates["error"] == "update failed: flash error"
+		})).Return(nil)
+		mocks.firmwareRepo.On("FindByID", artifact.ID).Return(artifact, nil)
+		mocks.rolloutRepo.On("Transition", rollout.ID, []string{models.RolloutRunning}, toStatus(models.RolloutCompleted)).Return(nil)
+
+		paused, completed, err := service.ProcessRollouts()
+
+		assert.NoError(t, err)
+		assert.Equal(t, 0, paused)
+		assert.Equal(t, 1, completed)
+		mocks.rolloutRepo.AssertExpectations(t)
+	})
+
+	t.Run("Success - Starts the next wave", func(t *testing.T) {
+		service, mocks := newTestFirmwareRolloutService()
+		rollout := models.FirmwareRollout{ID: uuid.New(), UserID: uuid.New(), ArtifactID: artifact.ID, Version: "2.0.0", Waves: []int{50, 100}, CurrentWave: 1, WaveTimeoutSeconds: 600, MaxFailurePercent: 10, Status: models.RolloutRunning}
+		next := uuid.New()
+		mocks.rolloutRepo.On("FindActive").Return([]models.FirmwareRollout{rollout}, nil)
+		mocks.rolloutRepo.On("FindDevices", rollout.ID).Return([]models.FirmwareRolloutDevice{
+			{RolloutID: rollout.ID, DeviceID: uuid.New(), Wave: 1, Status: models.RolloutDeviceSucceeded},
+			{RolloutID: rollout.ID, DeviceID: next, Wave: 2, Status: models.RolloutDevicePending},
+		}, nil)
+		mocks.firmwareRepo.On("FindByID", artifact.ID).Return(artifact, nil)
+		mocks.rolloutRepo.On("Transition", rollout.ID, []string{models.RolloutRunning}, toWave(2)).Return(nil)
+		mocks.deviceRepo.On("FindByID", next).Return(&models.Device{UUID: next, FirmwareVersion: "2.0.0"}, nil)
+		mocks.rolloutRepo.On("TransitionDevice", rollout.ID, next, []string{models.RolloutDevicePending}, toStatus(models.RolloutDeviceSkipped)).Return(nil)
+		mocks.rolloutRepo.On("Transition", rollout.ID, []string{models.RolloutRunning}, toStatus(models.RolloutCompleted)).Return(nil)
+
+		paused, completed, err := service.ProcessRollouts()
+
+		assert.NoError(t, err)
+		assert.Equal(t, 0, paused)
+		assert.Equal(t, 1, completed)
+		mocks.rolloutRepo.AssertExpectations(t)
+		mocks.publisher.AssertNotCalled(t, "PublishCommand", mock.Anything)
+	})
+
+	t.Run("Success - Holds the wave while updated devices are watched", func(t *testing.T) {
+		service, mocks := newTestFirmwareRolloutService()
+		now := time.Now()
+		updatedAt := now.Add(-30 * time.Second)
+		rollout := models.FirmwareRollout{ID: uuid.New(), ArtifactID: artifact.ID, Version: "2.0.0", Waves: []int{50, 100}, CurrentWave: 1, WaveTimeoutSeconds: 600, MaxFailurePercent: 10, Status: models.RolloutRunning}
+		updated := uuid.New()
+		mocks.rolloutRepo.On("FindActive").Return([]models.FirmwareRollout{rollout}, nil)
+		mocks.rolloutRepo.On("FindDevices", rollout.ID).Return([]models.FirmwareRolloutDevice{
+			{RolloutID: rollout.ID, DeviceID: updated, Wave: 1, Status: models.RolloutDeviceSucceeded, CompletedAt: &updatedAt},
+			{RolloutID: rollout.ID, DeviceID: uuid.New(), Wave: 2, Status: models.RolloutDevicePending},
+		}, nil)
+		mocks.alertRepo.On("FindFiredByDevices", []uuid.UUID{updated}, updatedAt).Return([]models.Alert{}, nil)
+		mocks.deviceRepo.On("FindByIDs", []uuid.UUID{updated}).Return([]models.Device{{UUID: updated, LastSeenAt: &now}}, nil)
+		mocks.firmwareRepo.On("FindByID", artifact.ID).Return(artifact, nil)
+
+		paused, completed, err := service.ProcessRollouts()
+
+		assert.NoError(t, err)
+		assert.Equal(t, 0, paused)
+		assert.Equal(t, 0, completed)
+		mocks.rolloutRepo.AssertNotCalled(t, "Transition", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Success - Watches updated devices again after a resume", func(t *testing.T) {
+		service, mocks := newTestFirmwareRolloutService()
+		now := time.Now()
+		updatedAt := now.Add(-30 * time.Minute)
+		resumedAt := now.Add(-time.Minute)
+		rollout := models.FirmwareRollout{ID: uuid.New(), ArtifactID: artifact.ID, Version: "2.0.0", Waves: []int{50, 100}, CurrentWave: 1, WaveTimeoutSeconds: 600, MaxFailurePercent: 10, Status: models.RolloutRunning, ResumedAt: &resumedAt}
+		updated := uuid.New()
+		mocks.rolloutRepo.On("FindActive").Return([]models.FirmwareRollout{rollout}, nil)
+		mocks.rolloutRepo.On("FindDevices", rollout.ID).Return([]models.FirmwareRolloutDevice{
+			{RolloutID: rollout.ID, DeviceID: updated, Wave: 1, Status: models.RolloutDeviceSucceeded, CompletedAt: &updatedAt},
+			{RolloutID: rollout.ID, DeviceID: uuid.New(), Wave: 2, Status: models.RolloutDevicePending},
+		}, nil)
+		mocks.alertRepo.On("FindFiredByDevices", []uuid.UUID{updated}, resumedAt).Return([]models.Alert{}, nil)
+		mocks.deviceRepo.On("FindByIDs", []uuid.UUID{updated}).Return([]models.Device{{UUID: updated, LastSeenAt: &now}}, nil)
+		mocks.firmwareRepo.On("FindByID", artifact.ID).Return(artifact, nil)
+
+		paused, completed, err := service.ProcessRollouts()
+
+		assert.NoError(t, err)
+		assert.Equal(t, 0, paused)
+		assert.Equal(t, 0, completed)
+		mocks.alertRepo.AssertExpectations(t)
+		mocks.rolloutRepo.AssertNotCalled(t, "Transition", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Error - Database failure", func(t *testing.T) {
+		service, mocks := newTestFirmwareRolloutService()
+		mocks.rolloutRepo.On("FindActive").Return(nil, gorm.ErrInvalidDB)
+
+		_, _, err := service.ProcessRollouts()
+
+		assert.Equal(t, custom_errors.ErrDatabaseError, err)
+	})
+}
+
+func TestFirmwareRolloutService_ResumeRollout(t *testing.T) {
+	userID := uuid.New()
+
+	t.Run("Error - Rollout is not paused", func(t *testing.T) {
+		service, mocks := newTestFirmwareRolloutService()
+		rollout := &models.FirmwareRollout{ID: uuid.New(), UserID: userID, Status: models.RolloutRunning}
+		mocks.rolloutRepo.On("FindByID", rollout.ID).Return(rollout, nil)
+
+		response, err := service.ResumeRollout(userID, rollout.ID)
+
+		assert.Nil(t, response)
+		assert.Equal(t, custom_errors.ErrRolloutNotPaused, err)
+	})
+}
@@ -0,0 +1,271 @@
+package services
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/dto"
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/models"
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/repository"
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/utils/errors"
+	logger "github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/utils/logger"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// MaxFirmwareSize caps the size of an uploaded firmware file.
+const MaxFirmwareSize = 256 << 20
+
+var firmwareVersionRegex = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._+-]{0,63}$`)
+
+type FirmwareService interface {
+	UploadFirmware(userID uuid.UUID, orgID *uuid.UUID, req dto.FirmwareUploadRequest, fileName string, file io.Reader) (*dto.FirmwareArtifactResponse, error)
+	ListFirmware(userID uuid.UUID) ([]dto.FirmwareArtifactResponse, error)
+	GetFirmware(userID, artifactID uuid.UUID) (*dto.FirmwareArtifactResponse, error)
+	OpenFirmware(userID, artifactID uuid.UUID) (*models.FirmwareArtifact, io.ReadSeekCloser, error)
+	OpenFirmwareForDevice(token string) (*models.FirmwareArtifact, io.ReadSeekCloser, error)
+	DeleteFirmware(userID, artifactID uuid.UUID) error
+	GetInventory(userID uuid.UUID) (*dto.FirmwareInventoryResponse, error)
+	ReportVersion(deviceID uuid.UUID, version string) error
+}
+
+type firmwareService struct {
+	firmwareRepo repository.FirmwareRepository
+	rolloutRepo  repository.FirmwareRolloutRepository
+	deviceRepo   repository.DeviceRepository
+	storage      FirmwareStorage
+	authz        Authorizer
+}
+
+func NewFirmwareService(firmwareRepo repository.FirmwareRepository, rolloutRepo repository.FirmwareRolloutRepository, deviceRepo repository.DeviceRepository, storage FirmwareStorage, authz Authorizer) FirmwareService {
+	return &firmwareService{
+		firmwareRepo: firmwareRepo,
+		rolloutRepo:  rolloutRepo,
+		deviceRepo:   deviceRepo,
+		storage:      storage,
+		authz:        authz,
+	}
+}
+
+// UploadFirmware stores a new firmware version in the registry of the user,
+// or of orgID when set. Requires the admin role.
+func (s *firmwareService) UploadFirmware(userID uuid.UUID, orgID *uuid.UUID, req dto.FirmwareUploadRequest, fileName string, file io.Reader) (*dto.FirmwareArtifactResponse, error) {
+	if err := s.authz.Authorize(userID, Ownership{UserID: userID, OrganizationID: orgID}, models.RoleAdmin); err != nil {
+		return nil, err
+	}
+
+	version := strings.TrimSpace(req.Version)
+	if !firmwareVersionRegex.MatchString(version) {
+		return nil, errors.NewValidationError("Version must have up to 64 letters, digits, '.', '_', '+' or '-'")
+	}
+	if _, err := s.firmwareRepo.FindByVersion(userID, orgID, version); err == nil {
+		return nil, errors.ErrFirmwareVersionExists
+	} else if err != gorm.ErrRecordNotFound {
+		return nil, errors.ErrDatabaseError
+	}
+
+	artifact := &models.FirmwareArtifact{
+		ID:             uuid.New(),
+		UserID:         userID,
+		OrganizationID: orgID,
+		Version:        version,
+		FileName:       filepath.Base(fileName),
+		Notes:          strings.TrimSpace(req.Notes),
+		CreatedAt:      time.Now(),
+	}
+
+	size, checksum, err := s.storage.Save(artifact.ID, io.LimitReader(file, MaxFirmwareSize+1))
+	if err != nil {
+		return nil, err
+	}
+	var invalid error
+	switch {
+	case size == 0:
+		invalid = errors.NewValidationError("Firmware file is empty")
+	case size > MaxFirmwareSize:
+		invalid = errors.NewValidationError(fmt.Sprintf("Firmware file must be at most %d MB", MaxFirmwareSize>>20))
+	case req.Checksum != "" && !strings.EqualFold(strings.TrimSpace(req.Checksum), checksum):
+		invalid = errors.NewValidationError("Checksum does not match the uploaded file")
+	}
+	if invalid != nil {
+		s.deleteFile(artifact.ID)
+		return nil, invalid
+	}
+
+	artifact.Size = size
+	artifact.Checksum = checksum
+	if err := s.firmwareRepo.Create(artifact); err != nil {
+		s.deleteFile(artifact.ID)
+		return nil, errors.ErrDatabaseError
+	}
+	return firmwareArtifactResponse(artifact), nil
+}
+
+func (s *firmwareService) ListFirmware(userID uuid.UUID) ([]dto.FirmwareArtifactResponse, error) {
+	artifacts, err := s.firmwareRepo.FindByUserID(userID)
+	if err != nil {
+		return nil, errors.ErrDatabaseError
+	}
+
+	responses := make([]dto.FirmwareArtifactResponse, 0, len(artifacts))
+	for i := range artifacts {
+		responses = append(responses, *firmwareArtifactResponse(&artifacts[i]))
+	}
+	return responses, nil
+}
+
+func (s *firmwareService) GetFirmware(userID, artifactID uuid.UUID) (*dto.FirmwareArtifactResponse, error) {
+	artifact, err := s.findArtifact(userID, artifactID, models.RoleViewer)
+	if err != nil {
+		return nil, err
+	}
+	return firmwareArtifactResponse(artifact), nil
+}
+
+// OpenFirmware returns the artifact with its file. The caller closes it.
+func (s *firmwareService) OpenFirmware(userID, artifactID uuid.UUID) (*models.FirmwareArtifact, io.ReadSeekCloser, error) {
+	artifact, err := s.findArtifact(userID, artifactID, models.RoleViewer)
+	if err != nil {
+		return nil, nil, err
+	}
+	return s.open(artifact)
+}
+
+// OpenFirmwareForDevice returns the artifact a device was told to install
+// by a rollout. token is the download token sent in its update_firmware
+// command; it is valid while the device is updating.
+func (s *firmwareService) OpenFirmwareForDevice(token string) (*models.FirmwareArtifact, io.ReadSeekCloser, error) {
+	rolloutDevice, err := s.rolloutRepo.FindDeviceByDownloadToken(models.HashSecret(token))
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil, errors.ErrFirmwareNotFound
+		}
+		return nil, nil, errors.ErrDatabaseError
+	}
+
+	rollout, err := s.rolloutRepo.FindByID(rolloutDevice.RolloutID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil, errors.ErrFirmwareNotFound
+		}
+		return nil, nil, errors.ErrDatabaseError
+	}
+	artifact, err := s.firmwareRepo.FindByID(rollout.ArtifactID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil, errors.ErrFirmwareNotFound
+		}
+		return nil, nil, errors.ErrDatabaseError
+	}
+	return s.open(artifact)
+}
+
+// DeleteFirmware removes an artifact that no running or paused rollout
+// uses. Requires the admin role.
+func (s *firmwareService) DeleteFirmware(userID, artifactID uuid.UUID) error {
+	artifact, err := s.findArtifact(userID, artifactID, models.RoleAdmin)
+	if err != nil {
+		return err
+	}
+
+	active, err := s.rolloutRepo.CountActiveByArtifact(artifact.ID)
+	if err != nil {
+		return errors.ErrDatabaseError
+	}
+	if active > 0 {
+		return errors.ErrFirmwareInUse
+	}
+
+	if err := s.firmwareRepo.Delete(artifact.ID); err != nil {
+		return errors.ErrDatabaseError
+	}
+	s.deleteFile(artifact.ID)
+	return nil
+}
+
+// GetInventory counts the devices the user can reach per firmware version.
+func (s *firmwareService) GetInventory(userID uuid.UUID) (*dto.FirmwareInventoryResponse, error) {
+	counts, err := s.deviceRepo.CountByFirmwareVersion(userID)
+	if err != nil {
+		return nil, errors.ErrDatabaseError
+	}
+
+	inventory := &dto.FirmwareInventoryResponse{Versions: make([]dto.FirmwareVersionCount, 0, len(counts))}
+	for _, count := range counts {
+		inventory.Total += count.Devices
+		inventory.Versions = append(inventory.Versions, dto.FirmwareVersionCount{Version: count.Version, Devices: count.Devices})
+	}
+	return inventory, nil
+}
+
+// ReportVersion records the firmware version a device reported in a
+// heartbeat and completes the rollouts that were updating it to that
+// version.
+func (s *firmwareService) ReportVersion(deviceID uuid.UUID, version string) error {
+	version = strings.TrimSpace(version)
+	if version == "" {
+		return nil
+	}
+
+	if err := s.deviceRepo.UpdateFirmwareVersion(deviceID, version); err != nil {
+		return errors.ErrDatabaseError
+	}
+	completed, err := s.rolloutRepo.CompleteDevice(deviceID, version, time.Now())
+	if err != nil {
+		return errors.ErrDatabaseError
+	}
+	if completed > 0 {
+		logger.Logger.Info("Device updated by firmware rollout", "device_id", deviceID, "version", version)
+	}
+	return nil
+}
+
+func (s *firmwareService) findArtifact(userID, artifactID uuid.UUID, role string) (*models.FirmwareArtifact, error) {
+	artifact, err := s.firmwareRepo.FindByID(artifactID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.ErrFirmwareNotFound
+		}
+		return nil, errors.ErrDatabaseError
+	}
+	if err := s.authz.Authorize(userID, firmwareOwnership(artifact), role); err != nil {
+		return nil, err
+	}
+	return artifact, nil
+}
+
+func (s *firmwareService) open(artifact *models.FirmwareArtifact) (*models.FirmwareArtifact, io.ReadSeekCloser, error) {
+	file, err := s.storage.Open(artifact.ID)
+	if err != nil {
+		logger.Logger.Error("Firmware file missing from storage", "artifact_id", artifact.ID, "error", err)
+		return nil, nil, errors.ErrFirmwareNotFound
+	}
+	return artifact, file, nil
+}
+
+func (s *firmwareService) deleteFile(id uuid.UUID) {
+	if err := s.storage.Delete(id); err != nil {
+		logger.Logger.Error("Error deleting firmware file", "artifact_id", id, "error", err)
+	}
+}
+
+func firmwareOwnership(artifact *models.FirmwareArtifact) Ownership {
+	return Ownership{UserID: artifact.UserID, OrganizationID: artifact.OrganizationID}
+}
+
+func firmwareArtifactResponse(artifact *models.FirmwareArtifact) *dto.FirmwareArtifactResponse {
+	return &dto.FirmwareArtifactResponse{
+		ID:             artifact.ID,
+		Version:        artifact.Version,
+		FileName:       artifact.FileName,
+		Size:           artifact.Size,
+		Checksum:       artifact.Checksum,
+		Notes:          artifact.Notes,
+		OrganizationID: artifact.OrganizationID,
+		CreatedAt:      artifact.CreatedAt,
+	}
+}
@@ -0,0 +1,239 @@
+package services
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/dto"
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/models"
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/repository"
+	custom_errors "github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/utils/errors"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+type MockFirmwareRepository struct {
+	mock.Mock
+}
+
+func (m *MockFirmwareRepository) Create(artifact *models.FirmwareArtifact) error {
+	args := m.Called(artifact)
+	return args.Error(0)
+}
+
+func (m *MockFirmwareRepository) FindByID(id uuid.UUID) (*models.FirmwareArtifact, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.FirmwareArtifact), args.Error(1)
+}
+
+func (m *MockFirmwareRepository) FindByUserID(userID uuid.UUID) ([]models.FirmwareArtifact, error) {
+	args := m.Called(userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.FirmwareArtifact), args.Error(1)
+}
+
+func (m *MockFirmwareRepository) FindByVersion(userID uuid.UUID, organizationID *uuid.UUID, version string) (*models.FirmwareArtifact, error) {
+	args := m.Called(userID, organizationID, version)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.FirmwareArtifact), args.Error(1)
+}
+
+func (m *MockFirmwareRepository) Delete(id uuid.UUID) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func newTestFirmwareService(t *testing.T) (FirmwareService, *MockFirmwareRepository, *MockFirmwareRolloutRepository, *MockDeviceRepository, FirmwareStorage) {
+	storage, err := NewDiskFirmwareStorage(t.TempDir())
+	assert.NoError(t, err)
+
+	firmwareRepo := new(MockFirmwareRepository)
+	rolloutRepo := new(MockFirmwareRolloutRepository)
+	deviceRepo := new(MockDeviceRepository)
+	authz := NewAuthorizer(new(MockOrganizationRepository), noDeviceShares())
+	return NewFirmwareService(firmwareRepo, rolloutRepo, deviceRepo, storage, authz), firmwareRepo, rolloutRepo, deviceRepo, storage
+}
+
+func TestFirmwareService_UploadFirmware(t *testing.T) {
+	userID := uuid.New()
+	content := "firmware image"
+	sum := sha256.Sum256([]byte(content))
+	checksum := hex.EncodeToString(sum[:])
+
+	t.Run("Success - Stores file with its checksum", func(t *testing.T) {
+		service, firmwareRepo, _, _, storage := newTestFirmwareService(t)
+		firmwareRepo.On("FindByVersion", userID, (*uuid.UUID)(nil), "1.4.0").Return(nil, gorm.ErrRecordNotFound)
+		firmwareRepo.On("Create", mock.AnythingOfType("*models.FirmwareArtifact")).Return(nil)
+
+		artifact, err := service.UploadFirmware(userID, nil, dto.FirmwareUploadRequest{Version: "1.4.0", Checksum: strings.ToUpper(checksum)}, "../gateway.bin", strings.NewReader(content))
+
+		assert.NoError(t, err)
+		assert.Equal(t, checksum, artifact.Checksum)
+		assert.Equal(t, int64(len(content)), artifact.Size)
+		assert.Equal(t, "gateway.bin", artifact.FileName)
+
+		file, err := storage.Open(artifact.ID)
+		assert.NoError(t, err)
+		stored, _ := io.ReadAll(file)
+		file.Close()
+		assert.Equal(t, content, string(stored))
+	})
+
+	t.Run("Error - Checksum mismatch", func(t *testing.T) {
+		service, firmwareRepo, _, _, storage := newTestFirmwareService(t)
+		firmwareRepo.On("FindByVersion", userID, (*uuid.UUID)(nil), "1.4.0").Return(nil, gorm.ErrRecordNotFound)
+
+		artifact, err := service.UploadFirmware(userID, nil, dto.FirmwareUploadRequest{Version: "1.4.0", Checksum: strings.Repeat("0", 64)}, "gateway.bin", strings.NewReader(content))
+
+		assert.Nil(t, artifact)
+		assert.ErrorContains(t, err, "Checksum")
+		firmwareRepo.AssertNotCalled(t, "Create", mock.Anything)
+
+		entries, _ := os.ReadDir(storage.(*diskFirmwareStorage).dir)
+		assert.Empty(t, entries)
+	})
+
+	t.Run("Error - Version already exists", func(t *testing.T) {
+		service, firmwareRepo, _, _, _ := newTestFirmwareService(t)
+		firmwareRepo.On("FindByVersion", userID, (*uuid.UUID)(nil), "1.4.0").Return(&models.FirmwareArtifact{ID: uuid.New()}, nil)
+
+		artifact, err := service.UploadFirmware(userID, nil, dto.FirmwareUploadRequest{Version: "1.4.0"}, "gateway.bin", strings.NewReader(content))
+
+		assert.Nil(t, artifact)
+		assert.Equal(t, custom_errors.ErrFirmwareVersionExists, err)
+	})
+
+	t.Run("Error - Invalid version", func(t *testing.T) {
+		service, firmwareRepo, _, _, _ := newTestFirmwareService(t)
+
+		artifact, err := service.UploadFirmware(userID, nil, dto.FirmwareUploadRequest{Version: "1.4 beta"}, "gateway.bin", strings.NewReader(content))
+
+		assert.Nil(t, artifact)
+		assert.Error(t, err)
+		firmwareRepo.AssertNotCalled(t, "FindByVersion", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestFirmwareService_DeleteFirmware(t *testing.T) {
+	userID := uuid.New()
+	artifact := &models.FirmwareArtifact{ID: uuid.New(), UserID: userID, Version: "1.4.0"}
+
+	t.Run("Success - Delete unused artifact", func(t *testing.T) {
+		service, firmwareRepo, rolloutRepo, _, _ := newTestFirmwareService(t)
+		firmwareRepo.On("FindByID", artifact.ID).Return(artifact, nil)
+		rolloutRepo.On("CountActiveByArtifact", artifact.ID).Return(int64(0), nil)
+		firmwareRepo.On("Delete", artifact.ID).Return(nil)
+
+		err := service.DeleteFirmware(userID, artifact.ID)
+
+		assert.NoError(t, err)
+		firmwareRepo.AssertExpectations(t)
+	})
+
+	t.Run("Error - Used by an active rollout", func(t *testing.T) {
+		service, firmwareRepo, rolloutRepo, _, _ := newTestFirmwareService(t)
+		firmwareRepo.On("FindByID", artifact.ID).Return(artifact, nil)
+		rolloutRepo.On("CountActiveByArtifact", artifact.ID).Return(int64(1), nil)
+
+		err := service.DeleteFirmware(userID, artifact.ID)
+
+		assert.Equal(t, custom_errors.ErrFirmwareInUse, err)
+		firmwareRepo.AssertNotCalled(t, "Delete", mock.Anything)
+	})
+
+	t.Run("Error - Another user's artifact", func(t *testing.T) {
+		service, firmwareRepo, _, _, _ := newTestFirmwareService(t)
+		firmwareRepo.On("FindByID", artifact.ID).Return(artifact, nil)
+
+		err := service.DeleteFirmware(uuid.New(), artifact.ID)
+
+		assert.Equal(t, custom_errors.ErrForbidden, err)
+	})
+}
+
+func TestFirmwareService_OpenFirmwareForDevice(t *testing.T) {
+	t.Run("Success - Token of an updating device", func(t *testing.T) {
+		service, firmwareRepo, rolloutRepo, _, storage := newTestFirmwareService(t)
+		artifact := &models.FirmwareArtifact{ID: uuid.New(), Version: "1.4.0"}
+		_, _, err := storage.Save(artifact.ID, strings.NewReader("image"))
+		assert.NoError(t, err)
+
+		rollout := &models.FirmwareRollout{ID: uuid.New(), ArtifactID: artifact.ID}
+		rolloutRepo.On("FindDeviceByDownloadToken", models.HashSecret("fw_token")).Return(&models.FirmwareRolloutDevice{RolloutID: rollout.ID}, nil)
+		rolloutRepo.On("FindByID", rollout.ID).Return(rollout, nil)
+		firmwareRepo.On("FindByID", artifact.ID).Return(artifact, nil)
+
+		found, file, err := service.OpenFirmwareForDevice("fw_token")
+
+		assert.NoError(t, err)
+		assert.Equal(t, artifact.ID, found.ID)
+		file.Close()
+	})
+
+	t.Run("Error - Unknown token", func(t *testing.T) {
+		service, _, rolloutRepo, _, _ := newTestFirmwareService(t)
+		rolloutRepo.On("FindDeviceByDownloadToken", mock.Anything).Return(nil, gorm.ErrRecordNotFound)
+
+		artifact, file, err := service.OpenFirmwareForDevice("fw_unknown")
+
+		assert.Nil(t, artifact)
+		assert.Nil(t, file)
+		assert.Equal(t, custom_errors.ErrFirmwareNotFound, err)
+	})
+}
+
+func TestFirmwareService_ReportVersion(t *testing.T) {
+	deviceID := uuid.New()
+
+	t.Run("Success - Records version and completes rollouts", func(t *testing.T) {
+		service, _, rolloutRepo, deviceRepo, _ := newTestFirmwareService(t)
+		deviceRepo.On("UpdateFirmwareVersion", deviceID, "1.4.0").Return(nil)
+		rolloutRepo.On("CompleteDevice", deviceID, "1.4.0", mock.AnythingOfType("time.Time")).Return(int64(1), nil)
+
+		err := service.ReportVersion(deviceID, " 1.4.0 ")
+
+		assert.NoError(t, err)
+		rolloutRepo.AssertExpectations(t)
+	})
+
+	t.Run("Success - Ignores empty version", func(t *testing.T) {
+		service, _, rolloutRepo, deviceRepo, _ := newTestFirmwareService(t)
+
+		err := service.ReportVersion(deviceID, "")
+
+		assert.NoError(t, err)
+		deviceRepo.AssertNotCalled(t, "UpdateFirmwareVersion", mock.Anything, mock.Anything)
+		rolloutRepo.AssertNotCalled(t, "CompleteDevice", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestFirmwareService_GetInventory(t *testing.T) {
+	userID := uuid.New()
+
+	t.Run("Success - Counts devices per version", func(t *testing.T) {
+		service, _, _, deviceRepo, _ := newTestFirmwareService(t)
+		deviceRepo.On("CountByFirmwareVersion", userID).Return([]repository.FirmwareVersionCount{
+			{Version: "1.4.0", Devices: 7},
+			{Version: "", Devices: 3},
+		}, nil)
+
+		inventory, err := service.GetInventory(userID)
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(10), inventory.Total)
+		assert.Len(t, inventory.Versions, 2)
+	})
+}
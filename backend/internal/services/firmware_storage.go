@@ -0,0 +1,71 @@
+package services
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/google/uuid"
+)
+
+// FirmwareStorage keeps the files of firmware artifacts.
+type FirmwareStorage interface {
+	// Save stores the content of r under id and returns its size and
+	// SHA-256 checksum.
+	Save(id uuid.UUID, r io.Reader) (int64, string, error)
+	Open(id uuid.UUID) (io.ReadSeekCloser, error)
+	Delete(id uuid.UUID) error
+}
+
+type diskFirmwareStorage struct {
+	dir string
+}
+
+// NewDiskFirmwareStorage stores firmware files in dir, creating it if needed.
+func NewDiskFirmwareStorage(dir string) (FirmwareStorage, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	return &diskFirmwareStorage{dir: dir}, nil
+}
+
+// Save writes to a temporary file first so that a failed upload never
+// leaves a partial artifact behind.
+func (s *diskFirmwareStorage) Save(id uuid.UUID, r io.Reader) (int64, string, error) {
+	tmp, err := os.CreateTemp(s.dir, id.String()+".*.tmp")
+	if err != nil {
+		return 0, "", err
+	}
+	defer os.Remove(tmp.Name())
+
+	hash := sha256.New()
+	size, err := io.Copy(io.MultiWriter(tmp, hash), r)
+	if err != nil {
+		tmp.Close()
+		return 0, "", err
+	}
+	if err := tmp.Close(); err != nil {
+		return 0, "", err
+	}
+	if err := os.Rename(tmp.Name(), s.path(id)); err != nil {
+		return 0, "", err
+	}
+	return size, hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+func (s *diskFirmwareStorage) Open(id uuid.UUID) (io.ReadSeekCloser, error) {
+	return os.Open(s.path(id))
+}
+
+func (s *diskFirmwareStorage) Delete(id uuid.UUID) error {
+	if err := os.Remove(s.path(id)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+func (s *diskFirmwareStorage) path(id uuid.UUID) string {
+	return filepath.Join(s.dir, id.String()+".bin")
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/models"
 	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/repository"
 	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/utils/errors"
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/utils/logger"
 	"github.com/google/uuid"
 	"gorm.io/gorm"
 )
@@ -15,17 +16,20 @@ type HeartbeatService interface {
     CreateHeartbeat(deviceID uuid.UUID, cpu, ram, diskFree, temperature float64, latency, connectivity int, bootTime time.Time) (*models.Heartbeat, error)
     GetDeviceHeartbeats(userID, deviceID uuid.UUID, startTime, endTime time.Time) ([]models.Heartbeat, error)
     GetLatestDeviceHeartbeat(userID, deviceID uuid.UUID) (*models.Heartbeat, error)
+    ExportHeartbeats(userID uuid.UUID, deviceID *uuid.UUID, startTime, endTime time.Time, fn func(*models.Heartbeat) error) error
 }
 
 type heartbeatService struct {
     heartbeatRepo repository.HeartbeatRepository
     deviceRepo    repository.DeviceRepository
+    authz         Authorizer
 }
 
-func NewHeartbeatService(heartbeatRepo repository.HeartbeatRepository, deviceRepo repository.DeviceRepository) HeartbeatService {
+func NewHeartbeatService(heartbeatRepo repository.HeartbeatRepository, deviceRepo repository.DeviceRepository, authz Authorizer) HeartbeatService {
     return &heartbeatService{
         heartbeatRepo: heartbeatRepo,
         deviceRepo:    deviceRepo,
+        authz:         authz,
     }
 }
 
@@ -45,6 +49,10 @@ func (s *heartbeatService) CreateHeartbeat(deviceID uuid.UUID, cpu, ram, diskFre
         return nil, errors.ErrDatabaseError
     }
 
+    if err := s.deviceRepo.UpdateLastSeen(deviceID, heartbeat.CreatedAt); err != nil {
+        logger.Logger.Error("Error updating device last seen", "device_id", deviceID, "error", err)
+    }
+
     return heartbeat, nil
 }
 
@@ -57,8 +65,8 @@ func (s *heartbeatService) GetDeviceHeartbeats(userID, deviceID uuid.UUID, start
         return nil, errors.ErrDatabaseError
     }
 
-    if device.UserID != userID {
-        return nil, errors.ErrForbidden
+    if err := s.authz.Authorize(userID, deviceOwnership(device), models.RoleViewer); err != nil {
+        return nil, err
     }
 
     heartbeats, err := s.heartbeatRepo.FindByDeviceID(deviceID, startTime, endTime)
@@ -78,8 +86,8 @@ func (s *heartbeatService) GetLatestDeviceHeartbeat(userID, deviceID uuid.UUID)
         return nil, errors.ErrDatabaseError
     }
 
-    if device.UserID != userID {
-        return nil, errors.ErrForbidden
+    if err := s.authz.Authorize(userID, deviceOwnership(device), models.RoleViewer); err != nil {
+        return nil, err
     }
 
     heartbeat, err := s.heartbeatRepo.FindLatestByDeviceID(deviceID)
@@ -91,4 +99,51 @@ func (s *heartbeatService) GetLatestDeviceHeartbeat(userID, deviceID uuid.UUID)
     }
 
     return heartbeat, nil
-}
\ No newline at end of file
+}
+
+// ExportHeartbeats streams the heartbeats of one device, or of every device of
+// the user when deviceID is nil, to fn. Access is checked before the first row
+// is produced so callers can still report errors cleanly.
+func (s *heartbeatService) ExportHeartbeats(userID uuid.UUID, deviceID *uuid.UUID, startTime, endTime time.Time, fn func(*models.Heartbeat) error) error {
+    if endTime.Before(startTime) {
+        return errors.NewValidationError("start must be before end")
+    }
+
+    var deviceIDs []uuid.UUID
+    if deviceID != nil {
+        device, err := s.deviceRepo.FindByID(*deviceID)
+        if err != nil {
+            if err == gorm.ErrRecordNotFound {
+                return errors.ErrDeviceNotFound
+            }
+            return errors.ErrDatabaseError
+        }
+
+        if err := s.authz.Authorize(userID, deviceOwnership(device), models.RoleViewer); err != nil {
+            return err
+        }
+        deviceIDs = append(deviceIDs, device.UUID)
+    } else {
+        devices, err := s.deviceRepo.FindByUserID(userID)
+        if err != nil {
+            return errors.ErrDatabaseError
+        }
+        for _, device := range devices {
+            deviceIDs = append(deviceIDs, device.UUID)
+        }
+    }
+
+    var writeErr error
+    err := s.heartbeatRepo.StreamByDeviceIDs(deviceIDs, startTime, endTime, func(heartbeat *models.Heartbeat) error {
+        writeErr = fn(heartbeat)
+        return writeErr
+    })
+    if writeErr != nil {
+        return writeErr
+    }
+    if err != nil {
+        return errors.ErrDatabaseError
+    }
+
+    return nil
+}
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/models"
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/repository"
 	custom_errors "github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/utils/errors"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
@@ -38,6 +39,42 @@ func (m *MockHeartbeatRepository) FindLatestByDeviceID(deviceID uuid.UUID) (*mod
 	return args.Get(0).(*models.Heartbeat), args.Error(1)
 }
 
+func (m *MockHeartbeatRepository) FindConnectivityByDeviceID(deviceID uuid.UUID, startTime, endTime time.Time) ([]models.Heartbeat, error) {
+	args := m.Called(deviceID, startTime, endTime)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Heartbeat), args.Error(1)
+}
+
+func (m *MockHeartbeatRepository) StreamByDeviceIDs(deviceIDs []uuid.UUID, startTime, endTime time.Time, fn func(*models.Heartbeat) error) error {
+	args := m.Called(deviceIDs, startTime, endTime)
+	if rows, ok := args.Get(0).([]models.Heartbeat); ok {
+		for i := range rows {
+			if err := fn(&rows[i]); err != nil {
+				return err
+			}
+		}
+	}
+	return args.Error(1)
+}
+
+func (m *MockHeartbeatRepository) FindLatestByDeviceIDs(deviceIDs []uuid.UUID) ([]models.Heartbeat, error) {
+	args := m.Called(deviceIDs)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Heartbeat), args.Error(1)
+}
+
+func (m *MockHeartbeatRepository) SummarizeByDeviceIDs(deviceIDs []uuid.UUID, startTime, endTime time.Time) ([]repository.HeartbeatSummary, error) {
+	args := m.Called(deviceIDs, startTime, endTime)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]repository.HeartbeatSummary), args.Error(1)
+}
+
 func TestHeartbeatService_CreateHeartbeat(t *testing.T) {
 	deviceID := uuid.New()
 	bootTime := time.Now().UTC().Add(-time.Hour * 24)
@@ -51,9 +88,10 @@ func TestHeartbeatService_CreateHeartbeat(t *testing.T) {
 	t.Run("Success - Create heartbeat", func(t *testing.T) {
 		mockHeartbeatRepo := new(MockHeartbeatRepository)
 		mockDeviceRepo := new(MockDeviceRepository)
-		service := NewHeartbeatService(mockHeartbeatRepo, mockDeviceRepo)
+		service := NewHeartbeatService(mockHeartbeatRepo, mockDeviceRepo, NewAuthorizer(new(MockOrganizationRepository), noDeviceShares()))
 
 		mockHeartbeatRepo.On("Create", mock.AnythingOfType("*models.Heartbeat")).Return(nil)
+		mockDeviceRepo.On("UpdateLastSeen", deviceID, mock.Anything).Return(nil)
 
 		heartbeat, err := service.CreateHeartbeat(deviceID, cpu, ram, diskFree, temperature, latency, connectivity, bootTime)
 
@@ -69,12 +107,30 @@ func TestHeartbeatService_CreateHeartbeat(t *testing.T) {
 		assert.Equal(t, bootTime, heartbeat.BootTime)
 
 		mockHeartbeatRepo.AssertExpectations(t)
+		mockDeviceRepo.AssertExpectations(t)
+	})
+
+	t.Run("Success - Last seen update failure is not fatal", func(t *testing.T) {
+		mockHeartbeatRepo := new(MockHeartbeatRepository)
+		mockDeviceRepo := new(MockDeviceRepository)
+		service := NewHeartbeatService(mockHeartbeatRepo, mockDeviceRepo, NewAuthorizer(new(MockOrganizationRepository), noDeviceShares()))
+
+		mockHeartbeatRepo.On("Create", mock.AnythingOfType("*models.Heartbeat")).Return(nil)
+		mockDeviceRepo.On("UpdateLastSeen", deviceID, mock.Anything).Return(errors.New("database error"))
+
+		heartbeat, err := service.CreateHeartbeat(deviceID, cpu, ram, diskFree, temperature, latency, connectivity, bootTime)
+
+		assert.NoError(t, err)
+		assert.NotNil(t, heartbeat)
+
+		mockHeartbeatRepo.AssertExpectations(t)
+		mockDeviceRepo.AssertExpectations(t)
 	})
 
 	t.Run("Error - Database error on Create", func(t *testing.T) {
 		mockHeartbeatRepo := new(MockHeartbeatRepository)
 		mockDeviceRepo := new(MockDeviceRepository)
-		service := NewHeartbeatService(mockHeartbeatRepo, mockDeviceRepo)
+		service := NewHeartbeatService(mockHeartbeatRepo, mockDeviceRepo, NewAuthorizer(new(MockOrganizationRepository), noDeviceShares()))
 
 		mockHeartbeatRepo.On("Create", mock.AnythingOfType("*models.Heartbeat")).Return(errors.New("database error"))
 
@@ -130,7 +186,7 @@ func TestHeartbeatService_GetDeviceHeartbeats(t *testing.T) {
 	t.Run("Success - Get device heartbeats", func(t *testing.T) {
 		mockHeartbeatRepo := new(MockHeartbeatRepository)
 		mockDeviceRepo := new(MockDeviceRepository)
-		service := NewHeartbeatService(mockHeartbeatRepo, mockDeviceRepo)
+		service := NewHeartbeatService(mockHeartbeatRepo, mockDeviceRepo, NewAuthorizer(new(MockOrganizationRepository), noDeviceShares()))
 
 		mockDeviceRepo.On("FindByID", deviceID).Return(device, nil)
 		mockHeartbeatRepo.On("FindByDeviceID", deviceID, startTime, endTime).Return(heartbeats, nil)
@@ -150,7 +206,7 @@ func TestHeartbeatService_GetDeviceHeartbeats(t *testing.T) {
 	t.Run("Error - Device not found", func(t *testing.T) {
 		mockHeartbeatRepo := new(MockHeartbeatRepository)
 		mockDeviceRepo := new(MockDeviceRepository)
-		service := NewHeartbeatService(mockHeartbeatRepo, mockDeviceRepo)
+		service := NewHeartbeatService(mockHeartbeatRepo, mockDeviceRepo, NewAuthorizer(new(MockOrganizationRepository), noDeviceShares()))
 
 		mockDeviceRepo.On("FindByID", deviceID).Return((*models.Device)(nil), gorm.ErrRecordNotFound)
 
@@ -166,7 +222,7 @@ func TestHeartbeatService_GetDeviceHeartbeats(t *testing.T) {
 	t.Run("Error - Database error on FindByID", func(t *testing.T) {
 		mockHeartbeatRepo := new(MockHeartbeatRepository)
 		mockDeviceRepo := new(MockDeviceRepository)
-		service := NewHeartbeatService(mockHeartbeatRepo, mockDeviceRepo)
+		service := NewHeartbeatService(mockHeartbeatRepo, mockDeviceRepo, NewAuthorizer(new(MockOrganizationRepository), noDeviceShares()))
 
 		mockDeviceRepo.On("FindByID", deviceID).Return((*models.Device)(nil), errors.New("database error"))
 
@@ -182,7 +238,7 @@ func TestHeartbeatService_GetDeviceHeartbeats(t *testing.T) {
 	t.Run("Error - Forbidden (different user)", func(t *testing.T) {
 		mockHeartbeatRepo := new(MockHeartbeatRepository)
 		mockDeviceRepo := new(MockDeviceRepository)
-		service := NewHeartbeatService(mockHeartbeatRepo, mockDeviceRepo)
+		service := NewHeartbeatService(mockHeartbeatRepo, mockDeviceRepo, NewAuthorizer(new(MockOrganizationRepository), noDeviceShares()))
 
 		mockDeviceRepo.On("FindByID", deviceID).Return(device, nil)
 
@@ -198,7 +254,7 @@ func TestHeartbeatService_GetDeviceHeartbeats(t *testing.T) {
 	t.Run("Error - Database error on FindByDeviceID", func(t *testing.T) {
 		mockHeartbeatRepo := new(MockHeartbeatRepository)
 		mockDeviceRepo := new(MockDeviceRepository)
-		service := NewHeartbeatService(mockHeartbeatRepo, mockDeviceRepo)
+		service := NewHeartbeatService(mockHeartbeatRepo, mockDeviceRepo, NewAuthorizer(new(MockOrganizationRepository), noDeviceShares()))
 
 		mockDeviceRepo.On("FindByID", deviceID).Return(device, nil)
 		mockHeartbeatRepo.On("FindByDeviceID", deviceID, startTime, endTime).Return(([]models.Heartbeat)(nil), errors.New("database error"))
@@ -240,7 +296,7 @@ func TestHeartbeatService_GetLatestDeviceHeartbeat(t *testing.T) {
 	t.Run("Success - Get latest device heartbeat", func(t *testing.T) {
 		mockHeartbeatRepo := new(MockHeartbeatRepository)
 		mockDeviceRepo := new(MockDeviceRepository)
-		service := NewHeartbeatService(mockHeartbeatRepo, mockDeviceRepo)
+		service := NewHeartbeatService(mockHeartbeatRepo, mockDeviceRepo, NewAuthorizer(new(MockOrganizationRepository), noDeviceShares()))
 
 		mockDeviceRepo.On("FindByID", deviceID).Return(device, nil)
 		mockHeartbeatRepo.On("FindLatestByDeviceID", deviceID).Return(heartbeat, nil)
@@ -259,7 +315,7 @@ func TestHeartbeatService_GetLatestDeviceHeartbeat(t *testing.T) {
 	t.Run("Error - Device not found", func(t *testing.T) {
 		mockHeartbeatRepo := new(MockHeartbeatRepository)
 		mockDeviceRepo := new(MockDeviceRepository)
-		service := NewHeartbeatService(mockHeartbeatRepo, mockDeviceRepo)
+		service := NewHeartbeatService(mockHeartbeatRepo, mockDeviceRepo, NewAuthorizer(new(MockOrganizationRepository), noDeviceShares()))
 
 		mockDeviceRepo.On("FindByID", deviceID).Return((*models.Device)(nil), gorm.ErrRecordNotFound)
 
@@ -275,7 +331,7 @@ func TestHeartbeatService_GetLatestDeviceHeartbeat(t *testing.T) {
 	t.Run("Error - Database error on FindByID", func(t *testing.T) {
 		mockHeartbeatRepo := new(MockHeartbeatRepository)
 		mockDeviceRepo := new(MockDeviceRepository)
-		service := NewHeartbeatService(mockHeartbeatRepo, mockDeviceRepo)
+		service := NewHeartbeatService(mockHeartbeatRepo, mockDeviceRepo, NewAuthorizer(new(MockOrganizationRepository), noDeviceShares()))
 
 		mockDeviceRepo.On("FindByID", deviceID).Return((*models.Device)(nil), errors.New("database error"))
 
@@ -291,7 +347,7 @@ func TestHeartbeatService_GetLatestDeviceHeartbeat(t *testing.T) {
 	t.Run("Error - Forbidden (different user)", func(t *testing.T) {
 		mockHeartbeatRepo := new(MockHeartbeatRepository)
 		mockDeviceRepo := new(MockDeviceRepository)
-		service := NewHeartbeatService(mockHeartbeatRepo, mockDeviceRepo)
+		service := NewHeartbeatService(mockHeartbeatRepo, mockDeviceRepo, NewAuthorizer(new(MockOrganizationRepository), noDeviceShares()))
 
 		mockDeviceRepo.On("FindByID", deviceID).Return(device, nil)
 
@@ -307,7 +363,7 @@ func TestHeartbeatService_GetLatestDeviceHeartbeat(t *testing.T) {
 	t.Run("Error - Heartbeat not found", func(t *testing.T) {
 		mockHeartbeatRepo := new(MockHeartbeatRepository)
 		mockDeviceRepo := new(MockDeviceRepository)
-		service := NewHeartbeatService(mockHeartbeatRepo, mockDeviceRepo)
+		service := NewHeartbeatService(mockHeartbeatRepo, mockDeviceRepo, NewAuthorizer(new(MockOrganizationRepository), noDeviceShares()))
 
 		mockDeviceRepo.On("FindByID", deviceID).Return(device, nil)
 		mockHeartbeatRepo.On("FindLatestByDeviceID", deviceID).Return((*models.Heartbeat)(nil), gorm.ErrRecordNotFound)
@@ -325,7 +381,7 @@ func TestHeartbeatService_GetLatestDeviceHeartbeat(t *testing.T) {
 	t.Run("Error - Database error on FindLatestByDeviceID", func(t *testing.T) {
 		mockHeartbeatRepo := new(MockHeartbeatRepository)
 		mockDeviceRepo := new(MockDeviceRepository)
-		service := NewHeartbeatService(mockHeartbeatRepo, mockDeviceRepo)
+		service := NewHeartbeatService(mockHeartbeatRepo, mockDeviceRepo, NewAuthorizer(new(MockOrganizationRepository), noDeviceShares()))
 
 		mockDeviceRepo.On("FindByID", deviceID).Return(device, nil)
 		mockHeartbeatRepo.On("FindLatestByDeviceID", deviceID).Return((*models.Heartbeat)(nil), errors.New("database error"))
@@ -341,6 +397,101 @@ func TestHeartbeatService_GetLatestDeviceHeartbeat(t *testing.T) {
 	})
 }
 
+func TestHeartbeatService_ExportHeartbeats(t *testing.T) {
+	userID := uuid.New()
+	deviceID := uuid.New()
+	startTime := time.Now().UTC().Add(-time.Hour)
+	endTime := time.Now().UTC()
+
+	t.Run("Success - Export single device", func(t *testing.T) {
+		mockHeartbeatRepo := new(MockHeartbeatRepository)
+		mockDeviceRepo := new(MockDeviceRepository)
+		service := NewHeartbeatService(mockHeartbeatRepo, mockDeviceRepo, NewAuthorizer(new(MockOrganizationRepository), noDeviceShares()))
+
+		device := &models.Device{UUID: deviceID, UserID: userID}
+		rows := []models.Heartbeat{{ID: uuid.New(), DeviceID: deviceID}, {ID: uuid.New(), DeviceID: deviceID}}
+
+		mockDeviceRepo.On("FindByID", deviceID).Return(device, nil)
+		mockHeartbeatRepo.On("StreamByDeviceIDs", []uuid.UUID{deviceID}, startTime, endTime).Return(rows, nil)
+
+		var exported []uuid.UUID
+		err := service.ExportHeartbeats(userID, &deviceID, startTime, endTime, func(h *models.Heartbeat) error {
+			exported = append(exported, h.ID)
+			return nil
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, []uuid.UUID{rows[0].ID, rows[1].ID}, exported)
+		mockDeviceRepo.AssertExpectations(t)
+		mockHeartbeatRepo.AssertExpectations(t)
+	})
+
+	t.Run("Success - Export all user devices", func(t *testing.T) {
+		mockHeartbeatRepo := new(MockHeartbeatRepository)
+		mockDeviceRepo := new(MockDeviceRepository)
+		service := NewHeartbeatService(mockHeartbeatRepo, mockDeviceRepo, NewAuthorizer(new(MockOrganizationRepository), noDeviceShares()))
+
+		otherID := uuid.New()
+		devices := []models.Device{{UUID: deviceID, UserID: userID}, {UUID: otherID, UserID: userID}}
+
+		mockDeviceRepo.On("FindByUserID", userID).Return(devices, nil)
+		mockHeartbeatRepo.On("StreamByDeviceIDs", []uuid.UUID{deviceID, otherID}, startTime, endTime).Return([]models.Heartbeat{}, nil)
+
+		err := service.ExportHeartbeats(userID, nil, startTime, endTime, func(h *models.Heartbeat) error { return nil })
+
+		assert.NoError(t, err)
+		mockHeartbeatRepo.AssertExpectations(t)
+	})
+
+	t.Run("Error - Device belongs to another user", func(t *testing.T) {
+		mockHeartbeatRepo := new(MockHeartbeatRepository)
+		mockDeviceRepo := new(MockDeviceRepository)
+		service := NewHeartbeatService(mockHeartbeatRepo, mockDeviceRepo, NewAuthorizer(new(MockOrganizationRepository), noDeviceShares()))
+
+		mockDeviceRepo.On("FindByID", deviceID).Return(&models.Device{UUID: deviceID, UserID: uuid.New()}, nil)
+
+		err := service.ExportHeartbeats(userID, &deviceID, startTime, endTime, func(h *models.Heartbeat) error { return nil })
+
+		assert.Equal(t, custom_errors.ErrForbidden, err)
+		mockHeartbeatRepo.AssertNotCalled(t, "StreamByDeviceIDs")
+	})
+
+	t.Run("Error - Invalid time range", func(t *testing.T) {
+		service := NewHeartbeatService(new(MockHeartbeatRepository), new(MockDeviceRepository), NewAuthorizer(new(MockOrganizationRepository), noDeviceShares()))
+
+		err := service.ExportHeartbeats(userID, &deviceID, endTime, startTime, func(h *models.Heartbeat) error { return nil })
+
+		assert.Equal(t, custom_errors.NewValidationError("start must be before end"), err)
+	})
+
+	t.Run("Error - Writer error is returned as is", func(t *testing.T) {
+		mockHeartbeatRepo := new(MockHeartbeatRepository)
+		mockDeviceRepo := new(MockDeviceRepository)
+		service := NewHeartbeatService(mockHeartbeatRepo, mockDeviceRepo, NewAuthorizer(new(MockOrganizationRepository), noDeviceShares()))
+
+		writeErr := errors.New("broken pipe")
+		mockDeviceRepo.On("FindByID", deviceID).Return(&models.Device{UUID: deviceID, UserID: userID}, nil)
+		mockHeartbeatRepo.On("StreamByDeviceIDs", []uuid.UUID{deviceID}, startTime, endTime).Return([]models.Heartbeat{{ID: uuid.New()}}, nil)
+
+		err := service.ExportHeartbeats(userID, &deviceID, startTime, endTime, func(h *models.Heartbeat) error { return writeErr })
+
+		assert.Equal(t, writeErr, err)
+	})
+
+	t.Run("Error - Database error while streaming", func(t *testing.T) {
+		mockHeartbeatRepo := new(MockHeartbeatRepository)
+		mockDeviceRepo := new(MockDeviceRepository)
+		service := NewHeartbeatService(mockHeartbeatRepo, mockDeviceRepo, NewAuthorizer(new(MockOrganizationRepository), noDeviceShares()))
+
+		mockDeviceRepo.On("FindByID", deviceID).Return(&models.Device{UUID: deviceID, UserID: userID}, nil)
+		mockHeartbeatRepo.On("StreamByDeviceIDs", []uuid.UUID{deviceID}, startTime, endTime).Return(nil, errors.New("connection reset"))
+
+		err := service.ExportHeartbeats(userID, &deviceID, startTime, endTime, func(h *models.Heartbeat) error { return nil })
+
+		assert.Equal(t, custom_errors.ErrDatabaseError, err)
+	})
+}
+
 func TestHeartbeatService_EdgeCases(t *testing.T) {
 	userID := uuid.New()
 	deviceID := uuid.New()
@@ -355,7 +506,7 @@ func TestHeartbeatService_EdgeCases(t *testing.T) {
 	t.Run("Success - Empty heartbeats list", func(t *testing.T) {
 		mockHeartbeatRepo := new(MockHeartbeatRepository)
 		mockDeviceRepo := new(MockDeviceRepository)
-		service := NewHeartbeatService(mockHeartbeatRepo, mockDeviceRepo)
+		service := NewHeartbeatService(mockHeartbeatRepo, mockDeviceRepo, NewAuthorizer(new(MockOrganizationRepository), noDeviceShares()))
 
 		emptyHeartbeats := []models.Heartbeat{}
 
@@ -375,7 +526,7 @@ func TestHeartbeatService_EdgeCases(t *testing.T) {
 	t.Run("Success - Single heartbeat", func(t *testing.T) {
 		mockHeartbeatRepo := new(MockHeartbeatRepository)
 		mockDeviceRepo := new(MockDeviceRepository)
-		service := NewHeartbeatService(mockHeartbeatRepo, mockDeviceRepo)
+		service := NewHeartbeatService(mockHeartbeatRepo, mockDeviceRepo, NewAuthorizer(new(MockOrganizationRepository), noDeviceShares()))
 
 		singleHeartbeat := []models.Heartbeat{
 			{
@@ -403,9 +554,10 @@ func TestHeartbeatService_EdgeCases(t *testing.T) {
 	t.Run("Success - Extreme values in heartbeat", func(t *testing.T) {
 		mockHeartbeatRepo := new(MockHeartbeatRepository)
 		mockDeviceRepo := new(MockDeviceRepository)
-		service := NewHeartbeatService(mockHeartbeatRepo, mockDeviceRepo)
+		service := NewHeartbeatService(mockHeartbeatRepo, mockDeviceRepo, NewAuthorizer(new(MockOrganizationRepository), noDeviceShares()))
 
 		mockHeartbeatRepo.On("Create", mock.AnythingOfType("*models.Heartbeat")).Return(nil)
+		mockDeviceRepo.On("UpdateLastSeen", deviceID, mock.Anything).Return(nil)
 
 		// Test with extreme values
 		heartbeat, err := service.CreateHeartbeat(
@@ -0,0 +1,58 @@
+package services
+
+import (
+	"time"
+
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/utils/logger"
+)
+
+// HeldNotificationJob periodically releases the messages held back by quiet
+// hours once they end, and the daily digests.
+type HeldNotificationJob struct {
+	preferenceService NotificationPreferenceService
+	interval          time.Duration
+	shutdown          chan struct{}
+}
+
+func NewHeldNotificationJob(preferenceService NotificationPreferenceService, interval time.Duration) *HeldNotificationJob {
+	return &HeldNotificationJob{
+		preferenceService: preferenceService,
+		interval:          interval,
+		shutdown:          make(chan struct{}),
+	}
+}
+
+// Run releases held messages once immediately and then every interval until
+// Stop is called.
+func (j *HeldNotificationJob) Run() {
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	for {
+		j.process()
+		select {
+		case <-ticker.C:
+		case <-j.shutdown:
+			return
+		}
+	}
+}
+
+func (j *HeldNotificationJob) Stop() {
+	select {
+	case <-j.shutdown:
+	default:
+		close(j.shutdown)
+	}
+}
+
+func (j *HeldNotificationJob) process() {
+	released, err := j.preferenceService.ReleaseHeld(time.Now())
+	if err != nil {
+		logger.Logger.Error("Error releasing held notifications", "error", err)
+		return
+	}
+	if released > 0 {
+		logger.Logger.Info("Released held notifications", "released", released)
+	}
+}
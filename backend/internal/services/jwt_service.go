@@ -11,11 +11,17 @@ import (
 
 type JWTService interface {
     GenerateToken(userID uuid.UUID) (string, error)
+    GenerateOrganizationToken(userID, organizationID uuid.UUID, role string) (string, error)
     ValidateToken(tokenString string) (*Claims, error)
 }
 
+// Claims identify the user and, for organization tokens, the active
+// organization and the user's role in it. The role is informative: access
+// checks always read the current membership.
 type Claims struct {
-    UserID uuid.UUID `json:"user_id"`
+    UserID         uuid.UUID  `json:"user_id"`
+    OrganizationID *uuid.UUID `json:"org_id,omitempty"`
+    Role           string     `json:"role,omitempty"`
     jwt.StandardClaims
 }
 
@@ -30,16 +36,25 @@ func NewJWTService(jwtSecret string) JWTService {
 }
 
 func (s *jwtService) GenerateToken(userID uuid.UUID) (string, error) {
+	return s.sign(&Claims{UserID: userID})
+}
+
+func (s *jwtService) GenerateOrganizationToken(userID, organizationID uuid.UUID, role string) (string, error) {
+	return s.sign(&Claims{
+		UserID:         userID,
+		OrganizationID: &organizationID,
+		Role:           role,
+	})
+}
+
+func (s *jwtService) sign(claims *Claims) (string, error) {
 	 if len(s.jwtKey) == 0 {
 		return "", errors.ErrTokenGeneration
 	}
 	
 	expirationTime := time.Now().Add(24 * time.Hour)
-	claims := &Claims{
-		UserID: userID,
-		StandardClaims: jwt.StandardClaims{
-			ExpiresAt: expirationTime.Unix(),
-		},
+	claims.StandardClaims = jwt.StandardClaims{
+		ExpiresAt: expirationTime.Unix(),
 	}
 
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
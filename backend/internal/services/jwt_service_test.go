@@ -33,6 +33,35 @@ func TestJWTService_GenerateToken(t *testing.T) {
     })
 }
 
+func TestJWTService_GenerateOrganizationToken(t *testing.T) {
+    t.Run("Organization and role round-trip through the token", func(t *testing.T) {
+        jwtService := NewJWTService("test-secret-key")
+        userID := uuid.New()
+        organizationID := uuid.New()
+
+        token, err := jwtService.GenerateOrganizationToken(userID, organizationID, "admin")
+        assert.NoError(t, err)
+
+        claims, err := jwtService.ValidateToken(token)
+        assert.NoError(t, err)
+        assert.Equal(t, userID, claims.UserID)
+        assert.Equal(t, organizationID, *claims.OrganizationID)
+        assert.Equal(t, "admin", claims.Role)
+    })
+
+    t.Run("Personal token has no organization", func(t *testing.T) {
+        jwtService := NewJWTService("test-secret-key")
+
+        token, err := jwtService.GenerateToken(uuid.New())
+        assert.NoError(t, err)
+
+        claims, err := jwtService.ValidateToken(token)
+        assert.NoError(t, err)
+        assert.Nil(t, claims.OrganizationID)
+        assert.Empty(t, claims.Role)
+    })
+}
+
 func TestJWTService_ValidateToken(t *testing.T) {
     t.Run("Successfully validate valid token", func(t *testing.T) {
         jwtService := NewJWTService("test-secret-key")
@@ -0,0 +1,447 @@
+package services
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/dto"
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/models"
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/repository"
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/utils"
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/utils/errors"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	MaintenanceWindowActive   = "active"
+	MaintenanceWindowUpcoming = "upcoming"
+)
+
+const (
+	// MaxMaintenanceDurationMinutes caps each occurrence of a recurring
+	// window at a week.
+	MaxMaintenanceDurationMinutes = 7 * 24 * 60
+	// MaxMaintenanceScopeItems caps each of the device, group and location
+	// lists of a window.
+	MaxMaintenanceScopeItems = 100
+	// MaxSuppressedAlerts is the number of suppressed alerts returned for a
+	// window.
+	MaxSuppressedAlerts = 200
+)
+
+type MaintenanceWindowService interface {
+	CreateWindow(userID uuid.UUID, orgID *uuid.UUID, req dto.MaintenanceWindowRequest) (*dto.MaintenanceWindowResponse, error)
+	ListWindows(userID uuid.UUID, status string) ([]dto.MaintenanceWindowResponse, error)
+	GetWindow(userID, windowID uuid.UUID) (*dto.MaintenanceWindowResponse, error)
+	DeleteWindow(userID, windowID uuid.UUID) error
+	ListSuppressedAlerts(userID, windowID uuid.UUID) ([]dto.SuppressedAlertResponse, error)
+	ActiveWindow(device *models.Device, now time.Time) (*models.MaintenanceWindow, error)
+	CoveringWindows(device *models.Device, from, to time.Time) ([]models.MaintenanceWindow, error)
+	RecordSuppressedAlert(window *models.MaintenanceWindow, notification models.Notification, device *models.Device, triggeredValue float64) error
+}
+
+type maintenanceWindowService struct {
+	windowRepo repository.MaintenanceWindowRepository
+	deviceRepo repository.DeviceRepository
+	groupRepo  repository.DeviceGroupRepository
+	authz      Authorizer
+}
+
+func NewMaintenanceWindowService(windowRepo repository.MaintenanceWindowRepository, deviceRepo repository.DeviceRepository, groupRepo repository.DeviceGroupRepository, authz Authorizer) MaintenanceWindowService {
+	return &maintenanceWindowService{
+		windowRepo: windowRepo,
+		deviceRepo: deviceRepo,
+		groupRepo:  groupRepo,
+		authz:      authz,
+	}
+}
+
+// CreateWindow creates a personal window, or an organization window when
+// orgID is set. Like notification rules it requires the operator role.
+func (s *maintenanceWindowService) CreateWindow(userID uuid.UUID, orgID *uuid.UUID, req dto.MaintenanceWindowRequest) (*dto.MaintenanceWindowResponse, error) {
+	owner := Ownership{UserID: userID, OrganizationID: orgID}
+	if err := s.authz.Authorize(userID, owner, models.RoleOperator); err != nil {
+		return nil, err
+	}
+
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return nil, errors.NewValidationError("Maintenance window name is required")
+	}
+
+	now := time.Now()
+	window := &models.MaintenanceWindow{
+		ID:             uuid.New(),
+		UserID:         userID,
+		OrganizationID: orgID,
+		Name:           name,
+		Description:    strings.TrimSpace(req.Description),
+		StartsAt:       req.StartsAt.UTC(),
+		Schedule:       strings.TrimSpace(req.Schedule),
+		Timezone:       strings.TrimSpace(req.Timezone),
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+	if req.EndsAt != nil {
+		endsAt := req.EndsAt.UTC()
+		if !endsAt.After(window.StartsAt) {
+			return nil, errors.NewValidationError("ends_at must be after starts_at")
+		}
+		if !endsAt.After(now) {
+			return nil, errors.NewValidationError("ends_at must be in the future")
+		}
+		window.EndsAt = &endsAt
+	}
+	if window.Timezone == "" {
+		window.Timezone = "UTC"
+	}
+	if _, err := time.LoadLocation(window.Timezone); err != nil {
+		return nil, errors.NewValidationError("Invalid timezone: " + window.Timezone)
+	}
+
+	if window.Recurring() {
+		if _, err := utils.ParseCronSchedule(window.Schedule); err != nil {
+			return nil, errors.NewValidationError("Invalid schedule: " + err.Error())
+		}
+		if req.DurationMinutes < 1 || req.DurationMinutes > MaxMaintenanceDurationMinutes {
+			return nil, errors.NewValidationError(fmt.Sprintf("duration_minutes must be between 1 and %d", MaxMaintenanceDurationMinutes))
+		}
+		window.DurationMinutes = req.DurationMinutes
+		if _, _, ok := windowOccurrence(window, now); !ok {
+			return nil, errors.NewValidationError("The schedule has no occurrence between starts_at and ends_at")
+		}
+	} else if window.EndsAt == nil {
+		return nil, errors.NewValidationError("ends_at is required for a one-off window")
+	}
+
+	if len(req.DeviceIDs) == 0 && len(req.GroupIDs) == 0 && len(req.Locations) == 0 {
+		return nil, errors.NewValidationError("Scope the window to device_ids, group_ids and/or locations")
+	}
+	if len(req.DeviceIDs) > MaxMaintenanceScopeItems || len(req.GroupIDs) > MaxMaintenanceScopeItems || len(req.Locations) > MaxMaintenanceScopeItems {
+		return nil, errors.NewValidationError(fmt.Sprintf("A window can list at most %d devices, groups and locations each", MaxMaintenanceScopeItems))
+	}
+
+	window.DeviceIDs = make([]uuid.UUID, 0, len(req.DeviceIDs))
+	for _, id := range uniqueIDs(req.DeviceIDs) {
+		device, err := s.deviceRepo.FindByID(id)
+		if err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return nil, errors.ErrDeviceNotFound
+			}
+			return nil, errors.ErrDatabaseError
+		}
+		if !deviceOwnership(device).SameOwner(owner) {
+			return nil, errors.ErrDeviceNotFound
+		}
+		window.DeviceIDs = append(window.DeviceIDs, id)
+	}
+
+	window.GroupIDs = make([]uuid.UUID, 0, len(req.GroupIDs))
+	for _, id := range uniqueIDs(req.GroupIDs) {
+		group, err := s.groupRepo.FindByID(id)
+		if err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return nil, errors.ErrDeviceGroupNotFound
+			}
+			return nil, errors.ErrDatabaseError
+		}
+		if !groupOwnership(group).SameOwner(owner) {
+			return nil, errors.ErrDeviceGroupNotFound
+		}
+		window.GroupIDs = append(window.GroupIDs, id)
+	}
+
+	window.Locations = make([]string, 0, len(req.Locations))
+	for _, location := range req.Locations {
+		location = strings.TrimSpace(location)
+		if location == "" {
+			return nil, errors.NewValidationError("Locations cannot be empty")
+		}
+		window.Locations = append(window.Locations, location)
+	}
+
+	if err := s.windowRepo.Create(window); err != nil {
+		return nil, errors.ErrDatabaseError
+	}
+	return maintenanceWindowResponse(window, now), nil
+}
+
+// ListWindows returns the active and upcoming windows the user can see,
+// soonest first. status narrows the list to one of them.
+func (s *maintenanceWindowService) ListWindows(userID uuid.UUID, status string) ([]dto.MaintenanceWindowResponse, error) {
+	if status != "" && status != MaintenanceWindowActive && status != MaintenanceWindowUpcoming {
+		return nil, errors.NewValidationError("status must be active or upcoming")
+	}
+
+	now := time.Now()
+	windows, err := s.windowRepo.FindUnfinishedByUserID(userID, now)
+	if err != nil {
+		return nil, errors.ErrDatabaseError
+	}
+
+	responses := make([]dto.MaintenanceWindowResponse, 0, len(windows))
+	for i := range windows {
+		response := maintenanceWindowResponse(&windows[i], now)
+		if response.NextStartAt == nil {
+			continue
+		}
+		if status == MaintenanceWindowActive && !response.Active || status == MaintenanceWindowUpcoming && response.Active {
+			continue
+		}
+		responses = append(responses, *response)
+	}
+	sort.SliceStable(responses, func(i, j int) bool {
+		return responses[i].NextStartAt.Before(*responses[j].NextStartAt)
+	})
+	return responses, nil
+}
+
+func (s *maintenanceWindowService) GetWindow(userID, windowID uuid.UUID) (*dto.MaintenanceWindowResponse, error) {
+	window, err := s.findWindow(userID, windowID, models.RoleViewer)
+	if err != nil {
+		return nil, err
+	}
+	return maintenanceWindowResponse(window, time.Now()), nil
+}
+
+// DeleteWindow removes the window and the alerts it suppressed. Requires
+// the operator role.
+func (s *maintenanceWindowService) DeleteWindow(userID, windowID uuid.UUID) error {
+	window, err := s.findWindow(userID, windowID, models.RoleOperator)
+	if err != nil {
+		return err
+	}
+
+	if err := s.windowRepo.Delete(window.ID); err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return errors.ErrMaintenanceWindowNotFound
+		}
+		return errors.ErrDatabaseError
+	}
+	return nil
+}
+
+func (s *maintenanceWindowService) ListSuppressedAlerts(userID, windowID uuid.UUID) ([]dto.SuppressedAlertResponse, error) {
+	window, err := s.findWindow(userID, windowID, models.RoleViewer)
+	if err != nil {
+		return nil, err
+	}
+
+	alerts, err := s.windowRepo.FindSuppressedAlerts(window.ID, MaxSuppressedAlerts)
+	if err != nil {
+		return nil, errors.ErrDatabaseError
+	}
+
+	responses := make([]dto.SuppressedAlertResponse, 0, len(alerts))
+	for _, alert := range alerts {
+		responses = append(responses, dto.SuppressedAlertResponse{
+			ID:             alert.ID,
+			NotificationID: alert.NotificationID,
+			DeviceID:       alert.DeviceID,
+			Name:           alert.Name,
+			TriggeredValue: alert.TriggeredValue,
+			CreatedAt:      alert.CreatedAt,
+		})
+	}
+	return responses, nil
+}
+
+// ActiveWindow returns a window of the device's owner that is active at now
+// and covers the device, or nil when there is none.
+func (s *maintenanceWindowService) ActiveWindow(device *models.Device, now time.Time) (*models.MaintenanceWindow, error) {
+	windows, err := s.windowRepo.FindStartedByOwner(device.UserID, device.OrganizationID, now)
+	if err != nil {
+		return nil, errors.ErrDatabaseError
+	}
+
+	for i := range windows {
+		window := &windows[i]
+		if !windowActive(window, now) {
+			continue
+		}
+		covered, err := s.covers(window, device)
+		if err != nil {
+			return nil, err
+		}
+		if covered {
+			return window, nil
+		}
+	}
+	return nil, nil
+}
+
+// CoveringWindows returns the windows of the device's owner that cover the
+// device and may be active between from and to. activeWindowAt tells which
+// of them is active at a given time.
+func (s *maintenanceWindowService) CoveringWindows(device *models.Device, from, to time.Time) ([]models.MaintenanceWindow, error) {
+	windows, err := s.windowRepo.FindOverlappingByOwner(device.UserID, device.OrganizationID, from, to)
+	if err != nil {
+		return nil, errors.ErrDatabaseError
+	}
+
+	var covering []models.MaintenanceWindow
+	for i := range windows {
+		covered, err := s.covers(&windows[i], device)
+		if err != nil {
+			return nil, err
+		}
+		if covered {
+			covering = append(covering, windows[i])
+		}
+	}
+	return covering, nil
+}
+
+func (s *maintenanceWindowService) RecordSuppressedAlert(window *models.MaintenanceWindow, notification models.Notification, device *models.Device, triggeredValue float64) error {
+	alert := &models.SuppressedAlert{
+		ID:             uuid.New(),
+		WindowID:       window.ID,
+		NotificationID: notification.ID,
+		DeviceID:       device.UUID,
+		Name:           notification.Name,
+		TriggeredValue: triggeredValue,
+		CreatedAt:      time.Now(),
+	}
+	if err := s.windowRepo.CreateSuppressedAlert(alert); err != nil {
+		return errors.ErrDatabaseError
+	}
+	return nil
+}
+
+// covers reports whether the device is in the scope of the window.
+func (s *maintenanceWindowService) covers(window *models.MaintenanceWindow, device *models.Device) (bool, error) {
+	for _, id := range window.DeviceIDs {
+		if id == device.UUID {
+			return true, nil
+		}
+	}
+	for _, location := range window.Locations {
+		if strings.EqualFold(location, strings.TrimSpace(device.Location)) {
+			return true, nil
+		}
+	}
+	if device.GroupID == nil {
+		return false, nil
+	}
+	for _, groupID := range window.GroupIDs {
+		groupIDs, err := s.groupRepo.FindDescendantIDs(groupID)
+		if err != nil {
+			return false, errors.ErrDatabaseError
+		}
+		for _, id := range groupIDs {
+			if id == *device.GroupID {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
+
+func (s *maintenanceWindowService) findWindow(userID, windowID uuid.UUID, role string) (*models.MaintenanceWindow, error) {
+	window, err := s.windowRepo.FindByID(windowID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.ErrMaintenanceWindowNotFound
+		}
+		return nil, errors.ErrDatabaseError
+	}
+	if err := s.authz.Authorize(userID, Ownership{UserID: window.UserID, OrganizationID: window.OrganizationID}, role); err != nil {
+		return nil, err
+	}
+	return window, nil
+}
+
+// windowOccurrence returns the occurrence of the window in progress at now,
+// or the next one. ok is false once the window has no occurrence left.
+func windowOccurrence(window *models.MaintenanceWindow, now time.Time) (start, end time.Time, ok bool) {
+	if !window.Recurring() {
+		if window.EndsAt == nil || !now.Before(*window.EndsAt) {
+			return time.Time{}, time.Time{}, false
+		}
+		return window.StartsAt, *window.EndsAt, true
+	}
+
+	schedule, err := utils.ParseCronSchedule(window.Schedule)
+	if err != nil {
+		return time.Time{}, time.Time{}, false
+	}
+	loc, err := time.LoadLocation(window.Timezone)
+	if err != nil {
+		loc = time.UTC
+	}
+
+	duration := time.Duration(window.DurationMinutes) * time.Minute
+	// An occurrence that started less than duration ago is still running.
+	from := now.Add(-duration)
+	if from.Before(window.StartsAt) {
+		from = window.StartsAt.Add(-time.Nanosecond)
+	}
+	start = schedule.Next(from.In(loc))
+	if start.IsZero() {
+		return time.Time{}, time.Time{}, false
+	}
+	end = start.Add(duration)
+	if window.EndsAt != nil {
+		if !start.Before(*window.EndsAt) {
+			return time.Time{}, time.Time{}, false
+		}
+		if end.After(*window.EndsAt) {
+			end = *window.EndsAt
+		}
+	}
+	return start.UTC(), end.UTC(), true
+}
+
+func windowActive(window *models.MaintenanceWindow, now time.Time) bool {
+	start, end, ok := windowOccurrence(window, now)
+	return ok && !now.Before(start) && now.Before(end)
+}
+
+// activeWindowAt returns the first of the windows active at t, or nil.
+func activeWindowAt(windows []models.MaintenanceWindow, t time.Time) *models.MaintenanceWindow {
+	for i := range windows {
+		if windowActive(&windows[i], t) {
+			return &windows[i]
+		}
+	}
+	return nil
+}
+
+func uniqueIDs(ids []uuid.UUID) []uuid.UUID {
+	seen := make(map[uuid.UUID]bool, len(ids))
+	unique := make([]uuid.UUID, 0, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+	return unique
+}
+
+func maintenanceWindowResponse(window *models.MaintenanceWindow, now time.Time) *dto.MaintenanceWindowResponse {
+	response := &dto.MaintenanceWindowResponse{
+		ID:              window.ID,
+		OrganizationID:  window.OrganizationID,
+		Name:            window.Name,
+		Description:     window.Description,
+		StartsAt:        window.StartsAt,
+		EndsAt:          window.EndsAt,
+		Schedule:        window.Schedule,
+		DurationMinutes: window.DurationMinutes,
+		Timezone:        window.Timezone,
+		DeviceIDs:       window.DeviceIDs,
+		GroupIDs:        window.GroupIDs,
+		Locations:       window.Locations,
+		CreatedAt:       window.CreatedAt,
+	}
+	if start, end, ok := windowOccurrence(window, now); ok {
+		response.Active = !now.Before(start) && now.Before(end)
+		response.NextStartAt = &start
+		response.NextEndAt = &end
+	}
+	return response
+}
@@ -0,0 +1,348 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/dto"
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/models"
+	custom_errors "github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/utils/errors"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+type MockMaintenanceWindowRepository struct {
+	mock.Mock
+}
+
+func (m *MockMaintenanceWindowRepository) Create(window *models.MaintenanceWindow) error {
+	args := m.Called(window)
+	return args.Error(0)
+}
+
+func (m *MockMaintenanceWindowRepository) FindByID(id uuid.UUID) (*models.MaintenanceWindow, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.MaintenanceWindow), args.Error(1)
+}
+
+func (m *MockMaintenanceWindowRepository) FindUnfinishedByUserID(userID uuid.UUID, now time.Time) ([]models.MaintenanceWindow, error) {
+	args := m.Called(userID, now)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.MaintenanceWindow), args.Error(1)
+}
+
+func (m *MockMaintenanceWindowRepository) FindStartedByOwner(userID uuid.UUID, organizationID *uuid.UUID, now time.Time) ([]models.MaintenanceWindow, error) {
+	args := m.Called(userID, organizationID, now)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.MaintenanceWindow), args.Error(1)
+}
+
+func (m *MockMaintenanceWindowRepository) FindOverlappingByOwner(userID uuid.UUID, organizationID *uuid.UUID, from, to time.Time) ([]models.MaintenanceWindow, error) {
+	args := m.Called(userID, organizationID, from, to)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.MaintenanceWindow), args.Error(1)
+}
+
+func (m *MockMaintenanceWindowRepository) Delete(id uuid.UUID) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MockMaintenanceWindowRepository) CreateSuppressedAlert(alert *models.SuppressedAlert) error {
+	args := m.Called(alert)
+	return args.Error(0)
+}
+
+func (m *MockMaintenanceWindowRepository) FindSuppressedAlerts(windowID uuid.UUID, limit int) ([]models.SuppressedAlert, error) {
+	args := m.Called(windowID, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.SuppressedAlert), args.Error(1)
+}
+
+// noMaintenanceWindows returns a maintenance window service for which no
+// window is ever active.
+func noMaintenanceWindows() MaintenanceWindowService {
+	windowRepo := new(MockMaintenanceWindowRepository)
+	windowRepo.On("FindStartedByOwner", mock.Anything, mock.Anything, mock.Anything).Return([]models.MaintenanceWindow{}, nil).Maybe()
+	windowRepo.On("FindOverlappingByOwner", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return([]models.MaintenanceWindow{}, nil).Maybe()
+	return NewMaintenanceWindowService(windowRepo, new(MockDeviceRepository), new(MockDeviceGroupRepository), NewAuthorizer(new(MockOrganizationRepository), noDeviceShares()))
+}
+
+func newTestMaintenanceWindowService() (MaintenanceWindowService, *MockMaintenanceWindowRepository, *MockDeviceRepository, *MockDeviceGroupRepository) {
+	windowRepo := new(MockMaintenanceWindowRepository)
+	deviceRepo := new(MockDeviceRepository)
+	groupRepo := new(MockDeviceGroupRepository)
+	authz := NewAuthorizer(new(MockOrganizationRepository), noDeviceShares())
+	return NewMaintenanceWindowService(windowRepo, deviceRepo, groupRepo, authz), windowRepo, deviceRepo, groupRepo
+}
+
+func TestMaintenanceWindowService_CreateWindow(t *testing.T) {
+	userID := uuid.New()
+	deviceID := uuid.New()
+	groupID := uuid.New()
+	startsAt := time.Now().Add(time.Hour).Truncate(time.Minute)
+	endsAt := startsAt.Add(2 * time.Hour)
+
+	t.Run("Success - One-off window on devices and locations", func(t *testing.T) {
+		service, windowRepo, deviceRepo, _ := newTestMaintenanceWindowService()
+		deviceRepo.On("FindByID", deviceID).Return(&models.Device{UUID: deviceID, UserID: userID}, nil).Once()
+		windowRepo.On("Create", mock.AnythingOfType("*models.MaintenanceWindow")).Return(nil)
+
+		window, err := service.CreateWindow(userID, nil, dto.MaintenanceWindowRequest{
+			Name:      "Rack 3 power work",
+			StartsAt:  startsAt,
+			EndsAt:    &endsAt,
+			DeviceIDs: []uuid.UUID{deviceID, deviceID},
+			Locations: []string{" Data Center SP "},
+		})
+
+		assert.NoError(t, err)
+		assert.False(t, window.Active)
+		assert.Equal(t, startsAt.UTC(), *window.NextStartAt)
+		assert.Equal(t, []uuid.UUID{deviceID}, window.DeviceIDs)
+		assert.Equal(t, []string{"Data Center SP"}, window.Locations)
+		assert.Equal(t, "UTC", window.Timezone)
+		deviceRepo.AssertExpectations(t)
+	})
+
+	t.Run("Success - Recurring window", func(t *testing.T) {
+		service, windowRepo, _, groupRepo := newTestMaintenanceWindowService()
+		groupRepo.On("FindByID", groupID).Return(&models.DeviceGroup{ID: groupID, UserID: userID}, nil)
+		windowRepo.On("Create", mock.AnythingOfType("*models.MaintenanceWindow")).Return(nil)
+
+		window, err := service.CreateWindow(userID, nil, dto.MaintenanceWindowRequest{
+			Name:            "Weekly patching",
+			StartsAt:        startsAt,
+			Schedule:        "0 2 * * 0",
+			DurationMinutes: 120,
+			Timezone:        "America/Sao_Paulo",
+			GroupIDs:        []uuid.UUID{groupID},
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, time.Sunday, window.NextStartAt.In(mustLoadLocation(t, "America/Sao_Paulo")).Weekday())
+		assert.Equal(t, 2*time.Hour, window.NextEndAt.Sub(*window.NextStartAt))
+	})
+
+	t.Run("Error - Invalid schedule", func(t *testing.T) {
+		service, windowRepo, _, _ := newTestMaintenanceWindowService()
+
+		window, err := service.CreateWindow(userID, nil, dto.MaintenanceWindowRequest{
+			Name:            "Broken",
+			StartsAt:        startsAt,
+			Schedule:        "61 * * * *",
+			DurationMinutes: 30,
+			Locations:       []string{"Lab"},
+		})
+
+		assert.Nil(t, window)
+		assert.ErrorContains(t, err, "Invalid schedule")
+		windowRepo.AssertNotCalled(t, "Create", mock.Anything)
+	})
+
+	t.Run("Error - One-off window without end", func(t *testing.T) {
+		service, _, _, _ := newTestMaintenanceWindowService()
+
+		window, err := service.CreateWindow(userID, nil, dto.MaintenanceWindowRequest{Name: "Open ended", StartsAt: startsAt, Locations: []string{"Lab"}})
+
+		assert.Nil(t, window)
+		assert.Error(t, err)
+	})
+
+	t.Run("Error - Without scope", func(t *testing.T) {
+		service, _, _, _ := newTestMaintenanceWindowService()
+
+		window, err := service.CreateWindow(userID, nil, dto.MaintenanceWindowRequest{Name: "Everything", StartsAt: startsAt, EndsAt: &endsAt})
+
+		assert.Nil(t, window)
+		assert.Error(t, err)
+	})
+
+	t.Run("Error - Group of another user", func(t *testing.T) {
+		service, windowRepo, _, groupRepo := newTestMaintenanceWindowService()
+		groupRepo.On("FindByID", groupID).Return(&models.DeviceGroup{ID: groupID, UserID: uuid.New()}, nil)
+
+		window, err := service.CreateWindow(userID, nil, dto.MaintenanceWindowRequest{Name: "Foreign", StartsAt: startsAt, EndsAt: &endsAt, GroupIDs: []uuid.UUID{groupID}})
+
+		assert.Nil(t, window)
+		assert.Equal(t, custom_errors.ErrDeviceGroupNotFound, err)
+		windowRepo.AssertNotCalled(t, "Create", mock.Anything)
+	})
+}
+
+func TestMaintenanceWindowService_ListWindows(t *testing.T) {
+	userID := uuid.New()
+	now := time.Now()
+	activeEnd := now.Add(time.Hour)
+	upcomingEnd := now.Add(3 * time.Hour)
+	active := models.MaintenanceWindow{ID: uuid.New(), UserID: userID, StartsAt: now.Add(-time.Hour), EndsAt: &activeEnd}
+	upcoming := models.MaintenanceWindow{ID: uuid.New(), UserID: userID, StartsAt: now.Add(2 * time.Hour), EndsAt: &upcomingEnd}
+
+	t.Run("Success - Active and upcoming, soonest first", func(t *testing.T) {
+		service, windowRepo, _, _ := newTestMaintenanceWindowService()
+		windowRepo.On("FindUnfinishedByUserID", userID, mock.AnythingOfType("time.Time")).Return([]models.MaintenanceWindow{upcoming, active}, nil)
+
+		windows, err := service.ListWindows(userID, "")
+
+		assert.NoError(t, err)
+		assert.Len(t, windows, 2)
+		assert.Equal(t, active.ID, windows[0].ID)
+		assert.True(t, windows[0].Active)
+		assert.False(t, windows[1].Active)
+	})
+
+	t.Run("Success - Only upcoming", func(t *testing.T) {
+		service, windowRepo, _, _ := newTestMaintenanceWindowService()
+		windowRepo.On("FindUnfinishedByUserID", userID, mock.AnythingOfType("time.Time")).Return([]models.MaintenanceWindow{upcoming, active}, nil)
+
+		windows, err := service.ListWindows(userID, MaintenanceWindowUpcoming)
+
+		assert.NoError(t, err)
+		assert.Len(t, windows, 1)
+		assert.Equal(t, upcoming.ID, windows[0].ID)
+	})
+
+	t.Run("Error - Invalid status", func(t *testing.T) {
+		service, windowRepo, _, _ := newTestMaintenanceWindowService()
+
+		windows, err := service.ListWindows(userID, "finished")
+
+		assert.Nil(t, windows)
+		assert.Error(t, err)
+		windowRepo.AssertNotCalled(t, "FindUnfinishedByUserID", mock.Anything, mock.Anything)
+	})
+}
+
+func TestMaintenanceWindowService_ActiveWindow(t *testing.T) {
+	userID := uuid.New()
+	rackID := uuid.New()
+	siteID := uuid.New()
+	device := &models.Device{UUID: uuid.New(), UserID: userID, GroupID: &rackID, Location: "Data Center SP"}
+	// Every day from 10:00 to 12:00 UTC.
+	now := time.Date(2025, 10, 1, 11, 0, 0, 0, time.UTC)
+	daily := models.MaintenanceWindow{ID: uuid.New(), UserID: userID, StartsAt: now.AddDate(0, -1, 0), Schedule: "0 10 * * *", DurationMinutes: 120, Timezone: "UTC"}
+
+	t.Run("Success - Recurring window covering a subgroup", func(t *testing.T) {
+		service, windowRepo, _, groupRepo := newTestMaintenanceWindowService()
+		window := daily
+		window.GroupIDs = []uuid.UUID{siteID}
+		windowRepo.On("FindStartedByOwner", userID, (*uuid.UUID)(nil), now).Return([]models.MaintenanceWindow{window}, nil)
+		groupRepo.On("FindDescendantIDs", siteID).Return([]uuid.UUID{siteID, rackID}, nil)
+
+		active, err := service.ActiveWindow(device, now)
+
+		assert.NoError(t, err)
+		assert.Equal(t, window.ID, active.ID)
+	})
+
+	t.Run("Success - Location matches case-insensitively", func(t *testing.T) {
+		service, windowRepo, _, _ := newTestMaintenanceWindowService()
+		window := daily
+		window.Locations = []string{"data center sp"}
+		windowRepo.On("FindStartedByOwner", userID, (*uuid.UUID)(nil), now).Return([]models.MaintenanceWindow{window}, nil)
+
+		active, err := service.ActiveWindow(device, now)
+
+		assert.NoError(t, err)
+		assert.NotNil(t, active)
+	})
+
+	t.Run("Success - Outside the occurrence", func(t *testing.T) {
+		service, windowRepo, _, _ := newTestMaintenanceWindowService()
+		window := daily
+		window.DeviceIDs = []uuid.UUID{device.UUID}
+		later := now.Add(time.Hour)
+		windowRepo.On("FindStartedByOwner", userID, (*uuid.UUID)(nil), later).Return([]models.MaintenanceWindow{window}, nil)
+
+		active, err := service.ActiveWindow(device, later)
+
+		assert.NoError(t, err)
+		assert.Nil(t, active)
+	})
+
+	t.Run("Success - Device out of scope", func(t *testing.T) {
+		service, windowRepo, _, _ := newTestMaintenanceWindowService()
+		window := daily
+		window.DeviceIDs = []uuid.UUID{uuid.New()}
+		windowRepo.On("FindStartedByOwner", userID, (*uuid.UUID)(nil), now).Return([]models.MaintenanceWindow{window}, nil)
+
+		active, err := service.ActiveWindow(device, now)
+
+		assert.NoError(t, err)
+		assert.Nil(t, active)
+	})
+}
+
+func TestMaintenanceWindowService_DeleteWindow(t *testing.T) {
+	userID := uuid.New()
+	windowID := uuid.New()
+
+	t.Run("Error - Window not found", func(t *testing.T) {
+		service, windowRepo, _, _ := newTestMaintenanceWindowService()
+		windowRepo.On("FindByID", windowID).Return(nil, gorm.ErrRecordNotFound)
+
+		err := service.DeleteWindow(userID, windowID)
+
+		assert.Equal(t, custom_errors.ErrMaintenanceWindowNotFound, err)
+	})
+
+	t.Run("Error - Window of another user", func(t *testing.T) {
+		service, windowRepo, _, _ := newTestMaintenanceWindowService()
+		windowRepo.On("FindByID", windowID).Return(&models.MaintenanceWindow{ID: windowID, UserID: uuid.New()}, nil)
+
+		err := service.DeleteWindow(userID, windowID)
+
+		assert.Equal(t, custom_errors.ErrForbidden, err)
+		windowRepo.AssertNotCalled(t, "Delete", mock.Anything)
+	})
+}
+
+func TestWindowOccurrence(t *testing.T) {
+	startsAt := time.Date(2025, 9, 1, 0, 0, 0, 0, time.UTC)
+	until := time.Date(2025, 9, 30, 0, 0, 0, 0, time.UTC)
+	// Weekdays from 22:00 to 23:30 in São Paulo (UTC-3).
+	window := &models.MaintenanceWindow{StartsAt: startsAt, EndsAt: &until, Schedule: "0 22 * * 1-5", DurationMinutes: 90, Timezone: "America/Sao_Paulo"}
+
+	t.Run("Next weekday occurrence", func(t *testing.T) {
+		// Saturday 2025-09-06 12:00 UTC; next occurrence is Monday 22:00 local.
+		start, end, ok := windowOccurrence(window, time.Date(2025, 9, 6, 12, 0, 0, 0, time.UTC))
+
+		assert.True(t, ok)
+		assert.Equal(t, time.Date(2025, 9, 9, 1, 0, 0, 0, time.UTC), start)
+		assert.Equal(t, time.Date(2025, 9, 9, 2, 30, 0, 0, time.UTC), end)
+	})
+
+	t.Run("Occurrence in progress", func(t *testing.T) {
+		now := time.Date(2025, 9, 9, 2, 0, 0, 0, time.UTC)
+		start, _, ok := windowOccurrence(window, now)
+
+		assert.True(t, ok)
+		assert.Equal(t, time.Date(2025, 9, 9, 1, 0, 0, 0, time.UTC), start)
+		assert.True(t, windowActive(window, now))
+	})
+
+	t.Run("No occurrence after ends_at", func(t *testing.T) {
+		_, _, ok := windowOccurrence(window, time.Date(2025, 9, 30, 0, 0, 0, 0, time.UTC))
+
+		assert.False(t, ok)
+	})
+}
+
+func mustLoadLocation(t *testing.T, name string) *time.Location {
+	loc, err := time.LoadLocation(name)
+	assert.NoError(t, err)
+	return loc
+}
@@ -0,0 +1,244 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/dto"
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/models"
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/repository"
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/utils/errors"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	MuteActive = "active"
+	MuteEnded  = "ended"
+	MuteAll    = "all"
+)
+
+// MaxMuteDurationMinutes caps a mute at 30 days; longer silences belong in a
+// maintenance window or in disabling the rule.
+const MaxMuteDurationMinutes = 30 * 24 * 60
+
+type MuteService interface {
+	CreateMute(userID uuid.UUID, req dto.MuteRequest) (*dto.MuteResponse, error)
+	ListMutes(userID uuid.UUID, status string) ([]dto.MuteResponse, error)
+	CancelMute(userID, muteID uuid.UUID) (*dto.MuteResponse, error)
+	ActiveMutes(device *models.Device, now time.Time) ([]models.Mute, error)
+	CoveringMutes(device *models.Device, from, to time.Time) ([]models.Mute, error)
+}
+
+type muteService struct {
+	muteRepo         repository.MuteRepository
+	notificationRepo repository.NotificationRepository
+	deviceRepo       repository.DeviceRepository
+	authz            Authorizer
+}
+
+func NewMuteService(muteRepo repository.MuteRepository, notificationRepo repository.NotificationRepository, deviceRepo repository.DeviceRepository, authz Authorizer) MuteService {
+	return &muteService{
+		muteRepo:         muteRepo,
+		notificationRepo: notificationRepo,
+		deviceRepo:       deviceRepo,
+		authz:            authz,
+	}
+}
+
+// CreateMute mutes a rule, a device, or a rule on one device. The mute
+// belongs to the owner of the rule or device, and like maintenance windows
+// requires the operator role. A rule and a device muted together must have
+// the same owner.
+func (s *muteService) CreateMute(userID uuid.UUID, req dto.MuteRequest) (*dto.MuteResponse, error) {
+	if req.NotificationID == nil && req.DeviceID == nil {
+		return nil, errors.NewValidationError("Scope the mute to notification_id, device_id or both")
+	}
+
+	now := time.Now()
+	var expiresAt time.Time
+	switch {
+	case req.DurationMinutes != 0 && req.ExpiresAt != nil:
+		return nil, errors.NewValidationError("Set either duration_minutes or expires_at, not both")
+	case req.ExpiresAt != nil:
+		expiresAt = req.ExpiresAt.UTC()
+		if !expiresAt.After(now) {
+			return nil, errors.NewValidationError("expires_at must be in the future")
+		}
+	case req.DurationMinutes != 0:
+		if req.DurationMinutes < 0 {
+			return nil, errors.NewValidationError("duration_minutes must be positive")
+		}
+		expiresAt = now.Add(time.Duration(req.DurationMinutes) * time.Minute).UTC()
+	default:
+		return nil, errors.NewValidationError("duration_minutes or expires_at is required")
+	}
+	if expiresAt.After(now.Add(MaxMuteDurationMinutes * time.Minute)) {
+		return nil, errors.NewValidationError(fmt.Sprintf("A mute can last at most %d minutes", MaxMuteDurationMinutes))
+	}
+
+	var owner *Ownership
+	if req.NotificationID != nil {
+		notification, err := s.notificationRepo.FindByID(*req.NotificationID)
+		if err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return nil, errors.ErrNotificationNotFound
+			}
+			return nil, errors.ErrDatabaseError
+		}
+		owner = &Ownership{UserID: notification.UserID, OrganizationID: notification.OrganizationID}
+	}
+	if req.DeviceID != nil {
+		device, err := s.deviceRepo.FindByID(*req.DeviceID)
+		if err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return nil, errors.ErrDeviceNotFound
+			}
+			return nil, errors.ErrDatabaseError
+		}
+		deviceOwner := deviceOwnership(device)
+		if owner != nil && !deviceOwner.SameOwner(*owner) {
+			return nil, errors.ErrDeviceNotFound
+		}
+		if owner == nil {
+			owner = &deviceOwner
+		}
+	}
+	if err := s.authz.Authorize(userID, *owner, models.RoleOperator); err != nil {
+		return nil, err
+	}
+
+	mute := &models.Mute{
+		ID:             uuid.New(),
+		UserID:         owner.UserID,
+		OrganizationID: owner.OrganizationID,
+		NotificationID: req.NotificationID,
+		DeviceID:       req.DeviceID,
+		Reason:         strings.TrimSpace(req.Reason),
+		ExpiresAt:      expiresAt,
+		CreatedBy:      userID,
+		CreatedAt:      now,
+	}
+	if err := s.muteRepo.Create(mute); err != nil {
+		return nil, errors.ErrDatabaseError
+	}
+	return muteResponse(mute, now), nil
+}
+
+// ListMutes returns the mutes the user can see, newest first: those in
+// effect by default, those that expired or were cancelled, or all of them.
+func (s *muteService) ListMutes(userID uuid.UUID, status string) ([]dto.MuteResponse, error) {
+	if status == "" {
+		status = MuteActive
+	}
+	if status != MuteActive && status != MuteEnded && status != MuteAll {
+		return nil, errors.NewValidationError("status must be active, ended or all")
+	}
+
+	now := time.Now()
+	var activeAt *time.Time
+	if status == MuteActive {
+		activeAt = &now
+	}
+	mutes, err := s.muteRepo.FindByUserID(userID, activeAt)
+	if err != nil {
+		return nil, errors.ErrDatabaseError
+	}
+
+	responses := make([]dto.MuteResponse, 0, len(mutes))
+	for i := range mutes {
+		if status == MuteEnded && mutes[i].Active(now) {
+			continue
+		}
+		responses = append(responses, *muteResponse(&mutes[i], now))
+	}
+	return responses, nil
+}
+
+// CancelMute ends a mute that is still in effect. Requires the operator
+// role.
+func (s *muteService) CancelMute(userID, muteID uuid.UUID) (*dto.MuteResponse, error) {
+	mute, err := s.muteRepo.FindByID(muteID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.ErrMuteNotFound
+		}
+		return nil, errors.ErrDatabaseError
+	}
+	if err := s.authz.Authorize(userID, Ownership{UserID: mute.UserID, OrganizationID: mute.OrganizationID}, models.RoleOperator); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if !mute.Active(now) {
+		return nil, errors.ErrMuteEnded
+	}
+	if err := s.muteRepo.Cancel(mute.ID, now); err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.ErrMuteEnded
+		}
+		return nil, errors.ErrDatabaseError
+	}
+	mute.CancelledAt = &now
+	return muteResponse(mute, now), nil
+}
+
+// ActiveMutes returns the mutes of the device's owner in effect at now that
+// may cover the device: those scoped to it and those scoped to a rule only.
+func (s *muteService) ActiveMutes(device *models.Device, now time.Time) ([]models.Mute, error) {
+	mutes, err := s.muteRepo.FindActiveForDevice(device.UserID, device.OrganizationID, device.UUID, now)
+	if err != nil {
+		return nil, errors.ErrDatabaseError
+	}
+	return mutes, nil
+}
+
+// CoveringMutes returns the mutes of the device's owner that may cover the
+// device and were in effect at some point between from and to, cancelled
+// ones included. activeMuteAt tells which of them silenced a rule at a
+// given time.
+func (s *muteService) CoveringMutes(device *models.Device, from, to time.Time) ([]models.Mute, error) {
+	mutes, err := s.muteRepo.FindOverlappingForDevice(device.UserID, device.OrganizationID, device.UUID, from, to)
+	if err != nil {
+		return nil, errors.ErrDatabaseError
+	}
+	return mutes, nil
+}
+
+// findMute returns the first of mutes that covers the rule on the device,
+// or nil.
+func findMute(mutes []models.Mute, notificationID, deviceID uuid.UUID) *models.Mute {
+	for i := range mutes {
+		if mutes[i].Covers(notificationID, deviceID) {
+			return &mutes[i]
+		}
+	}
+	return nil
+}
+
+// activeMuteAt returns the first of mutes in effect at t that covers the rule
+// on the device, or nil.
+func activeMuteAt(mutes []models.Mute, notificationID, deviceID uuid.UUID, t time.Time) *models.Mute {
+	for i := range mutes {
+		if mutes[i].ActiveAt(t) && mutes[i].Covers(notificationID, deviceID) {
+			return &mutes[i]
+		}
+	}
+	return nil
+}
+
+func muteResponse(mute *models.Mute, now time.Time) *dto.MuteResponse {
+	return &dto.MuteResponse{
+		ID:             mute.ID,
+		OrganizationID: mute.OrganizationID,
+		NotificationID: mute.NotificationID,
+		DeviceID:       mute.DeviceID,
+		Reason:         mute.Reason,
+		ExpiresAt:      mute.ExpiresAt,
+		CancelledAt:    mute.CancelledAt,
+		Active:         mute.Active(now),
+		CreatedBy:      mute.CreatedBy,
+		CreatedAt:      mute.CreatedAt,
+	}
+}
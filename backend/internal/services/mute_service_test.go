@@ -0,0 +1,266 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/dto"
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/models"
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/utils/errors"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+type MockMuteRepository struct {
+	mock.Mock
+}
+
+func (m *MockMuteRepository) Create(mute *models.Mute) error {
+	args := m.Called(mute)
+	return args.Error(0)
+}
+
+func (m *MockMuteRepository) FindByID(id uuid.UUID) (*models.Mute, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Mute), args.Error(1)
+}
+
+func (m *MockMuteRepository) FindByUserID(userID uuid.UUID, activeAt *time.Time) ([]models.Mute, error) {
+	args := m.Called(userID, activeAt)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Mute), args.Error(1)
+}
+
+func (m *MockMuteRepository) FindActiveForDevice(userID uuid.UUID, organizationID *uuid.UUID, deviceID uuid.UUID, now time.Time) ([]models.Mute, error) {
+	args := m.Called(userID, organizationID, deviceID, now)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Mute), args.Error(1)
+}
+
+func (m *MockMuteRepository) FindOverlappingForDevice(userID uuid.UUID, organizationID *uuid.UUID, deviceID uuid.UUID, from, to time.Time) ([]models.Mute, error) {
+	args := m.Called(userID, organizationID, deviceID, from, to)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Mute), args.Error(1)
+}
+
+func (m *MockMuteRepository) Cancel(id uuid.UUID, at time.Time) error {
+	args := m.Called(id, at)
+	return args.Error(0)
+}
+
+// noMutes returns a mute service for owners without mutes.
+func noMutes() MuteService {
+	muteRepo := new(MockMuteRepository)
+	muteRepo.On("FindActiveForDevice", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return([]models.Mute{}, nil).Maybe()
+	muteRepo.On("FindOverlappingForDevice", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return([]models.Mute{}, nil).Maybe()
+	return NewMuteService(muteRepo, new(MockNotificationRepository), new(MockDeviceRepository), NewAuthorizer(new(MockOrganizationRepository), noDeviceShares()))
+}
+
+func newTestMuteService() (MuteService, *MockMuteRepository, *MockNotificationRepository, *MockDeviceRepository) {
+	muteRepo := new(MockMuteRepository)
+	notificationRepo := new(MockNotificationRepository)
+	deviceRepo := new(MockDeviceRepository)
+	authz := NewAuthorizer(new(MockOrganizationRepository), noDeviceShares())
+	return NewMuteService(muteRepo, notificationRepo, deviceRepo, authz), muteRepo, notificationRepo, deviceRepo
+}
+
+func TestMuteService_CreateMute(t *testing.T) {
+	userID := uuid.New()
+	rule := &models.Notification{ID: uuid.New(), UserID: userID, Name: "High CPU"}
+	device := &models.Device{UUID: uuid.New(), UserID: userID, SN: "123456789012"}
+
+	t.Run("Success - Rule muted on one device", func(t *testing.T) {
+		service, muteRepo, notificationRepo, deviceRepo := newTestMuteService()
+
+		notificationRepo.On("FindByID", rule.ID).Return(rule, nil)
+		deviceRepo.On("FindByID", device.UUID).Return(device, nil)
+		muteRepo.On("Create", mock.MatchedBy(func(m *models.Mute) bool {
+			return m.UserID == userID && m.OrganizationID == nil && *m.NotificationID == rule.ID && *m.DeviceID == device.UUID &&
+				m.CreatedBy == userID && m.Reason == "Replacing the fan" && time.Until(m.ExpiresAt) > 119*time.Minute
+		})).Return(nil)
+
+		mute, err := service.CreateMute(userID, dto.MuteRequest{NotificationID: &rule.ID, DeviceID: &device.UUID, DurationMinutes: 120, Reason: " Replacing the fan "})
+
+		assert.NoError(t, err)
+		assert.True(t, mute.Active)
+		muteRepo.AssertExpectations(t)
+	})
+
+	t.Run("Success - Device of another user muted through a share", func(t *testing.T) {
+		muteRepo := new(MockMuteRepository)
+		deviceRepo := new(MockDeviceRepository)
+		shareRepo := new(MockDeviceShareRepository)
+		service := NewMuteService(muteRepo, new(MockNotificationRepository), deviceRepo, NewAuthorizer(new(MockOrganizationRepository), shareRepo))
+		ownerID := uuid.New()
+		shared := &models.Device{UUID: uuid.New(), UserID: ownerID}
+
+		deviceRepo.On("FindByID", shared.UUID).Return(shared, nil)
+		shareRepo.On("FindActive", shared.UUID, userID, mock.AnythingOfType("time.Time")).Return(&models.DeviceShare{Permission: models.RoleOperator}, nil)
+		muteRepo.On("Create", mock.MatchedBy(func(m *models.Mute) bool {
+			return m.UserID == ownerID && m.CreatedBy == userID && m.NotificationID == nil
+		})).Return(nil)
+
+		_, err := service.CreateMute(userID, dto.MuteRequest{DeviceID: &shared.UUID, DurationMinutes: 30})
+
+		assert.NoError(t, err)
+		muteRepo.AssertExpectations(t)
+	})
+
+	t.Run("Error - No scope", func(t *testing.T) {
+		service, _, _, _ := newTestMuteService()
+
+		_, err := service.CreateMute(userID, dto.MuteRequest{DurationMinutes: 60})
+
+		assert.IsType(t, &errors.BusinessError{}, err)
+	})
+
+	t.Run("Error - Both duration and expiry", func(t *testing.T) {
+		service, _, _, _ := newTestMuteService()
+		expiresAt := time.Now().Add(time.Hour)
+
+		_, err := service.CreateMute(userID, dto.MuteRequest{NotificationID: &rule.ID, DurationMinutes: 60, ExpiresAt: &expiresAt})
+
+		assert.IsType(t, &errors.BusinessError{}, err)
+	})
+
+	t.Run("Error - Longer than the maximum", func(t *testing.T) {
+		service, _, _, _ := newTestMuteService()
+
+		_, err := service.CreateMute(userID, dto.MuteRequest{NotificationID: &rule.ID, DurationMinutes: MaxMuteDurationMinutes + 1})
+
+		assert.IsType(t, &errors.BusinessError{}, err)
+	})
+
+	t.Run("Error - Rule not found", func(t *testing.T) {
+		service, _, notificationRepo, _ := newTestMuteService()
+		notificationRepo.On("FindByID", rule.ID).Return(nil, gorm.ErrRecordNotFound)
+
+		_, err := service.CreateMute(userID, dto.MuteRequest{NotificationID: &rule.ID, DurationMinutes: 60})
+
+		assert.Equal(t, errors.ErrNotificationNotFound, err)
+	})
+
+	t.Run("Error - Rule and device of different owners", func(t *testing.T) {
+		service, muteRepo, notificationRepo, deviceRepo := newTestMuteService()
+		other := &models.Device{UUID: uuid.New(), UserID: uuid.New()}
+
+		notificationRepo.On("FindByID", rule.ID).Return(rule, nil)
+		deviceRepo.On("FindByID", other.UUID).Return(other, nil)
+
+		_, err := service.CreateMute(userID, dto.MuteRequest{NotificationID: &rule.ID, DeviceID: &other.UUID, DurationMinutes: 60})
+
+		assert.Equal(t, errors.ErrDeviceNotFound, err)
+		muteRepo.AssertNotCalled(t, "Create", mock.Anything)
+	})
+
+	t.Run("Error - Rule of another user", func(t *testing.T) {
+		service, muteRepo, notificationRepo, _ := newTestMuteService()
+		foreign := &models.Notification{ID: uuid.New(), UserID: uuid.New()}
+		notificationRepo.On("FindByID", foreign.ID).Return(foreign, nil)
+
+		_, err := service.CreateMute(userID, dto.MuteRequest{NotificationID: &foreign.ID, DurationMinutes: 60})
+
+		assert.Equal(t, errors.ErrForbidden, err)
+		muteRepo.AssertNotCalled(t, "Create", mock.Anything)
+	})
+}
+
+func TestMuteService_ListMutes(t *testing.T) {
+	userID := uuid.New()
+	cancelledAt := time.Now().Add(-time.Minute)
+	active := models.Mute{ID: uuid.New(), UserID: userID, ExpiresAt: time.Now().Add(time.Hour)}
+	cancelled := models.Mute{ID: uuid.New(), UserID: userID, ExpiresAt: time.Now().Add(time.Hour), CancelledAt: &cancelledAt}
+	expired := models.Mute{ID: uuid.New(), UserID: userID, ExpiresAt: time.Now().Add(-time.Hour)}
+
+	t.Run("Success - Active mutes by default", func(t *testing.T) {
+		service, muteRepo, _, _ := newTestMuteService()
+		muteRepo.On("FindByUserID", userID, mock.MatchedBy(func(at *time.Time) bool { return at != nil })).Return([]models.Mute{active}, nil)
+
+		mutes, err := service.ListMutes(userID, "")
+
+		assert.NoError(t, err)
+		assert.Len(t, mutes, 1)
+		assert.True(t, mutes[0].Active)
+	})
+
+	t.Run("Success - Ended mutes", func(t *testing.T) {
+		service, muteRepo, _, _ := newTestMuteService()
+		muteRepo.On("FindByUserID", userID, (*time.Time)(nil)).Return([]models.Mute{active, cancelled, expired}, nil)
+
+		mutes, err := service.ListMutes(userID, MuteEnded)
+
+		assert.NoError(t, err)
+		assert.Len(t, mutes, 2)
+		assert.Equal(t, cancelled.ID, mutes[0].ID)
+		assert.Equal(t, expired.ID, mutes[1].ID)
+	})
+
+	t.Run("Error - Invalid status", func(t *testing.T) {
+		service, _, _, _ := newTestMuteService()
+
+		_, err := service.ListMutes(userID, "paused")
+
+		assert.IsType(t, &errors.BusinessError{}, err)
+	})
+}
+
+func TestMuteService_CancelMute(t *testing.T) {
+	userID := uuid.New()
+
+	t.Run("Success - Mute cancelled", func(t *testing.T) {
+		service, muteRepo, _, _ := newTestMuteService()
+		mute := &models.Mute{ID: uuid.New(), UserID: userID, ExpiresAt: time.Now().Add(time.Hour)}
+
+		muteRepo.On("FindByID", mute.ID).Return(mute, nil)
+		muteRepo.On("Cancel", mute.ID, mock.AnythingOfType("time.Time")).Return(nil)
+
+		response, err := service.CancelMute(userID, mute.ID)
+
+		assert.NoError(t, err)
+		assert.False(t, response.Active)
+		assert.NotNil(t, response.CancelledAt)
+		muteRepo.AssertExpectations(t)
+	})
+
+	t.Run("Error - Mute already expired", func(t *testing.T) {
+		service, muteRepo, _, _ := newTestMuteService()
+		mute := &models.Mute{ID: uuid.New(), UserID: userID, ExpiresAt: time.Now().Add(-time.Hour)}
+		muteRepo.On("FindByID", mute.ID).Return(mute, nil)
+
+		_, err := service.CancelMute(userID, mute.ID)
+
+		assert.Equal(t, errors.ErrMuteEnded, err)
+		muteRepo.AssertNotCalled(t, "Cancel", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Error - Mute of another user", func(t *testing.T) {
+		service, muteRepo, _, _ := newTestMuteService()
+		mute := &models.Mute{ID: uuid.New(), UserID: uuid.New(), ExpiresAt: time.Now().Add(time.Hour)}
+		muteRepo.On("FindByID", mute.ID).Return(mute, nil)
+
+		_, err := service.CancelMute(userID, mute.ID)
+
+		assert.Equal(t, errors.ErrForbidden, err)
+	})
+
+	t.Run("Error - Mute not found", func(t *testing.T) {
+		service, muteRepo, _, _ := newTestMuteService()
+		muteID := uuid.New()
+		muteRepo.On("FindByID", muteID).Return(nil, gorm.ErrRecordNotFound)
+
+		_, err := service.CancelMute(userID, muteID)
+
+		assert.Equal(t, errors.ErrMuteNotFound, err)
+	})
+}
@@ -0,0 +1,648 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/dto"
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/models"
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/repository"
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/utils/errors"
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/utils/logger"
+	"github.com/google/uuid"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+const (
+	// MaxDeliveryAttempts is the number of times a delivery is tried before
+	// it is marked as failed.
+	MaxDeliveryAttempts = 5
+	// DeliveryRetryDelay is the delay before the second attempt; it doubles
+	// after every failed attempt.
+	DeliveryRetryDelay = 30 * time.Second
+	// DeliveryTimeout bounds each attempt.
+	DeliveryTimeout = 15 * time.Second
+	// DeliveryBatchSize is the number of due deliveries sent per run.
+	DeliveryBatchSize = 100
+	// MaxChannelDeliveries is the number of deliveries returned for a
+	// channel.
+	MaxChannelDeliveries = 100
+	// MaxRuleChannels caps the channels of a notification rule.
+	MaxRuleChannels = 10
+	// ChannelFailureLimit is the number of consecutive failed attempts after
+	// which a channel is disabled.
+	ChannelFailureLimit = 15
+	// WebhookSecretGracePeriod is how long the previous secret of a webhook
+	// keeps signing payloads after a rotation.
+	WebhookSecretGracePeriod = 24 * time.Hour
+)
+
+// ChannelSender delivers alerts to one type of notification channel. config
+// is the channel's config as stored. Failed sends are retried unless the
+// error has a Retryable method that returns false.
+type ChannelSender interface {
+	Validate(config []byte) error
+	Send(ctx context.Context, config []byte, msg dto.ChannelMessage) error
+}
+
+type NotificationChannelService interface {
+	CreateChannel(userID uuid.UUID, orgID *uuid.UUID, req dto.NotificationChannelRequest) (*dto.NotificationChannelResponse, error)
+	ListChannels(userID uuid.UUID) ([]dto.NotificationChannelResponse, error)
+	GetChannel(userID, channelID uuid.UUID) (*dto.NotificationChannelResponse, error)
+	UpdateChannel(userID, channelID uuid.UUID, req dto.NotificationChannelRequest) (*dto.NotificationChannelResponse, error)
+	DeleteChannel(userID, channelID uuid.UUID) error
+	TestChannel(userID, channelID uuid.UUID) (*dto.NotificationDeliveryResponse, error)
+	ListDeliveries(userID, channelID uuid.UUID) ([]dto.NotificationDeliveryResponse, error)
+	ResolveChannels(owner Ownership, channelIDs []uuid.UUID) ([]uuid.UUID, error)
+	Enqueue(notification models.Notification, alert dto.NotificationAlert) error
+	ProcessDeliveries() (delivered, failed int, err error)
+}
+
+type notificationChannelService struct {
+	channelRepo repository.NotificationChannelRepository
+	senders     map[string]ChannelSender
+	authz       Authorizer
+}
+
+// NewNotificationChannelService creates the service with a sender per
+// channel type. Types without a sender, such as email when no SMTP server
+// is configured, cannot be used.
+func NewNotificationChannelService(channelRepo repository.NotificationChannelRepository, senders map[string]ChannelSender, authz Authorizer) NotificationChannelService {
+	return &notificationChannelService{
+		channelRepo: channelRepo,
+		senders:     senders,
+		authz:       authz,
+	}
+}
+
+// CreateChannel creates a personal channel, or an organization channel when
+// orgID is set. Like notification rules it requires the operator role.
+// Webhook channels get a signing secret, returned only in this response.
+func (s *notificationChannelService) CreateChannel(userID uuid.UUID, orgID *uuid.UUID, req dto.NotificationChannelRequest) (*dto.NotificationChannelResponse, error) {
+	channel, err := s.createChannel(userID, orgID, req)
+	if err != nil {
+		return nil, err
+	}
+
+	response := notificationChannelResponse(channel)
+	response.Secret = channel.Secret
+	return response, nil
+}
+
+func (s *notificationChannelService) createChannel(userID uuid.UUID, orgID *uuid.UUID, req dto.NotificationChannelRequest) (*models.NotificationChannel, error) {
+	if err := s.authz.Authorize(userID, Ownership{UserID: userID, OrganizationID: orgID}, models.RoleOperator); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	channel := &models.NotificationChannel{
+		ID:             uuid.New(),
+		UserID:         userID,
+		OrganizationID: orgID,
+		Enabled:        true,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+	if err := s.apply(channel, req); err != nil {
+		return nil, err
+	}
+	if err := ensureSecret(channel); err != nil {
+		return nil, err
+	}
+
+	if err := s.channelRepo.Create(channel); err != nil {
+		return nil, errors.ErrDatabaseError
+	}
+	return channel, nil
+}
+
+func (s *notificationChannelService) ListChannels(userID uuid.UUID) ([]dto.NotificationChannelResponse, error) {
+	channels, err := s.channelRepo.FindByUserID(userID)
+	if err != nil {
+		return nil, errors.ErrDatabaseError
+	}
+
+	responses := make([]dto.NotificationChannelResponse, 0, len(channels))
+	for i := range channels {
+		responses = append(responses, *notificationChannelResponse(&channels[i]))
+	}
+	return responses, nil
+}
+
+func (s *notificationChannelService) GetChannel(userID, channelID uuid.UUID) (*dto.NotificationChannelResponse, error) {
+	channel, err := s.findChannel(userID, channelID, models.RoleViewer)
+	if err != nil {
+		return nil, err
+	}
+	return notificationChannelResponse(channel), nil
+}
+
+// UpdateChannel replaces the name, type and config of the channel, and its
+// enabled flag when given. A channel turned into a webhook gets a signing
+// secret, returned only in this response. Requires the operator role.
+func (s *notificationChannelService) UpdateChannel(userID, channelID uuid.UUID, req dto.NotificationChannelRequest) (*dto.NotificationChannelResponse, error) {
+	channel, err := s.findChannel(userID, channelID, models.RoleOperator)
+	if err != nil {
+		return nil, err
+	}
+
+	hadSecret := channel.Secret != ""
+	if err := s.updateChannel(channel, req); err != nil {
+		return nil, err
+	}
+
+	response := notificationChannelResponse(channel)
+	if !hadSecret {
+		response.Secret = channel.Secret
+	}
+	return response, nil
+}
+
+func (s *notificationChannelService) updateChannel(channel *models.NotificationChannel, req dto.NotificationChannelRequest) error {
+	if err := s.apply(channel, req); err != nil {
+		return err
+	}
+	if err := ensureSecret(channel); err != nil {
+		return err
+	}
+	channel.UpdatedAt = time.Now()
+
+	if err := s.channelRepo.Update(channel); err != nil {
+		return errors.ErrDatabaseError
+	}
+	return nil
+}
+
+// DeleteChannel removes the channel with its deliveries. Rules still listing
+// it simply stop delivering to it. Requires the operator role.
+func (s *notificationChannelService) DeleteChannel(userID, channelID uuid.UUID) error {
+	channel, err := s.findChannel(userID, channelID, models.RoleOperator)
+	if err != nil {
+		return err
+	}
+
+	if err := s.channelRepo.Delete(channel.ID); err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return errors.ErrNotificationChannelNotFound
+		}
+		return errors.ErrDatabaseError
+	}
+	return nil
+}
+
+// TestChannel sends a sample alert to the channel right away, even when it
+// is disabled, and returns the outcome. Test messages are not retried.
+// Requires the operator role.
+func (s *notificationChannelService) TestChannel(userID, channelID uuid.UUID) (*dto.NotificationDeliveryResponse, error) {
+	channel, err := s.findChannel(userID, channelID, models.RoleOperator)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	payload, err := json.Marshal(dto.NotificationAlert{
+		UserID:      userID,
+		Name:        "Test notification",
+		Description: fmt.Sprintf("Test message for the %q channel", channel.Name),
+		DeviceSN:    "TEST",
+		Timestamp:   now.Format(time.RFC3339),
+	})
+	if err != nil {
+		return nil, errors.ErrDatabaseError
+	}
+
+	delivery := models.NotificationDelivery{
+		ID:            uuid.New(),
+		ChannelID:     channel.ID,
+		Payload:       datatypes.JSON(payload),
+		Status:        models.DeliveryPending,
+		NextAttemptAt: now,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+	if err := s.channelRepo.CreateDeliveries([]models.NotificationDelivery{delivery}); err != nil {
+		return nil, errors.ErrDatabaseError
+	}
+
+	attempt, err := s.deliver(&delivery, channel, false)
+	if err != nil {
+		return nil, err
+	}
+	return notificationDeliveryResponse(&delivery, []models.DeliveryAttempt{*attempt}), nil
+}
+
+// ListDeliveries returns the latest deliveries of the channel with their
+// attempts, newest first.
+func (s *notificationChannelService) ListDeliveries(userID, channelID uuid.UUID) ([]dto.NotificationDeliveryResponse, error) {
+	channel, err := s.findChannel(userID, channelID, models.RoleViewer)
+	if err != nil {
+		return nil, err
+	}
+	return s.listDeliveries(channel)
+}
+
+func (s *notificationChannelService) listDeliveries(channel *models.NotificationChannel) ([]dto.NotificationDeliveryResponse, error) {
+	deliveries, err := s.channelRepo.FindDeliveries(channel.ID, MaxChannelDeliveries)
+	if err != nil {
+		return nil, errors.ErrDatabaseError
+	}
+
+	deliveryIDs := make([]uuid.UUID, 0, len(deliveries))
+	for _, delivery := range deliveries {
+		deliveryIDs = append(deliveryIDs, delivery.ID)
+	}
+	attempts, err := s.channelRepo.FindAttempts(deliveryIDs)
+	if err != nil {
+		return nil, errors.ErrDatabaseError
+	}
+	attemptsByDelivery := make(map[uuid.UUID][]models.DeliveryAttempt, len(deliveries))
+	for _, attempt := range attempts {
+		attemptsByDelivery[attempt.DeliveryID] = append(attemptsByDelivery[attempt.DeliveryID], attempt)
+	}
+
+	responses := make([]dto.NotificationDeliveryResponse, 0, len(deliveries))
+	for i := range deliveries {
+		responses = append(responses, *notificationDeliveryResponse(&deliveries[i], attemptsByDelivery[deliveries[i].ID]))
+	}
+	return responses, nil
+}
+
+// ResolveChannels checks that the channels exist and belong to owner, and
+// returns them without duplicates.
+func (s *notificationChannelService) ResolveChannels(owner Ownership, channelIDs []uuid.UUID) ([]uuid.UUID, error) {
+	channelIDs = uniqueIDs(channelIDs)
+	if len(channelIDs) == 0 {
+		return channelIDs, nil
+	}
+	if len(channelIDs) > MaxRuleChannels {
+		return nil, errors.NewValidationError(fmt.Sprintf("A rule can deliver to at most %d channels", MaxRuleChannels))
+	}
+
+	channels, err := s.channelRepo.FindByIDs(channelIDs)
+	if err != nil {
+		return nil, errors.ErrDatabaseError
+	}
+	found := make(map[uuid.UUID]bool, len(channels))
+	for i := range channels {
+		if channelOwnership(&channels[i]).SameOwner(owner) {
+			found[channels[i].ID] = true
+		}
+	}
+	for _, id := range channelIDs {
+		if !found[id] {
+			return nil, errors.ErrNotificationChannelNotFound
+		}
+	}
+	return channelIDs, nil
+}
+
+// Enqueue queues the alert for every enabled channel of the rule whose
+// minimum severity it meets; messages without a severity, such as digests,
+// go to all of them. The deliveries are sent by ProcessDeliveries.
+func (s *notificationChannelService) Enqueue(notification models.Notification, alert dto.NotificationAlert) error {
+	if len(notification.ChannelIDs) == 0 {
+		return nil
+	}
+
+	channels, err := s.channelRepo.FindByIDs(notification.ChannelIDs)
+	if err != nil {
+		return errors.ErrDatabaseError
+	}
+	payload, err := json.Marshal(alert)
+	if err != nil {
+		return errors.ErrDatabaseError
+	}
+
+	owner := Ownership{UserID: notification.UserID, OrganizationID: notification.OrganizationID}
+	now := time.Now()
+	deliveries := make([]models.NotificationDelivery, 0, len(channels))
+	for i := range channels {
+		channel := &channels[i]
+		if !channel.Enabled || !channelOwnership(channel).SameOwner(owner) || (alert.Severity != "" && !models.SeverityAtLeast(alert.Severity, channel.MinSeverity)) {
+			continue
+		}
+		delivery := models.NotificationDelivery{
+			ID:            uuid.New(),
+			ChannelID:     channel.ID,
+			Payload:       datatypes.JSON(payload),
+			Status:        models.DeliveryPending,
+			NextAttemptAt: now,
+			CreatedAt:     now,
+			UpdatedAt:     now,
+		}
+		// Digests have neither a rule nor a device.
+		if notification.ID != uuid.Nil {
+			notificationID := notification.ID
+			delivery.NotificationID = &notificationID
+		}
+		if alert.DeviceID != uuid.Nil {
+			deviceID := alert.DeviceID
+			delivery.DeviceID = &deviceID
+		}
+		deliveries = append(deliveries, delivery)
+	}
+
+	if err := s.channelRepo.CreateDeliveries(deliveries); err != nil {
+		return errors.ErrDatabaseError
+	}
+	return nil
+}
+
+// ProcessDeliveries sends the due deliveries. It returns how many were
+// delivered and how many failed for good; the others are retried later.
+func (s *notificationChannelService) ProcessDeliveries() (delivered, failed int, err error) {
+	deliveries, err := s.channelRepo.FindDueDeliveries(time.Now(), DeliveryBatchSize)
+	if err != nil {
+		return 0, 0, errors.ErrDatabaseError
+	}
+	if len(deliveries) == 0 {
+		return 0, 0, nil
+	}
+
+	channelIDs := make([]uuid.UUID, 0, len(deliveries))
+	for _, delivery := range deliveries {
+		channelIDs = append(channelIDs, delivery.ChannelID)
+	}
+	channels, err := s.channelRepo.FindByIDs(uniqueIDs(channelIDs))
+	if err != nil {
+		return 0, 0, errors.ErrDatabaseError
+	}
+	channelsByID := make(map[uuid.UUID]*models.NotificationChannel, len(channels))
+	for i := range channels {
+		channelsByID[channels[i].ID] = &channels[i]
+	}
+
+	for i := range deliveries {
+		delivery := &deliveries[i]
+		channel := channelsByID[delivery.ChannelID]
+		if channel == nil || !channel.Enabled {
+			// The channel was disabled after the alert was queued.
+			delivery.Status = models.DeliveryFailed
+			delivery.LastError = "channel is disabled"
+			delivery.UpdatedAt = time.Now()
+			if err := s.channelRepo.UpdateDelivery(delivery); err != nil {
+				return delivered, failed, errors.ErrDatabaseError
+			}
+			failed++
+			continue
+		}
+
+		if _, err := s.deliver(delivery, channel, true); err != nil {
+			return delivered, failed, err
+		}
+		switch delivery.Status {
+		case models.DeliveryDelivered:
+			delivered++
+		case models.DeliveryFailed:
+			failed++
+		}
+	}
+	return delivered, failed, nil
+}
+
+// redeliver sends a delivery of the channel again right away, whatever its
+// status. It is not retried when it fails.
+func (s *notificationChannelService) redeliver(channel *models.NotificationChannel, deliveryID uuid.UUID) (*dto.NotificationDeliveryResponse, error) {
+	delivery, err := s.channelRepo.FindDeliveryByID(deliveryID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.ErrNotificationDeliveryNotFound
+		}
+		return nil, errors.ErrDatabaseError
+	}
+	if delivery.ChannelID != channel.ID {
+		return nil, errors.ErrNotificationDeliveryNotFound
+	}
+
+	delivery.Status = models.DeliveryPending
+	if _, err := s.deliver(delivery, channel, false); err != nil {
+		return nil, err
+	}
+
+	attempts, err := s.channelRepo.FindAttempts([]uuid.UUID{delivery.ID})
+	if err != nil {
+		return nil, errors.ErrDatabaseError
+	}
+	return notificationDeliveryResponse(delivery, attempts), nil
+}
+
+// deliver makes one attempt to send the delivery and logs it. On failure the
+// delivery is rescheduled with exponential backoff while retry is set, the
+// error is retryable and attempts are left, and marked as failed otherwise.
+// The channel is disabled once it reaches ChannelFailureLimit consecutive
+// failed attempts.
+func (s *notificationChannelService) deliver(delivery *models.NotificationDelivery, channel *models.NotificationChannel, retry bool) (*models.DeliveryAttempt, error) {
+	start := time.Now()
+	sendErr := s.send(channel, delivery)
+	now := time.Now()
+
+	delivery.Attempts++
+	attempt := &models.DeliveryAttempt{
+		ID:         uuid.New(),
+		DeliveryID: delivery.ID,
+		Attempt:    delivery.Attempts,
+		Success:    sendErr == nil,
+		DurationMs: now.Sub(start).Milliseconds(),
+		CreatedAt:  now,
+	}
+	if sendErr != nil {
+		attempt.Error = sendErr.Error()
+	}
+	if err := s.channelRepo.CreateAttempt(attempt); err != nil {
+		return nil, errors.ErrDatabaseError
+	}
+
+	delivery.UpdatedAt = now
+	if sendErr == nil {
+		delivery.Status = models.DeliveryDelivered
+		delivery.DeliveredAt = &now
+		delivery.LastError = ""
+	} else {
+		delivery.LastError = sendErr.Error()
+		if retry && retryable(sendErr) && delivery.Attempts < MaxDeliveryAttempts {
+			delivery.NextAttemptAt = now.Add(DeliveryRetryDelay << (delivery.Attempts - 1))
+		} else {
+			delivery.Status = models.DeliveryFailed
+		}
+	}
+	if err := s.channelRepo.UpdateDelivery(delivery); err != nil {
+		return nil, errors.ErrDatabaseError
+	}
+
+	var reason string
+	if sendErr != nil {
+		reason = fmt.Sprintf("Disabled after %d consecutive failed attempts, last error: %s", ChannelFailureLimit, delivery.LastError)
+	}
+	disabled, err := s.channelRepo.RecordDeliveryResult(channel.ID, sendErr == nil, ChannelFailureLimit, reason, now)
+	if err != nil {
+		return nil, errors.ErrDatabaseError
+	}
+	if sendErr == nil {
+		channel.ConsecutiveFailures = 0
+	} else {
+		channel.ConsecutiveFailures++
+	}
+	if disabled {
+		channel.Enabled = false
+		channel.DisabledAt = &now
+		channel.DisabledReason = reason
+		logger.Logger.Warn("Notification channel disabled after repeated failures", "channel_id", channel.ID.String(), "last_error", delivery.LastError)
+	}
+	return attempt, nil
+}
+
+func (s *notificationChannelService) send(channel *models.NotificationChannel, delivery *models.NotificationDelivery) error {
+	sender, ok := s.senders[channel.Type]
+	if !ok {
+		return fmt.Errorf("%s channels are not configured on this server", channel.Type)
+	}
+
+	msg := dto.ChannelMessage{DeliveryID: delivery.ID, Secrets: channel.SigningSecrets(time.Now())}
+	if err := json.Unmarshal(delivery.Payload, &msg.Alert); err != nil {
+		return fmt.Errorf("invalid payload: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), DeliveryTimeout)
+	defer cancel()
+	return sender.Send(ctx, channel.Config, msg)
+}
+
+// retryable reports whether a failed send may succeed later.
+func retryable(err error) bool {
+	if r, ok := err.(interface{ Retryable() bool }); ok {
+		return r.Retryable()
+	}
+	return true
+}
+
+// apply validates the request and sets it on the channel.
+func (s *notificationChannelService) apply(channel *models.NotificationChannel, req dto.NotificationChannelRequest) error {
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return errors.NewValidationError("Channel name is required")
+	}
+
+	channelType := strings.ToLower(strings.TrimSpace(req.Type))
+	if !isValidChannelType(channelType) {
+		return errors.NewValidationError("Invalid channel type: " + req.Type + " (must be webhook, email, slack or teams)")
+	}
+	sender, ok := s.senders[channelType]
+	if !ok {
+		return errors.NewValidationError(channelType + " channels are not configured on this server")
+	}
+	if err := sender.Validate(req.Config); err != nil {
+		return errors.NewValidationError("Invalid " + channelType + " config: " + err.Error())
+	}
+	minSeverity := strings.ToLower(strings.TrimSpace(req.MinSeverity))
+	if minSeverity != "" && !models.IsValidSeverity(minSeverity) {
+		return errors.NewValidationError("Invalid min_severity: " + req.MinSeverity + " (must be info, warning or critical)")
+	}
+
+	channel.Name = name
+	channel.Type = channelType
+	channel.Config = datatypes.JSON(req.Config)
+	channel.MinSeverity = minSeverity
+	if req.Enabled != nil {
+		if *req.Enabled && !channel.Enabled {
+			channel.ConsecutiveFailures = 0
+			channel.DisabledAt = nil
+			channel.DisabledReason = ""
+		}
+		channel.Enabled = *req.Enabled
+	}
+	return nil
+}
+
+// ensureSecret gives a webhook channel its signing secret.
+func ensureSecret(channel *models.NotificationChannel) error {
+	if channel.Type != models.ChannelWebhook || channel.Secret != "" {
+		return nil
+	}
+	secret, err := newWebhookSecret()
+	if err != nil {
+		return errors.ErrDatabaseError
+	}
+	channel.Secret = secret
+	return nil
+}
+
+func newWebhookSecret() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return "whsec_" + hex.EncodeToString(buf), nil
+}
+
+func (s *notificationChannelService) findChannel(userID, channelID uuid.UUID, role string) (*models.NotificationChannel, error) {
+	channel, err := s.channelRepo.FindByID(channelID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.ErrNotificationChannelNotFound
+		}
+		return nil, errors.ErrDatabaseError
+	}
+	if err := s.authz.Authorize(userID, channelOwnership(channel), role); err != nil {
+		return nil, err
+	}
+	return channel, nil
+}
+
+func isValidChannelType(channelType string) bool {
+	switch channelType {
+	case models.ChannelWebhook, models.ChannelEmail, models.ChannelSlack, models.ChannelTeams:
+		return true
+	}
+	return false
+}
+
+func notificationChannelResponse(channel *models.NotificationChannel) *dto.NotificationChannelResponse {
+	return &dto.NotificationChannelResponse{
+		ID:                  channel.ID,
+		OrganizationID:      channel.OrganizationID,
+		Name:                channel.Name,
+		Type:                channel.Type,
+		Config:              json.RawMessage(channel.Config),
+		Enabled:             channel.Enabled,
+		MinSeverity:         channel.MinSeverity,
+		ConsecutiveFailures: channel.ConsecutiveFailures,
+		DisabledAt:          channel.DisabledAt,
+		DisabledReason:      channel.DisabledReason,
+		CreatedAt:           channel.CreatedAt,
+		UpdatedAt:           channel.UpdatedAt,
+	}
+}
+
+func notificationDeliveryResponse(delivery *models.NotificationDelivery, attempts []models.DeliveryAttempt) *dto.NotificationDeliveryResponse {
+	response := &dto.NotificationDeliveryResponse{
+		ID:             delivery.ID,
+		ChannelID:      delivery.ChannelID,
+		NotificationID: delivery.NotificationID,
+		DeviceID:       delivery.DeviceID,
+		Status:         delivery.Status,
+		Attempts:       make([]dto.DeliveryAttemptResponse, 0, len(attempts)),
+		LastError:      delivery.LastError,
+		DeliveredAt:    delivery.DeliveredAt,
+		CreatedAt:      delivery.CreatedAt,
+	}
+	if delivery.Status == models.DeliveryPending {
+		nextAttemptAt := delivery.NextAttemptAt
+		response.NextAttemptAt = &nextAttemptAt
+	}
+	for _, attempt := range attempts {
+		response.Attempts = append(response.Attempts, dto.DeliveryAttemptResponse{
+			Attempt:    attempt.Attempt,
+			Success:    attempt.Success,
+			Error:      attempt.Error,
+			DurationMs: attempt.DurationMs,
+			CreatedAt:  attempt.CreatedAt,
+		})
+	}
+	return response
+}
@@ -0,0 +1,424 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/dto"
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/models"
+	custom_errors "github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/utils/errors"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+type MockNotificationChannelRepository struct {
+	mock.Mock
+}
+
+func (m *MockNotificationChannelRepository) Create(channel *models.NotificationChannel) error {
+	args := m.Called(channel)
+	return args.Error(0)
+}
+
+func (m *MockNotificationChannelRepository) FindByID(id uuid.UUID) (*models.NotificationChannel, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.NotificationChannel), args.Error(1)
+}
+
+func (m *MockNotificationChannelRepository) FindByIDs(ids []uuid.UUID) ([]models.NotificationChannel, error) {
+	args := m.Called(ids)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.NotificationChannel), args.Error(1)
+}
+
+func (m *MockNotificationChannelRepository) FindByUserID(userID uuid.UUID) ([]models.NotificationChannel, error) {
+	args := m.Called(userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.NotificationChannel), args.Error(1)
+}
+
+func (m *MockNotificationChannelRepository) Update(channel *models.NotificationChannel) error {
+	args := m.Called(channel)
+	return args.Error(0)
+}
+
+func (m *MockNotificationChannelRepository) Delete(id uuid.UUID) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MockNotificationChannelRepository) CreateDeliveries(deliveries []models.NotificationDelivery) error {
+	args := m.Called(deliveries)
+	return args.Error(0)
+}
+
+func (m *MockNotificationChannelRepository) FindDueDeliveries(now time.Time, limit int) ([]models.NotificationDelivery, error) {
+	args := m.Called(now, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.NotificationDelivery), args.Error(1)
+}
+
+func (m *MockNotificationChannelRepository) FindDeliveries(channelID uuid.UUID, limit int) ([]models.NotificationDelivery, error) {
+	args := m.Called(channelID, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.NotificationDelivery), args.Error(1)
+}
+
+func (m *MockNotificationChannelRepository) FindDeliveryByID(id uuid.UUID) (*models.NotificationDelivery, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.NotificationDelivery), args.Error(1)
+}
+
+func (m *MockNotificationChannelRepository) RecordDeliveryResult(channelID uuid.UUID, success bool, failureLimit int, disabledReason string, now time.Time) (bool, error) {
+	args := m.Called(channelID, success, failureLimit, disabledReason, now)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockNotificationChannelRepository) UpdateDelivery(delivery *models.NotificationDelivery) error {
+	args := m.Called(delivery)
+	return args.Error(0)
+}
+
+func (m *MockNotificationChannelRepository) CreateAttempt(attempt *models.DeliveryAttempt) error {
+	args := m.Called(attempt)
+	return args.Error(0)
+}
+
+func (m *MockNotificationChannelRepository) FindAttempts(deliveryIDs []uuid.UUID) ([]models.DeliveryAttempt, error) {
+	args := m.Called(deliveryIDs)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.DeliveryAttempt), args.Error(1)
+}
+
+// stubSender records the messages it is asked to send and fails with err.
+type stubSender struct {
+	err  error
+	sent []dto.ChannelMessage
+}
+
+func (s *stubSender) Validate(config []byte) error {
+	var cfg map[string]interface{}
+	if err := json.Unmarshal(config, &cfg); err != nil || cfg["url"] == nil {
+		return errors.New("url is required")
+	}
+	return nil
+}
+
+func (s *stubSender) Send(ctx context.Context, config []byte, msg dto.ChannelMessage) error {
+	s.sent = append(s.sent, msg)
+	return s.err
+}
+
+// permanentError is a send error that is not worth retrying.
+type permanentError struct{}
+
+func (permanentError) Error() string   { return "unexpected status 410" }
+func (permanentError) Retryable() bool { return false }
+
+// noNotificationChannels is the channel service of tests whose rules have no
+// channels.
+func noNotificationChannels() NotificationChannelService {
+	return NewNotificationChannelService(new(MockNotificationChannelRepository), map[string]ChannelSender{}, NewAuthorizer(new(MockOrganizationRepository), noDeviceShares()))
+}
+
+func newTestNotificationChannelService() (NotificationChannelService, *MockNotificationChannelRepository, *stubSender) {
+	channelRepo := new(MockNotificationChannelRepository)
+	sender := &stubSender{}
+	authz := NewAuthorizer(new(MockOrganizationRepository), noDeviceShares())
+	return NewNotificationChannelService(channelRepo, map[string]ChannelSender{models.ChannelWebhook: sender}, authz), channelRepo, sender
+}
+
+func TestNotificationChannelService_CreateChannel(t *testing.T) {
+	userID := uuid.New()
+
+	t.Run("Success - Enabled by default", func(t *testing.T) {
+		service, channelRepo, _ := newTestNotificationChannelService()
+		channelRepo.On("Create", mock.AnythingOfType("*models.NotificationChannel")).Return(nil)
+
+		channel, err := service.CreateChannel(userID, nil, dto.NotificationChannelRequest{
+			Name:   " On-call ",
+			Type:   "Webhook",
+			Config: json.RawMessage(`{"url": "https://example.com/hook"}`),
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "On-call", channel.Name)
+		assert.Equal(t, models.ChannelWebhook, channel.Type)
+		assert.True(t, channel.Enabled)
+	})
+
+	t.Run("Error - Invalid config", func(t *testing.T) {
+		service, channelRepo, _ := newTestNotificationChannelService()
+
+		channel, err := service.CreateChannel(userID, nil, dto.NotificationChannelRequest{Name: "On-call", Type: "webhook", Config: json.RawMessage(`{}`)})
+
+		assert.Nil(t, channel)
+		assert.ErrorContains(t, err, "url is required")
+		channelRepo.AssertNotCalled(t, "Create", mock.Anything)
+	})
+
+	t.Run("Error - Type not configured", func(t *testing.T) {
+		service, _, _ := newTestNotificationChannelService()
+
+		channel, err := service.CreateChannel(userID, nil, dto.NotificationChannelRequest{Name: "Ops", Type: "email", Config: json.RawMessage(`{"to": ["ops@example.com"]}`)})
+
+		assert.Nil(t, channel)
+		assert.ErrorContains(t, err, "not configured")
+	})
+
+	t.Run("Error - Unknown type", func(t *testing.T) {
+		service, _, _ := newTestNotificationChannelService()
+
+		channel, err := service.CreateChannel(userID, nil, dto.NotificationChannelRequest{Name: "Ops", Type: "sms", Config: json.RawMessage(`{}`)})
+
+		assert.Nil(t, channel)
+		assert.ErrorContains(t, err, "Invalid channel type")
+	})
+}
+
+func TestNotificationChannelService_DeleteChannel(t *testing.T) {
+	userID := uuid.New()
+	channel := &models.NotificationChannel{ID: uuid.New(), UserID: userID}
+
+	t.Run("Success - Delete own channel", func(t *testing.T) {
+		service, channelRepo, _ := newTestNotificationChannelService()
+		channelRepo.On("FindByID", channel.ID).Return(channel, nil)
+		channelRepo.On("Delete", channel.ID).Return(nil)
+
+		err := service.DeleteChannel(userID, channel.ID)
+
+		assert.NoError(t, err)
+		channelRepo.AssertExpectations(t)
+	})
+
+	t.Run("Error - Another user's channel", func(t *testing.T) {
+		service, channelRepo, _ := newTestNotificationChannelService()
+		channelRepo.On("FindByID", channel.ID).Return(channel, nil)
+
+		err := service.DeleteChannel(uuid.New(), channel.ID)
+
+		assert.Equal(t, custom_errors.ErrForbidden, err)
+		channelRepo.AssertNotCalled(t, "Delete", mock.Anything)
+	})
+
+	t.Run("Error - Channel not found", func(t *testing.T) {
+		service, channelRepo, _ := newTestNotificationChannelService()
+		channelRepo.On("FindByID", channel.ID).Return(nil, gorm.ErrRecordNotFound)
+
+		err := service.DeleteChannel(userID, channel.ID)
+
+		assert.Equal(t, custom_errors.ErrNotificationChannelNotFound, err)
+	})
+}
+
+func TestNotificationChannelService_Enqueue(t *testing.T) {
+	userID := uuid.New()
+	enabled := models.NotificationChannel{ID: uuid.New(), UserID: userID, Type: models.ChannelWebhook, Enabled: true}
+	disabled := models.NotificationChannel{ID: uuid.New(), UserID: userID, Type: models.ChannelWebhook}
+	notification := models.Notification{ID: uuid.New(), UserID: userID, ChannelIDs: []uuid.UUID{enabled.ID, disabled.ID}}
+
+	t.Run("Success - Skips disabled channels", func(t *testing.T) {
+		service, channelRepo, _ := newTestNotificationChannelService()
+		channelRepo.On("FindByIDs", []uuid.UUID(notification.ChannelIDs)).Return([]models.NotificationChannel{enabled, disabled}, nil)
+		channelRepo.On("CreateDeliveries", mock.MatchedBy(func(deliveries []models.NotificationDelivery) bool {
+			return len(deliveries) == 1 && deliveries[0].ChannelID == enabled.ID && deliveries[0].Status == models.DeliveryPending
+		})).Return(nil)
+
+		err := service.Enqueue(notification, dto.NotificationAlert{ID: notification.ID, DeviceID: uuid.New()})
+
+		assert.NoError(t, err)
+		channelRepo.AssertExpectations(t)
+	})
+
+	t.Run("Success - Skips channels above the alert severity", func(t *testing.T) {
+		service, channelRepo, _ := newTestNotificationChannelService()
+		email := models.NotificationChannel{ID: uuid.New(), UserID: userID, Type: models.ChannelEmail, Enabled: true, MinSeverity: models.SeverityCritical}
+		rule := models.Notification{ID: uuid.New(), UserID: userID, ChannelIDs: []uuid.UUID{enabled.ID, email.ID}}
+		channelRepo.On("FindByIDs", []uuid.UUID(rule.ChannelIDs)).Return([]models.NotificationChannel{enabled, email}, nil)
+		channelRepo.On("CreateDeliveries", mock.MatchedBy(func(deliveries []models.NotificationDelivery) bool {
+			return len(deliveries) == 1 && deliveries[0].ChannelID == enabled.ID
+		})).Return(nil)
+
+		err := service.Enqueue(rule, dto.NotificationAlert{ID: rule.ID, DeviceID: uuid.New(), Severity: models.SeverityWarning})
+
+		assert.NoError(t, err)
+		channelRepo.AssertExpectations(t)
+	})
+}
+
+func TestNotificationChannelService_ProcessDeliveries(t *testing.T) {
+	channel := models.NotificationChannel{ID: uuid.New(), Type: models.ChannelWebhook, Enabled: true}
+	payload, _ := json.Marshal(dto.NotificationAlert{Name: "High CPU Alert", DeviceSN: "SN123"})
+	newDelivery := func(attempts int) models.NotificationDelivery {
+		return models.NotificationDelivery{ID: uuid.New(), ChannelID: channel.ID, Payload: payload, Status: models.DeliveryPending, Attempts: attempts}
+	}
+
+	t.Run("Success - Delivered", func(t *testing.T) {
+		service, channelRepo, sender := newTestNotificationChannelService()
+		delivery := newDelivery(0)
+		channelRepo.On("FindDueDeliveries", mock.AnythingOfType("time.Time"), DeliveryBatchSize).Return([]models.NotificationDelivery{delivery}, nil)
+		channelRepo.On("FindByIDs", []uuid.UUID{channel.ID}).Return([]models.NotificationChannel{channel}, nil)
+		channelRepo.On("CreateAttempt", mock.MatchedBy(func(attempt *models.DeliveryAttempt) bool {
+			return attempt.Attempt == 1 && attempt.Success
+		})).Return(nil)
+		channelRepo.On("UpdateDelivery", mock.MatchedBy(func(d *models.NotificationDelivery) bool {
+			return d.Status == models.DeliveryDelivered && d.DeliveredAt != nil
+		})).Return(nil)
+		channelRepo.On("RecordDeliveryResult", channel.ID, true, ChannelFailureLimit, "", mock.AnythingOfType("time.Time")).Return(false, nil)
+
+		delivered, failed, err := service.ProcessDeliveries()
+
+		assert.NoError(t, err)
+		assert.Equal(t, 1, delivered)
+		assert.Equal(t, 0, failed)
+		assert.Equal(t, "SN123", sender.sent[0].Alert.DeviceSN)
+		assert.Equal(t, delivery.ID, sender.sent[0].DeliveryID)
+		channelRepo.AssertExpectations(t)
+	})
+
+	t.Run("Success - Failure is retried with backoff", func(t *testing.T) {
+		service, channelRepo, sender := newTestNotificationChannelService()
+		sender.err = errors.New("unexpected status 502")
+		delivery := newDelivery(2)
+		channelRepo.On("FindDueDeliveries", mock.AnythingOfType("time.Time"), DeliveryBatchSize).Return([]models.NotificationDelivery{delivery}, nil)
+		channelRepo.On("FindByIDs", []uuid.UUID{channel.ID}).Return([]models.NotificationChannel{channel}, nil)
+		channelRepo.On("CreateAttempt", mock.MatchedBy(func(attempt *models.DeliveryAttempt) bool {
+			return attempt.Attempt == 3 && !attempt.Success && attempt.Error == "unexpected status 502"
+		})).Return(nil)
+		channelRepo.On("UpdateDelivery", mock.MatchedBy(func(d *models.NotificationDelivery) bool {
+			delay := time.Until(d.NextAttemptAt)
+			return d.Status == models.DeliveryPending && delay > 3*DeliveryRetryDelay && delay <= 4*DeliveryRetryDelay
+		})).Return(nil)
+		channelRepo.On("RecordDeliveryResult", channel.ID, false, ChannelFailureLimit, mock.Anything, mock.AnythingOfType("time.Time")).Return(false, nil)
+
+		delivered, failed, err := service.ProcessDeliveries()
+
+		assert.NoError(t, err)
+		assert.Equal(t, 0, delivered)
+		assert.Equal(t, 0, failed)
+		channelRepo.AssertExpectations(t)
+	})
+
+	t.Run("Success - Last attempt fails the delivery", func(t *testing.T) {
+		service, channelRepo, sender := newTestNotificationChannelService()
+		sender.err = errors.New("connection refused")
+		delivery := newDelivery(MaxDeliveryAttempts - 1)
+		channelRepo.On("FindDueDeliveries", mock.AnythingOfType("time.Time"), DeliveryBatchSize).Return([]models.NotificationDelivery{delivery}, nil)
+		channelRepo.On("FindByIDs", []uuid.UUID{channel.ID}).Return([]models.NotificationChannel{channel}, nil)
+		channelRepo.On("CreateAttempt", mock.AnythingOfType("*models.DeliveryAttempt")).Return(nil)
+		channelRepo.On("UpdateDelivery", mock.MatchedBy(func(d *models.NotificationDelivery) bool {
+			return d.Status == models.DeliveryFailed && d.LastError == "connection refused"
+		})).Return(nil)
+		channelRepo.On("RecordDeliveryResult", channel.ID, false, ChannelFailureLimit, mock.Anything, mock.AnythingOfType("time.Time")).Return(false, nil)
+
+		delivered, failed, err := service.ProcessDeliveries()
+
+		assert.NoError(t, err)
+		assert.Equal(t, 0, delivered)
+		assert.Equal(t, 1, failed)
+	})
+
+	t.Run("Success - Client error is not retried", func(t *testing.T) {
+		service, channelRepo, sender := newTestNotificationChannelService()
+		sender.err = permanentError{}
+		delivery := newDelivery(0)
+		channelRepo.On("FindDueDeliveries", mock.AnythingOfType("time.Time"), DeliveryBatchSize).Return([]models.NotificationDelivery{delivery}, nil)
+		channelRepo.On("FindByIDs", []uuid.UUID{channel.ID}).Return([]models.NotificationChannel{channel}, nil)
+		channelRepo.On("CreateAttempt", mock.AnythingOfType("*models.DeliveryAttempt")).Return(nil)
+		channelRepo.On("UpdateDelivery", mock.MatchedBy(func(d *models.NotificationDelivery) bool {
+			return d.Status == models.DeliveryFailed && d.Attempts == 1
+		})).Return(nil)
+		channelRepo.On("RecordDeliveryResult", channel.ID, false, ChannelFailureLimit, mock.Anything, mock.AnythingOfType("time.Time")).Return(false, nil)
+
+		_, failed, err := service.ProcessDeliveries()
+
+		assert.NoError(t, err)
+		assert.Equal(t, 1, failed)
+		channelRepo.AssertExpectations(t)
+	})
+
+	t.Run("Success - Repeated failures disable the channel", func(t *testing.T) {
+		service, channelRepo, sender := newTestNotificationChannelService()
+		sender.err = errors.New("unexpected status 503")
+		first, second := newDelivery(0), newDelivery(0)
+		channelRepo.On("FindDueDeliveries", mock.AnythingOfType("time.Time"), DeliveryBatchSize).Return([]models.NotificationDelivery{first, second}, nil)
+		channelRepo.On("FindByIDs", []uuid.UUID{channel.ID}).Return([]models.NotificationChannel{channel}, nil)
+		channelRepo.On("CreateAttempt", mock.AnythingOfType("*models.DeliveryAttempt")).Return(nil).Once()
+		channelRepo.On("UpdateDelivery", mock.AnythingOfType("*models.NotificationDelivery")).Return(nil)
+		channelRepo.On("RecordDeliveryResult", channel.ID, false, ChannelFailureLimit, mock.MatchedBy(func(reason string) bool {
+			return strings.Contains(reason, "unexpected status 503")
+		}), mock.AnythingOfType("time.Time")).Return(true, nil).Once()
+
+		_, failed, err := service.ProcessDeliveries()
+
+		assert.NoError(t, err)
+		assert.Equal(t, 1, failed)
+		assert.Len(t, sender.sent, 1)
+		channelRepo.AssertExpectations(t)
+	})
+
+	t.Run("Success - Disabled channel fails the delivery", func(t *testing.T) {
+		service, channelRepo, sender := newTestNotificationChannelService()
+		disabled := channel
+		disabled.Enabled = false
+		delivery := newDelivery(0)
+		channelRepo.On("FindDueDeliveries", mock.AnythingOfType("time.Time"), DeliveryBatchSize).Return([]models.NotificationDelivery{delivery}, nil)
+		channelRepo.On("FindByIDs", []uuid.UUID{channel.ID}).Return([]models.NotificationChannel{disabled}, nil)
+		channelRepo.On("UpdateDelivery", mock.MatchedBy(func(d *models.NotificationDelivery) bool {
+			return d.Status == models.DeliveryFailed
+		})).Return(nil)
+
+		_, failed, err := service.ProcessDeliveries()
+
+		assert.NoError(t, err)
+		assert.Equal(t, 1, failed)
+		assert.Empty(t, sender.sent)
+		channelRepo.AssertNotCalled(t, "CreateAttempt", mock.Anything)
+	})
+}
+
+func TestNotificationChannelService_TestChannel(t *testing.T) {
+	userID := uuid.New()
+	channel := &models.NotificationChannel{ID: uuid.New(), UserID: userID, Name: "On-call", Type: models.ChannelWebhook}
+
+	t.Run("Error - Failed test is not retried", func(t *testing.T) {
+		service, channelRepo, sender := newTestNotificationChannelService()
+		sender.err = errors.New("unexpected status 404")
+		channelRepo.On("FindByID", channel.ID).Return(channel, nil)
+		channelRepo.On("CreateDeliveries", mock.AnythingOfType("[]models.NotificationDelivery")).Return(nil)
+		channelRepo.On("CreateAttempt", mock.AnythingOfType("*models.DeliveryAttempt")).Return(nil)
+		channelRepo.On("UpdateDelivery", mock.AnythingOfType("*models.NotificationDelivery")).Return(nil)
+		channelRepo.On("RecordDeliveryResult", channel.ID, false, ChannelFailureLimit, mock.Anything, mock.AnythingOfType("time.Time")).Return(false, nil)
+
+		delivery, err := service.TestChannel(userID, channel.ID)
+
+		assert.NoError(t, err)
+		assert.Equal(t, models.DeliveryFailed, delivery.Status)
+		assert.Nil(t, delivery.NotificationID)
+		assert.Len(t, delivery.Attempts, 1)
+		assert.Equal(t, "unexpected status 404", delivery.Attempts[0].Error)
+		assert.Equal(t, "Test notification", sender.sent[0].Alert.Name)
+	})
+}
@@ -0,0 +1,58 @@
+package services
+
+import (
+	"time"
+
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/utils/logger"
+)
+
+// NotificationDeliveryJob periodically sends the alerts queued for
+// notification channels, retrying the failed ones.
+type NotificationDeliveryJob struct {
+	channelService NotificationChannelService
+	interval       time.Duration
+	shutdown       chan struct{}
+}
+
+func NewNotificationDeliveryJob(channelService NotificationChannelService, interval time.Duration) *NotificationDeliveryJob {
+	return &NotificationDeliveryJob{
+		channelService: channelService,
+		interval:       interval,
+		shutdown:       make(chan struct{}),
+	}
+}
+
+// Run processes deliveries once immediately and then every interval until
+// Stop is called.
+func (j *NotificationDeliveryJob) Run() {
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	for {
+		j.process()
+		select {
+		case <-ticker.C:
+		case <-j.shutdown:
+			return
+		}
+	}
+}
+
+func (j *NotificationDeliveryJob) Stop() {
+	select {
+	case <-j.shutdown:
+	default:
+		close(j.shutdown)
+	}
+}
+
+func (j *NotificationDeliveryJob) process() {
+	delivered, failed, err := j.channelService.ProcessDeliveries()
+	if err != nil {
+		logger.Logger.Error("Error processing notification deliveries", "error", err)
+		return
+	}
+	if delivered > 0 || failed > 0 {
+		logger.Logger.Info("Processed notification deliveries", "delivered", delivered, "failed", failed)
+	}
+}
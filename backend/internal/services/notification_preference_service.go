@@ -0,0 +1,377 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/dto"
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/models"
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/repository"
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/utils/errors"
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/utils/logger"
+	"github.com/google/uuid"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+const (
+	// MaxQuietHours caps the quiet periods of a user.
+	MaxQuietHours = 14
+	// DefaultDigestHour is the local hour digests are sent at when none is
+	// set.
+	DefaultDigestHour = 8
+	// HeldBatchSize is the number of held messages released per run.
+	HeldBatchSize = 500
+)
+
+type NotificationPreferenceService interface {
+	GetPreferences(userID uuid.UUID) (*dto.NotificationPreferenceResponse, error)
+	UpdatePreferences(userID uuid.UUID, req dto.NotificationPreferenceRequest) (*dto.NotificationPreferenceResponse, error)
+	Deliver(userID uuid.UUID, msg dto.NotificationAlert, channelIDs []uuid.UUID, now time.Time) error
+	ReleaseHeld(now time.Time) (released int, err error)
+}
+
+type notificationPreferenceService struct {
+	preferenceRepo repository.NotificationPreferenceRepository
+	channelService NotificationChannelService
+	redisClient    RedisPublisher
+}
+
+func NewNotificationPreferenceService(preferenceRepo repository.NotificationPreferenceRepository, channelService NotificationChannelService, redisClient RedisPublisher) NotificationPreferenceService {
+	return &notificationPreferenceService{
+		preferenceRepo: preferenceRepo,
+		channelService: channelService,
+		redisClient:    redisClient,
+	}
+}
+
+// GetPreferences returns the preferences of the user, or the defaults when
+// they were never set.
+func (s *notificationPreferenceService) GetPreferences(userID uuid.UUID) (*dto.NotificationPreferenceResponse, error) {
+	preference, err := s.preference(userID)
+	if err != nil {
+		return nil, err
+	}
+	return notificationPreferenceResponse(preference), nil
+}
+
+// UpdatePreferences replaces the preferences of the user. Preferred channels
+// must be personal channels of the user, and the digest channel a personal
+// email channel.
+func (s *notificationPreferenceService) UpdatePreferences(userID uuid.UUID, req dto.NotificationPreferenceRequest) (*dto.NotificationPreferenceResponse, error) {
+	current, err := s.preference(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	preference := defaultPreference(userID)
+	preference.CreatedAt = current.CreatedAt
+	preference.DailyDigestSentAt = current.DailyDigestSentAt
+	preference.WeeklyDigestSentAt = current.WeeklyDigestSentAt
+	if preference.CreatedAt.IsZero() {
+		preference.CreatedAt = now
+	}
+	preference.UpdatedAt = now
+
+	if timezone := strings.TrimSpace(req.Timezone); timezone != "" {
+		if _, err := time.LoadLocation(timezone); err != nil {
+			return nil, errors.NewValidationError("Invalid timezone: " + timezone)
+		}
+		preference.Timezone = timezone
+	}
+
+	if len(req.QuietHours) > MaxQuietHours {
+		return nil, errors.NewValidationError(fmt.Sprintf("At most %d quiet periods are allowed", MaxQuietHours))
+	}
+	for _, period := range req.QuietHours {
+		if period.Weekday < 0 || period.Weekday > 6 {
+			return nil, errors.NewValidationError("Quiet hours weekday must be between 0 (Sunday) and 6 (Saturday)")
+		}
+		if _, ok := models.ParseClock(period.Start); !ok {
+			return nil, errors.NewValidationError("Invalid quiet hours start, expected HH:MM: " + period.Start)
+		}
+		if _, ok := models.ParseClock(period.End); !ok {
+			return nil, errors.NewValidationError("Invalid quiet hours end, expected HH:MM: " + period.End)
+		}
+		preference.QuietHours = append(preference.QuietHours, models.QuietHours{Weekday: period.Weekday, Start: period.Start, End: period.End})
+	}
+
+	switch action := strings.ToLower(strings.TrimSpace(req.QuietHoursAction)); action {
+	case "":
+	case models.QuietHoursDefer, models.QuietHoursDigest, models.QuietHoursDrop:
+		preference.QuietHoursAction = action
+	default:
+		return nil, errors.NewValidationError("quiet_hours_action must be defer, digest or drop")
+	}
+	if req.CriticalBypassesQuietHours != nil {
+		preference.CriticalBypassesQuietHours = *req.CriticalBypassesQuietHours
+	}
+
+	preferred := make(map[string]uuid.UUID, len(req.PreferredChannels))
+	channelIDs := make([]uuid.UUID, 0, len(req.PreferredChannels))
+	for severity, channelID := range req.PreferredChannels {
+		severity = strings.ToLower(strings.TrimSpace(severity))
+		if !models.IsValidSeverity(severity) {
+			return nil, errors.NewValidationError("Preferred channels are keyed by severity: info, warning or critical")
+		}
+		preferred[severity] = channelID
+		channelIDs = append(channelIDs, channelID)
+	}
+	if _, err := s.channelService.ResolveChannels(Ownership{UserID: userID}, channelIDs); err != nil {
+		return nil, err
+	}
+	preference.PreferredChannels = datatypes.NewJSONType(preferred)
+
+	preference.DailyDigest = req.DailyDigest
+	preference.WeeklyDigest = req.WeeklyDigest
+	if req.DigestHour != nil {
+		if *req.DigestHour < 0 || *req.DigestHour > 23 {
+			return nil, errors.NewValidationError("digest_hour must be between 0 and 23")
+		}
+		preference.DigestHour = *req.DigestHour
+	}
+	if req.DigestWeekday != nil {
+		if *req.DigestWeekday < 0 || *req.DigestWeekday > 6 {
+			return nil, errors.NewValidationError("digest_weekday must be between 0 (Sunday) and 6 (Saturday)")
+		}
+		preference.DigestWeekday = *req.DigestWeekday
+	}
+	if req.DigestChannelID != nil {
+		channel, err := s.channelService.GetChannel(userID, *req.DigestChannelID)
+		if err != nil {
+			return nil, err
+		}
+		if channel.OrganizationID != nil || channel.Type != models.ChannelEmail {
+			return nil, errors.NewValidationError("digest_channel_id must be a personal email channel")
+		}
+		preference.DigestChannelID = &channel.ID
+	}
+	if preference.QuietHoursAction == models.QuietHoursDigest && !preference.DailyDigest {
+		return nil, errors.NewValidationError("The digest quiet hours action requires the daily digest")
+	}
+
+	if err := s.preferenceRepo.Save(preference); err != nil {
+		return nil, errors.ErrDatabaseError
+	}
+	return notificationPreferenceResponse(preference), nil
+}
+
+// Deliver sends a message addressed to the user over WebSocket and queues it
+// for channelIDs, which must be personal channels of the user, and for the
+// user's preferred channel for its severity. During the user's quiet hours
+// the message is deferred, held for the digest or dropped instead, unless it
+// is critical and critical messages bypass them. When the preferences cannot
+// be loaded the message is sent right away. Suppressed and retriggered
+// messages only go to the WebSocket, right away.
+func (s *notificationPreferenceService) Deliver(userID uuid.UUID, msg dto.NotificationAlert, channelIDs []uuid.UUID, now time.Time) error {
+	if msg.Suppressed || msg.Retriggered {
+		return s.send(userID, msg, nil)
+	}
+
+	preference, err := s.preference(userID)
+	if err != nil {
+		logger.Logger.Error("Error loading notification preferences", "user_id", userID.String(), "error", err)
+		preference = defaultPreference(userID)
+	}
+
+	if channelID, ok := preference.PreferredChannels.Data()[msg.Severity]; ok {
+		channelIDs = uniqueIDs(append(append([]uuid.UUID{}, channelIDs...), channelID))
+	}
+
+	until, quiet := preference.QuietUntil(now)
+	if !quiet || (msg.Severity == models.SeverityCritical && preference.CriticalBypassesQuietHours) {
+		return s.send(userID, msg, channelIDs)
+	}
+
+	switch preference.QuietHoursAction {
+	case models.QuietHoursDrop:
+		logger.Logger.Info("Notification dropped during quiet hours",
+			"user_id", userID.String(),
+			"notification_id", msg.ID.String())
+		return nil
+	case models.QuietHoursDigest:
+		return s.hold(userID, msg, channelIDs, true, preference.NextDigestAt(now), now)
+	default:
+		return s.hold(userID, msg, channelIDs, false, until, now)
+	}
+}
+
+// ReleaseHeld sends the held messages due at now: deferred messages one by
+// one and digest messages as one digest per user. Failures are logged; the
+// messages are not held again.
+func (s *notificationPreferenceService) ReleaseHeld(now time.Time) (int, error) {
+	held, err := s.preferenceRepo.FindDueHeld(now, HeldBatchSize)
+	if err != nil {
+		return 0, errors.ErrDatabaseError
+	}
+	if len(held) == 0 {
+		return 0, nil
+	}
+
+	var users []uuid.UUID
+	digests := make(map[uuid.UUID][]models.HeldNotification)
+	ids := make([]uuid.UUID, 0, len(held))
+	for _, h := range held {
+		ids = append(ids, h.ID)
+		if h.Digest {
+			if _, ok := digests[h.UserID]; !ok {
+				users = append(users, h.UserID)
+			}
+			digests[h.UserID] = append(digests[h.UserID], h)
+			continue
+		}
+
+		var msg dto.NotificationAlert
+		if err := json.Unmarshal(h.Payload, &msg); err != nil {
+			logger.Logger.Error("Invalid held notification payload", "id", h.ID.String(), "error", err)
+			continue
+		}
+		if err := s.send(h.UserID, msg, h.ChannelIDs); err != nil {
+			logger.Logger.Error("Error releasing held notification", "id", h.ID.String(), "error", err)
+		}
+	}
+
+	for _, userID := range users {
+		msg, channelIDs := digestMessage(userID, digests[userID], now)
+		if err := s.send(userID, msg, channelIDs); err != nil {
+			logger.Logger.Error("Error sending notification digest", "user_id", userID.String(), "error", err)
+		}
+	}
+
+	if err := s.preferenceRepo.DeleteHeld(ids); err != nil {
+		return 0, errors.ErrDatabaseError
+	}
+	return len(held), nil
+}
+
+// send queues the message for the channels and publishes it to the
+// WebSocket clients of the user.
+func (s *notificationPreferenceService) send(userID uuid.UUID, msg dto.NotificationAlert, channelIDs []uuid.UUID) error {
+	// Channels do not depend on Redis being reachable. Enqueue only uses
+	// the owner and channels of the rule.
+	if len(channelIDs) > 0 {
+		rule := models.Notification{ID: msg.ID, UserID: userID, ChannelIDs: channelIDs}
+		if err := s.channelService.Enqueue(rule, msg); err != nil {
+			logger.Logger.Error("Error queueing notification deliveries", "user_id", userID.String(), "error", err)
+		}
+	}
+
+	messageJSON, err := json.Marshal(msg)
+	if err != nil {
+		return errors.ErrDatabaseError
+	}
+	if err := s.redisClient.Publish(context.Background(), "notifications:"+userID.String(), messageJSON); err != nil {
+		return errors.ErrDatabaseError
+	}
+	return nil
+}
+
+func (s *notificationPreferenceService) hold(userID uuid.UUID, msg dto.NotificationAlert, channelIDs []uuid.UUID, digest bool, releaseAt, now time.Time) error {
+	payload, err := json.Marshal(msg)
+	if err != nil {
+		return errors.ErrDatabaseError
+	}
+
+	held := &models.HeldNotification{
+		ID:         uuid.New(),
+		UserID:     userID,
+		ChannelIDs: channelIDs,
+		Payload:    datatypes.JSON(payload),
+		Digest:     digest,
+		ReleaseAt:  releaseAt,
+		CreatedAt:  now,
+	}
+	if held.ChannelIDs == nil {
+		held.ChannelIDs = []uuid.UUID{}
+	}
+	if err := s.preferenceRepo.CreateHeld(held); err != nil {
+		return errors.ErrDatabaseError
+	}
+	return nil
+}
+
+func (s *notificationPreferenceService) preference(userID uuid.UUID) (*models.NotificationPreference, error) {
+	preference, err := s.preferenceRepo.FindByUserID(userID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return defaultPreference(userID), nil
+		}
+		return nil, errors.ErrDatabaseError
+	}
+	return preference, nil
+}
+
+// defaultPreference has no quiet hours, no preferred channels and no
+// digests.
+func defaultPreference(userID uuid.UUID) *models.NotificationPreference {
+	return &models.NotificationPreference{
+		UserID:                     userID,
+		Timezone:                   "UTC",
+		QuietHours:                 []models.QuietHours{},
+		QuietHoursAction:           models.QuietHoursDefer,
+		CriticalBypassesQuietHours: true,
+		PreferredChannels:          datatypes.NewJSONType(map[string]uuid.UUID{}),
+		DigestHour:                 DefaultDigestHour,
+		DigestWeekday:              int(time.Monday),
+	}
+}
+
+// digestMessage combines held messages into one, listing them in its
+// description and in Digest. It goes to the channels of all of them.
+func digestMessage(userID uuid.UUID, held []models.HeldNotification, now time.Time) (dto.NotificationAlert, []uuid.UUID) {
+	msg := dto.NotificationAlert{
+		UserID:    userID,
+		Name:      fmt.Sprintf("Digest: %d alerts held during quiet hours", len(held)),
+		Severity:  models.SeverityInfo,
+		Timestamp: now.Format(time.RFC3339),
+	}
+
+	var lines []string
+	var channelIDs []uuid.UUID
+	for _, h := range held {
+		var item dto.NotificationAlert
+		if err := json.Unmarshal(h.Payload, &item); err != nil {
+			continue
+		}
+		if models.SeverityAtLeast(item.Severity, msg.Severity) {
+			msg.Severity = item.Severity
+		}
+		lines = append(lines, fmt.Sprintf("[%s] %s on device %s at %s", strings.ToUpper(item.Severity), item.Name, item.DeviceSN, item.Timestamp))
+		msg.Digest = append(msg.Digest, item)
+		channelIDs = append(channelIDs, h.ChannelIDs...)
+	}
+	msg.Description = strings.Join(lines, "\n")
+	return msg, uniqueIDs(channelIDs)
+}
+
+func notificationPreferenceResponse(preference *models.NotificationPreference) *dto.NotificationPreferenceResponse {
+	quietHours := make([]dto.QuietHours, 0, len(preference.QuietHours))
+	for _, period := range preference.QuietHours {
+		quietHours = append(quietHours, dto.QuietHours{Weekday: period.Weekday, Start: period.Start, End: period.End})
+	}
+	preferred := preference.PreferredChannels.Data()
+	if preferred == nil {
+		preferred = map[string]uuid.UUID{}
+	}
+
+	response := &dto.NotificationPreferenceResponse{
+		Timezone:                   preference.Timezone,
+		QuietHours:                 quietHours,
+		QuietHoursAction:           preference.QuietHoursAction,
+		CriticalBypassesQuietHours: preference.CriticalBypassesQuietHours,
+		PreferredChannels:          preferred,
+		DailyDigest:                preference.DailyDigest,
+		WeeklyDigest:               preference.WeeklyDigest,
+		DigestHour:                 preference.DigestHour,
+		DigestWeekday:              preference.DigestWeekday,
+		DigestChannelID:            preference.DigestChannelID,
+	}
+	if !preference.UpdatedAt.IsZero() {
+		response.UpdatedAt = &preference.UpdatedAt
+	}
+	return response
+}
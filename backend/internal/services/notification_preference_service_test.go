@@ -0,0 +1,282 @@
+package services
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/dto"
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/models"
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/utils/errors"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+type MockNotificationPreferenceRepository struct {
+	mock.Mock
+}
+
+func (m *MockNotificationPreferenceRepository) FindByUserID(userID uuid.UUID) (*models.NotificationPreference, error) {
+	args := m.Called(userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.NotificationPreference), args.Error(1)
+}
+
+func (m *MockNotificationPreferenceRepository) Save(preference *models.NotificationPreference) error {
+	args := m.Called(preference)
+	return args.Error(0)
+}
+
+func (m *MockNotificationPreferenceRepository) CreateHeld(held *models.HeldNotification) error {
+	args := m.Called(held)
+	return args.Error(0)
+}
+
+func (m *MockNotificationPreferenceRepository) FindDueHeld(now time.Time, limit int) ([]models.HeldNotification, error) {
+	args := m.Called(now, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.HeldNotification), args.Error(1)
+}
+
+func (m *MockNotificationPreferenceRepository) DeleteHeld(ids []uuid.UUID) error {
+	args := m.Called(ids)
+	return args.Error(0)
+}
+
+func (m *MockNotificationPreferenceRepository) FindDigestSubscribers() ([]models.NotificationPreference, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.NotificationPreference), args.Error(1)
+}
+
+func (m *MockNotificationPreferenceRepository) MarkDigestSent(userID uuid.UUID, weekly bool, at time.Time) error {
+	args := m.Called(userID, weekly, at)
+	return args.Error(0)
+}
+
+// defaultPreferences returns a preference service for users who never set
+// their preferences, so messages are sent right away through redis and
+// channelService.
+func defaultPreferences(redis RedisPublisher, channelService NotificationChannelService) NotificationPreferenceService {
+	preferenceRepo := new(MockNotificationPreferenceRepository)
+	preferenceRepo.On("FindByUserID", mock.Anything).Return(nil, gorm.ErrRecordNotFound).Maybe()
+	return NewNotificationPreferenceService(preferenceRepo, channelService, redis)
+}
+
+func newTestNotificationPreferenceService() (NotificationPreferenceService, *MockNotificationPreferenceRepository, *MockNotificationChannelRepository, *MockRedisPublisher) {
+	preferenceRepo := new(MockNotificationPreferenceRepository)
+	channelRepo := new(MockNotificationChannelRepository)
+	redis := new(MockRedisPublisher)
+	channelService := NewNotificationChannelService(channelRepo, map[string]ChannelSender{}, NewAuthorizer(new(MockOrganizationRepository), noDeviceShares()))
+	return NewNotificationPreferenceService(preferenceRepo, channelService, redis), preferenceRepo, channelRepo, redis
+}
+
+// nightPreference is quiet on Monday nights, 22:00 to 07:00 in São Paulo
+// (UTC-3).
+func nightPreference(userID uuid.UUID, action string) *models.NotificationPreference {
+	return &models.NotificationPreference{
+		UserID:                     userID,
+		Timezone:                   "America/Sao_Paulo",
+		QuietHours:                 []models.QuietHours{{Weekday: int(time.Monday), Start: "22:00", End: "07:00"}},
+		QuietHoursAction:           action,
+		CriticalBypassesQuietHours: true,
+		PreferredChannels:          datatypes.NewJSONType(map[string]uuid.UUID{}),
+		DailyDigest:                true,
+		DigestHour:                 8,
+	}
+}
+
+func TestNotificationPreferenceService_UpdatePreferences(t *testing.T) {
+	userID := uuid.New()
+
+	t.Run("Success - Preferences saved", func(t *testing.T) {
+		service, preferenceRepo, channelRepo, _ := newTestNotificationPreferenceService()
+		channel := models.NotificationChannel{ID: uuid.New(), UserID: userID}
+
+		preferenceRepo.On("FindByUserID", userID).Return(nil, gorm.ErrRecordNotFound)
+		channelRepo.On("FindByIDs", []uuid.UUID{channel.ID}).Return([]models.NotificationChannel{channel}, nil)
+		preferenceRepo.On("Save", mock.MatchedBy(func(p *models.NotificationPreference) bool {
+			return p.UserID == userID && p.Timezone == "America/Sao_Paulo" && p.QuietHoursAction == models.QuietHoursDigest &&
+				p.CriticalBypassesQuietHours && p.DigestHour == DefaultDigestHour && p.PreferredChannels.Data()[models.SeverityCritical] == channel.ID
+		})).Return(nil)
+
+		preferences, err := service.UpdatePreferences(userID, dto.NotificationPreferenceRequest{
+			Timezone:          "America/Sao_Paulo",
+			QuietHours:        []dto.QuietHours{{Weekday: 1, Start: "22:00", End: "07:00"}},
+			QuietHoursAction:  "digest",
+			PreferredChannels: map[string]uuid.UUID{"CRITICAL": channel.ID},
+			DailyDigest:       true,
+		})
+
+		assert.NoError(t, err)
+		assert.Len(t, preferences.QuietHours, 1)
+		assert.Equal(t, channel.ID, preferences.PreferredChannels[models.SeverityCritical])
+		preferenceRepo.AssertExpectations(t)
+	})
+
+	t.Run("Error - Invalid quiet hours", func(t *testing.T) {
+		service, preferenceRepo, _, _ := newTestNotificationPreferenceService()
+		preferenceRepo.On("FindByUserID", userID).Return(nil, gorm.ErrRecordNotFound)
+
+		_, err := service.UpdatePreferences(userID, dto.NotificationPreferenceRequest{
+			QuietHours: []dto.QuietHours{{Weekday: 1, Start: "10pm", End: "07:00"}},
+		})
+
+		assert.Error(t, err)
+		preferenceRepo.AssertNotCalled(t, "Save", mock.Anything)
+	})
+
+	t.Run("Error - Digest action without daily digest", func(t *testing.T) {
+		service, preferenceRepo, _, _ := newTestNotificationPreferenceService()
+		preferenceRepo.On("FindByUserID", userID).Return(nil, gorm.ErrRecordNotFound)
+
+		_, err := service.UpdatePreferences(userID, dto.NotificationPreferenceRequest{QuietHoursAction: "digest"})
+
+		assert.Error(t, err)
+		preferenceRepo.AssertNotCalled(t, "Save", mock.Anything)
+	})
+
+	t.Run("Error - Preferred channel of another user", func(t *testing.T) {
+		service, preferenceRepo, channelRepo, _ := newTestNotificationPreferenceService()
+		channel := models.NotificationChannel{ID: uuid.New(), UserID: uuid.New()}
+
+		preferenceRepo.On("FindByUserID", userID).Return(nil, gorm.ErrRecordNotFound)
+		channelRepo.On("FindByIDs", []uuid.UUID{channel.ID}).Return([]models.NotificationChannel{channel}, nil)
+
+		_, err := service.UpdatePreferences(userID, dto.NotificationPreferenceRequest{
+			PreferredChannels: map[string]uuid.UUID{"warning": channel.ID},
+		})
+
+		assert.Equal(t, errors.ErrNotificationChannelNotFound, err)
+		preferenceRepo.AssertNotCalled(t, "Save", mock.Anything)
+	})
+}
+
+func TestNotificationPreferenceService_Deliver(t *testing.T) {
+	userID := uuid.New()
+	// Monday 23:30 in São Paulo.
+	quiet := time.Date(2025, 9, 2, 2, 30, 0, 0, time.UTC)
+	// Tuesday 07:00 in São Paulo.
+	quietEnd := time.Date(2025, 9, 2, 10, 0, 0, 0, time.UTC)
+	msg := dto.NotificationAlert{ID: uuid.New(), Name: "High CPU Alert", Severity: models.SeverityWarning, DeviceID: uuid.New()}
+
+	t.Run("Success - Sent outside quiet hours", func(t *testing.T) {
+		service, preferenceRepo, _, redis := newTestNotificationPreferenceService()
+		preferenceRepo.On("FindByUserID", userID).Return(nightPreference(userID, models.QuietHoursDefer), nil)
+		redis.On("Publish", mock.Anything, "notifications:"+userID.String(), mock.Anything).Return(nil)
+
+		err := service.Deliver(userID, msg, nil, quietEnd)
+
+		assert.NoError(t, err)
+		redis.AssertExpectations(t)
+		preferenceRepo.AssertNotCalled(t, "CreateHeld", mock.Anything)
+	})
+
+	t.Run("Success - Deferred until quiet hours end", func(t *testing.T) {
+		service, preferenceRepo, _, redis := newTestNotificationPreferenceService()
+		channelID := uuid.New()
+		preferenceRepo.On("FindByUserID", userID).Return(nightPreference(userID, models.QuietHoursDefer), nil)
+		preferenceRepo.On("CreateHeld", mock.MatchedBy(func(held *models.HeldNotification) bool {
+			return held.UserID == userID && !held.Digest && held.ReleaseAt.Equal(quietEnd) && len(held.ChannelIDs) == 1 && held.ChannelIDs[0] == channelID
+		})).Return(nil)
+
+		err := service.Deliver(userID, msg, []uuid.UUID{channelID}, quiet)
+
+		assert.NoError(t, err)
+		preferenceRepo.AssertExpectations(t)
+		redis.AssertNotCalled(t, "Publish", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Success - Held for the digest", func(t *testing.T) {
+		service, preferenceRepo, _, _ := newTestNotificationPreferenceService()
+		// Tuesday 08:00 in São Paulo.
+		digestAt := time.Date(2025, 9, 2, 11, 0, 0, 0, time.UTC)
+		preferenceRepo.On("FindByUserID", userID).Return(nightPreference(userID, models.QuietHoursDigest), nil)
+		preferenceRepo.On("CreateHeld", mock.MatchedBy(func(held *models.HeldNotification) bool {
+			return held.Digest && held.ReleaseAt.Equal(digestAt)
+		})).Return(nil)
+
+		err := service.Deliver(userID, msg, nil, quiet)
+
+		assert.NoError(t, err)
+		preferenceRepo.AssertExpectations(t)
+	})
+
+	t.Run("Success - Dropped during quiet hours", func(t *testing.T) {
+		service, preferenceRepo, _, redis := newTestNotificationPreferenceService()
+		preferenceRepo.On("FindByUserID", userID).Return(nightPreference(userID, models.QuietHoursDrop), nil)
+
+		err := service.Deliver(userID, msg, nil, quiet)
+
+		assert.NoError(t, err)
+		preferenceRepo.AssertNotCalled(t, "CreateHeld", mock.Anything)
+		redis.AssertNotCalled(t, "Publish", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Success - Critical alerts bypass quiet hours and reach the preferred channel", func(t *testing.T) {
+		service, preferenceRepo, channelRepo, redis := newTestNotificationPreferenceService()
+		pager := models.NotificationChannel{ID: uuid.New(), UserID: userID, Type: models.ChannelWebhook, Enabled: true}
+		preference := nightPreference(userID, models.QuietHoursDrop)
+		preference.PreferredChannels = datatypes.NewJSONType(map[string]uuid.UUID{models.SeverityCritical: pager.ID})
+		critical := msg
+		critical.Severity = models.SeverityCritical
+
+		preferenceRepo.On("FindByUserID", userID).Return(preference, nil)
+		channelRepo.On("FindByIDs", []uuid.UUID{pager.ID}).Return([]models.NotificationChannel{pager}, nil)
+		channelRepo.On("CreateDeliveries", mock.MatchedBy(func(deliveries []models.NotificationDelivery) bool {
+			return len(deliveries) == 1 && deliveries[0].ChannelID == pager.ID
+		})).Return(nil)
+		redis.On("Publish", mock.Anything, "notifications:"+userID.String(), mock.Anything).Return(nil)
+
+		err := service.Deliver(userID, critical, nil, quiet)
+
+		assert.NoError(t, err)
+		channelRepo.AssertExpectations(t)
+		redis.AssertExpectations(t)
+	})
+}
+
+func TestNotificationPreferenceService_ReleaseHeld(t *testing.T) {
+	userID := uuid.New()
+	now := time.Now()
+	payload := func(name string) datatypes.JSON {
+		data, _ := json.Marshal(dto.NotificationAlert{ID: uuid.New(), Name: name, Severity: models.SeverityWarning, DeviceSN: "SN123456"})
+		return data
+	}
+
+	t.Run("Success - Deferred messages released and digests combined", func(t *testing.T) {
+		service, preferenceRepo, _, redis := newTestNotificationPreferenceService()
+		held := []models.HeldNotification{
+			{ID: uuid.New(), UserID: userID, Payload: payload("Deferred")},
+			{ID: uuid.New(), UserID: userID, Payload: payload("High CPU Alert"), Digest: true},
+			{ID: uuid.New(), UserID: userID, Payload: payload("Low Disk Alert"), Digest: true},
+		}
+
+		preferenceRepo.On("FindDueHeld", now, HeldBatchSize).Return(held, nil)
+		redis.On("Publish", mock.Anything, "notifications:"+userID.String(), mock.MatchedBy(func(message []byte) bool {
+			var msg dto.NotificationAlert
+			return json.Unmarshal(message, &msg) == nil && msg.Name == "Deferred"
+		})).Return(nil).Once()
+		redis.On("Publish", mock.Anything, "notifications:"+userID.String(), mock.MatchedBy(func(message []byte) bool {
+			var msg dto.NotificationAlert
+			return json.Unmarshal(message, &msg) == nil && len(msg.Digest) == 2 && msg.Severity == models.SeverityWarning
+		})).Return(nil).Once()
+		preferenceRepo.On("DeleteHeld", []uuid.UUID{held[0].ID, held[1].ID, held[2].ID}).Return(nil)
+
+		released, err := service.ReleaseHeld(now)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 3, released)
+		redis.AssertExpectations(t)
+		preferenceRepo.AssertExpectations(t)
+	})
+}
@@ -3,11 +3,14 @@ package services
 import (
 	"context"
 	"encoding/json"
+	"fmt"
+	"strings"
 	"time"
 
 	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/dto"
 	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/models"
 	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/repository"
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/utils"
 	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/utils/errors"
 	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/utils/logger"
 	"github.com/google/uuid"
@@ -15,35 +18,103 @@ import (
 	"gorm.io/gorm"
 )
 
+// MaxRuleConditions caps the conditions of a rule.
+const MaxRuleConditions = 20
+
 type RedisPublisher interface {
 	Publish(ctx context.Context, channel string, message interface{}) error
 }
 
 type NotificationService interface {
-	CreateNotification(userID uuid.UUID, req dto.CreateNotificationRequest) (*models.Notification, error)
+	CreateNotification(userID uuid.UUID, orgID *uuid.UUID, req dto.CreateNotificationRequest) (*models.Notification, error)
 	GetUserNotifications(userID uuid.UUID) ([]models.Notification, error)
 	CheckHeartbeat(heartbeat *models.Heartbeat) error
+	PreviewTemplate(userID uuid.UUID, req dto.TemplatePreviewRequest) (*dto.TemplatePreviewResponse, error)
+	TestRule(userID uuid.UUID, orgID *uuid.UUID, req dto.RuleTestRequest) (*dto.RuleTestResponse, error)
 }
 
 type notificationService struct {
-	notificationRepo repository.NotificationRepository
-	deviceRepo       repository.DeviceRepository
-	redisClient      RedisPublisher // Usando interface em vez do tipo concreto
+	notificationRepo   repository.NotificationRepository
+	deviceRepo         repository.DeviceRepository
+	heartbeatRepo      repository.HeartbeatRepository
+	maintenanceService MaintenanceWindowService
+	muteService        MuteService
+	channelService     NotificationChannelService
+	alertService       AlertService
+	policyService      EscalationPolicyService
+	preferenceService  NotificationPreferenceService
+	authz              Authorizer
 }
 
-func NewNotificationService(notificationRepo repository.NotificationRepository, deviceRepo repository.DeviceRepository, redisClient RedisPublisher) NotificationService {
+func NewNotificationService(notificationRepo repository.NotificationRepository, deviceRepo repository.DeviceRepository, heartbeatRepo repository.HeartbeatRepository, maintenanceService MaintenanceWindowService, muteService MuteService, channelService NotificationChannelService, alertService AlertService, policyService EscalationPolicyService, preferenceService NotificationPreferenceService, authz Authorizer) NotificationService {
 	return &notificationService{
-		notificationRepo: notificationRepo,
-		deviceRepo:       deviceRepo,
-		redisClient:      redisClient,
+		notificationRepo:   notificationRepo,
+		deviceRepo:         deviceRepo,
+		heartbeatRepo:      heartbeatRepo,
+		maintenanceService: maintenanceService,
+		muteService:        muteService,
+		channelService:     channelService,
+		alertService:       alertService,
+		policyService:      policyService,
+		preferenceService:  preferenceService,
+		authz:              authz,
 	}
 }
 
-func (s *notificationService) CreateNotification(userID uuid.UUID, req dto.CreateNotificationRequest) (*models.Notification, error) {
+// CreateNotification creates a personal rule, or an organization rule when
+// orgID is set; the latter requires the operator role in that organization.
+// The channels and escalation policy of the rule must belong to the same
+// owner.
+func (s *notificationService) CreateNotification(userID uuid.UUID, orgID *uuid.UUID, req dto.CreateNotificationRequest) (*models.Notification, error) {
+	owner := Ownership{UserID: userID, OrganizationID: orgID}
+	if err := s.authz.Authorize(userID, owner, models.RoleOperator); err != nil {
+		return nil, err
+	}
+
+	notification, err := s.buildRule(userID, orgID, req)
+	if err != nil {
+		return nil, err
+	}
+
+	channelIDs, err := s.channelService.ResolveChannels(owner, req.ChannelIDs)
+	if err != nil {
+		return nil, err
+	}
+	notification.ChannelIDs = channelIDs
+
+	if req.EscalationPolicyID != nil {
+		if err := s.policyService.ResolvePolicy(owner, *req.EscalationPolicyID); err != nil {
+			return nil, err
+		}
+		notification.EscalationPolicyID = req.EscalationPolicyID
+	}
+
+	if err := s.notificationRepo.Create(notification); err != nil {
+		return nil, errors.ErrDatabaseError
+	}
+
+	return notification, nil
+}
+
+// buildRule validates a rule definition and returns the rule it describes,
+// without its channels and escalation policy. The title and body templates
+// are checked by rendering them with a sample device and heartbeat.
+func (s *notificationService) buildRule(userID uuid.UUID, orgID *uuid.UUID, req dto.CreateNotificationRequest) (*models.Notification, error) {
 	if req.Name == "" {
 		return nil, errors.NewValidationError("Notification name is required")
 	}
 
+	severity := strings.ToLower(strings.TrimSpace(req.Severity))
+	if severity == "" {
+		severity = models.SeverityWarning
+	}
+	if !models.IsValidSeverity(severity) {
+		return nil, errors.NewValidationError("Invalid severity: " + req.Severity + " (must be info, warning or critical)")
+	}
+
+	if len(req.Conditions) > MaxRuleConditions {
+		return nil, errors.NewValidationError(fmt.Sprintf("A rule can have at most %d conditions", MaxRuleConditions))
+	}
 	for _, condition := range req.Conditions {
 		if !isValidParameter(condition.Parameter) {
 			return nil, errors.NewValidationError("Invalid parameter: " + condition.Parameter)
@@ -53,6 +124,11 @@ func (s *notificationService) CreateNotification(userID uuid.UUID, req dto.Creat
 		}
 	}
 
+	selector, err := utils.ParseLabelSelector(req.LabelSelector)
+	if err != nil {
+		return nil, errors.NewValidationError("Invalid label selector: " + err.Error())
+	}
+
 	conditionsJSON, err := json.Marshal(req.Conditions)
 	if err != nil {
 		return nil, errors.NewValidationError("Invalid conditions format")
@@ -64,19 +140,26 @@ func (s *notificationService) CreateNotification(userID uuid.UUID, req dto.Creat
 	}
 
 	notification := &models.Notification{
-		ID:          uuid.New(),
-		UserID:      userID,
-		Name:        req.Name,
-		Description: req.Description,
-		Enabled:     req.Enabled,
-		Conditions:  datatypes.JSON(conditionsJSON),
-		DeviceIDs:   datatypes.JSON(deviceIDsJSON),
-		CreatedAt:   time.Now(),
-		UpdatedAt:   time.Now(),
+		ID:             uuid.New(),
+		UserID:         userID,
+		OrganizationID: orgID,
+		Name:           req.Name,
+		Description:    req.Description,
+		Enabled:        req.Enabled,
+		Severity:       severity,
+		Conditions:     datatypes.JSON(conditionsJSON),
+		DeviceIDs:      datatypes.JSON(deviceIDsJSON),
+		LabelSelector:  selector.String(),
+		TitleTemplate:  req.TitleTemplate,
+		BodyTemplate:   req.BodyTemplate,
+		CreatedAt:      time.Now(),
+		UpdatedAt:      time.Now(),
 	}
 
-	if err := s.notificationRepo.Create(notification); err != nil {
-		return nil, errors.ErrDatabaseError
+	if req.TitleTemplate != "" || req.BodyTemplate != "" {
+		if _, _, err := s.renderTemplates(*notification, req.TitleTemplate, req.BodyTemplate, sampleTemplateDevice(), sampleTemplateHeartbeat(), time.Now()); err != nil {
+			return nil, errors.NewValidationError("Invalid " + err.Error())
+		}
 	}
 
 	return notification, nil
@@ -90,6 +173,11 @@ func (s *notificationService) GetUserNotifications(userID uuid.UUID) ([]models.N
 	return notifications, nil
 }
 
+// CheckHeartbeat sends the rules the heartbeat triggers. While a maintenance
+// window covers the device the alerts are recorded as suppressed instead;
+// the alerts of muted rules and devices only reach the WebSocket, marked as
+// suppressed. The open alerts of the rules it no longer triggers are
+// resolved.
 func (s *notificationService) CheckHeartbeat(heartbeat *models.Heartbeat) error {
 	device, err := s.deviceRepo.FindByID(heartbeat.DeviceID)
 	if err != nil {
@@ -99,138 +187,215 @@ func (s *notificationService) CheckHeartbeat(heartbeat *models.Heartbeat) error
 		return errors.ErrDatabaseError
 	}
 
-	notifications, err := s.notificationRepo.FindActiveByUserID(device.UserID)
+	// Organization devices are evaluated against the organization's rules,
+	// personal devices against the owner's personal rules.
+	var notifications []models.Notification
+	if device.OrganizationID != nil {
+		notifications, err = s.notificationRepo.FindActiveByOrganizationID(*device.OrganizationID)
+	} else {
+		notifications, err = s.notificationRepo.FindActiveByUserID(device.UserID)
+	}
 	if err != nil {
 		return errors.ErrDatabaseError
 	}
 
+	var window *models.MaintenanceWindow
+	windowChecked := false
+	var mutes []models.Mute
+	mutesChecked := false
+	var cleared []uuid.UUID
 	for _, notification := range notifications {
-		if !s.appliesToDevice(notification, device.UUID) {
+		if !s.appliesToDevice(notification, device) {
+			continue
+		}
+		results, matched := s.evaluateConditions(notification.Conditions, heartbeat)
+		if !matched {
+			cleared = append(cleared, notification.ID)
+			continue
+		}
+
+		// Windows are only looked up once a rule triggers; alerts are sent
+		// when the lookup fails.
+		if !windowChecked {
+			windowChecked = true
+			window, err = s.maintenanceService.ActiveWindow(device, time.Now())
+			if err != nil {
+				logger.Logger.Error("Error checking maintenance windows", "device_id", device.UUID, "error", err)
+			}
+		}
+		if window != nil {
+			if err := s.maintenanceService.RecordSuppressedAlert(window, notification, device, triggeredValue(results)); err != nil {
+				logger.Logger.Error("Error recording suppressed alert", "error", err)
+			}
 			continue
 		}
 
-		if s.checkConditions(notification.Conditions, heartbeat) {
-			if err := s.sendNotification(device.UserID, notification, device, heartbeat); err != nil {
-				logger.Logger.Error("Error sending notification", "error", err)
+		if !mutesChecked {
+			mutesChecked = true
+			mutes, err = s.muteService.ActiveMutes(device, time.Now())
+			if err != nil {
+				logger.Logger.Error("Error checking mutes", "device_id", device.UUID, "error", err)
+			}
+		}
+		if mute := findMute(mutes, notification.ID, device.UUID); mute != nil {
+			if err := s.sendSuppressed(notification.UserID, notification, device, heartbeat, results, mute); err != nil {
+				logger.Logger.Error("Error sending suppressed notification", "error", err)
 			}
+			continue
+		}
+
+		if err := s.sendNotification(notification.UserID, notification, device, heartbeat, results); err != nil {
+			logger.Logger.Error("Error sending notification", "error", err)
+		}
+	}
+
+	if len(cleared) > 0 {
+		if err := s.alertService.Resolve(device, cleared, time.Now()); err != nil {
+			logger.Logger.Error("Error resolving alerts", "device_id", device.UUID, "error", err)
 		}
 	}
 
 	return nil
 }
 
-func (s *notificationService) appliesToDevice(notification models.Notification, deviceID uuid.UUID) bool {
+// appliesToDevice reports whether a rule targets the device. A rule without
+// device IDs and without a label selector targets every device of its owner;
+// otherwise the device must be listed in DeviceIDs or match the selector.
+func (s *notificationService) appliesToDevice(notification models.Notification, device *models.Device) bool {
 	var deviceIDs []uuid.UUID
 	if err := json.Unmarshal(notification.DeviceIDs, &deviceIDs); err != nil {
 		return false
 	}
 
-	if len(deviceIDs) == 0 {
+	if len(deviceIDs) == 0 && notification.LabelSelector == "" {
 		return true
 	}
 
 	for _, id := range deviceIDs {
-		if id == deviceID {
+		if id == device.UUID {
 			return true
 		}
 	}
-	return false
+
+	if notification.LabelSelector == "" {
+		return false
+	}
+	selector, err := utils.ParseLabelSelector(notification.LabelSelector)
+	if err != nil {
+		logger.Logger.Error("Invalid label selector on notification rule",
+			"notification_id", notification.ID.String(),
+			"error", err)
+		return false
+	}
+	return selector.Matches(device.Labels)
 }
 
-func (s *notificationService) checkConditions(conditionsJSON datatypes.JSON, heartbeat *models.Heartbeat) bool {
+func (s *notificationService) checkCondition(condition dto.NotificationCondition, heartbeat *models.Heartbeat) bool {
+	return s.evaluateCondition(condition, heartbeat).Matched
+}
+
+// evaluateConditions evaluates every condition of a rule against the
+// heartbeat. matched is set when they all match; rules with invalid
+// conditions never match.
+func (s *notificationService) evaluateConditions(conditionsJSON datatypes.JSON, heartbeat *models.Heartbeat) (results []dto.ConditionResult, matched bool) {
 	var conditions []dto.NotificationCondition
 	if err := json.Unmarshal(conditionsJSON, &conditions); err != nil {
-		return false
+		return nil, false
 	}
 
+	results = make([]dto.ConditionResult, 0, len(conditions))
+	matched = true
 	for _, condition := range conditions {
-		if !s.checkCondition(condition, heartbeat) {
-			return false
-		}
+		result := s.evaluateCondition(condition, heartbeat)
+		matched = matched && result.Matched
+		results = append(results, result)
 	}
-	return true
+	return results, matched
 }
 
-func (s *notificationService) checkCondition(condition dto.NotificationCondition, heartbeat *models.Heartbeat) bool {
-	var value float64
+func (s *notificationService) evaluateCondition(condition dto.NotificationCondition, heartbeat *models.Heartbeat) dto.ConditionResult {
+	result := dto.ConditionResult{Parameter: condition.Parameter, Operator: condition.Operator}
 
 	switch condition.Parameter {
 	case "cpu":
-		value = heartbeat.CPU
+		result.Actual = heartbeat.CPU
 	case "ram":
-		value = heartbeat.RAM
+		result.Actual = heartbeat.RAM
 	case "disk_free":
-		value = heartbeat.DiskFree
+		result.Actual = heartbeat.DiskFree
 	case "temperature":
-		value = heartbeat.Temperature
+		result.Actual = heartbeat.Temperature
 	case "latency":
-		value = float64(heartbeat.Latency)
+		result.Actual = float64(heartbeat.Latency)
 	case "connectivity":
-		value = float64(heartbeat.Connectivity)
+		result.Actual = float64(heartbeat.Connectivity)
 	default:
-		return false
+		return result
 	}
 
-	var conditionValue float64
 	switch v := condition.Value.(type) {
 	case float64:
-		conditionValue = v
+		result.Threshold = v
 	case int:
-		conditionValue = float64(v)
+		result.Threshold = float64(v)
 	case float32:
-		conditionValue = float64(v)
+		result.Threshold = float64(v)
 	default:
-		return false
+		return result
 	}
 
 	switch condition.Operator {
 	case ">":
-		return value > conditionValue
+		result.Matched = result.Actual > result.Threshold
 	case "<":
-		return value < conditionValue
+		result.Matched = result.Actual < result.Threshold
 	case ">=":
-		return value >= conditionValue
+		result.Matched = result.Actual >= result.Threshold
 	case "<=":
-		return value <= conditionValue
+		result.Matched = result.Actual <= result.Threshold
 	case "==":
-		return value == conditionValue
+		result.Matched = result.Actual == result.Threshold
 	case "!=":
-		return value != conditionValue
-	default:
-		return false
+		result.Matched = result.Actual != result.Threshold
 	}
+	return result
 }
 
-func (s *notificationService) sendNotification(userID uuid.UUID, notification models.Notification, device *models.Device, heartbeat *models.Heartbeat) error {
-	notificationMessage := map[string]interface{}{
-		"id":              notification.ID.String(),
-		"user_id":         userID.String(),
-		"name":            notification.Name,
-		"description":     notification.Description,
-		"device_sn":       device.SN,
-		"triggered_value": s.getTriggeredValue(notification.Conditions, heartbeat),
-		"timestamp":       time.Now().Format(time.RFC3339),
-		"heartbeat_data": map[string]interface{}{
-			"cpu":          heartbeat.CPU,
-			"ram":          heartbeat.RAM,
-			"disk_free":    heartbeat.DiskFree,
-			"temperature":  heartbeat.Temperature,
-			"latency":      heartbeat.Latency,
-			"connectivity": heartbeat.Connectivity,
-		},
+// sendNotification records the alert, queues it for the channels of the rule
+// and delivers it to userID according to the user's preferences. The
+// preferences also cover the rule's channels when they are personal; the
+// shared channels of an organization always get the alert. Only the
+// heartbeat that opens the alert reaches the channels; while it stays open,
+// later triggers only update it on the WebSocket and escalation steps do the
+// paging.
+func (s *notificationService) sendNotification(userID uuid.UUID, notification models.Notification, device *models.Device, heartbeat *models.Heartbeat, results []dto.ConditionResult) error {
+	now := time.Now()
+	alert := s.newAlert(userID, notification, device, heartbeat, results, now)
+
+	opened, err := s.alertService.Fire(notification, device, &alert, now)
+	if err != nil {
+		// Without a record of the alert, channels still get it.
+		logger.Logger.Error("Error recording alert", "notification_id", notification.ID.String(), "error", err)
+		opened = true
+	}
+	if !opened {
+		alert.Retriggered = true
+		return s.preferenceService.Deliver(userID, alert, nil, now)
 	}
 
-	messageJSON, err := json.Marshal(notificationMessage)
-	if err != nil {
-		return errors.ErrDatabaseError
+	channelIDs := notification.ChannelIDs
+	if notification.OrganizationID != nil {
+		if err := s.channelService.Enqueue(notification, alert); err != nil {
+			logger.Logger.Error("Error queueing notification deliveries", "notification_id", notification.ID.String(), "error", err)
+		}
+		channelIDs = nil
 	}
 
-	ctx := context.Background()
-	if err := s.redisClient.Publish(ctx, "notifications:"+userID.String(), messageJSON); err != nil {
-		return errors.ErrDatabaseError
+	if err := s.preferenceService.Deliver(userID, alert, channelIDs, now); err != nil {
+		return err
 	}
 
-	logger.Logger.Info("Notification sent via Redis", 
+	logger.Logger.Info("Notification dispatched", 
 		"user_id", userID.String(),
 		"notification_id", notification.ID.String(),
 		"device_sn", device.SN)
@@ -238,27 +403,147 @@ func (s *notificationService) sendNotification(userID uuid.UUID, notification mo
 	return nil
 }
 
-func (s *notificationService) getTriggeredValue(conditionsJSON datatypes.JSON, heartbeat *models.Heartbeat) float64 {
-	var conditions []dto.NotificationCondition
-	if err := json.Unmarshal(conditionsJSON, &conditions); err != nil {
-		return 0
+// sendSuppressed sends the alert of a muted rule or device to the WebSocket
+// only, marked as suppressed. No alert is recorded and no channel gets it.
+func (s *notificationService) sendSuppressed(userID uuid.UUID, notification models.Notification, device *models.Device, heartbeat *models.Heartbeat, results []dto.ConditionResult, mute *models.Mute) error {
+	now := time.Now()
+	alert := s.newAlert(userID, notification, device, heartbeat, results, now)
+	alert.Suppressed = true
+	alert.MuteID = &mute.ID
+	alert.MutedUntil = &mute.ExpiresAt
+	return s.preferenceService.Deliver(userID, alert, nil, now)
+}
+
+// newAlert builds the message of a rule triggered by the heartbeat, with the
+// result of each of its conditions and the rule's title and body templates
+// rendered when it has any. A template that fails to render is logged and
+// channels fall back to their default text.
+func (s *notificationService) newAlert(userID uuid.UUID, notification models.Notification, device *models.Device, heartbeat *models.Heartbeat, results []dto.ConditionResult, now time.Time) dto.NotificationAlert {
+	alert := dto.NotificationAlert{
+		ID:             notification.ID,
+		UserID:         userID,
+		Name:           notification.Name,
+		Description:    notification.Description,
+		Severity:       notification.Severity,
+		DeviceID:       device.UUID,
+		DeviceSN:       device.SN,
+		TriggeredValue: triggeredValue(results),
+		Conditions:     results,
+		Timestamp:      now.Format(time.RFC3339),
+		HeartbeatData: dto.AlertHeartbeatData{
+			CPU:          heartbeat.CPU,
+			RAM:          heartbeat.RAM,
+			DiskFree:     heartbeat.DiskFree,
+			Temperature:  heartbeat.Temperature,
+			Latency:      heartbeat.Latency,
+			Connectivity: heartbeat.Connectivity,
+		},
 	}
 
-	for _, condition := range conditions {
-		switch condition.Parameter {
-		case "cpu":
-			return heartbeat.CPU
-		case "ram":
-			return heartbeat.RAM
-		case "disk_free":
-			return heartbeat.DiskFree
-		case "temperature":
-			return heartbeat.Temperature
-		case "latency":
-			return float64(heartbeat.Latency)
-		case "connectivity":
-			return float64(heartbeat.Connectivity)
+	if notification.TitleTemplate != "" || notification.BodyTemplate != "" {
+		rendered, err := renderAlertTemplates(notification.TitleTemplate, notification.BodyTemplate,
+			newAlertTemplateData(notification, device, heartbeat, results, alert.TriggeredValue, now))
+		if err != nil {
+			logger.Logger.Error("Error rendering notification templates", "notification_id", notification.ID.String(), "error", err)
+		} else {
+			alert.Rendered = rendered
+		}
+	}
+	return alert
+}
+
+// PreviewTemplate renders title and body templates as a rule would when
+// triggered by the heartbeat of the request on the device of the request,
+// a sample heartbeat and device by default. Previewing a real device
+// requires access to it.
+func (s *notificationService) PreviewTemplate(userID uuid.UUID, req dto.TemplatePreviewRequest) (*dto.TemplatePreviewResponse, error) {
+	if req.TitleTemplate == "" && req.BodyTemplate == "" {
+		return nil, errors.NewValidationError("title_template or body_template is required")
+	}
+	if len(req.Conditions) > MaxRuleConditions {
+		return nil, errors.NewValidationError(fmt.Sprintf("A rule can have at most %d conditions", MaxRuleConditions))
+	}
+	for _, condition := range req.Conditions {
+		if !isValidParameter(condition.Parameter) {
+			return nil, errors.NewValidationError("Invalid parameter: " + condition.Parameter)
 		}
+		if !isValidOperator(condition.Operator) {
+			return nil, errors.NewValidationError("Invalid operator: " + condition.Operator)
+		}
+	}
+	conditionsJSON, err := json.Marshal(req.Conditions)
+	if err != nil {
+		return nil, errors.NewValidationError("Invalid conditions format")
+	}
+
+	device := sampleTemplateDevice()
+	if req.DeviceID != nil {
+		device, err = s.deviceRepo.FindByID(*req.DeviceID)
+		if err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return nil, errors.ErrDeviceNotFound
+			}
+			return nil, errors.ErrDatabaseError
+		}
+		if err := s.authz.Authorize(userID, deviceOwnership(device), models.RoleViewer); err != nil {
+			return nil, err
+		}
+	}
+	heartbeat := sampleTemplateHeartbeat()
+	if req.Heartbeat != nil {
+		heartbeat.CPU = req.Heartbeat.CPU
+		heartbeat.RAM = req.Heartbeat.RAM
+		heartbeat.DiskFree = req.Heartbeat.DiskFree
+		heartbeat.Temperature = req.Heartbeat.Temperature
+		heartbeat.Latency = req.Heartbeat.Latency
+		heartbeat.Connectivity = req.Heartbeat.Connectivity
+	}
+
+	severity := strings.ToLower(strings.TrimSpace(req.Severity))
+	if severity == "" {
+		severity = models.SeverityWarning
+	}
+	rule := models.Notification{Name: req.Name, Description: req.Description, Severity: severity, Conditions: datatypes.JSON(conditionsJSON)}
+	rendered, results, err := s.renderTemplates(rule, req.TitleTemplate, req.BodyTemplate, device, heartbeat, time.Now())
+	if err != nil {
+		return nil, errors.NewValidationError("Invalid " + err.Error())
+	}
+
+	matched := true
+	for _, result := range results {
+		matched = matched && result.Matched
+	}
+	return &dto.TemplatePreviewResponse{
+		Title:      rendered.Subject,
+		Body:       rendered.Text,
+		Matched:    matched,
+		Conditions: results,
+	}, nil
+}
+
+// renderTemplates renders the title and body templates for the rule
+// triggered by the heartbeat on the device, and returns the condition
+// results they were rendered with.
+func (s *notificationService) renderTemplates(notification models.Notification, titleTemplate, bodyTemplate string, device *models.Device, heartbeat *models.Heartbeat, now time.Time) (*dto.RenderedMessage, []dto.ConditionResult, error) {
+	results, _ := s.evaluateConditions(notification.Conditions, heartbeat)
+	data := newAlertTemplateData(notification, device, heartbeat, results, triggeredValue(results), now)
+	rendered, err := renderAlertTemplates(titleTemplate, bodyTemplate, data)
+	if err != nil {
+		return nil, nil, err
+	}
+	return rendered, results, nil
+}
+
+// triggeredValue is the actual value of the first matched condition, or of
+// the first condition when none matched.
+func triggeredValue(results []dto.ConditionResult) float64 {
+	for _, result := range results {
+		if result.Matched {
+			return result.Actual
+		}
+	}
+	if len(results) > 0 {
+		return results[0].Actual
 	}
 	return 0
 }
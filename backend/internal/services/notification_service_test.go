@@ -35,6 +35,14 @@ func (m *MockNotificationRepository) Create(notification *models.Notification) e
 	return args.Error(0)
 }
 
+func (m *MockNotificationRepository) FindByID(id uuid.UUID) (*models.Notification, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Notification), args.Error(1)
+}
+
 func (m *MockNotificationRepository) FindByUserID(userID uuid.UUID) ([]models.Notification, error) {
 	args := m.Called(userID)
 	if args.Get(0) == nil {
@@ -51,6 +59,14 @@ func (m *MockNotificationRepository) FindActiveByUserID(userID uuid.UUID) ([]mod
 	return args.Get(0).([]models.Notification), args.Error(1)
 }
 
+func (m *MockNotificationRepository) FindActiveByOrganizationID(organizationID uuid.UUID) ([]models.Notification, error) {
+	args := m.Called(organizationID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Notification), args.Error(1)
+}
+
 func TestNotificationService_CreateNotification(t *testing.T) {
 	userID := uuid.New()
 	validConditions := []dto.NotificationCondition{
@@ -62,7 +78,7 @@ func TestNotificationService_CreateNotification(t *testing.T) {
 		mockNotifRepo := new(MockNotificationRepository)
 		mockDeviceRepo := new(MockDeviceRepository)
 		mockRedis := new(MockRedisPublisher)
-		service := NewNotificationService(mockNotifRepo, mockDeviceRepo, mockRedis)
+		service := NewNotificationService(mockNotifRepo, mockDeviceRepo, new(MockHeartbeatRepository), noMaintenanceWindows(), noMutes(), noNotificationChannels(), noAlerts(), noEscalationPolicies(), defaultPreferences(mockRedis, noNotificationChannels()), NewAuthorizer(new(MockOrganizationRepository), noDeviceShares()))
 
 		mockNotifRepo.On("Create", mock.AnythingOfType("*models.Notification")).Return(nil)
 
@@ -74,7 +90,7 @@ func TestNotificationService_CreateNotification(t *testing.T) {
 			DeviceIDs:   validDeviceIDs,
 		}
 
-		notification, err := service.CreateNotification(userID, req)
+		notification, err := service.CreateNotification(userID, nil, req)
 
 		assert.NoError(t, err)
 		assert.NotNil(t, notification)
@@ -86,11 +102,186 @@ func TestNotificationService_CreateNotification(t *testing.T) {
 		mockNotifRepo.AssertExpectations(t)
 	})
 
+	t.Run("Success - Label selector is normalized", func(t *testing.T) {
+		mockNotifRepo := new(MockNotificationRepository)
+		mockDeviceRepo := new(MockDeviceRepository)
+		mockRedis := new(MockRedisPublisher)
+		service := NewNotificationService(mockNotifRepo, mockDeviceRepo, new(MockHeartbeatRepository), noMaintenanceWindows(), noMutes(), noNotificationChannels(), noAlerts(), noEscalationPolicies(), defaultPreferences(mockRedis, noNotificationChannels()), NewAuthorizer(new(MockOrganizationRepository), noDeviceShares()))
+
+		mockNotifRepo.On("Create", mock.AnythingOfType("*models.Notification")).Return(nil)
+
+		req := dto.CreateNotificationRequest{
+			Name:          "Prod CPU",
+			Enabled:       true,
+			Conditions:    validConditions,
+			LabelSelector: " site=sp , env=prod ",
+		}
+
+		notification, err := service.CreateNotification(userID, nil, req)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "env=prod,site=sp", notification.LabelSelector)
+
+		mockNotifRepo.AssertExpectations(t)
+	})
+
+	t.Run("Error - Invalid label selector", func(t *testing.T) {
+		mockNotifRepo := new(MockNotificationRepository)
+		mockDeviceRepo := new(MockDeviceRepository)
+		mockRedis := new(MockRedisPublisher)
+		service := NewNotificationService(mockNotifRepo, mockDeviceRepo, new(MockHeartbeatRepository), noMaintenanceWindows(), noMutes(), noNotificationChannels(), noAlerts(), noEscalationPolicies(), defaultPreferences(mockRedis, noNotificationChannels()), NewAuthorizer(new(MockOrganizationRepository), noDeviceShares()))
+
+		req := dto.CreateNotificationRequest{
+			Name:          "Prod CPU",
+			Conditions:    validConditions,
+			LabelSelector: "env=prod,,site=sp",
+		}
+
+		notification, err := service.CreateNotification(userID, nil, req)
+
+		assert.Error(t, err)
+		assert.IsType(t, &custom_errors.BusinessError{}, err)
+		assert.Nil(t, notification)
+		mockNotifRepo.AssertNotCalled(t, "Create", mock.Anything)
+	})
+
+	t.Run("Error - Channel of another user", func(t *testing.T) {
+		mockNotifRepo := new(MockNotificationRepository)
+		mockDeviceRepo := new(MockDeviceRepository)
+		mockRedis := new(MockRedisPublisher)
+		channelRepo := new(MockNotificationChannelRepository)
+		authz := NewAuthorizer(new(MockOrganizationRepository), noDeviceShares())
+		channelService := NewNotificationChannelService(channelRepo, map[string]ChannelSender{}, authz)
+		service := NewNotificationService(mockNotifRepo, mockDeviceRepo, new(MockHeartbeatRepository), noMaintenanceWindows(), noMutes(), channelService, noAlerts(), noEscalationPolicies(), defaultPreferences(mockRedis, channelService), authz)
+
+		channel := models.NotificationChannel{ID: uuid.New(), UserID: uuid.New()}
+		channelRepo.On("FindByIDs", []uuid.UUID{channel.ID}).Return([]models.NotificationChannel{channel}, nil)
+
+		req := dto.CreateNotificationRequest{
+			Name:       "High CPU Alert",
+			Conditions: validConditions,
+			ChannelIDs: []uuid.UUID{channel.ID, channel.ID},
+		}
+
+		notification, err := service.CreateNotification(userID, nil, req)
+
+		assert.Nil(t, notification)
+		assert.Equal(t, custom_errors.ErrNotificationChannelNotFound, err)
+		mockNotifRepo.AssertNotCalled(t, "Create", mock.Anything)
+	})
+
+	t.Run("Error - Escalation policy of another user", func(t *testing.T) {
+		mockNotifRepo := new(MockNotificationRepository)
+		policyRepo := new(MockEscalationPolicyRepository)
+		authz := NewAuthorizer(new(MockOrganizationRepository), noDeviceShares())
+		policyService := NewEscalationPolicyService(policyRepo, new(MockOrganizationRepository), noNotificationChannels(), authz)
+		service := NewNotificationService(mockNotifRepo, new(MockDeviceRepository), new(MockHeartbeatRepository), noMaintenanceWindows(), noMutes(), noNotificationChannels(), noAlerts(), policyService, defaultPreferences(new(MockRedisPublisher), noNotificationChannels()), authz)
+
+		policy := &models.EscalationPolicy{ID: uuid.New(), UserID: uuid.New()}
+		policyRepo.On("FindByID", policy.ID).Return(policy, nil)
+
+		req := dto.CreateNotificationRequest{
+			Name:               "High CPU Alert",
+			Conditions:         validConditions,
+			EscalationPolicyID: &policy.ID,
+		}
+
+		notification, err := service.CreateNotification(userID, nil, req)
+
+		assert.Nil(t, notification)
+		assert.Equal(t, custom_errors.ErrEscalationPolicyNotFound, err)
+		mockNotifRepo.AssertNotCalled(t, "Create", mock.Anything)
+	})
+
+	t.Run("Success - Severity defaults to warning", func(t *testing.T) {
+		mockNotifRepo := new(MockNotificationRepository)
+		service := NewNotificationService(mockNotifRepo, new(MockDeviceRepository), new(MockHeartbeatRepository), noMaintenanceWindows(), noMutes(), noNotificationChannels(), noAlerts(), noEscalationPolicies(), defaultPreferences(new(MockRedisPublisher), noNotificationChannels()), NewAuthorizer(new(MockOrganizationRepository), noDeviceShares()))
+
+		mockNotifRepo.On("Create", mock.AnythingOfType("*models.Notification")).Return(nil)
+
+		notification, err := service.CreateNotification(userID, nil, dto.CreateNotificationRequest{Name: "High CPU Alert", Conditions: validConditions})
+
+		assert.NoError(t, err)
+		assert.Equal(t, models.SeverityWarning, notification.Severity)
+	})
+
+	t.Run("Error - Invalid severity", func(t *testing.T) {
+		mockNotifRepo := new(MockNotificationRepository)
+		service := NewNotificationService(mockNotifRepo, new(MockDeviceRepository), new(MockHeartbeatRepository), noMaintenanceWindows(), noMutes(), noNotificationChannels(), noAlerts(), noEscalationPolicies(), defaultPreferences(new(MockRedisPublisher), noNotificationChannels()), NewAuthorizer(new(MockOrganizationRepository), noDeviceShares()))
+
+		notification, err := service.CreateNotification(userID, nil, dto.CreateNotificationRequest{Name: "High CPU Alert", Severity: "urgent", Conditions: validConditions})
+
+		assert.Nil(t, notification)
+		assert.Error(t, err)
+		mockNotifRepo.AssertNotCalled(t, "Create", mock.Anything)
+	})
+
+	t.Run("Success - Templates are stored", func(t *testing.T) {
+		mockNotifRepo := new(MockNotificationRepository)
+		mockDeviceRepo := new(MockDeviceRepository)
+		mockRedis := new(MockRedisPublisher)
+		service := NewNotificationService(mockNotifRepo, mockDeviceRepo, new(MockHeartbeatRepository), noMaintenanceWindows(), noMutes(), noNotificationChannels(), noAlerts(), noEscalationPolicies(), defaultPreferences(mockRedis, noNotificationChannels()), NewAuthorizer(new(MockOrganizationRepository), noDeviceShares()))
+
+		mockNotifRepo.On("Create", mock.MatchedBy(func(n *models.Notification) bool {
+			return n.TitleTemplate == "{{.Rule.Name}} on {{.Device.Name}}" && n.BodyTemplate == "CPU at {{.Heartbeat.CPU}}%"
+		})).Return(nil)
+
+		req := dto.CreateNotificationRequest{
+			Name:          "Test Notification",
+			Conditions:    validConditions,
+			TitleTemplate: "{{.Rule.Name}} on {{.Device.Name}}",
+			BodyTemplate:  "CPU at {{.Heartbeat.CPU}}%",
+		}
+
+		_, err := service.CreateNotification(userID, nil, req)
+
+		assert.NoError(t, err)
+		mockNotifRepo.AssertExpectations(t)
+	})
+
+	t.Run("Error - Too many conditions", func(t *testing.T) {
+		mockNotifRepo := new(MockNotificationRepository)
+		mockDeviceRepo := new(MockDeviceRepository)
+		mockRedis := new(MockRedisPublisher)
+		service := NewNotificationService(mockNotifRepo, mockDeviceRepo, new(MockHeartbeatRepository), noMaintenanceWindows(), noMutes(), noNotificationChannels(), noAlerts(), noEscalationPolicies(), defaultPreferences(mockRedis, noNotificationChannels()), NewAuthorizer(new(MockOrganizationRepository), noDeviceShares()))
+
+		conditions := make([]dto.NotificationCondition, MaxRuleConditions+1)
+		for i := range conditions {
+			conditions[i] = dto.NotificationCondition{Parameter: "cpu", Operator: ">", Value: 80.0}
+		}
+
+		_, err := service.CreateNotification(userID, nil, dto.CreateNotificationRequest{Name: "Test Notification", Conditions: conditions})
+
+		assert.IsType(t, &custom_errors.BusinessError{}, err)
+		mockNotifRepo.AssertNotCalled(t, "Create", mock.Anything)
+	})
+
+	t.Run("Error - Invalid template", func(t *testing.T) {
+		mockNotifRepo := new(MockNotificationRepository)
+		mockDeviceRepo := new(MockDeviceRepository)
+		mockRedis := new(MockRedisPublisher)
+		service := NewNotificationService(mockNotifRepo, mockDeviceRepo, new(MockHeartbeatRepository), noMaintenanceWindows(), noMutes(), noNotificationChannels(), noAlerts(), noEscalationPolicies(), defaultPreferences(mockRedis, noNotificationChannels()), NewAuthorizer(new(MockOrganizationRepository), noDeviceShares()))
+
+		req := dto.CreateNotificationRequest{
+			Name:         "Test Notification",
+			Conditions:   validConditions,
+			BodyTemplate: "{{.Device.Token}}",
+		}
+
+		notification, err := service.CreateNotification(userID, nil, req)
+
+		assert.Error(t, err)
+		assert.Nil(t, notification)
+		assert.IsType(t, &custom_errors.BusinessError{}, err)
+		assert.Contains(t, err.Error(), "body_template")
+		mockNotifRepo.AssertNotCalled(t, "Create", mock.Anything)
+	})
+
 	t.Run("Error - Empty notification name", func(t *testing.T) {
 		mockNotifRepo := new(MockNotificationRepository)
 		mockDeviceRepo := new(MockDeviceRepository)
 		mockRedis := new(MockRedisPublisher)
-		service := NewNotificationService(mockNotifRepo, mockDeviceRepo, mockRedis)
+		service := NewNotificationService(mockNotifRepo, mockDeviceRepo, new(MockHeartbeatRepository), noMaintenanceWindows(), noMutes(), noNotificationChannels(), noAlerts(), noEscalationPolicies(), defaultPreferences(mockRedis, noNotificationChannels()), NewAuthorizer(new(MockOrganizationRepository), noDeviceShares()))
 
 		req := dto.CreateNotificationRequest{
 			Name:        "",
@@ -99,7 +290,7 @@ func TestNotificationService_CreateNotification(t *testing.T) {
 			DeviceIDs:   validDeviceIDs,
 		}
 
-		notification, err := service.CreateNotification(userID, req)
+		notification, err := service.CreateNotification(userID, nil, req)
 
 		assert.Error(t, err)
 		assert.Equal(t, custom_errors.NewValidationError("Notification name is required"), err)
@@ -110,7 +301,7 @@ func TestNotificationService_CreateNotification(t *testing.T) {
 		mockNotifRepo := new(MockNotificationRepository)
 		mockDeviceRepo := new(MockDeviceRepository)
 		mockRedis := new(MockRedisPublisher)
-		service := NewNotificationService(mockNotifRepo, mockDeviceRepo, mockRedis)
+		service := NewNotificationService(mockNotifRepo, mockDeviceRepo, new(MockHeartbeatRepository), noMaintenanceWindows(), noMutes(), noNotificationChannels(), noAlerts(), noEscalationPolicies(), defaultPreferences(mockRedis, noNotificationChannels()), NewAuthorizer(new(MockOrganizationRepository), noDeviceShares()))
 
 		invalidConditions := []dto.NotificationCondition{
 			{Parameter: "invalid_param", Operator: ">", Value: 80.0},
@@ -123,7 +314,7 @@ func TestNotificationService_CreateNotification(t *testing.T) {
 			DeviceIDs:   validDeviceIDs,
 		}
 
-		notification, err := service.CreateNotification(userID, req)
+		notification, err := service.CreateNotification(userID, nil, req)
 
 		assert.Error(t, err)
 		assert.Equal(t, custom_errors.NewValidationError("Invalid parameter: invalid_param"), err)
@@ -134,7 +325,7 @@ func TestNotificationService_CreateNotification(t *testing.T) {
 		mockNotifRepo := new(MockNotificationRepository)
 		mockDeviceRepo := new(MockDeviceRepository)
 		mockRedis := new(MockRedisPublisher)
-		service := NewNotificationService(mockNotifRepo, mockDeviceRepo, mockRedis)
+		service := NewNotificationService(mockNotifRepo, mockDeviceRepo, new(MockHeartbeatRepository), noMaintenanceWindows(), noMutes(), noNotificationChannels(), noAlerts(), noEscalationPolicies(), defaultPreferences(mockRedis, noNotificationChannels()), NewAuthorizer(new(MockOrganizationRepository), noDeviceShares()))
 
 		invalidConditions := []dto.NotificationCondition{
 			{Parameter: "cpu", Operator: "invalid_op", Value: 80.0},
@@ -147,7 +338,7 @@ func TestNotificationService_CreateNotification(t *testing.T) {
 			DeviceIDs:   validDeviceIDs,
 		}
 
-		notification, err := service.CreateNotification(userID, req)
+		notification, err := service.CreateNotification(userID, nil, req)
 
 		assert.Error(t, err)
 		assert.Equal(t, custom_errors.NewValidationError("Invalid operator: invalid_op"), err)
@@ -158,7 +349,7 @@ func TestNotificationService_CreateNotification(t *testing.T) {
 		mockNotifRepo := new(MockNotificationRepository)
 		mockDeviceRepo := new(MockDeviceRepository)
 		mockRedis := new(MockRedisPublisher)
-		service := NewNotificationService(mockNotifRepo, mockDeviceRepo, mockRedis)
+		service := NewNotificationService(mockNotifRepo, mockDeviceRepo, new(MockHeartbeatRepository), noMaintenanceWindows(), noMutes(), noNotificationChannels(), noAlerts(), noEscalationPolicies(), defaultPreferences(mockRedis, noNotificationChannels()), NewAuthorizer(new(MockOrganizationRepository), noDeviceShares()))
 
 		mockNotifRepo.On("Create", mock.AnythingOfType("*models.Notification")).Return(errors.New("database error"))
 
@@ -169,7 +360,7 @@ func TestNotificationService_CreateNotification(t *testing.T) {
 			DeviceIDs:   validDeviceIDs,
 		}
 
-		notification, err := service.CreateNotification(userID, req)
+		notification, err := service.CreateNotification(userID, nil, req)
 
 		assert.Error(t, err)
 		assert.Equal(t, custom_errors.ErrDatabaseError, err)
@@ -202,7 +393,7 @@ func TestNotificationService_GetUserNotifications(t *testing.T) {
 		mockNotifRepo := new(MockNotificationRepository)
 		mockDeviceRepo := new(MockDeviceRepository)
 		mockRedis := new(MockRedisPublisher)
-		service := NewNotificationService(mockNotifRepo, mockDeviceRepo, mockRedis)
+		service := NewNotificationService(mockNotifRepo, mockDeviceRepo, new(MockHeartbeatRepository), noMaintenanceWindows(), noMutes(), noNotificationChannels(), noAlerts(), noEscalationPolicies(), defaultPreferences(mockRedis, noNotificationChannels()), NewAuthorizer(new(MockOrganizationRepository), noDeviceShares()))
 
 		mockNotifRepo.On("FindByUserID", userID).Return(notifications, nil)
 
@@ -219,7 +410,7 @@ func TestNotificationService_GetUserNotifications(t *testing.T) {
 		mockNotifRepo := new(MockNotificationRepository)
 		mockDeviceRepo := new(MockDeviceRepository)
 		mockRedis := new(MockRedisPublisher)
-		service := NewNotificationService(mockNotifRepo, mockDeviceRepo, mockRedis)
+		service := NewNotificationService(mockNotifRepo, mockDeviceRepo, new(MockHeartbeatRepository), noMaintenanceWindows(), noMutes(), noNotificationChannels(), noAlerts(), noEscalationPolicies(), defaultPreferences(mockRedis, noNotificationChannels()), NewAuthorizer(new(MockOrganizationRepository), noDeviceShares()))
 
 		mockNotifRepo.On("FindByUserID", userID).Return(([]models.Notification)(nil), errors.New("database error"))
 
@@ -272,7 +463,7 @@ func TestNotificationService_CheckHeartbeat(t *testing.T) {
 		mockNotifRepo := new(MockNotificationRepository)
 		mockDeviceRepo := new(MockDeviceRepository)
 		mockRedis := new(MockRedisPublisher)
-		service := NewNotificationService(mockNotifRepo, mockDeviceRepo, mockRedis)
+		service := NewNotificationService(mockNotifRepo, mockDeviceRepo, new(MockHeartbeatRepository), noMaintenanceWindows(), noMutes(), noNotificationChannels(), noAlerts(), noEscalationPolicies(), defaultPreferences(mockRedis, noNotificationChannels()), NewAuthorizer(new(MockOrganizationRepository), noDeviceShares()))
 
 		mockDeviceRepo.On("FindByID", deviceID).Return(device, nil)
 		mockNotifRepo.On("FindActiveByUserID", userID).Return([]models.Notification{notification}, nil)
@@ -287,11 +478,269 @@ func TestNotificationService_CheckHeartbeat(t *testing.T) {
 		mockRedis.AssertExpectations(t)
 	})
 
+	t.Run("Success - Organization device uses organization rules", func(t *testing.T) {
+		mockNotifRepo := new(MockNotificationRepository)
+		mockDeviceRepo := new(MockDeviceRepository)
+		mockRedis := new(MockRedisPublisher)
+		service := NewNotificationService(mockNotifRepo, mockDeviceRepo, new(MockHeartbeatRepository), noMaintenanceWindows(), noMutes(), noNotificationChannels(), noAlerts(), noEscalationPolicies(), defaultPreferences(mockRedis, noNotificationChannels()), NewAuthorizer(new(MockOrganizationRepository), noDeviceShares()))
+
+		orgID := uuid.New()
+		authorID := uuid.New()
+		orgDevice := *device
+		orgDevice.OrganizationID = &orgID
+		orgRule := notification
+		orgRule.UserID = authorID
+		orgRule.OrganizationID = &orgID
+
+		mockDeviceRepo.On("FindByID", deviceID).Return(&orgDevice, nil)
+		mockNotifRepo.On("FindActiveByOrganizationID", orgID).Return([]models.Notification{orgRule}, nil)
+		mockRedis.On("Publish", mock.Anything, "notifications:"+authorID.String(), mock.AnythingOfType("[]uint8")).Return(nil)
+
+		err := service.CheckHeartbeat(heartbeat)
+
+		assert.NoError(t, err)
+		mockNotifRepo.AssertNotCalled(t, "FindActiveByUserID", mock.Anything)
+		mockRedis.AssertExpectations(t)
+	})
+
+	t.Run("Success - Alert queued for the rule's channels", func(t *testing.T) {
+		mockNotifRepo := new(MockNotificationRepository)
+		mockDeviceRepo := new(MockDeviceRepository)
+		mockRedis := new(MockRedisPublisher)
+		channelRepo := new(MockNotificationChannelRepository)
+		authz := NewAuthorizer(new(MockOrganizationRepository), noDeviceShares())
+		channelService := NewNotificationChannelService(channelRepo, map[string]ChannelSender{}, authz)
+		service := NewNotificationService(mockNotifRepo, mockDeviceRepo, new(MockHeartbeatRepository), noMaintenanceWindows(), noMutes(), channelService, noAlerts(), noEscalationPolicies(), defaultPreferences(mockRedis, channelService), authz)
+
+		channel := models.NotificationChannel{ID: uuid.New(), UserID: userID, Type: models.ChannelWebhook, Enabled: true}
+		rule := notification
+		rule.ChannelIDs = []uuid.UUID{channel.ID}
+
+		mockDeviceRepo.On("FindByID", deviceID).Return(device, nil)
+		mockNotifRepo.On("FindActiveByUserID", userID).Return([]models.Notification{rule}, nil)
+		mockRedis.On("Publish", mock.Anything, "notifications:"+userID.String(), mock.AnythingOfType("[]uint8")).Return(nil)
+		channelRepo.On("FindByIDs", []uuid.UUID{channel.ID}).Return([]models.NotificationChannel{channel}, nil)
+		channelRepo.On("CreateDeliveries", mock.MatchedBy(func(deliveries []models.NotificationDelivery) bool {
+			var alert dto.NotificationAlert
+			json.Unmarshal(deliveries[0].Payload, &alert)
+			return len(deliveries) == 1 && *deliveries[0].NotificationID == rule.ID && alert.DeviceSN == device.SN && alert.TriggeredValue == heartbeat.CPU
+		})).Return(nil)
+
+		err := service.CheckHeartbeat(heartbeat)
+
+		assert.NoError(t, err)
+		channelRepo.AssertExpectations(t)
+		mockRedis.AssertExpectations(t)
+	})
+
+	t.Run("Success - Open alert is only updated on the WebSocket", func(t *testing.T) {
+		mockNotifRepo := new(MockNotificationRepository)
+		mockDeviceRepo := new(MockDeviceRepository)
+		mockRedis := new(MockRedisPublisher)
+		channelRepo := new(MockNotificationChannelRepository)
+		alertRepo := new(MockAlertRepository)
+		authz := NewAuthorizer(new(MockOrganizationRepository), noDeviceShares())
+		channelService := NewNotificationChannelService(channelRepo, map[string]ChannelSender{}, authz)
+		preferences := defaultPreferences(mockRedis, channelService)
+		alertService := NewAlertService(alertRepo, new(MockEscalationPolicyRepository), channelService, preferences, authz)
+		service := NewNotificationService(mockNotifRepo, mockDeviceRepo, new(MockHeartbeatRepository), noMaintenanceWindows(), noMutes(), channelService, alertService, noEscalationPolicies(), preferences, authz)
+
+		rule := notification
+		rule.ChannelIDs = []uuid.UUID{uuid.New()}
+		open := &models.Alert{ID: uuid.New(), NotificationID: rule.ID, DeviceID: deviceID, Status: models.AlertAcknowledged}
+
+		mockDeviceRepo.On("FindByID", deviceID).Return(device, nil)
+		mockNotifRepo.On("FindActiveByUserID", userID).Return([]models.Notification{rule}, nil)
+		alertRepo.On("FindOpen", rule.ID, deviceID).Return(open, nil)
+		alertRepo.On("Retrigger", open.ID, heartbeat.CPU, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+		mockRedis.On("Publish", mock.Anything, "notifications:"+userID.String(), mock.MatchedBy(func(message []byte) bool {
+			var alert dto.NotificationAlert
+			json.Unmarshal(message, &alert)
+			return alert.Retriggered && alert.AlertID == open.ID
+		})).Return(nil)
+
+		err := service.CheckHeartbeat(heartbeat)
+
+		assert.NoError(t, err)
+		alertRepo.AssertExpectations(t)
+		mockRedis.AssertExpectations(t)
+		channelRepo.AssertNotCalled(t, "CreateDeliveries", mock.Anything)
+	})
+
+	t.Run("Success - Maintenance window suppresses the alert", func(t *testing.T) {
+		mockNotifRepo := new(MockNotificationRepository)
+		mockDeviceRepo := new(MockDeviceRepository)
+		mockRedis := new(MockRedisPublisher)
+		windowRepo := new(MockMaintenanceWindowRepository)
+		authz := NewAuthorizer(new(MockOrganizationRepository), noDeviceShares())
+		maintenance := NewMaintenanceWindowService(windowRepo, mockDeviceRepo, new(MockDeviceGroupRepository), authz)
+		service := NewNotificationService(mockNotifRepo, mockDeviceRepo, new(MockHeartbeatRepository), maintenance, noMutes(), noNotificationChannels(), noAlerts(), noEscalationPolicies(), defaultPreferences(mockRedis, noNotificationChannels()), authz)
+
+		endsAt := time.Now().Add(time.Hour)
+		window := models.MaintenanceWindow{ID: uuid.New(), UserID: userID, StartsAt: time.Now().Add(-time.Hour), EndsAt: &endsAt, DeviceIDs: []uuid.UUID{deviceID}}
+
+		mockDeviceRepo.On("FindByID", deviceID).Return(device, nil)
+		mockNotifRepo.On("FindActiveByUserID", userID).Return([]models.Notification{notification}, nil)
+		windowRepo.On("FindStartedByOwner", userID, (*uuid.UUID)(nil), mock.AnythingOfType("time.Time")).Return([]models.MaintenanceWindow{window}, nil)
+		windowRepo.On("CreateSuppressedAlert", mock.MatchedBy(func(alert *models.SuppressedAlert) bool {
+			return alert.WindowID == window.ID && alert.NotificationID == notification.ID && alert.TriggeredValue == heartbeat.CPU
+		})).Return(nil)
+
+		err := service.CheckHeartbeat(heartbeat)
+
+		assert.NoError(t, err)
+		windowRepo.AssertExpectations(t)
+		mockRedis.AssertNotCalled(t, "Publish", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Success - Muted rule only reaches the WebSocket, marked as suppressed", func(t *testing.T) {
+		mockNotifRepo := new(MockNotificationRepository)
+		mockDeviceRepo := new(MockDeviceRepository)
+		mockRedis := new(MockRedisPublisher)
+		muteRepo := new(MockMuteRepository)
+		channelRepo := new(MockNotificationChannelRepository)
+		authz := NewAuthorizer(new(MockOrganizationRepository), noDeviceShares())
+		channelService := NewNotificationChannelService(channelRepo, map[string]ChannelSender{}, authz)
+		mutes := NewMuteService(muteRepo, mockNotifRepo, mockDeviceRepo, authz)
+		service := NewNotificationService(mockNotifRepo, mockDeviceRepo, new(MockHeartbeatRepository), noMaintenanceWindows(), mutes, channelService, noAlerts(), noEscalationPolicies(), defaultPreferences(mockRedis, channelService), authz)
+
+		rule := notification
+		rule.ChannelIDs = []uuid.UUID{uuid.New()}
+		otherRule := uuid.New()
+		mute := models.Mute{ID: uuid.New(), UserID: userID, NotificationID: &rule.ID, DeviceID: &deviceID, ExpiresAt: time.Now().Add(2 * time.Hour)}
+
+		mockDeviceRepo.On("FindByID", deviceID).Return(device, nil)
+		mockNotifRepo.On("FindActiveByUserID", userID).Return([]models.Notification{rule}, nil)
+		muteRepo.On("FindActiveForDevice", userID, (*uuid.UUID)(nil), deviceID, mock.AnythingOfType("time.Time")).Return([]models.Mute{
+			{ID: uuid.New(), UserID: userID, NotificationID: &otherRule, ExpiresAt: time.Now().Add(time.Hour)},
+			mute,
+		}, nil)
+		mockRedis.On("Publish", mock.Anything, "notifications:"+userID.String(), mock.MatchedBy(func(message []byte) bool {
+			var alert dto.NotificationAlert
+			json.Unmarshal(message, &alert)
+			return alert.Suppressed && alert.MuteID != nil && *alert.MuteID == mute.ID && alert.MutedUntil != nil
+		})).Return(nil)
+
+		err := service.CheckHeartbeat(heartbeat)
+
+		assert.NoError(t, err)
+		mockRedis.AssertExpectations(t)
+		channelRepo.AssertNotCalled(t, "CreateDeliveries", mock.Anything)
+	})
+
+	t.Run("Success - Message carries the result of each condition", func(t *testing.T) {
+		mockNotifRepo := new(MockNotificationRepository)
+		mockDeviceRepo := new(MockDeviceRepository)
+		mockRedis := new(MockRedisPublisher)
+		service := NewNotificationService(mockNotifRepo, mockDeviceRepo, new(MockHeartbeatRepository), noMaintenanceWindows(), noMutes(), noNotificationChannels(), noAlerts(), noEscalationPolicies(), defaultPreferences(mockRedis, noNotificationChannels()), NewAuthorizer(new(MockOrganizationRepository), noDeviceShares()))
+
+		rule := notification
+		rule.Conditions, _ = json.Marshal([]dto.NotificationCondition{
+			{Parameter: "ram", Operator: "<", Value: 90.0},
+			{Parameter: "temperature", Operator: ">=", Value: 70.0},
+		})
+
+		mockDeviceRepo.On("FindByID", deviceID).Return(device, nil)
+		mockNotifRepo.On("FindActiveByUserID", userID).Return([]models.Notification{rule}, nil)
+		mockRedis.On("Publish", mock.Anything, "notifications:"+userID.String(), mock.MatchedBy(func(message []byte) bool {
+			var alert dto.NotificationAlert
+			json.Unmarshal(message, &alert)
+			return alert.TriggeredValue == 85.0 && assert.ObjectsAreEqual([]dto.ConditionResult{
+				{Parameter: "ram", Operator: "<", Threshold: 90, Actual: 85, Matched: true},
+				{Parameter: "temperature", Operator: ">=", Threshold: 70, Actual: 75, Matched: true},
+			}, alert.Conditions)
+		})).Return(nil)
+
+		err := service.CheckHeartbeat(heartbeat)
+
+		assert.NoError(t, err)
+		mockRedis.AssertExpectations(t)
+	})
+
+	t.Run("Success - Rule templates rendered into the alert", func(t *testing.T) {
+		mockNotifRepo := new(MockNotificationRepository)
+		mockDeviceRepo := new(MockDeviceRepository)
+		mockRedis := new(MockRedisPublisher)
+		service := NewNotificationService(mockNotifRepo, mockDeviceRepo, new(MockHeartbeatRepository), noMaintenanceWindows(), noMutes(), noNotificationChannels(), noAlerts(), noEscalationPolicies(), defaultPreferences(mockRedis, noNotificationChannels()), NewAuthorizer(new(MockOrganizationRepository), noDeviceShares()))
+
+		rule := notification
+		rule.TitleTemplate = "{{.Rule.Name | upper}} on {{.Device.SN}}"
+		rule.BodyTemplate = "{{range .Conditions}}{{.Parameter}} {{.Actual}} {{.Operator}} {{.Threshold}}{{end}}"
+
+		mockDeviceRepo.On("FindByID", deviceID).Return(device, nil)
+		mockNotifRepo.On("FindActiveByUserID", userID).Return([]models.Notification{rule}, nil)
+		mockRedis.On("Publish", mock.Anything, "notifications:"+userID.String(), mock.MatchedBy(func(message []byte) bool {
+			var alert dto.NotificationAlert
+			json.Unmarshal(message, &alert)
+			return alert.Rendered != nil && alert.Rendered.Subject == "HIGH CPU ALERT on 123456789012" && alert.Rendered.Text == "cpu 90 > 80"
+		})).Return(nil)
+
+		err := service.CheckHeartbeat(heartbeat)
+
+		assert.NoError(t, err)
+		mockRedis.AssertExpectations(t)
+	})
+
+	t.Run("Success - Alert opened and sent with its ID", func(t *testing.T) {
+		mockNotifRepo := new(MockNotificationRepository)
+		mockDeviceRepo := new(MockDeviceRepository)
+		mockRedis := new(MockRedisPublisher)
+		alertRepo := new(MockAlertRepository)
+		authz := NewAuthorizer(new(MockOrganizationRepository), noDeviceShares())
+		alertService := NewAlertService(alertRepo, new(MockEscalationPolicyRepository), noNotificationChannels(), defaultPreferences(mockRedis, noNotificationChannels()), authz)
+		service := NewNotificationService(mockNotifRepo, mockDeviceRepo, new(MockHeartbeatRepository), noMaintenanceWindows(), noMutes(), noNotificationChannels(), alertService, noEscalationPolicies(), defaultPreferences(mockRedis, noNotificationChannels()), authz)
+
+		var alertID uuid.UUID
+		mockDeviceRepo.On("FindByID", deviceID).Return(device, nil)
+		mockNotifRepo.On("FindActiveByUserID", userID).Return([]models.Notification{notification}, nil)
+		alertRepo.On("FindOpen", notification.ID, deviceID).Return(nil, gorm.ErrRecordNotFound)
+		alertRepo.On("Create", mock.MatchedBy(func(alert *models.Alert) bool {
+			alertID = alert.ID
+			return alert.NotificationID == notification.ID && alert.TriggeredValue == heartbeat.CPU && alert.NextEscalationAt == nil
+		})).Return(nil)
+		mockRedis.On("Publish", mock.Anything, "notifications:"+userID.String(), mock.MatchedBy(func(message []byte) bool {
+			var alert dto.NotificationAlert
+			json.Unmarshal(message, &alert)
+			return alert.AlertID == alertID
+		})).Return(nil)
+
+		err := service.CheckHeartbeat(heartbeat)
+
+		assert.NoError(t, err)
+		alertRepo.AssertExpectations(t)
+		mockRedis.AssertExpectations(t)
+	})
+
+	t.Run("Success - Alerts of rules no longer triggered are resolved", func(t *testing.T) {
+		mockNotifRepo := new(MockNotificationRepository)
+		mockDeviceRepo := new(MockDeviceRepository)
+		mockRedis := new(MockRedisPublisher)
+		alertRepo := new(MockAlertRepository)
+		authz := NewAuthorizer(new(MockOrganizationRepository), noDeviceShares())
+		alertService := NewAlertService(alertRepo, new(MockEscalationPolicyRepository), noNotificationChannels(), defaultPreferences(mockRedis, noNotificationChannels()), authz)
+		service := NewNotificationService(mockNotifRepo, mockDeviceRepo, new(MockHeartbeatRepository), noMaintenanceWindows(), noMutes(), noNotificationChannels(), alertService, noEscalationPolicies(), defaultPreferences(mockRedis, noNotificationChannels()), authz)
+
+		coolConditions, _ := json.Marshal([]dto.NotificationCondition{{Parameter: "temperature", Operator: ">", Value: 90.0}})
+		cleared := notification
+		cleared.ID = uuid.New()
+		cleared.Conditions = datatypes.JSON(coolConditions)
+
+		mockDeviceRepo.On("FindByID", deviceID).Return(device, nil)
+		mockNotifRepo.On("FindActiveByUserID", userID).Return([]models.Notification{cleared}, nil)
+		alertRepo.On("Resolve", deviceID, []uuid.UUID{cleared.ID}, mock.AnythingOfType("time.Time")).Return(int64(1), nil)
+
+		err := service.CheckHeartbeat(heartbeat)
+
+		assert.NoError(t, err)
+		alertRepo.AssertExpectations(t)
+		mockRedis.AssertNotCalled(t, "Publish", mock.Anything, mock.Anything, mock.Anything)
+	})
+
 	t.Run("Error - Device not found", func(t *testing.T) {
 		mockNotifRepo := new(MockNotificationRepository)
 		mockDeviceRepo := new(MockDeviceRepository)
 		mockRedis := new(MockRedisPublisher)
-		service := NewNotificationService(mockNotifRepo, mockDeviceRepo, mockRedis)
+		service := NewNotificationService(mockNotifRepo, mockDeviceRepo, new(MockHeartbeatRepository), noMaintenanceWindows(), noMutes(), noNotificationChannels(), noAlerts(), noEscalationPolicies(), defaultPreferences(mockRedis, noNotificationChannels()), NewAuthorizer(new(MockOrganizationRepository), noDeviceShares()))
 
 		mockDeviceRepo.On("FindByID", deviceID).Return((*models.Device)(nil), gorm.ErrRecordNotFound)
 
@@ -307,7 +756,7 @@ func TestNotificationService_CheckHeartbeat(t *testing.T) {
 		mockNotifRepo := new(MockNotificationRepository)
 		mockDeviceRepo := new(MockDeviceRepository)
 		mockRedis := new(MockRedisPublisher)
-		service := NewNotificationService(mockNotifRepo, mockDeviceRepo, mockRedis)
+		service := NewNotificationService(mockNotifRepo, mockDeviceRepo, new(MockHeartbeatRepository), noMaintenanceWindows(), noMutes(), noNotificationChannels(), noAlerts(), noEscalationPolicies(), defaultPreferences(mockRedis, noNotificationChannels()), NewAuthorizer(new(MockOrganizationRepository), noDeviceShares()))
 
 		mockDeviceRepo.On("FindByID", deviceID).Return((*models.Device)(nil), errors.New("database error"))
 
@@ -323,7 +772,7 @@ func TestNotificationService_CheckHeartbeat(t *testing.T) {
 		mockNotifRepo := new(MockNotificationRepository)
 		mockDeviceRepo := new(MockDeviceRepository)
 		mockRedis := new(MockRedisPublisher)
-		service := NewNotificationService(mockNotifRepo, mockDeviceRepo, mockRedis)
+		service := NewNotificationService(mockNotifRepo, mockDeviceRepo, new(MockHeartbeatRepository), noMaintenanceWindows(), noMutes(), noNotificationChannels(), noAlerts(), noEscalationPolicies(), defaultPreferences(mockRedis, noNotificationChannels()), NewAuthorizer(new(MockOrganizationRepository), noDeviceShares()))
 
 		mockDeviceRepo.On("FindByID", deviceID).Return(device, nil)
 		mockNotifRepo.On("FindActiveByUserID", userID).Return(([]models.Notification)(nil), errors.New("database error"))
@@ -341,7 +790,7 @@ func TestNotificationService_CheckHeartbeat(t *testing.T) {
 		mockNotifRepo := new(MockNotificationRepository)
 		mockDeviceRepo := new(MockDeviceRepository)
 		mockRedis := new(MockRedisPublisher)
-		service := NewNotificationService(mockNotifRepo, mockDeviceRepo, mockRedis)
+		service := NewNotificationService(mockNotifRepo, mockDeviceRepo, new(MockHeartbeatRepository), noMaintenanceWindows(), noMutes(), noNotificationChannels(), noAlerts(), noEscalationPolicies(), defaultPreferences(mockRedis, noNotificationChannels()), NewAuthorizer(new(MockOrganizationRepository), noDeviceShares()))
 
 		conditionsJSON, _ := json.Marshal([]dto.NotificationCondition{
 			{Parameter: "cpu", Operator: "<", Value: 50.0},
@@ -365,7 +814,7 @@ func TestNotificationService_CheckHeartbeat(t *testing.T) {
 		mockNotifRepo := new(MockNotificationRepository)
 		mockDeviceRepo := new(MockDeviceRepository)
 		mockRedis := new(MockRedisPublisher)
-		service := NewNotificationService(mockNotifRepo, mockDeviceRepo, mockRedis)
+		service := NewNotificationService(mockNotifRepo, mockDeviceRepo, new(MockHeartbeatRepository), noMaintenanceWindows(), noMutes(), noNotificationChannels(), noAlerts(), noEscalationPolicies(), defaultPreferences(mockRedis, noNotificationChannels()), NewAuthorizer(new(MockOrganizationRepository), noDeviceShares()))
 
 		otherDeviceID := uuid.New()
 		deviceIDsJSON, _ := json.Marshal([]uuid.UUID{otherDeviceID})
@@ -389,7 +838,7 @@ t.Run("Error - Redis publish error", func(t *testing.T) {
     mockNotifRepo := new(MockNotificationRepository)
     mockDeviceRepo := new(MockDeviceRepository)
     mockRedis := new(MockRedisPublisher)
-    service := NewNotificationService(mockNotifRepo, mockDeviceRepo, mockRedis)
+    service := NewNotificationService(mockNotifRepo, mockDeviceRepo, new(MockHeartbeatRepository), noMaintenanceWindows(), noMutes(), noNotificationChannels(), noAlerts(), noEscalationPolicies(), defaultPreferences(mockRedis, noNotificationChannels()), NewAuthorizer(new(MockOrganizationRepository), noDeviceShares()))
 
     conditionsJSON, _ := json.Marshal([]dto.NotificationCondition{
         {Parameter: "cpu", Operator: ">", Value: 80.0},
@@ -420,17 +869,107 @@ t.Run("Error - Redis publish error", func(t *testing.T) {
 })
 }
 
+func TestNotificationService_PreviewTemplate(t *testing.T) {
+	userID := uuid.New()
+	conditions := []dto.NotificationCondition{{Parameter: "cpu", Operator: ">", Value: 80.0}}
+
+	newService := func() (NotificationService, *MockDeviceRepository) {
+		mockNotifRepo := new(MockNotificationRepository)
+		mockDeviceRepo := new(MockDeviceRepository)
+		mockRedis := new(MockRedisPublisher)
+		return NewNotificationService(mockNotifRepo, mockDeviceRepo, new(MockHeartbeatRepository), noMaintenanceWindows(), noMutes(), noNotificationChannels(), noAlerts(), noEscalationPolicies(), defaultPreferences(mockRedis, noNotificationChannels()), NewAuthorizer(new(MockOrganizationRepository), noDeviceShares())), mockDeviceRepo
+	}
+
+	t.Run("Success - Rendered with the sample device and heartbeat", func(t *testing.T) {
+		service, _ := newService()
+
+		preview, err := service.PreviewTemplate(userID, dto.TemplatePreviewRequest{
+			Name:          "High CPU",
+			Conditions:    conditions,
+			TitleTemplate: "[{{.Rule.Severity}}] {{.Rule.Name}}\non {{.Device.Name}}",
+			BodyTemplate:  "CPU {{round 1 .TriggeredValue}}%",
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "[warning] High CPU on Sample device", preview.Title)
+		assert.Equal(t, "CPU 92.5%", preview.Body)
+		assert.True(t, preview.Matched)
+		assert.Len(t, preview.Conditions, 1)
+	})
+
+	t.Run("Success - Heartbeat override that does not match", func(t *testing.T) {
+		service, _ := newService()
+
+		preview, err := service.PreviewTemplate(userID, dto.TemplatePreviewRequest{
+			Conditions:   conditions,
+			BodyTemplate: "{{range .Conditions}}{{.Actual}}{{end}}",
+			Heartbeat:    &dto.AlertHeartbeatData{CPU: 40},
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "40", preview.Body)
+		assert.False(t, preview.Matched)
+		assert.False(t, preview.Conditions[0].Matched)
+	})
+
+	t.Run("Success - Rendered with a device of the user", func(t *testing.T) {
+		service, mockDeviceRepo := newService()
+		device := &models.Device{UUID: uuid.New(), UserID: userID, Name: "Gateway", Labels: models.Labels{"site": "lab"}}
+		mockDeviceRepo.On("FindByID", device.UUID).Return(device, nil)
+
+		preview, err := service.PreviewTemplate(userID, dto.TemplatePreviewRequest{
+			Conditions:    conditions,
+			TitleTemplate: "{{.Device.Name}} at {{index .Device.Labels \"site\"}}",
+			DeviceID:      &device.UUID,
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "Gateway at lab", preview.Title)
+	})
+
+	t.Run("Error - Device of another user", func(t *testing.T) {
+		service, mockDeviceRepo := newService()
+		device := &models.Device{UUID: uuid.New(), UserID: uuid.New()}
+		mockDeviceRepo.On("FindByID", device.UUID).Return(device, nil)
+
+		_, err := service.PreviewTemplate(userID, dto.TemplatePreviewRequest{TitleTemplate: "{{.Device.Name}}", DeviceID: &device.UUID})
+
+		assert.Error(t, err)
+	})
+
+	t.Run("Error - No template", func(t *testing.T) {
+		service, _ := newService()
+
+		_, err := service.PreviewTemplate(userID, dto.TemplatePreviewRequest{Conditions: conditions})
+
+		assert.IsType(t, &custom_errors.BusinessError{}, err)
+	})
+
+	t.Run("Error - Template does not parse", func(t *testing.T) {
+		service, _ := newService()
+
+		_, err := service.PreviewTemplate(userID, dto.TemplatePreviewRequest{TitleTemplate: "{{.Rule.Name"})
+
+		assert.IsType(t, &custom_errors.BusinessError{}, err)
+		assert.Contains(t, err.Error(), "title_template")
+	})
+}
+
 func TestAppliesToDevice(t *testing.T) {
 	service := &notificationService{}
 	deviceID := uuid.New()
 	otherDeviceID := uuid.New()
+	device := &models.Device{
+		UUID:   deviceID,
+		Labels: models.Labels{"env": "prod", "site": "sp"},
+	}
 
 	t.Run("Applies to all devices (empty device IDs)", func(t *testing.T) {
 		deviceIDsJSON, _ := json.Marshal([]uuid.UUID{})
 		notification := models.Notification{
 			DeviceIDs: datatypes.JSON(deviceIDsJSON),
 		}
-		result := service.appliesToDevice(notification, deviceID)
+		result := service.appliesToDevice(notification, device)
 		assert.True(t, result)
 	})
 
@@ -440,7 +979,7 @@ func TestAppliesToDevice(t *testing.T) {
 		notification := models.Notification{
 			DeviceIDs: datatypes.JSON(deviceIDsJSON),
 		}
-		result := service.appliesToDevice(notification, deviceID)
+		result := service.appliesToDevice(notification, device)
 		assert.True(t, result)
 	})
 
@@ -450,7 +989,7 @@ func TestAppliesToDevice(t *testing.T) {
 		notification := models.Notification{
 			DeviceIDs: datatypes.JSON(deviceIDsJSON),
 		}
-		result := service.appliesToDevice(notification, deviceID)
+		result := service.appliesToDevice(notification, device)
 		assert.False(t, result)
 	})
 
@@ -458,9 +997,37 @@ func TestAppliesToDevice(t *testing.T) {
 		notification := models.Notification{
 			DeviceIDs: datatypes.JSON("invalid json"),
 		}
-		result := service.appliesToDevice(notification, deviceID)
+		result := service.appliesToDevice(notification, device)
+		assert.False(t, result)
+	})
+
+	t.Run("Applies to device matching label selector", func(t *testing.T) {
+		notification := models.Notification{
+			DeviceIDs:     datatypes.JSON("null"),
+			LabelSelector: "env=prod,site=sp",
+		}
+		result := service.appliesToDevice(notification, device)
+		assert.True(t, result)
+	})
+
+	t.Run("Does not apply to device not matching label selector", func(t *testing.T) {
+		notification := models.Notification{
+			DeviceIDs:     datatypes.JSON("null"),
+			LabelSelector: "env=prod,site!=sp",
+		}
+		result := service.appliesToDevice(notification, device)
 		assert.False(t, result)
 	})
+
+	t.Run("Applies to listed device even if selector does not match", func(t *testing.T) {
+		deviceIDsJSON, _ := json.Marshal([]uuid.UUID{deviceID})
+		notification := models.Notification{
+			DeviceIDs:     datatypes.JSON(deviceIDsJSON),
+			LabelSelector: "env=staging",
+		}
+		result := service.appliesToDevice(notification, device)
+		assert.True(t, result)
+	})
 }
 
 func TestCheckCondition(t *testing.T) {
@@ -525,6 +1092,24 @@ func TestCheckCondition(t *testing.T) {
 	})
 }
 
+func TestTriggeredValue(t *testing.T) {
+	t.Run("First matched condition", func(t *testing.T) {
+		value := triggeredValue([]dto.ConditionResult{
+			{Parameter: "cpu", Actual: 40},
+			{Parameter: "temperature", Actual: 81, Matched: true},
+		})
+		assert.Equal(t, 81.0, value)
+	})
+
+	t.Run("First condition when none matched", func(t *testing.T) {
+		assert.Equal(t, 40.0, triggeredValue([]dto.ConditionResult{{Parameter: "cpu", Actual: 40}}))
+	})
+
+	t.Run("No conditions", func(t *testing.T) {
+		assert.Equal(t, 0.0, triggeredValue(nil))
+	})
+}
+
 func TestIsValidParameter(t *testing.T) {
 	t.Run("Valid parameters", func(t *testing.T) {
 		validParams := []string{"cpu", "ram", "disk_free", "temperature", "latency", "connectivity"}
@@ -0,0 +1,338 @@
+package services
+
+import (
+	"strings"
+	"time"
+
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/dto"
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/models"
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/repository"
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/utils/errors"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type OrganizationService interface {
+	CreateOrganization(userID uuid.UUID, name string) (*dto.OrganizationResponse, error)
+	ListOrganizations(userID uuid.UUID) ([]dto.OrganizationResponse, error)
+	GetOrganization(userID, orgID uuid.UUID) (*dto.OrganizationResponse, error)
+	ListMembers(userID, orgID uuid.UUID) ([]dto.OrganizationMemberResponse, error)
+	AddMember(userID, orgID uuid.UUID, email, role string) (*dto.OrganizationMemberResponse, error)
+	UpdateMemberRole(userID, orgID, memberID uuid.UUID, role string) error
+	RemoveMember(userID, orgID, memberID uuid.UUID) error
+	IssueToken(userID, orgID uuid.UUID) (string, error)
+	MoveDevices(userID, orgID uuid.UUID, deviceIDs []uuid.UUID) error
+}
+
+type organizationService struct {
+	orgRepo    repository.OrganizationRepository
+	userRepo   userRepository
+	deviceRepo repository.DeviceRepository
+	jwtService JWTService
+	authz      Authorizer
+}
+
+func NewOrganizationService(orgRepo repository.OrganizationRepository, userRepo userRepository, deviceRepo repository.DeviceRepository, jwtService JWTService, authz Authorizer) OrganizationService {
+	return &organizationService{
+		orgRepo:    orgRepo,
+		userRepo:   userRepo,
+		deviceRepo: deviceRepo,
+		jwtService: jwtService,
+		authz:      authz,
+	}
+}
+
+// CreateOrganization creates an organization whose first owner is userID.
+func (s *organizationService) CreateOrganization(userID uuid.UUID, name string) (*dto.OrganizationResponse, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, errors.NewValidationError("Organization name is required")
+	}
+
+	now := time.Now()
+	org := &models.Organization{
+		ID:        uuid.New(),
+		Name:      name,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	owner := &models.Membership{
+		ID:             uuid.New(),
+		OrganizationID: org.ID,
+		UserID:         userID,
+		Role:           models.RoleOwner,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+
+	if err := s.orgRepo.Create(org, owner); err != nil {
+		return nil, errors.ErrDatabaseError
+	}
+	return organizationResponse(org, models.RoleOwner), nil
+}
+
+func (s *organizationService) ListOrganizations(userID uuid.UUID) ([]dto.OrganizationResponse, error) {
+	memberships, err := s.orgRepo.FindMembershipsByUserID(userID)
+	if err != nil {
+		return nil, errors.ErrDatabaseError
+	}
+
+	orgs := make([]dto.OrganizationResponse, 0, len(memberships))
+	for _, membership := range memberships {
+		org, err := s.orgRepo.FindByID(membership.OrganizationID)
+		if err != nil {
+			return nil, errors.ErrDatabaseError
+		}
+		orgs = append(orgs, *organizationResponse(org, membership.Role))
+	}
+	return orgs, nil
+}
+
+func (s *organizationService) GetOrganization(userID, orgID uuid.UUID) (*dto.OrganizationResponse, error) {
+	org, membership, err := s.member(userID, orgID, models.RoleViewer)
+	if err != nil {
+		return nil, err
+	}
+	return organizationResponse(org, membership.Role), nil
+}
+
+func (s *organizationService) ListMembers(userID, orgID uuid.UUID) ([]dto.OrganizationMemberResponse, error) {
+	if _, _, err := s.member(userID, orgID, models.RoleViewer); err != nil {
+		return nil, err
+	}
+
+	memberships, err := s.orgRepo.FindMembers(orgID)
+	if err != nil {
+		return nil, errors.ErrDatabaseError
+	}
+
+	members := make([]dto.OrganizationMemberResponse, 0, len(memberships))
+	for _, membership := range memberships {
+		members = append(members, dto.OrganizationMemberResponse{
+			UserID:    membership.UserID,
+			Email:     membership.User.Email,
+			Role:      membership.Role,
+			CreatedAt: membership.CreatedAt,
+		})
+	}
+	return members, nil
+}
+
+// AddMember adds an existing user to the organization. Admins can grant any
+// role up to their own.
+func (s *organizationService) AddMember(userID, orgID uuid.UUID, email, role string) (*dto.OrganizationMemberResponse, error) {
+	_, caller, err := s.member(userID, orgID, models.RoleAdmin)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkGrantableRole(caller.Role, role); err != nil {
+		return nil, err
+	}
+
+	user, err := s.userRepo.FindByEmail(strings.TrimSpace(email))
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.ErrUserNotFound
+		}
+		return nil, errors.ErrFailedToCheckUser
+	}
+
+	_, err = s.orgRepo.FindMembership(orgID, user.ID)
+	if err == nil {
+		return nil, errors.ErrMemberAlreadyExists
+	}
+	if err != gorm.ErrRecordNotFound {
+		return nil, errors.ErrDatabaseError
+	}
+
+	now := time.Now()
+	membership := &models.Membership{
+		ID:             uuid.New(),
+		OrganizationID: orgID,
+		UserID:         user.ID,
+		Role:           role,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+	if err := s.orgRepo.CreateMembership(membership); err != nil {
+		return nil, errors.ErrDatabaseError
+	}
+
+	return &dto.OrganizationMemberResponse{
+		UserID:    user.ID,
+		Email:     user.Email,
+		Role:      role,
+		CreatedAt: membership.CreatedAt,
+	}, nil
+}
+
+// UpdateMemberRole changes the role of a member. Admins can only manage
+// members whose current and new roles do not exceed their own, and the last
+// owner cannot be demoted.
+func (s *organizationService) UpdateMemberRole(userID, orgID, memberID uuid.UUID, role string) error {
+	_, caller, err := s.member(userID, orgID, models.RoleAdmin)
+	if err != nil {
+		return err
+	}
+	if err := checkGrantableRole(caller.Role, role); err != nil {
+		return err
+	}
+
+	target, err := s.findMember(orgID, memberID)
+	if err != nil {
+		return err
+	}
+	if !models.RoleAtLeast(caller.Role, target.Role) {
+		return errors.ErrForbidden
+	}
+	if target.Role == models.RoleOwner && role != models.RoleOwner {
+		if err := s.checkNotLastOwner(orgID); err != nil {
+			return err
+		}
+	}
+
+	if err := s.orgRepo.UpdateMembershipRole(orgID, memberID, role); err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return errors.ErrMembershipNotFound
+		}
+		return errors.ErrDatabaseError
+	}
+	return nil
+}
+
+// RemoveMember removes a member from the organization. Any member may leave;
+// removing someone else requires the admin role and a role at least as high
+// as theirs. The last owner can neither leave nor be removed.
+func (s *organizationService) RemoveMember(userID, orgID, memberID uuid.UUID) error {
+	required := models.RoleAdmin
+	if memberID == userID {
+		required = models.RoleViewer
+	}
+	_, caller, err := s.member(userID, orgID, required)
+	if err != nil {
+		return err
+	}
+
+	target, err := s.findMember(orgID, memberID)
+	if err != nil {
+		return err
+	}
+	if !models.RoleAtLeast(caller.Role, target.Role) {
+		return errors.ErrForbidden
+	}
+	if target.Role == models.RoleOwner {
+		if err := s.checkNotLastOwner(orgID); err != nil {
+			return err
+		}
+	}
+
+	if err := s.orgRepo.DeleteMembership(orgID, memberID); err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return errors.ErrMembershipNotFound
+		}
+		return errors.ErrDatabaseError
+	}
+	return nil
+}
+
+// IssueToken returns a token that carries the organization and the user's
+// role in it. Resources created with this token belong to the organization.
+func (s *organizationService) IssueToken(userID, orgID uuid.UUID) (string, error) {
+	_, membership, err := s.member(userID, orgID, models.RoleViewer)
+	if err != nil {
+		return "", err
+	}
+	return s.jwtService.GenerateOrganizationToken(userID, orgID, membership.Role)
+}
+
+// MoveDevices hands personal devices of the caller over to the organization.
+func (s *organizationService) MoveDevices(userID, orgID uuid.UUID, deviceIDs []uuid.UUID) error {
+	if len(deviceIDs) == 0 {
+		return errors.NewValidationError("At least one device ID is required")
+	}
+	if _, _, err := s.member(userID, orgID, models.RoleAdmin); err != nil {
+		return err
+	}
+
+	for _, deviceID := range deviceIDs {
+		device, err := s.deviceRepo.FindByID(deviceID)
+		if err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return errors.ErrDeviceNotFound
+			}
+			return errors.ErrDatabaseError
+		}
+		if device.OrganizationID != nil || device.UserID != userID {
+			return errors.ErrForbidden
+		}
+	}
+
+	if err := s.deviceRepo.SetOrganization(deviceIDs, orgID); err != nil {
+		return errors.ErrDatabaseError
+	}
+	return nil
+}
+
+// member loads the organization and checks through the authorizer that
+// userID holds at least role in it, returning the caller's membership.
+func (s *organizationService) member(userID, orgID uuid.UUID, role string) (*models.Organization, *models.Membership, error) {
+	org, err := s.orgRepo.FindByID(orgID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil, errors.ErrOrganizationNotFound
+		}
+		return nil, nil, errors.ErrDatabaseError
+	}
+
+	if err := s.authz.Authorize(userID, Ownership{OrganizationID: &orgID}, role); err != nil {
+		return nil, nil, err
+	}
+
+	membership, err := s.orgRepo.FindMembership(orgID, userID)
+	if err != nil {
+		return nil, nil, errors.ErrDatabaseError
+	}
+	return org, membership, nil
+}
+
+func (s *organizationService) findMember(orgID, memberID uuid.UUID) (*models.Membership, error) {
+	membership, err := s.orgRepo.FindMembership(orgID, memberID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.ErrMembershipNotFound
+		}
+		return nil, errors.ErrDatabaseError
+	}
+	return membership, nil
+}
+
+func (s *organizationService) checkNotLastOwner(orgID uuid.UUID) error {
+	owners, err := s.orgRepo.CountMembersWithRole(orgID, models.RoleOwner)
+	if err != nil {
+		return errors.ErrDatabaseError
+	}
+	if owners <= 1 {
+		return errors.ErrLastOwner
+	}
+	return nil
+}
+
+func checkGrantableRole(callerRole, role string) error {
+	if !models.ValidRole(role) {
+		return errors.NewValidationError("Role must be one of owner, admin, operator, viewer")
+	}
+	if !models.RoleAtLeast(callerRole, role) {
+		return errors.ErrForbidden
+	}
+	return nil
+}
+
+func organizationResponse(org *models.Organization, role string) *dto.OrganizationResponse {
+	return &dto.OrganizationResponse{
+		ID:        org.ID,
+		Name:      org.Name,
+		Role:      role,
+		CreatedAt: org.CreatedAt,
+		UpdatedAt: org.UpdatedAt,
+	}
+}
@@ -0,0 +1,359 @@
+package services
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/models"
+	custom_errors "github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/utils/errors"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+type MockOrganizationRepository struct {
+	mock.Mock
+}
+
+func (m *MockOrganizationRepository) Create(organization *models.Organization, owner *models.Membership) error {
+	args := m.Called(organization, owner)
+	return args.Error(0)
+}
+
+func (m *MockOrganizationRepository) FindByID(id uuid.UUID) (*models.Organization, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Organization), args.Error(1)
+}
+
+func (m *MockOrganizationRepository) FindMembershipsByUserID(userID uuid.UUID) ([]models.Membership, error) {
+	args := m.Called(userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Membership), args.Error(1)
+}
+
+func (m *MockOrganizationRepository) FindMembership(organizationID, userID uuid.UUID) (*models.Membership, error) {
+	args := m.Called(organizationID, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Membership), args.Error(1)
+}
+
+func (m *MockOrganizationRepository) FindMembers(organizationID uuid.UUID) ([]models.Membership, error) {
+	args := m.Called(organizationID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Membership), args.Error(1)
+}
+
+func (m *MockOrganizationRepository) CreateMembership(membership *models.Membership) error {
+	args := m.Called(membership)
+	return args.Error(0)
+}
+
+func (m *MockOrganizationRepository) UpdateMembershipRole(organizationID, userID uuid.UUID, role string) error {
+	args := m.Called(organizationID, userID, role)
+	return args.Error(0)
+}
+
+func (m *MockOrganizationRepository) DeleteMembership(organizationID, userID uuid.UUID) error {
+	args := m.Called(organizationID, userID)
+	return args.Error(0)
+}
+
+func (m *MockOrganizationRepository) CountMembersWithRole(organizationID uuid.UUID, role string) (int64, error) {
+	args := m.Called(organizationID, role)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func newTestOrganizationService() (OrganizationService, *MockOrganizationRepository, *MockUserRepository, *MockDeviceRepository, *MockJWTService) {
+	orgRepo := new(MockOrganizationRepository)
+	userRepo := new(MockUserRepository)
+	deviceRepo := new(MockDeviceRepository)
+	jwtService := new(MockJWTService)
+	return NewOrganizationService(orgRepo, userRepo, deviceRepo, jwtService, NewAuthorizer(orgRepo, noDeviceShares())), orgRepo, userRepo, deviceRepo, jwtService
+}
+
+// expectMember stubs the lookups performed when userID acts inside org.
+func expectMember(orgRepo *MockOrganizationRepository, org *models.Organization, userID uuid.UUID, role string) {
+	orgRepo.On("FindByID", org.ID).Return(org, nil)
+	orgRepo.On("FindMembership", org.ID, userID).Return(&models.Membership{OrganizationID: org.ID, UserID: userID, Role: role}, nil)
+}
+
+func TestOrganizationService_CreateOrganization(t *testing.T) {
+	userID := uuid.New()
+
+	t.Run("Success - Creator becomes owner", func(t *testing.T) {
+		service, orgRepo, _, _, _ := newTestOrganizationService()
+		orgRepo.On("Create", mock.AnythingOfType("*models.Organization"), mock.MatchedBy(func(m *models.Membership) bool {
+			return m.UserID == userID && m.Role == models.RoleOwner
+		})).Return(nil)
+
+		org, err := service.CreateOrganization(userID, " Acme ")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "Acme", org.Name)
+		assert.Equal(t, models.RoleOwner, org.Role)
+		orgRepo.AssertExpectations(t)
+	})
+
+	t.Run("Error - Empty name", func(t *testing.T) {
+		service, orgRepo, _, _, _ := newTestOrganizationService()
+
+		org, err := service.CreateOrganization(userID, "  ")
+
+		assert.Nil(t, org)
+		assert.Error(t, err)
+		orgRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+}
+
+func TestOrganizationService_GetOrganization(t *testing.T) {
+	userID := uuid.New()
+	org := &models.Organization{ID: uuid.New(), Name: "Acme"}
+
+	t.Run("Success - Member sees organization with role", func(t *testing.T) {
+		service, orgRepo, _, _, _ := newTestOrganizationService()
+		expectMember(orgRepo, org, userID, models.RoleViewer)
+
+		result, err := service.GetOrganization(userID, org.ID)
+
+		assert.NoError(t, err)
+		assert.Equal(t, models.RoleViewer, result.Role)
+	})
+
+	t.Run("Error - Not a member", func(t *testing.T) {
+		service, orgRepo, _, _, _ := newTestOrganizationService()
+		orgRepo.On("FindByID", org.ID).Return(org, nil)
+		orgRepo.On("FindMembership", org.ID, userID).Return(nil, gorm.ErrRecordNotFound)
+
+		result, err := service.GetOrganization(userID, org.ID)
+
+		assert.Nil(t, result)
+		assert.Equal(t, custom_errors.ErrForbidden, err)
+	})
+
+	t.Run("Error - Organization not found", func(t *testing.T) {
+		service, orgRepo, _, _, _ := newTestOrganizationService()
+		orgRepo.On("FindByID", org.ID).Return(nil, gorm.ErrRecordNotFound)
+
+		result, err := service.GetOrganization(userID, org.ID)
+
+		assert.Nil(t, result)
+		assert.Equal(t, custom_errors.ErrOrganizationNotFound, err)
+	})
+}
+
+func TestOrganizationService_AddMember(t *testing.T) {
+	userID := uuid.New()
+	newcomer := &models.User{ID: uuid.New(), Email: "new@example.com"}
+	org := &models.Organization{ID: uuid.New(), Name: "Acme"}
+
+	t.Run("Success - Admin adds operator", func(t *testing.T) {
+		service, orgRepo, userRepo, _, _ := newTestOrganizationService()
+		expectMember(orgRepo, org, userID, models.RoleAdmin)
+		userRepo.On("FindByEmail", newcomer.Email).Return(newcomer, nil)
+		orgRepo.On("FindMembership", org.ID, newcomer.ID).Return(nil, gorm.ErrRecordNotFound)
+		orgRepo.On("CreateMembership", mock.AnythingOfType("*models.Membership")).Return(nil)
+
+		member, err := service.AddMember(userID, org.ID, newcomer.Email, models.RoleOperator)
+
+		assert.NoError(t, err)
+		assert.Equal(t, newcomer.ID, member.UserID)
+		assert.Equal(t, models.RoleOperator, member.Role)
+		orgRepo.AssertExpectations(t)
+	})
+
+	t.Run("Error - Admin cannot grant owner", func(t *testing.T) {
+		service, orgRepo, userRepo, _, _ := newTestOrganizationService()
+		expectMember(orgRepo, org, userID, models.RoleAdmin)
+
+		member, err := service.AddMember(userID, org.ID, newcomer.Email, models.RoleOwner)
+
+		assert.Nil(t, member)
+		assert.Equal(t, custom_errors.ErrForbidden, err)
+		userRepo.AssertNotCalled(t, "FindByEmail", mock.Anything)
+	})
+
+	t.Run("Error - Operator cannot add members", func(t *testing.T) {
+		service, orgRepo, _, _, _ := newTestOrganizationService()
+		expectMember(orgRepo, org, userID, models.RoleOperator)
+
+		member, err := service.AddMember(userID, org.ID, newcomer.Email, models.RoleViewer)
+
+		assert.Nil(t, member)
+		assert.Equal(t, custom_errors.ErrForbidden, err)
+	})
+
+	t.Run("Error - Invalid role", func(t *testing.T) {
+		service, orgRepo, _, _, _ := newTestOrganizationService()
+		expectMember(orgRepo, org, userID, models.RoleOwner)
+
+		member, err := service.AddMember(userID, org.ID, newcomer.Email, "superuser")
+
+		assert.Nil(t, member)
+		assert.Equal(t, 400, err.(*custom_errors.BusinessError).StatusCode())
+	})
+
+	t.Run("Error - Already a member", func(t *testing.T) {
+		service, orgRepo, userRepo, _, _ := newTestOrganizationService()
+		expectMember(orgRepo, org, userID, models.RoleAdmin)
+		userRepo.On("FindByEmail", newcomer.Email).Return(newcomer, nil)
+		orgRepo.On("FindMembership", org.ID, newcomer.ID).Return(&models.Membership{Role: models.RoleViewer}, nil)
+
+		member, err := service.AddMember(userID, org.ID, newcomer.Email, models.RoleViewer)
+
+		assert.Nil(t, member)
+		assert.Equal(t, custom_errors.ErrMemberAlreadyExists, err)
+		orgRepo.AssertNotCalled(t, "CreateMembership", mock.Anything)
+	})
+}
+
+func TestOrganizationService_UpdateMemberRole(t *testing.T) {
+	userID := uuid.New()
+	memberID := uuid.New()
+	org := &models.Organization{ID: uuid.New(), Name: "Acme"}
+
+	t.Run("Success - Demote one of several owners", func(t *testing.T) {
+		service, orgRepo, _, _, _ := newTestOrganizationService()
+		expectMember(orgRepo, org, userID, models.RoleOwner)
+		orgRepo.On("FindMembership", org.ID, memberID).Return(&models.Membership{UserID: memberID, Role: models.RoleOwner}, nil)
+		orgRepo.On("CountMembersWithRole", org.ID, models.RoleOwner).Return(int64(2), nil)
+		orgRepo.On("UpdateMembershipRole", org.ID, memberID, models.RoleAdmin).Return(nil)
+
+		err := service.UpdateMemberRole(userID, org.ID, memberID, models.RoleAdmin)
+
+		assert.NoError(t, err)
+		orgRepo.AssertExpectations(t)
+	})
+
+	t.Run("Error - Last owner cannot be demoted", func(t *testing.T) {
+		service, orgRepo, _, _, _ := newTestOrganizationService()
+		expectMember(orgRepo, org, userID, models.RoleOwner)
+		orgRepo.On("CountMembersWithRole", org.ID, models.RoleOwner).Return(int64(1), nil)
+
+		err := service.UpdateMemberRole(userID, org.ID, userID, models.RoleAdmin)
+
+		assert.Equal(t, custom_errors.ErrLastOwner, err)
+		orgRepo.AssertNotCalled(t, "UpdateMembershipRole", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Error - Admin cannot manage an owner", func(t *testing.T) {
+		service, orgRepo, _, _, _ := newTestOrganizationService()
+		expectMember(orgRepo, org, userID, models.RoleAdmin)
+		orgRepo.On("FindMembership", org.ID, memberID).Return(&models.Membership{UserID: memberID, Role: models.RoleOwner}, nil)
+
+		err := service.UpdateMemberRole(userID, org.ID, memberID, models.RoleViewer)
+
+		assert.Equal(t, custom_errors.ErrForbidden, err)
+	})
+
+	t.Run("Error - Member not found", func(t *testing.T) {
+		service, orgRepo, _, _, _ := newTestOrganizationService()
+		expectMember(orgRepo, org, userID, models.RoleAdmin)
+		orgRepo.On("FindMembership", org.ID, memberID).Return(nil, gorm.ErrRecordNotFound)
+
+		err := service.UpdateMemberRole(userID, org.ID, memberID, models.RoleViewer)
+
+		assert.Equal(t, custom_errors.ErrMembershipNotFound, err)
+	})
+}
+
+func TestOrganizationService_RemoveMember(t *testing.T) {
+	userID := uuid.New()
+	memberID := uuid.New()
+	org := &models.Organization{ID: uuid.New(), Name: "Acme"}
+
+	t.Run("Success - Viewer leaves", func(t *testing.T) {
+		service, orgRepo, _, _, _ := newTestOrganizationService()
+		expectMember(orgRepo, org, userID, models.RoleViewer)
+		orgRepo.On("DeleteMembership", org.ID, userID).Return(nil)
+
+		err := service.RemoveMember(userID, org.ID, userID)
+
+		assert.NoError(t, err)
+		orgRepo.AssertExpectations(t)
+	})
+
+	t.Run("Error - Viewer cannot remove others", func(t *testing.T) {
+		service, orgRepo, _, _, _ := newTestOrganizationService()
+		expectMember(orgRepo, org, userID, models.RoleViewer)
+
+		err := service.RemoveMember(userID, org.ID, memberID)
+
+		assert.Equal(t, custom_errors.ErrForbidden, err)
+		orgRepo.AssertNotCalled(t, "DeleteMembership", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Error - Last owner cannot leave", func(t *testing.T) {
+		service, orgRepo, _, _, _ := newTestOrganizationService()
+		expectMember(orgRepo, org, userID, models.RoleOwner)
+		orgRepo.On("CountMembersWithRole", org.ID, models.RoleOwner).Return(int64(1), nil)
+
+		err := service.RemoveMember(userID, org.ID, userID)
+
+		assert.Equal(t, custom_errors.ErrLastOwner, err)
+	})
+}
+
+func TestOrganizationService_IssueToken(t *testing.T) {
+	userID := uuid.New()
+	org := &models.Organization{ID: uuid.New(), Name: "Acme"}
+
+	t.Run("Success - Token carries membership role", func(t *testing.T) {
+		service, orgRepo, _, _, jwtService := newTestOrganizationService()
+		expectMember(orgRepo, org, userID, models.RoleOperator)
+		jwtService.On("GenerateOrganizationToken", userID, org.ID, models.RoleOperator).Return("org-token", nil)
+
+		token, err := service.IssueToken(userID, org.ID)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "org-token", token)
+	})
+}
+
+func TestOrganizationService_MoveDevices(t *testing.T) {
+	userID := uuid.New()
+	org := &models.Organization{ID: uuid.New(), Name: "Acme"}
+	deviceID := uuid.New()
+
+	t.Run("Success - Personal device moved", func(t *testing.T) {
+		service, orgRepo, _, deviceRepo, _ := newTestOrganizationService()
+		expectMember(orgRepo, org, userID, models.RoleAdmin)
+		deviceRepo.On("FindByID", deviceID).Return(&models.Device{UUID: deviceID, UserID: userID}, nil)
+		deviceRepo.On("SetOrganization", []uuid.UUID{deviceID}, org.ID).Return(nil)
+
+		err := service.MoveDevices(userID, org.ID, []uuid.UUID{deviceID})
+
+		assert.NoError(t, err)
+		deviceRepo.AssertExpectations(t)
+	})
+
+	t.Run("Error - Device of another user", func(t *testing.T) {
+		service, orgRepo, _, deviceRepo, _ := newTestOrganizationService()
+		expectMember(orgRepo, org, userID, models.RoleAdmin)
+		deviceRepo.On("FindByID", deviceID).Return(&models.Device{UUID: deviceID, UserID: uuid.New()}, nil)
+
+		err := service.MoveDevices(userID, org.ID, []uuid.UUID{deviceID})
+
+		assert.Equal(t, custom_errors.ErrForbidden, err)
+		deviceRepo.AssertNotCalled(t, "SetOrganization", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Error - Database error", func(t *testing.T) {
+		service, orgRepo, _, deviceRepo, _ := newTestOrganizationService()
+		expectMember(orgRepo, org, userID, models.RoleAdmin)
+		deviceRepo.On("FindByID", deviceID).Return(nil, errors.New("db down"))
+
+		err := service.MoveDevices(userID, org.ID, []uuid.UUID{deviceID})
+
+		assert.Equal(t, custom_errors.ErrDatabaseError, err)
+	})
+}
@@ -0,0 +1,243 @@
+package services
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/dto"
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/models"
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/repository"
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/utils/errors"
+	"github.com/google/uuid"
+)
+
+const (
+	ReportGroupByDevice   = "device"
+	ReportGroupByLocation = "location"
+)
+
+type ReportService interface {
+	GetAvailabilityReport(userID uuid.UUID, from, to time.Time, groupBy string) (*dto.AvailabilityReportResponse, error)
+}
+
+type reportService struct {
+	deviceRepo        repository.DeviceRepository
+	heartbeatRepo     repository.HeartbeatRepository
+	shadowRepo        repository.DeviceShadowRepository
+	heartbeatInterval time.Duration
+}
+
+// NewReportService builds the reporting service. heartbeatInterval is the
+// period at which devices are expected to publish heartbeats unless their
+// shadow sets heartbeat_interval_seconds.
+func NewReportService(deviceRepo repository.DeviceRepository, heartbeatRepo repository.HeartbeatRepository, shadowRepo repository.DeviceShadowRepository, heartbeatInterval time.Duration) ReportService {
+	return &reportService{
+		deviceRepo:        deviceRepo,
+		heartbeatRepo:     heartbeatRepo,
+		shadowRepo:        shadowRepo,
+		heartbeatInterval: heartbeatInterval,
+	}
+}
+
+// availabilityStats holds the raw figures for one device over its window.
+type availabilityStats struct {
+	period        time.Duration
+	expected      int64
+	received      int64
+	uptime        time.Duration
+	outages       int
+	longestOutage time.Duration
+}
+
+func (s *reportService) GetAvailabilityReport(userID uuid.UUID, from, to time.Time, groupBy string) (*dto.AvailabilityReportResponse, error) {
+	if !to.After(from) {
+		return nil, errors.NewValidationError("from must be before to")
+	}
+	if groupBy == "" {
+		groupBy = ReportGroupByDevice
+	}
+	if groupBy != ReportGroupByDevice && groupBy != ReportGroupByLocation {
+		return nil, errors.NewValidationError("group_by must be device or location")
+	}
+
+	devices, err := s.deviceRepo.FindByUserID(userID)
+	if err != nil {
+		return nil, errors.ErrDatabaseError
+	}
+
+	deviceIDs := make([]uuid.UUID, 0, len(devices))
+	for _, device := range devices {
+		deviceIDs = append(deviceIDs, device.UUID)
+	}
+	intervals, err := heartbeatIntervals(s.shadowRepo, deviceIDs, s.heartbeatInterval)
+	if err != nil {
+		return nil, errors.ErrDatabaseError
+	}
+
+	items := []dto.AvailabilityReportItem{}
+	locations := map[string]*availabilityStats{}
+	deviceCounts := map[string]int{}
+
+	for _, device := range devices {
+		start := from
+		if device.CreatedAt.After(start) {
+			start = device.CreatedAt
+		}
+		if !to.After(start) {
+			continue
+		}
+
+		samples, err := s.heartbeatRepo.FindConnectivityByDeviceID(device.UUID, start, to)
+		if err != nil {
+			return nil, errors.ErrDatabaseError
+		}
+
+		stats := computeAvailability(samples, start, to, intervals[device.UUID])
+
+		if groupBy == ReportGroupByDevice {
+			deviceID := device.UUID
+			item := buildAvailabilityItem(stats, device.Location, 1)
+			item.DeviceID = &deviceID
+			item.DeviceName = device.Name
+			item.DeviceSN = device.SN
+			items = append(items, item)
+			continue
+		}
+
+		agg, ok := locations[device.Location]
+		if !ok {
+			agg = &availabilityStats{}
+			locations[device.Location] = agg
+		}
+		agg.period += stats.period
+		agg.expected += stats.expected
+		agg.received += stats.received
+		agg.uptime += stats.uptime
+		agg.outages += stats.outages
+		if stats.longestOutage > agg.longestOutage {
+			agg.longestOutage = stats.longestOutage
+		}
+		deviceCounts[device.Location]++
+	}
+
+	if groupBy == ReportGroupByLocation {
+		for location, agg := range locations {
+			items = append(items, buildAvailabilityItem(*agg, location, deviceCounts[location]))
+		}
+		sort.Slice(items, func(i, j int) bool {
+			return items[i].Location < items[j].Location
+		})
+	}
+
+	return &dto.AvailabilityReportResponse{
+		From:    from,
+		To:      to,
+		GroupBy: groupBy,
+		Items:   items,
+	}, nil
+}
+
+// computeAvailability walks the heartbeats of a device in chronological order.
+// A heartbeat keeps the device in its reported connectivity state until the
+// next one arrives; silences longer than two intervals count as outages, as do
+// heartbeats reporting Connectivity=0.
+func computeAvailability(samples []models.Heartbeat, start, end time.Time, interval time.Duration) availabilityStats {
+	stats := availabilityStats{period: end.Sub(start)}
+	if interval > 0 {
+		stats.expected = int64(stats.period / interval)
+	}
+	grace := 2 * interval
+
+	inOutage := false
+	var outageStart time.Time
+	mark := func(segStart, segEnd time.Time, up bool) {
+		if !segEnd.After(segStart) {
+			return
+		}
+		if !up {
+			if !inOutage {
+				inOutage = true
+				outageStart = segStart
+			}
+			return
+		}
+		stats.uptime += segEnd.Sub(segStart)
+		if inOutage {
+			stats.closeOutage(segStart.Sub(outageStart))
+			inOutage = false
+		}
+	}
+
+	cursor := start
+	up := false
+	seen := false
+	for _, hb := range samples {
+		if hb.CreatedAt.Before(start) || hb.CreatedAt.After(end) {
+			continue
+		}
+		stats.received++
+
+		gap := hb.CreatedAt.Sub(cursor)
+		if !seen {
+			mark(cursor, hb.CreatedAt, gap <= grace && hb.Connectivity == 1)
+		} else {
+			covered := cursor.Add(min(gap, grace))
+			mark(cursor, covered, up)
+			mark(covered, hb.CreatedAt, false)
+		}
+
+		cursor = hb.CreatedAt
+		up = hb.Connectivity == 1
+		seen = true
+	}
+
+	if !seen {
+		mark(start, end, false)
+	} else {
+		covered := cursor.Add(min(end.Sub(cursor), grace))
+		mark(cursor, covered, up)
+		mark(covered, end, false)
+	}
+	if inOutage {
+		stats.closeOutage(end.Sub(outageStart))
+	}
+
+	return stats
+}
+
+func (s *availabilityStats) closeOutage(duration time.Duration) {
+	s.outages++
+	if duration > s.longestOutage {
+		s.longestOutage = duration
+	}
+}
+
+func buildAvailabilityItem(stats availabilityStats, location string, deviceCount int) dto.AvailabilityReportItem {
+	item := dto.AvailabilityReportItem{
+		Location:             location,
+		DeviceCount:          deviceCount,
+		PeriodSeconds:        stats.period.Seconds(),
+		ExpectedHeartbeats:   stats.expected,
+		ReceivedHeartbeats:   stats.received,
+		OutageCount:          stats.outages,
+		LongestOutageSeconds: stats.longestOutage.Seconds(),
+	}
+
+	if stats.expected > 0 {
+		item.HeartbeatPercentage = roundPercentage(math.Min(100, float64(stats.received)/float64(stats.expected)*100))
+	}
+	if stats.period > 0 {
+		item.ConnectivityPercentage = roundPercentage(float64(stats.uptime) / float64(stats.period) * 100)
+	}
+	if stats.outages > 0 {
+		mtbo := stats.uptime.Seconds() / float64(stats.outages)
+		item.MeanTimeBetweenOutagesSeconds = &mtbo
+	}
+
+	return item
+}
+
+func roundPercentage(value float64) float64 {
+	return math.Round(value*100) / 100
+}
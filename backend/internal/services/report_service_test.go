@@ -0,0 +1,213 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/models"
+	custom_errors "github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/utils/errors"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/datatypes"
+)
+
+func heartbeatsEvery(start time.Time, interval time.Duration, connectivity ...int) []models.Heartbeat {
+	heartbeats := make([]models.Heartbeat, 0, len(connectivity))
+	for i, c := range connectivity {
+		heartbeats = append(heartbeats, models.Heartbeat{
+			CreatedAt:    start.Add(time.Duration(i+1) * interval),
+			Connectivity: c,
+		})
+	}
+	return heartbeats
+}
+
+func TestComputeAvailability(t *testing.T) {
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("All heartbeats connected", func(t *testing.T) {
+		end := start.Add(10 * time.Minute)
+		samples := heartbeatsEvery(start, time.Minute, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1)
+
+		stats := computeAvailability(samples, start, end, time.Minute)
+
+		assert.Equal(t, int64(10), stats.expected)
+		assert.Equal(t, int64(10), stats.received)
+		assert.Equal(t, 10*time.Minute, stats.uptime)
+		assert.Equal(t, 0, stats.outages)
+	})
+
+	t.Run("Connectivity loss counts as an outage", func(t *testing.T) {
+		end := start.Add(10 * time.Minute)
+		samples := heartbeatsEvery(start, time.Minute, 1, 1, 0, 0, 0, 1, 1, 1, 1, 1)
+
+		stats := computeAvailability(samples, start, end, time.Minute)
+
+		assert.Equal(t, 1, stats.outages)
+		assert.Equal(t, 3*time.Minute, stats.longestOutage)
+		assert.Equal(t, 7*time.Minute, stats.uptime)
+	})
+
+	t.Run("Missing heartbeats beyond grace period count as an outage", func(t *testing.T) {
+		end := start.Add(20 * time.Minute)
+		samples := []models.Heartbeat{
+			{CreatedAt: start.Add(time.Minute), Connectivity: 1},
+			{CreatedAt: start.Add(2 * time.Minute), Connectivity: 1},
+			{CreatedAt: start.Add(12 * time.Minute), Connectivity: 1},
+			{CreatedAt: start.Add(13 * time.Minute), Connectivity: 1},
+		}
+
+		stats := computeAvailability(samples, start, end, time.Minute)
+
+		assert.Equal(t, int64(4), stats.received)
+		assert.Equal(t, 2, stats.outages)
+		assert.Equal(t, 8*time.Minute, stats.longestOutage)
+		assert.Equal(t, 7*time.Minute, stats.uptime)
+	})
+
+	t.Run("No heartbeats is a single outage", func(t *testing.T) {
+		end := start.Add(time.Hour)
+
+		stats := computeAvailability(nil, start, end, time.Minute)
+
+		assert.Equal(t, int64(0), stats.received)
+		assert.Equal(t, 1, stats.outages)
+		assert.Equal(t, time.Hour, stats.longestOutage)
+		assert.Equal(t, time.Duration(0), stats.uptime)
+	})
+}
+
+func TestReportService_GetAvailabilityReport(t *testing.T) {
+	userID := uuid.New()
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := from.Add(10 * time.Minute)
+
+	t.Run("Success - Group by device", func(t *testing.T) {
+		mockDeviceRepo := new(MockDeviceRepository)
+		mockHeartbeatRepo := new(MockHeartbeatRepository)
+		service := NewReportService(mockDeviceRepo, mockHeartbeatRepo, noShadows(), time.Minute)
+
+		device := models.Device{UUID: uuid.New(), Name: "Device 1", SN: "123456789012", Location: "SP", UserID: userID}
+		mockDeviceRepo.On("FindByUserID", userID).Return([]models.Device{device}, nil)
+		mockHeartbeatRepo.On("FindConnectivityByDeviceID", device.UUID, from, to).
+			Return(heartbeatsEvery(from, time.Minute, 1, 1, 1, 1, 1, 0, 0, 1, 1, 1), nil)
+
+		report, err := service.GetAvailabilityReport(userID, from, to, "")
+
+		assert.NoError(t, err)
+		assert.Equal(t, ReportGroupByDevice, report.GroupBy)
+		assert.Len(t, report.Items, 1)
+		item := report.Items[0]
+		assert.Equal(t, device.UUID, *item.DeviceID)
+		assert.Equal(t, float64(100), item.HeartbeatPercentage)
+		assert.Equal(t, float64(80), item.ConnectivityPercentage)
+		assert.Equal(t, 1, item.OutageCount)
+		assert.Equal(t, float64(120), item.LongestOutageSeconds)
+		assert.Equal(t, float64(480), *item.MeanTimeBetweenOutagesSeconds)
+
+		mockDeviceRepo.AssertExpectations(t)
+		mockHeartbeatRepo.AssertExpectations(t)
+	})
+
+	t.Run("Success - Interval from the device shadow", func(t *testing.T) {
+		mockDeviceRepo := new(MockDeviceRepository)
+		mockHeartbeatRepo := new(MockHeartbeatRepository)
+		mockShadowRepo := new(MockDeviceShadowRepository)
+		service := NewReportService(mockDeviceRepo, mockHeartbeatRepo, mockShadowRepo, time.Minute)
+
+		device := models.Device{UUID: uuid.New(), Location: "SP", UserID: userID}
+		mockDeviceRepo.On("FindByUserID", userID).Return([]models.Device{device}, nil)
+		mockShadowRepo.On("FindByDeviceIDs", []uuid.UUID{device.UUID}).Return([]models.DeviceShadow{{
+			DeviceID: device.UUID,
+			Desired:  datatypes.JSON(`{"heartbeat_interval_seconds": 60}`),
+			Reported: datatypes.JSON(`{"heartbeat_interval_seconds": 120}`),
+		}}, nil)
+		mockHeartbeatRepo.On("FindConnectivityByDeviceID", device.UUID, from, to).
+			Return(heartbeatsEvery(from, 2*time.Minute, 1, 1, 1, 1, 1), nil)
+
+		report, err := service.GetAvailabilityReport(userID, from, to, ReportGroupByDevice)
+
+		assert.NoError(t, err)
+		assert.Len(t, report.Items, 1)
+		assert.Equal(t, float64(100), report.Items[0].HeartbeatPercentage)
+		assert.Equal(t, float64(100), report.Items[0].ConnectivityPercentage)
+		assert.Equal(t, 0, report.Items[0].OutageCount)
+	})
+
+	t.Run("Success - Group by location", func(t *testing.T) {
+		mockDeviceRepo := new(MockDeviceRepository)
+		mockHeartbeatRepo := new(MockHeartbeatRepository)
+		service := NewReportService(mockDeviceRepo, mockHeartbeatRepo, noShadows(), time.Minute)
+
+		devices := []models.Device{
+			{UUID: uuid.New(), Location: "SP", UserID: userID},
+			{UUID: uuid.New(), Location: "SP", UserID: userID},
+			{UUID: uuid.New(), Location: "RJ", UserID: userID},
+		}
+		mockDeviceRepo.On("FindByUserID", userID).Return(devices, nil)
+		mockHeartbeatRepo.On("FindConnectivityByDeviceID", devices[0].UUID, from, to).
+			Return(heartbeatsEvery(from, time.Minute, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1), nil)
+		mockHeartbeatRepo.On("FindConnectivityByDeviceID", devices[1].UUID, from, to).
+			Return([]models.Heartbeat{}, nil)
+		mockHeartbeatRepo.On("FindConnectivityByDeviceID", devices[2].UUID, from, to).
+			Return(heartbeatsEvery(from, time.Minute, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1), nil)
+
+		report, err := service.GetAvailabilityReport(userID, from, to, ReportGroupByLocation)
+
+		assert.NoError(t, err)
+		assert.Len(t, report.Items, 2)
+		assert.Equal(t, "RJ", report.Items[0].Location)
+		assert.Equal(t, float64(100), report.Items[0].ConnectivityPercentage)
+		assert.Equal(t, "SP", report.Items[1].Location)
+		assert.Equal(t, 2, report.Items[1].DeviceCount)
+		assert.Equal(t, float64(50), report.Items[1].HeartbeatPercentage)
+		assert.Equal(t, float64(50), report.Items[1].ConnectivityPercentage)
+		assert.Nil(t, report.Items[1].DeviceID)
+	})
+
+	t.Run("Skips devices created after the period", func(t *testing.T) {
+		mockDeviceRepo := new(MockDeviceRepository)
+		mockHeartbeatRepo := new(MockHeartbeatRepository)
+		service := NewReportService(mockDeviceRepo, mockHeartbeatRepo, noShadows(), time.Minute)
+
+		devices := []models.Device{{UUID: uuid.New(), UserID: userID, CreatedAt: to.Add(time.Hour)}}
+		mockDeviceRepo.On("FindByUserID", userID).Return(devices, nil)
+
+		report, err := service.GetAvailabilityReport(userID, from, to, ReportGroupByDevice)
+
+		assert.NoError(t, err)
+		assert.Empty(t, report.Items)
+		mockHeartbeatRepo.AssertNotCalled(t, "FindConnectivityByDeviceID")
+	})
+
+	t.Run("Error - Invalid time range", func(t *testing.T) {
+		service := NewReportService(new(MockDeviceRepository), new(MockHeartbeatRepository), noShadows(), time.Minute)
+
+		report, err := service.GetAvailabilityReport(userID, to, from, ReportGroupByDevice)
+
+		assert.Nil(t, report)
+		assert.Equal(t, custom_errors.NewValidationError("from must be before to"), err)
+	})
+
+	t.Run("Error - Invalid group by", func(t *testing.T) {
+		service := NewReportService(new(MockDeviceRepository), new(MockHeartbeatRepository), noShadows(), time.Minute)
+
+		report, err := service.GetAvailabilityReport(userID, from, to, "rack")
+
+		assert.Nil(t, report)
+		assert.Equal(t, custom_errors.NewValidationError("group_by must be device or location"), err)
+	})
+
+	t.Run("Error - Database error", func(t *testing.T) {
+		mockDeviceRepo := new(MockDeviceRepository)
+		service := NewReportService(mockDeviceRepo, new(MockHeartbeatRepository), noShadows(), time.Minute)
+
+		mockDeviceRepo.On("FindByUserID", userID).Return(nil, errors.New("db down"))
+
+		report, err := service.GetAvailabilityReport(userID, from, to, ReportGroupByDevice)
+
+		assert.Nil(t, report)
+		assert.Equal(t, custom_errors.ErrDatabaseError, err)
+	})
+}
@@ -0,0 +1,251 @@
+package services
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/dto"
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/models"
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/utils/errors"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	// MaxRuleTestDays caps the period a rule is tested over.
+	MaxRuleTestDays = 31
+	// MaxRuleTestDevices caps the devices a rule is tested on.
+	MaxRuleTestDevices = 100
+	// MaxRuleTestHeartbeats caps the heartbeats a test evaluates; the test
+	// stops there and is marked truncated.
+	MaxRuleTestHeartbeats = 200000
+	// MaxRuleTestTimeline caps the alerts listed in the timeline of a test.
+	MaxRuleTestTimeline = 1000
+)
+
+var errRuleTestLimit = fmt.Errorf("rule test heartbeat limit reached")
+
+// ruleTestDevice is the state of one device during a rule test: the windows
+// and mutes that may hold its alerts and the alert open for it, like the
+// alerts table during live evaluation.
+type ruleTestDevice struct {
+	device   *models.Device
+	windows  []models.MaintenanceWindow
+	mutes    []models.Mute
+	open     *dto.RuleTestAlert
+	response dto.RuleTestDevice
+}
+
+// TestRule replays the stored heartbeats of the devices between req.From and
+// req.To through the evaluation CheckHeartbeat does, without recording,
+// sending or resolving anything. Like live evaluation, every heartbeat that
+// triggers the rule would send a message, the first one opens an alert that
+// the next heartbeat not triggering it resolves, and triggers while a
+// maintenance window covers the device or a mute silences the rule on it are
+// held. Mutes apply from their creation until they expired or were
+// cancelled. There is no duration or cooldown, and a stored rule is tested
+// even when disabled.
+func (s *notificationService) TestRule(userID uuid.UUID, orgID *uuid.UUID, req dto.RuleTestRequest) (*dto.RuleTestResponse, error) {
+	to := time.Now()
+	if req.To != nil {
+		to = *req.To
+	}
+	if !to.After(req.From) {
+		return nil, errors.NewValidationError("to must be after from")
+	}
+	if to.Sub(req.From) > MaxRuleTestDays*24*time.Hour {
+		return nil, errors.NewValidationError(fmt.Sprintf("The period cannot be longer than %d days", MaxRuleTestDays))
+	}
+	if len(req.DeviceIDs) > MaxRuleTestDevices {
+		return nil, errors.NewValidationError(fmt.Sprintf("At most %d devices can be tested", MaxRuleTestDevices))
+	}
+
+	rule, err := s.ruleToTest(userID, orgID, req)
+	if err != nil {
+		return nil, err
+	}
+	devices, err := s.ruleTestDevices(userID, rule, req.DeviceIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	states := make([]*ruleTestDevice, 0, len(devices))
+	byID := make(map[uuid.UUID]*ruleTestDevice, len(devices))
+	var deviceIDs []uuid.UUID
+	for i := range devices {
+		device := &devices[i]
+		state := &ruleTestDevice{
+			device: device,
+			response: dto.RuleTestDevice{
+				DeviceID: device.UUID,
+				DeviceSN: device.SN,
+				Targeted: sameOwner(rule, device) && s.appliesToDevice(*rule, device),
+			},
+		}
+		states = append(states, state)
+		if !state.response.Targeted {
+			continue
+		}
+		if state.windows, err = s.maintenanceService.CoveringWindows(device, req.From, to); err != nil {
+			return nil, err
+		}
+		if state.mutes, err = s.muteService.CoveringMutes(device, req.From, to); err != nil {
+			return nil, err
+		}
+		byID[device.UUID] = state
+		deviceIDs = append(deviceIDs, device.UUID)
+	}
+
+	response := &dto.RuleTestResponse{From: req.From, To: to, Devices: []dto.RuleTestDevice{}, Timeline: []dto.RuleTestAlert{}}
+	var timeline []*dto.RuleTestAlert
+	err = s.heartbeatRepo.StreamByDeviceIDs(deviceIDs, req.From, to, func(heartbeat *models.Heartbeat) error {
+		state := byID[heartbeat.DeviceID]
+		if state == nil {
+			return nil
+		}
+		if response.Heartbeats == MaxRuleTestHeartbeats {
+			response.Truncated = true
+			return errRuleTestLimit
+		}
+		response.Heartbeats++
+		state.response.Heartbeats++
+		if alert := s.replayHeartbeat(rule, state, heartbeat); alert != nil {
+			if len(timeline) < MaxRuleTestTimeline {
+				timeline = append(timeline, alert)
+			} else {
+				response.TimelineTruncated = true
+			}
+		}
+		return nil
+	})
+	if err != nil && err != errRuleTestLimit {
+		return nil, errors.ErrDatabaseError
+	}
+
+	for _, state := range states {
+		response.Matched += state.response.Matched
+		response.Notifications += state.response.Notifications
+		response.Suppressed += state.response.Suppressed
+		response.Alerts += state.response.Alerts
+		response.Devices = append(response.Devices, state.response)
+	}
+	for _, alert := range timeline {
+		response.Timeline = append(response.Timeline, *alert)
+	}
+	return response, nil
+}
+
+// replayHeartbeat applies a heartbeat of the device to the rule as
+// CheckHeartbeat would and returns the alert it opens, if any.
+func (s *notificationService) replayHeartbeat(rule *models.Notification, state *ruleTestDevice, heartbeat *models.Heartbeat) *dto.RuleTestAlert {
+	results, matched := s.evaluateConditions(rule.Conditions, heartbeat)
+	if !matched {
+		if state.open != nil {
+			resolvedAt := heartbeat.CreatedAt
+			state.open.ResolvedAt = &resolvedAt
+			state.open = nil
+		}
+		return nil
+	}
+
+	state.response.Matched++
+	if activeWindowAt(state.windows, heartbeat.CreatedAt) != nil ||
+		activeMuteAt(state.mutes, rule.ID, state.device.UUID, heartbeat.CreatedAt) != nil {
+		state.response.Suppressed++
+		return nil
+	}
+
+	state.response.Notifications++
+	if state.open != nil {
+		state.open.TriggerCount++
+		state.open.LastTriggeredAt = heartbeat.CreatedAt
+		return nil
+	}
+
+	state.response.Alerts++
+	state.open = &dto.RuleTestAlert{
+		DeviceID:        state.device.UUID,
+		DeviceSN:        state.device.SN,
+		FiredAt:         heartbeat.CreatedAt,
+		LastTriggeredAt: heartbeat.CreatedAt,
+		TriggerCount:    1,
+		Conditions:      results,
+	}
+	return state.open
+}
+
+// ruleToTest returns the stored rule of the request, which the user must be
+// able to view, or the rule its definition describes, owned like the rules
+// the user would create with it.
+func (s *notificationService) ruleToTest(userID uuid.UUID, orgID *uuid.UUID, req dto.RuleTestRequest) (*models.Notification, error) {
+	switch {
+	case req.NotificationID != nil && req.Rule != nil:
+		return nil, errors.NewValidationError("Set either notification_id or rule, not both")
+	case req.NotificationID != nil:
+		rule, err := s.notificationRepo.FindByID(*req.NotificationID)
+		if err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return nil, errors.ErrNotificationNotFound
+			}
+			return nil, errors.ErrDatabaseError
+		}
+		if err := s.authz.Authorize(userID, Ownership{UserID: rule.UserID, OrganizationID: rule.OrganizationID}, models.RoleViewer); err != nil {
+			return nil, err
+		}
+		return rule, nil
+	case req.Rule != nil:
+		if err := s.authz.Authorize(userID, Ownership{UserID: userID, OrganizationID: orgID}, models.RoleViewer); err != nil {
+			return nil, err
+		}
+		return s.buildRule(userID, orgID, *req.Rule)
+	default:
+		return nil, errors.NewValidationError("notification_id or rule is required")
+	}
+}
+
+// ruleTestDevices returns the listed devices, which the user must be able to
+// view, or every device of the rule's owner the user can access.
+func (s *notificationService) ruleTestDevices(userID uuid.UUID, rule *models.Notification, deviceIDs []uuid.UUID) ([]models.Device, error) {
+	if len(deviceIDs) == 0 {
+		accessible, err := s.deviceRepo.FindByUserID(userID)
+		if err != nil {
+			return nil, errors.ErrDatabaseError
+		}
+		var devices []models.Device
+		for i := range accessible {
+			if sameOwner(rule, &accessible[i]) {
+				devices = append(devices, accessible[i])
+			}
+		}
+		if len(devices) > MaxRuleTestDevices {
+			return nil, errors.NewValidationError(fmt.Sprintf("The rule's owner has more than %d devices; list the ones to test in device_ids", MaxRuleTestDevices))
+		}
+		return devices, nil
+	}
+
+	devices := make([]models.Device, 0, len(deviceIDs))
+	for _, id := range uniqueIDs(deviceIDs) {
+		device, err := s.deviceRepo.FindByID(id)
+		if err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return nil, errors.ErrDeviceNotFound
+			}
+			return nil, errors.ErrDatabaseError
+		}
+		if err := s.authz.Authorize(userID, deviceOwnership(device), models.RoleViewer); err != nil {
+			return nil, err
+		}
+		devices = append(devices, *device)
+	}
+	return devices, nil
+}
+
+// sameOwner reports whether CheckHeartbeat evaluates the rule for the
+// device: organization devices against the rules of their organization,
+// personal devices against the personal rules of their owner.
+func sameOwner(rule *models.Notification, device *models.Device) bool {
+	if device.OrganizationID != nil {
+		return rule.OrganizationID != nil && *rule.OrganizationID == *device.OrganizationID
+	}
+	return rule.OrganizationID == nil && rule.UserID == device.UserID
+}
@@ -0,0 +1,213 @@
+package services
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/dto"
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/models"
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/utils/errors"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+func newTestRuleBacktest(windows []models.MaintenanceWindow, mutes []models.Mute) (NotificationService, *MockNotificationRepository, *MockDeviceRepository, *MockHeartbeatRepository) {
+	notificationRepo := new(MockNotificationRepository)
+	deviceRepo := new(MockDeviceRepository)
+	heartbeatRepo := new(MockHeartbeatRepository)
+	windowRepo := new(MockMaintenanceWindowRepository)
+	windowRepo.On("FindOverlappingByOwner", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(windows, nil)
+	authz := NewAuthorizer(new(MockOrganizationRepository), noDeviceShares())
+	maintenance := NewMaintenanceWindowService(windowRepo, deviceRepo, new(MockDeviceGroupRepository), authz)
+	muteRepo := new(MockMuteRepository)
+	muteRepo.On("FindOverlappingForDevice", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(mutes, nil)
+	muteService := NewMuteService(muteRepo, notificationRepo, deviceRepo, authz)
+	service := NewNotificationService(notificationRepo, deviceRepo, heartbeatRepo, maintenance, muteService, noNotificationChannels(), noAlerts(), noEscalationPolicies(), defaultPreferences(new(MockRedisPublisher), noNotificationChannels()), authz)
+	return service, notificationRepo, deviceRepo, heartbeatRepo
+}
+
+func TestNotificationService_TestRule(t *testing.T) {
+	userID := uuid.New()
+	device := &models.Device{UUID: uuid.New(), UserID: userID, SN: "123456789012"}
+	from := time.Date(2025, 9, 1, 0, 0, 0, 0, time.UTC)
+	to := from.Add(24 * time.Hour)
+	at := func(minutes int) time.Time { return from.Add(time.Duration(minutes) * time.Minute) }
+	heartbeats := []models.Heartbeat{
+		{DeviceID: device.UUID, CPU: 91, CreatedAt: at(0)},
+		{DeviceID: device.UUID, CPU: 95, CreatedAt: at(1)},
+		{DeviceID: device.UUID, CPU: 40, CreatedAt: at(2)},
+		{DeviceID: device.UUID, CPU: 92, CreatedAt: at(3)},
+		{DeviceID: device.UUID, CPU: 93, CreatedAt: at(4)},
+	}
+	definition := &dto.CreateNotificationRequest{
+		Name:       "High CPU",
+		Conditions: []dto.NotificationCondition{{Parameter: "cpu", Operator: ">", Value: 90.0}},
+	}
+
+	t.Run("Success - Rule definition replayed with alerts opened and resolved", func(t *testing.T) {
+		service, notificationRepo, deviceRepo, heartbeatRepo := newTestRuleBacktest(nil, nil)
+
+		deviceRepo.On("FindByID", device.UUID).Return(device, nil)
+		heartbeatRepo.On("StreamByDeviceIDs", []uuid.UUID{device.UUID}, from, to).Return(heartbeats, nil)
+
+		result, err := service.TestRule(userID, nil, dto.RuleTestRequest{Rule: definition, DeviceIDs: []uuid.UUID{device.UUID}, From: from, To: &to})
+
+		assert.NoError(t, err)
+		assert.Equal(t, 5, result.Heartbeats)
+		assert.Equal(t, 4, result.Matched)
+		assert.Equal(t, 4, result.Notifications)
+		assert.Equal(t, 2, result.Alerts)
+		assert.Len(t, result.Timeline, 2)
+		assert.Equal(t, at(0), result.Timeline[0].FiredAt)
+		assert.Equal(t, at(1), result.Timeline[0].LastTriggeredAt)
+		assert.Equal(t, 2, result.Timeline[0].TriggerCount)
+		assert.Equal(t, at(2), *result.Timeline[0].ResolvedAt)
+		assert.Equal(t, 91.0, result.Timeline[0].Conditions[0].Actual)
+		assert.Nil(t, result.Timeline[1].ResolvedAt)
+		assert.True(t, result.Devices[0].Targeted)
+		notificationRepo.AssertNotCalled(t, "Create", mock.Anything)
+	})
+
+	t.Run("Success - Maintenance window holds triggers without opening alerts", func(t *testing.T) {
+		endsAt := at(4)
+		window := models.MaintenanceWindow{ID: uuid.New(), UserID: userID, StartsAt: at(3), EndsAt: &endsAt, DeviceIDs: []uuid.UUID{device.UUID}}
+		service, _, deviceRepo, heartbeatRepo := newTestRuleBacktest([]models.MaintenanceWindow{window}, nil)
+
+		deviceRepo.On("FindByID", device.UUID).Return(device, nil)
+		heartbeatRepo.On("StreamByDeviceIDs", []uuid.UUID{device.UUID}, from, to).Return(heartbeats, nil)
+
+		result, err := service.TestRule(userID, nil, dto.RuleTestRequest{Rule: definition, DeviceIDs: []uuid.UUID{device.UUID}, From: from, To: &to})
+
+		assert.NoError(t, err)
+		assert.Equal(t, 4, result.Matched)
+		assert.Equal(t, 1, result.Suppressed)
+		assert.Equal(t, 3, result.Notifications)
+		assert.Equal(t, 2, result.Alerts)
+		assert.Equal(t, at(4), result.Timeline[1].FiredAt)
+	})
+
+	t.Run("Success - Mutes hold triggers while they were in effect", func(t *testing.T) {
+		conditions, _ := json.Marshal(definition.Conditions)
+		rule := &models.Notification{ID: uuid.New(), UserID: userID, Conditions: datatypes.JSON(conditions), DeviceIDs: datatypes.JSON(`[]`)}
+		otherRuleID := uuid.New()
+		cancelledAt := at(4)
+		mutes := []models.Mute{
+			{ID: uuid.New(), UserID: userID, NotificationID: &rule.ID, CreatedAt: at(3), ExpiresAt: at(60), CancelledAt: &cancelledAt},
+			{ID: uuid.New(), UserID: userID, DeviceID: &device.UUID, CreatedAt: from.Add(-time.Hour), ExpiresAt: at(1)},
+			{ID: uuid.New(), UserID: userID, NotificationID: &otherRuleID, CreatedAt: from, ExpiresAt: to},
+		}
+		service, notificationRepo, deviceRepo, heartbeatRepo := newTestRuleBacktest(nil, mutes)
+
+		notificationRepo.On("FindByID", rule.ID).Return(rule, nil)
+		deviceRepo.On("FindByID", device.UUID).Return(device, nil)
+		heartbeatRepo.On("StreamByDeviceIDs", []uuid.UUID{device.UUID}, from, to).Return(heartbeats, nil)
+
+		result, err := service.TestRule(userID, nil, dto.RuleTestRequest{NotificationID: &rule.ID, DeviceIDs: []uuid.UUID{device.UUID}, From: from, To: &to})
+
+		assert.NoError(t, err)
+		assert.Equal(t, 4, result.Matched)
+		assert.Equal(t, 2, result.Suppressed)
+		assert.Equal(t, 2, result.Notifications)
+		assert.Equal(t, 2, result.Alerts)
+		assert.Equal(t, at(1), result.Timeline[0].FiredAt)
+		assert.Equal(t, at(4), result.Timeline[1].FiredAt)
+	})
+
+	t.Run("Success - Stored rule skips devices it does not target", func(t *testing.T) {
+		service, notificationRepo, deviceRepo, heartbeatRepo := newTestRuleBacktest(nil, nil)
+		other := models.Device{UUID: uuid.New(), UserID: userID, SN: "210987654321"}
+		deviceIDs, _ := json.Marshal([]uuid.UUID{device.UUID})
+		conditions, _ := json.Marshal(definition.Conditions)
+		rule := &models.Notification{ID: uuid.New(), UserID: userID, Conditions: datatypes.JSON(conditions), DeviceIDs: datatypes.JSON(deviceIDs)}
+
+		notificationRepo.On("FindByID", rule.ID).Return(rule, nil)
+		deviceRepo.On("FindByUserID", userID).Return([]models.Device{*device, other}, nil)
+		heartbeatRepo.On("StreamByDeviceIDs", []uuid.UUID{device.UUID}, from, to).Return(heartbeats[:2], nil)
+
+		result, err := service.TestRule(userID, nil, dto.RuleTestRequest{NotificationID: &rule.ID, From: from, To: &to})
+
+		assert.NoError(t, err)
+		assert.Equal(t, 1, result.Alerts)
+		assert.Len(t, result.Devices, 2)
+		assert.False(t, result.Devices[1].Targeted)
+		heartbeatRepo.AssertExpectations(t)
+	})
+
+	t.Run("Error - Both a stored rule and a definition", func(t *testing.T) {
+		service, _, _, _ := newTestRuleBacktest(nil, nil)
+		ruleID := uuid.New()
+
+		_, err := service.TestRule(userID, nil, dto.RuleTestRequest{NotificationID: &ruleID, Rule: definition, From: from, To: &to})
+
+		assert.IsType(t, &errors.BusinessError{}, err)
+	})
+
+	t.Run("Error - No rule", func(t *testing.T) {
+		service, _, _, _ := newTestRuleBacktest(nil, nil)
+
+		_, err := service.TestRule(userID, nil, dto.RuleTestRequest{From: from, To: &to})
+
+		assert.IsType(t, &errors.BusinessError{}, err)
+	})
+
+	t.Run("Error - Period longer than the maximum", func(t *testing.T) {
+		service, _, _, _ := newTestRuleBacktest(nil, nil)
+		end := from.Add((MaxRuleTestDays + 1) * 24 * time.Hour)
+
+		_, err := service.TestRule(userID, nil, dto.RuleTestRequest{Rule: definition, From: from, To: &end})
+
+		assert.IsType(t, &errors.BusinessError{}, err)
+	})
+
+	t.Run("Error - Period ends before it starts", func(t *testing.T) {
+		service, _, _, _ := newTestRuleBacktest(nil, nil)
+
+		_, err := service.TestRule(userID, nil, dto.RuleTestRequest{Rule: definition, From: to, To: &from})
+
+		assert.IsType(t, &errors.BusinessError{}, err)
+	})
+
+	t.Run("Error - Invalid rule definition", func(t *testing.T) {
+		service, _, _, _ := newTestRuleBacktest(nil, nil)
+		invalid := &dto.CreateNotificationRequest{Name: "High CPU", Conditions: []dto.NotificationCondition{{Parameter: "fan", Operator: ">", Value: 1.0}}}
+
+		_, err := service.TestRule(userID, nil, dto.RuleTestRequest{Rule: invalid, From: from, To: &to})
+
+		assert.IsType(t, &errors.BusinessError{}, err)
+	})
+
+	t.Run("Error - Stored rule not found", func(t *testing.T) {
+		service, notificationRepo, _, _ := newTestRuleBacktest(nil, nil)
+		ruleID := uuid.New()
+		notificationRepo.On("FindByID", ruleID).Return(nil, gorm.ErrRecordNotFound)
+
+		_, err := service.TestRule(userID, nil, dto.RuleTestRequest{NotificationID: &ruleID, From: from, To: &to})
+
+		assert.Equal(t, errors.ErrNotificationNotFound, err)
+	})
+
+	t.Run("Error - Rule of another user", func(t *testing.T) {
+		service, notificationRepo, _, heartbeatRepo := newTestRuleBacktest(nil, nil)
+		rule := &models.Notification{ID: uuid.New(), UserID: uuid.New()}
+		notificationRepo.On("FindByID", rule.ID).Return(rule, nil)
+
+		_, err := service.TestRule(userID, nil, dto.RuleTestRequest{NotificationID: &rule.ID, From: from, To: &to})
+
+		assert.Equal(t, errors.ErrForbidden, err)
+		heartbeatRepo.AssertNotCalled(t, "StreamByDeviceIDs", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Error - Device of another user", func(t *testing.T) {
+		service, _, deviceRepo, _ := newTestRuleBacktest(nil, nil)
+		foreign := &models.Device{UUID: uuid.New(), UserID: uuid.New()}
+		deviceRepo.On("FindByID", foreign.UUID).Return(foreign, nil)
+
+		_, err := service.TestRule(userID, nil, dto.RuleTestRequest{Rule: definition, DeviceIDs: []uuid.UUID{foreign.UUID}, From: from, To: &to})
+
+		assert.Error(t, err)
+	})
+}
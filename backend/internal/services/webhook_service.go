@@ -0,0 +1,223 @@
+package services
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/dto"
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/models"
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/repository"
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/utils/errors"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// WebhookService manages the webhook notification channels as signed
+// endpoints: their secrets, their delivery log and manual redeliveries.
+type WebhookService interface {
+	CreateWebhook(userID uuid.UUID, orgID *uuid.UUID, req dto.WebhookRequest) (*dto.WebhookResponse, error)
+	ListWebhooks(userID uuid.UUID) ([]dto.WebhookResponse, error)
+	GetWebhook(userID, webhookID uuid.UUID) (*dto.WebhookResponse, error)
+	UpdateWebhook(userID, webhookID uuid.UUID, req dto.WebhookRequest) (*dto.WebhookResponse, error)
+	DeleteWebhook(userID, webhookID uuid.UUID) error
+	RotateSecret(userID, webhookID uuid.UUID) (*dto.WebhookResponse, error)
+	ListDeliveries(userID, webhookID uuid.UUID) ([]dto.NotificationDeliveryResponse, error)
+	Redeliver(userID, webhookID, deliveryID uuid.UUID) (*dto.NotificationDeliveryResponse, error)
+}
+
+// webhookConfig mirrors the config of webhook channels.
+type webhookConfig struct {
+	URL     string            `json:"url"`
+	Headers map[string]string `json:"headers,omitempty"`
+}
+
+type webhookService struct {
+	channels *notificationChannelService
+}
+
+func NewWebhookService(channelRepo repository.NotificationChannelRepository, sender ChannelSender, authz Authorizer) WebhookService {
+	return &webhookService{
+		channels: &notificationChannelService{
+			channelRepo: channelRepo,
+			senders:     map[string]ChannelSender{models.ChannelWebhook: sender},
+			authz:       authz,
+		},
+	}
+}
+
+// CreateWebhook creates a webhook channel. Its signing secret is only
+// returned in this response. Requires the operator role.
+func (s *webhookService) CreateWebhook(userID uuid.UUID, orgID *uuid.UUID, req dto.WebhookRequest) (*dto.WebhookResponse, error) {
+	channelReq, err := webhookChannelRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	channel, err := s.channels.createChannel(userID, orgID, channelReq)
+	if err != nil {
+		return nil, err
+	}
+
+	response := webhookResponse(channel)
+	response.Secret = channel.Secret
+	return response, nil
+}
+
+func (s *webhookService) ListWebhooks(userID uuid.UUID) ([]dto.WebhookResponse, error) {
+	channels, err := s.channels.channelRepo.FindByUserID(userID)
+	if err != nil {
+		return nil, errors.ErrDatabaseError
+	}
+
+	responses := make([]dto.WebhookResponse, 0, len(channels))
+	for i := range channels {
+		if channels[i].Type == models.ChannelWebhook {
+			responses = append(responses, *webhookResponse(&channels[i]))
+		}
+	}
+	return responses, nil
+}
+
+func (s *webhookService) GetWebhook(userID, webhookID uuid.UUID) (*dto.WebhookResponse, error) {
+	channel, err := s.findWebhook(userID, webhookID, models.RoleViewer)
+	if err != nil {
+		return nil, err
+	}
+	return webhookResponse(channel), nil
+}
+
+// UpdateWebhook replaces the name, URL and headers of the webhook, and its
+// enabled flag when given. Enabling a webhook resets its failure count.
+// Requires the operator role.
+func (s *webhookService) UpdateWebhook(userID, webhookID uuid.UUID, req dto.WebhookRequest) (*dto.WebhookResponse, error) {
+	channel, err := s.findWebhook(userID, webhookID, models.RoleOperator)
+	if err != nil {
+		return nil, err
+	}
+
+	channelReq, err := webhookChannelRequest(req)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.channels.updateChannel(channel, channelReq); err != nil {
+		return nil, err
+	}
+	return webhookResponse(channel), nil
+}
+
+// DeleteWebhook removes the webhook with its delivery log. Requires the
+// operator role.
+func (s *webhookService) DeleteWebhook(userID, webhookID uuid.UUID) error {
+	channel, err := s.findWebhook(userID, webhookID, models.RoleOperator)
+	if err != nil {
+		return err
+	}
+
+	if err := s.channels.channelRepo.Delete(channel.ID); err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return errors.ErrWebhookNotFound
+		}
+		return errors.ErrDatabaseError
+	}
+	return nil
+}
+
+// RotateSecret replaces the signing secret of the webhook and returns the
+// new one. Payloads are also signed with the previous secret for
+// WebhookSecretGracePeriod; rotating again ends that period for the secret
+// replaced first. Requires the operator role.
+func (s *webhookService) RotateSecret(userID, webhookID uuid.UUID) (*dto.WebhookResponse, error) {
+	channel, err := s.findWebhook(userID, webhookID, models.RoleOperator)
+	if err != nil {
+		return nil, err
+	}
+
+	secret, err := newWebhookSecret()
+	if err != nil {
+		return nil, errors.ErrDatabaseError
+	}
+	now := time.Now()
+	expiresAt := now.Add(WebhookSecretGracePeriod)
+	channel.PreviousSecret = channel.Secret
+	channel.PreviousSecretExpiresAt = &expiresAt
+	channel.Secret = secret
+	channel.UpdatedAt = now
+
+	if err := s.channels.channelRepo.Update(channel); err != nil {
+		return nil, errors.ErrDatabaseError
+	}
+
+	response := webhookResponse(channel)
+	response.Secret = channel.Secret
+	return response, nil
+}
+
+func (s *webhookService) ListDeliveries(userID, webhookID uuid.UUID) ([]dto.NotificationDeliveryResponse, error) {
+	channel, err := s.findWebhook(userID, webhookID, models.RoleViewer)
+	if err != nil {
+		return nil, err
+	}
+	return s.channels.listDeliveries(channel)
+}
+
+// Redeliver sends a delivery of the webhook again right away with a fresh
+// signature, even when the webhook is disabled. Requires the operator role.
+func (s *webhookService) Redeliver(userID, webhookID, deliveryID uuid.UUID) (*dto.NotificationDeliveryResponse, error) {
+	channel, err := s.findWebhook(userID, webhookID, models.RoleOperator)
+	if err != nil {
+		return nil, err
+	}
+	return s.channels.redeliver(channel, deliveryID)
+}
+
+func (s *webhookService) findWebhook(userID, webhookID uuid.UUID, role string) (*models.NotificationChannel, error) {
+	channel, err := s.channels.findChannel(userID, webhookID, role)
+	if err != nil {
+		if err == errors.ErrNotificationChannelNotFound {
+			return nil, errors.ErrWebhookNotFound
+		}
+		return nil, err
+	}
+	if channel.Type != models.ChannelWebhook {
+		return nil, errors.ErrWebhookNotFound
+	}
+	return channel, nil
+}
+
+func webhookChannelRequest(req dto.WebhookRequest) (dto.NotificationChannelRequest, error) {
+	config, err := json.Marshal(webhookConfig{URL: req.URL, Headers: req.Headers})
+	if err != nil {
+		return dto.NotificationChannelRequest{}, errors.NewValidationError("Invalid webhook headers")
+	}
+	return dto.NotificationChannelRequest{
+		Name:        req.Name,
+		Type:        models.ChannelWebhook,
+		Config:      config,
+		Enabled:     req.Enabled,
+		MinSeverity: req.MinSeverity,
+	}, nil
+}
+
+func webhookResponse(channel *models.NotificationChannel) *dto.WebhookResponse {
+	var config webhookConfig
+	json.Unmarshal(channel.Config, &config)
+
+	response := &dto.WebhookResponse{
+		ID:                  channel.ID,
+		OrganizationID:      channel.OrganizationID,
+		Name:                channel.Name,
+		URL:                 config.URL,
+		Headers:             config.Headers,
+		Enabled:             channel.Enabled,
+		MinSeverity:         channel.MinSeverity,
+		ConsecutiveFailures: channel.ConsecutiveFailures,
+		DisabledAt:          channel.DisabledAt,
+		DisabledReason:      channel.DisabledReason,
+		CreatedAt:           channel.CreatedAt,
+		UpdatedAt:           channel.UpdatedAt,
+	}
+	if channel.PreviousSecret != "" && channel.PreviousSecretExpiresAt != nil && time.Now().Before(*channel.PreviousSecretExpiresAt) {
+		response.PreviousSecretExpiresAt = channel.PreviousSecretExpiresAt
+	}
+	return response
+}
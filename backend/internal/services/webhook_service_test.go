@@ -0,0 +1,131 @@
+package services
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/dto"
+	"github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/models"
+	custom_errors "github.com/Arthur-7Melo/exame-fullstack-setembro-dtlabs-2025/internal/utils/errors"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newTestWebhookService() (WebhookService, *MockNotificationChannelRepository, *stubSender) {
+	channelRepo := new(MockNotificationChannelRepository)
+	sender := &stubSender{}
+	authz := NewAuthorizer(new(MockOrganizationRepository), noDeviceShares())
+	return NewWebhookService(channelRepo, sender, authz), channelRepo, sender
+}
+
+func TestWebhookService_CreateWebhook(t *testing.T) {
+	userID := uuid.New()
+
+	t.Run("Success - Returns the signing secret", func(t *testing.T) {
+		service, channelRepo, _ := newTestWebhookService()
+		channelRepo.On("Create", mock.MatchedBy(func(channel *models.NotificationChannel) bool {
+			return channel.Type == models.ChannelWebhook && strings.HasPrefix(channel.Secret, "whsec_")
+		})).Return(nil)
+
+		webhook, err := service.CreateWebhook(userID, nil, dto.WebhookRequest{
+			Name:    "Incident manager",
+			URL:     "https://hooks.example.com/alerts",
+			Headers: map[string]string{"Authorization": "Bearer token"},
+		})
+
+		assert.NoError(t, err)
+		assert.True(t, strings.HasPrefix(webhook.Secret, "whsec_"))
+		assert.Equal(t, "https://hooks.example.com/alerts", webhook.URL)
+		assert.Equal(t, "Bearer token", webhook.Headers["Authorization"])
+		assert.True(t, webhook.Enabled)
+		channelRepo.AssertExpectations(t)
+	})
+}
+
+func TestWebhookService_GetWebhook(t *testing.T) {
+	userID := uuid.New()
+
+	t.Run("Success - Secret is not returned", func(t *testing.T) {
+		service, channelRepo, _ := newTestWebhookService()
+		channel := &models.NotificationChannel{ID: uuid.New(), UserID: userID, Type: models.ChannelWebhook, Secret: "whsec_current", Config: []byte(`{"url":"https://hooks.example.com/alerts"}`)}
+		channelRepo.On("FindByID", channel.ID).Return(channel, nil)
+
+		webhook, err := service.GetWebhook(userID, channel.ID)
+
+		assert.NoError(t, err)
+		assert.Empty(t, webhook.Secret)
+		assert.Equal(t, "https://hooks.example.com/alerts", webhook.URL)
+	})
+
+	t.Run("Error - Channel is not a webhook", func(t *testing.T) {
+		service, channelRepo, _ := newTestWebhookService()
+		channel := &models.NotificationChannel{ID: uuid.New(), UserID: userID, Type: models.ChannelSlack}
+		channelRepo.On("FindByID", channel.ID).Return(channel, nil)
+
+		webhook, err := service.GetWebhook(userID, channel.ID)
+
+		assert.Nil(t, webhook)
+		assert.Equal(t, custom_errors.ErrWebhookNotFound, err)
+	})
+}
+
+func TestWebhookService_RotateSecret(t *testing.T) {
+	userID := uuid.New()
+
+	t.Run("Success - Previous secret still signs during the grace period", func(t *testing.T) {
+		service, channelRepo, _ := newTestWebhookService()
+		channel := &models.NotificationChannel{ID: uuid.New(), UserID: userID, Type: models.ChannelWebhook, Secret: "whsec_old", Config: []byte(`{"url":"https://hooks.example.com/alerts"}`)}
+		channelRepo.On("FindByID", channel.ID).Return(channel, nil)
+		channelRepo.On("Update", channel).Return(nil)
+
+		webhook, err := service.RotateSecret(userID, channel.ID)
+
+		assert.NoError(t, err)
+		assert.NotEqual(t, "whsec_old", webhook.Secret)
+		assert.NotNil(t, webhook.PreviousSecretExpiresAt)
+		assert.Equal(t, []string{webhook.Secret, "whsec_old"}, channel.SigningSecrets(time.Now()))
+		assert.Equal(t, []string{webhook.Secret}, channel.SigningSecrets(time.Now().Add(WebhookSecretGracePeriod+time.Minute)))
+	})
+}
+
+func TestWebhookService_Redeliver(t *testing.T) {
+	userID := uuid.New()
+	channel := &models.NotificationChannel{ID: uuid.New(), UserID: userID, Type: models.ChannelWebhook, Secret: "whsec_current"}
+	payload, _ := json.Marshal(dto.NotificationAlert{Name: "High CPU Alert"})
+
+	t.Run("Success - Failed delivery sent again", func(t *testing.T) {
+		service, channelRepo, sender := newTestWebhookService()
+		delivery := &models.NotificationDelivery{ID: uuid.New(), ChannelID: channel.ID, Payload: payload, Status: models.DeliveryFailed, Attempts: MaxDeliveryAttempts}
+		channelRepo.On("FindByID", channel.ID).Return(channel, nil)
+		channelRepo.On("FindDeliveryByID", delivery.ID).Return(delivery, nil)
+		channelRepo.On("CreateAttempt", mock.MatchedBy(func(attempt *models.DeliveryAttempt) bool {
+			return attempt.Attempt == MaxDeliveryAttempts+1 && attempt.Success
+		})).Return(nil)
+		channelRepo.On("UpdateDelivery", delivery).Return(nil)
+		channelRepo.On("RecordDeliveryResult", channel.ID, true, ChannelFailureLimit, "", mock.AnythingOfType("time.Time")).Return(false, nil)
+		channelRepo.On("FindAttempts", []uuid.UUID{delivery.ID}).Return([]models.DeliveryAttempt{{DeliveryID: delivery.ID, Attempt: MaxDeliveryAttempts + 1, Success: true}}, nil)
+
+		response, err := service.Redeliver(userID, channel.ID, delivery.ID)
+
+		assert.NoError(t, err)
+		assert.Equal(t, models.DeliveryDelivered, response.Status)
+		assert.Equal(t, delivery.ID, sender.sent[0].DeliveryID)
+		assert.Equal(t, []string{"whsec_current"}, sender.sent[0].Secrets)
+	})
+
+	t.Run("Error - Delivery of another channel", func(t *testing.T) {
+		service, channelRepo, sender := newTestWebhookService()
+		delivery := &models.NotificationDelivery{ID: uuid.New(), ChannelID: uuid.New(), Payload: payload}
+		channelRepo.On("FindByID", channel.ID).Return(channel, nil)
+		channelRepo.On("FindDeliveryByID", delivery.ID).Return(delivery, nil)
+
+		response, err := service.Redeliver(userID, channel.ID, delivery.ID)
+
+		assert.Nil(t, response)
+		assert.Equal(t, custom_errors.ErrNotificationDeliveryNotFound, err)
+		assert.Empty(t, sender.sent)
+	})
+}
@@ -0,0 +1,208 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// cronSearchLimit bounds the search for the next occurrence so that
+// schedules that never fire (e.g. "0 0 31 2 *") do not loop forever.
+const cronSearchLimit = 5
+
+var cronMacros = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+type cronField struct {
+	name     string
+	min, max int
+}
+
+var cronFields = []cronField{
+	{"minute", 0, 59},
+	{"hour", 0, 23},
+	{"day of month", 1, 31},
+	{"month", 1, 12},
+	{"day of week", 0, 6},
+}
+
+// CronSchedule is a standard five field cron expression
+// ("minute hour day-of-month month day-of-week"). Fields accept '*', lists,
+// ranges and steps ("*/15", "1-5", "0,30"); Sunday is 0 or 7. The macros
+// @yearly, @monthly, @weekly, @daily and @hourly are also accepted. Names
+// (JAN, MON), L, W, # and iCalendar RRULEs are not.
+type CronSchedule struct {
+	minute, hour, dom, month, dow uint64
+	// When both day fields are restricted a day matches either of them,
+	// as in cron.
+	domAny, dowAny bool
+}
+
+func ParseCronSchedule(expression string) (*CronSchedule, error) {
+	expression = strings.TrimSpace(expression)
+	if macro, ok := cronMacros[strings.ToLower(expression)]; ok {
+		expression = macro
+	}
+
+	if upper := strings.ToUpper(expression); strings.HasPrefix(upper, "RRULE:") || strings.HasPrefix(upper, "FREQ=") {
+		return nil, errors.New("RRULE schedules are not supported, use a 5 field cron expression")
+	}
+	fields := strings.Fields(expression)
+	if len(fields) != len(cronFields) {
+		return nil, errors.New("cron expression must have 5 fields: minute hour day-of-month month day-of-week")
+	}
+
+	var bits [5]uint64
+	for i, field := range fields {
+		max := cronFields[i].max
+		if i == 4 {
+			// Accept 7 as Sunday.
+			max = 7
+		}
+		parsed, err := parseCronField(field, cronFields[i].min, max)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s %q: %w", cronFields[i].name, field, err)
+		}
+		bits[i] = parsed
+	}
+	if bits[4]&(1<<7) != 0 {
+		bits[4] = bits[4]&^(1<<7) | 1
+	}
+
+	return &CronSchedule{
+		minute: bits[0],
+		hour:   bits[1],
+		dom:    bits[2],
+		month:  bits[3],
+		dow:    bits[4],
+		domAny: fields[2] == "*" || fields[2] == "?",
+		dowAny: fields[4] == "*" || fields[4] == "?",
+	}, nil
+}
+
+func parseCronField(field string, min, max int) (uint64, error) {
+	var bits uint64
+	for _, part := range strings.Split(field, ",") {
+		rangeExpr, step := part, 1
+		if i := strings.Index(part, "/"); i >= 0 {
+			rangeExpr = part[:i]
+			n, err := strconv.Atoi(part[i+1:])
+			if err != nil || n <= 0 {
+				return 0, errors.New("step must be a positive number")
+			}
+			step = n
+		}
+
+		start, end := min, max
+		switch {
+		case rangeExpr == "*" || rangeExpr == "?":
+		case strings.Contains(rangeExpr, "-"):
+			bounds := strings.SplitN(rangeExpr, "-", 2)
+			var err error
+			if start, err = strconv.Atoi(bounds[0]); err != nil {
+				return 0, errors.New("range start must be a number")
+			}
+			if end, err = strconv.Atoi(bounds[1]); err != nil {
+				return 0, errors.New("range end must be a number")
+			}
+		default:
+			n, err := strconv.Atoi(rangeExpr)
+			if err != nil {
+				return 0, errors.New("value must be a number")
+			}
+			start = n
+			end = n
+			if step > 1 {
+				end = max
+			}
+		}
+		if start < min || end > max || start > end {
+			return 0, fmt.Errorf("values must be between %d and %d", min, max)
+		}
+
+		for v := start; v <= end; v += step {
+			bits |= 1 << uint(v)
+		}
+	}
+	return bits, nil
+}
+
+// Next returns the first occurrence strictly after t, in t's location, or
+// the zero time when the schedule does not fire within the next years.
+// Occurrences are wall-clock times: one skipped when clocks go forward does
+// not fire, and one repeated when they go back fires once, on its first pass.
+func (s *CronSchedule) Next(t time.Time) time.Time {
+	loc := t.Location()
+	t = t.Truncate(time.Minute).Add(time.Minute)
+	limit := t.Year() + cronSearchLimit
+
+	for t.Year() <= limit {
+		if s.month&(1<<uint(t.Month())) == 0 {
+			t = advance(t, time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, loc))
+			continue
+		}
+		if !s.dayMatches(t) {
+			t = advance(t, time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, loc))
+			continue
+		}
+		if s.hour&(1<<uint(t.Hour())) == 0 {
+			t = advance(t, time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, loc))
+			continue
+		}
+		if s.minute&(1<<uint(t.Minute())) == 0 || repeatedBy(t) > 0 {
+			t = t.Add(time.Minute)
+			continue
+		}
+		return t
+	}
+	return time.Time{}
+}
+
+// advance moves the search from t to the wall-clock time next. time.Date may
+// resolve a time that happens twice to its second pass, which is moved back
+// to the first one when that is still ahead, and a time skipped by clocks
+// going forward to an earlier time, in which case the search moves on to the
+// start of the next hour instead.
+func advance(t, next time.Time) time.Time {
+	if shift := repeatedBy(next); shift > 0 && next.Add(-shift).After(t) {
+		next = next.Add(-shift)
+	}
+	if next.After(t) {
+		return next
+	}
+	return t.Add(time.Duration(60-t.Minute()) * time.Minute)
+}
+
+// repeatedBy returns how far clocks went back when t is on the second pass
+// through wall-clock times that happen twice, and 0 otherwise.
+func repeatedBy(t time.Time) time.Duration {
+	start, _ := t.ZoneBounds()
+	if start.IsZero() {
+		return 0
+	}
+	_, offset := t.Zone()
+	_, previous := start.Add(-time.Second).Zone()
+	shift := time.Duration(previous-offset) * time.Second
+	if shift <= 0 || t.Sub(start) >= shift {
+		return 0
+	}
+	return shift
+}
+
+func (s *CronSchedule) dayMatches(t time.Time) bool {
+	domMatch := s.dom&(1<<uint(t.Day())) != 0
+	dowMatch := s.dow&(1<<uint(t.Weekday())) != 0
+	if s.domAny || s.dowAny {
+		return domMatch && dowMatch
+	}
+	return domMatch || dowMatch
+}
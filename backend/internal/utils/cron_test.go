@@ -0,0 +1,107 @@
+package utils
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseCronSchedule(t *testing.T) {
+	valid := []string{
+		"* * * * *",
+		"*/15 9-17 * * 1-5",
+		"0,30 0 1,15 * *",
+		"0 0 * * 7",
+		"0 0 ? * 0",
+		" @Daily ",
+	}
+	for _, expression := range valid {
+		t.Run("Success - "+expression, func(t *testing.T) {
+			schedule, err := ParseCronSchedule(expression)
+
+			assert.NoError(t, err)
+			assert.NotNil(t, schedule)
+		})
+	}
+
+	tests := []struct {
+		name       string
+		expression string
+		err        string
+	}{
+		{"Too few fields", "0 0 * *", "cron expression must have 5 fields: minute hour day-of-month month day-of-week"},
+		{"Too many fields", "0 0 0 * * *", "cron expression must have 5 fields: minute hour day-of-month month day-of-week"},
+		{"Minute out of range", "60 * * * *", `invalid minute "60": values must be between 0 and 59`},
+		{"Day of week out of range", "0 0 * * 8", `invalid day of week "8": values must be between 0 and 7`},
+		{"Zero day of month", "0 0 0 * *", `invalid day of month "0": values must be between 1 and 31`},
+		{"Zero step", "*/0 * * * *", `invalid minute "*/0": step must be a positive number`},
+		{"Reversed range", "0 17-9 * * *", `invalid hour "17-9": values must be between 0 and 23`},
+		{"Day names", "0 0 * * MON", `invalid day of week "MON": value must be a number`},
+		{"RRULE", "RRULE:FREQ=WEEKLY;BYDAY=SU", "RRULE schedules are not supported, use a 5 field cron expression"},
+	}
+	for _, tt := range tests {
+		t.Run("Error - "+tt.name, func(t *testing.T) {
+			schedule, err := ParseCronSchedule(tt.expression)
+
+			assert.Nil(t, schedule)
+			assert.EqualError(t, err, tt.err)
+		})
+	}
+}
+
+func TestCronSchedule_Next(t *testing.T) {
+	newYork, _ := time.LoadLocation("America/New_York")
+	berlin, _ := time.LoadLocation("Europe/Berlin")
+	santiago, _ := time.LoadLocation("America/Santiago")
+	// 2025-01-01 is a Wednesday.
+	utc := func(month time.Month, day, hour, minute int) time.Time {
+		return time.Date(2025, month, day, hour, minute, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		name       string
+		expression string
+		from       time.Time
+		want       time.Time
+	}{
+		{"Step", "*/15 * * * *", utc(1, 1, 10, 7), utc(1, 1, 10, 15)},
+		{"Step from a value", "5/20 * * * *", utc(1, 1, 10, 26), utc(1, 1, 10, 45)},
+		{"Strictly after from", "0,30 * * * *", utc(1, 1, 10, 0), utc(1, 1, 10, 30)},
+		{"Range", "0 9-17 * * *", utc(1, 1, 17, 30), utc(1, 2, 9, 0)},
+		{"Stepped range", "0 8-18/5 * * *", utc(1, 1, 13, 1), utc(1, 1, 18, 0)},
+		{"Sunday as 0", "0 0 * * 0", utc(1, 1, 0, 0), utc(1, 5, 0, 0)},
+		{"Sunday as 7", "0 0 * * 7", utc(1, 1, 0, 0), utc(1, 5, 0, 0)},
+		{"Range ending on 7", "0 0 * * 5-7", utc(1, 4, 0, 0), utc(1, 5, 0, 0)},
+		{"Day of month only", "0 0 13 * *", utc(1, 1, 0, 0), utc(1, 13, 0, 0)},
+		{"Day of week only", "0 0 * * 1", utc(1, 1, 0, 0), utc(1, 6, 0, 0)},
+		{"Either day field when both are set", "0 0 13 * 5", utc(1, 1, 0, 0), utc(1, 3, 0, 0)},
+		{"Either day field, day of month first", "0 0 13 * 5", utc(1, 10, 0, 0), utc(1, 13, 0, 0)},
+		{"Month", "0 0 1 6 *", utc(1, 1, 0, 0), utc(6, 1, 0, 0)},
+		{"Macro", "@weekly", utc(1, 1, 0, 0), utc(1, 5, 0, 0)},
+		{"31st skips short months", "0 0 31 * *", utc(1, 31, 0, 0), utc(3, 31, 0, 0)},
+		{"29 February waits for a leap year", "0 0 29 2 *", utc(1, 1, 0, 0), time.Date(2028, 2, 29, 0, 0, 0, 0, time.UTC)},
+		{"30 February never fires", "0 0 30 2 *", utc(1, 1, 0, 0), time.Time{}},
+		{"31 April never fires", "0 0 31 4 *", utc(1, 1, 0, 0), time.Time{}},
+		{"Time skipped by clocks going forward", "30 2 * * *", time.Date(2025, 3, 9, 0, 0, 0, 0, newYork), time.Date(2025, 3, 10, 2, 30, 0, 0, newYork)},
+		{"Hourly across clocks going forward", "0 * * * *", time.Date(2025, 3, 9, 1, 30, 0, 0, newYork), utc(3, 9, 7, 0)},
+		{"First pass when clocks go back", "30 1 * * *", time.Date(2025, 11, 2, 0, 0, 0, 0, newYork), utc(11, 2, 5, 30)},
+		{"Repeated time fires once", "30 1 * * *", utc(11, 2, 5, 30).In(newYork), utc(11, 3, 6, 30)},
+		{"First pass east of UTC", "30 2 * * *", time.Date(2025, 10, 26, 0, 0, 0, 0, berlin), utc(10, 26, 0, 30)},
+		{"Repeated time fires once east of UTC", "30 2 * * *", utc(10, 26, 0, 30).In(berlin), utc(10, 27, 1, 30)},
+		{"Midnight skipped by clocks going forward", "0 0 * * *", time.Date(2025, 9, 6, 12, 0, 0, 0, santiago), time.Date(2025, 9, 8, 0, 0, 0, 0, santiago)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schedule, err := ParseCronSchedule(tt.expression)
+			assert.NoError(t, err)
+
+			next := schedule.Next(tt.from)
+
+			assert.True(t, tt.want.Equal(next), "want %v, got %v", tt.want, next)
+			if !tt.want.IsZero() {
+				assert.Equal(t, tt.from.Location(), next.Location())
+			}
+		})
+	}
+}
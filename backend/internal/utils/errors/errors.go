@@ -58,4 +58,67 @@ var (
     ErrDeviceAlreadyExists = &BusinessError{Msg: "device with this serial number already exists", Code: http.StatusConflict}
     ErrForbidden           = &BusinessError{Msg: "access to this resource is forbidden", Code: http.StatusForbidden}
     ErrDatabaseError       = &BusinessError{Msg: "database error", Code: http.StatusInternalServerError}
+    ErrLabelNotFound       = &BusinessError{Msg: "label not found", Code: http.StatusNotFound}
+    ErrDeviceAwaitingPurge = &BusinessError{Msg: "a deleted device still holds this serial number; restore or purge it first", Code: http.StatusConflict}
+
+    // Device group errors
+    ErrDeviceGroupNotFound    = &BusinessError{Msg: "device group not found", Code: http.StatusNotFound}
+    ErrDeviceGroupHasChildren = &BusinessError{Msg: "device group has subgroups", Code: http.StatusConflict}
+    ErrDeviceNotInGroup       = &BusinessError{Msg: "device is not a member of this group", Code: http.StatusNotFound}
+
+    // Organization errors
+    ErrOrganizationNotFound = &BusinessError{Msg: "organization not found", Code: http.StatusNotFound}
+    ErrMembershipNotFound   = &BusinessError{Msg: "user is not a member of this organization", Code: http.StatusNotFound}
+    ErrMemberAlreadyExists  = &BusinessError{Msg: "user is already a member of this organization", Code: http.StatusConflict}
+    ErrLastOwner            = &BusinessError{Msg: "an organization must keep at least one owner", Code: http.StatusConflict}
+
+    // Device share errors
+    ErrDeviceShareNotFound = &BusinessError{Msg: "device share not found", Code: http.StatusNotFound}
+
+    // Device transfer errors
+    ErrDeviceTransferNotFound   = &BusinessError{Msg: "device transfer not found", Code: http.StatusNotFound}
+    ErrDeviceTransferPending    = &BusinessError{Msg: "device already has a pending transfer", Code: http.StatusConflict}
+    ErrDeviceTransferNotPending = &BusinessError{Msg: "device transfer is no longer pending", Code: http.StatusConflict}
+    ErrDeviceTransferExpired    = &BusinessError{Msg: "device transfer has expired", Code: http.StatusConflict}
+
+    // Provisioning errors
+    ErrInvalidClaimCode     = &BusinessError{Msg: "invalid serial number or claim code", Code: http.StatusForbidden}
+    ErrClaimCodeUsed        = &BusinessError{Msg: "claim code has already been used", Code: http.StatusConflict}
+    ErrDeviceNotClaimed     = &BusinessError{Msg: "device has not been claimed yet", Code: http.StatusConflict}
+    ErrDeviceAlreadyClaimed = &BusinessError{Msg: "device has already been claimed", Code: http.StatusConflict}
+    ErrInvalidDeviceToken   = &BusinessError{Msg: "invalid device token", Code: http.StatusUnauthorized}
+
+    // Device shadow errors
+    ErrShadowVersionConflict = &BusinessError{Msg: "shadow has been modified, reload it and retry", Code: http.StatusConflict}
+
+    // Device command errors
+    ErrDeviceCommandNotFound = &BusinessError{Msg: "device command not found", Code: http.StatusNotFound}
+    ErrDeviceCommandFinished = &BusinessError{Msg: "device command can no longer be cancelled", Code: http.StatusConflict}
+
+    // Firmware errors
+    ErrFirmwareNotFound      = &BusinessError{Msg: "firmware artifact not found", Code: http.StatusNotFound}
+    ErrFirmwareVersionExists = &BusinessError{Msg: "firmware version already exists", Code: http.StatusConflict}
+    ErrFirmwareInUse         = &BusinessError{Msg: "firmware artifact is used by an active rollout", Code: http.StatusConflict}
+    ErrRolloutNotFound       = &BusinessError{Msg: "firmware rollout not found", Code: http.StatusNotFound}
+    ErrRolloutNotRunning     = &BusinessError{Msg: "firmware rollout is not running", Code: http.StatusConflict}
+    ErrRolloutNotPaused      = &BusinessError{Msg: "firmware rollout is not paused", Code: http.StatusConflict}
+    ErrRolloutFinished       = &BusinessError{Msg: "firmware rollout has already finished", Code: http.StatusConflict}
+
+    // Maintenance window errors
+    ErrMaintenanceWindowNotFound = &BusinessError{Msg: "maintenance window not found", Code: http.StatusNotFound}
+
+    // Notification rule errors
+    ErrNotificationNotFound = &BusinessError{Msg: "notification rule not found", Code: http.StatusNotFound}
+    ErrMuteNotFound         = &BusinessError{Msg: "mute not found", Code: http.StatusNotFound}
+    ErrMuteEnded            = &BusinessError{Msg: "mute has already expired or been cancelled", Code: http.StatusConflict}
+
+    // Notification channel errors
+    ErrNotificationChannelNotFound  = &BusinessError{Msg: "notification channel not found", Code: http.StatusNotFound}
+    ErrNotificationDeliveryNotFound = &BusinessError{Msg: "notification delivery not found", Code: http.StatusNotFound}
+    ErrWebhookNotFound              = &BusinessError{Msg: "webhook not found", Code: http.StatusNotFound}
+
+    // Alert errors
+    ErrAlertNotFound            = &BusinessError{Msg: "alert not found", Code: http.StatusNotFound}
+    ErrAlertNotFiring           = &BusinessError{Msg: "alert is no longer firing", Code: http.StatusConflict}
+    ErrEscalationPolicyNotFound = &BusinessError{Msg: "escalation policy not found", Code: http.StatusNotFound}
 )
\ No newline at end of file
@@ -0,0 +1,152 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+const MaxDeviceLabels = 32
+
+const (
+	LabelOpEquals    = "="
+	LabelOpNotEquals = "!="
+	LabelOpExists    = "exists"
+	LabelOpNotExists = "!exists"
+)
+
+var (
+	labelKeyRegex   = regexp.MustCompile(`^[A-Za-z0-9]([A-Za-z0-9._/-]{0,61}[A-Za-z0-9])?$`)
+	labelValueRegex = regexp.MustCompile(`^([A-Za-z0-9]([A-Za-z0-9._-]{0,61}[A-Za-z0-9])?)?$`)
+)
+
+// LabelRequirement is a single term of a label selector.
+type LabelRequirement struct {
+	Key      string
+	Operator string
+	Value    string
+}
+
+// LabelSelector is a conjunction of requirements, written as a comma separated
+// list such as "env=prod,site!=rj,rack,!decommissioned".
+type LabelSelector []LabelRequirement
+
+func ValidateLabelKey(key string) error {
+	if !labelKeyRegex.MatchString(key) {
+		return fmt.Errorf("invalid label key %q: use up to 63 letters, digits, '.', '_', '-' or '/', starting and ending with a letter or digit", key)
+	}
+	return nil
+}
+
+func ValidateLabelValue(value string) error {
+	if !labelValueRegex.MatchString(value) {
+		return fmt.Errorf("invalid label value %q: use up to 63 letters, digits, '.', '_' or '-', starting and ending with a letter or digit", value)
+	}
+	return nil
+}
+
+func ValidateLabels(labels map[string]string) error {
+	if len(labels) > MaxDeviceLabels {
+		return fmt.Errorf("a device can have at most %d labels", MaxDeviceLabels)
+	}
+	for key, value := range labels {
+		if err := ValidateLabelKey(key); err != nil {
+			return err
+		}
+		if err := ValidateLabelValue(value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ParseLabelSelector parses a selector expression. An empty expression yields
+// an empty selector, which matches every set of labels.
+func ParseLabelSelector(expression string) (LabelSelector, error) {
+	expression = strings.TrimSpace(expression)
+	if expression == "" {
+		return nil, nil
+	}
+
+	var selector LabelSelector
+	for _, term := range strings.Split(expression, ",") {
+		term = strings.TrimSpace(term)
+		if term == "" {
+			return nil, errors.New("label selector contains an empty term")
+		}
+
+		var req LabelRequirement
+		switch {
+		case strings.Contains(term, "!="):
+			parts := strings.SplitN(term, "!=", 2)
+			req = LabelRequirement{Key: strings.TrimSpace(parts[0]), Operator: LabelOpNotEquals, Value: strings.TrimSpace(parts[1])}
+		case strings.Contains(term, "="):
+			parts := strings.SplitN(term, "=", 2)
+			req = LabelRequirement{Key: strings.TrimSpace(parts[0]), Operator: LabelOpEquals, Value: strings.TrimSpace(parts[1])}
+		case strings.HasPrefix(term, "!"):
+			req = LabelRequirement{Key: strings.TrimSpace(term[1:]), Operator: LabelOpNotExists}
+		default:
+			req = LabelRequirement{Key: term, Operator: LabelOpExists}
+		}
+
+		if err := ValidateLabelKey(req.Key); err != nil {
+			return nil, err
+		}
+		if err := ValidateLabelValue(req.Value); err != nil {
+			return nil, err
+		}
+		selector = append(selector, req)
+	}
+
+	sort.SliceStable(selector, func(i, j int) bool {
+		return selector[i].Key < selector[j].Key
+	})
+	return selector, nil
+}
+
+// Matches reports whether labels satisfy every requirement of the selector.
+// As with Kubernetes selectors, "!=" also matches when the key is absent.
+func (s LabelSelector) Matches(labels map[string]string) bool {
+	for _, req := range s {
+		value, ok := labels[req.Key]
+		switch req.Operator {
+		case LabelOpEquals:
+			if !ok || value != req.Value {
+				return false
+			}
+		case LabelOpNotEquals:
+			if ok && value == req.Value {
+				return false
+			}
+		case LabelOpExists:
+			if !ok {
+				return false
+			}
+		case LabelOpNotExists:
+			if ok {
+				return false
+			}
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// String renders the selector in its canonical form, with terms sorted by key.
+func (s LabelSelector) String() string {
+	terms := make([]string, 0, len(s))
+	for _, req := range s {
+		switch req.Operator {
+		case LabelOpExists:
+			terms = append(terms, req.Key)
+		case LabelOpNotExists:
+			terms = append(terms, "!"+req.Key)
+		default:
+			terms = append(terms, req.Key+req.Operator+req.Value)
+		}
+	}
+	return strings.Join(terms, ",")
+}